@@ -27,6 +27,7 @@ func NewRouter(app core.App) (*router.Router[*core.RequestEvent], error) {
 	})
 
 	// register default middlewares
+	pbRouter.Bind(requestTrace())
 	pbRouter.Bind(activityLogger())
 	pbRouter.Bind(panicRecover())
 	pbRouter.Bind(rateLimit())
@@ -38,6 +39,15 @@ func NewRouter(app core.App) (*router.Router[*core.RequestEvent], error) {
 	bindSettingsApi(app, apiGroup)
 	bindCollectionApi(app, apiGroup)
 	bindRecordCrudApi(app, apiGroup)
+	bindRecordImportApi(app, apiGroup)
+	bindRecordExportApi(app, apiGroup)
+	bindRecordRevisionsApi(app, apiGroup)
+	bindRecordSoftDeleteApi(app, apiGroup)
+	bindRecordTenantApi(app, apiGroup)
+	bindRecordAggregateApi(app, apiGroup)
+	bindRecordChangesApi(app, apiGroup)
+	bindOpenAPIApi(app, apiGroup)
+	bindGraphQLApi(app, apiGroup)
 	bindRecordAuthApi(app, apiGroup)
 	bindLogsApi(app, apiGroup)
 	bindBackupApi(app, apiGroup)
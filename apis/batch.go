@@ -284,6 +284,19 @@ func processInternalRequest(
 		return nil, errors.New("unknown batch request action")
 	}
 
+	return execInternalRequest(activeApp, baseEvent, ir, params, handle, infoContext)
+}
+
+// execInternalRequest executes the provided handler with a new RequestEvent
+// constructed from the InternalRequest data and the base event auth state.
+func execInternalRequest(
+	activeApp core.App,
+	baseEvent *core.RequestEvent,
+	ir *core.InternalRequest,
+	params map[string]string,
+	handle HandleFunc,
+	infoContext string,
+) (*BatchRequestResult, error) {
 	// construct a new http.Request
 	// ---------------------------------------------------------------
 	buf, mw, err := multipartDataFromInternalRequest(ir)
@@ -335,6 +348,9 @@ func processInternalRequest(
 		r.Header = baseEvent.Request.Header.Clone()
 	}
 
+	// the preconditions of the main batch request are not applicable for the individual items
+	r.Header.Del("If-Match")
+
 	// apply batch request specific headers
 	// ---
 	for k, v := range ir.Headers {
@@ -20,7 +20,11 @@ func bindCollectionApi(app core.App, rg *router.RouterGroup[*core.RequestEvent])
 	subGroup.PATCH("/{collection}", collectionUpdate)
 	subGroup.DELETE("/{collection}", collectionDelete)
 	subGroup.DELETE("/{collection}/truncate", collectionTruncate)
+	subGroup.GET("/{collection}/materialized", collectionMaterializedInfo)
+	subGroup.POST("/{collection}/materialized/refresh", collectionMaterializedRefresh)
 	subGroup.PUT("/import", collectionsImport)
+	subGroup.POST("/schema/plan", collectionsSchemaPlan)
+	subGroup.POST("/schema/apply", collectionsSchemaApply)
 	subGroup.GET("/meta/scaffolds", collectionScaffolds)
 }
 
@@ -191,6 +195,47 @@ func collectionTruncate(e *core.RequestEvent) error {
 	return e.NoContent(http.StatusNoContent)
 }
 
+func collectionMaterializedInfo(e *core.RequestEvent) error {
+	collection, err := e.App.FindCachedCollectionByNameOrId(e.Request.PathValue("collection"))
+	if err != nil || collection == nil {
+		return e.NotFoundError("", err)
+	}
+
+	if !collection.IsMaterializedView() {
+		return e.BadRequestError("The collection is not a materialized view.", nil)
+	}
+
+	info, err := e.App.FindMaterializedViewInfo(collection)
+	if err != nil {
+		return e.InternalServerError("Failed to load the materialized view info.", err)
+	}
+
+	return e.JSON(http.StatusOK, info)
+}
+
+func collectionMaterializedRefresh(e *core.RequestEvent) error {
+	collection, err := e.App.FindCachedCollectionByNameOrId(e.Request.PathValue("collection"))
+	if err != nil || collection == nil {
+		return e.NotFoundError("", err)
+	}
+
+	if !collection.IsMaterializedView() {
+		return e.BadRequestError("The collection is not a materialized view.", nil)
+	}
+
+	err = e.App.RefreshMaterializedView(collection)
+	if err != nil {
+		return e.BadRequestError("Failed to refresh the materialized view.", err)
+	}
+
+	info, err := e.App.FindMaterializedViewInfo(collection)
+	if err != nil {
+		return e.InternalServerError("Failed to load the materialized view info.", err)
+	}
+
+	return e.JSON(http.StatusOK, info)
+}
+
 func collectionScaffolds(e *core.RequestEvent) error {
 	collections := map[string]*core.Collection{
 		core.CollectionTypeBase: core.NewBaseCollection(""),
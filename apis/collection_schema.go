@@ -0,0 +1,101 @@
+package apis
+
+import (
+	"errors"
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func collectionsSchemaPlan(e *core.RequestEvent) error {
+	form := new(collectionsSchemaForm)
+
+	err := e.BindBody(form)
+	if err != nil {
+		return firstApiError(err, e.BadRequestError("An error occurred while loading the submitted data.", err))
+	}
+
+	err = form.validate()
+	if err != nil {
+		return firstApiError(err, e.BadRequestError("An error occurred while validating the submitted data.", err))
+	}
+
+	plan, err := core.PlanSchema(e.App, form.Collections, form.DeleteMissing)
+	if err != nil {
+		return schemaPlanApiError(e, "Failed to plan the collections schema changes.", err)
+	}
+
+	return e.JSON(http.StatusOK, plan)
+}
+
+func collectionsSchemaApply(e *core.RequestEvent) error {
+	form := new(collectionsSchemaForm)
+
+	err := e.BindBody(form)
+	if err != nil {
+		return firstApiError(err, e.BadRequestError("An error occurred while loading the submitted data.", err))
+	}
+
+	err = form.validate()
+	if err != nil {
+		return firstApiError(err, e.BadRequestError("An error occurred while validating the submitted data.", err))
+	}
+
+	event := new(core.CollectionsImportRequestEvent)
+	event.RequestEvent = e
+	event.CollectionsData = form.Collections
+	event.DeleteMissing = form.DeleteMissing
+
+	return event.App.OnCollectionsImportRequest().Trigger(event, func(e *core.CollectionsImportRequestEvent) error {
+		plan, applyErr := core.ApplySchema(e.App, e.CollectionsData, core.SchemaApplyOptions{
+			DeleteMissing:    e.DeleteMissing,
+			AllowDestructive: form.AllowDestructive,
+		})
+
+		if errors.Is(applyErr, core.ErrDestructiveSchemaChanges) {
+			return e.BadRequestError("Failed to apply the collections schema changes.", validation.Errors{
+				"allowDestructive": validation.NewError(
+					"validation_destructive_schema_changes",
+					"The schema plan contains destructive changes. Review the plan and set allowDestructive to apply it anyway.",
+				),
+			})
+		}
+
+		if applyErr != nil {
+			return schemaPlanApiError(e.RequestEvent, "Failed to apply the collections schema changes.", applyErr)
+		}
+
+		return execAfterSuccessTx(true, e.App, func() error {
+			return e.JSON(http.StatusOK, plan)
+		})
+	})
+}
+
+func schemaPlanApiError(e *core.RequestEvent, message string, err error) error {
+	// validation failure
+	var validationErrors validation.Errors
+	if errors.As(err, &validationErrors) {
+		return e.BadRequestError(message, validationErrors)
+	}
+
+	// generic/db failure
+	return e.BadRequestError(message, validation.Errors{"collections": validation.NewError(
+		"validation_collections_schema_failure",
+		"Failed to process the collections schema. Raw error:\n"+err.Error(),
+	)})
+}
+
+// -------------------------------------------------------------------
+
+type collectionsSchemaForm struct {
+	Collections      []map[string]any `form:"collections" json:"collections"`
+	DeleteMissing    bool             `form:"deleteMissing" json:"deleteMissing"`
+	AllowDestructive bool             `form:"allowDestructive" json:"allowDestructive"`
+}
+
+func (form *collectionsSchemaForm) validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(&form.Collections, validation.Required),
+	)
+}
@@ -119,7 +119,9 @@ func (api *fileApi) download(e *core.RequestEvent) error {
 		requestInfo.Context = core.RequestInfoContextProtectedFile
 		requestInfo.Auth = authRecord
 
-		if ok, _ := e.App.CanAccessRecord(record, &requestInfo, record.Collection().ViewRule); !ok {
+		if ok, _ := e.App.CanAccessRecord(record, &requestInfo, record.Collection().ViewRule); !ok ||
+			!canAccessTenantRecord(record, &requestInfo) ||
+			!canAccessSoftDeletedRecord(e.App, record, &requestInfo) {
 			return e.NotFoundError("", errors.New("insufficient permissions to access the file resource"))
 		}
 	}
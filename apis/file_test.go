@@ -319,6 +319,31 @@ func TestFileDownload(t *testing.T) {
 				"OnFileDownloadRequest": 1,
 			},
 		},
+		{
+			Name:   "protected file - guest with view access of a soft deleted record",
+			Method: http.MethodGet,
+			URL:    "/api/files/demo1/al1h9ijdeojtsjy/300_Jsjq7RdBgA.png",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				softDeleteFileRecord(t, app, "")
+			},
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "protected file - guest with view rule referencing the soft deleted records",
+			Method: http.MethodGet,
+			URL:    "/api/files/demo1/al1h9ijdeojtsjy/300_Jsjq7RdBgA.png",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				softDeleteFileRecord(t, app, "deleted != ''")
+			},
+			ExpectedStatus:  200,
+			ExpectedContent: []string{"PNG"},
+			ExpectedEvents: map[string]int{
+				"*":                     0,
+				"OnFileDownloadRequest": 1,
+			},
+		},
 		{
 			Name:   "protected file - auth record without view access",
 			Method: http.MethodGet,
@@ -428,6 +453,28 @@ func TestFileDownload(t *testing.T) {
 	}
 }
 
+// softDeleteFileRecord enables the demo1 soft delete with the specified
+// view rule and soft deletes the "al1h9ijdeojtsjy" record.
+func softDeleteFileRecord(t testing.TB, app *tests.TestApp, viewRule string) {
+	c, err := app.FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatalf("Failed to fetch mock collection: %v", err)
+	}
+	c.ViewRule = types.Pointer(viewRule)
+	c.SoftDelete = &core.SoftDeleteConfig{Enabled: true}
+	if err := app.Save(c); err != nil {
+		t.Fatalf("Failed to update mock collection: %v", err)
+	}
+
+	record, err := app.FindRecordById(c, "al1h9ijdeojtsjy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := app.Delete(record); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestConcurrentThumbsGeneration(t *testing.T) {
 	t.Parallel()
 
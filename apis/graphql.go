@@ -0,0 +1,979 @@
+package apis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/graphql"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/inflector"
+	"github.com/pocketbase/pocketbase/tools/router"
+	"github.com/pocketbase/pocketbase/tools/search"
+	"github.com/spf13/cast"
+)
+
+// bindGraphQLApi registers the GraphQL api endpoints.
+func bindGraphQLApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	cache := &graphQLSchemaCache{}
+
+	// regenerate the schema on the next request after collection changes
+	resetCollection := func(e *core.CollectionEvent) error {
+		cache.reset()
+		return e.Next()
+	}
+	app.OnCollectionAfterCreateSuccess().Bind(&hook.Handler[*core.CollectionEvent]{Func: resetCollection})
+	app.OnCollectionAfterUpdateSuccess().Bind(&hook.Handler[*core.CollectionEvent]{Func: resetCollection})
+	app.OnCollectionAfterDeleteSuccess().Bind(&hook.Handler[*core.CollectionEvent]{Func: resetCollection})
+
+	subGroup := rg.Group("/graphql")
+	subGroup.GET("", graphQLExecute(cache))
+	subGroup.POST("", graphQLExecute(cache))
+	subGroup.GET("/schema.graphql", graphQLSchemaSDL(cache))
+}
+
+// graphQLSchemaCache stores the last generated GraphQL schemas
+// (one for the superusers and one for everyone else).
+type graphQLSchemaCache struct {
+	mu      sync.Mutex
+	schemas map[bool]*graphql.Schema
+}
+
+func (c *graphQLSchemaCache) reset() {
+	c.mu.Lock()
+	c.schemas = nil
+	c.mu.Unlock()
+}
+
+func (c *graphQLSchemaCache) load(app core.App, superuser bool) (*graphql.Schema, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if schema, ok := c.schemas[superuser]; ok {
+		return schema, nil
+	}
+
+	schema, err := GraphQLSchema(app, superuser)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.schemas == nil {
+		c.schemas = map[bool]*graphql.Schema{}
+	}
+	c.schemas[superuser] = schema
+
+	return schema, nil
+}
+
+func graphQLExecute(cache *graphQLSchemaCache) func(e *core.RequestEvent) error {
+	return func(e *core.RequestEvent) error {
+		config := e.App.Settings().GraphQL
+		if !config.Enabled {
+			return e.ForbiddenError("GraphQL requests are not allowed.", nil)
+		}
+
+		req := graphql.Request{}
+
+		if e.Request.Method == http.MethodGet {
+			query := e.Request.URL.Query()
+			req.Query = query.Get("query")
+			req.OperationName = query.Get("operationName")
+			if raw := query.Get("variables"); raw != "" {
+				if err := json.Unmarshal([]byte(raw), &req.Variables); err != nil {
+					return e.BadRequestError("Invalid GraphQL variables.", err)
+				}
+			}
+			req.QueryOnly = true // mutations are allowed only with POST
+		} else if err := e.BindBody(&req); err != nil {
+			return e.BadRequestError("Failed to read the submitted GraphQL request.", err)
+		}
+
+		if strings.TrimSpace(req.Query) == "" {
+			return e.BadRequestError("Missing required GraphQL query.", nil)
+		}
+
+		req.MaxDepth = config.MaxDepth
+		req.MaxComplexity = config.MaxComplexity
+
+		schema, err := cache.load(e.App, e.HasSuperuserAuth())
+		if err != nil {
+			return e.InternalServerError("Failed to generate the GraphQL schema.", err)
+		}
+
+		requestInfo, err := e.RequestInfo()
+		if err != nil {
+			return firstApiError(err, e.BadRequestError("", err))
+		}
+
+		state := &graphQLRequest{
+			event:       e,
+			requestInfo: requestInfo,
+			related:     map[string]map[string]map[string]any{},
+		}
+
+		result := schema.Execute(context.WithValue(e.Request.Context(), graphQLRequestKey{}, state), req)
+
+		return e.JSON(http.StatusOK, result)
+	}
+}
+
+func graphQLSchemaSDL(cache *graphQLSchemaCache) func(e *core.RequestEvent) error {
+	return func(e *core.RequestEvent) error {
+		if !e.App.Settings().GraphQL.Enabled {
+			return e.ForbiddenError("GraphQL requests are not allowed.", nil)
+		}
+
+		schema, err := cache.load(e.App, e.HasSuperuserAuth())
+		if err != nil {
+			return e.InternalServerError("Failed to generate the GraphQL schema.", err)
+		}
+
+		return e.Blob(http.StatusOK, "text/plain; charset=utf-8", []byte(schema.SDL()))
+	}
+}
+
+// -------------------------------------------------------------------
+
+type graphQLRequestKey struct{}
+
+// graphQLRequest holds the state of a single GraphQL request execution.
+type graphQLRequest struct {
+	event       *core.RequestEvent
+	requestInfo *core.RequestInfo
+
+	// related caches the exported related records per collection id and record id
+	// (nil means that the record is missing or not accessible).
+	related map[string]map[string]map[string]any
+}
+
+func graphQLRequestFromContext(ctx context.Context) (*graphQLRequest, error) {
+	state, ok := ctx.Value(graphQLRequestKey{}).(*graphQLRequest)
+	if !ok {
+		return nil, errors.New("missing GraphQL request context")
+	}
+	return state, nil
+}
+
+// exec routes the internal request through the provided records api handler
+// so that the collection API rules and hooks are applied exactly as for the regular requests.
+func (r *graphQLRequest) exec(method string, collection string, id string, query url.Values, body map[string]any, handle HandleFunc) (*BatchRequestResult, error) {
+	params := map[string]string{"collection": collection}
+
+	path := "/api/collections/" + url.PathEscape(collection) + "/records"
+	if id != "" {
+		params["id"] = id
+		path += "/" + url.PathEscape(id)
+	}
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	ir := &core.InternalRequest{
+		Method: method,
+		URL:    path,
+		Body:   body,
+	}
+
+	// the mutations could change the already loaded related records
+	if method != http.MethodGet {
+		clear(r.related)
+	}
+
+	result, err := execInternalRequest(r.event.App, r.event, ir, params, handle, core.RequestInfoContextGraphQL)
+	if err != nil {
+		return nil, graphQLError(err)
+	}
+
+	return result, nil
+}
+
+// loadRelated fetches the related records with the provided ids
+// using the same rules as the regular relations expand.
+func (r *graphQLRequest) loadRelated(collectionId string, ids []string) ([]map[string]any, error) {
+	cached := r.related[collectionId]
+	if cached == nil {
+		cached = map[string]map[string]any{}
+		r.related[collectionId] = cached
+	}
+
+	missing := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := cached[id]; !ok && !slices.Contains(missing, id) {
+			missing = append(missing, id)
+		}
+	}
+
+	if len(missing) > 0 {
+		collection, err := r.event.App.FindCachedCollectionByNameOrId(collectionId)
+		if err != nil {
+			return nil, graphQLError(err)
+		}
+
+		records, err := expandFetch(r.event.App, r.requestInfo)(collection, missing)
+		if err != nil {
+			return nil, graphQLError(err)
+		}
+
+		for _, id := range missing {
+			cached[id] = nil
+		}
+
+		for _, record := range records {
+			exported, err := graphQLExportRecord(record)
+			if err != nil {
+				return nil, graphQLError(err)
+			}
+			cached[record.Id] = exported
+		}
+	}
+
+	result := make([]map[string]any, 0, len(ids))
+	for _, id := range ids {
+		if item := cached[id]; item != nil {
+			result = append(result, item)
+		}
+	}
+
+	return result, nil
+}
+
+func graphQLExportRecord(record *core.Record) (map[string]any, error) {
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]any{}
+
+	return result, json.Unmarshal(raw, &result)
+}
+
+// graphQLError converts err into a GraphQL error with the public safe api error details.
+func graphQLError(err error) error {
+	apiErr := router.ToApiError(err)
+
+	extensions := map[string]any{"status": apiErr.Status}
+	if len(apiErr.Data) > 0 {
+		extensions["data"] = apiErr.Data
+	}
+
+	return graphql.NewError(apiErr.Message, extensions)
+}
+
+// graphQLRelationIds extracts the relation ids from the exported record field value.
+func graphQLRelationIds(value any) []string {
+	switch v := value.(type) {
+	case string:
+		if v == "" {
+			return nil
+		}
+		return []string{v}
+	case []any:
+		return cast.ToStringSlice(v)
+	}
+
+	return nil
+}
+
+// -------------------------------------------------------------------
+
+var graphQLNameRegex = regexp.MustCompile(`^[_a-zA-Z][_a-zA-Z0-9]*$`)
+
+// GraphQLSchema generates a GraphQL schema from the current app collections.
+//
+// Each collection has list and view query fields (and create, update and
+// delete mutations for the non-view collections) that are executed through
+// the regular records api handlers, aka. the collection API rules are enforced
+// in the same way. The relation fields are resolved as nested objects using
+// the related collection view API rule (the same as with the "expand" parameter).
+//
+// If includeSuperuserOnly is false, the operations that are accessible only
+// by superusers (aka. with nil collection API rule) and the hidden fields
+// are excluded from the schema.
+func GraphQLSchema(app core.App, includeSuperuserOnly bool) (*graphql.Schema, error) {
+	collections, err := app.FindAllCollections()
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(collections, func(a, b *core.Collection) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	g := &graphQLGenerator{
+		superuser:   includeSuperuserOnly,
+		collections: collections,
+		prefixes:    make(map[string]string, len(collections)),
+		records:     map[string]*graphql.Object{},
+		lists:       map[string]*graphql.Object{},
+		query:       &graphql.Object{Name: "Query"},
+		mutation:    &graphql.Object{Name: "Mutation"},
+		rootNames:   map[string]struct{}{},
+		jsonScalar: &graphql.Scalar{
+			Name:        "JSON",
+			Description: "Any JSON value.",
+		},
+		geoPoint: &graphql.Object{
+			Name: "GeoPoint",
+			Fields: []*graphql.Field{
+				{Name: "lon", Type: graphql.NewNonNull(graphql.Float)},
+				{Name: "lat", Type: graphql.NewNonNull(graphql.Float)},
+			},
+		},
+		geoPointInput: &graphql.InputObject{
+			Name: "GeoPointInput",
+			Fields: []*graphql.Argument{
+				{Name: "lon", Type: graphql.NewNonNull(graphql.Float)},
+				{Name: "lat", Type: graphql.NewNonNull(graphql.Float)},
+			},
+		},
+	}
+
+	usedPrefixes := map[string]struct{}{}
+	for _, c := range collections {
+		prefix := inflector.UcFirst(inflector.Camelize(c.Name))
+		if _, ok := usedPrefixes[prefix]; ok || !graphQLNameRegex.MatchString(prefix) {
+			prefix = "Collection" + inflector.UcFirst(c.Id) // eg. "_superusers" and "superusers"
+		}
+		usedPrefixes[prefix] = struct{}{}
+		g.prefixes[c.Id] = prefix
+	}
+
+	// register the object types first to allow circular relations
+	for _, c := range collections {
+		if !g.canAccess(c.ListRule) && !g.canAccess(c.ViewRule) {
+			continue
+		}
+
+		prefix := g.prefixes[c.Id]
+
+		g.records[c.Id] = &graphql.Object{
+			Name:        prefix + "Record",
+			Description: "A single " + c.Name + " record.",
+		}
+
+		g.lists[c.Id] = &graphql.Object{
+			Name:        prefix + "RecordList",
+			Description: "A paginated list of " + c.Name + " records.",
+			Fields: []*graphql.Field{
+				{Name: "page", Type: graphql.NewNonNull(graphql.Int)},
+				{Name: "perPage", Type: graphql.NewNonNull(graphql.Int)},
+				{Name: "totalItems", Type: graphql.NewNonNull(graphql.Int)},
+				{Name: "totalPages", Type: graphql.NewNonNull(graphql.Int)},
+				{Name: "items", Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(g.records[c.Id])))},
+			},
+		}
+	}
+
+	for _, c := range collections {
+		if g.records[c.Id] != nil {
+			g.addRecordFields(c)
+		}
+		g.addQueries(c)
+		g.addMutations(c)
+	}
+
+	if len(g.query.Fields) == 0 {
+		g.query.Fields = append(g.query.Fields, &graphql.Field{
+			Name:        "_empty",
+			Description: "Placeholder field in case there are no accessible collections.",
+			Type:        graphql.Boolean,
+		})
+	}
+
+	var mutation *graphql.Object
+	if len(g.mutation.Fields) > 0 {
+		mutation = g.mutation
+	}
+
+	return graphql.NewSchema(g.query, mutation)
+}
+
+type graphQLGenerator struct {
+	superuser     bool
+	collections   []*core.Collection
+	prefixes      map[string]string          // collection id -> type name prefix
+	records       map[string]*graphql.Object // collection id -> record object type
+	lists         map[string]*graphql.Object // collection id -> records list object type
+	query         *graphql.Object
+	mutation      *graphql.Object
+	rootNames     map[string]struct{}
+	jsonScalar    *graphql.Scalar
+	geoPoint      *graphql.Object
+	geoPointInput *graphql.InputObject
+}
+
+func (g *graphQLGenerator) canAccess(rule *string) bool {
+	return rule != nil || g.superuser
+}
+
+// rootFieldName returns a unique root query or mutation field name.
+func (g *graphQLGenerator) rootFieldName(name string, c *core.Collection) string {
+	if _, ok := g.rootNames[name]; ok {
+		name += inflector.UcFirst(c.Id)
+	}
+	g.rootNames[name] = struct{}{}
+	return name
+}
+
+func (g *graphQLGenerator) listArgs() []*graphql.Argument {
+	return []*graphql.Argument{
+		{Name: "filter", Type: graphql.String, Description: "Records filter expression (eg. `created > '2024-01-01'`)."},
+		{Name: "sort", Type: graphql.String, Description: "Records sort expression (eg. `-created,title`)."},
+		{Name: "page", Type: graphql.Int, DefaultValue: 1},
+		{Name: "perPage", Type: graphql.Int, DefaultValue: search.DefaultPerPage},
+		{Name: "skipTotal", Type: graphql.Boolean, DefaultValue: false, Description: "Skip the total counts query (totalItems and totalPages will be -1)."},
+	}
+}
+
+// listComplexity multiplies the sub-selection complexity by the requested page size.
+func listComplexity(childComplexity int, args map[string]any) int {
+	perPage, _ := args["perPage"].(int)
+	if perPage <= 0 {
+		perPage = search.DefaultPerPage
+	}
+	if perPage > search.MaxPerPage {
+		perPage = search.MaxPerPage
+	}
+
+	return 1 + perPage*childComplexity
+}
+
+func (g *graphQLGenerator) addQueries(c *core.Collection) {
+	name := strings.ToLower(g.prefixes[c.Id][:1]) + g.prefixes[c.Id][1:]
+
+	if g.canAccess(c.ListRule) {
+		g.query.Fields = append(g.query.Fields, &graphql.Field{
+			Name:        g.rootFieldName(name+"List", c),
+			Description: "Returns a paginated " + c.Name + " records list.",
+			Type:        graphql.NewNonNull(g.lists[c.Id]),
+			Args:        g.listArgs(),
+			Complexity:  listComplexity,
+			Resolve:     g.listResolver(c, ""),
+		})
+	}
+
+	if g.canAccess(c.ViewRule) {
+		collectionName := c.Name
+		g.query.Fields = append(g.query.Fields, &graphql.Field{
+			Name:        g.rootFieldName(name, c),
+			Description: "Returns a single " + c.Name + " record by its id.",
+			Type:        g.records[c.Id],
+			Args:        []*graphql.Argument{{Name: "id", Type: graphql.NewNonNull(graphql.ID)}},
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				state, err := graphQLRequestFromContext(p.Context)
+				if err != nil {
+					return nil, err
+				}
+
+				result, err := state.exec(http.MethodGet, collectionName, p.Args["id"].(string), nil, nil, recordView)
+				if err != nil {
+					return nil, err
+				}
+
+				return graphQLResultRecord(result)
+			},
+		})
+	}
+}
+
+// listResolver returns a records list resolver that executes the regular list api handler.
+//
+// relFilter is an optional filter expression that is combined with the user provided one
+// (the placeholder "{id}" is replaced with the id of the parent record source).
+func (g *graphQLGenerator) listResolver(c *core.Collection, relFilter string) graphql.ResolveFunc {
+	collectionName := c.Name
+
+	// the relations that are resolved as nested objects
+	relations := map[string]*core.RelationField{}
+	for _, f := range c.Fields {
+		relField, ok := f.(*core.RelationField)
+		if !ok || g.records[relField.CollectionId] == nil {
+			continue
+		}
+		if rel := g.collection(relField.CollectionId); rel != nil && g.canAccess(rel.ViewRule) {
+			relations[relField.Name] = relField
+		}
+	}
+
+	return func(p graphql.ResolveParams) (any, error) {
+		state, err := graphQLRequestFromContext(p.Context)
+		if err != nil {
+			return nil, err
+		}
+
+		filters := []string{}
+
+		if relFilter != "" {
+			source, _ := p.Source.(map[string]any)
+			parentId := cast.ToString(source[core.FieldNameId])
+			if parentId == "" {
+				return nil, nil
+			}
+			filters = append(filters, strings.ReplaceAll(relFilter, "{id}", strconv.Quote(parentId)))
+		}
+
+		if filter, _ := p.Args["filter"].(string); filter != "" {
+			filters = append(filters, "("+filter+")")
+		}
+
+		query := url.Values{}
+		if len(filters) > 0 {
+			query.Set(search.FilterQueryParam, strings.Join(filters, " && "))
+		}
+		if sort, _ := p.Args["sort"].(string); sort != "" {
+			query.Set(search.SortQueryParam, sort)
+		}
+		if page, ok := p.Args["page"].(int); ok {
+			query.Set(search.PageQueryParam, strconv.Itoa(page))
+		}
+		if perPage, ok := p.Args["perPage"].(int); ok {
+			query.Set(search.PerPageQueryParam, strconv.Itoa(perPage))
+		}
+		if skipTotal, _ := p.Args["skipTotal"].(bool); skipTotal {
+			query.Set(search.SkipTotalQueryParam, "1")
+		}
+
+		result, err := state.exec(http.MethodGet, collectionName, "", query, nil, recordsList)
+		if err != nil {
+			return nil, err
+		}
+
+		raw, err := json.Marshal(result.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		list := struct {
+			Items      []map[string]any `json:"items"`
+			Page       int              `json:"page"`
+			PerPage    int              `json:"perPage"`
+			TotalItems int              `json:"totalItems"`
+			TotalPages int              `json:"totalPages"`
+		}{}
+		if err := json.Unmarshal(raw, &list); err != nil {
+			return nil, err
+		}
+
+		// prefetch the selected relations of all items at once
+		for _, selected := range p.Info.SelectionSet {
+			if selected.Name != "items" {
+				continue
+			}
+			for _, itemField := range selected.SelectionSet {
+				relField, ok := relations[itemField.Name]
+				if !ok {
+					continue
+				}
+
+				var ids []string
+				for _, item := range list.Items {
+					ids = append(ids, graphQLRelationIds(item[relField.Name])...)
+				}
+				if len(ids) > 0 {
+					if _, err := state.loadRelated(relField.CollectionId, ids); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+
+		if list.Items == nil {
+			list.Items = []map[string]any{}
+		}
+
+		return map[string]any{
+			"page":       list.Page,
+			"perPage":    list.PerPage,
+			"totalItems": list.TotalItems,
+			"totalPages": list.TotalPages,
+			"items":      list.Items,
+		}, nil
+	}
+}
+
+func (g *graphQLGenerator) addMutations(c *core.Collection) {
+	if c.IsView() {
+		return
+	}
+
+	prefix := g.prefixes[c.Id]
+	collectionName := c.Name
+
+	// the returned record is nil if it is not viewable by the current requester
+	var resultType graphql.Type = g.jsonScalar
+	if g.records[c.Id] != nil {
+		resultType = g.records[c.Id]
+	}
+
+	if g.canAccess(c.CreateRule) {
+		if input := g.recordInput(c, true); input != nil {
+			g.mutation.Fields = append(g.mutation.Fields, &graphql.Field{
+				Name:        g.rootFieldName("create"+prefix, c),
+				Description: "Creates a new " + c.Name + " record.",
+				Type:        resultType,
+				Args:        []*graphql.Argument{{Name: "data", Type: graphql.NewNonNull(input)}},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					state, err := graphQLRequestFromContext(p.Context)
+					if err != nil {
+						return nil, err
+					}
+
+					data, _ := p.Args["data"].(map[string]any)
+
+					result, err := state.exec(http.MethodPost, collectionName, "", nil, data, recordCreate(true, nil))
+					if err != nil {
+						return nil, err
+					}
+
+					return graphQLResultRecord(result)
+				},
+			})
+		}
+	}
+
+	if g.canAccess(c.UpdateRule) {
+		if input := g.recordInput(c, false); input != nil {
+			g.mutation.Fields = append(g.mutation.Fields, &graphql.Field{
+				Name:        g.rootFieldName("update"+prefix, c),
+				Description: "Updates a single " + c.Name + " record.",
+				Type:        resultType,
+				Args: []*graphql.Argument{
+					{Name: "id", Type: graphql.NewNonNull(graphql.ID)},
+					{Name: "data", Type: graphql.NewNonNull(input)},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					state, err := graphQLRequestFromContext(p.Context)
+					if err != nil {
+						return nil, err
+					}
+
+					data, _ := p.Args["data"].(map[string]any)
+
+					result, err := state.exec(http.MethodPatch, collectionName, p.Args["id"].(string), nil, data, recordUpdate(true, nil))
+					if err != nil {
+						return nil, err
+					}
+
+					return graphQLResultRecord(result)
+				},
+			})
+		}
+	}
+
+	if g.canAccess(c.DeleteRule) {
+		g.mutation.Fields = append(g.mutation.Fields, &graphql.Field{
+			Name:        g.rootFieldName("delete"+prefix, c),
+			Description: "Deletes a single " + c.Name + " record.",
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Args:        []*graphql.Argument{{Name: "id", Type: graphql.NewNonNull(graphql.ID)}},
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				state, err := graphQLRequestFromContext(p.Context)
+				if err != nil {
+					return nil, err
+				}
+
+				if _, err := state.exec(http.MethodDelete, collectionName, p.Args["id"].(string), nil, nil, recordDelete(true, nil)); err != nil {
+					return nil, err
+				}
+
+				return true, nil
+			},
+		})
+	}
+}
+
+// graphQLResultRecord extracts the record data from a records api handler result
+// (nil if the record is not viewable by the current requester).
+func graphQLResultRecord(result *BatchRequestResult) (any, error) {
+	if result.Status == http.StatusNoContent || result.Body == nil {
+		return nil, nil
+	}
+
+	raw, err := json.Marshal(result.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	record := map[string]any{}
+	if err := json.Unmarshal(raw, &record); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// recordInput returns the create or update record input type
+// (nil if the collection doesn't have any writable fields).
+//
+// Note: file uploads are not supported and should be submitted with the regular records api.
+func (g *graphQLGenerator) recordInput(c *core.Collection, isCreate bool) *graphql.InputObject {
+	input := &graphql.InputObject{}
+	if isCreate {
+		input.Name = g.prefixes[c.Id] + "CreateInput"
+	} else {
+		input.Name = g.prefixes[c.Id] + "UpdateInput"
+	}
+
+	for _, f := range c.Fields {
+		name := f.GetName()
+
+		if !graphQLNameRegex.MatchString(name) || strings.HasPrefix(name, "__") {
+			continue
+		}
+
+		if c.IsAuth() && name == core.FieldNameTokenKey {
+			continue
+		}
+
+		if !isCreate && name == core.FieldNameId {
+			continue
+		}
+
+		if gc, ok := f.(core.GeneratedColumner); ok && gc.IsGeneratedColumn() {
+			continue // read-only
+		}
+
+		var t graphql.Type
+
+		switch v := f.(type) {
+		case *core.AutodateField, *core.FileField:
+			continue
+		case *core.RelationField:
+			if v.IsMultiple() {
+				t = graphql.NewList(graphql.NewNonNull(graphql.ID))
+			} else {
+				t = graphql.ID
+			}
+		case *core.GeoPointField:
+			t = g.geoPointInput
+		case *core.PasswordField:
+			t = graphql.String
+			input.Fields = append(input.Fields, &graphql.Argument{Name: name, Type: t})
+			input.Fields = append(input.Fields, &graphql.Argument{Name: name + "Confirm", Type: t})
+			if !isCreate && c.IsAuth() && name == core.FieldNamePassword {
+				input.Fields = append(input.Fields, &graphql.Argument{Name: "oldPassword", Type: t})
+			}
+			continue
+		default:
+			t = g.leafType(f)
+		}
+
+		input.Fields = append(input.Fields, &graphql.Argument{Name: name, Type: t})
+	}
+
+	if len(input.Fields) == 0 {
+		return nil
+	}
+
+	return input
+}
+
+// leafType returns the GraphQL type of a single non-relation collection field.
+func (g *graphQLGenerator) leafType(f core.Field) graphql.Type {
+	stringOrList := func(isMultiple bool) graphql.Type {
+		if isMultiple {
+			return graphql.NewList(graphql.NewNonNull(graphql.String))
+		}
+		return graphql.String
+	}
+
+	switch v := f.(type) {
+	case *core.NumberField:
+		if v.OnlyInt {
+			return graphql.Int
+		}
+		return graphql.Float
+	case *core.DecimalField:
+		if v.JSONNumber {
+			return graphql.Float
+		}
+		return graphql.String
+	case *core.BoolField:
+		return graphql.Boolean
+	case *core.SelectField:
+		return stringOrList(v.IsMultiple())
+	case *core.FileField:
+		return stringOrList(v.IsMultiple())
+	case *core.VectorField:
+		return graphql.NewList(graphql.NewNonNull(graphql.Float))
+	case *core.ComputedField:
+		switch v.ValueType {
+		case core.ComputedValueTypeNumber:
+			return graphql.Float
+		case core.ComputedValueTypeBool:
+			return graphql.Boolean
+		}
+		return graphql.String
+	case *core.TextField, *core.EditorField, *core.EmailField, *core.URLField,
+		*core.DateField, *core.AutodateField:
+		return graphql.String
+	}
+
+	// json and unknown/custom field types
+	return g.jsonScalar
+}
+
+func (g *graphQLGenerator) addRecordFields(c *core.Collection) {
+	obj := g.records[c.Id]
+
+	for _, f := range c.Fields {
+		name := f.GetName()
+
+		if !graphQLNameRegex.MatchString(name) || strings.HasPrefix(name, "__") {
+			continue
+		}
+
+		if c.IsAuth() && (name == core.FieldNamePassword || name == core.FieldNameTokenKey) {
+			continue // never exported
+		}
+
+		if f.Type() == core.FieldTypePassword || (f.GetHidden() && !g.superuser) {
+			continue
+		}
+
+		field := &graphql.Field{Name: name}
+
+		switch v := f.(type) {
+		case *core.RelationField:
+			relObj := g.records[v.CollectionId]
+			relCollection := g.collection(v.CollectionId)
+			if relObj == nil || relCollection == nil || !g.canAccess(relCollection.ViewRule) {
+				// the related records are not accessible -> return only their ids
+				if v.IsMultiple() {
+					field.Type = graphql.NewList(graphql.NewNonNull(graphql.ID))
+				} else {
+					field.Type = graphql.ID
+				}
+				break
+			}
+
+			field.Description = "The related " + relCollection.Name + " record(s)."
+			field.Resolve = relationResolver(v)
+
+			if v.IsMultiple() {
+				field.Type = graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(relObj)))
+				maxSelect := v.MaxSelect
+				field.Complexity = func(childComplexity int, args map[string]any) int {
+					return 1 + maxSelect*childComplexity
+				}
+			} else {
+				field.Type = relObj
+			}
+		case *core.GeoPointField:
+			field.Type = g.geoPoint
+		default:
+			field.Type = g.leafType(f)
+		}
+
+		if name == core.FieldNameId {
+			field.Type = graphql.NewNonNull(graphql.ID)
+		}
+
+		if f.GetHidden() {
+			field.Description = "Hidden field (visible only for superusers)."
+		}
+
+		obj.Fields = append(obj.Fields, field)
+	}
+
+	obj.Fields = append(obj.Fields,
+		&graphql.Field{Name: core.FieldNameCollectionId, Type: graphql.NewNonNull(graphql.String)},
+		&graphql.Field{Name: core.FieldNameCollectionName, Type: graphql.NewNonNull(graphql.String)},
+	)
+
+	g.addBackRelationFields(c, obj)
+}
+
+// addBackRelationFields registers the "{collection}Via{Field}" list fields
+// for the relation fields of the other collections that reference c.
+func (g *graphQLGenerator) addBackRelationFields(c *core.Collection, obj *graphql.Object) {
+	for _, other := range g.collections {
+		if g.lists[other.Id] == nil || !g.canAccess(other.ListRule) {
+			continue
+		}
+
+		for _, f := range other.Fields {
+			relField, ok := f.(*core.RelationField)
+			if !ok || relField.CollectionId != c.Id || (relField.Hidden && !g.superuser) {
+				continue
+			}
+
+			prefix := g.prefixes[other.Id]
+			name := strings.ToLower(prefix[:1]) + prefix[1:] + "Via" + inflector.UcFirst(inflector.Camelize(relField.Name))
+			if !graphQLNameRegex.MatchString(name) || obj.Field(name) != nil {
+				continue
+			}
+
+			op := "="
+			if relField.IsMultiple() {
+				op = "?="
+			}
+
+			obj.Fields = append(obj.Fields, &graphql.Field{
+				Name:        name,
+				Description: "Returns the " + other.Name + " records that reference the current record via the " + relField.Name + " field.",
+				Type:        graphql.NewNonNull(g.lists[other.Id]),
+				Args:        g.listArgs(),
+				Complexity:  listComplexity,
+				Resolve:     g.listResolver(other, "("+relField.Name+op+"{id})"),
+			})
+		}
+	}
+}
+
+func (g *graphQLGenerator) collection(id string) *core.Collection {
+	for _, c := range g.collections {
+		if c.Id == id {
+			return c
+		}
+	}
+	return nil
+}
+
+func relationResolver(relField *core.RelationField) graphql.ResolveFunc {
+	name := relField.Name
+	collectionId := relField.CollectionId
+	isMultiple := relField.IsMultiple()
+
+	return func(p graphql.ResolveParams) (any, error) {
+		source, _ := p.Source.(map[string]any)
+
+		ids := graphQLRelationIds(source[name])
+		if len(ids) == 0 {
+			if isMultiple {
+				return []map[string]any{}, nil
+			}
+			return nil, nil
+		}
+
+		state, err := graphQLRequestFromContext(p.Context)
+		if err != nil {
+			return nil, err
+		}
+
+		records, err := state.loadRelated(collectionId, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		if isMultiple {
+			return records, nil
+		}
+
+		if len(records) == 0 {
+			return nil, nil
+		}
+
+		return records[0], nil
+	}
+}
@@ -0,0 +1,501 @@
+package apis_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/graphql"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func setupGraphQLTest(t testing.TB, app core.App, enabled bool) {
+	app.Settings().GraphQL.Enabled = enabled
+	app.Settings().GraphQL.MaxDepth = 5
+	app.Settings().GraphQL.MaxComplexity = 1000
+
+	private := core.NewBaseCollection("gql_private")
+	private.Fields.Add(&core.TextField{Name: "name"})
+	if err := app.Save(private); err != nil {
+		t.Fatal(err)
+	}
+
+	authors := core.NewBaseCollection("gql_authors")
+	authors.ListRule = types.Pointer("")
+	authors.ViewRule = types.Pointer("")
+	authors.Fields.Add(
+		&core.TextField{Name: "name"},
+		&core.TextField{Name: "secret", Hidden: true},
+	)
+	if err := app.Save(authors); err != nil {
+		t.Fatal(err)
+	}
+
+	posts := core.NewBaseCollection("gql_posts")
+	posts.ListRule = types.Pointer("title != 'Unlisted'")
+	posts.ViewRule = types.Pointer("")
+	posts.CreateRule = types.Pointer("@request.auth.id != ''")
+	posts.UpdateRule = types.Pointer("@request.auth.id != '' && @request.context = 'graphql'")
+	posts.Fields.Add(
+		&core.TextField{Name: "title", Required: true},
+		&core.NumberField{Name: "views", OnlyInt: true},
+		&core.RelationField{Name: "author", CollectionId: authors.Id, MaxSelect: 1},
+		&core.RelationField{Name: "private", CollectionId: private.Id, MaxSelect: 1},
+	)
+	if err := app.Save(posts); err != nil {
+		t.Fatal(err)
+	}
+
+	privateRecord := core.NewRecord(private)
+	privateRecord.Id = "gqlprivate00001"
+	privateRecord.Set("name", "private")
+	if err := app.Save(privateRecord); err != nil {
+		t.Fatal(err)
+	}
+
+	author := core.NewRecord(authors)
+	author.Id = "gqlauthor000001"
+	author.Set("name", "Alice")
+	author.Set("secret", "abc")
+	if err := app.Save(author); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, title := range []string{"First", "Second", "Unlisted"} {
+		post := core.NewRecord(posts)
+		post.Id = "gqlpost0000000" + string(rune('1'+i))
+		post.Set("title", title)
+		post.Set("views", i*10)
+		post.Set("author", author.Id)
+		post.Set("private", privateRecord.Id)
+		if err := app.Save(post); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func graphQLBody(t testing.TB, query string, variables map[string]any) *strings.Reader {
+	raw, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.NewReader(string(raw))
+}
+
+func TestGraphQLApi(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:   "disabled",
+			Method: http.MethodPost,
+			URL:    "/api/graphql",
+			Body:   graphQLBody(t, "{ gqlPostsList { totalItems } }", nil),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupGraphQLTest(t, app, false)
+			},
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "missing query",
+			Method: http.MethodPost,
+			URL:    "/api/graphql",
+			Body:   graphQLBody(t, " ", nil),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupGraphQLTest(t, app, true)
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "guest list with list rule, nested relations and hidden fields",
+			Method: http.MethodPost,
+			URL:    "/api/graphql",
+			Body: graphQLBody(t, `{
+				gqlPostsList(sort: "title") {
+					totalItems
+					items { id title views author { name } private }
+				}
+			}`, nil),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupGraphQLTest(t, app, true)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`{"data":{"gqlPostsList":{"totalItems":2,"items":[` +
+					`{"id":"gqlpost00000001","title":"First","views":0,"author":{"name":"Alice"},"private":"gqlprivate00001"},` +
+					`{"id":"gqlpost00000002","title":"Second","views":10,"author":{"name":"Alice"},"private":"gqlprivate00001"}]}}}`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                    0,
+				"OnRecordsListRequest": 1,
+				"OnRecordEnrich":       3,
+			},
+		},
+		{
+			Name:   "guest query of a hidden field",
+			Method: http.MethodPost,
+			URL:    "/api/graphql",
+			Body:   graphQLBody(t, `{ gqlAuthors(id: "gqlauthor000001") { secret } }`, nil),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupGraphQLTest(t, app, true)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`{"errors":[{"message":"Cannot query field \"secret\" on type \"GqlAuthorsRecord\"."}]}`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+		{
+			Name:   "guest query of a superuser only collection",
+			Method: http.MethodPost,
+			URL:    "/api/graphql",
+			Body:   graphQLBody(t, `{ gqlPrivateList { totalItems } }`, nil),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupGraphQLTest(t, app, true)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`{"errors":[{"message":"Cannot query field \"gqlPrivateList\" on type \"Query\"."}]}`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+		{
+			Name:   "view of a record excluded by the list rule and back-relations",
+			Method: http.MethodPost,
+			URL:    "/api/graphql",
+			Body: graphQLBody(t, `query ($id: ID!) {
+				post: gqlPosts(id: $id) { title }
+				author: gqlAuthors(id: "gqlauthor000001") {
+					name
+					gqlPostsViaAuthor(sort: "-title", filter: "views > 0") { items { title } }
+				}
+			}`, map[string]any{"id": "gqlpost00000003"}),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupGraphQLTest(t, app, true)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`{"data":{"post":{"title":"Unlisted"},"author":{"name":"Alice","gqlPostsViaAuthor":{"items":[{"title":"Second"}]}}}}`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                    0,
+				"OnRecordViewRequest":  2,
+				"OnRecordsListRequest": 1,
+				"OnRecordEnrich":       3,
+			},
+		},
+		{
+			Name:   "missing record",
+			Method: http.MethodPost,
+			URL:    "/api/graphql",
+			Body:   graphQLBody(t, `{ gqlPosts(id: "missing") { title } }`, nil),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupGraphQLTest(t, app, true)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"errors":[{"message":"The requested resource wasn't found.","path":["gqlPosts"],"extensions":{"status":404}}]`,
+				`"data":{"gqlPosts":null}`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+		{
+			Name:   "invalid filter",
+			Method: http.MethodPost,
+			URL:    "/api/graphql",
+			Body:   graphQLBody(t, `{ gqlPostsList(filter: "missing = 1") { totalItems } }`, nil),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupGraphQLTest(t, app, true)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"extensions":{"status":400}`,
+				`"data":null`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+		{
+			Name:   "max depth",
+			Method: http.MethodPost,
+			URL:    "/api/graphql",
+			Body:   graphQLBody(t, `{ gqlAuthors(id: "a") { gqlPostsViaAuthor { items { author { gqlPostsViaAuthor { totalItems } } } } } }`, nil),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupGraphQLTest(t, app, true)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`{"errors":[{"message":"The query depth 6 exceeds the max allowed depth of 5."}]}`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+		{
+			Name:   "max complexity",
+			Method: http.MethodPost,
+			URL:    "/api/graphql",
+			Body:   graphQLBody(t, `{ gqlPostsList(perPage: 500) { items { id title } } }`, nil),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupGraphQLTest(t, app, true)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`{"errors":[{"message":"The query complexity 1501 exceeds the max allowed complexity of 1000."}]}`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+		{
+			Name:   "GET query with variables",
+			Method: http.MethodGet,
+			URL: "/api/graphql?" + url.Values{
+				"query":     {`query ($filter: String) { gqlPostsList(filter: $filter) { items { title } } }`},
+				"variables": {`{"filter":"title = 'Second'"}`},
+			}.Encode(),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupGraphQLTest(t, app, true)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`{"data":{"gqlPostsList":{"items":[{"title":"Second"}]}}}`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                    0,
+				"OnRecordsListRequest": 1,
+				"OnRecordEnrich":       1,
+			},
+		},
+		{
+			Name:   "GET mutation",
+			Method: http.MethodGet,
+			URL: "/api/graphql?" + url.Values{
+				"query": {`mutation { createGqlPosts(data: {title: "new"}) { id } }`},
+			}.Encode(),
+			Headers: map[string]string{
+				"Authorization": fieldRulesTestUserToken,
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupGraphQLTest(t, app, true)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`{"errors":[{"message":"Mutations are not allowed for this request."}]}`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+		{
+			Name:   "guest create mutation (create rule failure)",
+			Method: http.MethodPost,
+			URL:    "/api/graphql",
+			Body:   graphQLBody(t, `mutation { createGqlPosts(data: {title: "new"}) { id } }`, nil),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupGraphQLTest(t, app, true)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"path":["createGqlPosts"],"extensions":{"status":400}`,
+				`"data":{"createGqlPosts":null}`,
+			},
+			ExpectedEvents: map[string]int{
+				"*": 0,
+			},
+		},
+		{
+			Name:   "user create mutation with validation errors",
+			Method: http.MethodPost,
+			URL:    "/api/graphql",
+			Body:   graphQLBody(t, `mutation { createGqlPosts(data: {views: 1}) { id } }`, nil),
+			Headers: map[string]string{
+				"Authorization": fieldRulesTestUserToken,
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupGraphQLTest(t, app, true)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"extensions":{"data":{"title":{"code":"validation_required"`,
+				`"data":{"createGqlPosts":null}`,
+			},
+			ExpectedEvents: map[string]int{
+				"OnRecordCreateRequest":      1,
+				"OnRecordAfterCreateError":   1,
+				"OnRecordAfterCreateSuccess": 0,
+				"OnRecordEnrich":             0,
+			},
+		},
+		{
+			Name:   "user create and update mutations",
+			Method: http.MethodPost,
+			URL:    "/api/graphql",
+			Body: graphQLBody(t, `mutation ($data: GqlPostsCreateInput!) {
+				created: createGqlPosts(data: $data) { title views author { name } }
+				updated: updateGqlPosts(id: "gqlpost00000001", data: {title: "First (updated)", author: null}) { title author { name } }
+			}`, map[string]any{"data": map[string]any{"title": "new", "views": 5, "author": "gqlauthor000001"}}),
+			Headers: map[string]string{
+				"Authorization": fieldRulesTestUserToken,
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupGraphQLTest(t, app, true)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`{"data":{"created":{"title":"new","views":5,"author":{"name":"Alice"}},"updated":{"title":"First (updated)","author":null}}}`,
+			},
+			ExpectedEvents: map[string]int{
+				"OnRecordCreateRequest":      1,
+				"OnRecordUpdateRequest":      1,
+				"OnRecordAfterCreateSuccess": 1,
+				"OnRecordAfterUpdateSuccess": 1,
+			},
+		},
+		{
+			Name:   "guest delete mutation (superuser only)",
+			Method: http.MethodPost,
+			URL:    "/api/graphql",
+			Body:   graphQLBody(t, `mutation { deleteGqlPosts(id: "gqlpost00000001") }`, nil),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupGraphQLTest(t, app, true)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`{"errors":[{"message":"Cannot query field \"deleteGqlPosts\" on type \"Mutation\"."}]}`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+		{
+			Name:   "superuser delete mutation and superuser only fields",
+			Method: http.MethodPost,
+			URL:    "/api/graphql",
+			Body: graphQLBody(t, `mutation {
+				deleteGqlPosts(id: "gqlpost00000002")
+				updateGqlAuthors(id: "gqlauthor000001", data: {secret: "new"}) { secret }
+			}`, nil),
+			Headers: map[string]string{
+				"Authorization": revisionsTestSuperuserToken,
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupGraphQLTest(t, app, true)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`{"data":{"deleteGqlPosts":true,"updateGqlAuthors":{"secret":"new"}}}`,
+			},
+			ExpectedEvents: map[string]int{
+				"OnRecordDeleteRequest":      1,
+				"OnRecordAfterDeleteSuccess": 1,
+				"OnRecordUpdateRequest":      1,
+			},
+		},
+		{
+			Name:   "superuser nested superuser only relation",
+			Method: http.MethodPost,
+			URL:    "/api/graphql",
+			Body:   graphQLBody(t, `{ gqlPosts(id: "gqlpost00000003") { private { name } } }`, nil),
+			Headers: map[string]string{
+				"Authorization": revisionsTestSuperuserToken,
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupGraphQLTest(t, app, true)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`{"data":{"gqlPosts":{"private":{"name":"private"}}}}`,
+			},
+		},
+		{
+			Name:   "introspection",
+			Method: http.MethodPost,
+			URL:    "/api/graphql",
+			Body:   graphQLBody(t, `{ __type(name: "GqlPostsRecord") { name fields { name } } }`, nil),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupGraphQLTest(t, app, true)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`{"data":{"__type":{"name":"GqlPostsRecord","fields":[{"name":"id"},{"name":"title"},{"name":"views"},{"name":"author"},{"name":"private"},{"name":"collectionId"},{"name":"collectionName"}]}}}`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+		{
+			Name:   "guest schema SDL",
+			Method: http.MethodGet,
+			URL:    "/api/graphql/schema.graphql",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupGraphQLTest(t, app, true)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				"type GqlAuthorsRecord {",
+				"gqlPostsViaAuthor(filter: String, sort: String, page: Int = 1, perPage: Int = 30, skipTotal: Boolean = false): GqlPostsRecordList!",
+				"author: GqlAuthorsRecord",
+				"private: ID",
+				"createGqlPosts(data: GqlPostsCreateInput!): GqlPostsRecord",
+				"input GqlPostsUpdateInput {",
+			},
+			NotExpectedContent: []string{
+				"secret",
+				"GqlPrivateRecord",
+				"deleteGqlPosts",
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+		{
+			Name:   "superuser schema SDL",
+			Method: http.MethodGet,
+			URL:    "/api/graphql/schema.graphql",
+			Headers: map[string]string{
+				"Authorization": revisionsTestSuperuserToken,
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupGraphQLTest(t, app, true)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"Hidden field (visible only for superusers)."`,
+				"secret: String",
+				"type GqlPrivateRecord {",
+				"private: GqlPrivateRecord",
+				"deleteGqlPosts(id: ID!): Boolean!",
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestGraphQLSchemaRegenerate(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	// the base test collections must produce a valid schema
+	for _, superuser := range []bool{true, false} {
+		if _, err := apis.GraphQLSchema(app, superuser); err != nil {
+			t.Fatalf("[superuser %v] Failed to generate the base schema: %v", superuser, err)
+		}
+	}
+
+	setupGraphQLTest(t, app, true)
+
+	schema, err := apis.GraphQLSchema(app, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// executing outside of the api handler should fail gracefully
+	result := schema.Execute(context.Background(), graphql.Request{Query: `{ gqlPostsList { totalItems } }`})
+	if len(result.Errors) != 1 || result.Errors[0].Message != "missing GraphQL request context" {
+		t.Fatalf("Expected missing context error, got %v", result.Errors)
+	}
+}
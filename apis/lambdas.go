@@ -22,6 +22,13 @@ type LambdaFunctionCreateRequest struct {
 	Triggers    map[string]interface{} `json:"triggers" form:"triggers"`
 	EnvVars     map[string]string      `json:"env_vars" form:"env_vars"`
 	Description string                 `json:"description" form:"description"`
+	KeepWarm    bool                   `json:"keepWarm" form:"keepWarm"`
+
+	// Environment is the function deployment environment (default to the current app environment).
+	Environment string `json:"environment" form:"environment"`
+
+	// Environments holds the per environment function overrides.
+	Environments map[string]*core.LambdaEnvironmentConfig `json:"environments" form:"environments"`
 }
 
 // LambdaFunctionUpdateRequest represents the request for updating a lambda function
@@ -33,6 +40,13 @@ type LambdaFunctionUpdateRequest struct {
 	Triggers    map[string]interface{} `json:"triggers" form:"triggers"`
 	EnvVars     map[string]string      `json:"env_vars" form:"env_vars"`
 	Description string                 `json:"description" form:"description"`
+	KeepWarm    *bool                  `json:"keepWarm" form:"keepWarm"`
+
+	// Environment is the function deployment environment.
+	Environment string `json:"environment" form:"environment"`
+
+	// Environments holds the per environment function overrides.
+	Environments map[string]*core.LambdaEnvironmentConfig `json:"environments" form:"environments"`
 }
 
 // BindLambdaFunctionRoutes binds the lambda function API routes
@@ -69,6 +83,8 @@ func (api *lambdaFunctionAPI) list(e *core.RequestEvent) error {
 			"enabled":     record.GetBool("enabled"),
 			"timeout":     record.GetInt("timeout") / 1000, // Convert ms to seconds for display
 			"description": record.GetString("description"),
+			"keepWarm":    record.GetBool("keepWarm"),
+			"environment": record.GetString("environment"),
 			"created":     record.GetDateTime("created"),
 			"updated":     record.GetDateTime("updated"),
 		}
@@ -132,6 +148,17 @@ func (api *lambdaFunctionAPI) create(e *core.RequestEvent) error {
 	record.Set("enabled", form.Enabled)
 	record.Set("timeout", timeoutMs)
 	record.Set("description", form.Description)
+	record.Set("keepWarm", form.KeepWarm)
+
+	if form.Environment == "" {
+		form.Environment = api.app.Environment()
+	}
+	record.Set("environment", form.Environment)
+
+	if form.Environments != nil {
+		environmentsJSON, _ := json.Marshal(form.Environments)
+		record.Set("environments", string(environmentsJSON))
+	}
 
 	// Convert triggers to JSON
 	triggersJSON, _ := json.Marshal(form.Triggers)
@@ -151,6 +178,8 @@ func (api *lambdaFunctionAPI) create(e *core.RequestEvent) error {
 		"enabled":     record.GetBool("enabled"),
 		"timeout":     record.GetInt("timeout") / 1000, // Convert ms to seconds for display
 		"description": record.GetString("description"),
+		"keepWarm":    record.GetBool("keepWarm"),
+		"environment": record.GetString("environment"),
 		"created":     record.GetDateTime("created"),
 		"updated":     record.GetDateTime("updated"),
 	})
@@ -164,16 +193,19 @@ func (api *lambdaFunctionAPI) view(e *core.RequestEvent) error {
 	}
 
 	return e.JSON(200, map[string]interface{}{
-		"id":          record.Id,
-		"name":        record.GetString("name"),
-		"code":        record.GetString("code"),
-		"enabled":     record.GetBool("enabled"),
-		"timeout":     record.GetInt("timeout") / 1000, // Convert ms to seconds for display
-		"triggers":    record.GetString("triggers"),
-		"env_vars":    record.GetString("envVars"),
-		"description": record.GetString("description"),
-		"created":     record.GetDateTime("created"),
-		"updated":     record.GetDateTime("updated"),
+		"id":           record.Id,
+		"name":         record.GetString("name"),
+		"code":         record.GetString("code"),
+		"enabled":      record.GetBool("enabled"),
+		"timeout":      record.GetInt("timeout") / 1000, // Convert ms to seconds for display
+		"triggers":     record.GetString("triggers"),
+		"env_vars":     record.GetString("envVars"),
+		"description":  record.GetString("description"),
+		"keepWarm":     record.GetBool("keepWarm"),
+		"environment":  record.GetString("environment"),
+		"environments": record.GetString("environments"),
+		"created":      record.GetDateTime("created"),
+		"updated":      record.GetDateTime("updated"),
 	})
 }
 
@@ -218,6 +250,10 @@ func (api *lambdaFunctionAPI) update(e *core.RequestEvent) error {
 		record.Set("description", form.Description)
 	}
 
+	if form.KeepWarm != nil {
+		record.Set("keepWarm", *form.KeepWarm)
+	}
+
 	if form.Triggers != nil {
 		if err := validateTriggers(form.Triggers); err != nil {
 			return e.BadRequestError("Invalid trigger configuration", err)
@@ -231,6 +267,15 @@ func (api *lambdaFunctionAPI) update(e *core.RequestEvent) error {
 		record.Set("envVars", string(envVarsJSON))
 	}
 
+	if form.Environment != "" {
+		record.Set("environment", form.Environment)
+	}
+
+	if form.Environments != nil {
+		environmentsJSON, _ := json.Marshal(form.Environments)
+		record.Set("environments", string(environmentsJSON))
+	}
+
 	if err := api.app.Save(record); err != nil {
 		return e.BadRequestError("Failed to update lambda function", err)
 	}
@@ -241,6 +286,8 @@ func (api *lambdaFunctionAPI) update(e *core.RequestEvent) error {
 		"enabled":     record.GetBool("enabled"),
 		"timeout":     record.GetInt("timeout") / 1000, // Convert ms to seconds for display
 		"description": record.GetString("description"),
+		"keepWarm":    record.GetBool("keepWarm"),
+		"environment": record.GetString("environment"),
 		"created":     record.GetDateTime("created"),
 		"updated":     record.GetDateTime("updated"),
 	})
@@ -276,10 +323,20 @@ func (api *lambdaFunctionAPI) execute(e *core.RequestEvent) error {
 	functionName := record.GetString("name")
 	timeoutMs := record.GetInt("timeout")
 
+	// Reuse the request trace id so that the execution could be linked with the request log
+	requestId := core.TraceIdFromContext(e.Request.Context())
+	if requestId == "" {
+		requestId = core.NewTraceId()
+	}
+
 	// Execute the function
 	startTime := time.Now()
 	
 	result, err := executeLambdaFunction(api.app, code, functionName, timeoutMs, map[string]interface{}{
+		"$trace": map[string]interface{}{
+			"id":          requestId,
+			"traceparent": core.FormatTraceparent(requestId),
+		},
 		"request": map[string]interface{}{
 			"method": e.Request.Method,
 			"url":    e.Request.URL.String(),
@@ -296,7 +353,7 @@ func (api *lambdaFunctionAPI) execute(e *core.RequestEvent) error {
 		errorMsg = err.Error()
 	}
 
-	logExecution(api.app, record.Id, functionName, "manual", success, result, errorMsg, duration, map[string]interface{}{
+	logExecution(api.app, record.Id, functionName, "manual", requestId, success, result, errorMsg, duration, map[string]interface{}{
 		"request": map[string]interface{}{
 			"method": e.Request.Method,
 			"url":    e.Request.URL.String(),
@@ -308,6 +365,7 @@ func (api *lambdaFunctionAPI) execute(e *core.RequestEvent) error {
 			"success":     false,
 			"error":       err.Error(),
 			"duration_ms": duration.Milliseconds(),
+			"request_id":  requestId,
 			"timestamp":   time.Now(),
 		})
 	}
@@ -316,6 +374,7 @@ func (api *lambdaFunctionAPI) execute(e *core.RequestEvent) error {
 		"success":     true,
 		"output":      result,
 		"duration_ms": duration.Milliseconds(),
+		"request_id":  requestId,
 		"timestamp":   time.Now(),
 	})
 }
@@ -346,6 +405,7 @@ func (api *lambdaFunctionAPI) logs(e *core.RequestEvent) error {
 			"error":        record.GetString("error"),
 			"duration_ms":  record.GetInt("duration_ms"),
 			"context":      record.Get("context"),
+			"request_id":   record.GetString("request_id"),
 			"timestamp":    record.GetDateTime("created"),
 		}
 	}
@@ -426,6 +486,20 @@ func validateTriggers(triggers map[string]interface{}) error {
 					if !isValidPath(path.(string)) {
 						return fmt.Errorf("Invalid path: %s", path)
 					}
+
+					if summary, ok := httpTrigger["summary"]; ok {
+						if _, isString := summary.(string); !isString {
+							return fmt.Errorf("HTTP trigger summary must be a string")
+						}
+					}
+
+					for _, key := range []string{"requestSchema", "responseSchema"} {
+						if schema, ok := httpTrigger[key]; ok && schema != nil {
+							if _, isObject := schema.(map[string]interface{}); !isObject {
+								return fmt.Errorf("HTTP trigger %s must be a JSON Schema object", key)
+							}
+						}
+					}
 				}
 			}
 		}
@@ -451,6 +525,26 @@ func validateTriggers(triggers map[string]interface{}) error {
 		}
 	}
 
+	// Validate realtime triggers
+	if realtimeTriggers, ok := triggers["realtime"]; ok {
+		if realtimeList, ok := realtimeTriggers.([]interface{}); ok {
+			for _, trigger := range realtimeList {
+				if realtimeTrigger, ok := trigger.(map[string]interface{}); ok {
+					topic, _ := realtimeTrigger["topic"].(string)
+					if topic == "" {
+						return fmt.Errorf("Realtime trigger must have topic")
+					}
+
+					if rule, ok := realtimeTrigger["publishRule"]; ok && rule != nil {
+						if _, isString := rule.(string); !isString {
+							return fmt.Errorf("Realtime trigger publishRule must be a string or null")
+						}
+					}
+				}
+			}
+		}
+	}
+
 	// Validate cron triggers
 	if cronTriggers, ok := triggers["cron"]; ok {
 		if cronList, ok := cronTriggers.([]interface{}); ok {
@@ -645,7 +739,7 @@ func recordToMap(record *core.Record) map[string]interface{} {
 }
 
 // logExecution logs function execution to the database
-func logExecution(app core.App, functionId, functionName, triggerType string, success bool, output interface{}, errorMsg string, duration time.Duration, context map[string]interface{}) {
+func logExecution(app core.App, functionId, functionName, triggerType, requestId string, success bool, output interface{}, errorMsg string, duration time.Duration, context map[string]interface{}) {
 	collection, err := app.FindCollectionByNameOrId("lambda_logs")
 	if err != nil {
 		fmt.Printf("Failed to find logs collection: %v\n", err)
@@ -656,6 +750,7 @@ func logExecution(app core.App, functionId, functionName, triggerType string, su
 	record.Set("function_id", functionId)
 	record.Set("function_name", functionName)
 	record.Set("trigger_type", triggerType)
+	record.Set("request_id", requestId)
 	record.Set("success", success)
 	record.Set("duration_ms", int(duration.Milliseconds()))
 	record.Set("error", errorMsg)
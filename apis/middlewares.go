@@ -31,6 +31,9 @@ const (
 	DefaultWWWRedirectMiddlewarePriority = -99999
 	DefaultWWWRedirectMiddlewareId       = "pbWWWRedirect"
 
+	DefaultRequestTraceMiddlewarePriority = DefaultRateLimitMiddlewarePriority - 50
+	DefaultRequestTraceMiddlewareId       = "pbRequestTrace"
+
 	DefaultActivityLoggerMiddlewarePriority   = DefaultRateLimitMiddlewarePriority - 40
 	DefaultActivityLoggerMiddlewareId         = "pbActivityLogger"
 	DefaultSkipSuccessActivityLogMiddlewareId = "pbSkipSuccessActivityLog"
@@ -309,6 +312,32 @@ func SkipSuccessActivityLog() *hook.Handler[*core.RequestEvent] {
 	}
 }
 
+// requestTrace middleware assigns a trace id to the request.
+//
+// The trace id is taken from the W3C "traceparent" request header
+// or a new one is generated if the header is missing or invalid.
+//
+// The trace id is stored in the request context (see [core.TraceIdFromContext])
+// and it is returned to the client with the "X-Request-Id" response header.
+func requestTrace() *hook.Handler[*core.RequestEvent] {
+	return &hook.Handler[*core.RequestEvent]{
+		Id:       DefaultRequestTraceMiddlewareId,
+		Priority: DefaultRequestTraceMiddlewarePriority,
+		Func: func(e *core.RequestEvent) error {
+			traceId := core.ParseTraceparent(e.Request.Header.Get(core.TraceparentHeader))
+			if traceId == "" {
+				traceId = core.NewTraceId()
+			}
+
+			e.Request = e.Request.WithContext(core.ContextWithTraceId(e.Request.Context(), traceId))
+
+			e.Response.Header().Set(core.RequestIdHeader, traceId)
+
+			return e.Next()
+		},
+	}
+}
+
 // activityLogger middleware takes care to save the request information
 // into the logs database.
 //
@@ -359,6 +388,10 @@ func logRequest(event *core.RequestEvent, err error) {
 		attrs = append(attrs, slog.Any("meta", meta))
 	}
 
+	if requestId := core.TraceIdFromContext(event.Request.Context()); requestId != "" {
+		attrs = append(attrs, slog.String("requestId", requestId))
+	}
+
 	status := event.Status()
 	method := cutStr(strings.ToUpper(event.Request.Method), 50)
 	requestUri := cutStr(event.Request.URL.RequestURI(), 3000)
@@ -48,6 +48,62 @@ func TestPanicRecover(t *testing.T) {
 	}
 }
 
+func TestRequestTrace(t *testing.T) {
+	t.Parallel()
+
+	const traceId = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:   "valid traceparent header",
+			Method: http.MethodGet,
+			URL:    "/my/test",
+			Headers: map[string]string{
+				"traceparent": "00-" + traceId + "-00f067aa0ba902b7-01",
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				e.Router.GET("/my/test", func(e *core.RequestEvent) error {
+					return e.String(http.StatusOK, core.TraceIdFromContext(e.Request.Context()))
+				})
+			},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				if v := res.Header.Get("X-Request-Id"); v != traceId {
+					t.Fatalf("Expected X-Request-Id %q, got %q", traceId, v)
+				}
+			},
+			ExpectedStatus:  200,
+			ExpectedContent: []string{traceId},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "invalid traceparent header",
+			Method: http.MethodGet,
+			URL:    "/my/test",
+			Headers: map[string]string{
+				"traceparent": "00-" + traceId + "-0000000000000000-01",
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				e.Router.GET("/my/test", func(e *core.RequestEvent) error {
+					return e.String(http.StatusOK, core.TraceIdFromContext(e.Request.Context()))
+				})
+			},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				v := res.Header.Get("X-Request-Id")
+				if len(v) != 32 || v == traceId {
+					t.Fatalf("Expected new generated X-Request-Id, got %q", v)
+				}
+			},
+			ExpectedStatus:     200,
+			NotExpectedContent: []string{traceId},
+			ExpectedEvents:     map[string]int{"*": 0},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
 func TestRequireGuestOnly(t *testing.T) {
 	t.Parallel()
 
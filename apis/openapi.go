@@ -0,0 +1,971 @@
+package apis
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sync"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/inflector"
+	"github.com/pocketbase/pocketbase/tools/openapi"
+	"github.com/pocketbase/pocketbase/tools/router"
+)
+
+// bindOpenAPIApi registers the OpenAPI document api endpoint.
+func bindOpenAPIApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	cache := &openAPICache{}
+
+	// regenerate the document on the next request after collection or settings changes
+	resetCollection := func(e *core.CollectionEvent) error {
+		cache.reset()
+		return e.Next()
+	}
+	app.OnCollectionAfterCreateSuccess().Bind(&hook.Handler[*core.CollectionEvent]{Func: resetCollection})
+	app.OnCollectionAfterUpdateSuccess().Bind(&hook.Handler[*core.CollectionEvent]{Func: resetCollection})
+	app.OnCollectionAfterDeleteSuccess().Bind(&hook.Handler[*core.CollectionEvent]{Func: resetCollection})
+	app.OnSettingsReload().Bind(&hook.Handler[*core.SettingsReloadEvent]{
+		Func: func(e *core.SettingsReloadEvent) error {
+			cache.reset()
+			return e.Next()
+		},
+	})
+
+	rg.GET("/openapi.json", openAPIDocument(cache))
+}
+
+// openAPICache stores the last generated encoded OpenAPI documents
+// (one for the superusers and one for everyone else).
+type openAPICache struct {
+	mu   sync.Mutex
+	docs map[bool][]byte
+}
+
+func (c *openAPICache) reset() {
+	c.mu.Lock()
+	c.docs = nil
+	c.mu.Unlock()
+}
+
+func (c *openAPICache) load(app core.App, superuser bool) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if raw, ok := c.docs[superuser]; ok {
+		return raw, nil
+	}
+
+	doc, err := OpenAPIDocument(app, superuser)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.docs == nil {
+		c.docs = map[bool][]byte{}
+	}
+	c.docs[superuser] = raw
+
+	return raw, nil
+}
+
+func openAPIDocument(cache *openAPICache) func(e *core.RequestEvent) error {
+	return func(e *core.RequestEvent) error {
+		raw, err := cache.load(e.App, e.HasSuperuserAuth())
+		if err != nil {
+			return e.InternalServerError("Failed to generate the OpenAPI document.", err)
+		}
+
+		return e.Blob(http.StatusOK, "application/json", raw)
+	}
+}
+
+// -------------------------------------------------------------------
+
+const (
+	openAPISecurityScheme = "authToken"
+	openAPISystemTag      = "system"
+	openAPISuperuserOnly  = "Only superusers can perform this action."
+)
+
+// OpenAPIDocument generates an OpenAPI 3.1 document describing the
+// records endpoints of the current collections and the system endpoints.
+//
+// If includeSuperuserOnly is false, the endpoints that are accessible only
+// by superusers (aka. with nil collection API rule) and the hidden fields
+// from the records responses are excluded from the document.
+func OpenAPIDocument(app core.App, includeSuperuserOnly bool) (*openapi.Document, error) {
+	collections, err := app.FindAllCollections()
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(collections, func(a, b *core.Collection) int {
+		if a.Name < b.Name {
+			return -1
+		}
+		if a.Name > b.Name {
+			return 1
+		}
+		return 0
+	})
+
+	settings := app.Settings()
+
+	doc := openapi.NewDocument(settings.Meta.AppName+" API", "1.0.0")
+	doc.Info.Description = "Auto-generated from the current collections schema."
+	if settings.Meta.AppURL != "" {
+		doc.Servers = []openapi.Server{{URL: settings.Meta.AppURL}}
+	}
+	doc.Components = &openapi.Components{
+		Schemas: map[string]openapi.Schema{
+			"ApiError": openapi.ErrorSchema(),
+		},
+		SecuritySchemes: map[string]*openapi.SecurityScheme{
+			openAPISecurityScheme: {
+				Type:        "apiKey",
+				In:          "header",
+				Name:        "Authorization",
+				Description: "The auth record or superuser token.",
+			},
+		},
+	}
+
+	g := &openAPIGenerator{
+		doc:          doc,
+		superuser:    includeSuperuserOnly,
+		schemaPrefix: make(map[string]string, len(collections)),
+	}
+
+	usedPrefixes := map[string]struct{}{}
+	for _, c := range collections {
+		prefix := inflector.UcFirst(inflector.Camelize(c.Name))
+		if _, ok := usedPrefixes[prefix]; ok || prefix == "" {
+			prefix += inflector.UcFirst(c.Id) // eg. "_superusers" and "superusers"
+		}
+		usedPrefixes[prefix] = struct{}{}
+		g.schemaPrefix[c.Id] = prefix
+	}
+
+	for _, c := range collections {
+		g.addCollection(c)
+	}
+
+	g.addSystem()
+
+	return doc, nil
+}
+
+type openAPIGenerator struct {
+	doc          *openapi.Document
+	schemaPrefix map[string]string // collection id -> components schema name prefix
+	superuser    bool
+}
+
+func (g *openAPIGenerator) ref(name string) openapi.Schema {
+	return openapi.Schema{"$ref": "#/components/schemas/" + name}
+}
+
+func (g *openAPIGenerator) errorResponse(description string) *openapi.Response {
+	return &openapi.Response{
+		Description: description,
+		Content:     openapi.JSONContent(g.ref("ApiError")),
+	}
+}
+
+// addOperation registers op if the related rule is accessible by the document consumer.
+//
+// A nil rule marks the operation as superuser only.
+func (g *openAPIGenerator) addOperation(method string, path string, rule *string, op *openapi.Operation) {
+	if rule == nil {
+		if !g.superuser {
+			return
+		}
+
+		op.Security = []map[string][]string{{openAPISecurityScheme: {}}}
+		if op.Description == "" {
+			op.Description = openAPISuperuserOnly
+		} else {
+			op.Description += "\n\n" + openAPISuperuserOnly
+		}
+	} else if *rule != "" {
+		// the access depends on the rule so the auth token is optional
+		op.Security = []map[string][]string{{openAPISecurityScheme: {}}, {}}
+	}
+
+	if op.Responses == nil {
+		op.Responses = map[string]*openapi.Response{}
+	}
+	if rule == nil || *rule != "" {
+		op.Responses["403"] = g.errorResponse("The request is not allowed to perform this action.")
+	}
+
+	g.doc.AddOperation(method, path, op)
+}
+
+// collection endpoints
+// -------------------------------------------------------------------
+
+func (g *openAPIGenerator) addCollection(c *core.Collection) {
+	var hasOperations bool
+
+	addOperation := func(method string, path string, rule *string, op *openapi.Operation) {
+		if rule == nil && !g.superuser {
+			return
+		}
+		hasOperations = true
+		op.Tags = []string{c.Name}
+		g.addOperation(method, path, rule, op)
+	}
+
+	prefix := g.schemaPrefix[c.Id]
+	recordRef := g.ref(prefix + "Record")
+	basePath := "/api/collections/" + c.Name
+
+	addOperation(http.MethodGet, basePath+"/records", c.ListRule, &openapi.Operation{
+		OperationId: c.Name + "_list",
+		Summary:     "List " + c.Name + " records",
+		Parameters:  openAPIListParameters(),
+		Responses: map[string]*openapi.Response{
+			"200": {
+				Description: "Paginated records list.",
+				Content:     openapi.JSONContent(openAPIListResultSchema(recordRef)),
+			},
+			"400": g.errorResponse("Invalid filter, sort or pagination parameters."),
+		},
+	})
+
+	addOperation(http.MethodGet, basePath+"/records/{id}", c.ViewRule, &openapi.Operation{
+		OperationId: c.Name + "_view",
+		Summary:     "View a single " + c.Name + " record",
+		Parameters:  append([]*openapi.Parameter{openAPIPathParameter("id")}, openAPIViewParameters()...),
+		Responses: map[string]*openapi.Response{
+			"200": {Description: "The found record.", Content: openapi.JSONContent(recordRef)},
+			"404": g.errorResponse("Missing or inaccessible record."),
+		},
+	})
+
+	addOperation(http.MethodGet, basePath+"/export", c.ListRule, &openapi.Operation{
+		OperationId: c.Name + "_export",
+		Summary:     "Export " + c.Name + " records",
+		Parameters: append([]*openapi.Parameter{
+			{
+				Name:   "format",
+				In:     "query",
+				Schema: openapi.Schema{"type": "string", "enum": []string{core.RecordExportFormatCSV, core.RecordExportFormatNDJSON, core.RecordExportFormatXLSX}},
+			},
+			{Name: "filter", In: "query", Schema: openapi.Schema{"type": "string"}},
+			{Name: "sort", In: "query", Schema: openapi.Schema{"type": "string"}},
+		}, openAPIViewParameters()...),
+		Responses: map[string]*openapi.Response{
+			"200": {Description: "The exported records file."},
+			"400": g.errorResponse("Invalid export parameters."),
+		},
+	})
+
+	if !c.IsView() {
+		if c.CreateRule != nil || g.superuser {
+			g.doc.Components.Schemas[prefix+"CreateRequest"] = openAPIRecordRequestSchema(c, true, false)
+		}
+		if c.UpdateRule != nil || g.superuser {
+			g.doc.Components.Schemas[prefix+"UpdateRequest"] = openAPIRecordRequestSchema(c, false, false)
+		}
+
+		createContent := openapi.JSONContent(g.ref(prefix + "CreateRequest"))
+		updateContent := openapi.JSONContent(g.ref(prefix + "UpdateRequest"))
+		if openAPIHasFileFields(c) {
+			createContent["multipart/form-data"] = &openapi.MediaType{Schema: openAPIRecordRequestSchema(c, true, true)}
+			updateContent["multipart/form-data"] = &openapi.MediaType{Schema: openAPIRecordRequestSchema(c, false, true)}
+		}
+
+		addOperation(http.MethodPost, basePath+"/records", c.CreateRule, &openapi.Operation{
+			OperationId: c.Name + "_create",
+			Summary:     "Create a new " + c.Name + " record",
+			Parameters:  openAPIViewParameters(),
+			RequestBody: &openapi.RequestBody{Required: true, Content: createContent},
+			Responses: map[string]*openapi.Response{
+				"200": {Description: "The created record.", Content: openapi.JSONContent(recordRef)},
+				"400": g.errorResponse("Failed to create the record."),
+			},
+		})
+
+		addOperation(http.MethodPatch, basePath+"/records/{id}", c.UpdateRule, &openapi.Operation{
+			OperationId: c.Name + "_update",
+			Summary:     "Update a single " + c.Name + " record",
+			Parameters:  append([]*openapi.Parameter{openAPIPathParameter("id")}, openAPIViewParameters()...),
+			RequestBody: &openapi.RequestBody{Required: true, Content: updateContent},
+			Responses: map[string]*openapi.Response{
+				"200": {Description: "The updated record.", Content: openapi.JSONContent(recordRef)},
+				"400": g.errorResponse("Failed to update the record."),
+				"404": g.errorResponse("Missing or inaccessible record."),
+			},
+		})
+
+		addOperation(http.MethodDelete, basePath+"/records/{id}", c.DeleteRule, &openapi.Operation{
+			OperationId: c.Name + "_delete",
+			Summary:     "Delete a single " + c.Name + " record",
+			Parameters:  []*openapi.Parameter{openAPIPathParameter("id")},
+			Responses: map[string]*openapi.Response{
+				"204": {Description: "The record was deleted."},
+				"400": g.errorResponse("Failed to delete the record."),
+				"404": g.errorResponse("Missing or inaccessible record."),
+			},
+		})
+	}
+
+	if c.IsAuth() {
+		hasOperations = true
+		g.addAuthCollection(c, recordRef)
+	}
+
+	if !hasOperations {
+		return
+	}
+
+	g.doc.Components.Schemas[prefix+"Record"] = openAPIRecordSchema(c, g.superuser)
+	g.doc.Tags = append(g.doc.Tags, openapi.Tag{
+		Name:        c.Name,
+		Description: "The " + c.Name + " collection records (" + c.Type + ").",
+	})
+}
+
+func (g *openAPIGenerator) addAuthCollection(c *core.Collection, recordRef openapi.Schema) {
+	basePath := "/api/collections/" + c.Name
+	public := new(string)
+
+	authResponse := map[string]*openapi.Response{
+		"200": {
+			Description: "The authenticated record and its auth token.",
+			Content: openapi.JSONContent(openapi.Schema{
+				"type":     "object",
+				"required": []string{"token", "record"},
+				"properties": map[string]any{
+					"token":  openapi.Schema{"type": "string"},
+					"record": recordRef,
+					"meta":   openapi.Schema{"type": "object"},
+				},
+			}),
+		},
+		"400": g.errorResponse("Failed to authenticate."),
+	}
+
+	noContentResponse := func(description string) map[string]*openapi.Response {
+		return map[string]*openapi.Response{
+			"204": {Description: description},
+			"400": g.errorResponse("Invalid request data."),
+		}
+	}
+
+	addOperation := func(method string, path string, rule *string, op *openapi.Operation) {
+		op.Tags = []string{c.Name}
+		g.addOperation(method, basePath+path, rule, op)
+	}
+
+	addOperation(http.MethodGet, "/auth-methods", public, &openapi.Operation{
+		OperationId: c.Name + "_authMethods",
+		Summary:     "List the " + c.Name + " auth methods",
+		Responses: map[string]*openapi.Response{
+			"200": {Description: "The enabled auth methods.", Content: openapi.JSONContent(openapi.Schema{"type": "object"})},
+		},
+	})
+
+	addOperation(http.MethodPost, "/auth-refresh", public, &openapi.Operation{
+		OperationId: c.Name + "_authRefresh",
+		Summary:     "Refresh the " + c.Name + " auth token",
+		Security:    []map[string][]string{{openAPISecurityScheme: {}}},
+		Parameters:  openAPIViewParameters(),
+		Responses:   authResponse,
+	})
+
+	if c.PasswordAuth.Enabled {
+		addOperation(http.MethodPost, "/auth-with-password", public, &openapi.Operation{
+			OperationId: c.Name + "_authWithPassword",
+			Summary:     "Authenticate a " + c.Name + " record with password",
+			Parameters:  openAPIViewParameters(),
+			RequestBody: openAPIFormBody(map[string]string{
+				"identity":      "string",
+				"password":      "string",
+				"identityField": "string",
+			}, "identity", "password"),
+			Responses: authResponse,
+		})
+	}
+
+	if c.OTP.Enabled {
+		addOperation(http.MethodPost, "/request-otp", public, &openapi.Operation{
+			OperationId: c.Name + "_requestOTP",
+			Summary:     "Request a " + c.Name + " one-time password",
+			RequestBody: openAPIFormBody(map[string]string{"email": "string"}, "email"),
+			Responses: map[string]*openapi.Response{
+				"200": {
+					Description: "The created OTP id.",
+					Content: openapi.JSONContent(openapi.Schema{
+						"type":       "object",
+						"properties": map[string]any{"otpId": openapi.Schema{"type": "string"}},
+					}),
+				},
+				"400": g.errorResponse("Invalid request data."),
+			},
+		})
+
+		addOperation(http.MethodPost, "/auth-with-otp", public, &openapi.Operation{
+			OperationId: c.Name + "_authWithOTP",
+			Summary:     "Authenticate a " + c.Name + " record with one-time password",
+			Parameters:  openAPIViewParameters(),
+			RequestBody: openAPIFormBody(map[string]string{"otpId": "string", "password": "string"}, "otpId", "password"),
+			Responses:   authResponse,
+		})
+	}
+
+	if c.OAuth2.Enabled {
+		addOperation(http.MethodPost, "/auth-with-oauth2", public, &openapi.Operation{
+			OperationId: c.Name + "_authWithOAuth2",
+			Summary:     "Authenticate a " + c.Name + " record with OAuth2",
+			Parameters:  openAPIViewParameters(),
+			RequestBody: openAPIFormBody(map[string]string{
+				"provider":     "string",
+				"code":         "string",
+				"codeVerifier": "string",
+				"redirectURL":  "string",
+				"createData":   "object",
+			}, "provider", "code", "redirectURL"),
+			Responses: authResponse,
+		})
+	}
+
+	addOperation(http.MethodPost, "/request-password-reset", public, &openapi.Operation{
+		OperationId: c.Name + "_requestPasswordReset",
+		Summary:     "Send a " + c.Name + " password reset email",
+		RequestBody: openAPIFormBody(map[string]string{"email": "string"}, "email"),
+		Responses:   noContentResponse("The password reset email was sent (if the record exists)."),
+	})
+
+	addOperation(http.MethodPost, "/confirm-password-reset", public, &openapi.Operation{
+		OperationId: c.Name + "_confirmPasswordReset",
+		Summary:     "Confirm a " + c.Name + " password reset",
+		RequestBody: openAPIFormBody(map[string]string{
+			"token":           "string",
+			"password":        "string",
+			"passwordConfirm": "string",
+		}, "token", "password", "passwordConfirm"),
+		Responses: noContentResponse("The password was changed."),
+	})
+
+	addOperation(http.MethodPost, "/request-verification", public, &openapi.Operation{
+		OperationId: c.Name + "_requestVerification",
+		Summary:     "Send a " + c.Name + " verification email",
+		RequestBody: openAPIFormBody(map[string]string{"email": "string"}, "email"),
+		Responses:   noContentResponse("The verification email was sent (if the record exists)."),
+	})
+
+	addOperation(http.MethodPost, "/confirm-verification", public, &openapi.Operation{
+		OperationId: c.Name + "_confirmVerification",
+		Summary:     "Confirm a " + c.Name + " email verification",
+		RequestBody: openAPIFormBody(map[string]string{"token": "string"}, "token"),
+		Responses:   noContentResponse("The record was verified."),
+	})
+
+	addOperation(http.MethodPost, "/request-email-change", public, &openapi.Operation{
+		OperationId: c.Name + "_requestEmailChange",
+		Summary:     "Send a " + c.Name + " email change request",
+		Security:    []map[string][]string{{openAPISecurityScheme: {}}},
+		RequestBody: openAPIFormBody(map[string]string{"newEmail": "string"}, "newEmail"),
+		Responses:   noContentResponse("The email change request was sent."),
+	})
+
+	addOperation(http.MethodPost, "/confirm-email-change", public, &openapi.Operation{
+		OperationId: c.Name + "_confirmEmailChange",
+		Summary:     "Confirm a " + c.Name + " email change",
+		RequestBody: openAPIFormBody(map[string]string{"token": "string", "password": "string"}, "token", "password"),
+		Responses:   noContentResponse("The email was changed."),
+	})
+
+	addOperation(http.MethodPost, "/impersonate/{id}", nil, &openapi.Operation{
+		OperationId: c.Name + "_impersonate",
+		Summary:     "Impersonate a single " + c.Name + " record",
+		Parameters:  append([]*openapi.Parameter{openAPIPathParameter("id")}, openAPIViewParameters()...),
+		RequestBody: openAPIFormBody(map[string]string{"duration": "integer"}),
+		Responses:   authResponse,
+	})
+}
+
+// system endpoints
+// -------------------------------------------------------------------
+
+func (g *openAPIGenerator) addSystem() {
+	public := new(string)
+	object := openapi.JSONContent(openapi.Schema{"type": "object"})
+
+	addOperation := func(method string, path string, rule *string, op *openapi.Operation) {
+		op.Tags = []string{openAPISystemTag}
+		g.addOperation(method, path, rule, op)
+	}
+
+	addOperation(http.MethodGet, "/api/health", public, &openapi.Operation{
+		OperationId: "health",
+		Summary:     "Check the API health status",
+		Responses: map[string]*openapi.Response{
+			"200": {Description: "The API health status.", Content: object},
+		},
+	})
+
+	addOperation(http.MethodGet, "/api/realtime", public, &openapi.Operation{
+		OperationId: "realtimeConnect",
+		Summary:     "Establish a new realtime SSE connection",
+		Responses: map[string]*openapi.Response{
+			"200": {
+				Description: "The realtime events stream.",
+				Content: map[string]*openapi.MediaType{
+					"text/event-stream": {Schema: openapi.Schema{"type": "string"}},
+				},
+			},
+		},
+	})
+
+	addOperation(http.MethodPost, "/api/realtime", public, &openapi.Operation{
+		OperationId: "realtimeSetSubscriptions",
+		Summary:     "Set the realtime client subscriptions",
+		RequestBody: &openapi.RequestBody{
+			Required: true,
+			Content: openapi.JSONContent(openapi.Schema{
+				"type":     "object",
+				"required": []string{"clientId"},
+				"properties": map[string]any{
+					"clientId":      openapi.Schema{"type": "string"},
+					"subscriptions": openapi.Schema{"type": "array", "items": openapi.Schema{"type": "string"}},
+				},
+			}),
+		},
+		Responses: map[string]*openapi.Response{
+			"204": {Description: "The subscriptions were updated."},
+			"400": g.errorResponse("Invalid request data."),
+			"404": g.errorResponse("Missing or invalid client id."),
+		},
+	})
+
+	addOperation(http.MethodPost, "/api/files/token", public, &openapi.Operation{
+		OperationId: "fileToken",
+		Summary:     "Generate a short-lived protected files access token",
+		Security:    []map[string][]string{{openAPISecurityScheme: {}}},
+		Responses: map[string]*openapi.Response{
+			"200": {
+				Description: "The generated file token.",
+				Content: openapi.JSONContent(openapi.Schema{
+					"type":       "object",
+					"properties": map[string]any{"token": openapi.Schema{"type": "string"}},
+				}),
+			},
+			"400": g.errorResponse("Failed to generate the file token."),
+		},
+	})
+
+	addOperation(http.MethodGet, "/api/files/{collection}/{recordId}/{filename}", public, &openapi.Operation{
+		OperationId: "fileDownload",
+		Summary:     "Download a single record file",
+		Parameters: []*openapi.Parameter{
+			openAPIPathParameter("collection"),
+			openAPIPathParameter("recordId"),
+			openAPIPathParameter("filename"),
+			{Name: "thumb", In: "query", Schema: openapi.Schema{"type": "string"}},
+			{Name: "token", In: "query", Schema: openapi.Schema{"type": "string"}},
+			{Name: "download", In: "query", Schema: openapi.Schema{"type": "boolean"}},
+		},
+		Responses: map[string]*openapi.Response{
+			"200": {Description: "The file content."},
+			"404": g.errorResponse("Missing or inaccessible file."),
+		},
+	})
+
+	addOperation(http.MethodPost, "/api/batch", public, &openapi.Operation{
+		OperationId: "batch",
+		Summary:     "Execute multiple records create, update, upsert and delete requests in a single transaction",
+		RequestBody: &openapi.RequestBody{
+			Required: true,
+			Content: openapi.JSONContent(openapi.Schema{
+				"type":     "object",
+				"required": []string{"requests"},
+				"properties": map[string]any{
+					"requests": openapi.Schema{
+						"type": "array",
+						"items": openapi.Schema{
+							"type":     "object",
+							"required": []string{"method", "url"},
+							"properties": map[string]any{
+								"method":  openapi.Schema{"type": "string"},
+								"url":     openapi.Schema{"type": "string"},
+								"headers": openapi.Schema{"type": "object"},
+								"body":    openapi.Schema{"type": "object"},
+							},
+						},
+					},
+				},
+			}),
+		},
+		Responses: map[string]*openapi.Response{
+			"200": {Description: "The individual requests responses.", Content: openapi.JSONContent(openapi.Schema{"type": "array", "items": openapi.Schema{"type": "object"}})},
+			"400": g.errorResponse("Failed to execute the batch transaction."),
+		},
+	})
+
+	addOperation(http.MethodGet, "/api/changes", public, &openapi.Operation{
+		OperationId: "changesList",
+		Summary:     "List the records change log entries",
+		Parameters: []*openapi.Parameter{
+			{Name: "since", In: "query", Schema: openapi.Schema{"type": "integer"}},
+			{Name: "collections", In: "query", Schema: openapi.Schema{"type": "string"}},
+			{Name: "limit", In: "query", Schema: openapi.Schema{"type": "integer"}},
+			{Name: "wait", In: "query", Schema: openapi.Schema{"type": "integer"}},
+			{Name: "consumer", In: "query", Schema: openapi.Schema{"type": "string"}},
+		},
+		Responses: map[string]*openapi.Response{
+			"200": {Description: "The change log entries.", Content: object},
+			"400": g.errorResponse("Invalid request parameters."),
+			"410": g.errorResponse("The requested changes were already pruned."),
+		},
+	})
+
+	// superuser only
+	superuserOperations := []struct {
+		method     string
+		path       string
+		id         string
+		summary    string
+		pathParams []string
+	}{
+		{http.MethodGet, "/api/collections", "collectionsList", "List all collections", nil},
+		{http.MethodGet, "/api/collections/{collection}", "collectionView", "View a single collection", []string{"collection"}},
+		{http.MethodGet, "/api/settings", "settingsList", "List the app settings", nil},
+		{http.MethodPatch, "/api/settings", "settingsUpdate", "Update the app settings", nil},
+		{http.MethodGet, "/api/logs", "logsList", "List the request logs", nil},
+		{http.MethodGet, "/api/backups", "backupsList", "List the app backups", nil},
+		{http.MethodGet, "/api/crons", "cronsList", "List the registered app cron jobs", nil},
+	}
+	for _, item := range superuserOperations {
+		op := &openapi.Operation{
+			OperationId: item.id,
+			Summary:     item.summary,
+			Responses: map[string]*openapi.Response{
+				"200": {Description: "Successful response.", Content: object},
+				"401": g.errorResponse("Missing superuser authorization."),
+			},
+		}
+
+		if item.method == http.MethodPatch {
+			op.RequestBody = &openapi.RequestBody{Required: true, Content: object}
+		}
+
+		for _, name := range item.pathParams {
+			op.Parameters = append(op.Parameters, openAPIPathParameter(name))
+		}
+
+		addOperation(item.method, item.path, nil, op)
+	}
+
+	g.doc.Tags = append(g.doc.Tags, openapi.Tag{Name: openAPISystemTag, Description: "The system endpoints."})
+}
+
+// schemas and parameters helpers
+// -------------------------------------------------------------------
+
+func openAPIPathParameter(name string) *openapi.Parameter {
+	return &openapi.Parameter{
+		Name:     name,
+		In:       "path",
+		Required: true,
+		Schema:   openapi.Schema{"type": "string"},
+	}
+}
+
+func openAPIViewParameters() []*openapi.Parameter {
+	return []*openapi.Parameter{
+		{Name: "expand", In: "query", Description: "Comma separated relations to expand.", Schema: openapi.Schema{"type": "string"}},
+		{Name: "fields", In: "query", Description: "Comma separated fields to return.", Schema: openapi.Schema{"type": "string"}},
+	}
+}
+
+func openAPIListParameters() []*openapi.Parameter {
+	return append([]*openapi.Parameter{
+		{Name: "page", In: "query", Schema: openapi.Schema{"type": "integer", "minimum": 1}},
+		{Name: "perPage", In: "query", Schema: openapi.Schema{"type": "integer", "minimum": 1, "maximum": 1000}},
+		{Name: "sort", In: "query", Schema: openapi.Schema{"type": "string"}},
+		{Name: "filter", In: "query", Schema: openapi.Schema{"type": "string"}},
+		{Name: "skipTotal", In: "query", Schema: openapi.Schema{"type": "boolean"}},
+		{Name: "cursor", In: "query", Schema: openapi.Schema{"type": "string"}},
+	}, openAPIViewParameters()...)
+}
+
+func openAPIListResultSchema(itemSchema openapi.Schema) openapi.Schema {
+	return openapi.Schema{
+		"type":     "object",
+		"required": []string{"page", "perPage", "totalItems", "totalPages", "items"},
+		"properties": map[string]any{
+			"page":       openapi.Schema{"type": "integer"},
+			"perPage":    openapi.Schema{"type": "integer"},
+			"totalItems": openapi.Schema{"type": "integer"},
+			"totalPages": openapi.Schema{"type": "integer"},
+			"items":      openapi.Schema{"type": "array", "items": itemSchema},
+			"nextCursor": openapi.Schema{"type": "string"},
+			"prevCursor": openapi.Schema{"type": "string"},
+		},
+	}
+}
+
+// openAPIFormBody returns a JSON object request body with the specified simple typed properties.
+func openAPIFormBody(props map[string]string, required ...string) *openapi.RequestBody {
+	properties := make(map[string]any, len(props))
+	for name, typ := range props {
+		properties[name] = openapi.Schema{"type": typ}
+	}
+
+	schema := openapi.Schema{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	return &openapi.RequestBody{
+		Required: len(required) > 0,
+		Content:  openapi.JSONContent(schema),
+	}
+}
+
+func openAPIHasFileFields(c *core.Collection) bool {
+	for _, f := range c.Fields {
+		if f.Type() == core.FieldTypeFile {
+			return true
+		}
+	}
+
+	return false
+}
+
+// openAPIRecordSchema returns the JSON Schema of a single collection record response.
+func openAPIRecordSchema(c *core.Collection, includeHidden bool) openapi.Schema {
+	properties := map[string]any{}
+	required := []string{}
+
+	viewRules := c.FieldViewRules()
+
+	for _, f := range c.Fields {
+		name := f.GetName()
+
+		if c.IsAuth() && (name == core.FieldNamePassword || name == core.FieldNameTokenKey) {
+			continue // never exported
+		}
+
+		if f.Type() == core.FieldTypePassword || (f.GetHidden() && !includeHidden) {
+			continue
+		}
+
+		schema, _ := openAPIFieldSchema(f, false)
+		if f.GetHidden() {
+			schema["description"] = "Hidden field (visible only for superusers)."
+		}
+		properties[name] = schema
+
+		// the email of auth records and the fields with view rule may not be returned
+		if _, ok := viewRules[name]; ok || (c.IsAuth() && name == core.FieldNameEmail) {
+			continue
+		}
+
+		required = append(required, name)
+	}
+
+	properties[core.FieldNameCollectionId] = openapi.Schema{"type": "string", "const": c.Id}
+	properties[core.FieldNameCollectionName] = openapi.Schema{"type": "string", "const": c.Name}
+	properties[core.FieldNameExpand] = openapi.Schema{"type": "object", "description": "The expanded relations (if requested)."}
+	required = append(required, core.FieldNameCollectionId, core.FieldNameCollectionName)
+
+	return openapi.Schema{
+		"type":       "object",
+		"required":   required,
+		"properties": properties,
+	}
+}
+
+// openAPIRecordRequestSchema returns the JSON Schema of a collection record create or update request body.
+func openAPIRecordRequestSchema(c *core.Collection, isCreate bool, isMultipart bool) openapi.Schema {
+	properties := map[string]any{}
+	required := []string{}
+
+	for _, f := range c.Fields {
+		name := f.GetName()
+
+		if c.IsAuth() && name == core.FieldNameTokenKey {
+			continue
+		}
+
+		if f.Type() == core.FieldTypeAutodate {
+			continue // auto managed
+		}
+
+		if g, ok := f.(core.GeneratedColumner); ok && g.IsGeneratedColumn() {
+			continue // read-only
+		}
+
+		schema, isRequired := openAPIFieldSchema(f, isMultipart)
+		properties[name] = schema
+
+		if isCreate && isRequired {
+			required = append(required, name)
+		}
+
+		if f.Type() == core.FieldTypePassword {
+			properties[name+"Confirm"] = openapi.Schema{"type": "string", "writeOnly": true}
+			if isCreate && isRequired {
+				required = append(required, name+"Confirm")
+			}
+			if !isCreate && c.IsAuth() && name == core.FieldNamePassword {
+				properties["oldPassword"] = openapi.Schema{"type": "string", "writeOnly": true}
+			}
+		}
+	}
+
+	schema := openapi.Schema{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	return schema
+}
+
+// openAPIFieldSchema maps a single collection field to JSON Schema.
+//
+// isUpload indicates whether the file fields schema should describe
+// multipart file uploads instead of the stored file names.
+//
+// Returns also whether the field is required on record create.
+func openAPIFieldSchema(f core.Field, isUpload bool) (openapi.Schema, bool) {
+	var schema openapi.Schema
+	var required bool
+
+	multiple := func(item openapi.Schema, isMultiple bool, maxSelect int) openapi.Schema {
+		if !isMultiple {
+			return item
+		}
+		s := openapi.Schema{"type": "array", "items": item}
+		if maxSelect > 0 {
+			s["maxItems"] = maxSelect
+		}
+		return s
+	}
+
+	switch v := f.(type) {
+	case *core.TextField:
+		schema = openapi.Schema{"type": "string"}
+		if v.Min > 0 {
+			schema["minLength"] = v.Min
+		}
+		if v.Max > 0 {
+			schema["maxLength"] = v.Max
+		}
+		if v.Pattern != "" {
+			schema["pattern"] = v.Pattern
+		}
+		required = v.Required && v.AutogeneratePattern == ""
+	case *core.EditorField:
+		schema = openapi.Schema{"type": "string", "contentMediaType": "text/html"}
+		required = v.Required
+	case *core.EmailField:
+		schema = openapi.Schema{"type": "string", "format": "email"}
+		required = v.Required
+	case *core.URLField:
+		schema = openapi.Schema{"type": "string", "format": "uri"}
+		required = v.Required
+	case *core.PasswordField:
+		schema = openapi.Schema{"type": "string", "writeOnly": true}
+		if v.Min > 0 {
+			schema["minLength"] = v.Min
+		}
+		if v.Max > 0 {
+			schema["maxLength"] = v.Max
+		}
+		required = v.Required
+	case *core.NumberField:
+		schema = openapi.Schema{"type": "number"}
+		if v.OnlyInt {
+			schema["type"] = "integer"
+		}
+		if v.Min != nil {
+			schema["minimum"] = *v.Min
+		}
+		if v.Max != nil {
+			schema["maximum"] = *v.Max
+		}
+		required = v.Required
+	case *core.DecimalField:
+		if v.JSONNumber {
+			schema = openapi.Schema{"type": "number"}
+		} else {
+			schema = openapi.Schema{"type": "string", "description": "Decimal number."}
+		}
+		required = v.Required
+	case *core.BoolField:
+		schema = openapi.Schema{"type": "boolean"}
+		required = v.Required
+	case *core.DateField:
+		schema = openapi.Schema{"type": "string", "description": "Date in the format 2006-01-02 15:04:05.000Z (or empty)."}
+		required = v.Required
+	case *core.AutodateField:
+		schema = openapi.Schema{"type": "string", "readOnly": true, "description": "Date in the format 2006-01-02 15:04:05.000Z."}
+	case *core.SelectField:
+		values := slices.Clone(v.Values)
+		if !v.Required && !v.IsMultiple() {
+			values = append(values, "")
+		}
+		schema = multiple(openapi.Schema{"type": "string", "enum": values}, v.IsMultiple(), v.MaxSelect)
+		required = v.Required
+	case *core.RelationField:
+		schema = multiple(openapi.Schema{"type": "string", "description": "Related record id."}, v.IsMultiple(), v.MaxSelect)
+		required = v.Required
+	case *core.FileField:
+		item := openapi.Schema{"type": "string", "description": "File name."}
+		if isUpload {
+			item = openapi.Schema{"type": "string", "format": "binary"}
+		}
+		schema = multiple(item, v.IsMultiple(), v.MaxSelect)
+		required = v.Required
+	case *core.JSONField:
+		schema = openapi.Schema{"description": "Any JSON value."}
+		required = v.Required
+	case *core.GeoPointField:
+		schema = openapi.Schema{
+			"type":     "object",
+			"required": []string{"lon", "lat"},
+			"properties": map[string]any{
+				"lon": openapi.Schema{"type": "number", "minimum": -180, "maximum": 180},
+				"lat": openapi.Schema{"type": "number", "minimum": -90, "maximum": 90},
+			},
+		}
+		required = v.Required
+	case *core.VectorField:
+		schema = openapi.Schema{"type": "array", "items": openapi.Schema{"type": "number"}}
+		if v.Dimensions > 0 {
+			schema["minItems"] = v.Dimensions
+			schema["maxItems"] = v.Dimensions
+		}
+		required = v.Required
+	case *core.ComputedField:
+		switch v.ValueType {
+		case core.ComputedValueTypeNumber:
+			schema = openapi.Schema{"type": "number"}
+		case core.ComputedValueTypeBool:
+			schema = openapi.Schema{"type": "boolean"}
+		default:
+			schema = openapi.Schema{"type": "string"}
+		}
+		schema["readOnly"] = true
+	default:
+		// unknown/custom field type
+		schema = openapi.Schema{}
+	}
+
+	return schema, required
+}
@@ -0,0 +1,189 @@
+package apis_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func setupOpenAPITest(t testing.TB, app core.App) {
+	collection := core.NewBaseCollection("test_openapi")
+	collection.ListRule = types.Pointer("")
+	collection.ViewRule = types.Pointer("@request.auth.id != ''")
+	collection.CreateRule = types.Pointer("")
+	collection.Fields.Add(
+		&core.TextField{Name: "title", Required: true, Max: 100},
+		&core.TextField{Name: "secret", Hidden: true},
+		&core.NumberField{Name: "total", OnlyInt: true},
+		&core.SelectField{Name: "tags", Values: []string{"a", "b"}, MaxSelect: 2},
+		&core.FileField{Name: "files", MaxSelect: 1},
+		&core.AutodateField{Name: "created", OnCreate: true},
+	)
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOpenAPIDocumentApi(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:   "guest",
+			Method: http.MethodGet,
+			URL:    "/api/openapi.json",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupOpenAPITest(t, app)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"openapi":"3.1.0"`,
+				`"/api/collections/test_openapi/records":{"get":`,
+				`"/api/collections/test_openapi/records/{id}":{"get":`,
+				`"/api/collections/test_openapi/export":{"get":`,
+				`"/api/collections/users/auth-with-password":{"post":`,
+				`"/api/health":{"get":`,
+				`"TestOpenapiRecord":{`,
+				`"TestOpenapiCreateRequest":{`,
+				`"multipart/form-data"`,
+				`"format":"binary"`,
+				`"maxLength":100`,
+				`"enum":["a","b"]`,
+			},
+			NotExpectedContent: []string{
+				// superuser only
+				`"/api/collections/demo1/records"`,
+				`"/api/collections/users/records":{"get":`,
+				`"/api/collections/users/impersonate/{id}"`,
+				`"/api/settings"`,
+				`"TestOpenapiUpdateRequest"`,
+				`"Demo1Record"`,
+				// hidden field
+				`Hidden field`,
+			},
+		},
+		{
+			Name:   "superuser",
+			Method: http.MethodGet,
+			URL:    "/api/openapi.json",
+			Headers: map[string]string{
+				"Authorization": revisionsTestSuperuserToken,
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupOpenAPITest(t, app)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"/api/collections/demo1/records":{"get":`,
+				`"/api/collections/users/impersonate/{id}":{"post":`,
+				`"/api/settings":{"get":`,
+				`"TestOpenapiUpdateRequest":{`,
+				`"Demo1Record":{`,
+				`Hidden field`,
+				`Only superusers can perform this action.`,
+			},
+		},
+		{
+			Name:   "regenerate after collection changes",
+			Method: http.MethodGet,
+			URL:    "/api/openapi.json",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				mux, err := e.Router.BuildMux()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				// warm up the cache
+				rec := httptest.NewRecorder()
+				mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+				if strings.Contains(rec.Body.String(), "test_openapi") {
+					t.Fatal("Expected the test collection to be missing")
+				}
+
+				setupOpenAPITest(t, app)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"/api/collections/test_openapi/records":{"get":`,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestOpenAPIDocumentRecordSchemas(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	setupOpenAPITest(t, app)
+
+	doc, err := apis.OpenAPIDocument(app, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record := doc.Components.Schemas["TestOpenapiRecord"]
+	create := doc.Components.Schemas["TestOpenapiCreateRequest"]
+
+	raw, err := json.Marshal(map[string]any{"record": record, "create": create})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var schemas struct {
+		Record struct {
+			Required   []string                  `json:"required"`
+			Properties map[string]map[string]any `json:"properties"`
+		} `json:"record"`
+		Create struct {
+			Required   []string                  `json:"required"`
+			Properties map[string]map[string]any `json:"properties"`
+		} `json:"create"`
+	}
+	if err := json.Unmarshal(raw, &schemas); err != nil {
+		t.Fatal(err)
+	}
+
+	// record response
+	if _, ok := schemas.Record.Properties["secret"]; ok {
+		t.Fatal("Expected the hidden field to be excluded from the record schema")
+	}
+	if v := schemas.Record.Properties["total"]["type"]; v != "integer" {
+		t.Fatalf("Expected total integer type, got %v", v)
+	}
+	if v := schemas.Record.Properties["tags"]["type"]; v != "array" {
+		t.Fatalf("Expected tags array type, got %v", v)
+	}
+	if v := schemas.Record.Properties["collectionName"]["const"]; v != "test_openapi" {
+		t.Fatalf("Expected collectionName const, got %v", v)
+	}
+	for _, name := range []string{"id", "title", "total", "created", "collectionId"} {
+		if !slices.Contains(schemas.Record.Required, name) {
+			t.Fatalf("Expected %q to be required in %v", name, schemas.Record.Required)
+		}
+	}
+
+	// create request
+	if _, ok := schemas.Create.Properties["created"]; ok {
+		t.Fatal("Expected the autodate field to be excluded from the create schema")
+	}
+	if _, ok := schemas.Create.Properties["secret"]; !ok {
+		t.Fatal("Expected the hidden field to be settable in the create schema")
+	}
+	if strings.Join(schemas.Create.Required, ",") != "title" {
+		t.Fatalf("Expected only title to be required, got %v", schemas.Create.Required)
+	}
+}
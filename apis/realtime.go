@@ -566,6 +566,16 @@ func realtimeBroadcastRecord(app core.App, action string, record *core.Record, d
 								}
 							}
 
+							if err := applyFieldViewRules(accessCheckApp, []*core.Record{cleanRecord}, requestInfo); err != nil {
+								app.Logger().Debug(
+									"[broadcastRecord] field view rules error",
+									slog.String("id", cleanRecord.Id),
+									slog.String("collectionName", cleanRecord.Collection().Name),
+									slog.String("sub", sub),
+									slog.String("error", err.Error()),
+								)
+							}
+
 							// ignore the auth record email visibility checks
 							// for auth owner, superuser or manager
 							if collection.IsAuth() {
@@ -744,6 +754,12 @@ func realtimeCanAccessRecord(
 		return false
 	}
 
+	// deliver the tenant scoped records only within the same tenant
+	// ---
+	if !canAccessTenantRecord(record, requestInfo) {
+		return false
+	}
+
 	// check the subscription client-side filter (if any)
 	// ---
 	filter := requestInfo.Query[search.FilterQueryParam]
@@ -0,0 +1,156 @@
+package apis
+
+import (
+	"encoding/json"
+	"log/slog"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/routine"
+	"github.com/pocketbase/pocketbase/tools/search"
+	"github.com/pocketbase/pocketbase/tools/subscriptions"
+	"golang.org/x/sync/errgroup"
+)
+
+// realtimeMessageCollectionName is the name of the dummy collection
+// used for evaluating the custom realtime message rules.
+const realtimeMessageCollectionName = "_pbRealtimeMessage"
+
+// RealtimeCheckMessageRule reports whether the custom topic message
+// rule is satisfied for the provided request info.
+//
+// In addition to the @request.* and @collection.* fields, the rule
+// could also reference the message "topic" and "data" as regular
+// fields, eg. `topic = "chat" && data.text != ""`.
+//
+// Similar to the collection API rules, nil rule means that only
+// superusers are allowed and empty rule means that everyone is allowed.
+func RealtimeCheckMessageRule(app core.App, requestInfo *core.RequestInfo, topic string, data any, rule *string) bool {
+	if requestInfo != nil && requestInfo.HasSuperuserAuth() {
+		return true
+	}
+
+	if rule == nil {
+		return false
+	}
+
+	return realtimeMatchMessageRule(app, requestInfo, topic, data, *rule)
+}
+
+// RealtimeBroadcast sends a custom topic message to the realtime clients subscribed to the topic.
+//
+// The message data is JSON serialized.
+//
+// If filter is not empty, the message is sent only to the subscribers that satisfy the filter rule
+// (it is evaluated with the client auth state and subscription options as @request.* fields,
+// and the message "topic" and "data" as regular fields).
+func RealtimeBroadcast(app core.App, topic string, data any, filter string) error {
+	dataBytes, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	chunks := app.SubscriptionsBroker().ChunkedClients(clientsChunkSize)
+	if len(chunks) == 0 {
+		return nil // no subscribers
+	}
+
+	// "?" ensures that only the exact topic subscriptions are matched (with or without options)
+	prefix := topic + "?"
+
+	group := new(errgroup.Group)
+
+	for _, chunk := range chunks {
+		group.Go(func() error {
+			for _, client := range chunk {
+				subs := client.Subscriptions(prefix)
+				if len(subs) == 0 {
+					continue
+				}
+
+				clientAuth, _ := client.Get(RealtimeClientAuthKey).(*core.Record)
+
+				for sub, options := range subs {
+					if filter != "" {
+						// mock request data
+						requestInfo := &core.RequestInfo{
+							Context: core.RequestInfoContextRealtime,
+							Method:  "GET",
+							Query:   options.Query,
+							Headers: options.Headers,
+							Auth:    clientAuth,
+						}
+
+						if !realtimeMatchMessageRule(app, requestInfo, topic, data, filter) {
+							continue
+						}
+					}
+
+					msg := subscriptions.Message{
+						Name: sub,
+						Data: dataBytes,
+					}
+
+					client := client
+
+					routine.FireAndForget(func() {
+						client.Send(msg)
+					})
+				}
+			}
+
+			return nil
+		})
+	}
+
+	return group.Wait()
+}
+
+// realtimeMatchMessageRule evaluates the rule expression against
+// a dummy single row collection with the message topic and data.
+func realtimeMatchMessageRule(app core.App, requestInfo *core.RequestInfo, topic string, data any, rule string) bool {
+	if rule == "" {
+		return true
+	}
+
+	rawData, err := json.Marshal(data)
+	if err != nil {
+		return false
+	}
+
+	collection := core.NewBaseCollection(realtimeMessageCollectionName)
+	collection.Fields.Add(
+		&core.TextField{Name: "topic"},
+		&core.JSONField{Name: "data"},
+	)
+
+	query := app.ConcurrentDB().Select("(1)").
+		PreFragment("WITH {{" + realtimeMessageCollectionName + "}} AS (SELECT '' AS [[id]], {:topic} AS [[topic]], {:data} AS [[data]])").
+		From(realtimeMessageCollectionName).
+		AndBind(dbx.Params{
+			"topic": topic,
+			"data":  string(rawData),
+		})
+
+	resolver := core.NewRecordFieldResolver(app, collection, requestInfo, true)
+
+	expr, err := search.FilterData(rule).BuildExpr(resolver)
+	if err != nil {
+		app.Logger().Debug(
+			"Failed to build the realtime message rule expression",
+			slog.String("topic", topic),
+			slog.String("rule", rule),
+			slog.String("error", err.Error()),
+		)
+		return false
+	}
+	query.AndWhere(expr)
+
+	resolver.UpdateQuery(query)
+
+	var exists int
+
+	err = query.Limit(1).Row(&exists)
+
+	return err == nil && exists > 0
+}
@@ -0,0 +1,164 @@
+package apis_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/subscriptions"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestRealtimeCheckMessageRule(t *testing.T) {
+	t.Parallel()
+
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	user, err := testApp.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	superuser, err := testApp.FindAuthRecordByEmail(core.CollectionNameSuperusers, "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := map[string]any{"text": "hello", "room": "general"}
+
+	scenarios := []struct {
+		name     string
+		auth     *core.Record
+		rule     *string
+		expected bool
+	}{
+		{"nil rule as guest", nil, nil, false},
+		{"nil rule as user", user, nil, false},
+		{"nil rule as superuser", superuser, nil, true},
+		{"empty rule as guest", nil, types.Pointer(""), true},
+		{"auth rule as guest", nil, types.Pointer("@request.auth.id != ''"), false},
+		{"auth rule as user", user, types.Pointer("@request.auth.id != ''"), true},
+		{"topic rule match", nil, types.Pointer("topic = 'chat'"), true},
+		{"topic rule mismatch", nil, types.Pointer("topic = 'other'"), false},
+		{"data rule match", nil, types.Pointer("data.room = 'general' && data.text ~ 'ell'"), true},
+		{"data rule mismatch", nil, types.Pointer("data.room = 'private'"), false},
+		{"collection rule", user, types.Pointer("@collection.users.id ?= @request.auth.id"), true},
+		{"invalid rule", user, types.Pointer("missing = 1"), false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			requestInfo := &core.RequestInfo{Auth: s.auth}
+
+			result := apis.RealtimeCheckMessageRule(testApp, requestInfo, "chat", data, s.rule)
+			if result != s.expected {
+				t.Fatalf("Expected %v, got %v", s.expected, result)
+			}
+		})
+	}
+}
+
+func TestRealtimeBroadcast(t *testing.T) {
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	user, err := testApp.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	guestClient := subscriptions.NewDefaultClient()
+	guestClient.Subscribe("chat", "other")
+	testApp.SubscriptionsBroker().Register(guestClient)
+
+	userClient := subscriptions.NewDefaultClient()
+	userClient.Set(apis.RealtimeClientAuthKey, user)
+	userClient.Subscribe(`chat?options={"query":{"a":"1"}}`)
+	testApp.SubscriptionsBroker().Register(userClient)
+
+	prefixClient := subscriptions.NewDefaultClient()
+	prefixClient.Subscribe("chat2")
+	testApp.SubscriptionsBroker().Register(prefixClient)
+
+	scenarios := []struct {
+		name     string
+		filter   string
+		expected map[subscriptions.Client][]string
+	}{
+		{
+			"without filter",
+			"",
+			map[subscriptions.Client][]string{
+				guestClient:  {"chat"},
+				userClient:   {`chat?options={"query":{"a":"1"}}`},
+				prefixClient: nil,
+			},
+		},
+		{
+			"with auth filter",
+			"@request.auth.id != ''",
+			map[subscriptions.Client][]string{
+				guestClient:  nil,
+				userClient:   {`chat?options={"query":{"a":"1"}}`},
+				prefixClient: nil,
+			},
+		},
+		{
+			"with subscription options filter",
+			"@request.query.a = '2'",
+			map[subscriptions.Client][]string{
+				guestClient:  nil,
+				userClient:   nil,
+				prefixClient: nil,
+			},
+		},
+		{
+			"with data filter",
+			"data.text = 'hello'",
+			map[subscriptions.Client][]string{
+				guestClient:  {"chat"},
+				userClient:   {`chat?options={"query":{"a":"1"}}`},
+				prefixClient: nil,
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			err := apis.RealtimeBroadcast(testApp, "chat", map[string]any{"text": "hello"}, s.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for client, expectedNames := range s.expected {
+				names := []string{}
+
+				timeout := time.After(100 * time.Millisecond)
+			loop:
+				for {
+					select {
+					case msg := <-client.Channel():
+						names = append(names, msg.Name)
+						if string(msg.Data) != `{"text":"hello"}` {
+							t.Fatalf("Unexpected message data %s", msg.Data)
+						}
+					case <-timeout:
+						break loop
+					}
+				}
+
+				if len(names) != len(expectedNames) {
+					t.Fatalf("[%s] Expected messages %v, got %v", client.Id(), expectedNames, names)
+				}
+				for i, name := range expectedNames {
+					if names[i] != name {
+						t.Fatalf("[%s] Expected message %q, got %q", client.Id(), name, names[i])
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,466 @@
+package apis
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+	"github.com/pocketbase/pocketbase/tools/search"
+	"github.com/pocketbase/pocketbase/tools/types"
+	"github.com/spf13/cast"
+)
+
+// URL aggregate query params
+const (
+	aggregateGroupByQueryParam   = "groupBy"
+	aggregateFunctionsQueryParam = "aggregate"
+	aggregateLimitQueryParam     = "limit"
+)
+
+const (
+	aggregateMaxGroupBy   = 5
+	aggregateMaxFunctions = 20
+)
+
+// supported aggregate functions
+const (
+	aggregateCount         = "count"
+	aggregateCountDistinct = "countDistinct"
+	aggregateSum           = "sum"
+	aggregateAvg           = "avg"
+	aggregateMin           = "min"
+	aggregateMax           = "max"
+)
+
+// supported date buckets
+const (
+	aggregateBucketDay   = "day"
+	aggregateBucketWeek  = "week"
+	aggregateBucketMonth = "month"
+)
+
+var aggregateFunctionRegex = regexp.MustCompile(`^(\w+)(?:\((\w*)\))?$`)
+
+// bindRecordAggregateApi registers the collection records aggregation api endpoint.
+func bindRecordAggregateApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	rg.GET("/collections/{collection}/aggregate", recordsAggregate)
+}
+
+type aggregateGroupBy struct {
+	field  core.Field
+	bucket string
+}
+
+type aggregateFunction struct {
+	name  string
+	field core.Field
+}
+
+// recordsAggregate returns the grouped aggregated values of the
+// collection records that satisfy the list rule and the optional filter.
+//
+// Supported query parameters:
+//   - groupBy - comma separated field names with optional ":day", ":week" or ":month" date bucket suffix (e.g. "status,created:month")
+//   - aggregate - comma separated aggregate functions (e.g. "count,sum(total),countDistinct(author)"); defaults to "count"
+//   - filter - regular records filter expression
+//   - limit - max number of returned groups (default and max [search.MaxPerPage])
+//
+// Each result item has the shape:
+//
+//	{"group": {"status": "active"}, "count": 10, "sum": {"total": 123}}
+func recordsAggregate(e *core.RequestEvent) error {
+	collection, err := e.App.FindCachedCollectionByNameOrId(e.Request.PathValue("collection"))
+	if err != nil || collection == nil {
+		return e.NotFoundError("Missing collection context.", err)
+	}
+
+	err = checkCollectionRateLimit(e, collection, "list")
+	if err != nil {
+		return err
+	}
+
+	requestInfo, err := e.RequestInfo()
+	if err != nil {
+		return firstApiError(err, e.BadRequestError("", err))
+	}
+
+	if collection.ListRule == nil && !requestInfo.HasSuperuserAuth() {
+		return e.ForbiddenError("Only superusers can perform this action.", nil)
+	}
+
+	// forbid users and guests to query special filter fields
+	err = checkForSuperuserOnlyRuleFields(requestInfo)
+	if err != nil {
+		return err
+	}
+
+	allowHidden := requestInfo.HasSuperuserAuth()
+
+	groupBy, err := parseAggregateGroupBy(collection, requestInfo.Query[aggregateGroupByQueryParam], allowHidden)
+	if err != nil {
+		return e.BadRequestError("Invalid "+aggregateGroupByQueryParam+" parameter.", err)
+	}
+
+	functions, err := parseAggregateFunctions(collection, requestInfo.Query[aggregateFunctionsQueryParam], allowHidden)
+	if err != nil {
+		return e.BadRequestError("Invalid "+aggregateFunctionsQueryParam+" parameter.", err)
+	}
+
+	limit := search.MaxPerPage
+	if raw := requestInfo.Query[aggregateLimitQueryParam]; raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			return e.BadRequestError("Invalid "+aggregateLimitQueryParam+" parameter.", err)
+		}
+		limit = min(limit, search.MaxPerPage)
+	}
+
+	// build the filtered records ids subquery
+	// ---
+	tableName := collection.Name
+	idCol := "[[" + tableName + "." + core.FieldNameId + "]]"
+
+	idsQuery := e.App.DB().Select(idCol).From(tableName)
+
+	fieldsResolver := core.NewRecordFieldResolver(e.App, collection, requestInfo, true)
+
+	var ruleResolver *core.RecordFieldResolver
+	if !requestInfo.HasSuperuserAuth() && collection.ListRule != nil && *collection.ListRule != "" {
+		expr, err := search.FilterData(*collection.ListRule).BuildExpr(fieldsResolver)
+		if err != nil {
+			return err
+		}
+		ruleResolver = fieldsResolver
+		idsQuery.AndWhere(expr)
+	}
+
+	// exclude the soft deleted records unless explicitly requested by the list rule
+	if expr := softDeleteExcludeExpr(collection, ruleResolver); expr != nil {
+		idsQuery.AndWhere(expr)
+	}
+
+	// limit the tenant scoped records to the auth record tenant
+	if expr := core.TenantScopeExpr(collection, requestInfo.Auth); expr != nil {
+		idsQuery.AndWhere(expr)
+	}
+
+	// exclude the expired but not yet deleted records
+	if expr := expiredExcludeExpr(collection); expr != nil {
+		idsQuery.AndWhere(expr)
+	}
+
+	// hidden fields are searchable only by superusers
+	fieldsResolver.SetAllowHiddenFields(allowHidden)
+
+	idsQuery, err = search.NewProvider(fieldsResolver).
+		Query(idsQuery).
+		AddFilter(search.FilterData(requestInfo.Query[search.FilterQueryParam])).
+		BuildQuery()
+	if err != nil {
+		return firstApiError(err, e.BadRequestError("", err))
+	}
+
+	idsSubquery := idsQuery.Build()
+
+	// build the aggregation query
+	// ---
+	selects := make([]string, 0, len(groupBy)+len(functions))
+	groupCols := make([]string, 0, len(groupBy))
+	for i, g := range groupBy {
+		col := "g" + strconv.Itoa(i)
+		selects = append(selects, aggregateGroupByExpr(tableName, g)+" AS [["+col+"]]")
+		groupCols = append(groupCols, "[["+col+"]]")
+	}
+	for i, f := range functions {
+		selects = append(selects, aggregateFunctionExpr(tableName, f)+" AS [[a"+strconv.Itoa(i)+"]]")
+	}
+
+	query := e.App.DB().Select(selects...).
+		From(tableName).
+		Where(dbx.NewExp(idCol+" IN ("+idsSubquery.SQL()+")", idsSubquery.Params())).
+		Limit(int64(limit))
+
+	if len(groupCols) > 0 {
+		query.GroupBy(groupCols...)
+		for _, col := range groupCols {
+			query.AndOrderBy(col + " ASC")
+		}
+	}
+
+	rows, err := query.WithContext(e.Request.Context()).Rows()
+	if err != nil {
+		return firstApiError(err, e.BadRequestError("Failed to aggregate the collection records.", err))
+	}
+	defer rows.Close()
+
+	items := []map[string]any{}
+
+	for rows.Next() {
+		values := make([]any, len(selects))
+		pointers := make([]any, len(selects))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+
+		if err := rows.Scan(pointers...); err != nil {
+			return e.InternalServerError("Failed to read the aggregated values.", err)
+		}
+
+		items = append(items, newAggregateItem(groupBy, functions, values))
+	}
+
+	if err := rows.Err(); err != nil {
+		return e.InternalServerError("Failed to read the aggregated values.", err)
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"items": items,
+	})
+}
+
+func parseAggregateGroupBy(collection *core.Collection, raw string, allowHidden bool) ([]aggregateGroupBy, error) {
+	result := []aggregateGroupBy{}
+
+	if raw == "" {
+		return result, nil
+	}
+
+	parts := strings.Split(raw, ",")
+	if len(parts) > aggregateMaxGroupBy {
+		return nil, errors.New("max " + strconv.Itoa(aggregateMaxGroupBy) + " group by fields are allowed")
+	}
+
+	for _, part := range parts {
+		name, bucket, _ := strings.Cut(strings.TrimSpace(part), ":")
+
+		field, err := findAggregateField(collection, name, allowHidden)
+		if err != nil {
+			return nil, err
+		}
+
+		// the field name is used as group key
+		for _, g := range result {
+			if g.field.GetName() == name {
+				return nil, errors.New("duplicated group by field " + name)
+			}
+		}
+
+		switch bucket {
+		case "":
+		case aggregateBucketDay, aggregateBucketWeek, aggregateBucketMonth:
+			if !isAggregateDateField(field) {
+				return nil, errors.New("the " + bucket + " bucket is allowed only for date fields")
+			}
+		default:
+			return nil, errors.New("unsupported date bucket " + bucket)
+		}
+
+		result = append(result, aggregateGroupBy{field: field, bucket: bucket})
+	}
+
+	return result, nil
+}
+
+func parseAggregateFunctions(collection *core.Collection, raw string, allowHidden bool) ([]aggregateFunction, error) {
+	if raw == "" {
+		raw = aggregateCount
+	}
+
+	parts := strings.Split(raw, ",")
+	if len(parts) > aggregateMaxFunctions {
+		return nil, errors.New("max " + strconv.Itoa(aggregateMaxFunctions) + " aggregate functions are allowed")
+	}
+
+	result := make([]aggregateFunction, 0, len(parts))
+
+	for _, part := range parts {
+		match := aggregateFunctionRegex.FindStringSubmatch(strings.TrimSpace(part))
+		if len(match) != 3 {
+			return nil, errors.New("invalid aggregate function " + part)
+		}
+
+		fn := aggregateFunction{name: match[1]}
+
+		switch fn.name {
+		case aggregateCount:
+			if match[2] != "" {
+				return nil, errors.New("count doesn't accept arguments (use countDistinct to count unique field values)")
+			}
+		case aggregateCountDistinct, aggregateSum, aggregateAvg, aggregateMin, aggregateMax:
+			field, err := findAggregateField(collection, match[2], allowHidden)
+			if err != nil {
+				return nil, err
+			}
+
+			if (fn.name == aggregateSum || fn.name == aggregateAvg) &&
+				field.Type() != core.FieldTypeNumber &&
+				field.Type() != core.FieldTypeDecimal {
+				return nil, errors.New(fn.name + " is allowed only for number and decimal fields")
+			}
+
+			fn.field = field
+		default:
+			return nil, errors.New("unsupported aggregate function " + fn.name)
+		}
+
+		result = append(result, fn)
+	}
+
+	return result, nil
+}
+
+func findAggregateField(collection *core.Collection, name string, allowHidden bool) (core.Field, error) {
+	field := collection.Fields.GetByName(name)
+	if field == nil || (field.GetHidden() && !allowHidden) {
+		return nil, errors.New("unknown field " + name)
+	}
+
+	// the field view rule cannot be applied on aggregated values
+	if _, ok := collection.FieldViewRules()[name]; ok && !allowHidden {
+		return nil, errors.New("field " + name + " with view rule can be aggregated only by superusers")
+	}
+
+	// the stored encrypted values are not meaningful for aggregation
+	if ev, ok := field.(core.EncryptedValuer); ok && ev.IsEncrypted() {
+		return nil, errors.New("encrypted field " + name + " cannot be aggregated")
+	}
+
+	return field, nil
+}
+
+func isAggregateDateField(field core.Field) bool {
+	switch field.Type() {
+	case core.FieldTypeDate, core.FieldTypeAutodate:
+		return true
+	default:
+		return false
+	}
+}
+
+func aggregateGroupByExpr(tableName string, g aggregateGroupBy) string {
+	col := "[[" + tableName + "." + g.field.GetName() + "]]"
+
+	switch g.bucket {
+	case aggregateBucketDay:
+		return "strftime('%Y-%m-%d', " + col + ")"
+	case aggregateBucketWeek:
+		// the date of the Monday of the week
+		return "date(" + col + ", 'weekday 0', '-6 days')"
+	case aggregateBucketMonth:
+		return "strftime('%Y-%m', " + col + ")"
+	default:
+		return col
+	}
+}
+
+func aggregateFunctionExpr(tableName string, f aggregateFunction) string {
+	if f.name == aggregateCount {
+		return "COUNT(*)"
+	}
+
+	col := "[[" + tableName + "." + f.field.GetName() + "]]"
+
+	// the decimal values are stored as canonical strings so they need
+	// to be casted in order to be compared numerically (see also newAggregateItem)
+	if decimalField, ok := f.field.(*core.DecimalField); ok {
+		col = "CAST(" + col + " AS REAL)"
+
+		// sum the unscaled integers to avoid the floating point rounding errors
+		if f.name == aggregateSum {
+			return fmt.Sprintf("SUM(CAST(ROUND(%s * %s) AS INTEGER))", col, decimalScaleMultiplier(decimalField))
+		}
+	}
+
+	switch f.name {
+	case aggregateCountDistinct:
+		return "COUNT(DISTINCT " + col + ")"
+	case aggregateSum:
+		return "SUM(" + col + ")"
+	case aggregateAvg:
+		return "AVG(" + col + ")"
+	case aggregateMin:
+		return "MIN(" + col + ")"
+	default:
+		return "MAX(" + col + ")"
+	}
+}
+
+func newAggregateItem(groupBy []aggregateGroupBy, functions []aggregateFunction, values []any) map[string]any {
+	item := make(map[string]any, len(functions)+1)
+
+	group := make(map[string]any, len(groupBy))
+	for i, g := range groupBy {
+		v := normalizeAggregateValue(values[i])
+
+		if g.bucket == "" && g.field.Type() == core.FieldTypeBool {
+			v = cast.ToBool(v)
+		} else if v == nil && g.bucket != "" {
+			v = "" // empty date
+		}
+
+		group[g.field.GetName()] = v
+	}
+	item["group"] = group
+
+	for i, f := range functions {
+		v := normalizeAggregateValue(values[len(groupBy)+i])
+
+		if f.name == aggregateCount {
+			item[f.name] = v
+			continue
+		}
+
+		fieldValues, _ := item[f.name].(map[string]any)
+		if fieldValues == nil {
+			fieldValues = map[string]any{}
+			item[f.name] = fieldValues
+		}
+		if decimalField, ok := f.field.(*core.DecimalField); ok && v != nil {
+			v = normalizeAggregateDecimal(decimalField, f.name, v)
+		}
+
+		fieldValues[f.field.GetName()] = v
+	}
+
+	return item
+}
+
+// decimalScaleMultiplier returns the 10^scale multiplier of the decimal field
+// as SQL literal (e.g. "100" for scale 2).
+func decimalScaleMultiplier(field *core.DecimalField) string {
+	return "1" + strings.Repeat("0", max(field.Scale, 0))
+}
+
+// normalizeAggregateDecimal converts the decimal field aggregate result
+// to a decimal with the field scale.
+//
+// The avg result is returned as regular number since it is not exact.
+func normalizeAggregateDecimal(field *core.DecimalField, fn string, v any) any {
+	switch fn {
+	case aggregateSum:
+		return types.NewDecimal(cast.ToInt64(v), int32(max(field.Scale, 0))).WithJSONNumber(field.JSONNumber)
+	case aggregateMin, aggregateMax:
+		// note: the max field precision ensures that the conversion is exact
+		d, err := types.NewDecimalFromFloat(cast.ToFloat64(v))
+		if err != nil {
+			return v
+		}
+		return d.Round(int32(max(field.Scale, 0))).WithJSONNumber(field.JSONNumber)
+	default:
+		return v
+	}
+}
+
+func normalizeAggregateValue(v any) any {
+	if b, ok := v.([]byte); ok {
+		return string(b)
+	}
+
+	return v
+}
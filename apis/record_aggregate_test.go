@@ -0,0 +1,285 @@
+package apis_test
+
+import (
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestRecordsAggregate(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "missing collection",
+			Method:          http.MethodGet,
+			URL:             "/api/collections/missing/aggregate",
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:            "superuser only collection as guest",
+			Method:          http.MethodGet,
+			URL:             "/api/collections/demo1/aggregate",
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "superuser only collection as superuser",
+			Method: http.MethodGet,
+			URL:    "/api/collections/demo1/aggregate",
+			Headers: map[string]string{
+				"Authorization": revisionsTestSuperuserToken,
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"items":[{"count":3,"group":{}}]`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+		{
+			Name:            "unknown group by field",
+			Method:          http.MethodGet,
+			URL:             "/api/collections/demo2/aggregate?groupBy=missing",
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:            "date bucket for non-date field",
+			Method:          http.MethodGet,
+			URL:             "/api/collections/demo2/aggregate?groupBy=title:day",
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:            "unsupported aggregate function",
+			Method:          http.MethodGet,
+			URL:             "/api/collections/demo2/aggregate?aggregate=median(title)",
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:            "sum of non-number field",
+			Method:          http.MethodGet,
+			URL:             "/api/collections/demo2/aggregate?aggregate=sum(title)",
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "hidden field as guest",
+			Method: http.MethodGet,
+			URL:    "/api/collections/demo2/aggregate?aggregate=max(deleted)",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				stubSoftDelete(t, app)
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "encrypted field",
+			Method: http.MethodGet,
+			URL:    "/api/collections/demo2/aggregate?groupBy=title",
+			Headers: map[string]string{
+				"Authorization": revisionsTestSuperuserToken,
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				// note: t.Setenv can't be used with parallel tests
+				os.Setenv(core.FieldEncryptionKeyEnv, "abcdabcdabcdabcdabcdabcdabcdabcd")
+				t.Cleanup(func() { os.Unsetenv(core.FieldEncryptionKeyEnv) })
+
+				collection, err := app.FindCollectionByNameOrId("demo2")
+				if err != nil {
+					t.Fatal(err)
+				}
+				// encrypted fields can't be part of a unique index
+				collection.RemoveIndex("idx_unique_demo2_title")
+				collection.Fields.GetByName("title").(*core.TextField).Encrypted = true
+				if err := app.Save(collection); err != nil {
+					t.Fatal(err)
+				}
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "hidden field as superuser",
+			Method: http.MethodGet,
+			URL:    "/api/collections/demo2/aggregate?aggregate=count&filter=deleted!=''",
+			Headers: map[string]string{
+				"Authorization": revisionsTestSuperuserToken,
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				stubSoftDelete(t, app)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				// the soft deleted records are still excluded
+				`"items":[{"count":0,"group":{}}]`,
+			},
+		},
+		{
+			Name:           "group by bool field with count and countDistinct",
+			Method:         http.MethodGet,
+			URL:            "/api/collections/demo2/aggregate?groupBy=active&aggregate=count,countDistinct(title),min(title),max(title)",
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"items":[`,
+				`{"count":1,"countDistinct":{"title":1},"group":{"active":false},"max":{"title":"test1"},"min":{"title":"test1"}}`,
+				`{"count":2,"countDistinct":{"title":2},"group":{"active":true},"max":{"title":"test3"},"min":{"title":"test2"}}`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+		{
+			Name:            "duplicated group by field",
+			Method:          http.MethodGet,
+			URL:             "/api/collections/demo2/aggregate?groupBy=created:day,created:month",
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:           "month bucket",
+			Method:         http.MethodGet,
+			URL:            "/api/collections/demo2/aggregate?groupBy=created:month",
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"items":[{"count":3,"group":{"created":"2022-10"}}]`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+		{
+			Name:           "week bucket",
+			Method:         http.MethodGet,
+			URL:            "/api/collections/demo2/aggregate?groupBy=created:week",
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"items":[{"count":3,"group":{"created":"2022-10-10"}}]`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+		{
+			Name:           "filter",
+			Method:         http.MethodGet,
+			URL:            "/api/collections/demo2/aggregate?filter=title!='test1'&groupBy=created:day",
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"items":[{"count":2,"group":{"created":"2022-10-12"}}]`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+		{
+			Name:           "limit",
+			Method:         http.MethodGet,
+			URL:            "/api/collections/demo2/aggregate?groupBy=title&limit=1",
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"items":[{"count":1,"group":{"title":"test1"}}]`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+		{
+			Name:           "list rule as guest",
+			Method:         http.MethodGet,
+			URL:            "/api/collections/demo5/aggregate?aggregate=count,sum(total),avg(total)",
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"items":[{"avg":{"total":0},"count":1,"group":{},"sum":{"total":0}}]`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+		{
+			Name:   "list rule as superuser",
+			Method: http.MethodGet,
+			URL:    "/api/collections/demo5/aggregate?aggregate=count,sum(total),avg(total)",
+			Headers: map[string]string{
+				"Authorization": revisionsTestSuperuserToken,
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"items":[{"avg":{"total":1},"count":2,"group":{},"sum":{"total":2}}]`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+		{
+			Name:   "decimal field",
+			Method: http.MethodGet,
+			URL:    "/api/collections/demo2/aggregate?aggregate=sum(amount),avg(amount),min(amount),max(amount)",
+			Headers: map[string]string{
+				"Authorization": revisionsTestSuperuserToken,
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				stubAggregateDecimalAmounts(t, app)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"avg":{"amount":3.75}`,
+				`"sum":{"amount":"15.00"}`,
+				`"min":{"amount":"-2.50"}`,
+				`"max":{"amount":"10.00"}`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+		{
+			Name:   "soft deleted records exclusion",
+			Method: http.MethodGet,
+			URL:    "/api/collections/demo2/aggregate",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				stubSoftDelete(t, app)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"items":[{"count":2,"group":{}}]`,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func stubAggregateDecimalAmounts(t testing.TB, app *tests.TestApp) {
+	collection, err := app.FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	collection.Fields.Add(&core.DecimalField{Name: "amount", Scale: 2})
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	// values of different lengths and signs so that a text comparison
+	// would pick "9.00" as max and "-1.50" as min
+	amounts := map[string]string{
+		"llvuca81nly1qls": "9.00",
+		"achvryl401bhse3": "10.00",
+		"0yxhwia2amd8gec": "-2.50",
+	}
+	for id, amount := range amounts {
+		record, err := app.FindRecordById(collection, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		record.Set("amount", amount)
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	record := core.NewRecord(collection)
+	record.Set("title", "test4")
+	record.Set("amount", "-1.50")
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+}
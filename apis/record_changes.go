@@ -0,0 +1,458 @@
+package apis
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/router"
+	"github.com/pocketbase/pocketbase/tools/search"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const (
+	recordChangesDefaultLimit = 100
+	recordChangesMaxLimit     = 500
+	recordChangesMaxWait      = 60 * time.Second
+)
+
+var (
+	recordChangeConsumerRegex = regexp.MustCompile(`^[\w\-\.]+$`)
+	recordChangeColumnRegex   = regexp.MustCompile(`^\w+$`)
+)
+
+// bindRecordChangesApi registers the records change data capture api endpoints.
+func bindRecordChangesApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	notifier := newRecordChangesNotifier()
+
+	// wake up the long polling requests after every logged change
+	notify := func(e *core.RecordEvent) error {
+		if e.Record.Collection().ChangeLogEnabled() {
+			notifier.notify()
+		}
+
+		return e.Next()
+	}
+	app.OnRecordAfterCreateSuccess().Bind(&hook.Handler[*core.RecordEvent]{Func: notify})
+	app.OnRecordAfterUpdateSuccess().Bind(&hook.Handler[*core.RecordEvent]{Func: notify})
+	app.OnRecordAfterDeleteSuccess().Bind(&hook.Handler[*core.RecordEvent]{Func: notify})
+
+	sub := rg.Group("/changes")
+	sub.GET("", recordChangesList(notifier))
+	sub.GET("/checkpoints/{consumer}", recordChangeCheckpointView).Bind(RequireAuth())
+	sub.PUT("/checkpoints/{consumer}", recordChangeCheckpointSave).Bind(RequireAuth())
+}
+
+// recordChangeItem defines a single change log api item.
+type recordChangeItem struct {
+	Cursor         int64          `json:"cursor"`
+	Action         string         `json:"action"`
+	CollectionId   string         `json:"collectionId"`
+	CollectionName string         `json:"collectionName"`
+	RecordId       string         `json:"recordId"`
+	Record         *core.Record   `json:"record"`
+	Created        types.DateTime `json:"created"`
+}
+
+// recordChangesResult defines the change log api list response.
+type recordChangesResult struct {
+	Items []*recordChangeItem `json:"items"`
+
+	// Cursor is the position from which the next request should continue
+	// (it could be greater than the last item cursor in case some
+	// of the scanned changes are not accessible by the current request).
+	Cursor int64 `json:"cursor"`
+
+	HasMore bool `json:"hasMore"`
+}
+
+// recordChangesList returns the record changes after the specified cursor.
+//
+// Supported query parameters:
+//   - since       - the cursor after which to return the changes (default to the consumer checkpoint or the oldest available change)
+//   - collections - comma separated collection names or ids to limit the changes to (default to all accessible collections)
+//   - limit       - max number of changes to scan (default 100, max 500)
+//   - wait        - max number of seconds to wait for new changes if there are none (default 0, max 60)
+//   - consumer    - the name of the auth record checkpoint to resume from (when "since" is not set)
+//
+// Each change is returned only if its record snapshot satisfies the collection list rule.
+func recordChangesList(notifier *recordChangesNotifier) func(e *core.RequestEvent) error {
+	return func(e *core.RequestEvent) error {
+		requestInfo, err := e.RequestInfo()
+		if err != nil {
+			return firstApiError(err, e.BadRequestError("", err))
+		}
+
+		query := e.Request.URL.Query()
+
+		collections, err := findRecordChangesCollections(e.App, requestInfo, query.Get("collections"))
+		if err != nil {
+			return firstApiError(err, e.BadRequestError("", err))
+		}
+
+		collectionIds := make([]string, 0, len(collections))
+		for id := range collections {
+			collectionIds = append(collectionIds, id)
+		}
+
+		limit := recordChangesDefaultLimit
+		if raw := query.Get("limit"); raw != "" {
+			limit, err = strconv.Atoi(raw)
+			if err != nil || limit <= 0 {
+				return e.BadRequestError("Invalid limit query parameter.", err)
+			}
+			limit = min(limit, recordChangesMaxLimit)
+		}
+
+		var wait time.Duration
+		if raw := query.Get("wait"); raw != "" {
+			seconds, err := strconv.Atoi(raw)
+			if err != nil || seconds < 0 {
+				return e.BadRequestError("Invalid wait query parameter.", err)
+			}
+			wait = min(time.Duration(seconds)*time.Second, recordChangesMaxWait)
+		}
+
+		var since int64
+		if raw := query.Get("since"); raw != "" {
+			since, err = strconv.ParseInt(raw, 10, 64)
+			if err != nil || since < 0 {
+				return e.BadRequestError("Invalid since query parameter.", err)
+			}
+		} else if consumer := query.Get("consumer"); consumer != "" {
+			if requestInfo.Auth == nil {
+				return e.UnauthorizedError("The request requires valid record authorization token to resume from a consumer checkpoint.", nil)
+			}
+
+			since, err = e.App.FindRecordChangeCheckpoint(requestInfo.Auth, consumer)
+			if err != nil {
+				return e.InternalServerError("Failed to load the consumer checkpoint.", err)
+			}
+		}
+
+		result := &recordChangesResult{
+			Items:  []*recordChangeItem{},
+			Cursor: since,
+		}
+
+		if len(collectionIds) == 0 {
+			return e.JSON(http.StatusOK, result)
+		}
+
+		// the changes right after the cursor were deleted because of the retention limit
+		// (since=0 means "from the oldest available change" and it is never considered stale)
+		if since > 0 {
+			prunedCursor, err := e.App.FindRecordChangesPrunedCursor(collectionIds)
+			if err != nil {
+				return e.InternalServerError("Failed to load the change log state.", err)
+			}
+
+			if prunedCursor > since {
+				return e.Error(http.StatusGone, "Some of the requested changes are no longer available. Resynchronize and resume from the latest change cursor.", nil)
+			}
+		}
+
+		deadline := time.Now().Add(wait)
+
+		var changes []*core.RecordChange
+		for {
+			// note: subscribe before the query to avoid missing a change in between
+			ready := notifier.wait()
+
+			changes, err = e.App.FindRecordChanges(since, collectionIds, limit+1)
+			if err != nil {
+				return e.InternalServerError("Failed to load the record changes.", err)
+			}
+
+			remaining := time.Until(deadline)
+			if len(changes) > 0 || remaining <= 0 {
+				break
+			}
+
+			timer := time.NewTimer(remaining)
+			select {
+			case <-ready:
+			case <-timer.C:
+			case <-e.Request.Context().Done():
+				timer.Stop()
+				return e.Request.Context().Err()
+			}
+			timer.Stop()
+		}
+
+		if len(changes) > limit {
+			result.HasMore = true
+			changes = changes[:limit]
+		}
+
+		if len(changes) > 0 {
+			result.Cursor = changes[len(changes)-1].Cursor
+		}
+
+		grouped := map[string][]*core.Record{}
+
+		for _, change := range changes {
+			collection := collections[change.CollectionRef]
+
+			ok, err := canAccessRecordChange(e.App, collection, change, requestInfo)
+			if err != nil {
+				e.App.Logger().Debug(
+					"Failed to check the record change access",
+					"error", err,
+					"cursor", change.Cursor,
+					"collectionName", collection.Name,
+				)
+			}
+			if !ok {
+				continue
+			}
+
+			record, err := change.Record(collection)
+			if err != nil {
+				return e.InternalServerError("Failed to load the record change snapshot.", err)
+			}
+
+			grouped[collection.Id] = append(grouped[collection.Id], record)
+
+			result.Items = append(result.Items, &recordChangeItem{
+				Cursor:         change.Cursor,
+				Action:         change.Action,
+				CollectionId:   collection.Id,
+				CollectionName: collection.Name,
+				RecordId:       change.RecordRef,
+				Record:         record,
+				Created:        change.Created,
+			})
+		}
+
+		for _, records := range grouped {
+			if err := EnrichRecords(e, records); err != nil {
+				return firstApiError(err, e.InternalServerError("Failed to enrich records", err))
+			}
+		}
+
+		return e.JSON(http.StatusOK, result)
+	}
+}
+
+// findRecordChangesCollections returns the change log enabled collections
+// (with their id as map key) that are listable by the current request.
+//
+// rawNames is an optional comma separated list of collection names or ids.
+// If empty, all accessible change log enabled collections are returned.
+func findRecordChangesCollections(app core.App, requestInfo *core.RequestInfo, rawNames string) (map[string]*core.Collection, error) {
+	result := map[string]*core.Collection{}
+
+	isSuperuser := requestInfo.HasSuperuserAuth()
+
+	if rawNames == "" {
+		collections, err := app.FindAllCollections(core.CollectionTypeBase, core.CollectionTypeAuth)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, collection := range collections {
+			if collection.ChangeLogEnabled() && (isSuperuser || collection.ListRule != nil) {
+				result[collection.Id] = collection
+			}
+		}
+
+		return result, nil
+	}
+
+	for _, name := range strings.Split(rawNames, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		collection, err := app.FindCachedCollectionByNameOrId(name)
+		if err != nil || !collection.ChangeLogEnabled() {
+			return nil, router.NewBadRequestError(fmt.Sprintf("Missing or not change log enabled collection %q.", name), err)
+		}
+
+		if !isSuperuser && collection.ListRule == nil {
+			return nil, router.NewForbiddenError(fmt.Sprintf("Only superusers can list the %q collection changes.", collection.Name), nil)
+		}
+
+		result[collection.Id] = collection
+	}
+
+	return result, nil
+}
+
+// canAccessRecordChange reports whether the record snapshot of the
+// provided change satisfies its collection list rule.
+//
+// The rule is evaluated against the snapshot (and not against the current
+// record state) so that it could be applied also for the deleted records.
+func canAccessRecordChange(app core.App, collection *core.Collection, change *core.RecordChange, requestInfo *core.RequestInfo) (bool, error) {
+	if requestInfo.HasSuperuserAuth() {
+		return true, nil
+	}
+
+	if collection.ListRule == nil {
+		return false, nil
+	}
+
+	// only the same tenant users can access the tenant scoped records changes
+	if collection.TenantScoped() {
+		tenantId := core.AuthTenantId(collection, requestInfo.Auth)
+		if tenantId == "" || tenantId != change.RowData()[core.FieldNameTenant].String {
+			return false, nil
+		}
+	}
+
+	if *collection.ListRule == "" {
+		return true, nil
+	}
+
+	params := dbx.Params{}
+	columns := make([]string, 0, len(change.Data))
+
+	var i int
+	for name, value := range change.RowData() {
+		if !recordChangeColumnRegex.MatchString(name) {
+			continue
+		}
+
+		placeholder := "snapshot" + strconv.Itoa(i)
+		i++
+
+		if value.Valid {
+			params[placeholder] = value.String
+		} else {
+			params[placeholder] = nil
+		}
+
+		column := "{:" + placeholder + "}"
+
+		// restore the column numeric affinity since the bound params are strings
+		if field := collection.Fields.GetByName(name); field != nil {
+			switch field.Type() {
+			case core.FieldTypeNumber, core.FieldTypeBool:
+				column = "CAST(" + column + " AS NUMERIC)"
+			}
+		}
+
+		columns = append(columns, column+" AS [["+name+"]]")
+	}
+
+	if len(columns) == 0 {
+		return false, nil
+	}
+
+	var exists int
+
+	query := app.DB().Select("(1)").
+		From("(SELECT " + strings.Join(columns, ", ") + ") " + collection.Name).
+		AndBind(params)
+
+	resolver := core.NewRecordFieldResolver(app, collection, requestInfo, true)
+	expr, err := search.FilterData(*collection.ListRule).BuildExpr(resolver)
+	if err != nil {
+		return false, err
+	}
+	resolver.UpdateQuery(query)
+
+	err = query.AndWhere(expr).Limit(1).Row(&exists)
+	if err != nil {
+		return false, err
+	}
+
+	return exists > 0, nil
+}
+
+// -------------------------------------------------------------------
+
+func recordChangeCheckpointView(e *core.RequestEvent) error {
+	consumer := e.Request.PathValue("consumer")
+	if !recordChangeConsumerRegex.MatchString(consumer) {
+		return e.NotFoundError("", nil)
+	}
+
+	cursor, err := e.App.FindRecordChangeCheckpoint(e.Auth, consumer)
+	if err != nil {
+		return e.InternalServerError("Failed to load the consumer checkpoint.", err)
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"consumer": consumer,
+		"cursor":   cursor,
+	})
+}
+
+func recordChangeCheckpointSave(e *core.RequestEvent) error {
+	form := new(recordChangeCheckpointForm)
+
+	err := e.BindBody(form)
+	if err != nil {
+		return e.BadRequestError("An error occurred while loading the submitted data.", err)
+	}
+
+	form.Consumer = e.Request.PathValue("consumer")
+
+	err = form.validate()
+	if err != nil {
+		return e.BadRequestError("An error occurred while validating the submitted data.", err)
+	}
+
+	err = e.App.SaveRecordChangeCheckpoint(e.Auth, form.Consumer, form.Cursor)
+	if err != nil {
+		return e.BadRequestError("Failed to save the consumer checkpoint.", err)
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"consumer": form.Consumer,
+		"cursor":   form.Cursor,
+	})
+}
+
+type recordChangeCheckpointForm struct {
+	Consumer string `form:"consumer" json:"consumer"`
+	Cursor   int64  `form:"cursor" json:"cursor"`
+}
+
+func (form *recordChangeCheckpointForm) validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(&form.Consumer, validation.Required, validation.Length(1, 100), validation.Match(recordChangeConsumerRegex)),
+		validation.Field(&form.Cursor, validation.Min(int64(0))),
+	)
+}
+
+// -------------------------------------------------------------------
+
+// recordChangesNotifier broadcasts a signal to all waiting
+// long polling requests when a new record change is logged.
+type recordChangesNotifier struct {
+	mu sync.Mutex
+	ch chan struct{}
+}
+
+func newRecordChangesNotifier() *recordChangesNotifier {
+	return &recordChangesNotifier{ch: make(chan struct{})}
+}
+
+// wait returns a channel that is closed on the next notify call.
+func (n *recordChangesNotifier) wait() <-chan struct{} {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.ch
+}
+
+func (n *recordChangesNotifier) notify() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	close(n.ch)
+	n.ch = make(chan struct{})
+}
@@ -0,0 +1,395 @@
+package apis_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func setupRecordChangesTest(t testing.TB, app core.App) {
+	users, err := app.FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	collection := core.NewBaseCollection("test_changes")
+	collection.ChangeLog = &core.ChangeLogConfig{Enabled: true}
+	collection.ListRule = types.Pointer("(active = true && total > 5) || (@request.auth.id != '' && owner = @request.auth.id)")
+	collection.Fields.Add(
+		&core.TextField{Name: "title"},
+		&core.NumberField{Name: "total"},
+		&core.BoolField{Name: "active"},
+		&core.RelationField{Name: "owner", CollectionId: users.Id, MaxSelect: 1},
+	)
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	superuserOnly := core.NewBaseCollection("test_changes_superuser")
+	superuserOnly.ChangeLog = &core.ChangeLogConfig{Enabled: true}
+	superuserOnly.Fields.Add(&core.TextField{Name: "title"})
+	if err := app.Save(superuserOnly); err != nil {
+		t.Fatal(err)
+	}
+
+	data := []struct {
+		collection *core.Collection
+		values     map[string]any
+	}{
+		{collection, map[string]any{"title": "visible_deleted", "total": 10, "active": true}},
+		{collection, map[string]any{"title": "hidden_total", "total": 3, "active": true}},
+		{collection, map[string]any{"title": "hidden_owned", "total": 10, "active": false, "owner": "4q1xlclmfloku33"}},
+		{superuserOnly, map[string]any{"title": "superuser_only"}},
+	}
+
+	records := make([]*core.Record, len(data))
+	for i, d := range data {
+		records[i] = core.NewRecord(d.collection)
+		records[i].Load(d.values)
+		if err := app.Save(records[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := app.Delete(records[0]); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRecordChangesList(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:           "guest",
+			Method:         http.MethodGet,
+			URL:            "/api/changes",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) { setupRecordChangesTest(t, app) },
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"cursor":5`,
+				`"hasMore":false`,
+				`"action":"create","collectionId":`,
+				`"action":"delete","collectionId":`,
+				`"title":"visible_deleted"`,
+			},
+			NotExpectedContent: []string{
+				`"hidden_total"`,
+				`"hidden_owned"`,
+				`"superuser_only"`,
+			},
+		},
+		{
+			Name:   "auth record satisfying the rule for the owned record",
+			Method: http.MethodGet,
+			URL:    "/api/changes?collections=test_changes",
+			Headers: map[string]string{
+				"Authorization": fieldRulesTestUserToken,
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) { setupRecordChangesTest(t, app) },
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"title":"visible_deleted"`,
+				`"title":"hidden_owned"`,
+			},
+			NotExpectedContent: []string{
+				`"hidden_total"`,
+				`"superuser_only"`,
+			},
+		},
+		{
+			Name:   "superuser",
+			Method: http.MethodGet,
+			URL:    "/api/changes",
+			Headers: map[string]string{
+				"Authorization": revisionsTestSuperuserToken,
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) { setupRecordChangesTest(t, app) },
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"title":"visible_deleted"`,
+				`"title":"hidden_total"`,
+				`"title":"hidden_owned"`,
+				`"title":"superuser_only"`,
+				`"collectionName":"test_changes_superuser"`,
+			},
+		},
+		{
+			Name:            "guest with superuser only collection",
+			Method:          http.MethodGet,
+			URL:             "/api/changes?collections=test_changes,test_changes_superuser",
+			BeforeTestFunc:  func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) { setupRecordChangesTest(t, app) },
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "collection without change log",
+			Method: http.MethodGet,
+			URL:    "/api/changes?collections=demo1",
+			Headers: map[string]string{
+				"Authorization": revisionsTestSuperuserToken,
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "missing collection",
+			Method: http.MethodGet,
+			URL:    "/api/changes?collections=missing",
+			Headers: map[string]string{
+				"Authorization": revisionsTestSuperuserToken,
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:            "invalid since",
+			Method:          http.MethodGet,
+			URL:             "/api/changes?since=abc",
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "since and limit",
+			Method: http.MethodGet,
+			URL:    "/api/changes?since=1&limit=2",
+			Headers: map[string]string{
+				"Authorization": revisionsTestSuperuserToken,
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) { setupRecordChangesTest(t, app) },
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"cursor":3,"hasMore":true`,
+				`"title":"hidden_total"`,
+				`"title":"hidden_owned"`,
+			},
+			NotExpectedContent: []string{
+				`"visible_deleted"`,
+				`"superuser_only"`,
+			},
+		},
+		{
+			Name:   "no new changes",
+			Method: http.MethodGet,
+			URL:    "/api/changes?since=5",
+			Headers: map[string]string{
+				"Authorization": revisionsTestSuperuserToken,
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) { setupRecordChangesTest(t, app) },
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`{"items":[],"cursor":5,"hasMore":false}`,
+			},
+		},
+		{
+			Name:   "pruned changes after the requested cursor",
+			Method: http.MethodGet,
+			URL:    "/api/changes?since=1&collections=test_changes",
+			Headers: map[string]string{
+				"Authorization": revisionsTestSuperuserToken,
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupRecordChangesTest(t, app)
+
+				collection, err := app.FindCollectionByNameOrId("test_changes")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				_, err = app.DB().Insert(core.RecordChangesPrunedTable, dbx.Params{
+					"collectionRef": collection.Id,
+					"cursor":        2,
+				}).Execute()
+				if err != nil {
+					t.Fatal(err)
+				}
+			},
+			ExpectedStatus:  410,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "long polling until a new change",
+			Method: http.MethodGet,
+			URL:    "/api/changes?since=5&wait=10",
+			Headers: map[string]string{
+				"Authorization": revisionsTestSuperuserToken,
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupRecordChangesTest(t, app)
+
+				collection, err := app.FindCollectionByNameOrId("test_changes")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				time.AfterFunc(200*time.Millisecond, func() {
+					record := core.NewRecord(collection)
+					record.Set("title", "new_change")
+					if err := app.Save(record); err != nil {
+						t.Error(err)
+					}
+				})
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"cursor":6`,
+				`"title":"new_change"`,
+			},
+		},
+		{
+			Name:   "long polling timeout",
+			Method: http.MethodGet,
+			URL:    "/api/changes?since=5&wait=1",
+			Headers: map[string]string{
+				"Authorization": revisionsTestSuperuserToken,
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) { setupRecordChangesTest(t, app) },
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`{"items":[],"cursor":5,"hasMore":false}`,
+			},
+		},
+		{
+			Name:            "guest resuming from consumer checkpoint",
+			Method:          http.MethodGet,
+			URL:             "/api/changes?consumer=sync",
+			ExpectedStatus:  401,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "resuming from consumer checkpoint",
+			Method: http.MethodGet,
+			URL:    "/api/changes?consumer=sync",
+			Headers: map[string]string{
+				"Authorization": fieldRulesTestUserToken,
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupRecordChangesTest(t, app)
+
+				user, err := app.FindRecordById("users", "4q1xlclmfloku33")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if err := app.SaveRecordChangeCheckpoint(user, "sync", 3); err != nil {
+					t.Fatal(err)
+				}
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"cursor":5`,
+				`"action":"delete"`,
+				`"title":"visible_deleted"`,
+			},
+			NotExpectedContent: []string{
+				`"hidden_owned"`,
+				`"action":"create"`,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestRecordChangeCheckpoints(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "view as guest",
+			Method:          http.MethodGet,
+			URL:             "/api/changes/checkpoints/sync",
+			ExpectedStatus:  401,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "view missing checkpoint",
+			Method: http.MethodGet,
+			URL:    "/api/changes/checkpoints/sync",
+			Headers: map[string]string{
+				"Authorization": fieldRulesTestUserToken,
+			},
+			ExpectedStatus:  200,
+			ExpectedContent: []string{`{"consumer":"sync","cursor":0}`},
+		},
+		{
+			Name:   "view existing checkpoint",
+			Method: http.MethodGet,
+			URL:    "/api/changes/checkpoints/sync",
+			Headers: map[string]string{
+				"Authorization": fieldRulesTestUserToken,
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				user, err := app.FindRecordById("users", "4q1xlclmfloku33")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if err := app.SaveRecordChangeCheckpoint(user, "sync", 12); err != nil {
+					t.Fatal(err)
+				}
+			},
+			ExpectedStatus:  200,
+			ExpectedContent: []string{`{"consumer":"sync","cursor":12}`},
+		},
+		{
+			Name:            "save as guest",
+			Method:          http.MethodPut,
+			URL:             "/api/changes/checkpoints/sync",
+			Body:            strings.NewReader(`{"cursor":10}`),
+			ExpectedStatus:  401,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "save with invalid data",
+			Method: http.MethodPut,
+			URL:    "/api/changes/checkpoints/sync@invalid",
+			Body:   strings.NewReader(`{"cursor":-1}`),
+			Headers: map[string]string{
+				"Authorization": fieldRulesTestUserToken,
+			},
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"consumer":{`,
+				`"cursor":{`,
+			},
+		},
+		{
+			Name:   "save",
+			Method: http.MethodPut,
+			URL:    "/api/changes/checkpoints/sync",
+			Body:   strings.NewReader(`{"cursor":10}`),
+			Headers: map[string]string{
+				"Authorization": fieldRulesTestUserToken,
+			},
+			ExpectedStatus:  200,
+			ExpectedContent: []string{`{"consumer":"sync","cursor":10}`},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				user, err := app.FindRecordById("users", "4q1xlclmfloku33")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				cursor, err := app.FindRecordChangeCheckpoint(user, "sync")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if cursor != 10 {
+					t.Fatalf("Expected stored cursor 10, got %d", cursor)
+				}
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
@@ -1,6 +1,7 @@
 package apis
 
 import (
+	"context"
 	cryptoRand "crypto/rand"
 	"errors"
 	"fmt"
@@ -63,21 +64,45 @@ func recordsList(e *core.RequestEvent) error {
 
 	fieldsResolver := core.NewRecordFieldResolver(e.App, collection, requestInfo, true)
 
+	var ruleResolver *core.RecordFieldResolver
 	if !requestInfo.HasSuperuserAuth() && collection.ListRule != nil && *collection.ListRule != "" {
 		expr, err := search.FilterData(*collection.ListRule).BuildExpr(fieldsResolver)
 		if err != nil {
 			return err
 		}
+		ruleResolver = fieldsResolver
 		query.AndWhere(expr)
 
 		// will be applied by the search provider right before executing the query
 		// fieldsResolver.UpdateQuery(query)
 	}
 
+	// exclude the soft deleted records unless explicitly requested by the list rule
+	if expr := softDeleteExcludeExpr(collection, ruleResolver); expr != nil {
+		query.AndWhere(expr)
+	}
+
+	// limit the tenant scoped records to the auth record tenant
+	if expr := core.TenantScopeExpr(collection, requestInfo.Auth); expr != nil {
+		query.AndWhere(expr)
+	}
+
+	// exclude the expired but not yet deleted records
+	if expr := expiredExcludeExpr(collection); expr != nil {
+		query.AndWhere(expr)
+	}
+
 	// hidden fields are searchable only by superusers
 	fieldsResolver.SetAllowHiddenFields(requestInfo.HasSuperuserAuth())
 
-	searchProvider := search.NewProvider(fieldsResolver).Query(query)
+	cursorSecret, err := core.RecordsCursorSecret(e.App, collection)
+	if err != nil {
+		return e.InternalServerError("Failed to load the cursor secret.", err)
+	}
+
+	searchProvider := search.NewProvider(fieldsResolver).
+		Query(query).
+		CursorSecret(cursorSecret)
 
 	// use rowid when available to minimize the need of a covering index with the "id" field
 	if !collection.IsView() {
@@ -90,6 +115,13 @@ func recordsList(e *core.RequestEvent) error {
 		return firstApiError(err, e.BadRequestError("", err))
 	}
 
+	// load the fts() snippets only if explicitly requested with the fields picker
+	if strings.Contains(e.Request.URL.Query().Get(fieldsQueryParam), core.SearchSnippetKey) {
+		if err := core.LoadSearchSnippets(e.App, fieldsResolver, records); err != nil {
+			return firstApiError(err, e.BadRequestError("Failed to load the search snippets.", err))
+		}
+	}
+
 	event := new(core.RecordsListRequestEvent)
 	event.RequestEvent = e
 	event.Collection = collection
@@ -167,13 +199,26 @@ func recordView(e *core.RequestEvent) error {
 	}
 
 	ruleFunc := func(q *dbx.SelectQuery) error {
+		if expr := core.TenantScopeExpr(collection, requestInfo.Auth); expr != nil {
+			q.AndWhere(expr)
+		}
+
+		if expr := expiredExcludeExpr(collection); expr != nil {
+			q.AndWhere(expr)
+		}
+
+		var ruleResolver *core.RecordFieldResolver
 		if !requestInfo.HasSuperuserAuth() && collection.ViewRule != nil && *collection.ViewRule != "" {
-			resolver := core.NewRecordFieldResolver(e.App, collection, requestInfo, true)
-			expr, err := search.FilterData(*collection.ViewRule).BuildExpr(resolver)
+			ruleResolver = core.NewRecordFieldResolver(e.App, collection, requestInfo, true)
+			expr, err := search.FilterData(*collection.ViewRule).BuildExpr(ruleResolver)
 			if err != nil {
 				return err
 			}
-			resolver.UpdateQuery(q)
+			ruleResolver.UpdateQuery(q)
+			q.AndWhere(expr)
+		}
+
+		if expr := softDeleteExcludeExpr(collection, ruleResolver); expr != nil {
 			q.AndWhere(expr)
 		}
 		return nil
@@ -195,6 +240,8 @@ func recordView(e *core.RequestEvent) error {
 		}
 
 		return execAfterSuccessTx(true, e.App, func() error {
+			setRecordETagHeader(e.RequestEvent, e.Record)
+
 			return e.JSON(http.StatusOK, e.Record)
 		})
 	})
@@ -233,6 +280,15 @@ func recordCreate(responseWriteAfterTx bool, optFinalizer func(data any) error)
 			return firstApiError(err, e.BadRequestError("Failed to read the submitted data.", err))
 		}
 
+		// tenant scoped records can be created by non-superusers only within their own tenant
+		var tenantId string
+		if !hasSuperuserAuth && collection.TenantScoped() {
+			tenantId = core.AuthTenantId(collection, requestInfo.Auth)
+			if tenantId == "" {
+				return e.ForbiddenError("Missing or invalid auth record tenant.", nil)
+			}
+		}
+
 		// set a random password for the OAuth2 ignoring its plain password validators
 		var skipPlainPasswordRecordValidators bool
 		if requestInfo.Context == core.RequestInfoContextOAuth2 {
@@ -248,11 +304,17 @@ func recordCreate(responseWriteAfterTx bool, optFinalizer func(data any) error)
 		requestInfo.Body = data
 
 		form := forms.NewRecordUpsert(e.App, record)
+		form.SetContext(recordChangeContext(e))
 		if hasSuperuserAuth {
 			form.GrantSuperuserAccess()
 		}
 		form.Load(data)
 
+		// note: the hidden tenant field is not loaded from the submitted data for non-superusers
+		if tenantId != "" {
+			record.Set(core.FieldNameTenant, tenantId)
+		}
+
 		if skipPlainPasswordRecordValidators {
 			// unset the plain value to skip the plain password field validators
 			if raw, ok := record.GetRaw(core.FieldNamePassword).(*core.PasswordFieldValue); ok {
@@ -428,13 +490,22 @@ func recordUpdate(responseWriteAfterTx bool, optFinalizer func(data any) error)
 		requestInfo.Body = data
 
 		ruleFunc := func(q *dbx.SelectQuery) error {
+			if expr := core.TenantScopeExpr(collection, requestInfo.Auth); expr != nil {
+				q.AndWhere(expr)
+			}
+
+			var ruleResolver *core.RecordFieldResolver
 			if !hasSuperuserAuth && collection.UpdateRule != nil && *collection.UpdateRule != "" {
-				resolver := core.NewRecordFieldResolver(e.App, collection, requestInfo, true)
-				expr, err := search.FilterData(*collection.UpdateRule).BuildExpr(resolver)
+				ruleResolver = core.NewRecordFieldResolver(e.App, collection, requestInfo, true)
+				expr, err := search.FilterData(*collection.UpdateRule).BuildExpr(ruleResolver)
 				if err != nil {
 					return err
 				}
-				resolver.UpdateQuery(q)
+				ruleResolver.UpdateQuery(q)
+				q.AndWhere(expr)
+			}
+
+			if expr := softDeleteExcludeExpr(collection, ruleResolver); expr != nil {
 				q.AndWhere(expr)
 			}
 			return nil
@@ -446,7 +517,17 @@ func recordUpdate(responseWriteAfterTx bool, optFinalizer func(data any) error)
 			return firstApiError(err, e.NotFoundError("", err))
 		}
 
+		err = checkFieldUpdateRules(e.App, record, requestInfo)
+		if err != nil {
+			return firstApiError(err, e.BadRequestError("Failed to update record.", err))
+		}
+
+		if apiErr := applyIfMatchPrecondition(e, record); apiErr != nil {
+			return apiErr
+		}
+
 		form := forms.NewRecordUpsert(e.App, record)
+		form.SetContext(recordChangeContext(e))
 		if hasSuperuserAuth {
 			form.GrantSuperuserAccess()
 		}
@@ -473,6 +554,9 @@ func recordUpdate(responseWriteAfterTx bool, optFinalizer func(data any) error)
 
 			err := form.Submit()
 			if err != nil {
+				if apiErr := normalizeETagMismatchError(e.RequestEvent, err); apiErr != nil {
+					return apiErr
+				}
 				return firstApiError(err, e.BadRequestError("Failed to update record.", err))
 			}
 
@@ -481,6 +565,8 @@ func recordUpdate(responseWriteAfterTx bool, optFinalizer func(data any) error)
 				return firstApiError(err, e.InternalServerError("Failed to enrich record", err))
 			}
 
+			setRecordETagHeader(e.RequestEvent, e.Record)
+
 			err = execAfterSuccessTx(responseWriteAfterTx, e.App, func() error {
 				return e.JSON(http.StatusOK, e.Record)
 			})
@@ -544,15 +630,27 @@ func recordDelete(responseWriteAfterTx bool, optFinalizer func(data any) error)
 		}
 
 		ruleFunc := func(q *dbx.SelectQuery) error {
+			if expr := core.TenantScopeExpr(collection, requestInfo.Auth); expr != nil {
+				q.AndWhere(expr)
+			}
+
+			var ruleResolver *core.RecordFieldResolver
 			if !requestInfo.HasSuperuserAuth() && collection.DeleteRule != nil && *collection.DeleteRule != "" {
-				resolver := core.NewRecordFieldResolver(e.App, collection, requestInfo, true)
-				expr, err := search.FilterData(*collection.DeleteRule).BuildExpr(resolver)
+				ruleResolver = core.NewRecordFieldResolver(e.App, collection, requestInfo, true)
+				expr, err := search.FilterData(*collection.DeleteRule).BuildExpr(ruleResolver)
 				if err != nil {
 					return err
 				}
-				resolver.UpdateQuery(q)
+				ruleResolver.UpdateQuery(q)
 				q.AndWhere(expr)
 			}
+
+			// note: superusers can delete (aka. purge) the soft deleted records
+			if !requestInfo.HasSuperuserAuth() {
+				if expr := softDeleteExcludeExpr(collection, ruleResolver); expr != nil {
+					q.AndWhere(expr)
+				}
+			}
 			return nil
 		}
 
@@ -561,6 +659,10 @@ func recordDelete(responseWriteAfterTx bool, optFinalizer func(data any) error)
 			return e.NotFoundError("", err)
 		}
 
+		if apiErr := applyIfMatchPrecondition(e, record); apiErr != nil {
+			return apiErr
+		}
+
 		var isOptFinalizerCalled bool
 
 		event := new(core.RecordRequestEvent)
@@ -569,7 +671,10 @@ func recordDelete(responseWriteAfterTx bool, optFinalizer func(data any) error)
 		event.Record = record
 
 		hookErr := e.App.OnRecordDeleteRequest().Trigger(event, func(e *core.RecordRequestEvent) error {
-			if err := e.App.Delete(e.Record); err != nil {
+			if err := e.App.DeleteWithContext(recordChangeContext(e.RequestEvent), e.Record); err != nil {
+				if apiErr := normalizeETagMismatchError(e.RequestEvent, err); apiErr != nil {
+					return apiErr
+				}
 				return firstApiError(err, e.BadRequestError("Failed to delete record. Make sure that the record is not part of a required relation reference.", err))
 			}
 
@@ -740,3 +845,13 @@ func hasAuthManageAccess(app core.App, requestInfo *core.RequestInfo, collection
 
 	return err == nil && exists > 0
 }
+
+// recordChangeContext returns the context for the record create/update/delete db operations.
+//
+// It preserves the request context values (eg. the request trace id) but not its cancellation
+// so that an interrupted client connection doesn't abort an already started record change.
+//
+// The request auth record (if any) is also stored in the context (see [core.AuthFromContext]).
+func recordChangeContext(e *core.RequestEvent) context.Context {
+	return core.ContextWithAuth(context.WithoutCancel(e.Request.Context()), e.Auth)
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/tests"
 	"github.com/pocketbase/pocketbase/tools/router"
+	"github.com/pocketbase/pocketbase/tools/search"
 	"github.com/pocketbase/pocketbase/tools/types"
 )
 
@@ -3582,3 +3583,125 @@ func TestRecordCrudUpdate(t *testing.T) {
 		scenario.Test(t)
 	}
 }
+
+func TestRecordCrudListCursor(t *testing.T) {
+	t.Parallel()
+
+	// generate the cursor of the second page
+	var nextCursor, unsignedCursor string
+	func() {
+		app, _ := tests.NewTestApp()
+		defer app.Cleanup()
+
+		collection, err := app.FindCollectionByNameOrId("demo2")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		secret, err := core.RecordsCursorSecret(app, collection)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := search.NewProvider(core.NewRecordFieldResolver(app, collection, nil, true)).
+			Query(app.RecordQuery(collection)).
+			CursorSecret(secret).
+			Sort(search.ParseSortFromString("-title")).
+			PerPage(2).
+			Cursor("").
+			Exec(&[]*core.Record{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		nextCursor = result.NextCursor
+
+		// the same cursor signed with the default empty secret
+		result, err = search.NewProvider(core.NewRecordFieldResolver(app, collection, nil, true)).
+			Query(app.RecordQuery(collection)).
+			Sort(search.ParseSortFromString("-title")).
+			PerPage(2).
+			Cursor("").
+			Exec(&[]*core.Record{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		unsignedCursor = result.NextCursor
+	}()
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "invalid cursor",
+			Method:          http.MethodGet,
+			URL:             "/api/collections/demo2/records?cursor=invalid",
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:           "first page",
+			Method:         http.MethodGet,
+			URL:            "/api/collections/demo2/records?cursor=&perPage=2&sort=-title",
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"totalItems":3`,
+				`"items":[{`,
+				`"id":"0yxhwia2amd8gec"`,
+				`"id":"achvryl401bhse3"`,
+				`"nextCursor":"`,
+			},
+			NotExpectedContent: []string{
+				`"id":"llvuca81nly1qls"`,
+				`"prevCursor"`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                    0,
+				"OnRecordsListRequest": 1,
+				"OnRecordEnrich":       2,
+			},
+		},
+		{
+			Name:           "last page",
+			Method:         http.MethodGet,
+			URL:            "/api/collections/demo2/records?perPage=2&sort=-title&skipTotal=1&cursor=" + nextCursor,
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"totalItems":-1`,
+				`"items":[{`,
+				`"id":"llvuca81nly1qls"`,
+				`"prevCursor":"`,
+			},
+			NotExpectedContent: []string{
+				`"id":"0yxhwia2amd8gec"`,
+				`"id":"achvryl401bhse3"`,
+				`"nextCursor"`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                    0,
+				"OnRecordsListRequest": 1,
+				"OnRecordEnrich":       1,
+			},
+		},
+		{
+			Name:            "cursor with an invalid signature",
+			Method:          http.MethodGet,
+			URL:             "/api/collections/demo2/records?perPage=2&sort=-title&cursor=" + unsignedCursor,
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:            "cursor with different sort",
+			Method:          http.MethodGet,
+			URL:             "/api/collections/demo2/records?perPage=2&sort=title&cursor=" + nextCursor,
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
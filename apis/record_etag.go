@@ -0,0 +1,83 @@
+package apis
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+)
+
+const (
+	headerETag    = "ETag"
+	headerIfMatch = "If-Match"
+)
+
+// applyIfMatchPrecondition checks the request If-Match header (if any)
+// against the current record ETag and registers it as record save/delete
+// precondition so that it could be rechecked as part of the db operation.
+//
+// Returns a 412 error if the If-Match header is not satisfied.
+func applyIfMatchPrecondition(e *core.RequestEvent, record *core.Record) *router.ApiError {
+	header := strings.TrimSpace(e.Request.Header.Get(headerIfMatch))
+	if header == "" || header == "*" {
+		return nil // no precondition
+	}
+
+	etag := record.ETag()
+
+	if etag == "" || !etagListContains(header, etag) {
+		return preconditionFailedError(e)
+	}
+
+	record.SetExpectedETag(etag)
+
+	return nil
+}
+
+// etagListContains reports whether the comma separated list of
+// entity tags contains the specified etag (using strong comparison).
+func etagListContains(list string, etag string) bool {
+	for _, item := range strings.Split(list, ",") {
+		if strings.TrimSpace(item) == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+func preconditionFailedError(e *core.RequestEvent) *router.ApiError {
+	return e.Error(http.StatusPreconditionFailed, "The record was modified since it was last fetched.", nil)
+}
+
+// normalizeETagMismatchError converts the [core.ErrETagMismatch] error
+// to a 412 api error and returns nil for any other error.
+func normalizeETagMismatchError(e *core.RequestEvent, err error) *router.ApiError {
+	if errors.Is(err, core.ErrETagMismatch) {
+		return preconditionFailedError(e)
+	}
+
+	return nil
+}
+
+// setRecordETagHeader sets the ETag response header with the
+// ETag of the current stored state of the provided record.
+func setRecordETagHeader(e *core.RequestEvent, record *core.Record) {
+	etag := record.ETag()
+
+	// refetch to get the ETag of the latest stored state
+	if etag == "" && !record.IsNew() {
+		stored, err := e.App.FindRecordById(record.Collection(), record.Id)
+		if err != nil {
+			e.App.Logger().Warn("Failed to load the record ETag", "error", err, "id", record.Id)
+			return
+		}
+		etag = stored.ETag()
+	}
+
+	if etag != "" {
+		e.Response.Header().Set(headerETag, etag)
+	}
+}
@@ -0,0 +1,269 @@
+package apis_test
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func findTestRecordETag(t testing.TB, collection string, id string) string {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record, err := app.FindRecordById(collection, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return record.ETag()
+}
+
+func TestRecordETagHeaders(t *testing.T) {
+	t.Parallel()
+
+	etag := findTestRecordETag(t, "demo2", "0yxhwia2amd8gec")
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:           "view response",
+			Method:         http.MethodGet,
+			URL:            "/api/collections/demo2/records/0yxhwia2amd8gec",
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"id":"0yxhwia2amd8gec"`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                   0,
+				"OnRecordViewRequest": 1,
+				"OnRecordEnrich":      1,
+			},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				if v := res.Header.Get("ETag"); v != etag {
+					t.Fatalf("Expected ETag header %q, got %q", etag, v)
+				}
+			},
+		},
+		{
+			Name:           "update response",
+			Method:         http.MethodPatch,
+			URL:            "/api/collections/demo2/records/0yxhwia2amd8gec",
+			Body:           strings.NewReader(`{"title":"new"}`),
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"id":"0yxhwia2amd8gec"`,
+				`"title":"new"`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                          0,
+				"OnRecordUpdateRequest":      1,
+				"OnModelUpdate":              1,
+				"OnModelUpdateExecute":       1,
+				"OnModelAfterUpdateSuccess":  1,
+				"OnRecordUpdate":             1,
+				"OnRecordUpdateExecute":      1,
+				"OnRecordAfterUpdateSuccess": 1,
+				"OnModelValidate":            1,
+				"OnRecordValidate":           1,
+				"OnRecordEnrich":             1,
+			},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				record, err := app.FindRecordById("demo2", "0yxhwia2amd8gec")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				v := res.Header.Get("ETag")
+				if v == "" || v == etag || v != record.ETag() {
+					t.Fatalf("Expected the new record ETag header %q, got %q (old %q)", record.ETag(), v, etag)
+				}
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestRecordIfMatchPrecondition(t *testing.T) {
+	t.Parallel()
+
+	etag := findTestRecordETag(t, "demo2", "0yxhwia2amd8gec")
+
+	updateSuccessEvents := map[string]int{
+		"*":                          0,
+		"OnRecordUpdateRequest":      1,
+		"OnModelUpdate":              1,
+		"OnModelUpdateExecute":       1,
+		"OnModelAfterUpdateSuccess":  1,
+		"OnRecordUpdate":             1,
+		"OnRecordUpdateExecute":      1,
+		"OnRecordAfterUpdateSuccess": 1,
+		"OnModelValidate":            1,
+		"OnRecordValidate":           1,
+		"OnRecordEnrich":             1,
+	}
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:   "update with mismatched If-Match",
+			Method: http.MethodPatch,
+			URL:    "/api/collections/demo2/records/0yxhwia2amd8gec",
+			Body:   strings.NewReader(`{"title":"new"}`),
+			Headers: map[string]string{
+				"If-Match": `"invalid"`,
+			},
+			ExpectedStatus:  412,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "update with weak If-Match",
+			Method: http.MethodPatch,
+			URL:    "/api/collections/demo2/records/0yxhwia2amd8gec",
+			Body:   strings.NewReader(`{"title":"new"}`),
+			Headers: map[string]string{
+				"If-Match": "W/" + etag,
+			},
+			ExpectedStatus:  412,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "update with matching If-Match",
+			Method: http.MethodPatch,
+			URL:    "/api/collections/demo2/records/0yxhwia2amd8gec",
+			Body:   strings.NewReader(`{"title":"new"}`),
+			Headers: map[string]string{
+				"If-Match": `"other", ` + etag,
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"id":"0yxhwia2amd8gec"`,
+				`"title":"new"`,
+			},
+			ExpectedEvents: updateSuccessEvents,
+		},
+		{
+			Name:   "update with wildcard If-Match",
+			Method: http.MethodPatch,
+			URL:    "/api/collections/demo2/records/0yxhwia2amd8gec",
+			Body:   strings.NewReader(`{"title":"new"}`),
+			Headers: map[string]string{
+				"If-Match": "*",
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"id":"0yxhwia2amd8gec"`,
+				`"title":"new"`,
+			},
+			ExpectedEvents: updateSuccessEvents,
+		},
+		{
+			Name:   "update with matching If-Match but concurrently modified record",
+			Method: http.MethodPatch,
+			URL:    "/api/collections/demo2/records/0yxhwia2amd8gec",
+			Body:   strings.NewReader(`{"title":"new"}`),
+			Headers: map[string]string{
+				"If-Match": etag,
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				// simulate a concurrent change between the request record fetch and its save
+				app.OnRecordUpdateRequest("demo2").BindFunc(func(e *core.RecordRequestEvent) error {
+					_, err := e.App.DB().Update("demo2", dbx.Params{"title": "concurrent"}, dbx.HashExp{"id": e.Record.Id}).Execute()
+					if err != nil {
+						t.Fatal(err)
+					}
+					return e.Next()
+				})
+			},
+			ExpectedStatus:  412,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents: map[string]int{
+				"*":                        0,
+				"OnRecordUpdateRequest":    1,
+				"OnModelUpdate":            1,
+				"OnModelUpdateExecute":     1,
+				"OnModelAfterUpdateError":  1,
+				"OnRecordUpdate":           1,
+				"OnRecordUpdateExecute":    1,
+				"OnRecordAfterUpdateError": 1,
+				"OnModelValidate":          1,
+				"OnRecordValidate":         1,
+			},
+		},
+		{
+			Name:   "delete with mismatched If-Match",
+			Method: http.MethodDelete,
+			URL:    "/api/collections/demo2/records/0yxhwia2amd8gec",
+			Headers: map[string]string{
+				"If-Match": `"invalid"`,
+			},
+			ExpectedStatus:  412,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "delete with matching If-Match",
+			Method: http.MethodDelete,
+			URL:    "/api/collections/demo2/records/0yxhwia2amd8gec",
+			Headers: map[string]string{
+				"If-Match": etag,
+			},
+			ExpectedStatus: 204,
+			ExpectedEvents: map[string]int{
+				"*":                          0,
+				"OnRecordDeleteRequest":      1,
+				"OnModelDelete":              1,
+				"OnModelDeleteExecute":       1,
+				"OnModelAfterDeleteSuccess":  1,
+				"OnRecordDelete":             1,
+				"OnRecordDeleteExecute":      1,
+				"OnRecordAfterDeleteSuccess": 1,
+				// cascade update of the referencing users record
+				"OnModelUpdate":              1,
+				"OnModelUpdateExecute":       1,
+				"OnModelAfterUpdateSuccess":  1,
+				"OnRecordUpdate":             1,
+				"OnRecordUpdateExecute":      1,
+				"OnRecordAfterUpdateSuccess": 1,
+			},
+		},
+		{
+			Name:   "batch with per item If-Match",
+			Method: http.MethodPost,
+			URL:    "/api/batch",
+			Body: strings.NewReader(`{
+				"requests": [
+					{"method":"PATCH", "url":"/api/collections/demo2/records/0yxhwia2amd8gec", "body": {"title": "batch1"}, "headers": {"If-Match": ` + strconv.Quote(etag) + `}},
+					{"method":"PATCH", "url":"/api/collections/demo2/records/achvryl401bhse3", "body": {"title": "batch2"}, "headers": {"If-Match": "\"invalid\""}}
+				]
+			}`),
+			Headers: map[string]string{
+				// the batch request precondition should be ignored
+				"If-Match": `"invalid"`,
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				app.Settings().Batch.Enabled = true
+				app.Settings().Batch.MaxRequests = 10
+			},
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"requests":{"1":{"code":"batch_request_failed"`,
+				`"status":412`,
+			},
+			NotExpectedContent: []string{
+				`"requests":{"0"`,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
@@ -0,0 +1,180 @@
+package apis
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+	"github.com/pocketbase/pocketbase/tools/search"
+)
+
+// bindRecordExportApi registers the records export api endpoint.
+func bindRecordExportApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	rg.GET("/collections/{collection}/export", recordsExport)
+}
+
+var recordExportContentTypes = map[string]string{
+	core.RecordExportFormatCSV:    "text/csv; charset=utf-8",
+	core.RecordExportFormatNDJSON: "application/x-ndjson",
+	core.RecordExportFormatXLSX:   "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// recordsExport streams the collection records that satisfy the list API rule
+// as CSV, NDJSON or XLSX file.
+//
+// Supported query parameters:
+//   - format - "csv" (default), "ndjson" or "xlsx"
+//   - filter - the same as the records list API filter
+//   - sort   - the same as the records list API sort
+//   - fields - the same as the records list API fields picker
+//   - expand - the same as the records list API expand (flattened as "rel.field" CSV/XLSX columns)
+func recordsExport(e *core.RequestEvent) error {
+	collection, err := e.App.FindCachedCollectionByNameOrId(e.Request.PathValue("collection"))
+	if err != nil || collection == nil {
+		return e.NotFoundError("Missing collection context.", err)
+	}
+
+	requestInfo, err := e.RequestInfo()
+	if err != nil {
+		return firstApiError(err, e.BadRequestError("", err))
+	}
+
+	if collection.ListRule == nil && !requestInfo.HasSuperuserAuth() {
+		return e.ForbiddenError("Only superusers can perform this action.", nil)
+	}
+
+	// forbid users and guests to query special filter/sort fields
+	err = checkForSuperuserOnlyRuleFields(requestInfo)
+	if err != nil {
+		return err
+	}
+
+	query := e.Request.URL.Query()
+
+	format := query.Get("format")
+	if format == "" {
+		format = core.RecordExportFormatCSV
+	}
+
+	contentType, ok := recordExportContentTypes[format]
+	if !ok {
+		return e.BadRequestError("Invalid or unsupported export format.", nil)
+	}
+
+	recordsQuery := e.App.RecordQuery(collection)
+
+	fieldsResolver := core.NewRecordFieldResolver(e.App, collection, requestInfo, true)
+
+	var ruleResolver *core.RecordFieldResolver
+	if !requestInfo.HasSuperuserAuth() && collection.ListRule != nil && *collection.ListRule != "" {
+		expr, err := search.FilterData(*collection.ListRule).BuildExpr(fieldsResolver)
+		if err != nil {
+			return err
+		}
+		ruleResolver = fieldsResolver
+		recordsQuery.AndWhere(expr)
+	}
+
+	// exclude the soft deleted records unless explicitly requested by the list rule
+	if expr := softDeleteExcludeExpr(collection, ruleResolver); expr != nil {
+		recordsQuery.AndWhere(expr)
+	}
+
+	// limit the tenant scoped records to the auth record tenant
+	if expr := core.TenantScopeExpr(collection, requestInfo.Auth); expr != nil {
+		recordsQuery.AndWhere(expr)
+	}
+
+	// exclude the expired but not yet deleted records
+	if expr := expiredExcludeExpr(collection); expr != nil {
+		recordsQuery.AndWhere(expr)
+	}
+
+	// hidden fields are searchable only by superusers
+	fieldsResolver.SetAllowHiddenFields(requestInfo.HasSuperuserAuth())
+
+	searchProvider := search.NewProvider(fieldsResolver).Query(recordsQuery)
+
+	if raw := query.Get(search.FilterQueryParam); raw != "" {
+		searchProvider.AddFilter(search.FilterData(raw))
+	}
+
+	if raw := query.Get(search.SortQueryParam); raw != "" {
+		for _, sortField := range search.ParseSortFromString(raw) {
+			searchProvider.AddSort(sortField)
+		}
+	}
+
+	// validate the filter and sort expressions before start streaming
+	if _, err := searchProvider.BuildQuery(); err != nil {
+		return e.BadRequestError("Invalid filter or sort parameters.", err)
+	}
+
+	var expands []string
+	if raw := query.Get(expandQueryParam); raw != "" {
+		expands = strings.Split(raw, ",")
+	}
+
+	// delay the file headers until the first write so that the errors
+	// that occur before streaming are still returned as regular api errors
+	out := &recordExportResponseWriter{
+		ResponseWriter: e.Response,
+		headers: func(h http.Header) {
+			h.Set("Content-Type", contentType)
+			h.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+				"filename": collection.Name + "." + format,
+			}))
+		},
+	}
+
+	_, err = core.ExportRecords(e.Request.Context(), e.App, collection, searchProvider, out, core.RecordExportOptions{
+		Format:            format,
+		Fields:            query.Get(fieldsQueryParam),
+		Expand:            expands,
+		AllowHiddenFields: requestInfo.HasSuperuserAuth(),
+		Enrich: func(records []*core.Record) error {
+			return EnrichRecords(e, records)
+		},
+	})
+	if err != nil {
+		if out.headers == nil {
+			return err // already streaming, can't change the response
+		}
+
+		return firstApiError(err, e.BadRequestError("Failed to export records.", err))
+	}
+
+	if out.headers != nil {
+		// nothing was written (should be rare)
+		out.writeHeaders()
+	}
+
+	return nil
+}
+
+// recordExportResponseWriter is a http.ResponseWriter wrapper
+// that sets the specified headers on the first write.
+type recordExportResponseWriter struct {
+	http.ResponseWriter
+	headers func(h http.Header)
+}
+
+func (w *recordExportResponseWriter) writeHeaders() {
+	if w.headers == nil {
+		return
+	}
+
+	w.headers(w.ResponseWriter.Header())
+	w.headers = nil
+
+	w.ResponseWriter.WriteHeader(http.StatusOK)
+}
+
+// Write implements the [io.Writer] interface.
+func (w *recordExportResponseWriter) Write(b []byte) (int, error) {
+	w.writeHeaders()
+
+	return w.ResponseWriter.Write(b)
+}
@@ -0,0 +1,195 @@
+package apis_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func setupRecordExportTest(t testing.TB, app core.App) {
+	authors := core.NewBaseCollection("test_export_authors")
+	authors.ListRule = types.Pointer("")
+	authors.ViewRule = types.Pointer("")
+	authors.Fields.Add(&core.TextField{Name: "name"})
+	if err := app.Save(authors); err != nil {
+		t.Fatal(err)
+	}
+
+	author := core.NewRecord(authors)
+	author.Set("name", "author1")
+	if err := app.Save(author); err != nil {
+		t.Fatal(err)
+	}
+
+	posts := core.NewBaseCollection("test_export")
+	posts.ListRule = types.Pointer("title != 'private'")
+	posts.Fields.Add(
+		&core.TextField{Name: "title"},
+		&core.TextField{Name: "secret", Hidden: true},
+		&core.RelationField{Name: "author", CollectionId: authors.Id, MaxSelect: 1},
+	)
+	if err := app.Save(posts); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, title := range []string{"public1", "private", "public2"} {
+		post := core.NewRecord(posts)
+		post.Set("title", title)
+		post.Set("secret", title+"_secret")
+		post.Set("author", author.Id)
+		if err := app.Save(post); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	superusersOnly := core.NewBaseCollection("test_export_superusers")
+	superusersOnly.Fields.Add(&core.TextField{Name: "title"})
+	if err := app.Save(superusersOnly); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRecordsExport(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:   "missing collection",
+			Method: http.MethodGet,
+			URL:    "/api/collections/missing/export",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupRecordExportTest(t, app)
+			},
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "guest with superusers only collection",
+			Method: http.MethodGet,
+			URL:    "/api/collections/test_export_superusers/export",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupRecordExportTest(t, app)
+			},
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "invalid format",
+			Method: http.MethodGet,
+			URL:    "/api/collections/test_export/export?format=xml",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupRecordExportTest(t, app)
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "invalid filter",
+			Method: http.MethodGet,
+			URL:    "/api/collections/test_export/export?filter=missing=1",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupRecordExportTest(t, app)
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "guest filtering by hidden field",
+			Method: http.MethodGet,
+			URL:    "/api/collections/test_export/export?filter=secret!=''",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupRecordExportTest(t, app)
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "invalid expand",
+			Method: http.MethodGet,
+			URL:    "/api/collections/test_export/export?expand=title",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupRecordExportTest(t, app)
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "guest csv with list rule, expand and fields",
+			Method: http.MethodGet,
+			URL:    "/api/collections/test_export/export?sort=-title&fields=title,secret,expand.author.name&expand=author",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupRecordExportTest(t, app)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				"title,author.name\npublic2,author1\npublic1,author1\n",
+			},
+			NotExpectedContent: []string{
+				"private",
+				"secret",
+			},
+			ExpectedEvents: map[string]int{
+				"*":                    0,
+				"OnRecordEnrich":       3,
+				"OnRecordsListRequest": 0,
+			},
+		},
+		{
+			Name:   "superuser ndjson with hidden fields",
+			Method: http.MethodGet,
+			URL:    "/api/collections/test_export/export?format=ndjson&fields=title,secret&filter=title='private'",
+			Headers: map[string]string{
+				"Authorization": revisionsTestSuperuserToken,
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupRecordExportTest(t, app)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`{"secret":"private_secret","title":"private"}`,
+			},
+			NotExpectedContent: []string{
+				"public",
+			},
+			ExpectedEvents: map[string]int{
+				"*":              0,
+				"OnRecordEnrich": 1,
+			},
+		},
+		{
+			Name:   "superuser xlsx",
+			Method: http.MethodGet,
+			URL:    "/api/collections/test_export/export?format=xlsx",
+			Headers: map[string]string{
+				"Authorization": revisionsTestSuperuserToken,
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupRecordExportTest(t, app)
+			},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				expectedHeaders := map[string]string{
+					"Content-Type":        "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+					"Content-Disposition": `attachment; filename=test_export.xlsx`,
+				}
+				for k, v := range expectedHeaders {
+					if h := res.Header.Get(k); h != v {
+						t.Fatalf("Expected %s header %q, got %q", k, v, h)
+					}
+				}
+			},
+			ExpectedStatus:  200,
+			ExpectedContent: []string{"PK"},
+			ExpectedEvents: map[string]int{
+				"*":              0,
+				"OnRecordEnrich": 3,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
@@ -0,0 +1,157 @@
+package apis
+
+import (
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/search"
+)
+
+// applyFieldViewRules hides the fields of the provided records
+// whose collection field view rule is not satisfied by the current request.
+//
+// Note: Expects all records to be from the same collection!
+func applyFieldViewRules(app core.App, records []*core.Record, requestInfo *core.RequestInfo) error {
+	if len(records) == 0 || requestInfo.HasSuperuserAuth() {
+		return nil // nothing to check
+	}
+
+	collection := records[0].Collection()
+
+	viewRules := collection.FieldViewRules()
+	if len(viewRules) == 0 {
+		return nil // no field rules
+	}
+
+	recordIds := make([]any, len(records))
+	for i, rec := range records {
+		recordIds[i] = rec.Id
+	}
+
+	// group the fields by rule to minimize the number of queries
+	ruleFields := map[string][]string{}
+	for name, rule := range viewRules {
+		ruleFields[rule] = append(ruleFields[rule], name)
+	}
+
+	for rule, fieldNames := range ruleFields {
+		allowedIds, err := findRuleMatchingRecordIds(app, collection, requestInfo, rule, recordIds)
+		if err != nil {
+			// hide all fields with view rule on error to prevent accidental leaks
+			// (including the ones from the already processed and the remaining rule groups)
+			for _, rec := range records {
+				for name := range viewRules {
+					rec.Hide(name)
+				}
+			}
+			return err
+		}
+
+		for _, rec := range records {
+			if _, ok := allowedIds[rec.Id]; !ok {
+				rec.Hide(fieldNames...)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkFieldUpdateRules checks whether the submitted data of an existing record
+// satisfies the collection field update rules and returns a validation error
+// for each field that is not allowed to be updated.
+//
+// requestInfo.Body is expected to be already populated with the submitted data.
+func checkFieldUpdateRules(app core.App, record *core.Record, requestInfo *core.RequestInfo) error {
+	if requestInfo.HasSuperuserAuth() {
+		return nil // superusers can update everything
+	}
+
+	updateRules := record.Collection().FieldUpdateRules()
+	if len(updateRules) == 0 {
+		return nil // no field rules
+	}
+
+	submitted := submittedFieldNames(requestInfo.Body)
+
+	errs := validation.Errors{}
+
+	checkedRules := map[string]bool{}
+	for name, rule := range updateRules {
+		if _, ok := submitted[name]; !ok {
+			continue
+		}
+
+		allowed, checked := checkedRules[rule]
+		if !checked {
+			allowedIds, err := findRuleMatchingRecordIds(app, record.Collection(), requestInfo, rule, []any{record.Id})
+			if err != nil {
+				return err
+			}
+
+			_, allowed = allowedIds[record.Id]
+			checkedRules[rule] = allowed
+		}
+
+		if !allowed {
+			errs[name] = validation.NewError("validation_field_update_forbidden", "You are not allowed to update this field.")
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// submittedFieldNames returns the field names of the submitted data keys
+// (aka. with trimmed modifiers like "+name", "name+" and "name-").
+func submittedFieldNames(data map[string]any) map[string]struct{} {
+	result := make(map[string]struct{}, len(data))
+
+	for key := range data {
+		name := strings.TrimPrefix(key, "+")
+		name = strings.TrimSuffix(name, "+")
+		name = strings.TrimSuffix(name, "-")
+		result[name] = struct{}{}
+	}
+
+	return result
+}
+
+// findRuleMatchingRecordIds returns the ids of the specified collection
+// records that satisfy the provided rule.
+func findRuleMatchingRecordIds(
+	app core.App,
+	collection *core.Collection,
+	requestInfo *core.RequestInfo,
+	rule string,
+	recordIds []any,
+) (map[string]struct{}, error) {
+	query := app.RecordQuery(collection).
+		Select(app.ConcurrentDB().QuoteSimpleColumnName(collection.Name) + ".id").
+		AndWhere(dbx.In(app.ConcurrentDB().QuoteSimpleColumnName(collection.Name)+".id", recordIds...))
+
+	resolver := core.NewRecordFieldResolver(app, collection, requestInfo, true)
+	expr, err := search.FilterData(rule).BuildExpr(resolver)
+	if err != nil {
+		return nil, err
+	}
+	resolver.UpdateQuery(query)
+	query.AndWhere(expr)
+
+	ids := []string{}
+	if err := query.Column(&ids); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		result[id] = struct{}{}
+	}
+
+	return result, nil
+}
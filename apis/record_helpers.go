@@ -47,6 +47,10 @@ func recordAuthResponse(e *core.RequestEvent, authRecord *core.Record, token str
 		return err
 	}
 
+	if authRecord.IsSoftDeleted() {
+		return e.ForbiddenError("The auth record is deleted.", nil)
+	}
+
 	ok, err := e.App.CanAccessRecord(authRecord, originalRequestInfo, authRecord.Collection().AuthRule)
 	if !ok {
 		return firstApiError(err, e.ForbiddenError("The request doesn't satisfy the collection requirements to authenticate.", err))
@@ -92,6 +96,10 @@ func recordAuthResponse(e *core.RequestEvent, authRecord *core.Record, token str
 				e.Record.Unhide(e.Record.Collection().Fields.FieldNames()...)
 			}
 
+			if err := applyFieldViewRules(e.App, []*core.Record{e.Record}, &requestInfo); err != nil {
+				e.App.Logger().Warn("[recordAuthResponse] Failed to apply the field view rules", "error", err)
+			}
+
 			// allow always returning the email address of the authenticated model
 			e.Record.IgnoreEmailVisibility(true)
 
@@ -360,6 +368,16 @@ func defaultEnrichRecords(app core.App, requestInfo *core.RequestInfo, records [
 	return nil
 }
 
+// expiredExcludeExpr returns an expression that excludes the expired
+// records of collections with enabled expiry and hideExpired option.
+func expiredExcludeExpr(collection *core.Collection) dbx.Expression {
+	if !collection.ExpiryEnabled() || !collection.Expiry.HideExpired {
+		return nil
+	}
+
+	return dbx.Not(core.ExpiredRecordsExpr(collection))
+}
+
 // expandFetch is the records fetch function that is used to expand related records.
 func expandFetch(app core.App, originalRequestInfo *core.RequestInfo) core.ExpandFetchFunc {
 	// shallow clone the provided request info to set an "expand" context
@@ -369,21 +387,33 @@ func expandFetch(app core.App, originalRequestInfo *core.RequestInfo) core.Expan
 
 	return func(relCollection *core.Collection, relIds []string) ([]*core.Record, error) {
 		records, findErr := app.FindRecordsByIds(relCollection.Id, relIds, func(q *dbx.SelectQuery) error {
-			if requestInfoPtr.Auth != nil && requestInfoPtr.Auth.IsSuperuser() {
-				return nil // superusers can access everything
+			if expr := core.TenantScopeExpr(relCollection, requestInfoPtr.Auth); expr != nil {
+				q.AndWhere(expr)
 			}
 
-			if relCollection.ViewRule == nil {
+			if expr := expiredExcludeExpr(relCollection); expr != nil {
+				q.AndWhere(expr)
+			}
+
+			// superusers can access everything (except the soft deleted records)
+			isSuperuser := requestInfoPtr.Auth != nil && requestInfoPtr.Auth.IsSuperuser()
+
+			if !isSuperuser && relCollection.ViewRule == nil {
 				return fmt.Errorf("only superusers can view collection %q records", relCollection.Name)
 			}
 
-			if *relCollection.ViewRule != "" {
-				resolver := core.NewRecordFieldResolver(app, relCollection, requestInfoPtr, true)
-				expr, err := search.FilterData(*(relCollection.ViewRule)).BuildExpr(resolver)
+			var ruleResolver *core.RecordFieldResolver
+			if !isSuperuser && *relCollection.ViewRule != "" {
+				ruleResolver = core.NewRecordFieldResolver(app, relCollection, requestInfoPtr, true)
+				expr, err := search.FilterData(*(relCollection.ViewRule)).BuildExpr(ruleResolver)
 				if err != nil {
 					return err
 				}
-				resolver.UpdateQuery(q)
+				ruleResolver.UpdateQuery(q)
+				q.AndWhere(expr)
+			}
+
+			if expr := softDeleteExcludeExpr(relCollection, ruleResolver); expr != nil {
 				q.AndWhere(expr)
 			}
 
@@ -413,6 +443,7 @@ func expandFetch(app core.App, originalRequestInfo *core.RequestInfo) core.Expan
 //
 // Currently it enables:
 // - export of hidden fields if the current auth model is a superuser
+// - hiding of the fields whose field view rule is not satisfied
 // - email export ignoring the emailVisibity checks if the current auth model is superuser, owner or a "manager".
 //
 // Note: Expects all records to be from the same collection!
@@ -429,6 +460,11 @@ func autoResolveRecordsFlags(app core.App, records []*core.Record, requestInfo *
 		}
 	}
 
+	err := applyFieldViewRules(app, records, requestInfo)
+	if err != nil {
+		return err
+	}
+
 	// additional emailVisibility checks
 	// ---------------------------------------------------------------
 	if !records[0].Collection().IsAuth() {
@@ -0,0 +1,105 @@
+package apis
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+)
+
+// bindRecordImportApi registers the bulk records import api endpoints.
+func bindRecordImportApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	sub := rg.Group("/collections/{collection}/import").Bind(RequireSuperuserAuth())
+	sub.POST("", recordsImport).Bind(BodyLimit(0)) // the body is streamed
+	sub.GET("/{importId}/report", recordsImportReport)
+}
+
+// recordsImport imports the CSV or NDJSON request body rows as collection records.
+//
+// Supported query parameters:
+//   - format    - "csv" or "ndjson" (default to the request Content-Type)
+//   - mapping   - JSON object with the source column to field name mapping (eg. {"Email":"email"})
+//   - upsert    - unique field name used to update the existing records
+//   - chunkSize - number of rows imported in a single transaction
+func recordsImport(e *core.RequestEvent) error {
+	collection, err := e.App.FindCachedCollectionByNameOrId(e.Request.PathValue("collection"))
+	if err != nil || collection == nil {
+		return e.NotFoundError("Missing collection context.", err)
+	}
+
+	query := e.Request.URL.Query()
+
+	options := core.RecordImportOptions{
+		Format:      query.Get("format"),
+		UpsertField: query.Get("upsert"),
+	}
+
+	if options.Format == "" {
+		options.Format = recordImportFormatFromContentType(e.Request.Header.Get("Content-Type"))
+	}
+
+	if raw := query.Get("mapping"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &options.Mapping); err != nil {
+			return e.BadRequestError("Invalid mapping query parameter.", err)
+		}
+	}
+
+	if raw := query.Get("chunkSize"); raw != "" {
+		options.ChunkSize, err = strconv.Atoi(raw)
+		if err != nil || options.ChunkSize <= 0 {
+			return e.BadRequestError("Invalid chunkSize query parameter.", err)
+		}
+	}
+
+	// read directly from the original request body to avoid keeping
+	// the entire streamed content in memory (it is not reread later)
+	var body io.Reader = e.Request.Body
+	if rr, ok := body.(*router.RereadableReadCloser); ok {
+		body = rr.ReadCloser
+	}
+
+	report, err := core.ImportRecords(e.Request.Context(), e.App, collection, body, options)
+	if err != nil {
+		return firstApiError(err, e.BadRequestError("Failed to import records.", err))
+	}
+
+	return e.JSON(http.StatusOK, report)
+}
+
+// recordsImportReport sends the per-row errors report of a single import as CSV file.
+func recordsImportReport(e *core.RequestEvent) error {
+	collection, err := e.App.FindCachedCollectionByNameOrId(e.Request.PathValue("collection"))
+	if err != nil || collection == nil {
+		return e.NotFoundError("Missing collection context.", err)
+	}
+
+	report, err := core.FindRecordImportById(e.App, e.Request.PathValue("importId"))
+	if err != nil || report.CollectionRef != collection.Id {
+		return e.NotFoundError("", err)
+	}
+
+	e.Response.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	e.Response.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": "import_" + report.Id + "_report.csv",
+	}))
+	e.Response.WriteHeader(http.StatusOK)
+
+	return report.WriteErrorsCSV(e.Response)
+}
+
+func recordImportFormatFromContentType(contentType string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	switch mediaType {
+	case "text/csv":
+		return core.RecordImportFormatCSV
+	case "application/x-ndjson", "application/ndjson", "application/jsonl":
+		return core.RecordImportFormatNDJSON
+	}
+
+	return ""
+}
@@ -0,0 +1,240 @@
+package apis_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func setupRecordImportTest(t testing.TB, app core.App) *core.Collection {
+	collection := core.NewBaseCollection("test_import")
+	collection.Fields.Add(
+		&core.TextField{Name: "title", Required: true},
+		&core.TextField{Name: "slug"},
+	)
+	collection.AddIndex("idx_test_import_slug", true, "slug", "slug != ''")
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	existing := core.NewRecord(collection)
+	existing.Set("title", "existing")
+	existing.Set("slug", "existing")
+	if err := app.Save(existing); err != nil {
+		t.Fatal(err)
+	}
+
+	return collection
+}
+
+func TestRecordsImport(t *testing.T) {
+	t.Parallel()
+
+	csvData := "Title,slug\na,a\n,b\nupdated,existing\n"
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:   "guest",
+			Method: http.MethodPost,
+			URL:    "/api/collections/test_import/import?format=csv",
+			Body:   strings.NewReader(csvData),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupRecordImportTest(t, app)
+			},
+			ExpectedStatus:  401,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "regular auth record",
+			Method: http.MethodPost,
+			URL:    "/api/collections/test_import/import?format=csv",
+			Body:   strings.NewReader(csvData),
+			Headers: map[string]string{
+				"Authorization": fieldRulesTestUserToken,
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupRecordImportTest(t, app)
+			},
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "missing collection",
+			Method: http.MethodPost,
+			URL:    "/api/collections/missing/import?format=csv",
+			Body:   strings.NewReader(csvData),
+			Headers: map[string]string{
+				"Authorization": revisionsTestSuperuserToken,
+			},
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "unknown format",
+			Method: http.MethodPost,
+			URL:    "/api/collections/test_import/import",
+			Body:   strings.NewReader(csvData),
+			Headers: map[string]string{
+				"Authorization": revisionsTestSuperuserToken,
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupRecordImportTest(t, app)
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "invalid mapping",
+			Method: http.MethodPost,
+			URL:    `/api/collections/test_import/import?format=csv&mapping={"Title":"missing"}`,
+			Body:   strings.NewReader(csvData),
+			Headers: map[string]string{
+				"Authorization": revisionsTestSuperuserToken,
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupRecordImportTest(t, app)
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "csv with mapping and upsert (format from Content-Type)",
+			Method: http.MethodPost,
+			URL:    `/api/collections/test_import/import?upsert=slug&chunkSize=1&mapping={"Title":"title","slug":"slug"}`,
+			Body:   strings.NewReader(csvData),
+			Headers: map[string]string{
+				"Authorization": revisionsTestSuperuserToken,
+				"Content-Type":  "text/csv; charset=utf-8",
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupRecordImportTest(t, app)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"format":"csv"`,
+				`"totalRows":3`,
+				`"createdRows":1`,
+				`"updatedRows":1`,
+				`"failedRows":1`,
+			},
+			NotExpectedContent: []string{
+				`"errors"`,
+			},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				record, err := app.FindFirstRecordByData("test_import", "slug", "existing")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if v := record.GetString("title"); v != "updated" {
+					t.Fatalf("Expected the existing record title to be updated, got %q", v)
+				}
+			},
+		},
+		{
+			Name:   "ndjson",
+			Method: http.MethodPost,
+			URL:    "/api/collections/test_import/import",
+			Body:   strings.NewReader(`{"title":"a","slug":"a"}` + "\n" + `{"title":"b"}`),
+			Headers: map[string]string{
+				"Authorization": revisionsTestSuperuserToken,
+				"Content-Type":  "application/x-ndjson",
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupRecordImportTest(t, app)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"format":"ndjson"`,
+				`"totalRows":2`,
+				`"createdRows":2`,
+				`"failedRows":0`,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestRecordsImportReport(t *testing.T) {
+	t.Parallel()
+
+	createReport := func(t testing.TB, app core.App) *core.RecordImport {
+		collection := setupRecordImportTest(t, app)
+
+		report, err := core.ImportRecords(context.Background(), app, collection, strings.NewReader("title\na\n\n\"\"\n"), core.RecordImportOptions{
+			Format: core.RecordImportFormatCSV,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return report
+	}
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:   "guest",
+			Method: http.MethodGet,
+			URL:    "/api/collections/test_import/import/test/report",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupRecordImportTest(t, app)
+			},
+			ExpectedStatus:  401,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "missing report",
+			Method: http.MethodGet,
+			URL:    "/api/collections/test_import/import/missing/report",
+			Headers: map[string]string{
+				"Authorization": revisionsTestSuperuserToken,
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupRecordImportTest(t, app)
+			},
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+
+	// the report id is known only after the report creation
+	// (note: the app is cleaned up by the scenario)
+	app, _ := tests.NewTestApp()
+
+	reportId := createReport(t, app).Id
+
+	(&tests.ApiScenario{
+		Name:   "existing report",
+		Method: http.MethodGet,
+		URL:    "/api/collections/test_import/import/" + reportId + "/report",
+		Headers: map[string]string{
+			"Authorization": revisionsTestSuperuserToken,
+		},
+		TestAppFactory: func(t testing.TB) *tests.TestApp {
+			return app
+		},
+		ExpectedStatus: 200,
+		ExpectedContent: []string{
+			"row,field,code,message\n",
+			"2,title,validation_required,",
+		},
+		AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+			if v := res.Header.Get("Content-Type"); !strings.HasPrefix(v, "text/csv") {
+				t.Fatalf("Expected text/csv Content-Type, got %q", v)
+			}
+			if v := res.Header.Get("Content-Disposition"); !strings.Contains(v, "attachment") || !strings.Contains(v, reportId) {
+				t.Fatalf("Expected attachment Content-Disposition, got %q", v)
+			}
+		},
+	}).Test(t)
+}
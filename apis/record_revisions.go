@@ -0,0 +1,130 @@
+package apis
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+	"github.com/pocketbase/pocketbase/tools/search"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// bindRecordRevisionsApi registers the record revisions history api endpoints.
+func bindRecordRevisionsApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	sub := rg.Group("/collections/{collection}/records/{id}").Bind(RequireSuperuserAuth())
+	sub.GET("/revisions", recordRevisionsList)
+	sub.GET("/asof", recordViewAsOf)
+	sub.POST("/revisions/{revisionId}/restore", recordRevisionRestore)
+}
+
+func recordRevisionsList(e *core.RequestEvent) error {
+	collection, err := e.App.FindCachedCollectionByNameOrId(e.Request.PathValue("collection"))
+	if err != nil || collection == nil || collection.IsView() {
+		return e.NotFoundError("Missing collection context.", err)
+	}
+
+	revisionsCollection, err := e.App.FindCachedCollectionByNameOrId(core.CollectionNameRecordRevisions)
+	if err != nil {
+		return e.InternalServerError("Missing record revisions collection.", err)
+	}
+
+	requestInfo, err := e.RequestInfo()
+	if err != nil {
+		return firstApiError(err, e.BadRequestError("", err))
+	}
+
+	query := e.App.RecordQuery(revisionsCollection).AndWhere(dbx.HashExp{
+		"collectionRef": collection.Id,
+		"recordRef":     e.Request.PathValue("id"),
+	})
+
+	// newest first by default
+	if e.Request.URL.Query().Get(search.SortQueryParam) == "" {
+		query.OrderBy("created DESC", "_rowid_ DESC")
+	}
+
+	fieldsResolver := core.NewRecordFieldResolver(e.App, revisionsCollection, requestInfo, true)
+
+	records := []*core.Record{}
+	result, err := search.NewProvider(fieldsResolver).
+		Query(query).
+		CountCol("_rowid_").
+		ParseAndExec(e.Request.URL.Query().Encode(), &records)
+	if err != nil {
+		return firstApiError(err, e.BadRequestError("", err))
+	}
+
+	return e.JSON(http.StatusOK, result)
+}
+
+func recordViewAsOf(e *core.RequestEvent) error {
+	collection, err := e.App.FindCachedCollectionByNameOrId(e.Request.PathValue("collection"))
+	if err != nil || collection == nil || collection.IsView() {
+		return e.NotFoundError("Missing collection context.", err)
+	}
+
+	date, err := types.ParseDateTime(e.Request.URL.Query().Get("date"))
+	if err != nil || date.IsZero() {
+		return e.BadRequestError("Missing or invalid date query parameter.", err)
+	}
+
+	record, err := e.App.FindRecordAsOf(collection, e.Request.PathValue("id"), date.Time())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return e.NotFoundError("The record didn't exist at the specified date.", err)
+		}
+		return e.InternalServerError("Failed to load the record state.", err)
+	}
+
+	if err := EnrichRecord(e, record); err != nil {
+		return firstApiError(err, e.InternalServerError("Failed to enrich record", err))
+	}
+
+	return e.JSON(http.StatusOK, record)
+}
+
+func recordRevisionRestore(e *core.RequestEvent) error {
+	collection, err := e.App.FindCachedCollectionByNameOrId(e.Request.PathValue("collection"))
+	if err != nil || collection == nil || collection.IsView() {
+		return e.NotFoundError("Missing collection context.", err)
+	}
+
+	recordId := e.Request.PathValue("id")
+
+	revision, err := e.App.FindRecordRevisionById(e.Request.PathValue("revisionId"))
+	if err != nil || revision.CollectionRef() != collection.Id || revision.RecordRef() != recordId {
+		return e.NotFoundError("", err)
+	}
+
+	if revision.Action() == core.RecordRevisionActionDelete {
+		return e.BadRequestError("Delete revisions can't be restored. Restore one of the previous record revisions instead.", nil)
+	}
+
+	record, err := e.App.FindRecordById(collection, recordId)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return e.InternalServerError("Failed to load the record.", err)
+		}
+
+		// recreate the deleted record
+		record = core.NewRecord(collection)
+		record.Id = recordId
+	}
+
+	if err := revision.ApplyTo(record); err != nil {
+		return e.InternalServerError("Failed to apply the revision state.", err)
+	}
+
+	if err := e.App.SaveWithContext(recordChangeContext(e), record); err != nil {
+		return firstApiError(err, e.BadRequestError("Failed to restore the record revision.", err))
+	}
+
+	if err := EnrichRecord(e, record); err != nil {
+		return firstApiError(err, e.InternalServerError("Failed to enrich record", err))
+	}
+
+	return e.JSON(http.StatusOK, record)
+}
@@ -0,0 +1,92 @@
+package apis_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+// stubRecordSearch enables the demo2 full-text search for the "title" field.
+func stubRecordSearch(t testing.TB, app *tests.TestApp) {
+	collection, err := app.FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	collection.Search = &core.SearchConfig{Fields: []string{"title"}}
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRecordsListSearch(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "fts filter for collection without searchable fields",
+			Method:          http.MethodGet,
+			URL:             "/api/collections/demo2/records?filter=fts('test1')=true",
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "fts filter",
+			Method: http.MethodGet,
+			URL:    "/api/collections/demo2/records?filter=fts('test1')=true",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				stubRecordSearch(t, app)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"totalItems":1`,
+				`"id":"llvuca81nly1qls"`,
+			},
+			NotExpectedContent: []string{
+				`"@snippet"`,
+			},
+		},
+		{
+			Name:   "fts filter with prefix query and @rank sort",
+			Method: http.MethodGet,
+			URL:    "/api/collections/demo2/records?filter=fts('test*')=true&sort=@rank",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				stubRecordSearch(t, app)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"totalItems":3`,
+			},
+		},
+		{
+			Name:   "@rank sort without fts filter",
+			Method: http.MethodGet,
+			URL:    "/api/collections/demo2/records?sort=@rank",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				stubRecordSearch(t, app)
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "fts filter with snippets",
+			Method: http.MethodGet,
+			URL:    "/api/collections/demo2/records?filter=fts('test1')=true&fields=id,@snippet",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				stubRecordSearch(t, app)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"totalItems":1`,
+				`"items":[{"@snippet":{"title":"\u003cmark\u003etest1\u003c/mark\u003e"},"id":"llvuca81nly1qls"}]`,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
@@ -0,0 +1,122 @@
+package apis
+
+import (
+	"net/http"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+	"github.com/pocketbase/pocketbase/tools/search"
+)
+
+// bindRecordSoftDeleteApi registers the soft deleted records api endpoints.
+func bindRecordSoftDeleteApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	sub := rg.Group("/collections/{collection}/records/{id}")
+	sub.POST("/restore", recordRestore)
+}
+
+// recordRestore restores a single soft deleted record.
+//
+// The action is allowed for the superusers and for the users that
+// satisfy the collection delete rule.
+func recordRestore(e *core.RequestEvent) error {
+	collection, err := e.App.FindCachedCollectionByNameOrId(e.Request.PathValue("collection"))
+	if err != nil || collection == nil {
+		return e.NotFoundError("Missing collection context.", err)
+	}
+
+	if !collection.SoftDeleteEnabled() {
+		return e.BadRequestError("The collection doesn't have soft delete enabled.", nil)
+	}
+
+	requestInfo, err := e.RequestInfo()
+	if err != nil {
+		return firstApiError(err, e.BadRequestError("", err))
+	}
+
+	if !requestInfo.HasSuperuserAuth() && collection.DeleteRule == nil {
+		return e.ForbiddenError("Only superusers can perform this action.", nil)
+	}
+
+	ruleFunc := func(q *dbx.SelectQuery) error {
+		q.AndWhere(dbx.Not(dbx.HashExp{collection.Name + "." + core.FieldNameDeleted: ""}))
+
+		if expr := core.TenantScopeExpr(collection, requestInfo.Auth); expr != nil {
+			q.AndWhere(expr)
+		}
+
+		if !requestInfo.HasSuperuserAuth() && *collection.DeleteRule != "" {
+			resolver := core.NewRecordFieldResolver(e.App, collection, requestInfo, true)
+			expr, err := search.FilterData(*collection.DeleteRule).BuildExpr(resolver)
+			if err != nil {
+				return err
+			}
+			resolver.UpdateQuery(q)
+			q.AndWhere(expr)
+		}
+
+		return nil
+	}
+
+	record, err := e.App.FindRecordById(collection, e.Request.PathValue("id"), ruleFunc)
+	if err != nil {
+		return e.NotFoundError("", err)
+	}
+
+	record.Set(core.FieldNameDeleted, "")
+
+	if err := e.App.SaveWithContext(recordChangeContext(e), record); err != nil {
+		return firstApiError(err, e.BadRequestError("Failed to restore record.", err))
+	}
+
+	if err := EnrichRecord(e, record); err != nil {
+		return firstApiError(err, e.InternalServerError("Failed to enrich record", err))
+	}
+
+	return e.JSON(http.StatusOK, record)
+}
+
+// softDeleteExcludeExpr returns an expression that excludes the soft
+// deleted collection records.
+//
+// ruleResolver is the resolver used to build the applied access rule
+// (nil if no rule was applied, eg. for superusers).
+// Note that it must be called before resolving any client-side filters
+// with the same resolver.
+//
+// Returns nil if the collection doesn't have soft delete enabled or the
+// applied access rule explicitly references the "deleted" field, aka.
+// the soft deleted records are intentionally requested by the rule.
+func softDeleteExcludeExpr(collection *core.Collection, ruleResolver *core.RecordFieldResolver) dbx.Expression {
+	if !collection.SoftDeleteEnabled() {
+		return nil
+	}
+
+	if ruleResolver != nil && ruleResolver.SoftDeleteFieldResolved() {
+		return nil
+	}
+
+	return dbx.HashExp{collection.Name + "." + core.FieldNameDeleted: ""}
+}
+
+// canAccessSoftDeletedRecord reports whether the record is accessible
+// in regards to its soft delete state, aka. whether the record is not
+// soft deleted or the collection view rule explicitly references the
+// "deleted" field (ignored for superusers).
+func canAccessSoftDeletedRecord(app core.App, record *core.Record, requestInfo *core.RequestInfo) bool {
+	if !record.IsSoftDeleted() {
+		return true
+	}
+
+	rule := record.Collection().ViewRule
+	if requestInfo.HasSuperuserAuth() || rule == nil || *rule == "" {
+		return false
+	}
+
+	resolver := core.NewRecordFieldResolver(app, record.Collection(), requestInfo, true)
+	if _, err := search.FilterData(*rule).BuildExpr(resolver); err != nil {
+		return false
+	}
+
+	return softDeleteExcludeExpr(record.Collection(), resolver) == nil
+}
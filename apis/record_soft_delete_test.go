@@ -0,0 +1,316 @@
+package apis_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// stubSoftDelete enables the demo2 soft delete and
+// soft deletes the "llvuca81nly1qls" record.
+func stubSoftDelete(t testing.TB, app *tests.TestApp) {
+	collection, err := app.FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	collection.SoftDelete = &core.SoftDeleteConfig{Enabled: true}
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	record, err := app.FindRecordById(collection, "llvuca81nly1qls")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Delete(record); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRecordSoftDeleteCrud(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:   "list as guest",
+			Method: http.MethodGet,
+			URL:    "/api/collections/demo2/records",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				stubSoftDelete(t, app)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"totalItems":2`,
+				`"id":"achvryl401bhse3"`,
+				`"id":"0yxhwia2amd8gec"`,
+			},
+			NotExpectedContent: []string{
+				`"id":"llvuca81nly1qls"`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                    0,
+				"OnRecordsListRequest": 1,
+				"OnRecordEnrich":       2,
+			},
+		},
+		{
+			Name:   "list as guest + filter with the hidden deleted field",
+			Method: http.MethodGet,
+			URL:    "/api/collections/demo2/records?filter=deleted!=''",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				stubSoftDelete(t, app)
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "list as guest + list rule referencing the deleted field",
+			Method: http.MethodGet,
+			URL:    "/api/collections/demo2/records",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				stubSoftDelete(t, app)
+
+				collection, err := app.FindCollectionByNameOrId("demo2")
+				if err != nil {
+					t.Fatal(err)
+				}
+				collection.ListRule = types.Pointer("@request.query.trash = '1' && deleted != ''")
+				if err := app.Save(collection); err != nil {
+					t.Fatal(err)
+				}
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"totalItems":0`,
+			},
+		},
+		{
+			Name:   "list as guest + list rule referencing the deleted field (explicit trash query)",
+			Method: http.MethodGet,
+			URL:    "/api/collections/demo2/records?trash=1",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				stubSoftDelete(t, app)
+
+				collection, err := app.FindCollectionByNameOrId("demo2")
+				if err != nil {
+					t.Fatal(err)
+				}
+				collection.ListRule = types.Pointer("@request.query.trash = '1' && deleted != ''")
+				if err := app.Save(collection); err != nil {
+					t.Fatal(err)
+				}
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"totalItems":1`,
+				`"id":"llvuca81nly1qls"`,
+			},
+		},
+		{
+			Name:   "list as superuser + deleted filter (the client filter can't opt out)",
+			Method: http.MethodGet,
+			URL:    "/api/collections/demo2/records?filter=deleted!=''",
+			Headers: map[string]string{
+				"Authorization": revisionsTestSuperuserToken,
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				stubSoftDelete(t, app)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"totalItems":0`,
+			},
+		},
+		{
+			Name:   "list as guest + list rule referencing only another collection deleted field",
+			Method: http.MethodGet,
+			URL:    "/api/collections/demo2/records",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				stubSoftDelete(t, app)
+
+				collection, err := app.FindCollectionByNameOrId("demo2")
+				if err != nil {
+					t.Fatal(err)
+				}
+				collection.ListRule = types.Pointer("@collection.demo2.deleted ?!= ''")
+				if err := app.Save(collection); err != nil {
+					t.Fatal(err)
+				}
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"totalItems":2`,
+			},
+			NotExpectedContent: []string{
+				`"id":"llvuca81nly1qls"`,
+			},
+		},
+		{
+			Name:   "view soft deleted record as superuser",
+			Method: http.MethodGet,
+			URL:    "/api/collections/demo2/records/llvuca81nly1qls",
+			Headers: map[string]string{
+				"Authorization": revisionsTestSuperuserToken,
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				stubSoftDelete(t, app)
+			},
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "update soft deleted record as guest",
+			Method: http.MethodPatch,
+			URL:    "/api/collections/demo2/records/llvuca81nly1qls",
+			Body:   strings.NewReader(`{"title":"new"}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				stubSoftDelete(t, app)
+			},
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "delete soft deleted record as guest",
+			Method: http.MethodDelete,
+			URL:    "/api/collections/demo2/records/llvuca81nly1qls",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				stubSoftDelete(t, app)
+			},
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "delete (purge) soft deleted record as superuser",
+			Method: http.MethodDelete,
+			URL:    "/api/collections/demo2/records/llvuca81nly1qls",
+			Headers: map[string]string{
+				"Authorization": revisionsTestSuperuserToken,
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				stubSoftDelete(t, app)
+			},
+			ExpectedStatus: 204,
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				if _, err := app.FindRecordById("demo2", "llvuca81nly1qls"); err == nil {
+					t.Fatal("Expected the record to be purged")
+				}
+			},
+		},
+		{
+			Name:   "delete active record as guest",
+			Method: http.MethodDelete,
+			URL:    "/api/collections/demo2/records/achvryl401bhse3",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				stubSoftDelete(t, app)
+			},
+			ExpectedStatus: 204,
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				record, err := app.FindRecordById("demo2", "achvryl401bhse3")
+				if err != nil {
+					t.Fatalf("Expected the record to be soft deleted, got %v", err)
+				}
+				if !record.IsSoftDeleted() {
+					t.Fatal("Expected the record to be marked as soft deleted")
+				}
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestRecordRestore(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "collection without soft delete",
+			Method:          http.MethodPost,
+			URL:             "/api/collections/demo2/records/llvuca81nly1qls/restore",
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "missing delete rule access",
+			Method: http.MethodPost,
+			URL:    "/api/collections/demo2/records/llvuca81nly1qls/restore",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				stubSoftDelete(t, app)
+
+				collection, err := app.FindCollectionByNameOrId("demo2")
+				if err != nil {
+					t.Fatal(err)
+				}
+				collection.DeleteRule = nil
+				if err := app.Save(collection); err != nil {
+					t.Fatal(err)
+				}
+			},
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "non-deleted record",
+			Method: http.MethodPost,
+			URL:    "/api/collections/demo2/records/achvryl401bhse3/restore",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				stubSoftDelete(t, app)
+			},
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "soft deleted record as guest",
+			Method: http.MethodPost,
+			URL:    "/api/collections/demo2/records/llvuca81nly1qls/restore",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				stubSoftDelete(t, app)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"id":"llvuca81nly1qls"`,
+			},
+			NotExpectedContent: []string{
+				`"deleted"`,
+			},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				record, err := app.FindRecordById("demo2", "llvuca81nly1qls")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if record.IsSoftDeleted() {
+					t.Fatal("Expected the record to be restored")
+				}
+			},
+		},
+		{
+			Name:   "soft deleted record as superuser",
+			Method: http.MethodPost,
+			URL:    "/api/collections/demo2/records/llvuca81nly1qls/restore",
+			Headers: map[string]string{
+				"Authorization": revisionsTestSuperuserToken,
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				stubSoftDelete(t, app)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"id":"llvuca81nly1qls"`,
+				`"deleted":""`,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
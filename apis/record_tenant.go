@@ -0,0 +1,124 @@
+package apis
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+)
+
+// bindRecordTenantApi registers the tenant records api endpoints.
+func bindRecordTenantApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	sub := rg.Group("/collections/{collection}/records/{id}").Bind(RequireSuperuserAuth())
+	sub.GET("/tenant-export", recordTenantExport)
+}
+
+// recordTenantExport streams the tenant record together with all
+// of its tenant scoped records grouped by their collection name.
+//
+// To delete a tenant as a unit, delete its tenant record
+// (the scoped records are removed with the relations cascade).
+func recordTenantExport(e *core.RequestEvent) error {
+	collection, err := e.App.FindCachedCollectionByNameOrId(e.Request.PathValue("collection"))
+	if err != nil || collection == nil {
+		return e.NotFoundError("Missing collection context.", err)
+	}
+
+	tenant, err := e.App.FindRecordById(collection, e.Request.PathValue("id"))
+	if err != nil {
+		return e.NotFoundError("", err)
+	}
+
+	if err := EnrichRecord(e, tenant); err != nil {
+		return firstApiError(err, e.InternalServerError("Failed to enrich record", err))
+	}
+
+	rawTenant, err := json.Marshal(tenant)
+	if err != nil {
+		return e.InternalServerError("Failed to serialize the tenant record.", err)
+	}
+
+	// delay the headers until the first write so that the errors
+	// that occur before streaming are still returned as regular api errors
+	out := &recordExportResponseWriter{
+		ResponseWriter: e.Response,
+		headers: func(h http.Header) {
+			h.Set("Content-Type", "application/json")
+		},
+	}
+
+	// the records are written as they are loaded in order to avoid
+	// keeping the entire tenant data in memory
+	var currentCollection string
+	var hasItems bool
+	err = e.App.EachTenantRecords(tenant, func(c *core.Collection, records []*core.Record) error {
+		if err := EnrichRecords(e, records); err != nil {
+			return err
+		}
+
+		buf := &bytes.Buffer{}
+
+		if currentCollection == "" {
+			buf.WriteString(`{"tenant":`)
+			buf.Write(rawTenant)
+			buf.WriteString(`,"collections":{`)
+		}
+
+		if c.Name != currentCollection {
+			if currentCollection != "" {
+				buf.WriteString("],")
+			}
+			rawName, _ := json.Marshal(c.Name)
+			buf.Write(rawName)
+			buf.WriteString(":[")
+			currentCollection = c.Name
+			hasItems = false
+		}
+
+		for _, r := range records {
+			raw, err := json.Marshal(r)
+			if err != nil {
+				return err
+			}
+			if hasItems {
+				buf.WriteByte(',')
+			}
+			buf.Write(raw)
+			hasItems = true
+		}
+
+		_, err := out.Write(buf.Bytes())
+
+		return err
+	})
+	if err != nil {
+		if out.headers == nil {
+			return err // already streaming, can't change the response
+		}
+
+		return firstApiError(err, e.BadRequestError("Failed to load the tenant records.", err))
+	}
+
+	if currentCollection == "" {
+		_, err = out.Write([]byte(`{"tenant":` + string(rawTenant) + `,"collections":{}}`))
+	} else {
+		_, err = out.Write([]byte("]}}"))
+	}
+
+	return err
+}
+
+// canAccessTenantRecord reports whether the record is accessible by
+// the request auth record tenant (always true for superusers and
+// records that are not tenant scoped).
+func canAccessTenantRecord(record *core.Record, requestInfo *core.RequestInfo) bool {
+	if requestInfo.HasSuperuserAuth() || !record.Collection().TenantScoped() {
+		return true
+	}
+
+	tenantId := core.AuthTenantId(record.Collection(), requestInfo.Auth)
+
+	return tenantId != "" && tenantId == record.TenantId()
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/spf13/cobra"
+)
+
+// DefaultNewFieldEncryptionKeyEnv is the default name of the env variable
+// holding the new field encryption key used by the "encryption rotate" command.
+const DefaultNewFieldEncryptionKeyEnv = "PB_FIELD_ENCRYPTION_NEW_KEY"
+
+// NewEncryptionCommand creates and returns new command for managing
+// the record fields encryption at rest.
+func NewEncryptionCommand(app core.App) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "encryption",
+		Short: "Manage the record fields encryption",
+	}
+
+	command.AddCommand(encryptionRotateCommand(app))
+
+	return command
+}
+
+func encryptionRotateCommand(app core.App) *cobra.Command {
+	var newKeyEnv string
+
+	command := &cobra.Command{
+		Use:     "rotate",
+		Example: core.FieldEncryptionKeyEnv + "=old_key " + DefaultNewFieldEncryptionKeyEnv + "=new_key encryption rotate",
+		Short: fmt.Sprintf(
+			"Reencrypts all encrypted record field values from the %s key to the new key",
+			core.FieldEncryptionKeyEnv,
+		),
+		Long: fmt.Sprintf(
+			"Decrypts all encrypted record field values with the %s env key and encrypts them with the new env key.\n"+
+				"After the rotation the %s env variable must be updated with the new key.",
+			core.FieldEncryptionKeyEnv,
+			core.FieldEncryptionKeyEnv,
+		),
+		SilenceUsage: true,
+		RunE: func(command *cobra.Command, args []string) error {
+			if newKeyEnv == "" {
+				return errors.New("missing new key env variable name")
+			}
+
+			oldKey := os.Getenv(core.FieldEncryptionKeyEnv)
+			newKey := os.Getenv(newKeyEnv)
+
+			if err := core.ReencryptRecordFields(app, oldKey, newKey); err != nil {
+				return fmt.Errorf("failed to rotate the field encryption key: %w", err)
+			}
+
+			color.Green("Successfully reencrypted the record field values!")
+			color.Yellow("Don't forget to replace the %s env variable value with the new key.", core.FieldEncryptionKeyEnv)
+
+			return nil
+		},
+	}
+
+	command.Flags().StringVar(
+		&newKeyEnv,
+		"newKeyEnv",
+		DefaultNewFieldEncryptionKeyEnv,
+		"the env variable whose value of 32 characters will be used as the new field encryption key",
+	)
+
+	return command
+}
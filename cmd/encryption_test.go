@@ -0,0 +1,122 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/cmd"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestEncryptionRotateCommand(t *testing.T) {
+	const (
+		oldKey = "abcdabcdabcdabcdabcdabcdabcdabcd"
+		newKey = "0123456789012345678901234567890a"
+	)
+
+	t.Setenv(core.FieldEncryptionKeyEnv, oldKey)
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test_encrypted")
+	collection.Fields.Add(&core.TextField{Name: "secret", Encrypted: true})
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	record := core.NewRecord(collection)
+	record.Set("secret", "test")
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	findRaw := func() string {
+		var raw string
+		err := app.DB().Select("secret").From(collection.Name).Where(dbx.HashExp{"id": record.Id}).Row(&raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return raw
+	}
+
+	rawBefore := findRaw()
+
+	scenarios := []struct {
+		name        string
+		args        []string
+		newKey      string
+		expectError bool
+	}{
+		{
+			"missing new key env name",
+			[]string{"rotate", "--newKeyEnv", ""},
+			newKey,
+			true,
+		},
+		{
+			"missing new key",
+			[]string{"rotate"},
+			"",
+			true,
+		},
+		{
+			"invalid new key",
+			[]string{"rotate"},
+			"abc",
+			true,
+		},
+		{
+			"valid new key with custom env name",
+			[]string{"rotate", "--newKeyEnv", "TEST_CUSTOM_NEW_KEY"},
+			newKey,
+			false,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			t.Setenv(cmd.DefaultNewFieldEncryptionKeyEnv, s.newKey)
+			t.Setenv("TEST_CUSTOM_NEW_KEY", s.newKey)
+
+			command := cmd.NewEncryptionCommand(app)
+			command.SetArgs(s.args)
+
+			err := command.Execute()
+
+			hasErr := err != nil
+			if s.expectError != hasErr {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			rawAfter := findRaw()
+			if !strings.HasPrefix(rawAfter, "pbenc:") {
+				t.Fatalf("Expected encrypted value, got %q", rawAfter)
+			}
+
+			if hasErr {
+				if rawAfter != rawBefore {
+					t.Fatalf("Expected the stored value to remain unchanged, got %q", rawAfter)
+				}
+				return
+			}
+
+			if rawAfter == rawBefore {
+				t.Fatal("Expected the stored value to be reencrypted")
+			}
+
+			t.Setenv(core.FieldEncryptionKeyEnv, s.newKey)
+
+			updated, err := app.FindRecordById(collection, record.Id)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if v := updated.GetString("secret"); v != "test" {
+				t.Fatalf("Expected the decrypted value to be %q, got %q", "test", v)
+			}
+		})
+	}
+}
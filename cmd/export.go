@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/search"
+	"github.com/spf13/cobra"
+)
+
+// NewExportCommand creates and returns new command for exporting
+// collection records to a CSV, NDJSON or XLSX file.
+func NewExportCommand(app core.App) *cobra.Command {
+	var format string
+	var filter string
+	var sort string
+	var fields string
+	var expand []string
+
+	command := &cobra.Command{
+		Use:     "export",
+		Example: "export posts ./posts.csv --filter=\"published=true\" --expand=author",
+		Short:   "Exports collection records to a CSV, NDJSON or XLSX file",
+		Long: "Exports collection records to a CSV, NDJSON or XLSX file.\n" +
+			"The expanded relations are flattened as \"relation.field\" columns for the CSV and XLSX formats.",
+		SilenceUsage: true,
+		RunE: func(command *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return errors.New("missing collection and file path arguments")
+			}
+
+			collection, err := app.FindCollectionByNameOrId(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to fetch %q collection: %w", args[0], err)
+			}
+
+			if format == "" {
+				format = exportFormatFromExt(args[1])
+			}
+
+			provider := search.NewProvider(core.NewRecordFieldResolver(app, collection, nil, true)).
+				Query(app.RecordQuery(collection))
+
+			if filter != "" {
+				provider.AddFilter(search.FilterData(filter))
+			}
+
+			if sort != "" {
+				for _, sortField := range search.ParseSortFromString(sort) {
+					provider.AddSort(sortField)
+				}
+			}
+
+			f, err := os.Create(args[1])
+			if err != nil {
+				return err
+			}
+
+			total, err := core.ExportRecords(context.Background(), app, collection, provider, f, core.RecordExportOptions{
+				Format:            format,
+				Fields:            fields,
+				Expand:            expand,
+				AllowHiddenFields: true,
+			})
+			if err != nil {
+				return errors.Join(fmt.Errorf("failed to export records: %w", err), f.Close(), os.Remove(args[1]))
+			}
+
+			if err := f.Close(); err != nil {
+				return err
+			}
+
+			color.Green("Successfully exported %d records to %q.", total, args[1])
+
+			return nil
+		},
+	}
+
+	command.Flags().StringVar(
+		&format,
+		"format",
+		"",
+		"the file format - csv, ndjson or xlsx (default to the file extension)",
+	)
+
+	command.Flags().StringVar(
+		&filter,
+		"filter",
+		"",
+		"optional records filter expression (eg. --filter=\"created>'2024-01-01'\")",
+	)
+
+	command.Flags().StringVar(
+		&sort,
+		"sort",
+		"",
+		"optional records sort expression (eg. --sort=-created)",
+	)
+
+	command.Flags().StringVar(
+		&fields,
+		"fields",
+		"",
+		"optional comma separated fields to export (eg. --fields=id,title,expand.author.name)",
+	)
+
+	command.Flags().StringSliceVar(
+		&expand,
+		"expand",
+		nil,
+		"relations to expand (eg. --expand=author,tags)",
+	)
+
+	return command
+}
+
+func exportFormatFromExt(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return core.RecordExportFormatCSV
+	case ".ndjson", ".jsonl":
+		return core.RecordExportFormatNDJSON
+	case ".xlsx":
+		return core.RecordExportFormatXLSX
+	}
+
+	return ""
+}
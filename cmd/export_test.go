@@ -0,0 +1,123 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/cmd"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestExportCommand(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test_export")
+	collection.Fields.Add(
+		&core.TextField{Name: "title"},
+		&core.TextField{Name: "secret", Hidden: true},
+	)
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, title := range []string{"a", "b", "c"} {
+		record := core.NewRecord(collection)
+		record.Set("title", title)
+		record.Set("secret", title+"_secret")
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dir := t.TempDir()
+
+	scenarios := []struct {
+		name            string
+		args            []string
+		file            string
+		expectError     bool
+		expectedContent []string
+	}{
+		{
+			"missing arguments",
+			[]string{"test_export"},
+			"",
+			true,
+			nil,
+		},
+		{
+			"missing collection",
+			[]string{"missing", filepath.Join(dir, "missing.csv")},
+			"missing.csv",
+			true,
+			nil,
+		},
+		{
+			"unknown file format",
+			[]string{"test_export", filepath.Join(dir, "data.txt")},
+			"data.txt",
+			true,
+			nil,
+		},
+		{
+			"csv with filter, sort and fields",
+			[]string{"test_export", filepath.Join(dir, "data.csv"), "--filter", "title!='b'", "--sort", "-title", "--fields", "title,secret"},
+			"data.csv",
+			false,
+			[]string{"title,secret\nc,c_secret\na,a_secret\n"},
+		},
+		{
+			"ndjson",
+			[]string{"test_export", filepath.Join(dir, "data.ndjson"), "--fields", "title", "--sort", "title"},
+			"data.ndjson",
+			false,
+			[]string{`{"title":"a"}` + "\n" + `{"title":"b"}` + "\n" + `{"title":"c"}` + "\n"},
+		},
+		{
+			"explicit xlsx format",
+			[]string{"test_export", filepath.Join(dir, "data.bin"), "--format", "xlsx"},
+			"data.bin",
+			false,
+			[]string{"PK"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			command := cmd.NewExportCommand(app)
+			command.SetArgs(s.args)
+
+			err := command.Execute()
+
+			hasErr := err != nil
+			if s.expectError != hasErr {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if s.file == "" {
+				return
+			}
+
+			content, err := os.ReadFile(filepath.Join(dir, s.file))
+			if s.expectError {
+				if err == nil {
+					t.Fatalf("Expected the %q file to not exist", s.file)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, expected := range s.expectedContent {
+				if !strings.Contains(string(content), expected) {
+					t.Fatalf("Expected %q in\n%s", expected, content)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/spf13/cobra"
+)
+
+// NewImportCommand creates and returns new command for importing
+// collection records from a CSV or NDJSON file.
+func NewImportCommand(app core.App) *cobra.Command {
+	var format string
+	var mapping map[string]string
+	var upsert string
+	var chunkSize int
+	var reportPath string
+
+	command := &cobra.Command{
+		Use:     "import",
+		Example: "import posts ./posts.csv --map Title=title --upsert=slug",
+		Short:   "Imports collection records from a CSV or NDJSON file",
+		Long: "Imports collection records from a CSV (with header row) or NDJSON file.\n" +
+			"Each row is validated and saved as a collection record and the failed rows are reported without stopping the import.",
+		SilenceUsage: true,
+		RunE: func(command *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return errors.New("missing collection and file path arguments")
+			}
+
+			collection, err := app.FindCollectionByNameOrId(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to fetch %q collection: %w", args[0], err)
+			}
+
+			if format == "" {
+				format = importFormatFromExt(args[1])
+			}
+
+			f, err := os.Open(args[1])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			report, err := core.ImportRecords(context.Background(), app, collection, f, core.RecordImportOptions{
+				Format:      format,
+				Mapping:     mapping,
+				UpsertField: upsert,
+				ChunkSize:   chunkSize,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to import records: %w", err)
+			}
+
+			if reportPath != "" {
+				if err := writeImportReport(report, reportPath); err != nil {
+					return fmt.Errorf("failed to write the import report: %w", err)
+				}
+			}
+
+			color.Green(
+				"Processed %d rows (created %d, updated %d, failed %d).",
+				report.TotalRows,
+				report.CreatedRows,
+				report.UpdatedRows,
+				report.FailedRows,
+			)
+
+			if report.FailedRows > 0 {
+				if reportPath != "" {
+					color.Yellow("The failed rows errors were written in %q.", reportPath)
+				} else {
+					color.Yellow("Use the --report flag to write the failed rows errors in a CSV file.")
+				}
+			}
+
+			return nil
+		},
+	}
+
+	command.Flags().StringVar(
+		&format,
+		"format",
+		"",
+		"the file format - csv or ndjson (default to the file extension)",
+	)
+
+	command.Flags().StringToStringVar(
+		&mapping,
+		"map",
+		nil,
+		"source column to collection field mapping (eg. --map Email=email,Name=name)",
+	)
+
+	command.Flags().StringVar(
+		&upsert,
+		"upsert",
+		"",
+		"unique field name used to update the existing records instead of creating new ones",
+	)
+
+	command.Flags().IntVar(
+		&chunkSize,
+		"chunkSize",
+		core.DefaultRecordImportChunkSize,
+		"the number of rows imported in a single transaction",
+	)
+
+	command.Flags().StringVar(
+		&reportPath,
+		"report",
+		"",
+		"optional file path where to write the failed rows errors as CSV",
+	)
+
+	return command
+}
+
+func importFormatFromExt(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return core.RecordImportFormatCSV
+	case ".ndjson", ".jsonl":
+		return core.RecordImportFormatNDJSON
+	}
+
+	return ""
+}
+
+func writeImportReport(report *core.RecordImport, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := report.WriteErrorsCSV(f); err != nil {
+		return err
+	}
+
+	return f.Close()
+}
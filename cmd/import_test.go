@@ -0,0 +1,133 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/cmd"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestImportCommand(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test_import")
+	collection.Fields.Add(
+		&core.TextField{Name: "title", Required: true},
+		&core.TextField{Name: "slug"},
+	)
+	collection.AddIndex("idx_test_import_slug", true, "slug", "slug != ''")
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+
+	writeFile := func(name string, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	csvPath := writeFile("data.csv", "Name,slug\na,a\n,b\nc,a\n")
+	ndjsonPath := writeFile("data.ndjson", `{"title":"d","slug":"d"}`+"\n")
+	unknownPath := writeFile("data.txt", "title\ne\n")
+	reportPath := filepath.Join(dir, "report.csv")
+
+	scenarios := []struct {
+		name          string
+		args          []string
+		expectError   bool
+		expectedSlugs map[string]string
+	}{
+		{
+			"missing arguments",
+			[]string{"test_import"},
+			true,
+			map[string]string{},
+		},
+		{
+			"missing collection",
+			[]string{"missing", csvPath},
+			true,
+			map[string]string{},
+		},
+		{
+			"missing file",
+			[]string{"test_import", filepath.Join(dir, "missing.csv")},
+			true,
+			map[string]string{},
+		},
+		{
+			"unknown file format",
+			[]string{"test_import", unknownPath},
+			true,
+			map[string]string{},
+		},
+		{
+			"csv with mapping, upsert and report",
+			[]string{"test_import", csvPath, "--map", "Name=title,slug=slug", "--upsert", "slug", "--report", reportPath},
+			false,
+			map[string]string{"a": "c"},
+		},
+		{
+			"ndjson",
+			[]string{"test_import", ndjsonPath},
+			false,
+			map[string]string{"a": "c", "d": "d"},
+		},
+		{
+			"explicit format",
+			[]string{"test_import", unknownPath, "--format", "csv"},
+			false,
+			map[string]string{"a": "c", "d": "d", "": "e"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			command := cmd.NewImportCommand(app)
+			command.SetArgs(s.args)
+
+			err := command.Execute()
+
+			hasErr := err != nil
+			if s.expectError != hasErr {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			records, err := app.FindAllRecords(collection)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			slugs := map[string]string{}
+			for _, r := range records {
+				slugs[r.GetString("slug")] = r.GetString("title")
+			}
+
+			if len(slugs) != len(s.expectedSlugs) {
+				t.Fatalf("Expected records %v, got %v", s.expectedSlugs, slugs)
+			}
+			for slug, title := range s.expectedSlugs {
+				if slugs[slug] != title {
+					t.Fatalf("Expected record %q title %q, got %q", slug, title, slugs[slug])
+				}
+			}
+		})
+	}
+
+	report, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(report), "2,title,validation_required,") {
+		t.Fatalf("Expected the failed row in the report, got\n%s", report)
+	}
+}
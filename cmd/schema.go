@@ -0,0 +1,274 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/yaml"
+	"github.com/spf13/cobra"
+)
+
+// NewSchemaCommand creates and returns new command for planning and applying
+// declarative collections schema files (plan, apply, check, dump).
+func NewSchemaCommand(app core.App) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "schema",
+		Short: "Manage the collections schema declaratively",
+		Long: "Compares, applies and checks a collections schema file (JSON or YAML) against the database.\n" +
+			"The schema file has the same format as the Dashboard collections export.",
+	}
+
+	command.AddCommand(schemaPlanCommand(app))
+	command.AddCommand(schemaApplyCommand(app))
+	command.AddCommand(schemaCheckCommand(app))
+	command.AddCommand(schemaDumpCommand(app))
+
+	return command
+}
+
+func schemaPlanCommand(app core.App) *cobra.Command {
+	var deleteMissing bool
+
+	command := &cobra.Command{
+		Use:          "plan",
+		Example:      "schema plan ./pb_schema.yaml",
+		Short:        "Prints the changes that applying the schema file would execute",
+		SilenceUsage: true,
+		RunE: func(command *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("missing schema file path argument")
+			}
+
+			data, err := readSchemaFile(args[0])
+			if err != nil {
+				return err
+			}
+
+			plan, err := core.PlanSchema(app, data, deleteMissing)
+			if err != nil {
+				return fmt.Errorf("failed to plan the schema changes: %w", err)
+			}
+
+			printSchemaPlan(plan)
+
+			return nil
+		},
+	}
+
+	command.Flags().BoolVar(
+		&deleteMissing,
+		"deleteMissing",
+		false,
+		"delete the non-system collections and fields that are not in the schema file",
+	)
+
+	return command
+}
+
+func schemaApplyCommand(app core.App) *cobra.Command {
+	var deleteMissing bool
+	var allowDestructive bool
+
+	command := &cobra.Command{
+		Use:          "apply",
+		Example:      "schema apply ./pb_schema.yaml --deleteMissing",
+		Short:        "Applies the schema file changes in a single transaction",
+		SilenceUsage: true,
+		RunE: func(command *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("missing schema file path argument")
+			}
+
+			data, err := readSchemaFile(args[0])
+			if err != nil {
+				return err
+			}
+
+			plan, err := core.ApplySchema(app, data, core.SchemaApplyOptions{
+				DeleteMissing:    deleteMissing,
+				AllowDestructive: allowDestructive,
+			})
+			if errors.Is(err, core.ErrDestructiveSchemaChanges) {
+				printSchemaPlan(plan)
+				return errors.New("the schema plan contains destructive changes, use the --allowDestructive flag to apply it anyway")
+			}
+			if err != nil {
+				return fmt.Errorf("failed to apply the schema changes: %w", err)
+			}
+
+			printSchemaPlan(plan)
+
+			if plan.HasChanges() {
+				color.Green("Successfully applied %d schema change(s)!", len(plan.Changes))
+			}
+
+			return nil
+		},
+	}
+
+	command.Flags().BoolVar(
+		&deleteMissing,
+		"deleteMissing",
+		false,
+		"delete the non-system collections and fields that are not in the schema file",
+	)
+
+	command.Flags().BoolVar(
+		&allowDestructive,
+		"allowDestructive",
+		false,
+		"allow applying changes that could result in data loss (eg. column drops)",
+	)
+
+	return command
+}
+
+func schemaCheckCommand(app core.App) *cobra.Command {
+	command := &cobra.Command{
+		Use:          "check",
+		Example:      "schema check ./pb_schema.yaml",
+		Short:        "Fails if the database schema differs from the schema file",
+		Long:         "Fails if the database schema differs from the schema file (eg. to detect drift in CI).\nThe schema file is expected to contain all non-system collections.",
+		SilenceUsage: true,
+		RunE: func(command *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("missing schema file path argument")
+			}
+
+			data, err := readSchemaFile(args[0])
+			if err != nil {
+				return err
+			}
+
+			plan, err := core.PlanSchema(app, data, true)
+			if err != nil {
+				return fmt.Errorf("failed to plan the schema changes: %w", err)
+			}
+
+			if plan.HasChanges() {
+				printSchemaPlan(plan)
+				return fmt.Errorf("the database schema differs from %q", args[0])
+			}
+
+			color.Green("The database schema matches %q.", args[0])
+
+			return nil
+		},
+	}
+
+	return command
+}
+
+func schemaDumpCommand(app core.App) *cobra.Command {
+	command := &cobra.Command{
+		Use:          "dump",
+		Example:      "schema dump ./pb_schema.json",
+		Short:        "Writes the current collections schema as JSON file",
+		SilenceUsage: true,
+		RunE: func(command *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("missing schema file path argument")
+			}
+
+			if isYAMLSchemaFile(args[0]) {
+				return errors.New("only JSON schema files could be dumped")
+			}
+
+			collections, err := app.FindAllCollections()
+			if err != nil {
+				return fmt.Errorf("failed to fetch the collections: %w", err)
+			}
+
+			raw, err := json.MarshalIndent(collections, "", "  ")
+			if err != nil {
+				return err
+			}
+
+			if err := os.WriteFile(args[0], append(raw, '\n'), 0644); err != nil {
+				return err
+			}
+
+			color.Green("Successfully written %d collection(s) in %q.", len(collections), args[0])
+
+			return nil
+		},
+	}
+
+	return command
+}
+
+func isYAMLSchemaFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// readSchemaFile loads the collections data from a JSON or YAML schema file.
+//
+// The file could be either an array of collections or
+// an object with "collections" array key.
+func readSchemaFile(path string) ([]map[string]any, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded any
+	if isYAMLSchemaFile(path) {
+		decoded, err = yaml.Decode(raw)
+	} else {
+		err = json.Unmarshal(raw, &decoded)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse schema file %q: %w", path, err)
+	}
+
+	if m, ok := decoded.(map[string]any); ok {
+		decoded = m["collections"]
+	}
+
+	if _, ok := decoded.([]any); !ok {
+		return nil, fmt.Errorf("schema file %q must contain an array of collections", path)
+	}
+
+	// normalize
+	normalized, err := json.Marshal(decoded)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []map[string]any{}
+	if err := json.Unmarshal(normalized, &result); err != nil {
+		return nil, fmt.Errorf("schema file %q must contain an array of collections: %w", path, err)
+	}
+
+	return result, nil
+}
+
+func printSchemaPlan(plan *core.SchemaPlan) {
+	if !plan.HasChanges() {
+		color.Green("No changes. The database schema is up to date.")
+		return
+	}
+
+	for _, c := range plan.Changes {
+		switch {
+		case c.Destructive:
+			color.Red("%s", c.String())
+		case c.Action == core.SchemaChangeCreate:
+			color.Green("%s", c.String())
+		case c.Action == core.SchemaChangeDelete:
+			color.Red("%s", c.String())
+		default:
+			color.Yellow("%s", c.String())
+		}
+	}
+
+	fmt.Printf("\nPlan: %d change(s), %d destructive.\n", len(plan.Changes), len(plan.DestructiveChanges()))
+}
@@ -0,0 +1,91 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/cmd"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestSchemaCommand(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	dir := t.TempDir()
+
+	dumpPath := filepath.Join(dir, "schema.json")
+
+	yamlPath := filepath.Join(dir, "schema.yaml")
+	err := os.WriteFile(yamlPath, []byte(`
+collections:
+  - name: schema_test
+    type: base
+    listRule: ""
+    fields:
+      - name: title
+        type: text
+        required: true
+    indexes:
+      - CREATE INDEX idx_schema_test_title ON schema_test (title)
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	invalidPath := filepath.Join(dir, "invalid.yaml")
+	if err := os.WriteFile(invalidPath, []byte("name: test"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(args ...string) error {
+		command := cmd.NewSchemaCommand(app)
+		command.SetArgs(args)
+		return command.Execute()
+	}
+
+	scenarios := []struct {
+		name        string
+		args        []string
+		expectError bool
+	}{
+		{"plan with missing file argument", []string{"plan"}, true},
+		{"plan with missing file", []string{"plan", filepath.Join(dir, "missing.json")}, true},
+		{"plan with invalid file", []string{"plan", invalidPath}, true},
+		{"dump in yaml file", []string{"dump", filepath.Join(dir, "dump.yaml")}, true},
+		{"dump", []string{"dump", dumpPath}, false},
+		{"check without drift", []string{"check", dumpPath}, false},
+		{"plan yaml", []string{"plan", yamlPath}, false},
+		{"apply yaml", []string{"apply", yamlPath}, false},
+		{"check with drift", []string{"check", dumpPath}, true},
+		{"apply destructive without flag", []string{"apply", dumpPath, "--deleteMissing"}, true},
+		{"apply destructive with flag", []string{"apply", dumpPath, "--deleteMissing", "--allowDestructive"}, false},
+		{"check after apply", []string{"check", dumpPath}, false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			err := run(s.args...)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+		})
+
+		if s.name == "apply yaml" {
+			collection, err := app.FindCollectionByNameOrId("schema_test")
+			if err != nil {
+				t.Fatalf("Expected the schema_test collection to be created: %v", err)
+			}
+			if collection.ListRule == nil || *collection.ListRule != "" || collection.Fields.GetByName("title") == nil || len(collection.Indexes) != 1 {
+				t.Fatalf("Unexpected schema_test collection %v", collection)
+			}
+		}
+	}
+
+	if _, err := app.FindCollectionByNameOrId("schema_test"); err == nil {
+		t.Fatal("Expected the schema_test collection to be deleted")
+	}
+}
@@ -73,6 +73,12 @@ type App interface {
 	// When enabled logs, executed sql statements, etc. are printed to the stderr.
 	IsDev() bool
 
+	// Environment returns the app environment name (eg. "development", "staging", "production").
+	//
+	// It is used to distinguish between the different deployments
+	// of the same database (eg. a production db cloned in a dev machine).
+	Environment() string
+
 	// Settings returns the loaded app settings.
 	Settings() *Settings
 
@@ -267,6 +273,14 @@ type App interface {
 	// FindRecordByViewFile returns the original Record of the provided view collection file.
 	FindRecordByViewFile(viewCollectionModelOrIdentifier any, fileFieldName string, filename string) (*Record, error)
 
+	// RefreshMaterializedView recomputes the view query of the provided
+	// materialized view collection and syncs its table in a single transaction.
+	RefreshMaterializedView(collection *Collection) error
+
+	// FindMaterializedViewInfo returns the last refresh state of the provided
+	// materialized view collection.
+	FindMaterializedViewInfo(collection *Collection) (*MaterializedViewInfo, error)
+
 	// Vacuum executes VACUUM on the data.db in order to reclaim unused data db disk space.
 	Vacuum() error
 
@@ -511,6 +525,81 @@ type App interface {
 
 	// ---------------------------------------------------------------
 
+	// FindAllRecordRevisions returns all revisions of a single collection record
+	// (including the revisions of an already deleted record) ordered from the newest to the oldest.
+	FindAllRecordRevisions(collectionModelOrIdentifier any, recordId string) ([]*RecordRevision, error)
+
+	// FindRecordRevisionById returns a single RecordRevision model by its id.
+	FindRecordRevisionById(id string) (*RecordRevision, error)
+
+	// FindRecordRevisionAsOf returns the latest revision of a single
+	// collection record that was created before or at the specified date.
+	//
+	// Returns [sql.ErrNoRows] if there is no such revision.
+	FindRecordRevisionAsOf(collectionModelOrIdentifier any, recordId string, date time.Time) (*RecordRevision, error)
+
+	// FindRecordAsOf returns the state of a single collection record
+	// as it was at the specified date (based on the record revisions).
+	//
+	// Returns [sql.ErrNoRows] if the record didn't exist at the specified date
+	// or there is no revision history for it.
+	FindRecordAsOf(collectionModelOrIdentifier any, recordId string, date time.Time) (*Record, error)
+
+	// DeleteExpiredRecordRevisions deletes the expired record revisions
+	// for all collections with history max days limit.
+	//
+	// The record files that are no longer referenced by the record itself or
+	// by any of its remaining revisions are also deleted.
+	DeleteExpiredRecordRevisions() error
+
+	// PurgeSoftDeletedRecords permanently deletes the soft deleted records
+	// that are older than their collection soft delete max days limit.
+	PurgeSoftDeletedRecords() error
+
+	// DeleteExpiredRecords deletes the expired records
+	// of all collections with enabled expiry.
+	DeleteExpiredRecords() error
+
+	// EachTenantRecords loads the records of the specified tenant
+	// in batches and calls fn for each batch.
+	//
+	// All batches of a tenant scoped collection are passed consecutively.
+	EachTenantRecords(tenant *Record, fn func(collection *Collection, records []*Record) error) error
+
+	// ---------------------------------------------------------------
+
+	// FindRecordChanges returns the record changes logged after the
+	// specified cursor ordered from the oldest to the newest.
+	//
+	// collectionIds could be used to limit the changes to specific collections
+	// (an empty slice means all collections).
+	//
+	// If limit is <= 0 no limit is applied.
+	FindRecordChanges(afterCursor int64, collectionIds []string, limit int) ([]*RecordChange, error)
+
+	// FindLatestRecordChangeCursor returns the cursor of the last logged
+	// record change (or 0 if no changes were logged).
+	FindLatestRecordChangeCursor() (int64, error)
+
+	// FindRecordChangesPrunedCursor returns the highest change log cursor
+	// that was deleted because of the retention limit of the specified
+	// collections (an empty slice means all collections).
+	FindRecordChangesPrunedCursor(collectionIds []string) (int64, error)
+
+	// DeleteExpiredRecordChanges deletes the record changes that are older
+	// than their collection change log max days limit.
+	DeleteExpiredRecordChanges() error
+
+	// FindRecordChangeCheckpoint returns the last stored change log cursor
+	// of the specified auth record consumer (or 0 if there is no checkpoint).
+	FindRecordChangeCheckpoint(authRecord *Record, consumer string) (int64, error)
+
+	// SaveRecordChangeCheckpoint stores (or replaces) the change log
+	// cursor of the specified auth record consumer.
+	SaveRecordChangeCheckpoint(authRecord *Record, consumer string, cursor int64) error
+
+	// ---------------------------------------------------------------
+
 	// FindAllOTPsByRecord returns all OTP models linked to the provided auth record.
 	FindAllOTPsByRecord(authRecord *Record) ([]*OTP, error)
 
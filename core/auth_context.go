@@ -0,0 +1,26 @@
+package core
+
+import "context"
+
+type authContextKey struct{}
+
+// ContextWithAuth returns a copy of ctx with the specified auth record
+// (usually the auth record of the request that initiated the model change).
+func ContextWithAuth(ctx context.Context, auth *Record) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return context.WithValue(ctx, authContextKey{}, auth)
+}
+
+// AuthFromContext returns the auth record stored in ctx (if any).
+func AuthFromContext(ctx context.Context) *Record {
+	if ctx == nil {
+		return nil
+	}
+
+	auth, _ := ctx.Value(authContextKey{}).(*Record)
+
+	return auth
+}
@@ -41,6 +41,9 @@ const (
 	LocalBackupsDirName       string = "backups"
 	LocalTempDirName          string = ".pb_temp_to_delete" // temp pb_data sub directory that will be deleted on each app.Bootstrap()
 	LocalAutocertCacheDirName string = ".autocert_cache"
+
+	EnvironmentDevelopment string = "development"
+	EnvironmentProduction  string = "production"
 )
 
 // FilesManager defines an interface with common methods that files manager models should implement.
@@ -63,6 +66,12 @@ type BaseAppConfig struct {
 	AuxMaxOpenConns  int
 	AuxMaxIdleConns  int
 	IsDev            bool
+
+	// Environment is the optional app environment name (eg. "staging").
+	//
+	// If not set it fallbacks to [EnvironmentDevelopment] or
+	// [EnvironmentProduction] depending on the IsDev flag.
+	Environment string
 }
 
 // ensures that the BaseApp implements the App interface.
@@ -620,6 +629,19 @@ func (app *BaseApp) IsDev() bool {
 	return app.config.IsDev
 }
 
+// Environment returns the app environment name (eg. "development", "staging", "production").
+func (app *BaseApp) Environment() string {
+	if app.config.Environment != "" {
+		return app.config.Environment
+	}
+
+	if app.config.IsDev {
+		return EnvironmentDevelopment
+	}
+
+	return EnvironmentProduction
+}
+
 // Settings returns the loaded app settings.
 func (app *BaseApp) Settings() *Settings {
 	return app.settings
@@ -1338,7 +1360,10 @@ func (app *BaseApp) registerBaseHooks() {
 	app.OnModelAfterDeleteSuccess().Bind(&hook.Handler[*ModelEvent]{
 		Id: "__pbFilesManagerDelete__",
 		Func: func(e *ModelEvent) error {
-			if m, ok := e.Model.(FilesManager); ok && m.BaseFilesPath() != "" && supportFiles(e.Model) {
+			// note: the files of the records with enabled history are kept
+			// until their last revision expires (see DeleteExpiredRecordRevisions)
+			// and the files of the soft deleted records are kept until purged
+			if m, ok := e.Model.(FilesManager); ok && m.BaseFilesPath() != "" && supportFiles(e.Model) && !isHistoryRecord(e.Model) && !isSoftDeletedRecord(e.Model) {
 				// ensure that there is a trailing slash so that the list iterator could start walking from the prefix dir
 				// (https://github.com/pocketbase/pocketbase/discussions/5246#discussioncomment-10128955)
 				prefix := strings.TrimRight(m.BaseFilesPath(), "/") + "/"
@@ -1408,6 +1433,15 @@ func (app *BaseApp) registerBaseHooks() {
 	app.registerMFAHooks()
 	app.registerOTPHooks()
 	app.registerAuthOriginHooks()
+	app.registerRecordRevisionHooks()
+	app.registerRecordETagHooks()
+	app.registerRecordChangeHooks()
+	app.registerMaterializedViewHooks()
+	app.registerRecordImportHooks()
+	app.registerRecordSoftDeleteHooks()
+	app.registerRecordExpiryHooks()
+	app.registerRecordSearchHooks()
+	app.registerRecordVectorIndexHooks()
 	app.registerLambdaFunctionHooks()
 }
 
@@ -40,6 +40,10 @@ func TestNewBaseApp(t *testing.T) {
 		t.Fatalf("expected IsDev true, got %v", app.IsDev())
 	}
 
+	if app.Environment() != core.EnvironmentDevelopment {
+		t.Fatalf("expected Environment %q, got %q", core.EnvironmentDevelopment, app.Environment())
+	}
+
 	if app.Store() == nil {
 		t.Fatal("expected Store to be set, got nil")
 	}
@@ -57,6 +61,28 @@ func TestNewBaseApp(t *testing.T) {
 	}
 }
 
+func TestBaseAppEnvironment(t *testing.T) {
+	scenarios := []struct {
+		name        string
+		config      core.BaseAppConfig
+		expectedEnv string
+	}{
+		{"default", core.BaseAppConfig{}, core.EnvironmentProduction},
+		{"dev mode", core.BaseAppConfig{IsDev: true}, core.EnvironmentDevelopment},
+		{"explicit", core.BaseAppConfig{IsDev: true, Environment: "staging"}, "staging"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			app := core.NewBaseApp(s.config)
+
+			if env := app.Environment(); env != s.expectedEnv {
+				t.Fatalf("Expected environment %q, got %q", s.expectedEnv, env)
+			}
+		})
+	}
+}
+
 func TestBaseAppBootstrap(t *testing.T) {
 	const testDataDir = "./pb_base_app_test_data_dir/"
 	defer os.RemoveAll(testDataDir)
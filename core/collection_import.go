@@ -39,8 +39,83 @@ func (app *BaseApp) ImportCollections(toImport []map[string]any, deleteMissing b
 		return errors.New("no collections to import")
 	}
 
+	importedCollections, err := prepareImportedCollections(app, toImport, deleteMissing)
+	if err != nil {
+		return err
+	}
+
+	mappedImported := make(map[string]*Collection, len(importedCollections))
+	for _, imported := range importedCollections {
+		mappedImported[imported.Id] = imported
+	}
+
+	return app.RunInTransaction(func(txApp App) error {
+		existingCollections := []*Collection{}
+		if err := txApp.CollectionQuery().OrderBy("updated ASC").All(&existingCollections); err != nil {
+			return err
+		}
+		mappedExisting := make(map[string]*Collection, len(existingCollections))
+		for _, existing := range existingCollections {
+			existing.IntegrityChecks(false)
+			mappedExisting[existing.Id] = existing
+		}
+
+		// delete old collections not available in the new configuration
+		// (before saving the imports in case a deleted collection name is being reused)
+		if deleteMissing {
+			for _, existing := range existingCollections {
+				if mappedImported[existing.Id] != nil || existing.System {
+					continue // exist or system
+				}
+
+				// delete collection
+				if err := txApp.Delete(existing); err != nil {
+					return err
+				}
+			}
+		}
+
+		// upsert imported collections
+		for _, imported := range importedCollections {
+			if err := txApp.SaveNoValidate(imported); err != nil {
+				return fmt.Errorf("failed to save collection %q: %w", imported.Name, err)
+			}
+		}
+
+		// run validations
+		for _, imported := range importedCollections {
+			original := mappedExisting[imported.Id]
+			if original == nil {
+				original = imported
+			}
+
+			validator := newCollectionValidator(
+				context.Background(),
+				txApp,
+				imported,
+				original,
+			)
+			if err := validator.run(); err != nil {
+				// serialize the validation error(s)
+				serializedErr, _ := json.MarshalIndent(err, "", "  ")
+
+				return validation.Errors{"collections": validation.NewError(
+					"validation_collections_import_failure",
+					fmt.Sprintf("Data validations failed for collection %q (%s):\n%s", imported.Name, imported.Id, serializedErr),
+				)}
+			}
+		}
+
+		return nil
+	})
+}
+
+// prepareImportedCollections normalizes the provided collections import data
+// by loading it on top of the matching existing collections (if any).
+//
+// The returned collections are sorted so that the views are last.
+func prepareImportedCollections(app App, toImport []map[string]any, deleteMissing bool) ([]*Collection, error) {
 	importedCollections := make([]*Collection, len(toImport))
-	mappedImported := make(map[string]*Collection, len(toImport))
 
 	// normalize imported collections data to ensure that all
 	// collection fields are present and properly initialized
@@ -54,14 +129,14 @@ func (app *BaseApp) ImportCollections(toImport []map[string]any, deleteMissing b
 
 		existing, err := app.FindCollectionByNameOrId(identifier)
 		if err != nil && !errors.Is(err, sql.ErrNoRows) {
-			return err
+			return nil, err
 		}
 
 		if existing != nil {
 			// refetch for deep copy
 			imported, err = app.FindCollectionByNameOrId(existing.Id)
 			if err != nil {
-				return err
+				return nil, err
 			}
 
 			// ensure that the fields will be cleared
@@ -71,13 +146,13 @@ func (app *BaseApp) ImportCollections(toImport []map[string]any, deleteMissing b
 
 			rawData, err := json.Marshal(data)
 			if err != nil {
-				return err
+				return nil, err
 			}
 
 			// load the imported data
 			err = json.Unmarshal(rawData, imported)
 			if err != nil {
-				return err
+				return nil, err
 			}
 
 			// extend with the existing fields if necessary
@@ -100,20 +175,19 @@ func (app *BaseApp) ImportCollections(toImport []map[string]any, deleteMissing b
 
 			rawData, err := json.Marshal(data)
 			if err != nil {
-				return err
+				return nil, err
 			}
 
 			// load the imported data
 			err = json.Unmarshal(rawData, imported)
 			if err != nil {
-				return err
+				return nil, err
 			}
 		}
 
 		imported.IntegrityChecks(false)
 
 		importedCollections[i] = imported
-		mappedImported[imported.Id] = imported
 	}
 
 	// reorder views last since the view query could depend on some of the other collections
@@ -138,63 +212,5 @@ func (app *BaseApp) ImportCollections(toImport []map[string]any, deleteMissing b
 		return res
 	})
 
-	return app.RunInTransaction(func(txApp App) error {
-		existingCollections := []*Collection{}
-		if err := txApp.CollectionQuery().OrderBy("updated ASC").All(&existingCollections); err != nil {
-			return err
-		}
-		mappedExisting := make(map[string]*Collection, len(existingCollections))
-		for _, existing := range existingCollections {
-			existing.IntegrityChecks(false)
-			mappedExisting[existing.Id] = existing
-		}
-
-		// delete old collections not available in the new configuration
-		// (before saving the imports in case a deleted collection name is being reused)
-		if deleteMissing {
-			for _, existing := range existingCollections {
-				if mappedImported[existing.Id] != nil || existing.System {
-					continue // exist or system
-				}
-
-				// delete collection
-				if err := txApp.Delete(existing); err != nil {
-					return err
-				}
-			}
-		}
-
-		// upsert imported collections
-		for _, imported := range importedCollections {
-			if err := txApp.SaveNoValidate(imported); err != nil {
-				return fmt.Errorf("failed to save collection %q: %w", imported.Name, err)
-			}
-		}
-
-		// run validations
-		for _, imported := range importedCollections {
-			original := mappedExisting[imported.Id]
-			if original == nil {
-				original = imported
-			}
-
-			validator := newCollectionValidator(
-				context.Background(),
-				txApp,
-				imported,
-				original,
-			)
-			if err := validator.run(); err != nil {
-				// serialize the validation error(s)
-				serializedErr, _ := json.MarshalIndent(err, "", "  ")
-
-				return validation.Errors{"collections": validation.NewError(
-					"validation_collections_import_failure",
-					fmt.Sprintf("Data validations failed for collection %q (%s):\n%s", imported.Name, imported.Id, serializedErr),
-				)}
-			}
-		}
-
-		return nil
-	})
+	return importedCollections, nil
 }
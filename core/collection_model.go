@@ -380,6 +380,7 @@ type baseCollection struct {
 // Collection defines the table, fields and various options related to a set of records.
 type Collection struct {
 	baseCollection
+	collectionBaseOptions
 	collectionAuthOptions
 	collectionViewOptions
 }
@@ -510,10 +511,13 @@ func (m *Collection) unmarshalRawOptions() error {
 	case CollectionTypeView:
 		return json.Unmarshal(raw, &m.collectionViewOptions)
 	case CollectionTypeAuth:
+		if err := json.Unmarshal(raw, &m.collectionBaseOptions); err != nil {
+			return err
+		}
 		return json.Unmarshal(raw, &m.collectionAuthOptions)
 	}
 
-	return nil
+	return json.Unmarshal(raw, &m.collectionBaseOptions)
 }
 
 // UnmarshalJSON implements the [json.Unmarshaler] interface.
@@ -556,8 +560,9 @@ func (m Collection) MarshalJSON() ([]byte, error) {
 	case CollectionTypeAuth:
 		alias := struct {
 			baseCollection
+			collectionBaseOptions
 			collectionAuthOptions
-		}{m.baseCollection, m.collectionAuthOptions}
+		}{m.baseCollection, m.collectionBaseOptions, m.collectionAuthOptions}
 
 		// ensure that it is always returned as array
 		if alias.OAuth2.Providers == nil {
@@ -576,7 +581,10 @@ func (m Collection) MarshalJSON() ([]byte, error) {
 
 		return json.Marshal(alias)
 	default:
-		return json.Marshal(m.baseCollection)
+		return json.Marshal(struct {
+			baseCollection
+			collectionBaseOptions
+		}{m.baseCollection, m.collectionBaseOptions})
 	}
 }
 
@@ -613,7 +621,16 @@ func (m *Collection) DBExport(app App) (map[string]any, error) {
 			return nil, err
 		}
 	case CollectionTypeAuth:
-		if raw, err := types.ParseJSONRaw(m.collectionAuthOptions); err == nil {
+		if raw, err := types.ParseJSONRaw(struct {
+			collectionBaseOptions
+			collectionAuthOptions
+		}{m.collectionBaseOptions, m.collectionAuthOptions}); err == nil {
+			result["options"] = raw
+		} else {
+			return nil, err
+		}
+	case CollectionTypeBase:
+		if raw, err := types.ParseJSONRaw(m.collectionBaseOptions); err == nil {
 			result["options"] = raw
 		} else {
 			return nil, err
@@ -709,13 +726,22 @@ func onCollectionDeleteExecute(e *CollectionEvent) error {
 
 		// delete the related view or records table
 		if e.Collection.IsView() {
-			if err := txApp.DeleteView(e.Collection.Name); err != nil {
+			if err := dropViewOrTable(txApp, e.Collection.Name); err != nil {
+				return err
+			}
+
+			_, err := txApp.DB().Delete(MaterializedViewsTable, dbx.HashExp{"collectionRef": e.Collection.Id}).Execute()
+			if err != nil {
 				return err
 			}
 		} else {
 			if err := txApp.DeleteTable(e.Collection.Name); err != nil {
 				return err
 			}
+
+			if err := txApp.DeleteTable(searchTableName(e.Collection)); err != nil {
+				return err
+			}
 		}
 
 		if !e.Collection.disableIntegrityChecks {
@@ -854,26 +880,47 @@ func onCollectionSaveExecute(e *CollectionEvent) error {
 				return err
 			}
 
-			// delete old renamed view
-			if oldCollection != nil {
-				if err := e.App.DeleteView(oldCollection.Name); err != nil {
-					return err
-				}
-			}
-
 			// wrap view query if necessary
 			query, err = normalizeViewQueryId(e.App, query)
 			if err != nil {
 				return fmt.Errorf("failed to normalize view query id: %w", err)
 			}
 
-			// (re)create the view
-			if err := e.App.SaveView(e.Collection.Name, query); err != nil {
-				return err
-			}
-
 			// updates newCollection.Fields based on the generated view table info and query
 			e.Collection.Fields = viewFields
+
+			if e.Collection.IsMaterializedView() {
+				// delete old renamed view or materialized view table
+				if oldCollection != nil && (oldCollection.Name != e.Collection.Name || !oldCollection.IsMaterializedView()) {
+					if err := dropViewOrTable(e.App, oldCollection.Name); err != nil {
+						return err
+					}
+				}
+
+				// (re)create and refresh the materialized view table
+				if err := saveMaterializedView(e.App, e.Collection, oldCollection, query); err != nil {
+					return err
+				}
+			} else {
+				// delete old renamed view or materialized view table
+				if oldCollection != nil {
+					if err := dropViewOrTable(e.App, oldCollection.Name); err != nil {
+						return err
+					}
+
+					if oldCollection.IsMaterializedView() {
+						_, err := e.App.DB().Delete(MaterializedViewsTable, dbx.HashExp{"collectionRef": oldCollection.Id}).Execute()
+						if err != nil {
+							return err
+						}
+					}
+				}
+
+				// (re)create the view
+				if err := e.App.SaveView(e.Collection.Name, query); err != nil {
+					return err
+				}
+			}
 		}
 
 		// save the Collection model
@@ -908,6 +955,8 @@ func (c *Collection) initDefaultFields() {
 	switch c.Type {
 	case CollectionTypeBase:
 		c.initIdField()
+		c.initDeletedField()
+		c.initTenantField()
 	case CollectionTypeAuth:
 		c.initIdField()
 		c.initPasswordField()
@@ -915,6 +964,8 @@ func (c *Collection) initDefaultFields() {
 		c.initEmailField()
 		c.initEmailVisibilityField()
 		c.initVerifiedField()
+		c.initDeletedField()
+		c.initTenantField()
 	case CollectionTypeView:
 		// view fields are autogenerated
 	}
@@ -1054,6 +1105,79 @@ func (c *Collection) initVerifiedField() {
 	}
 }
 
+func (c *Collection) initDeletedField() {
+	field, _ := c.Fields.GetByName(FieldNameDeleted).(*DateField)
+
+	if !c.SoftDeleteEnabled() {
+		// release the field so that it could be removed
+		if field != nil {
+			field.System = false
+		}
+		return
+	}
+
+	if field == nil {
+		// load default field
+		c.Fields.Add(&DateField{
+			Name:   FieldNameDeleted,
+			System: true,
+			Hidden: true,
+		})
+	} else {
+		// enforce system defaults
+		field.System = true
+		field.Hidden = true
+		field.Required = false
+	}
+}
+
+func (c *Collection) initTenantField() {
+	field, _ := c.Fields.GetByName(FieldNameTenant).(*RelationField)
+
+	if !c.TenantScoped() {
+		// release the field and its default index so that it could be removed
+		if field != nil && field.System {
+			field.System = false
+			c.RemoveIndex(c.fieldIndexName(FieldNameTenant))
+		}
+		return
+	}
+
+	if field == nil {
+		// load default field
+		c.Fields.Add(&RelationField{
+			Name:          FieldNameTenant,
+			System:        true,
+			Hidden:        true,
+			CollectionId:  c.Tenant.CollectionId,
+			MaxSelect:     1,
+			CascadeDelete: true,
+		})
+	} else {
+		// enforce system defaults
+		field.System = true
+		field.Hidden = true
+		field.Required = false
+		field.CollectionId = c.Tenant.CollectionId
+		field.MaxSelect = 1
+		field.CascadeDelete = true
+	}
+
+	// ensure that there is an index for the field
+	for _, idx := range c.Indexes {
+		parsed := dbutils.ParseIndex(idx)
+		if len(parsed.Columns) > 0 && strings.EqualFold(parsed.Columns[0].Name, FieldNameTenant) {
+			return
+		}
+	}
+	c.Indexes = append(c.Indexes, fmt.Sprintf(
+		"CREATE INDEX `%s` ON `%s` (`%s`)",
+		c.fieldIndexName(FieldNameTenant),
+		c.Name,
+		FieldNameTenant,
+	))
+}
+
 func (c *Collection) fieldIndexName(field string) string {
 	name := "idx_" + field + "_"
 
@@ -1,11 +1,291 @@
 package core
 
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
 var _ optionsValidator = (*collectionBaseOptions)(nil)
 
 // collectionBaseOptions defines the options for the "base" type collection.
+//
+// Note that the options are also available for the "auth" type collections.
 type collectionBaseOptions struct {
+	// History specifies the optional record revisions history configuration.
+	History *HistoryConfig `form:"history" json:"history,omitempty"`
+
+	// SoftDelete specifies the optional records soft delete configuration.
+	SoftDelete *SoftDeleteConfig `form:"softDelete" json:"softDelete,omitempty"`
+
+	// Search specifies the optional full-text search configuration.
+	Search *SearchConfig `form:"search" json:"search,omitempty"`
+
+	// ChangeLog specifies the optional records change data capture configuration.
+	ChangeLog *ChangeLogConfig `form:"changeLog" json:"changeLog,omitempty"`
+
+	// FieldRules specifies the optional field level API rules
+	// with the field name as map key.
+	FieldRules map[string]FieldRule `form:"fieldRules" json:"fieldRules,omitempty"`
+
+	// Tenant specifies the optional multi-tenancy configuration.
+	Tenant *TenantConfig `form:"tenant" json:"tenant,omitempty"`
+
+	// Expiry specifies the optional records time-to-live configuration.
+	Expiry *ExpiryConfig `form:"expiry" json:"expiry,omitempty"`
 }
 
 func (o *collectionBaseOptions) validate(cv *collectionValidator) error {
-	return nil
+	return validation.ValidateStruct(o,
+		validation.Field(&o.History),
+		validation.Field(&o.SoftDelete),
+		validation.Field(&o.Search, validation.By(cv.checkSearchConfig)),
+		validation.Field(&o.ChangeLog),
+		validation.Field(&o.FieldRules, validation.By(cv.checkFieldRules)),
+		validation.Field(&o.Tenant, validation.By(cv.checkTenantConfig)),
+		validation.Field(&o.Expiry, validation.By(cv.checkExpiryConfig)),
+	)
+}
+
+// HistoryEnabled reports whether the collection records revisions history is enabled.
+//
+// Always returns false for "view" type collections.
+func (m *Collection) HistoryEnabled() bool {
+	return !m.IsView() && m.History != nil && m.History.Enabled
+}
+
+// SoftDeleteEnabled reports whether the collection records soft delete is enabled.
+//
+// Always returns false for "view" type collections.
+func (m *Collection) SoftDeleteEnabled() bool {
+	return !m.IsView() && m.SoftDelete != nil && m.SoftDelete.Enabled
+}
+
+// ChangeLogEnabled reports whether the collection records changes are logged.
+//
+// Always returns false for "view" type collections.
+func (m *Collection) ChangeLogEnabled() bool {
+	return !m.IsView() && m.ChangeLog != nil && m.ChangeLog.Enabled
+}
+
+// SearchEnabled reports whether the collection has at least one
+// full-text searchable field.
+//
+// Always returns false for "view" type collections.
+func (m *Collection) SearchEnabled() bool {
+	return !m.IsView() && m.Search != nil && len(m.Search.Fields) > 0
+}
+
+// TenantScoped reports whether the collection records are scoped
+// to a tenant with a hidden "tenant" relation field.
+//
+// Always returns false for "view" type collections.
+func (m *Collection) TenantScoped() bool {
+	return !m.IsView() && m.Tenant != nil && m.Tenant.Enabled && m.Tenant.CollectionId != ""
+}
+
+// ExpiryEnabled reports whether the collection records expire.
+//
+// Always returns false for "view" type collections.
+func (m *Collection) ExpiryEnabled() bool {
+	return !m.IsView() && m.Expiry != nil && m.Expiry.Enabled && (m.Expiry.Field != "" || m.Expiry.TTL > 0)
+}
+
+// FieldViewRules returns the nonempty field view rules of the collection
+// with the field name as map key.
+//
+// Always returns nil for "view" type collections.
+func (m *Collection) FieldViewRules() map[string]string {
+	return m.fieldRules(func(r FieldRule) string { return r.ViewRule })
+}
+
+// FieldUpdateRules returns the nonempty field update rules of the collection
+// with the field name as map key.
+//
+// Always returns nil for "view" type collections.
+func (m *Collection) FieldUpdateRules() map[string]string {
+	return m.fieldRules(func(r FieldRule) string { return r.UpdateRule })
+}
+
+func (m *Collection) fieldRules(get func(r FieldRule) string) map[string]string {
+	if m.IsView() || len(m.FieldRules) == 0 {
+		return nil
+	}
+
+	result := make(map[string]string, len(m.FieldRules))
+
+	for name, rule := range m.FieldRules {
+		if v := get(rule); v != "" {
+			result[name] = v
+		}
+	}
+
+	if len(result) == 0 {
+		return nil
+	}
+
+	return result
+}
+
+// -------------------------------------------------------------------
+
+// HistoryConfig defines the collection records revisions history configuration.
+type HistoryConfig struct {
+	Enabled bool `form:"enabled" json:"enabled"`
+
+	// MaxDays specifies how many days the record revisions are kept.
+	//
+	// Set to 0 to keep the revisions forever.
+	MaxDays int `form:"maxDays" json:"maxDays"`
+}
+
+// Validate makes HistoryConfig validatable by implementing [validation.Validatable] interface.
+func (c HistoryConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.MaxDays, validation.Min(0), validation.Max(36500)), // ~100y max
+	)
+}
+
+// MaxDuration returns the MaxDays as [time.Duration] (0 if unlimited).
+func (c HistoryConfig) MaxDuration() time.Duration {
+	return time.Duration(c.MaxDays) * 24 * time.Hour
+}
+
+// -------------------------------------------------------------------
+
+// SoftDeleteConfig defines the collection records soft delete configuration.
+type SoftDeleteConfig struct {
+	Enabled bool `form:"enabled" json:"enabled"`
+
+	// MaxDays specifies how many days the soft deleted records are kept
+	// before being permanently deleted (aka. purged).
+	//
+	// Set to 0 to keep the soft deleted records forever.
+	MaxDays int `form:"maxDays" json:"maxDays"`
+}
+
+// Validate makes SoftDeleteConfig validatable by implementing [validation.Validatable] interface.
+func (c SoftDeleteConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.MaxDays, validation.Min(0), validation.Max(36500)), // ~100y max
+	)
+}
+
+// MaxDuration returns the MaxDays as [time.Duration] (0 if unlimited).
+func (c SoftDeleteConfig) MaxDuration() time.Duration {
+	return time.Duration(c.MaxDays) * 24 * time.Hour
+}
+
+// -------------------------------------------------------------------
+
+// ChangeLogConfig defines the collection records change log configuration.
+type ChangeLogConfig struct {
+	Enabled bool `form:"enabled" json:"enabled"`
+
+	// MaxDays specifies how many days the logged record changes are kept.
+	//
+	// Set to 0 to keep the changes forever.
+	MaxDays int `form:"maxDays" json:"maxDays"`
+}
+
+// Validate makes ChangeLogConfig validatable by implementing [validation.Validatable] interface.
+func (c ChangeLogConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.MaxDays, validation.Min(0), validation.Max(36500)), // ~100y max
+	)
+}
+
+// MaxDuration returns the MaxDays as [time.Duration] (0 if unlimited).
+func (c ChangeLogConfig) MaxDuration() time.Duration {
+	return time.Duration(c.MaxDays) * 24 * time.Hour
+}
+
+// -------------------------------------------------------------------
+
+// SearchConfig defines the collection full-text search configuration.
+//
+// The listed fields are indexed in a SQLite FTS5 shadow table and
+// could be queried with the fts() filter function.
+type SearchConfig struct {
+	// Fields specifies the names of the searchable text and editor fields.
+	Fields []string `form:"fields" json:"fields"`
+}
+
+// -------------------------------------------------------------------
+
+// FieldRule defines the optional API rules of a single collection field.
+//
+// The rules are regular filter expressions with access to the same
+// fields and macros as the collection API rules.
+// Empty rule means that no additional restriction is applied.
+//
+// Superusers are not affected by the field rules.
+type FieldRule struct {
+	// ViewRule specifies the condition that must be satisfied
+	// in order to return the field value as part of the record
+	// API responses, expands and realtime payloads.
+	//
+	// When not satisfied the field is silently omitted.
+	ViewRule string `form:"viewRule" json:"viewRule"`
+
+	// UpdateRule specifies the condition that must be satisfied
+	// in order to submit a new field value with the record update API.
+	//
+	// When not satisfied the request fails with a field validation error.
+	UpdateRule string `form:"updateRule" json:"updateRule"`
+}
+
+// -------------------------------------------------------------------
+
+// TenantConfig defines the collection records multi-tenancy configuration.
+//
+// When enabled, the collection records get a hidden "tenant" relation
+// to the tenant collection and for non-superusers all records queries
+// and API rules are additionally scoped to "tenant = @request.auth.tenant".
+type TenantConfig struct {
+	Enabled bool `form:"enabled" json:"enabled"`
+
+	// CollectionId specifies the id of the tenant collection
+	// (usually the same for all tenant scoped collections).
+	//
+	// Deleting a tenant record cascade deletes all of its scoped records.
+	CollectionId string `form:"collectionId" json:"collectionId"`
+}
+
+// -------------------------------------------------------------------
+
+// ExpiryConfig defines the collection records time-to-live configuration.
+//
+// The expired records are periodically deleted with app.Delete
+// (see DeleteExpiredRecords).
+type ExpiryConfig struct {
+	Enabled bool `form:"enabled" json:"enabled"`
+
+	// Field specifies the name of the date or autodate field
+	// that holds the record expiry date.
+	//
+	// Records with empty field value never expire.
+	Field string `form:"field" json:"field"`
+
+	// TTL specifies a fixed records lifetime in seconds
+	// after their "created" autodate field value.
+	//
+	// It is used only when Field is not set.
+	TTL int `form:"ttl" json:"ttl"`
+
+	// HideExpired specifies whether to exclude the expired records from
+	// the records API responses even before they are deleted.
+	HideExpired bool `form:"hideExpired" json:"hideExpired"`
+}
+
+// Validate makes ExpiryConfig validatable by implementing [validation.Validatable] interface.
+func (c ExpiryConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.TTL, validation.Min(0), validation.Max(3153600000)), // ~100y max
+	)
+}
+
+// TTLDuration returns the TTL as [time.Duration].
+func (c ExpiryConfig) TTLDuration() time.Duration {
+	return time.Duration(c.TTL) * time.Second
 }
@@ -0,0 +1,523 @@
+package core_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestCollectionBaseOptionsValidate(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name           string
+		collection     func(app core.App) (*core.Collection, error)
+		expectedErrors []string
+	}{
+		{
+			name: "base without history",
+			collection: func(app core.App) (*core.Collection, error) {
+				return core.NewBaseCollection("new_base"), nil
+			},
+			expectedErrors: []string{},
+		},
+		{
+			name: "base with invalid history max days",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewBaseCollection("new_base")
+				c.History = &core.HistoryConfig{Enabled: true, MaxDays: -1}
+				return c, nil
+			},
+			expectedErrors: []string{"history"},
+		},
+		{
+			name: "base with valid history",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewBaseCollection("new_base")
+				c.History = &core.HistoryConfig{Enabled: true, MaxDays: 30}
+				return c, nil
+			},
+			expectedErrors: []string{},
+		},
+		{
+			name: "auth with invalid history max days",
+			collection: func(app core.App) (*core.Collection, error) {
+				c, _ := app.FindCollectionByNameOrId("users")
+				c.History = &core.HistoryConfig{Enabled: true, MaxDays: -1}
+				return c, nil
+			},
+			expectedErrors: []string{"history"},
+		},
+		{
+			name: "base with invalid soft delete max days",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewBaseCollection("new_base")
+				c.SoftDelete = &core.SoftDeleteConfig{Enabled: true, MaxDays: 40000}
+				return c, nil
+			},
+			expectedErrors: []string{"softDelete"},
+		},
+		{
+			name: "base with valid soft delete",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewBaseCollection("new_base")
+				c.SoftDelete = &core.SoftDeleteConfig{Enabled: true, MaxDays: 30}
+				return c, nil
+			},
+			expectedErrors: []string{},
+		},
+		{
+			name: "base with invalid change log max days",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewBaseCollection("new_base")
+				c.ChangeLog = &core.ChangeLogConfig{Enabled: true, MaxDays: -1}
+				return c, nil
+			},
+			expectedErrors: []string{"changeLog"},
+		},
+		{
+			name: "base with valid change log",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewBaseCollection("new_base")
+				c.ChangeLog = &core.ChangeLogConfig{Enabled: true, MaxDays: 7}
+				return c, nil
+			},
+			expectedErrors: []string{},
+		},
+		{
+			name: "base with field rules for missing fields",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewBaseCollection("new_base")
+				c.FieldRules = map[string]core.FieldRule{
+					"missing": {ViewRule: "id != ''"},
+				}
+				return c, nil
+			},
+			expectedErrors: []string{"fieldRules"},
+		},
+		{
+			name: "base with field rule for the id field",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewBaseCollection("new_base")
+				c.FieldRules = map[string]core.FieldRule{
+					"id": {ViewRule: "id != ''"},
+				}
+				return c, nil
+			},
+			expectedErrors: []string{"fieldRules"},
+		},
+		{
+			name: "base with invalid field rules",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewBaseCollection("new_base")
+				c.Fields.Add(&core.TextField{Name: "title"})
+				c.FieldRules = map[string]core.FieldRule{
+					"title": {UpdateRule: "missing = 1"},
+				}
+				return c, nil
+			},
+			expectedErrors: []string{"fieldRules"},
+		},
+		{
+			name: "base with tenant without collection",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewBaseCollection("new_base")
+				c.Tenant = &core.TenantConfig{Enabled: true}
+				return c, nil
+			},
+			expectedErrors: []string{"tenant"},
+		},
+		{
+			name: "base with tenant and missing tenant collection",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewBaseCollection("new_base")
+				c.Tenant = &core.TenantConfig{Enabled: true, CollectionId: "missing"}
+				return c, nil
+			},
+			expectedErrors: []string{"tenant"},
+		},
+		{
+			name: "base with tenant and view tenant collection",
+			collection: func(app core.App) (*core.Collection, error) {
+				view, err := app.FindCollectionByNameOrId("view1")
+				if err != nil {
+					return nil, err
+				}
+				c := core.NewBaseCollection("new_base")
+				c.Tenant = &core.TenantConfig{Enabled: true, CollectionId: view.Id}
+				return c, nil
+			},
+			expectedErrors: []string{"tenant"},
+		},
+		{
+			name: "base with itself as tenant collection",
+			collection: func(app core.App) (*core.Collection, error) {
+				c, err := app.FindCollectionByNameOrId("demo1")
+				if err != nil {
+					return nil, err
+				}
+				c.Tenant = &core.TenantConfig{Enabled: true, CollectionId: c.Id}
+				return c, nil
+			},
+			expectedErrors: []string{"tenant"},
+		},
+		{
+			name: "base with disabled tenant and missing tenant collection",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewBaseCollection("new_base")
+				c.Tenant = &core.TenantConfig{CollectionId: "missing"}
+				return c, nil
+			},
+			expectedErrors: []string{},
+		},
+		{
+			name: "auth with valid tenant",
+			collection: func(app core.App) (*core.Collection, error) {
+				tenants, err := app.FindCollectionByNameOrId("demo2")
+				if err != nil {
+					return nil, err
+				}
+				c, _ := app.FindCollectionByNameOrId("users")
+				c.Tenant = &core.TenantConfig{Enabled: true, CollectionId: tenants.Id}
+				return c, nil
+			},
+			expectedErrors: []string{},
+		},
+		{
+			name: "base with expiry without field and ttl",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewBaseCollection("new_base")
+				c.Expiry = &core.ExpiryConfig{Enabled: true}
+				return c, nil
+			},
+			expectedErrors: []string{"expiry"},
+		},
+		{
+			name: "base with expiry ttl and missing created field",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewBaseCollection("new_base")
+				c.Expiry = &core.ExpiryConfig{Enabled: true, TTL: 60}
+				return c, nil
+			},
+			expectedErrors: []string{"expiry"},
+		},
+		{
+			name: "base with negative expiry ttl",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewBaseCollection("new_base")
+				c.Fields.Add(&core.AutodateField{Name: "created", OnCreate: true})
+				c.Expiry = &core.ExpiryConfig{TTL: -1}
+				return c, nil
+			},
+			expectedErrors: []string{"expiry"},
+		},
+		{
+			name: "base with expiry field and ttl",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewBaseCollection("new_base")
+				c.Fields.Add(&core.DateField{Name: "expires"})
+				c.Expiry = &core.ExpiryConfig{Enabled: true, Field: "expires", TTL: 60}
+				return c, nil
+			},
+			expectedErrors: []string{"expiry"},
+		},
+		{
+			name: "base with non-date expiry field",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewBaseCollection("new_base")
+				c.Fields.Add(&core.TextField{Name: "expires"})
+				c.Expiry = &core.ExpiryConfig{Enabled: true, Field: "expires"}
+				return c, nil
+			},
+			expectedErrors: []string{"expiry"},
+		},
+		{
+			name: "base with valid expiry field",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewBaseCollection("new_base")
+				c.Fields.Add(&core.DateField{Name: "expires"})
+				c.Expiry = &core.ExpiryConfig{Enabled: true, Field: "expires", HideExpired: true}
+				return c, nil
+			},
+			expectedErrors: []string{},
+		},
+		{
+			name: "auth with valid expiry ttl",
+			collection: func(app core.App) (*core.Collection, error) {
+				c, _ := app.FindCollectionByNameOrId("users")
+				c.Expiry = &core.ExpiryConfig{Enabled: true, TTL: 3600}
+				return c, nil
+			},
+			expectedErrors: []string{},
+		},
+		{
+			name: "auth with valid field rules",
+			collection: func(app core.App) (*core.Collection, error) {
+				c, _ := app.FindCollectionByNameOrId("users")
+				c.FieldRules = map[string]core.FieldRule{
+					"name":     {ViewRule: "id = @request.auth.id", UpdateRule: "@request.auth.verified = true"},
+					"username": {},
+				}
+				return c, nil
+			},
+			expectedErrors: []string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			app, _ := tests.NewTestApp()
+			defer app.Cleanup()
+
+			collection, err := s.collection(app)
+			if err != nil {
+				t.Fatalf("Failed to retrieve test collection: %v", err)
+			}
+
+			result := app.Validate(collection)
+
+			tests.TestValidationErrors(t, result, s.expectedErrors)
+		})
+	}
+}
+
+func TestCollectionHistoryEnabled(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name       string
+		collection *core.Collection
+		history    *core.HistoryConfig
+		expected   bool
+	}{
+		{"base without history", core.NewBaseCollection("test"), nil, false},
+		{"base with disabled history", core.NewBaseCollection("test"), &core.HistoryConfig{}, false},
+		{"base with enabled history", core.NewBaseCollection("test"), &core.HistoryConfig{Enabled: true}, true},
+		{"auth with enabled history", core.NewAuthCollection("test"), &core.HistoryConfig{Enabled: true}, true},
+		{"view with enabled history", core.NewViewCollection("test"), &core.HistoryConfig{Enabled: true}, false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			s.collection.History = s.history
+
+			if v := s.collection.HistoryEnabled(); v != s.expected {
+				t.Fatalf("Expected %v, got %v", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestCollectionHistoryPersistence(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	for _, name := range []string{"demo1", "nologin"} {
+		t.Run(name, func(t *testing.T) {
+			collection, err := app.FindCollectionByNameOrId(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			raw, err := json.Marshal(collection)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(string(raw), `"history"`) {
+				t.Fatalf("Expected no history key in %s", raw)
+			}
+
+			collection.History = &core.HistoryConfig{Enabled: true, MaxDays: 10}
+			if err := app.Save(collection); err != nil {
+				t.Fatal(err)
+			}
+
+			collection, err = app.FindCollectionByNameOrId(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if collection.History == nil || !collection.History.Enabled || collection.History.MaxDays != 10 {
+				t.Fatalf("Expected the history options to be persisted, got %v", collection.History)
+			}
+
+			raw, err = json.Marshal(collection)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(raw), `"history":{"enabled":true,"maxDays":10}`) {
+				t.Fatalf("Expected history key in %s", raw)
+			}
+		})
+	}
+}
+
+func TestCollectionSoftDeleteEnabled(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name       string
+		collection *core.Collection
+		softDelete *core.SoftDeleteConfig
+		expected   bool
+	}{
+		{"base without soft delete", core.NewBaseCollection("test"), nil, false},
+		{"base with disabled soft delete", core.NewBaseCollection("test"), &core.SoftDeleteConfig{}, false},
+		{"base with enabled soft delete", core.NewBaseCollection("test"), &core.SoftDeleteConfig{Enabled: true}, true},
+		{"auth with enabled soft delete", core.NewAuthCollection("test"), &core.SoftDeleteConfig{Enabled: true}, true},
+		{"view with enabled soft delete", core.NewViewCollection("test"), &core.SoftDeleteConfig{Enabled: true}, false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			s.collection.SoftDelete = s.softDelete
+
+			if v := s.collection.SoftDeleteEnabled(); v != s.expected {
+				t.Fatalf("Expected %v, got %v", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestCollectionChangeLogEnabled(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name       string
+		collection *core.Collection
+		changeLog  *core.ChangeLogConfig
+		expected   bool
+	}{
+		{"base without change log", core.NewBaseCollection("test"), nil, false},
+		{"base with disabled change log", core.NewBaseCollection("test"), &core.ChangeLogConfig{}, false},
+		{"base with enabled change log", core.NewBaseCollection("test"), &core.ChangeLogConfig{Enabled: true}, true},
+		{"auth with enabled change log", core.NewAuthCollection("test"), &core.ChangeLogConfig{Enabled: true}, true},
+		{"view with enabled change log", core.NewViewCollection("test"), &core.ChangeLogConfig{Enabled: true}, false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			s.collection.ChangeLog = s.changeLog
+
+			if v := s.collection.ChangeLogEnabled(); v != s.expected {
+				t.Fatalf("Expected %v, got %v", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestCollectionTenantScoped(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name       string
+		collection *core.Collection
+		tenant     *core.TenantConfig
+		expected   bool
+	}{
+		{"base without tenant", core.NewBaseCollection("test"), nil, false},
+		{"base with disabled tenant", core.NewBaseCollection("test"), &core.TenantConfig{CollectionId: "abc"}, false},
+		{"base with enabled tenant and no collection", core.NewBaseCollection("test"), &core.TenantConfig{Enabled: true}, false},
+		{"base with enabled tenant", core.NewBaseCollection("test"), &core.TenantConfig{Enabled: true, CollectionId: "abc"}, true},
+		{"auth with enabled tenant", core.NewAuthCollection("test"), &core.TenantConfig{Enabled: true, CollectionId: "abc"}, true},
+		{"view with enabled tenant", core.NewViewCollection("test"), &core.TenantConfig{Enabled: true, CollectionId: "abc"}, false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			s.collection.Tenant = s.tenant
+
+			if v := s.collection.TenantScoped(); v != s.expected {
+				t.Fatalf("Expected %v, got %v", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestCollectionExpiryEnabled(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name       string
+		collection *core.Collection
+		expiry     *core.ExpiryConfig
+		expected   bool
+	}{
+		{"base without expiry", core.NewBaseCollection("test"), nil, false},
+		{"base with disabled expiry", core.NewBaseCollection("test"), &core.ExpiryConfig{TTL: 10}, false},
+		{"base with enabled expiry and no field or ttl", core.NewBaseCollection("test"), &core.ExpiryConfig{Enabled: true}, false},
+		{"base with enabled expiry field", core.NewBaseCollection("test"), &core.ExpiryConfig{Enabled: true, Field: "expires"}, true},
+		{"auth with enabled expiry ttl", core.NewAuthCollection("test"), &core.ExpiryConfig{Enabled: true, TTL: 10}, true},
+		{"view with enabled expiry ttl", core.NewViewCollection("test"), &core.ExpiryConfig{Enabled: true, TTL: 10}, false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			s.collection.Expiry = s.expiry
+
+			if v := s.collection.ExpiryEnabled(); v != s.expected {
+				t.Fatalf("Expected %v, got %v", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestCollectionFieldRules(t *testing.T) {
+	t.Parallel()
+
+	rules := map[string]core.FieldRule{
+		"a": {ViewRule: "1 = 1"},
+		"b": {UpdateRule: "2 = 2"},
+		"c": {ViewRule: "3 = 3", UpdateRule: "4 = 4"},
+		"d": {},
+	}
+
+	scenarios := []struct {
+		name           string
+		collection     *core.Collection
+		rules          map[string]core.FieldRule
+		expectedView   map[string]string
+		expectedUpdate map[string]string
+	}{
+		{"base without field rules", core.NewBaseCollection("test"), nil, nil, nil},
+		{"base with empty field rules", core.NewBaseCollection("test"), map[string]core.FieldRule{"d": {}}, nil, nil},
+		{
+			"base with field rules",
+			core.NewBaseCollection("test"),
+			rules,
+			map[string]string{"a": "1 = 1", "c": "3 = 3"},
+			map[string]string{"b": "2 = 2", "c": "4 = 4"},
+		},
+		{
+			"auth with field rules",
+			core.NewAuthCollection("test"),
+			rules,
+			map[string]string{"a": "1 = 1", "c": "3 = 3"},
+			map[string]string{"b": "2 = 2", "c": "4 = 4"},
+		},
+		{"view with field rules", core.NewViewCollection("test"), rules, nil, nil},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			s.collection.FieldRules = s.rules
+
+			view, _ := json.Marshal(s.collection.FieldViewRules())
+			expectedView, _ := json.Marshal(s.expectedView)
+			if string(view) != string(expectedView) {
+				t.Fatalf("Expected view rules %s, got %s", expectedView, view)
+			}
+
+			update, _ := json.Marshal(s.collection.FieldUpdateRules())
+			expectedUpdate, _ := json.Marshal(s.expectedUpdate)
+			if string(update) != string(expectedUpdate) {
+				t.Fatalf("Expected update rules %s, got %s", expectedUpdate, update)
+			}
+		})
+	}
+}
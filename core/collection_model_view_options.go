@@ -9,10 +9,57 @@ var _ optionsValidator = (*collectionViewOptions)(nil)
 // collectionViewOptions defines the options for the "view" type collection.
 type collectionViewOptions struct {
 	ViewQuery string `form:"viewQuery" json:"viewQuery"`
+
+	// Materialized specifies the optional materialized view configuration
+	// (aka. storing the view query result in a regular table).
+	Materialized *MaterializedViewConfig `form:"materialized" json:"materialized,omitempty"`
 }
 
 func (o *collectionViewOptions) validate(cv *collectionValidator) error {
 	return validation.ValidateStruct(o,
 		validation.Field(&o.ViewQuery, validation.Required, validation.By(cv.checkViewQuery)),
+		validation.Field(&o.Materialized),
+	)
+}
+
+// IsMaterializedView reports whether the collection is a "view"
+// with enabled materialization.
+func (m *Collection) IsMaterializedView() bool {
+	return m.IsView() && m.Materialized != nil && m.Materialized.Enabled
+}
+
+// -------------------------------------------------------------------
+
+// MaterializedViewConfig defines the materialized view collection configuration.
+//
+// The view query result of a materialized view is stored in a regular
+// table with the collection name, allowing indexes and avoiding the
+// query recomputation on every request.
+type MaterializedViewConfig struct {
+	Enabled bool `form:"enabled" json:"enabled"`
+
+	// RefreshCron is an optional cron expression to schedule
+	// the materialized view refresh, eg. "*/30 * * * *".
+	RefreshCron string `form:"refreshCron" json:"refreshCron"`
+
+	// RefreshOnChange specifies whether to refresh the materialized view
+	// when a record of its source collections is created, updated or deleted.
+	RefreshOnChange bool `form:"refreshOnChange" json:"refreshOnChange"`
+
+	// RefreshOnChangeDelay is the number of milliseconds to wait before
+	// refreshing on source records change in order to combine multiple
+	// changes into a single refresh.
+	//
+	// The refresh is always performed in the background, outside of the
+	// record request. Set to 0 to use the default 500ms delay
+	// (see [DefaultMaterializedViewRefreshOnChangeDelay]).
+	RefreshOnChangeDelay int `form:"refreshOnChangeDelay" json:"refreshOnChangeDelay"`
+}
+
+// Validate makes MaterializedViewConfig validatable by implementing [validation.Validatable] interface.
+func (c MaterializedViewConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.RefreshCron, validation.By(checkCronExpression)),
+		validation.Field(&c.RefreshOnChangeDelay, validation.Min(0), validation.Max(3600000)), // 1h max
 	)
 }
@@ -286,8 +286,11 @@ func saveViewCollection(app App, newCollection, oldCollection *Collection) error
 		}
 
 		// (re)create the view
-		if err := txApp.SaveView(newCollection.Name, query); err != nil {
-			return err
+		// (the materialized view tables are recreated as part of the collection save)
+		if !newCollection.IsMaterializedView() {
+			if err := txApp.SaveView(newCollection.Name, query); err != nil {
+				return err
+			}
 		}
 
 		newCollection.Fields = viewFields
@@ -3,6 +3,7 @@ package core
 import (
 	"fmt"
 	"log/slog"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -43,7 +44,11 @@ func (app *BaseApp) SyncRecordTableSchema(newCollection *Collection, oldCollecti
 				return err
 			}
 
-			return createCollectionIndexes(txApp, newCollection)
+			if err := createCollectionIndexes(txApp, newCollection); err != nil {
+				return err
+			}
+
+			return syncSearchTable(txApp, newCollection, nil)
 		}
 
 		// update
@@ -77,8 +82,34 @@ func (app *BaseApp) SyncRecordTableSchema(newCollection *Collection, oldCollecti
 			}
 		}
 
+		// drop the deleted or changed generated columns first since
+		// their expressions could reference other to be deleted columns
+		// (they will be recreated later as new columns)
+		droppedGenerated := map[string]bool{}
+		for _, oldField := range oldFields {
+			if !isGeneratedColumnField(oldField) {
+				continue
+			}
+
+			newField := newFields.GetById(oldField.GetId())
+			if newField != nil && newField.Type() == oldField.Type() && newField.ColumnType(txApp) == oldField.ColumnType(txApp) {
+				continue // no change
+			}
+
+			_, err := txApp.DB().DropColumn(newTableName, oldField.GetName()).Execute()
+			if err != nil {
+				return fmt.Errorf("failed to drop generated column %s - %w", oldField.GetName(), err)
+			}
+
+			droppedGenerated[oldField.GetId()] = true
+		}
+
 		// check for deleted columns
 		for _, oldField := range oldFields {
+			if droppedGenerated[oldField.GetId()] {
+				continue // already dropped
+			}
+
 			if f := newFields.GetById(oldField.GetId()); f != nil {
 				continue // exist
 			}
@@ -91,8 +122,12 @@ func (app *BaseApp) SyncRecordTableSchema(newCollection *Collection, oldCollecti
 
 		// check for new or renamed columns
 		toRename := map[string]string{}
+		var needTableRebuild bool
 		for _, field := range newFields {
 			oldField := oldFields.GetById(field.GetId())
+			if droppedGenerated[field.GetId()] {
+				oldField = nil // recreate
+			}
 			// Note:
 			// We are using a temporary column name when adding or renaming columns
 			// to ensure that there are no name collisions in case there is
@@ -100,6 +135,12 @@ func (app *BaseApp) SyncRecordTableSchema(newCollection *Collection, oldCollecti
 			// This way we are always doing 1 more rename operation but it provides better less ambiguous experience.
 
 			if oldField == nil {
+				// SQLite doesn't support adding STORED generated columns
+				if isGeneratedColumnField(field) && strings.HasSuffix(strings.ToUpper(field.ColumnType(txApp)), "STORED") {
+					needTableRebuild = true
+					continue
+				}
+
 				tempName := field.GetName() + security.PseudorandomString(5)
 				toRename[tempName] = field.GetName()
 
@@ -132,11 +173,23 @@ func (app *BaseApp) SyncRecordTableSchema(newCollection *Collection, oldCollecti
 			return err
 		}
 
+		if needTableRebuild {
+			if err := rebuildRecordTable(txApp, newCollection); err != nil {
+				return err
+			}
+		}
+
 		if needIndexesUpdate {
-			return createCollectionIndexes(txApp, newCollection)
+			if err := createCollectionIndexes(txApp, newCollection); err != nil {
+				return err
+			}
 		}
 
-		return nil
+		if err := syncEncryptedFieldValues(txApp, newCollection, oldCollection); err != nil {
+			return fmt.Errorf("failed to sync the encrypted field values - %w", err)
+		}
+
+		return syncSearchTable(txApp, newCollection, oldCollection)
 	})
 	if txErr != nil {
 		return txErr
@@ -297,8 +350,87 @@ func normalizeSingleVsMultipleFieldChanges(app App, newCollection *Collection, o
 	})
 }
 
+func isGeneratedColumnField(field Field) bool {
+	gc, ok := field.(GeneratedColumner)
+
+	return ok && gc.IsGeneratedColumn()
+}
+
+// rebuildRecordTable recreates the collection records table with the
+// current fields definition and copies the existing non-generated columns data.
+//
+// Note that the collection indexes are not restored.
+func rebuildRecordTable(app App, collection *Collection) error {
+	return app.RunInTransaction(func(txApp App) error {
+		// temporary drop all views to prevent reference errors during the table renaming
+		views := []struct {
+			Name string `db:"name"`
+			SQL  string `db:"sql"`
+		}{}
+		err := txApp.DB().Select("name", "sql").
+			From("sqlite_master").
+			AndWhere(dbx.NewExp("sql is not null")).
+			AndWhere(dbx.HashExp{"type": "view"}).
+			All(&views)
+		if err != nil {
+			return err
+		}
+		for _, view := range views {
+			err = txApp.DeleteView(view.Name)
+			if err != nil {
+				return err
+			}
+		}
+
+		tempName := "_" + collection.Name + security.PseudorandomString(5)
+
+		cols := make(map[string]string, len(collection.Fields))
+		copyCols := make([]string, 0, len(collection.Fields))
+		for _, field := range collection.Fields {
+			cols[field.GetName()] = field.ColumnType(txApp)
+
+			if !isGeneratedColumnField(field) {
+				copyCols = append(copyCols, "[["+field.GetName()+"]]")
+			}
+		}
+
+		if _, err := txApp.DB().CreateTable(tempName, cols).Execute(); err != nil {
+			return err
+		}
+
+		_, err = txApp.DB().NewQuery(fmt.Sprintf(
+			"INSERT INTO {{%s}} (%s) SELECT %s FROM {{%s}}",
+			tempName,
+			strings.Join(copyCols, ","),
+			strings.Join(copyCols, ","),
+			collection.Name,
+		)).Execute()
+		if err != nil {
+			return err
+		}
+
+		if _, err := txApp.DB().DropTable(collection.Name).Execute(); err != nil {
+			return err
+		}
+
+		if _, err := txApp.DB().RenameTable("{{"+tempName+"}}", "{{"+collection.Name+"}}").Execute(); err != nil {
+			return err
+		}
+
+		// restore views
+		for _, view := range views {
+			_, err = txApp.DB().NewQuery(view.SQL).Execute()
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
 func dropCollectionIndexes(app App, collection *Collection) error {
-	if collection.IsView() {
+	if collection.IsView() && !collection.IsMaterializedView() {
 		return nil // views don't have indexes
 	}
 
@@ -321,7 +453,7 @@ func dropCollectionIndexes(app App, collection *Collection) error {
 }
 
 func createCollectionIndexes(app App, collection *Collection) error {
-	if collection.IsView() {
+	if collection.IsView() && !collection.IsMaterializedView() {
 		return nil // views don't have indexes
 	}
 
@@ -362,3 +494,71 @@ func createCollectionIndexes(app App, collection *Collection) error {
 		return nil
 	})
 }
+
+// searchTableName returns the name of the collection FTS5 shadow table.
+//
+// Note that the collection id is used to avoid renaming the
+// shadow table on collection rename.
+func searchTableName(collection *Collection) string {
+	return "_fts_" + collection.Id
+}
+
+// syncSearchTable (re)creates and populates the collection FTS5
+// shadow table if the searchable fields have changed.
+//
+// The shadow table is dropped if the collection full-text search is disabled.
+func syncSearchTable(app App, newCollection *Collection, oldCollection *Collection) error {
+	tableName := searchTableName(newCollection)
+
+	var newFields, oldFields []string
+	if newCollection.SearchEnabled() {
+		newFields = newCollection.Search.Fields
+	}
+	if oldCollection != nil && oldCollection.SearchEnabled() {
+		// note: the shadow table columns are named after the fields
+		// so a field rename will also trigger the table recreation
+		oldFields = oldCollection.Search.Fields
+	}
+
+	if slices.Equal(newFields, oldFields) && (len(newFields) == 0) == !app.HasTable(tableName) {
+		return nil // no changes
+	}
+
+	if err := app.DeleteTable(tableName); err != nil {
+		return err
+	}
+
+	if len(newFields) == 0 {
+		return nil
+	}
+
+	cols := make([]string, 0, len(newFields)+1)
+	cols = append(cols, "[["+FieldNameId+"]]")
+	for _, name := range newFields {
+		cols = append(cols, "[["+name+"]]")
+	}
+	colsList := strings.Join(cols, ",")
+
+	_, err := app.DB().NewQuery(fmt.Sprintf(
+		"CREATE VIRTUAL TABLE {{%s}} USING fts5(%s, tokenize='unicode61 remove_diacritics 2')",
+		tableName,
+		colsList,
+	)).Execute()
+	if err != nil {
+		return fmt.Errorf("failed to create full-text search table: %w", err)
+	}
+
+	// populate with the existing records
+	_, err = app.DB().NewQuery(fmt.Sprintf(
+		"INSERT INTO {{%s}} (%s) SELECT %s FROM {{%s}}",
+		tableName,
+		colsList,
+		colsList,
+		newCollection.Name,
+	)).Execute()
+	if err != nil {
+		return fmt.Errorf("failed to populate full-text search table: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,694 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/tools/dbutils"
+)
+
+// ErrDestructiveSchemaChanges is returned by [ApplySchema] when the schema plan
+// contains destructive changes and they were not explicitly allowed.
+var ErrDestructiveSchemaChanges = errors.New("the schema plan contains destructive changes")
+
+const (
+	SchemaChangeCreate = "create"
+	SchemaChangeUpdate = "update"
+	SchemaChangeDelete = "delete"
+)
+
+const (
+	SchemaChangeKindCollection = "collection"
+	SchemaChangeKindField      = "field"
+	SchemaChangeKindIndex      = "index"
+	SchemaChangeKindRule       = "rule"
+	SchemaChangeKindOption     = "option"
+)
+
+// SchemaChange describes a single difference between the live
+// collections schema and the desired (aka. imported) one.
+type SchemaChange struct {
+	// Old is the current value of the changed element (nil for create).
+	Old any `json:"old"`
+
+	// New is the desired value of the changed element (nil for delete).
+	New any `json:"new"`
+
+	// Action is one of the SchemaChangeCreate, SchemaChangeUpdate or SchemaChangeDelete constants.
+	Action string `json:"action"`
+
+	// Kind is one of the SchemaChangeKind* constants.
+	Kind string `json:"kind"`
+
+	// Collection is the name of the changed collection
+	// (or the new name in case of a rename).
+	Collection string `json:"collection"`
+
+	// Name is the name of the changed field, index, rule or option
+	// (empty for collection level changes).
+	Name string `json:"name,omitempty"`
+
+	// Note is an optional human readable description of the change.
+	Note string `json:"note,omitempty"`
+
+	// Destructive indicates whether applying the change could result in data loss.
+	Destructive bool `json:"destructive"`
+}
+
+// String returns a short single line representation of the change
+// (ex. "- delete field posts.title (the column and its data will be dropped)").
+func (c *SchemaChange) String() string {
+	var sb strings.Builder
+
+	switch c.Action {
+	case SchemaChangeCreate:
+		sb.WriteString("+ ")
+	case SchemaChangeDelete:
+		sb.WriteString("- ")
+	default:
+		sb.WriteString("~ ")
+	}
+
+	sb.WriteString(c.Action)
+	sb.WriteString(" ")
+	sb.WriteString(c.Kind)
+	sb.WriteString(" ")
+	sb.WriteString(c.Collection)
+
+	if c.Name != "" {
+		sb.WriteString(".")
+		sb.WriteString(c.Name)
+	}
+
+	if c.Note != "" {
+		sb.WriteString(" (")
+		sb.WriteString(c.Note)
+		sb.WriteString(")")
+	}
+
+	if c.Destructive {
+		sb.WriteString(" [destructive]")
+	}
+
+	return sb.String()
+}
+
+// SchemaPlan is the list of changes that would be executed when
+// importing the desired collections schema (see [PlanSchema]).
+type SchemaPlan struct {
+	Changes       []*SchemaChange `json:"changes"`
+	DeleteMissing bool            `json:"deleteMissing"`
+}
+
+// HasChanges reports whether the plan has at least one change.
+func (p *SchemaPlan) HasChanges() bool {
+	return len(p.Changes) > 0
+}
+
+// HasDestructiveChanges reports whether the plan has at least one destructive change.
+func (p *SchemaPlan) HasDestructiveChanges() bool {
+	for _, c := range p.Changes {
+		if c.Destructive {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DestructiveChanges returns only the destructive changes of the plan.
+func (p *SchemaPlan) DestructiveChanges() []*SchemaChange {
+	result := []*SchemaChange{}
+
+	for _, c := range p.Changes {
+		if c.Destructive {
+			result = append(result, c)
+		}
+	}
+
+	return result
+}
+
+// PlanSchema compares the provided collections data (in the same format
+// as for [App.ImportCollections]) with the live database schema and
+// returns the list of collection, field, index, rule and option changes
+// that an import would execute.
+//
+// It doesn't modify the database.
+//
+// When deleteMissing is true, the non-system collections and fields
+// that are not present in toImport are reported as deleted
+// (the same as with [App.ImportCollections]).
+func PlanSchema(app App, toImport []map[string]any, deleteMissing bool) (*SchemaPlan, error) {
+	if len(toImport) == 0 {
+		return nil, errors.New("no collections to plan")
+	}
+
+	imported, err := prepareImportedCollections(app, cloneImportData(toImport), deleteMissing)
+	if err != nil {
+		return nil, err
+	}
+
+	existingCollections := []*Collection{}
+	if err := app.CollectionQuery().OrderBy("created ASC").All(&existingCollections); err != nil {
+		return nil, err
+	}
+
+	mappedExisting := make(map[string]*Collection, len(existingCollections))
+	for _, existing := range existingCollections {
+		mappedExisting[existing.Id] = existing
+	}
+
+	plan := &SchemaPlan{
+		DeleteMissing: deleteMissing,
+		Changes:       []*SchemaChange{},
+	}
+
+	mappedImported := make(map[string]struct{}, len(imported))
+
+	for _, c := range imported {
+		mappedImported[c.Id] = struct{}{}
+
+		changes, err := diffCollections(mappedExisting[c.Id], c)
+		if err != nil {
+			return nil, err
+		}
+
+		plan.Changes = append(plan.Changes, changes...)
+	}
+
+	if deleteMissing {
+		for _, existing := range existingCollections {
+			if _, ok := mappedImported[existing.Id]; ok || existing.System {
+				continue
+			}
+
+			plan.Changes = append(plan.Changes, &SchemaChange{
+				Action:      SchemaChangeDelete,
+				Kind:        SchemaChangeKindCollection,
+				Collection:  existing.Name,
+				Old:         existing,
+				Note:        deleteCollectionNote(existing),
+				Destructive: !existing.IsView(),
+			})
+		}
+	}
+
+	return plan, nil
+}
+
+// SchemaApplyOptions defines the options for [ApplySchema].
+type SchemaApplyOptions struct {
+	// DeleteMissing reports the same as the [App.ImportCollections] deleteMissing argument.
+	DeleteMissing bool
+
+	// AllowDestructive allows applying plans with destructive changes.
+	//
+	// If not set and the plan contains destructive changes,
+	// [ErrDestructiveSchemaChanges] is returned and nothing is applied.
+	AllowDestructive bool
+}
+
+// ApplySchema plans and imports the provided collections data
+// in a single transaction and returns the executed plan.
+//
+// Plans without changes are not imported.
+func ApplySchema(app App, toImport []map[string]any, options SchemaApplyOptions) (*SchemaPlan, error) {
+	var plan *SchemaPlan
+
+	err := app.RunInTransaction(func(txApp App) error {
+		var err error
+
+		plan, err = PlanSchema(txApp, toImport, options.DeleteMissing)
+		if err != nil {
+			return err
+		}
+
+		if !plan.HasChanges() {
+			return nil
+		}
+
+		if !options.AllowDestructive && plan.HasDestructiveChanges() {
+			return ErrDestructiveSchemaChanges
+		}
+
+		return txApp.ImportCollections(toImport, options.DeleteMissing)
+	})
+
+	return plan, err
+}
+
+// -------------------------------------------------------------------
+
+// cloneImportData shallow copies the import data maps because
+// prepareImportedCollections could modify them.
+func cloneImportData(toImport []map[string]any) []map[string]any {
+	result := make([]map[string]any, len(toImport))
+
+	for i, data := range toImport {
+		clone := make(map[string]any, len(data))
+		for k, v := range data {
+			clone[k] = v
+		}
+		result[i] = clone
+	}
+
+	return result
+}
+
+func deleteCollectionNote(c *Collection) string {
+	if c.IsView() {
+		return "the view will be dropped"
+	}
+
+	return "the table and all of its records will be dropped"
+}
+
+func diffCollections(old, new *Collection) ([]*SchemaChange, error) {
+	if old == nil {
+		return []*SchemaChange{{
+			Action:     SchemaChangeCreate,
+			Kind:       SchemaChangeKindCollection,
+			Collection: new.Name,
+			New:        new,
+			Note:       new.Type,
+		}}, nil
+	}
+
+	changes := []*SchemaChange{}
+
+	if old.Name != new.Name {
+		changes = append(changes, &SchemaChange{
+			Action:     SchemaChangeUpdate,
+			Kind:       SchemaChangeKindCollection,
+			Collection: new.Name,
+			Old:        old.Name,
+			New:        new.Name,
+			Note:       "renamed from " + old.Name,
+		})
+	}
+
+	if old.Type != new.Type {
+		changes = append(changes, &SchemaChange{
+			Action:      SchemaChangeUpdate,
+			Kind:        SchemaChangeKindCollection,
+			Collection:  new.Name,
+			Old:         old.Type,
+			New:         new.Type,
+			Note:        "type changed from " + old.Type + " to " + new.Type,
+			Destructive: old.IsView() != new.IsView(),
+		})
+	}
+
+	// the view fields are regenerated from the view query on save
+	// so only the viewQuery option change is reported
+	if !old.IsView() || !new.IsView() {
+		fieldChanges, err := diffCollectionFields(old, new)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, fieldChanges...)
+	}
+
+	changes = append(changes, diffCollectionIndexes(old, new)...)
+
+	changes = append(changes, diffCollectionRules(old, new)...)
+
+	optionChanges, err := diffCollectionOptions(old, new)
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, optionChanges...)
+
+	return changes, nil
+}
+
+func diffCollectionFields(old, new *Collection) ([]*SchemaChange, error) {
+	changes := []*SchemaChange{}
+
+	// view fields don't have their own data
+	hasData := !old.IsView() && !new.IsView()
+
+	for _, newField := range new.Fields {
+		oldField := old.Fields.GetById(newField.GetId())
+
+		if oldField == nil {
+			// a different field with the same name is going to be replaced
+			replaced := old.Fields.GetByName(newField.GetName())
+			if replaced != nil && new.Fields.GetById(replaced.GetId()) == nil {
+				note := fmt.Sprintf("replaced by a new field with id %q", newField.GetId())
+				if replaced.Type() != newField.Type() {
+					note = fmt.Sprintf("type changed from %s to %s", replaced.Type(), newField.Type())
+				}
+				if hasData {
+					note += ", the existing column data will be dropped"
+				}
+
+				changes = append(changes, &SchemaChange{
+					Action:      SchemaChangeUpdate,
+					Kind:        SchemaChangeKindField,
+					Collection:  new.Name,
+					Name:        newField.GetName(),
+					Old:         replaced,
+					New:         newField,
+					Note:        note,
+					Destructive: hasData,
+				})
+				continue
+			}
+
+			changes = append(changes, &SchemaChange{
+				Action:     SchemaChangeCreate,
+				Kind:       SchemaChangeKindField,
+				Collection: new.Name,
+				Name:       newField.GetName(),
+				New:        newField,
+				Note:       newField.Type(),
+			})
+			continue
+		}
+
+		equal, err := jsonEqual(oldField, newField)
+		if err != nil {
+			return nil, err
+		}
+		if equal && oldField.Type() == newField.Type() {
+			continue
+		}
+
+		change := &SchemaChange{
+			Action:     SchemaChangeUpdate,
+			Kind:       SchemaChangeKindField,
+			Collection: new.Name,
+			Name:       newField.GetName(),
+			Old:        oldField,
+			New:        newField,
+		}
+
+		notes := []string{}
+
+		if oldField.GetName() != newField.GetName() {
+			notes = append(notes, "renamed from "+oldField.GetName())
+		}
+
+		if oldField.Type() != newField.Type() {
+			notes = append(notes, fmt.Sprintf("type changed from %s to %s", oldField.Type(), newField.Type()))
+			change.Destructive = hasData
+		} else if isMultipleField(oldField) && !isMultipleField(newField) {
+			notes = append(notes, "changed from multiple to single value, only the last value of each record will be kept")
+			change.Destructive = hasData
+		}
+
+		if len(notes) == 0 {
+			notes = append(notes, "options changed")
+		}
+
+		change.Note = strings.Join(notes, "; ")
+
+		changes = append(changes, change)
+	}
+
+	for _, oldField := range old.Fields {
+		if new.Fields.GetById(oldField.GetId()) != nil {
+			continue
+		}
+
+		// already reported as replaced
+		replacement := new.Fields.GetByName(oldField.GetName())
+		if replacement != nil && old.Fields.GetById(replacement.GetId()) == nil {
+			continue
+		}
+
+		note := "the field will be removed"
+		if hasData {
+			note = "the column and its data will be dropped"
+		}
+
+		changes = append(changes, &SchemaChange{
+			Action:      SchemaChangeDelete,
+			Kind:        SchemaChangeKindField,
+			Collection:  new.Name,
+			Name:        oldField.GetName(),
+			Old:         oldField,
+			Note:        note,
+			Destructive: hasData,
+		})
+	}
+
+	return changes, nil
+}
+
+func isMultipleField(f Field) bool {
+	m, ok := f.(interface{ IsMultiple() bool })
+
+	return ok && m.IsMultiple()
+}
+
+func diffCollectionIndexes(old, new *Collection) []*SchemaChange {
+	changes := []*SchemaChange{}
+
+	oldIndexes := mappedIndexes(old.Indexes)
+	newIndexes := mappedIndexes(new.Indexes)
+
+	for _, name := range sortedKeys(newIndexes) {
+		oldIndex, ok := oldIndexes[name]
+		if !ok {
+			changes = append(changes, &SchemaChange{
+				Action:     SchemaChangeCreate,
+				Kind:       SchemaChangeKindIndex,
+				Collection: new.Name,
+				Name:       name,
+				New:        newIndexes[name],
+			})
+			continue
+		}
+
+		if normalizeIndex(oldIndex, old.Name, new.Name) != normalizeIndex(newIndexes[name], new.Name, new.Name) {
+			changes = append(changes, &SchemaChange{
+				Action:     SchemaChangeUpdate,
+				Kind:       SchemaChangeKindIndex,
+				Collection: new.Name,
+				Name:       name,
+				Old:        oldIndex,
+				New:        newIndexes[name],
+				Note:       "the index will be recreated",
+			})
+		}
+	}
+
+	for _, name := range sortedKeys(oldIndexes) {
+		if _, ok := newIndexes[name]; ok {
+			continue
+		}
+
+		changes = append(changes, &SchemaChange{
+			Action:     SchemaChangeDelete,
+			Kind:       SchemaChangeKindIndex,
+			Collection: new.Name,
+			Name:       name,
+			Old:        oldIndexes[name],
+		})
+	}
+
+	return changes
+}
+
+func mappedIndexes(indexes []string) map[string]string {
+	result := make(map[string]string, len(indexes))
+
+	for _, idx := range indexes {
+		parsed := dbutils.ParseIndex(idx)
+		if parsed.IndexName != "" {
+			result[parsed.IndexName] = idx
+		} else {
+			result[strings.TrimSpace(idx)] = idx
+		}
+	}
+
+	return result
+}
+
+// normalizeIndex returns a normalized index definition so that
+// formatting differences and collection renames are not reported.
+func normalizeIndex(idx string, tableName string, newTableName string) string {
+	parsed := dbutils.ParseIndex(idx)
+	if !parsed.IsValid() {
+		return strings.Join(strings.Fields(idx), " ")
+	}
+
+	if strings.EqualFold(parsed.TableName, tableName) {
+		parsed.TableName = newTableName
+	}
+
+	return parsed.Build()
+}
+
+func diffCollectionRules(old, new *Collection) []*SchemaChange {
+	changes := []*SchemaChange{}
+
+	type rule struct {
+		old  *string
+		new  *string
+		name string
+	}
+
+	rules := []rule{
+		{old.ListRule, new.ListRule, "listRule"},
+		{old.ViewRule, new.ViewRule, "viewRule"},
+		{old.CreateRule, new.CreateRule, "createRule"},
+		{old.UpdateRule, new.UpdateRule, "updateRule"},
+		{old.DeleteRule, new.DeleteRule, "deleteRule"},
+	}
+
+	if new.IsAuth() {
+		rules = append(rules,
+			rule{old.AuthRule, new.AuthRule, "authRule"},
+			rule{old.ManageRule, new.ManageRule, "manageRule"},
+		)
+	}
+
+	for _, r := range rules {
+		if (r.old == nil) == (r.new == nil) && (r.old == nil || *r.old == *r.new) {
+			continue
+		}
+
+		var note string
+		switch {
+		case r.new == nil:
+			note = "superusers only"
+		case *r.new == "":
+			note = "public"
+		}
+
+		changes = append(changes, &SchemaChange{
+			Action:     SchemaChangeUpdate,
+			Kind:       SchemaChangeKindRule,
+			Collection: new.Name,
+			Name:       r.name,
+			Old:        r.old,
+			New:        r.new,
+			Note:       note,
+		})
+	}
+
+	return changes
+}
+
+// schemaPlanNonOptionKeys lists the serialized collection keys
+// that are compared separately or shouldn't be compared at all.
+var schemaPlanNonOptionKeys = []string{
+	"id", "name", "type", "fields", "indexes", "created", "updated",
+	"listRule", "viewRule", "createRule", "updateRule", "deleteRule",
+	"authRule", "manageRule",
+}
+
+func diffCollectionOptions(old, new *Collection) ([]*SchemaChange, error) {
+	oldOptions, err := collectionOptionsMap(old)
+	if err != nil {
+		return nil, err
+	}
+
+	newOptions, err := collectionOptionsMap(new)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []*SchemaChange{}
+
+	for _, key := range sortedKeys(newOptions) {
+		if bytes.Equal(oldOptions[key], newOptions[key]) {
+			continue
+		}
+
+		change := &SchemaChange{
+			Action:     SchemaChangeUpdate,
+			Kind:       SchemaChangeKindOption,
+			Collection: new.Name,
+			Name:       key,
+			New:        newOptions[key],
+		}
+		if v, ok := oldOptions[key]; ok {
+			change.Old = v
+		}
+
+		changes = append(changes, change)
+	}
+
+	for _, key := range sortedKeys(oldOptions) {
+		if _, ok := newOptions[key]; ok {
+			continue
+		}
+
+		changes = append(changes, &SchemaChange{
+			Action:     SchemaChangeUpdate,
+			Kind:       SchemaChangeKindOption,
+			Collection: new.Name,
+			Name:       key,
+			Old:        oldOptions[key],
+		})
+	}
+
+	return changes, nil
+}
+
+func collectionOptionsMap(c *Collection) (map[string]json.RawMessage, error) {
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]json.RawMessage{}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, err
+	}
+
+	for _, key := range schemaPlanNonOptionKeys {
+		delete(result, key)
+	}
+
+	// normalize the nested objects keys order and formatting
+	for key, v := range result {
+		var decoded any
+		if err := json.Unmarshal(v, &decoded); err != nil {
+			return nil, err
+		}
+
+		normalized, err := json.Marshal(decoded)
+		if err != nil {
+			return nil, err
+		}
+
+		result[key] = normalized
+	}
+
+	return result, nil
+}
+
+func jsonEqual(a, b any) (bool, error) {
+	rawA, err := json.Marshal(a)
+	if err != nil {
+		return false, err
+	}
+
+	rawB, err := json.Marshal(b)
+	if err != nil {
+		return false, err
+	}
+
+	return bytes.Equal(rawA, rawB), nil
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
@@ -0,0 +1,271 @@
+package core_test
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func exportCollectionsData(t *testing.T, app core.App) []map[string]any {
+	collections, err := app.FindAllCollections()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := json.Marshal(collections)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := []map[string]any{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		t.Fatal(err)
+	}
+
+	return data
+}
+
+func findCollectionData(data []map[string]any, name string) map[string]any {
+	for _, c := range data {
+		if c["name"] == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func findFieldData(collection map[string]any, name string) map[string]any {
+	for _, f := range collection["fields"].([]any) {
+		field := f.(map[string]any)
+		if field["name"] == name {
+			return field
+		}
+	}
+	return nil
+}
+
+func removeFieldData(collection map[string]any, name string) {
+	collection["fields"] = slices.DeleteFunc(collection["fields"].([]any), func(f any) bool {
+		return f.(map[string]any)["name"] == name
+	})
+}
+
+func TestPlanSchema(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name            string
+		modify          func(data []map[string]any) []map[string]any
+		deleteMissing   bool
+		expectError     bool
+		expectChanges   []string
+		wantDestructive bool
+	}{
+		{
+			name:        "empty data",
+			modify:      func(data []map[string]any) []map[string]any { return nil },
+			expectError: true,
+		},
+		{
+			name:          "unchanged schema",
+			modify:        func(data []map[string]any) []map[string]any { return data },
+			deleteMissing: true,
+		},
+		{
+			name: "partial schema without deleteMissing",
+			modify: func(data []map[string]any) []map[string]any {
+				return []map[string]any{findCollectionData(data, "demo2")}
+			},
+		},
+		{
+			name: "partial schema with deleteMissing",
+			modify: func(data []map[string]any) []map[string]any {
+				return []map[string]any{findCollectionData(data, "demo2"), findCollectionData(data, "view1")}
+			},
+			deleteMissing: true,
+			expectChanges: []string{
+				"- delete collection users (the table and all of its records will be dropped) [destructive]",
+				"- delete collection demo1 (the table and all of its records will be dropped) [destructive]",
+				"- delete collection demo3 (the table and all of its records will be dropped) [destructive]",
+				"- delete collection demo4 (the table and all of its records will be dropped) [destructive]",
+				"- delete collection clients (the table and all of its records will be dropped) [destructive]",
+				"- delete collection demo5 (the table and all of its records will be dropped) [destructive]",
+				"- delete collection view2 (the view will be dropped)",
+				"- delete collection numeric_id_view (the view will be dropped)",
+			},
+			wantDestructive: true,
+		},
+		{
+			name: "new collection",
+			modify: func(data []map[string]any) []map[string]any {
+				return append(data, map[string]any{"name": "plan_new", "type": "base"})
+			},
+			expectChanges: []string{"+ create collection plan_new (base)"},
+		},
+		{
+			name: "fields, rules and indexes changes",
+			modify: func(data []map[string]any) []map[string]any {
+				demo2 := findCollectionData(data, "demo2")
+				demo2["listRule"] = nil
+				demo2["viewRule"] = "id != ''"
+				demo2["indexes"] = []any{
+					"CREATE INDEX `idx_demo2_created` ON `demo2` (`created`)",
+					"CREATE INDEX `idx_demo2_new` ON `demo2` (`title`, `active`)",
+					"CREATE INDEX \"idx_demo2_active\" ON \"demo2\" (\"active\", \"title\")",
+				}
+				findFieldData(demo2, "title")["name"] = "title_renamed"
+				removeFieldData(demo2, "active")
+				demo2["fields"] = append(demo2["fields"].([]any), map[string]any{"name": "plan_new_field", "type": "number"})
+
+				demo1 := findCollectionData(data, "demo1")
+				findFieldData(demo1, "select_many")["maxSelect"] = 1
+				findFieldData(demo1, "text")["max"] = 100
+				removeFieldData(demo1, "bool")
+				removeFieldData(demo1, "email")
+				demo1["fields"] = append(demo1["fields"].([]any),
+					map[string]any{"name": "bool", "type": "text"},
+					map[string]any{"id": "plan_email_new", "name": "email", "type": "email"},
+				)
+
+				return data
+			},
+			deleteMissing: true,
+			expectChanges: []string{
+				"~ update field demo1.text (options changed)",
+				"~ update field demo1.select_many (changed from multiple to single value, only the last value of each record will be kept) [destructive]",
+				"~ update field demo1.bool (type changed from bool to text, the existing column data will be dropped) [destructive]",
+				"~ update field demo1.email (replaced by a new field with id \"plan_email_new\", the existing column data will be dropped) [destructive]",
+				"~ update field demo2.title_renamed (renamed from title)",
+				"+ create field demo2.plan_new_field (number)",
+				"- delete field demo2.active (the column and its data will be dropped) [destructive]",
+				"~ update index demo2.idx_demo2_active (the index will be recreated)",
+				"+ create index demo2.idx_demo2_new",
+				"- delete index demo2.idx_unique_demo2_title",
+				"~ update rule demo2.listRule (superusers only)",
+				"~ update rule demo2.viewRule",
+			},
+			wantDestructive: true,
+		},
+		{
+			name: "view query change",
+			modify: func(data []map[string]any) []map[string]any {
+				view1 := findCollectionData(data, "view1")
+				view1["viewQuery"] = "select id from demo1"
+				view1["fields"] = []any{}
+				return data
+			},
+			deleteMissing: true,
+			expectChanges: []string{
+				"~ update option view1.viewQuery",
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			app, _ := tests.NewTestApp()
+			defer app.Cleanup()
+
+			data := s.modify(exportCollectionsData(t, app))
+
+			plan, err := core.PlanSchema(app, data, s.deleteMissing)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+			if hasErr {
+				return
+			}
+
+			changes := make([]string, len(plan.Changes))
+			for i, c := range plan.Changes {
+				changes[i] = c.String()
+			}
+
+			if len(changes) != len(s.expectChanges) {
+				t.Fatalf("Expected %d changes, got %d:\n%v", len(s.expectChanges), len(changes), changes)
+			}
+
+			for _, expected := range s.expectChanges {
+				if !slices.Contains(changes, expected) {
+					t.Fatalf("Missing change %q in\n%v", expected, changes)
+				}
+			}
+
+			if v := plan.HasDestructiveChanges(); v != s.wantDestructive {
+				t.Fatalf("Expected HasDestructiveChanges %v, got %v", s.wantDestructive, v)
+			}
+
+			if v := plan.HasChanges(); v != (len(s.expectChanges) > 0) {
+				t.Fatalf("Expected HasChanges %v, got %v", len(s.expectChanges) > 0, v)
+			}
+
+			// the database must remain unchanged
+			if _, err := app.FindCollectionByNameOrId("plan_new"); err == nil {
+				t.Fatal("Expected the plan_new collection to not be created")
+			}
+		})
+	}
+}
+
+func TestApplySchema(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	data := exportCollectionsData(t, app)
+
+	demo2 := findCollectionData(data, "demo2")
+	removeFieldData(demo2, "active")
+	demo2["fields"] = append(demo2["fields"].([]any), map[string]any{"name": "plan_new_field", "type": "number"})
+	demo2["indexes"] = []any{"CREATE INDEX `idx_demo2_created` ON `demo2` (`created`)"}
+
+	// destructive changes not allowed
+	plan, err := core.ApplySchema(app, data, core.SchemaApplyOptions{DeleteMissing: true})
+	if !errors.Is(err, core.ErrDestructiveSchemaChanges) {
+		t.Fatalf("Expected ErrDestructiveSchemaChanges, got %v", err)
+	}
+	if len(plan.DestructiveChanges()) != 1 {
+		t.Fatalf("Expected 1 destructive change, got %v", plan.DestructiveChanges())
+	}
+
+	collection, err := app.FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if collection.Fields.GetByName("active") == nil || collection.Fields.GetByName("plan_new_field") != nil {
+		t.Fatal("Expected the demo2 collection to remain unchanged")
+	}
+
+	// destructive changes allowed
+	plan, err = core.ApplySchema(app, data, core.SchemaApplyOptions{DeleteMissing: true, AllowDestructive: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Changes) != 4 {
+		t.Fatalf("Expected 4 applied changes, got %v", plan.Changes)
+	}
+
+	collection, err = app.FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if collection.Fields.GetByName("active") != nil || collection.Fields.GetByName("plan_new_field") == nil {
+		t.Fatal("Expected the demo2 collection to be updated")
+	}
+
+	// no drift after apply
+	plan, err = core.PlanSchema(app, data, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.HasChanges() {
+		t.Fatalf("Expected no changes after apply, got %v", plan.Changes)
+	}
+}
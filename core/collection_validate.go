@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -469,6 +470,162 @@ func (cv *collectionValidator) checkFieldsForUniqueIndex(value any) error {
 	return nil
 }
 
+// reservedSearchFieldNames lists the field names that are not allowed
+// to be searchable because they conflict with the FTS5 shadow table columns.
+var reservedSearchFieldNames = []string{FieldNameId, "rank", "rowid"}
+
+func (cv *collectionValidator) checkSearchConfig(value any) error {
+	config, _ := value.(*SearchConfig)
+	if config == nil {
+		return nil // nothing to check
+	}
+
+	// the fts() matches and snippets are not limited by the field
+	// visibility so the protected fields must not be searchable
+	viewRules := cv.new.FieldViewRules()
+
+	for i, name := range config.Fields {
+		var err error
+
+		field := cv.new.Fields.GetByName(name)
+		switch {
+		case field == nil:
+			err = validation.NewError("validation_missing_field", "Invalid or missing field {{.fieldName}}.").
+				SetParams(map[string]any{"fieldName": name})
+		case field.Type() != FieldTypeText && field.Type() != FieldTypeEditor:
+			err = validation.NewError("validation_invalid_search_field", "Only text and editor fields could be searchable.")
+		case isEncryptedField(field):
+			err = validation.NewError("validation_encrypted_search_field", "Encrypted fields cannot be searchable.")
+		case field.GetHidden():
+			err = validation.NewError("validation_hidden_search_field", "Hidden fields cannot be searchable.")
+		case viewRules[name] != "":
+			err = validation.NewError("validation_protected_search_field", "Fields with a view rule cannot be searchable.")
+		case list.ExistInSlice(strings.ToLower(name), reservedSearchFieldNames):
+			err = validation.NewError("validation_reserved_search_field", "The field {{.fieldName}} cannot be searchable.").
+				SetParams(map[string]any{"fieldName": name})
+		case slices.Index(config.Fields, name) != i:
+			err = validation.NewError("validation_duplicated_search_field", "Duplicated searchable field {{.fieldName}}.").
+				SetParams(map[string]any{"fieldName": name})
+		}
+
+		if err != nil {
+			return validation.Errors{"fields": validation.Errors{strconv.Itoa(i): err}}
+		}
+	}
+
+	return nil
+}
+
+func (cv *collectionValidator) checkTenantConfig(value any) error {
+	config, _ := value.(*TenantConfig)
+	if config == nil || !config.Enabled {
+		return nil // nothing to check
+	}
+
+	if config.CollectionId == "" {
+		return validation.Errors{"collectionId": validation.ErrRequired}
+	}
+
+	tenantCollection, _ := cv.app.FindCachedCollectionByNameOrId(config.CollectionId)
+
+	var err error
+	switch {
+	case tenantCollection == nil || tenantCollection.Id != config.CollectionId:
+		err = validation.NewError("validation_missing_tenant_collection", "Invalid or missing tenant collection.")
+	case tenantCollection.Id == cv.new.Id:
+		err = validation.NewError("validation_self_tenant_collection", "The collection cannot be its own tenant collection.")
+	case tenantCollection.IsView():
+		err = validation.NewError("validation_view_tenant_collection", "View collections cannot be used as tenant collection.")
+	case tenantCollection.TenantScoped():
+		err = validation.NewError("validation_scoped_tenant_collection", "The tenant collection cannot be tenant scoped itself.")
+	case !cv.new.IsNew() && cv.isTenantCollection():
+		err = validation.NewError("validation_scoped_tenant_collection", "The collection is used as tenant collection and cannot be tenant scoped itself.")
+	}
+
+	if err != nil {
+		return validation.Errors{"collectionId": err}
+	}
+
+	return nil
+}
+
+func (cv *collectionValidator) checkExpiryConfig(value any) error {
+	config, _ := value.(*ExpiryConfig)
+	if config == nil || !config.Enabled {
+		return nil // nothing to check
+	}
+
+	if config.Field == "" {
+		if config.TTL <= 0 {
+			return validation.Errors{"ttl": validation.NewError("validation_missing_expiry", "Either an expiry field or a TTL is required.")}
+		}
+
+		if _, ok := cv.new.Fields.GetByName(FieldNameCreated).(*AutodateField); !ok {
+			return validation.Errors{"ttl": validation.NewError("validation_missing_created_field", "The TTL requires a \"created\" autodate field.")}
+		}
+
+		return nil
+	}
+
+	if config.TTL > 0 {
+		return validation.Errors{"ttl": validation.NewError("validation_expiry_field_and_ttl", "The TTL cannot be used together with an expiry field.")}
+	}
+
+	field := cv.new.Fields.GetByName(config.Field)
+	if field == nil || (field.Type() != FieldTypeDate && field.Type() != FieldTypeAutodate) {
+		return validation.Errors{"field": validation.NewError("validation_invalid_expiry_field", "Invalid or missing date field {{.fieldName}}.").
+			SetParams(map[string]any{"fieldName": config.Field})}
+	}
+
+	return nil
+}
+
+// isTenantCollection reports whether the new collection is used
+// as tenant collection by any of the other collections.
+func (cv *collectionValidator) isTenantCollection() bool {
+	collections, _ := cv.app.FindAllCollections(CollectionTypeBase, CollectionTypeAuth)
+
+	for _, c := range collections {
+		if c.Id != cv.new.Id && c.TenantScoped() && c.Tenant.CollectionId == cv.new.Id {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (cv *collectionValidator) checkFieldRules(value any) error {
+	rules, _ := value.(map[string]FieldRule)
+	if len(rules) == 0 {
+		return nil // nothing to check
+	}
+
+	errs := validation.Errors{}
+
+	for name, rule := range rules {
+		field := cv.new.Fields.GetByName(name)
+		if field == nil || name == FieldNameId {
+			errs[name] = validation.NewError("validation_missing_field", "Invalid or missing field {{.fieldName}}.").
+				SetParams(map[string]any{"fieldName": name})
+			continue
+		}
+
+		err := validation.ValidateStruct(&rule,
+			validation.Field(&rule.ViewRule, validation.By(cv.checkRule)),
+			validation.Field(&rule.UpdateRule, validation.By(cv.checkRule)),
+		)
+		if err != nil {
+			errs[name] = err
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
 // note: value could be either *string or string
 func (validator *collectionValidator) checkRule(value any) error {
 	var vStr string
@@ -521,10 +678,10 @@ func (validator *collectionValidator) ensureNoSystemRuleChange(oldRule *string)
 func (cv *collectionValidator) checkIndexes(value any) error {
 	indexes, _ := value.(types.JSONArray[string])
 
-	if cv.new.IsView() && len(indexes) > 0 {
+	if cv.new.IsView() && !cv.new.IsMaterializedView() && len(indexes) > 0 {
 		return validation.NewError(
 			"validation_indexes_not_supported",
-			"View collections don't support indexes.",
+			"Only materialized view collections support indexes.",
 		)
 	}
 
@@ -590,6 +747,22 @@ func (cv *collectionValidator) checkIndexes(value any) error {
 		}
 		duplicatedDefinitions[parsedDef] = struct{}{}
 
+		// the encrypted values use a random nonce so
+		// the unique constraint cannot be enforced on them
+		if parsed.Unique {
+			for _, column := range parsed.Columns {
+				field := cv.new.Fields.GetByName(column.Name)
+				if field != nil && isEncryptedField(field) {
+					return validation.Errors{
+						strconv.Itoa(i): validation.NewError(
+							"validation_encrypted_unique_index",
+							"Unique indexes are not supported for the encrypted field {{.fieldName}}.",
+						).SetParams(map[string]any{"fieldName": field.GetName()}),
+					}
+				}
+			}
+		}
+
 		// note: we don't check the index table name because it is always
 		// overwritten by the SyncRecordTableSchema to allow
 		// easier partial modifications (eg. changing only the collection name).
@@ -685,10 +858,13 @@ func (cv *collectionValidator) checkIndexes(value any) error {
 func (validator *collectionValidator) validateOptions() error {
 	switch validator.new.Type {
 	case CollectionTypeAuth:
+		if err := validator.new.collectionBaseOptions.validate(validator); err != nil {
+			return err
+		}
 		return validator.new.collectionAuthOptions.validate(validator)
 	case CollectionTypeView:
 		return validator.new.collectionViewOptions.validate(validator)
 	}
 
-	return nil
+	return validator.new.collectionBaseOptions.validate(validator)
 }
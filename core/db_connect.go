@@ -3,10 +3,24 @@
 package core
 
 import (
+	"database/sql/driver"
+
 	"github.com/pocketbase/dbx"
-	_ "modernc.org/sqlite"
+	"github.com/pocketbase/pocketbase/tools/vector"
+	"modernc.org/sqlite"
 )
 
+func init() {
+	// register the custom vector distance function used by the vectorDistance() filter function
+	sqlite.MustRegisterDeterministicScalarFunction(
+		vector.SQLDistanceFunction,
+		3,
+		func(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+			return vector.SQLDistance(args[0], args[1], args[2])
+		},
+	)
+}
+
 func DefaultDBConnect(dbPath string) (*dbx.DB, error) {
 	// Note: the busy_timeout pragma must be first because
 	// the connection needs to be set to block on busy before WAL mode
@@ -155,6 +155,7 @@ const (
 	RequestInfoContextRealtime      = "realtime"
 	RequestInfoContextProtectedFile = "protectedFile"
 	RequestInfoContextBatch         = "batch"
+	RequestInfoContextGraphQL       = "graphql"
 	RequestInfoContextOAuth2        = "oauth2"
 	RequestInfoContextOTP           = "otp"
 	RequestInfoContextPasswordAuth  = "password"
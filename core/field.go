@@ -26,6 +26,9 @@ const (
 	FieldNameVerified        = "verified"
 	FieldNameTokenKey        = "tokenKey"
 	FieldNamePassword        = "password"
+	FieldNameDeleted         = "deleted"
+	FieldNameTenant          = "tenant"
+	FieldNameCreated         = "created"
 )
 
 // SystemFields returns special internal field names that are usually readonly.
@@ -160,12 +163,31 @@ type DriverValuer interface {
 	DriverValue(record *Record) (driver.Value, error)
 }
 
+// EncryptedValuer defines a field interface for fields which values
+// could be encrypted at rest (see [FieldEncryptionKeyEnv]).
+type EncryptedValuer interface {
+	// IsEncrypted checks whether the field values are encrypted at rest.
+	IsEncrypted() bool
+
+	// BlindIndexValue returns the normalized plain string representation
+	// of the raw value that is used for the field blind index.
+	BlindIndexValue(raw any) string
+}
+
 // MultiValuer defines a field interface that every multi-valued (eg. with MaxSelect) field has.
 type MultiValuer interface {
 	// IsMultiple checks whether the field is configured to support multiple or single values.
 	IsMultiple() bool
 }
 
+// GeneratedColumner defines a field interface for fields which column
+// value is generated by the database (aka. read-only fields that are
+// never exported for persistence).
+type GeneratedColumner interface {
+	// IsGeneratedColumn checks whether the field column is a DB generated column.
+	IsGeneratedColumn() bool
+}
+
 // RecordInterceptor defines a field interface for reacting to various
 // Record related operations (create, delete, validate, etc.).
 type RecordInterceptor interface {
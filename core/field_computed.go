@@ -0,0 +1,294 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/spf13/cast"
+)
+
+func init() {
+	Fields[FieldTypeComputed] = func() Field {
+		return &ComputedField{}
+	}
+}
+
+const FieldTypeComputed = "computed"
+
+// supported computed field value types
+const (
+	ComputedValueTypeText   = "text"
+	ComputedValueTypeNumber = "number"
+	ComputedValueTypeBool   = "bool"
+)
+
+var (
+	_ Field             = (*ComputedField)(nil)
+	_ SetterFinder      = (*ComputedField)(nil)
+	_ RecordInterceptor = (*ComputedField)(nil)
+	_ GeneratedColumner = (*ComputedField)(nil)
+)
+
+// ComputedField defines "computed" type field, aka. a read-only field
+// which value is calculated by the database from a SQL expression over
+// the other fields of the same record (e.g. "price * qty" or "lower(email)").
+//
+// The field is stored as SQLite generated column and could be used
+// in filters, sort expressions and indexes as any other regular field.
+//
+// The expression can reference only non-computed, non-hidden and non-encrypted
+// fields without a view rule of the same collection and must satisfy the SQLite generated columns restrictions
+// (e.g. no subqueries and only deterministic functions).
+//
+// The respective zero record field value depends on the ValueType
+// (empty string, 0 or false).
+type ComputedField struct {
+	// Name (required) is the unique name of the field.
+	Name string `form:"name" json:"name"`
+
+	// Id is the unique stable field identifier.
+	//
+	// It is automatically generated from the name when adding to a collection FieldsList.
+	Id string `form:"id" json:"id"`
+
+	// System prevents the renaming and removal of the field.
+	System bool `form:"system" json:"system"`
+
+	// Hidden hides the field from the API response.
+	Hidden bool `form:"hidden" json:"hidden"`
+
+	// Presentable hints the Dashboard UI to use the underlying
+	// field record value in the relation preview label.
+	Presentable bool `form:"presentable" json:"presentable"`
+
+	// ---
+
+	// Expression (required) is the SQL expression used to compute the field value.
+	Expression string `form:"expression" json:"expression"`
+
+	// ValueType specifies the type of the computed value
+	// (text, number or bool; default to text if empty).
+	ValueType string `form:"valueType" json:"valueType"`
+
+	// Stored specifies whether the computed value should be
+	// persisted on disk (STORED) or calculated on read (VIRTUAL).
+	//
+	// Note that changing it or adding a new stored field to
+	// an existing collection requires rebuilding the records table.
+	Stored bool `form:"stored" json:"stored"`
+}
+
+// Type implements [Field.Type] interface method.
+func (f *ComputedField) Type() string {
+	return FieldTypeComputed
+}
+
+// GetId implements [Field.GetId] interface method.
+func (f *ComputedField) GetId() string {
+	return f.Id
+}
+
+// SetId implements [Field.SetId] interface method.
+func (f *ComputedField) SetId(id string) {
+	f.Id = id
+}
+
+// GetName implements [Field.GetName] interface method.
+func (f *ComputedField) GetName() string {
+	return f.Name
+}
+
+// SetName implements [Field.SetName] interface method.
+func (f *ComputedField) SetName(name string) {
+	f.Name = name
+}
+
+// GetSystem implements [Field.GetSystem] interface method.
+func (f *ComputedField) GetSystem() bool {
+	return f.System
+}
+
+// SetSystem implements [Field.SetSystem] interface method.
+func (f *ComputedField) SetSystem(system bool) {
+	f.System = system
+}
+
+// GetHidden implements [Field.GetHidden] interface method.
+func (f *ComputedField) GetHidden() bool {
+	return f.Hidden
+}
+
+// SetHidden implements [Field.SetHidden] interface method.
+func (f *ComputedField) SetHidden(hidden bool) {
+	f.Hidden = hidden
+}
+
+// IsGeneratedColumn implements [GeneratedColumner] interface method.
+func (f *ComputedField) IsGeneratedColumn() bool {
+	return true
+}
+
+// ColumnType implements [Field.ColumnType] interface method.
+func (f *ComputedField) ColumnType(app App) string {
+	var affinity string
+	switch f.ValueType {
+	case ComputedValueTypeNumber:
+		affinity = "NUMERIC"
+	case ComputedValueTypeBool:
+		affinity = "BOOLEAN"
+	default:
+		affinity = "TEXT"
+	}
+
+	storage := "VIRTUAL"
+	if f.Stored {
+		storage = "STORED"
+	}
+
+	return fmt.Sprintf("%s GENERATED ALWAYS AS (%s) %s", affinity, f.Expression, storage)
+}
+
+// PrepareValue implements [Field.PrepareValue] interface method.
+func (f *ComputedField) PrepareValue(record *Record, raw any) (any, error) {
+	switch f.ValueType {
+	case ComputedValueTypeNumber:
+		return cast.ToFloat64(raw), nil
+	case ComputedValueTypeBool:
+		return cast.ToBool(raw), nil
+	default:
+		return cast.ToString(raw), nil
+	}
+}
+
+// ValidateValue implements [Field.ValidateValue] interface method.
+func (f *ComputedField) ValidateValue(ctx context.Context, app App, record *Record) error {
+	return nil // read-only
+}
+
+// ValidateSettings implements [Field.ValidateSettings] interface method.
+func (f *ComputedField) ValidateSettings(ctx context.Context, app App, collection *Collection) error {
+	return validation.ValidateStruct(f,
+		validation.Field(&f.Id, validation.By(DefaultFieldIdValidationRule)),
+		validation.Field(&f.Name, validation.By(DefaultFieldNameValidationRule)),
+		validation.Field(
+			&f.ValueType,
+			validation.In(ComputedValueTypeText, ComputedValueTypeNumber, ComputedValueTypeBool),
+		),
+		validation.Field(
+			&f.Expression,
+			validation.Required,
+			validation.Length(1, 1000),
+			validation.By(f.checkExpression(app, collection)),
+		),
+	)
+}
+
+func (f *ComputedField) checkExpression(app App, collection *Collection) validation.RuleFunc {
+	return func(value any) error {
+		v, _ := value.(string)
+		if v == "" {
+			return nil // nothing to check
+		}
+
+		if strings.Contains(v, ";") {
+			return validation.NewError("validation_invalid_computed_expression", "The expression must not contain ';'.")
+		}
+
+		// verify the expression by creating a temp table with the same
+		// non-computed columns so that any unknown field reference or
+		// unsupported generated column expression could be detected
+		//
+		// hidden, view rule protected and encrypted fields are also excluded
+		// because otherwise their values could be exposed through the computed field
+		viewRules := collection.FieldViewRules()
+		cols := []string{}
+		for _, field := range collection.Fields {
+			if gc, ok := field.(GeneratedColumner); ok && gc.IsGeneratedColumn() {
+				continue
+			}
+			if _, hasViewRule := viewRules[field.GetName()]; hasViewRule || field.GetHidden() || isEncryptedField(field) {
+				continue
+			}
+			cols = append(cols, "[["+field.GetName()+"]]")
+		}
+
+		tableName := "_pb_computed_check_" + security.PseudorandomString(6)
+
+		checkErr := app.RunInTransaction(func(txApp App) error {
+			_, err := txApp.DB().NewQuery(fmt.Sprintf(
+				"CREATE TEMP TABLE {{%s}} (%s, [[__pb_computed__]] %s)",
+				tableName,
+				strings.Join(cols, ", "),
+				f.ColumnType(txApp),
+			)).Execute()
+			if err != nil {
+				return err
+			}
+
+			_, err = txApp.DB().NewQuery("DROP TABLE temp.{{" + tableName + "}}").Execute()
+
+			return err
+		})
+		if checkErr != nil {
+			app.Logger().Debug("Invalid computed field expression", "expression", v, "error", checkErr)
+			return validation.NewError(
+				"validation_invalid_computed_expression",
+				"Invalid expression - it must be a deterministic SQL expression that references only existing non-computed, non-hidden and non-encrypted fields without a view rule.",
+			)
+		}
+
+		return nil
+	}
+}
+
+// FindSetter implements the [SetterFinder] interface.
+func (f *ComputedField) FindSetter(key string) SetterFunc {
+	switch key {
+	case f.Name:
+		// return noopSetter to disallow updating the value with record.Set()
+		return noopSetter
+	default:
+		return nil
+	}
+}
+
+// Intercept implements the [RecordInterceptor] interface.
+//
+// It reloads the computed field value after a successful record create or update.
+func (f *ComputedField) Intercept(
+	ctx context.Context,
+	app App,
+	record *Record,
+	actionName string,
+	actionFunc func() error,
+) error {
+	switch actionName {
+	case InterceptorActionCreateExecute, InterceptorActionUpdateExecute:
+		if err := actionFunc(); err != nil {
+			return err
+		}
+
+		var raw sql.NullString
+
+		err := app.DB().Select("[[" + f.Name + "]]").
+			From(record.Collection().Name).
+			Where(dbx.HashExp{FieldNameId: record.Id}).
+			Limit(1).
+			Row(&raw)
+		if err != nil {
+			return err
+		}
+
+		v, _ := f.PrepareValue(record, raw.String)
+		record.SetRaw(f.Name, v)
+
+		return nil
+	default:
+		return actionFunc()
+	}
+}
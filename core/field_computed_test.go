@@ -0,0 +1,300 @@
+package core_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/search"
+)
+
+func TestComputedFieldBaseMethods(t *testing.T) {
+	testFieldBaseMethods(t, core.FieldTypeComputed)
+}
+
+func TestComputedFieldColumnType(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		field    *core.ComputedField
+		expected string
+	}{
+		{
+			&core.ComputedField{Expression: "lower(a)"},
+			"TEXT GENERATED ALWAYS AS (lower(a)) VIRTUAL",
+		},
+		{
+			&core.ComputedField{Expression: "a * b", ValueType: core.ComputedValueTypeNumber, Stored: true},
+			"NUMERIC GENERATED ALWAYS AS (a * b) STORED",
+		},
+		{
+			&core.ComputedField{Expression: "a > 1", ValueType: core.ComputedValueTypeBool},
+			"BOOLEAN GENERATED ALWAYS AS (a > 1) VIRTUAL",
+		},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%s", i, s.expected), func(t *testing.T) {
+			if v := s.field.ColumnType(app); v != s.expected {
+				t.Fatalf("Expected\n%q\ngot\n%q", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestComputedFieldPrepareValue(t *testing.T) {
+	record := core.NewRecord(core.NewBaseCollection("test"))
+
+	scenarios := []struct {
+		valueType string
+		raw       any
+		expected  string
+	}{
+		{"", nil, `""`},
+		{"", 123, `"123"`},
+		{core.ComputedValueTypeText, "abc", `"abc"`},
+		{core.ComputedValueTypeNumber, "", `0`},
+		{core.ComputedValueTypeNumber, "1.5", `1.5`},
+		{core.ComputedValueTypeBool, "", `false`},
+		{core.ComputedValueTypeBool, "1", `true`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%s_%#v", i, s.valueType, s.raw), func(t *testing.T) {
+			f := &core.ComputedField{ValueType: s.valueType}
+
+			v, err := f.PrepareValue(record, s.raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if str := fmt.Sprintf("%#v", v); str != s.expected {
+				t.Fatalf("Expected %s, got %s", s.expected, str)
+			}
+		})
+	}
+}
+
+func TestComputedFieldValidateSettings(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test_collection")
+	collection.Fields.Add(
+		&core.NumberField{Name: "price"},
+		&core.NumberField{Name: "qty"},
+		&core.ComputedField{Name: "other", Expression: "price + 1"},
+		&core.NumberField{Name: "secret", Hidden: true},
+		&core.TextField{Name: "encrypted", Encrypted: true},
+		&core.NumberField{Name: "protected"},
+	)
+	collection.FieldRules = map[string]core.FieldRule{
+		"protected": {ViewRule: "@request.auth.id != ''"},
+	}
+
+	scenarios := []struct {
+		name         string
+		field        *core.ComputedField
+		expectErrors []string
+	}{
+		{
+			"empty expression",
+			&core.ComputedField{Id: "test", Name: "test"},
+			[]string{"expression"},
+		},
+		{
+			"invalid value type",
+			&core.ComputedField{Id: "test", Name: "test", Expression: "price", ValueType: "abc"},
+			[]string{"valueType"},
+		},
+		{
+			"multiple statements",
+			&core.ComputedField{Id: "test", Name: "test", Expression: "price; DROP TABLE demo1"},
+			[]string{"expression"},
+		},
+		{
+			"unknown field",
+			&core.ComputedField{Id: "test", Name: "test", Expression: "price * missing"},
+			[]string{"expression"},
+		},
+		{
+			"self reference",
+			&core.ComputedField{Id: "test", Name: "test", Expression: "test + 1"},
+			[]string{"expression"},
+		},
+		{
+			"computed field reference",
+			&core.ComputedField{Id: "test", Name: "test", Expression: "other + 1"},
+			[]string{"expression"},
+		},
+		{
+			"hidden field reference",
+			&core.ComputedField{Id: "test", Name: "test", Expression: "price * secret"},
+			[]string{"expression"},
+		},
+		{
+			"encrypted field reference",
+			&core.ComputedField{Id: "test", Name: "test", Expression: "lower(encrypted)"},
+			[]string{"expression"},
+		},
+		{
+			"view rule protected field reference",
+			&core.ComputedField{Id: "test", Name: "test", Expression: "price * protected"},
+			[]string{"expression"},
+		},
+		{
+			"non-deterministic function",
+			&core.ComputedField{Id: "test", Name: "test", Expression: "random()"},
+			[]string{"expression"},
+		},
+		{
+			"subquery",
+			&core.ComputedField{Id: "test", Name: "test", Expression: "(SELECT 1)"},
+			[]string{"expression"},
+		},
+		{
+			"valid expression",
+			&core.ComputedField{Id: "test", Name: "test", Expression: "price * qty", ValueType: core.ComputedValueTypeNumber, Stored: true},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			errs := s.field.ValidateSettings(context.Background(), app, collection)
+
+			tests.TestValidationErrors(t, errs, s.expectErrors)
+		})
+	}
+}
+
+func TestComputedFieldFindSetter(t *testing.T) {
+	field := &core.ComputedField{Name: "test"}
+
+	collection := core.NewBaseCollection("test_collection")
+	collection.Fields.Add(field)
+
+	record := core.NewRecord(collection)
+	record.SetRaw("test", "initial")
+
+	if f := field.FindSetter("abc"); f != nil {
+		t.Fatal("Expected nil setter")
+	}
+
+	record.Set("test", "new") // should be ignored
+
+	if v := record.GetString("test"); v != "initial" {
+		t.Fatalf("Expected no value change, got %q", v)
+	}
+}
+
+func TestComputedFieldRecordSave(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test_computed")
+	collection.Fields.Add(
+		&core.NumberField{Name: "price"},
+		&core.NumberField{Name: "qty"},
+		&core.TextField{Name: "email"},
+		&core.ComputedField{Name: "total", Expression: "price * qty", ValueType: core.ComputedValueTypeNumber},
+	)
+	collection.AddIndex("idx_test_computed_total", false, "total", "")
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	record := core.NewRecord(collection)
+	record.Set("price", 2.5)
+	record.Set("qty", 4)
+	record.Set("email", "TEST@example.com")
+	record.Set("total", 1000) // should be ignored
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	if v := record.GetFloat("total"); v != 10 {
+		t.Fatalf("Expected the computed value to be loaded after create, got %v", v)
+	}
+
+	record.Set("qty", 2)
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	if v := record.GetFloat("total"); v != 5 {
+		t.Fatalf("Expected the computed value to be reloaded after update, got %v", v)
+	}
+
+	// add a stored computed field to the existing collection (requires table rebuild)
+	collection.Fields.Add(&core.ComputedField{Name: "email_lower", Expression: "lower(email)", Stored: true})
+	collection.AddIndex("idx_test_computed_email_lower", true, "email_lower", "")
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := app.FindRecordById(collection, record.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := found.GetString("email_lower"); v != "test@example.com" {
+		t.Fatalf("Expected the stored computed value to be populated for the existing records, got %q", v)
+	}
+	if v := found.GetFloat("total"); v != 5 {
+		t.Fatalf("Expected the virtual computed value to be preserved, got %v", v)
+	}
+
+	// filter and sort
+	resolver := core.NewRecordFieldResolver(app, collection, nil, true)
+	records := []*core.Record{}
+	_, err = search.NewProvider(resolver).
+		Query(app.RecordQuery(collection)).
+		Filter([]search.FilterData{"total > 4 && email_lower = 'test@example.com'"}).
+		Sort(search.ParseSortFromString("-total")).
+		Exec(&records)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Id != record.Id {
+		t.Fatalf("Expected the record to be found by its computed fields, got %v", records)
+	}
+
+	// change the computed expression
+	collection.Fields.GetByName("total").(*core.ComputedField).Expression = "price + qty"
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	var total float64
+	err = app.DB().Select("total").From(collection.Name).Where(dbx.HashExp{"id": record.Id}).Row(&total)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 4.5 {
+		t.Fatalf("Expected the computed column to be recreated with the new expression, got %v", total)
+	}
+
+	// remove a computed field together with its referenced field
+	collection.Fields.RemoveByName("email_lower")
+	collection.Fields.RemoveByName("email")
+	collection.RemoveIndex("idx_test_computed_email_lower")
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	columns, err := app.TableColumns(collection.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, col := range columns {
+		if col == "email" || col == "email_lower" {
+			t.Fatalf("Expected column %q to be deleted", col)
+		}
+	}
+}
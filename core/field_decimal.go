@@ -0,0 +1,311 @@
+package core
+
+import (
+	"context"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func init() {
+	Fields[FieldTypeDecimal] = func() Field {
+		return &DecimalField{}
+	}
+}
+
+const FieldTypeDecimal = "decimal"
+
+// DecimalFieldMaxPrecision is the max allowed DecimalField precision.
+//
+// It is limited to the number of significant decimal digits that
+// could be represented exactly by a float64 so that the filter and sort
+// comparisons (which are performed on the REAL casted value) remain exact.
+const DecimalFieldMaxPrecision = 15
+
+var (
+	_ Field        = (*DecimalField)(nil)
+	_ SetterFinder = (*DecimalField)(nil)
+)
+
+// DecimalField defines "decimal" type field for storing exact
+// fixed-point decimal numbers (e.g. prices, amounts, etc.).
+//
+// The value is stored as its canonical string representation
+// (with exactly Scale decimal digits, e.g. "12.50").
+//
+// The respective zero record field value is 0 ([types.Decimal]).
+//
+// By default the value is serialized in JSON as string.
+// Enable JSONNumber to serialize it as JSON number instead.
+//
+// The following additional setter keys are available:
+//
+//   - "fieldName+" - exactly adds to the existing record value. For example:
+//     record.Set("total+", "0.10")
+//   - "fieldName-" - exactly subtracts from the existing record value. For example:
+//     record.Set("total-", "0.10")
+type DecimalField struct {
+	// Name (required) is the unique name of the field.
+	Name string `form:"name" json:"name"`
+
+	// Id is the unique stable field identifier.
+	//
+	// It is automatically generated from the name when adding to a collection FieldsList.
+	Id string `form:"id" json:"id"`
+
+	// System prevents the renaming and removal of the field.
+	System bool `form:"system" json:"system"`
+
+	// Hidden hides the field from the API response.
+	Hidden bool `form:"hidden" json:"hidden"`
+
+	// Presentable hints the Dashboard UI to use the underlying
+	// field record value in the relation preview label.
+	Presentable bool `form:"presentable" json:"presentable"`
+
+	// ---
+
+	// Precision specifies the max number of significant digits
+	// (including the Scale digits).
+	//
+	// If zero, fallbacks to DecimalFieldMaxPrecision.
+	Precision int `form:"precision" json:"precision"`
+
+	// Scale specifies the number of digits after the decimal point.
+	//
+	// Values with more decimal digits are rejected (unless the extra digits are zeros).
+	Scale int `form:"scale" json:"scale"`
+
+	// Min specifies the min allowed field value.
+	//
+	// Leave it nil to skip the validator.
+	Min *types.Decimal `form:"min" json:"min"`
+
+	// Max specifies the max allowed field value.
+	//
+	// Leave it nil to skip the validator.
+	Max *types.Decimal `form:"max" json:"max"`
+
+	// JSONNumber serializes the field value as JSON number instead of string.
+	//
+	// Note that some JSON consumers (e.g. JavaScript) may lose precision.
+	JSONNumber bool `form:"jsonNumber" json:"jsonNumber"`
+
+	// Required will require the field value to be non-zero.
+	Required bool `form:"required" json:"required"`
+}
+
+// Type implements [Field.Type] interface method.
+func (f *DecimalField) Type() string {
+	return FieldTypeDecimal
+}
+
+// GetId implements [Field.GetId] interface method.
+func (f *DecimalField) GetId() string {
+	return f.Id
+}
+
+// SetId implements [Field.SetId] interface method.
+func (f *DecimalField) SetId(id string) {
+	f.Id = id
+}
+
+// GetName implements [Field.GetName] interface method.
+func (f *DecimalField) GetName() string {
+	return f.Name
+}
+
+// SetName implements [Field.SetName] interface method.
+func (f *DecimalField) SetName(name string) {
+	f.Name = name
+}
+
+// GetSystem implements [Field.GetSystem] interface method.
+func (f *DecimalField) GetSystem() bool {
+	return f.System
+}
+
+// SetSystem implements [Field.SetSystem] interface method.
+func (f *DecimalField) SetSystem(system bool) {
+	f.System = system
+}
+
+// GetHidden implements [Field.GetHidden] interface method.
+func (f *DecimalField) GetHidden() bool {
+	return f.Hidden
+}
+
+// SetHidden implements [Field.SetHidden] interface method.
+func (f *DecimalField) SetHidden(hidden bool) {
+	f.Hidden = hidden
+}
+
+// ColumnType implements [Field.ColumnType] interface method.
+func (f *DecimalField) ColumnType(app App) string {
+	return "TEXT DEFAULT '0' NOT NULL"
+}
+
+// PrepareValue implements [Field.PrepareValue] interface method.
+func (f *DecimalField) PrepareValue(record *Record, raw any) (any, error) {
+	var val types.Decimal
+
+	if err := val.Scan(raw); err != nil {
+		return types.Decimal{}.WithJSONNumber(f.JSONNumber), err
+	}
+
+	return f.normalize(val), nil
+}
+
+// normalize adjusts the decimal to the field scale (if the conversion is exact)
+// and applies the field JSON serialization settings.
+func (f *DecimalField) normalize(val types.Decimal) types.Decimal {
+	scale := int32(f.Scale)
+
+	if val.Scale() <= scale {
+		val = val.Round(scale)
+	} else if rounded := val.Round(scale); rounded.Equal(val) {
+		val = rounded
+	}
+
+	return val.WithJSONNumber(f.JSONNumber)
+}
+
+// ValidateValue implements [Field.ValidateValue] interface method.
+func (f *DecimalField) ValidateValue(ctx context.Context, app App, record *Record) error {
+	val, ok := record.GetRaw(f.Name).(types.Decimal)
+	if !ok {
+		return validation.NewError("validation_invalid_decimal", "The submitted decimal number is not properly formatted.")
+	}
+
+	if val.IsZero() {
+		if f.Required {
+			return validation.ErrRequired
+		}
+		return nil
+	}
+
+	if val.Scale() > int32(f.Scale) {
+		return validation.NewError("validation_decimal_scale_constraint", "Must have no more than {{.scale}} decimal digits.").
+			SetParams(map[string]any{"scale": f.Scale})
+	}
+
+	if val.Precision() > f.precision() {
+		return validation.NewError("validation_decimal_precision_constraint", "Must have no more than {{.precision}} significant digits.").
+			SetParams(map[string]any{"precision": f.precision()})
+	}
+
+	if f.Min != nil && val.Cmp(*f.Min) < 0 {
+		return validation.NewError("validation_min_decimal_constraint", "Must be larger than {{.min}}.").
+			SetParams(map[string]any{"min": f.Min.String()})
+	}
+
+	if f.Max != nil && val.Cmp(*f.Max) > 0 {
+		return validation.NewError("validation_max_decimal_constraint", "Must be less than {{.max}}.").
+			SetParams(map[string]any{"max": f.Max.String()})
+	}
+
+	return nil
+}
+
+// ValidateSettings implements [Field.ValidateSettings] interface method.
+func (f *DecimalField) ValidateSettings(ctx context.Context, app App, collection *Collection) error {
+	return validation.ValidateStruct(f,
+		validation.Field(&f.Id, validation.By(DefaultFieldIdValidationRule)),
+		validation.Field(&f.Name, validation.By(DefaultFieldNameValidationRule)),
+		validation.Field(&f.Precision, validation.Min(0), validation.Max(DecimalFieldMaxPrecision)),
+		validation.Field(&f.Scale, validation.Min(0), validation.Max(f.precision())),
+		validation.Field(&f.Min, validation.By(f.checkBoundary)),
+		validation.Field(&f.Max, validation.By(f.checkBoundary), validation.By(f.checkMinMax)),
+	)
+}
+
+// precision returns the field precision or its default.
+func (f *DecimalField) precision() int {
+	if f.Precision <= 0 {
+		return DecimalFieldMaxPrecision
+	}
+
+	return f.Precision
+}
+
+func (f *DecimalField) checkBoundary(value any) error {
+	v, _ := value.(*types.Decimal)
+	if v == nil {
+		return nil // nothing to check
+	}
+
+	if v.Scale() > int32(f.Scale) && !v.Round(int32(f.Scale)).Equal(*v) {
+		return validation.NewError("validation_decimal_scale_constraint", "Must have no more than {{.scale}} decimal digits.").
+			SetParams(map[string]any{"scale": f.Scale})
+	}
+
+	return nil
+}
+
+func (f *DecimalField) checkMinMax(value any) error {
+	v, _ := value.(*types.Decimal)
+	if v == nil || f.Min == nil {
+		return nil // nothing to check
+	}
+
+	if v.Cmp(*f.Min) < 0 {
+		return validation.NewError("validation_min_greater_equal_than_required", "Must be greater than or equal to {{.threshold}}.").
+			SetParams(map[string]any{"threshold": f.Min.String()})
+	}
+
+	return nil
+}
+
+// FindSetter implements the [SetterFinder] interface.
+func (f *DecimalField) FindSetter(key string) SetterFunc {
+	switch key {
+	case f.Name:
+		return f.setValue
+	case f.Name + "+":
+		return f.addValue
+	case f.Name + "-":
+		return f.subtractValue
+	default:
+		return nil
+	}
+}
+
+func (f *DecimalField) setValue(record *Record, raw any) {
+	val, err := f.PrepareValue(record, raw)
+	if err != nil {
+		// store the invalid raw value as it is so that it could fail on validation
+		record.SetRaw(f.Name, raw)
+		return
+	}
+
+	record.SetRaw(f.Name, val)
+}
+
+func (f *DecimalField) addValue(record *Record, raw any) {
+	f.modifyValue(record, raw, types.Decimal.Add)
+}
+
+func (f *DecimalField) subtractValue(record *Record, raw any) {
+	f.modifyValue(record, raw, types.Decimal.Sub)
+}
+
+func (f *DecimalField) modifyValue(record *Record, raw any, op func(a, b types.Decimal) types.Decimal) {
+	current, ok := record.GetRaw(f.Name).(types.Decimal)
+	if !ok {
+		// try to parse the current value (e.g. nil or previously invalid)
+		prepared, err := f.PrepareValue(record, record.GetRaw(f.Name))
+		if err != nil {
+			return // the existing invalid value will fail on validation
+		}
+		current = prepared.(types.Decimal)
+	}
+
+	var val types.Decimal
+	if err := val.Scan(raw); err != nil {
+		// store the invalid raw value as it is so that it could fail on validation
+		record.SetRaw(f.Name, raw)
+		return
+	}
+
+	record.SetRaw(f.Name, f.normalize(op(current, val)))
+}
@@ -0,0 +1,531 @@
+package core_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/search"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestDecimalFieldBaseMethods(t *testing.T) {
+	testFieldBaseMethods(t, core.FieldTypeDecimal)
+}
+
+func TestDecimalFieldColumnType(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	f := &core.DecimalField{}
+
+	expected := "TEXT DEFAULT '0' NOT NULL"
+
+	if v := f.ColumnType(app); v != expected {
+		t.Fatalf("Expected\n%q\ngot\n%q", expected, v)
+	}
+}
+
+func TestDecimalFieldPrepareValue(t *testing.T) {
+	record := core.NewRecord(core.NewBaseCollection("test"))
+
+	scenarios := []struct {
+		field       *core.DecimalField
+		raw         any
+		expected    string
+		expectError bool
+	}{
+		{&core.DecimalField{}, nil, `"0"`, false},
+		{&core.DecimalField{}, "", `"0"`, false},
+		{&core.DecimalField{}, "abc", `"0"`, true},
+		{&core.DecimalField{}, "12.00", `"12"`, false},
+		{&core.DecimalField{}, "12.55", `"12.55"`, false}, // inexact scale adjustment
+		{&core.DecimalField{Scale: 2}, "12.5", `"12.50"`, false},
+		{&core.DecimalField{Scale: 2}, 0.1, `"0.10"`, false},
+		{&core.DecimalField{Scale: 2}, -3, `"-3.00"`, false},
+		{&core.DecimalField{Scale: 2}, "1.2300", `"1.23"`, false},
+		{&core.DecimalField{Scale: 2}, "1.234", `"1.234"`, false}, // inexact scale adjustment
+		{&core.DecimalField{Scale: 2, JSONNumber: true}, "1.5", `1.50`, false},
+		{&core.DecimalField{Scale: 2, JSONNumber: true}, types.MustParseDecimal("1"), `1.00`, false},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%#v", i, s.raw), func(t *testing.T) {
+			v, err := s.field.PrepareValue(record, s.raw)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if _, ok := v.(types.Decimal); !ok {
+				t.Fatalf("Expected types.Decimal instance, got %T", v)
+			}
+
+			raw, err := json.Marshal(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if str := string(raw); str != s.expected {
+				t.Fatalf("Expected %s, got %s", s.expected, str)
+			}
+		})
+	}
+}
+
+func TestDecimalFieldValidateValue(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test_collection")
+
+	scenarios := []struct {
+		name        string
+		field       *core.DecimalField
+		record      func() *core.Record
+		expectError bool
+	}{
+		{
+			"invalid raw value",
+			&core.DecimalField{Name: "test"},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.SetRaw("test", "123")
+				return record
+			},
+			true,
+		},
+		{
+			"invalid set value",
+			&core.DecimalField{Name: "test"},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.Set("test", "abc")
+				return record
+			},
+			true,
+		},
+		{
+			"zero field value (not required)",
+			&core.DecimalField{Name: "test"},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.SetRaw("test", types.Decimal{})
+				return record
+			},
+			false,
+		},
+		{
+			"zero field value (required)",
+			&core.DecimalField{Name: "test", Required: true},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.SetRaw("test", types.MustParseDecimal("0.00"))
+				return record
+			},
+			true,
+		},
+		{
+			"non-zero field value (required)",
+			&core.DecimalField{Name: "test", Required: true},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.SetRaw("test", types.MustParseDecimal("1"))
+				return record
+			},
+			false,
+		},
+		{
+			"> scale",
+			&core.DecimalField{Name: "test", Scale: 2},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.Set("test", "1.234")
+				return record
+			},
+			true,
+		},
+		{
+			"<= scale",
+			&core.DecimalField{Name: "test", Scale: 2},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.Set("test", "1.230")
+				return record
+			},
+			false,
+		},
+		{
+			"> precision",
+			&core.DecimalField{Name: "test", Precision: 4, Scale: 2},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.Set("test", "123.4")
+				return record
+			},
+			true,
+		},
+		{
+			"<= precision",
+			&core.DecimalField{Name: "test", Precision: 4, Scale: 2},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.Set("test", "-12.34")
+				return record
+			},
+			false,
+		},
+		{
+			"> default max precision",
+			&core.DecimalField{Name: "test"},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.Set("test", "1234567890123456")
+				return record
+			},
+			true,
+		},
+		{
+			"< min",
+			&core.DecimalField{Name: "test", Scale: 2, Min: types.Pointer(types.MustParseDecimal("0.10"))},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.Set("test", "0.09")
+				return record
+			},
+			true,
+		},
+		{
+			">= min",
+			&core.DecimalField{Name: "test", Scale: 2, Min: types.Pointer(types.MustParseDecimal("0.10"))},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.Set("test", "0.1")
+				return record
+			},
+			false,
+		},
+		{
+			"> max",
+			&core.DecimalField{Name: "test", Scale: 2, Max: types.Pointer(types.MustParseDecimal("0.10"))},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.Set("test", "0.11")
+				return record
+			},
+			true,
+		},
+		{
+			"<= max",
+			&core.DecimalField{Name: "test", Scale: 2, Max: types.Pointer(types.MustParseDecimal("0.10"))},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.Set("test", "-5")
+				return record
+			},
+			false,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			collection.Fields = core.NewFieldsList(s.field)
+
+			err := s.field.ValidateValue(context.Background(), app, s.record())
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+		})
+	}
+}
+
+func TestDecimalFieldValidateSettings(t *testing.T) {
+	testDefaultFieldIdValidation(t, core.FieldTypeDecimal)
+	testDefaultFieldNameValidation(t, core.FieldTypeDecimal)
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test_collection")
+
+	scenarios := []struct {
+		name         string
+		field        func() *core.DecimalField
+		expectErrors []string
+	}{
+		{
+			"zero",
+			func() *core.DecimalField {
+				return &core.DecimalField{Id: "test", Name: "test"}
+			},
+			[]string{},
+		},
+		{
+			"negative precision and scale",
+			func() *core.DecimalField {
+				return &core.DecimalField{Id: "test", Name: "test", Precision: -1, Scale: -1}
+			},
+			[]string{"precision", "scale"},
+		},
+		{
+			"precision > max",
+			func() *core.DecimalField {
+				return &core.DecimalField{Id: "test", Name: "test", Precision: core.DecimalFieldMaxPrecision + 1}
+			},
+			[]string{"precision"},
+		},
+		{
+			"scale > precision",
+			func() *core.DecimalField {
+				return &core.DecimalField{Id: "test", Name: "test", Precision: 4, Scale: 5}
+			},
+			[]string{"scale"},
+		},
+		{
+			"scale > default precision",
+			func() *core.DecimalField {
+				return &core.DecimalField{Id: "test", Name: "test", Scale: core.DecimalFieldMaxPrecision + 1}
+			},
+			[]string{"scale"},
+		},
+		{
+			"valid precision and scale",
+			func() *core.DecimalField {
+				return &core.DecimalField{Id: "test", Name: "test", Precision: 10, Scale: 10}
+			},
+			[]string{},
+		},
+		{
+			"min and max with more decimal digits than scale",
+			func() *core.DecimalField {
+				return &core.DecimalField{
+					Id:    "test",
+					Name:  "test",
+					Scale: 1,
+					Min:   types.Pointer(types.MustParseDecimal("0.15")),
+					Max:   types.Pointer(types.MustParseDecimal("1.25")),
+				}
+			},
+			[]string{"min", "max"},
+		},
+		{
+			"min and max with trailing zeros",
+			func() *core.DecimalField {
+				return &core.DecimalField{
+					Id:    "test",
+					Name:  "test",
+					Scale: 1,
+					Min:   types.Pointer(types.MustParseDecimal("0.100")),
+					Max:   types.Pointer(types.MustParseDecimal("1.200")),
+				}
+			},
+			[]string{},
+		},
+		{
+			"min > max",
+			func() *core.DecimalField {
+				return &core.DecimalField{
+					Id:    "test",
+					Name:  "test",
+					Scale: 2,
+					Min:   types.Pointer(types.MustParseDecimal("1.01")),
+					Max:   types.Pointer(types.MustParseDecimal("1")),
+				}
+			},
+			[]string{"max"},
+		},
+		{
+			"min <= max",
+			func() *core.DecimalField {
+				return &core.DecimalField{
+					Id:    "test",
+					Name:  "test",
+					Scale: 2,
+					Min:   types.Pointer(types.MustParseDecimal("1.00")),
+					Max:   types.Pointer(types.MustParseDecimal("1")),
+				}
+			},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			errs := s.field().ValidateSettings(context.Background(), app, collection)
+
+			tests.TestValidationErrors(t, errs, s.expectErrors)
+		})
+	}
+}
+
+func TestDecimalFieldFindSetter(t *testing.T) {
+	field := &core.DecimalField{Name: "test", Scale: 2}
+
+	collection := core.NewBaseCollection("test_collection")
+	collection.Fields.Add(field)
+
+	scenarios := []struct {
+		key      string
+		initial  any
+		value    any
+		expected string
+	}{
+		{"test", "2", "123.4", "123.40"},
+		{"test", "2", "invalid", "invalid"},
+		{"test+", "0.1", "0.2", "0.30"},
+		{"test+", "0.1", 0.2, "0.30"},
+		{"test+", "1.01", "-1.015", "-0.005"}, // inexact scale adjustment (should fail on validation)
+		{"test+", "1", "invalid", "invalid"},
+		{"test-", "0.3", "0.1", "0.20"},
+		{"test-", "1", 0.99, "0.01"},
+		{"test-", "1", "invalid", "invalid"},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%s_%v", i, s.key, s.value), func(t *testing.T) {
+			f := field.FindSetter(s.key)
+			if f == nil {
+				t.Fatal("Expected non-nil setter")
+			}
+
+			record := core.NewRecord(collection)
+			record.Set("test", s.initial)
+
+			f(record, s.value)
+
+			if v := fmt.Sprint(record.GetRaw("test")); v != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, v)
+			}
+		})
+	}
+
+	t.Run("no match", func(t *testing.T) {
+		if f := field.FindSetter("abc"); f != nil {
+			t.Fatal("Expected nil setter")
+		}
+	})
+}
+
+func TestDecimalFieldRecordSaveAndFilter(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test_decimals")
+	collection.Fields.Add(
+		&core.TextField{Name: "title"},
+		&core.DecimalField{Name: "amount", Scale: 2},
+		&core.DecimalField{Name: "amount_num", Scale: 2, JSONNumber: true},
+	)
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	data := map[string]string{
+		"a": "0.1",
+		"b": "10",
+		"c": "9.99",
+		"d": "-0.3",
+		"e": "0",
+	}
+	for title, amount := range data {
+		record := core.NewRecord(collection)
+		record.Set("title", title)
+		record.Set("amount", amount)
+		record.Set("amount_num", amount)
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// exact arithmetic
+	record, err := app.FindFirstRecordByData(collection, "title", "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	record.Set("amount+", "0.2")
+	record.Set("amount_num+", 0.2)
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	record, err = app.FindRecordById(collection, record.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var rawAmount string
+	err = app.DB().Select("amount").
+		From(collection.Name).
+		Where(dbx.HashExp{"id": record.Id}).
+		Row(&rawAmount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rawAmount != "0.30" {
+		t.Fatalf("Expected the stored amount to be 0.30, got %q", rawAmount)
+	}
+
+	exported, err := json.Marshal(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var exportedData map[string]any
+	if err := json.Unmarshal(exported, &exportedData); err != nil {
+		t.Fatal(err)
+	}
+	if v := exportedData["amount"]; v != "0.30" {
+		t.Fatalf("Expected amount JSON string 0.30, got %#v", v)
+	}
+	if v := exportedData["amount_num"]; v != 0.3 {
+		t.Fatalf("Expected amount_num JSON number 0.3, got %#v", v)
+	}
+
+	scenarios := []struct {
+		filter   string
+		sort     string
+		expected []string
+	}{
+		{"amount = 0.3", "title", []string{"a"}},
+		{"amount = '0.30'", "title", []string{"a"}},
+		{"amount = '0.3'", "title", []string{"a"}},
+		{"amount > 9.99", "title", []string{"b"}},
+		{"amount >= '9.99'", "title", []string{"b", "c"}},
+		{"amount < 0", "title", []string{"d"}},
+		{"amount != 0", "amount", []string{"d", "a", "c", "b"}},
+		{"id != ''", "-amount", []string{"b", "c", "a", "e", "d"}},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.filter+"_"+s.sort, func(t *testing.T) {
+			records := []*core.Record{}
+
+			resolver := core.NewRecordFieldResolver(app, collection, nil, true)
+			_, err := search.NewProvider(resolver).
+				Query(app.RecordQuery(collection)).
+				Filter([]search.FilterData{search.FilterData(s.filter)}).
+				Sort(search.ParseSortFromString(s.sort)).
+				Exec(&records)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			titles := make([]string, len(records))
+			for i, r := range records {
+				titles[i] = r.GetString("title")
+			}
+
+			if fmt.Sprint(titles) != fmt.Sprint(s.expected) {
+				t.Fatalf("Expected %v, got %v", s.expected, titles)
+			}
+		})
+	}
+}
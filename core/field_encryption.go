@@ -0,0 +1,532 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+// FieldEncryptionKeyEnv is the name of the env variable holding the
+// 32 characters AES key used for the encrypted record field values.
+const FieldEncryptionKeyEnv = "PB_FIELD_ENCRYPTION_KEY"
+
+// encryptedValuePrefix is the prefix of all encrypted field values.
+//
+// The stored encrypted value has the format:
+//
+//	pbenc:[32 chars blind index]:[base64 AES-256-GCM cipher text]
+const encryptedValuePrefix = "pbenc:"
+
+const blindIndexLength = 32
+
+// reencryptBatchSize is the number of records processed at once
+// when reencrypting the collection field values.
+const reencryptBatchSize = 500
+
+// isEncryptedField checks whether the provided field has enabled encryption at rest.
+func isEncryptedField(field Field) bool {
+	f, ok := field.(EncryptedValuer)
+
+	return ok && f.IsEncrypted()
+}
+
+// fieldEncryptionKey returns the field encryption key from the [FieldEncryptionKeyEnv] env variable.
+func fieldEncryptionKey() (string, error) {
+	key := os.Getenv(FieldEncryptionKeyEnv)
+
+	if err := validateFieldEncryptionKey(key); err != nil {
+		return "", fmt.Errorf("%s: %w", FieldEncryptionKeyEnv, err)
+	}
+
+	return key, nil
+}
+
+func validateFieldEncryptionKey(key string) error {
+	if key == "" {
+		return errors.New("missing field encryption key")
+	}
+
+	if len(key) != 32 {
+		return errors.New("the field encryption key must be exactly 32 characters")
+	}
+
+	return nil
+}
+
+// checkFieldEncryptionKey is a field settings validation rule
+// that ensures that a valid field encryption key is configured.
+func checkFieldEncryptionKey(value any) error {
+	enabled, _ := value.(bool)
+	if !enabled {
+		return nil // nothing to check
+	}
+
+	if _, err := fieldEncryptionKey(); err != nil {
+		return validation.NewError("validation_missing_encryption_key", "Missing or invalid {{.env}} field encryption key env variable.").
+			SetParams(map[string]any{"env": FieldEncryptionKeyEnv})
+	}
+
+	return nil
+}
+
+// isEncryptedValue checks whether the provided string is an encrypted field value.
+func isEncryptedValue(str string) bool {
+	return strings.HasPrefix(str, encryptedValuePrefix)
+}
+
+// fieldBlindIndex returns the blind index hash of the provided normalized
+// plain value (empty values are not hashed).
+func fieldBlindIndex(value string, key string) string {
+	if value == "" {
+		return ""
+	}
+
+	return security.HS256(value, security.HS256("pb_blind_index", key))[:blindIndexLength]
+}
+
+// blindIndexIdentifier wraps the provided column identifier with
+// an SQL expression that extracts the stored value blind index.
+func blindIndexIdentifier(identifier string) string {
+	return fmt.Sprintf("substr(%s, %d, %d)", identifier, len(encryptedValuePrefix)+1, blindIndexLength)
+}
+
+// blindIndexTransform returns a [search.ResolverResult.EqualityTransform]
+// function that converts the compared plain value to its blind index.
+func blindIndexTransform(field EncryptedValuer) func(value any) (any, error) {
+	return func(value any) (any, error) {
+		key, err := fieldEncryptionKey()
+		if err != nil {
+			return nil, err
+		}
+
+		return fieldBlindIndex(field.BlindIndexValue(value), key), nil
+	}
+}
+
+// encryptFieldValue encrypts the plain value and prefixes it with its blind index.
+func encryptFieldValue(field EncryptedValuer, plain string, key string) (string, error) {
+	cipherText, err := security.Encrypt([]byte(plain), key)
+	if err != nil {
+		return "", err
+	}
+
+	return encryptedValuePrefix + fieldBlindIndex(field.BlindIndexValue(plain), key) + ":" + cipherText, nil
+}
+
+// decryptFieldValue decrypts a value previously encrypted with [encryptFieldValue].
+func decryptFieldValue(value string, key string) (string, error) {
+	payload := strings.TrimPrefix(value, encryptedValuePrefix)
+
+	if len(payload) < blindIndexLength+1 || payload[blindIndexLength] != ':' {
+		return "", errors.New("invalid encrypted field value format")
+	}
+
+	plain, err := security.Decrypt(payload[blindIndexLength+1:], key)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt field value: %w", err)
+	}
+
+	return string(plain), nil
+}
+
+// decryptFieldValueWithEnvKey decrypts the provided value using the [FieldEncryptionKeyEnv] key.
+func decryptFieldValueWithEnvKey(value string) (string, error) {
+	key, err := fieldEncryptionKey()
+	if err != nil {
+		return "", err
+	}
+
+	return decryptFieldValue(value, key)
+}
+
+// encryptFieldValueWithEnvKey encrypts the provided value using the [FieldEncryptionKeyEnv] key.
+func encryptFieldValueWithEnvKey(field EncryptedValuer, plain string) (string, error) {
+	key, err := fieldEncryptionKey()
+	if err != nil {
+		return "", err
+	}
+
+	return encryptFieldValue(field, plain, key)
+}
+
+// ReencryptRecordFields decrypts with oldKey and encrypts with newKey
+// all stored encrypted field values of all collections, including their
+// copies in the record revisions and the records change log snapshots.
+//
+// Not yet encrypted values of the encrypted fields are also encrypted.
+//
+// The operation is performed in a single transaction and it doesn't
+// trigger any of the record hooks.
+//
+// Note that the [FieldEncryptionKeyEnv] env variable needs
+// to be updated with the new key after the rotation.
+func ReencryptRecordFields(app App, oldKey string, newKey string) error {
+	if err := validateFieldEncryptionKey(oldKey); err != nil {
+		return fmt.Errorf("old key: %w", err)
+	}
+
+	if err := validateFieldEncryptionKey(newKey); err != nil {
+		return fmt.Errorf("new key: %w", err)
+	}
+
+	return app.RunInTransaction(func(txApp App) error {
+		collections, err := txApp.FindAllCollections(CollectionTypeBase, CollectionTypeAuth)
+		if err != nil {
+			return err
+		}
+
+		for _, collection := range collections {
+			var fields []Field
+			for _, f := range collection.Fields {
+				if isEncryptedField(f) {
+					fields = append(fields, f)
+				}
+			}
+
+			if err := reencryptCollectionFields(txApp, collection, fields, oldKey, newKey); err != nil {
+				return fmt.Errorf("%s: %w", collection.Name, err)
+			}
+		}
+
+		// the revisions and the change log snapshots store a copy of the encrypted values
+		if err := reencryptRevisionSnapshots(txApp, collections, oldKey, newKey); err != nil {
+			return fmt.Errorf("%s: %w", CollectionNameRecordRevisions, err)
+		}
+
+		if err := reencryptChangeSnapshots(txApp, collections, oldKey, newKey); err != nil {
+			return fmt.Errorf("%s: %w", RecordChangesTable, err)
+		}
+
+		return nil
+	})
+}
+
+// syncEncryptedFieldValues encrypts or decrypts the stored values
+// of the collection fields with changed encryption option.
+func syncEncryptedFieldValues(app App, newCollection *Collection, oldCollection *Collection) error {
+	var changed []Field
+
+	for _, newField := range newCollection.Fields {
+		oldField := oldCollection.Fields.GetById(newField.GetId())
+		if oldField == nil || oldField.Type() != newField.Type() {
+			continue
+		}
+
+		if isEncryptedField(oldField) != isEncryptedField(newField) {
+			changed = append(changed, newField)
+		}
+	}
+
+	if len(changed) == 0 {
+		return nil // nothing to sync
+	}
+
+	key, err := fieldEncryptionKey()
+	if err != nil {
+		return err
+	}
+
+	return reencryptCollectionFields(app, newCollection, changed, key, key)
+}
+
+// reencryptCollectionFields reencrypts (or decrypts if the field
+// is no longer encrypted) the stored values of the specified collection fields.
+func reencryptCollectionFields(app App, collection *Collection, fields []Field, oldKey string, newKey string) error {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	cols := make([]string, 0, len(fields)+1)
+	cols = append(cols, "[["+FieldNameId+"]]")
+	for _, f := range fields {
+		cols = append(cols, "[["+f.GetName()+"]]")
+	}
+
+	var lastId string
+	for {
+		rows := []dbx.NullStringMap{}
+
+		err := app.DB().Select(cols...).
+			From(collection.Name).
+			Where(dbx.NewExp("[["+FieldNameId+"]] > {:lastId}", dbx.Params{"lastId": lastId})).
+			OrderBy(FieldNameId + " ASC").
+			Limit(reencryptBatchSize).
+			All(&rows)
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			lastId = row[FieldNameId].String
+
+			updates := dbx.Params{}
+
+			for _, f := range fields {
+				current := row[f.GetName()]
+				if !current.Valid || current.String == "" {
+					continue
+				}
+
+				newValue, err := reencryptFieldValue(f, current.String, oldKey, newKey)
+				if err != nil {
+					return fmt.Errorf("record %q field %q: %w", lastId, f.GetName(), err)
+				}
+
+				if newValue != current.String {
+					updates[f.GetName()] = newValue
+				}
+			}
+
+			if len(updates) == 0 {
+				continue
+			}
+
+			_, err := app.DB().Update(collection.Name, updates, dbx.HashExp{FieldNameId: lastId}).Execute()
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(rows) < reencryptBatchSize {
+			return nil
+		}
+	}
+}
+
+func reencryptFieldValue(field Field, value string, oldKey string, newKey string) (string, error) {
+	plain := value
+
+	if isEncryptedValue(value) {
+		var err error
+		plain, err = decryptFieldValue(value, oldKey)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if !isEncryptedField(field) || plain == "" {
+		return plain, nil
+	}
+
+	return encryptFieldValue(field.(EncryptedValuer), plain, newKey)
+}
+
+// reencryptRevisionSnapshots reencrypts the encrypted values
+// stored in the record revisions "data" and "diff" snapshots.
+func reencryptRevisionSnapshots(app App, collections []*Collection, oldKey string, newKey string) error {
+	collectionsById := make(map[string]*Collection, len(collections))
+	for _, c := range collections {
+		collectionsById[c.Id] = c
+	}
+
+	var lastId string
+	for {
+		rows := []struct {
+			Id            string `db:"id"`
+			CollectionRef string `db:"collectionRef"`
+			Data          string `db:"data"`
+			Diff          string `db:"diff"`
+		}{}
+
+		err := app.DB().Select("id", "collectionRef", "COALESCE([[data]], '') as data", "COALESCE([[diff]], '') as diff").
+			From(CollectionNameRecordRevisions).
+			Where(dbx.NewExp("[[id]] > {:lastId}", dbx.Params{"lastId": lastId})).
+			AndWhere(dbx.NewExp(
+				"(instr([[data]], {:prefix}) > 0 OR instr([[diff]], {:prefix}) > 0)",
+				dbx.Params{"prefix": encryptedValuePrefix},
+			)).
+			OrderBy("id ASC").
+			Limit(reencryptBatchSize).
+			All(&rows)
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			lastId = row.Id
+
+			collection := collectionsById[row.CollectionRef]
+
+			data, dataChanged, err := reencryptSnapshotData(collection, row.Data, oldKey, newKey)
+			if err != nil {
+				return fmt.Errorf("revision %q: %w", row.Id, err)
+			}
+
+			diff, diffChanged, err := reencryptSnapshotDiff(collection, row.Diff, oldKey, newKey)
+			if err != nil {
+				return fmt.Errorf("revision %q: %w", row.Id, err)
+			}
+
+			if !dataChanged && !diffChanged {
+				continue
+			}
+
+			_, err = app.DB().Update(
+				CollectionNameRecordRevisions,
+				dbx.Params{"data": data, "diff": diff},
+				dbx.HashExp{"id": row.Id},
+			).Execute()
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(rows) < reencryptBatchSize {
+			return nil
+		}
+	}
+}
+
+// reencryptChangeSnapshots reencrypts the encrypted values
+// stored in the records change log "data" snapshots.
+func reencryptChangeSnapshots(app App, collections []*Collection, oldKey string, newKey string) error {
+	collectionsById := make(map[string]*Collection, len(collections))
+	for _, c := range collections {
+		collectionsById[c.Id] = c
+	}
+
+	var lastCursor int64
+	for {
+		rows := []struct {
+			Cursor        int64  `db:"cursor"`
+			CollectionRef string `db:"collectionRef"`
+			Data          string `db:"data"`
+		}{}
+
+		err := app.DB().Select("cursor", "collectionRef", "data").
+			From(RecordChangesTable).
+			Where(dbx.NewExp("[[cursor]] > {:lastCursor}", dbx.Params{"lastCursor": lastCursor})).
+			AndWhere(dbx.NewExp("instr([[data]], {:prefix}) > 0", dbx.Params{"prefix": encryptedValuePrefix})).
+			OrderBy("cursor ASC").
+			Limit(reencryptBatchSize).
+			All(&rows)
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			lastCursor = row.Cursor
+
+			data, changed, err := reencryptSnapshotData(collectionsById[row.CollectionRef], row.Data, oldKey, newKey)
+			if err != nil {
+				return fmt.Errorf("change %d: %w", row.Cursor, err)
+			}
+
+			if !changed {
+				continue
+			}
+
+			_, err = app.DB().Update(
+				RecordChangesTable,
+				dbx.Params{"data": data},
+				dbx.HashExp{"cursor": row.Cursor},
+			).Execute()
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(rows) < reencryptBatchSize {
+			return nil
+		}
+	}
+}
+
+// reencryptSnapshotData reencrypts the encrypted values of
+// a serialized record fields snapshot (aka. {"field": value}).
+func reencryptSnapshotData(collection *Collection, rawData string, oldKey string, newKey string) (string, bool, error) {
+	data := map[string]json.RawMessage{}
+	if err := json.Unmarshal([]byte(rawData), &data); err != nil {
+		return rawData, false, nil // not a snapshot object
+	}
+
+	var changed bool
+	for name, raw := range data {
+		newRaw, ok, err := reencryptSnapshotValue(collection, name, raw, oldKey, newKey)
+		if err != nil {
+			return "", false, fmt.Errorf("field %q: %w", name, err)
+		}
+		if ok {
+			data[name] = newRaw
+			changed = true
+		}
+	}
+
+	if !changed {
+		return rawData, false, nil
+	}
+
+	encoded, err := json.Marshal(data)
+
+	return string(encoded), true, err
+}
+
+// reencryptSnapshotDiff reencrypts the encrypted values of
+// a serialized revision diff (aka. {"field": {"old": value, "new": value}}).
+func reencryptSnapshotDiff(collection *Collection, rawDiff string, oldKey string, newKey string) (string, bool, error) {
+	diff := map[string]map[string]json.RawMessage{}
+	if err := json.Unmarshal([]byte(rawDiff), &diff); err != nil {
+		return rawDiff, false, nil // not a diff object
+	}
+
+	var changed bool
+	for name, values := range diff {
+		for k, raw := range values {
+			newRaw, ok, err := reencryptSnapshotValue(collection, name, raw, oldKey, newKey)
+			if err != nil {
+				return "", false, fmt.Errorf("field %q: %w", name, err)
+			}
+			if ok {
+				values[k] = newRaw
+				changed = true
+			}
+		}
+	}
+
+	if !changed {
+		return rawDiff, false, nil
+	}
+
+	encoded, err := json.Marshal(diff)
+
+	return string(encoded), true, err
+}
+
+// reencryptSnapshotValue decrypts with oldKey and encrypts with newKey
+// the provided serialized snapshot value (if it is an encrypted string).
+//
+// The value remains encrypted even if the field was removed or it is
+// no longer encrypted so that the snapshots don't expose more than before.
+func reencryptSnapshotValue(collection *Collection, name string, raw json.RawMessage, oldKey string, newKey string) (json.RawMessage, bool, error) {
+	var str string
+	if len(raw) == 0 || raw[0] != '"' || json.Unmarshal(raw, &str) != nil || !isEncryptedValue(str) {
+		return raw, false, nil
+	}
+
+	plain, err := decryptFieldValue(str, oldKey)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var valuer EncryptedValuer = &TextField{}
+	if collection != nil {
+		if f, ok := collection.Fields.GetByName(name).(EncryptedValuer); ok {
+			valuer = f
+		}
+	}
+
+	encrypted, err := encryptFieldValue(valuer, plain, newKey)
+	if err != nil {
+		return nil, false, err
+	}
+
+	encoded, err := json.Marshal(encrypted)
+
+	return encoded, true, err
+}
@@ -0,0 +1,534 @@
+package core_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/search"
+)
+
+const (
+	testFieldEncryptionKey  = "abcdabcdabcdabcdabcdabcdabcdabcd"
+	testFieldEncryptionKey2 = "0123456789012345678901234567890a"
+)
+
+func createEncryptedTestCollection(t *testing.T, app core.App) *core.Collection {
+	collection := core.NewBaseCollection("test_encrypted")
+	collection.Fields.Add(
+		&core.TextField{Name: "title"},
+		&core.TextField{Name: "ssn", Encrypted: true},
+		&core.JSONField{Name: "notes", Encrypted: true},
+	)
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	data := []map[string]any{
+		{"title": "a", "ssn": "111-11-1111", "notes": map[string]any{"allergies": []string{"peanuts"}}},
+		{"title": "b", "ssn": "222-22-2222", "notes": "plain"},
+		{"title": "c", "ssn": "111-11-1111"},
+		{"title": "d"},
+	}
+
+	for _, d := range data {
+		record := core.NewRecord(collection)
+		record.Load(d)
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return collection
+}
+
+func findRawColumnValues(t *testing.T, app core.App, collection *core.Collection, column string) map[string]string {
+	rows := []dbx.NullStringMap{}
+
+	err := app.DB().Select("title", column).From(collection.Name).All(&rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := make(map[string]string, len(rows))
+	for _, row := range rows {
+		result[row["title"].String] = row[column].String
+	}
+
+	return result
+}
+
+func TestFieldEncryptionSaveAndLoad(t *testing.T) {
+	t.Setenv(core.FieldEncryptionKeyEnv, testFieldEncryptionKey)
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := createEncryptedTestCollection(t, app)
+
+	// raw db values
+	rawSSN := findRawColumnValues(t, app, collection, "ssn")
+	rawNotes := findRawColumnValues(t, app, collection, "notes")
+
+	for _, title := range []string{"a", "b", "c"} {
+		if !strings.HasPrefix(rawSSN[title], "pbenc:") || strings.Contains(rawSSN[title], "-11-") || strings.Contains(rawSSN[title], "-22-") {
+			t.Fatalf("[%s] Expected encrypted ssn db value, got %q", title, rawSSN[title])
+		}
+	}
+	if rawSSN["d"] != "" {
+		t.Fatalf("Expected the empty ssn to be stored as empty string, got %q", rawSSN["d"])
+	}
+	if rawSSN["a"] == rawSSN["c"] {
+		t.Fatal("Expected the same ssn values to have different cipher texts")
+	}
+	if rawSSN["a"][:38] != rawSSN["c"][:38] {
+		t.Fatalf("Expected the same ssn values to have the same blind index, got %q and %q", rawSSN["a"], rawSSN["c"])
+	}
+	if !strings.HasPrefix(rawNotes["a"], "pbenc:") || strings.Contains(rawNotes["a"], "peanuts") {
+		t.Fatalf("Expected encrypted notes db value, got %q", rawNotes["a"])
+	}
+	if rawNotes["c"] != "" {
+		t.Fatalf("Expected empty notes to be stored as NULL, got %q", rawNotes["c"])
+	}
+
+	// decrypted record values
+	record, err := app.FindFirstRecordByData(collection, "title", "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v := record.GetString("ssn"); v != "111-11-1111" {
+		t.Fatalf("Expected decrypted ssn, got %q", v)
+	}
+
+	if v := record.GetString("notes"); v != `{"allergies":["peanuts"]}` {
+		t.Fatalf("Expected decrypted notes, got %q", v)
+	}
+
+	// update
+	record.Set("ssn", "333-33-3333")
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	record, err = app.FindRecordById(collection, record.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := record.GetString("ssn"); v != "333-33-3333" {
+		t.Fatalf("Expected updated decrypted ssn, got %q", v)
+	}
+
+	// missing key
+	t.Setenv(core.FieldEncryptionKeyEnv, "")
+
+	if _, err := app.FindRecordById(collection, record.Id); err == nil {
+		t.Fatal("Expected the record load to fail with missing encryption key")
+	}
+
+	record.Set("ssn", "444-44-4444")
+	if err := app.Save(record); err == nil {
+		t.Fatal("Expected the record save to fail with missing encryption key")
+	}
+}
+
+func TestFieldEncryptionFilter(t *testing.T) {
+	t.Setenv(core.FieldEncryptionKeyEnv, testFieldEncryptionKey)
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := createEncryptedTestCollection(t, app)
+
+	scenarios := []struct {
+		filter      string
+		sort        string
+		expectError bool
+		expected    []string
+	}{
+		{"ssn = '111-11-1111'", "title", false, []string{"a", "c"}},
+		{"ssn != '111-11-1111'", "title", false, []string{"b", "d"}},
+		{"ssn = ''", "title", false, []string{"d"}},
+		{"ssn = null", "title", false, []string{"d"}},
+		{"ssn = 'missing'", "title", false, []string{}},
+		{"notes = 'plain'", "title", false, []string{"b"}},
+		{`notes = '{"allergies": ["peanuts"]}'`, "title", false, []string{"a"}},
+		{"notes = null", "title", false, []string{"c", "d"}},
+		{"ssn ~ '111'", "title", true, nil},
+		{"ssn > '111'", "title", true, nil},
+		{"ssn = title", "title", true, nil},
+		{"ssn:lower = '111-11-1111'", "title", true, nil},
+		{"notes.allergies ?= 'peanuts'", "title", true, nil},
+		{"id != ''", "ssn", true, nil},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.filter+"_"+s.sort, func(t *testing.T) {
+			records := []*core.Record{}
+
+			resolver := core.NewRecordFieldResolver(app, collection, nil, true)
+			_, err := search.NewProvider(resolver).
+				Query(app.RecordQuery(collection)).
+				Filter([]search.FilterData{search.FilterData(s.filter)}).
+				Sort(search.ParseSortFromString(s.sort)).
+				Exec(&records)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr {
+				return
+			}
+
+			titles := make([]string, len(records))
+			for i, r := range records {
+				titles[i] = r.GetString("title")
+			}
+
+			if fmt.Sprint(titles) != fmt.Sprint(s.expected) {
+				t.Fatalf("Expected %v, got %v", s.expected, titles)
+			}
+		})
+	}
+}
+
+func TestFieldEncryptionToggle(t *testing.T) {
+	t.Setenv(core.FieldEncryptionKeyEnv, testFieldEncryptionKey)
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := createEncryptedTestCollection(t, app)
+
+	// disable
+	collection.Fields.GetByName("ssn").(*core.TextField).Encrypted = false
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	rawSSN := findRawColumnValues(t, app, collection, "ssn")
+	if rawSSN["a"] != "111-11-1111" || rawSSN["b"] != "222-22-2222" || rawSSN["d"] != "" {
+		t.Fatalf("Expected the ssn values to be decrypted, got %v", rawSSN)
+	}
+
+	// the other encrypted fields should remain unchanged
+	rawNotes := findRawColumnValues(t, app, collection, "notes")
+	if !strings.HasPrefix(rawNotes["a"], "pbenc:") {
+		t.Fatalf("Expected the notes value to remain encrypted, got %q", rawNotes["a"])
+	}
+
+	// enable
+	collection.Fields.GetByName("ssn").(*core.TextField).Encrypted = true
+	collection.Fields.GetByName("title").(*core.TextField).Encrypted = true
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, column := range []string{"ssn", "title"} {
+		rows := []dbx.NullStringMap{}
+		if err := app.DB().Select(column).From(collection.Name).All(&rows); err != nil {
+			t.Fatal(err)
+		}
+		for _, row := range rows {
+			if v := row[column].String; v != "" && !strings.HasPrefix(v, "pbenc:") {
+				t.Fatalf("Expected encrypted %s value, got %q", column, v)
+			}
+		}
+	}
+
+	record, err := app.FindFirstRecordByFilter(collection, "title = 'a'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := record.GetString("ssn"); v != "111-11-1111" {
+		t.Fatalf("Expected decrypted ssn, got %q", v)
+	}
+}
+
+func TestReencryptRecordFields(t *testing.T) {
+	t.Setenv(core.FieldEncryptionKeyEnv, testFieldEncryptionKey)
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := createEncryptedTestCollection(t, app)
+
+	if err := core.ReencryptRecordFields(app, "", testFieldEncryptionKey2); err == nil {
+		t.Fatal("Expected error for missing old key")
+	}
+
+	if err := core.ReencryptRecordFields(app, testFieldEncryptionKey, "short"); err == nil {
+		t.Fatal("Expected error for invalid new key")
+	}
+
+	if err := core.ReencryptRecordFields(app, testFieldEncryptionKey2, testFieldEncryptionKey); err == nil {
+		t.Fatal("Expected error for wrong old key")
+	}
+
+	before := findRawColumnValues(t, app, collection, "ssn")
+
+	if err := core.ReencryptRecordFields(app, testFieldEncryptionKey, testFieldEncryptionKey2); err != nil {
+		t.Fatal(err)
+	}
+
+	after := findRawColumnValues(t, app, collection, "ssn")
+	if before["a"] == after["a"] || !strings.HasPrefix(after["a"], "pbenc:") {
+		t.Fatalf("Expected the ssn value to be reencrypted, got %q", after["a"])
+	}
+
+	// the old key should no longer work
+	if _, err := app.FindFirstRecordByData(collection, "title", "a"); err == nil {
+		t.Fatal("Expected the old key to fail decrypting the reencrypted values")
+	}
+
+	t.Setenv(core.FieldEncryptionKeyEnv, testFieldEncryptionKey2)
+
+	record, err := app.FindFirstRecordByFilter(collection, "ssn = '111-11-1111' && title = 'a'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := record.GetString("ssn"); v != "111-11-1111" {
+		t.Fatalf("Expected decrypted ssn, got %q", v)
+	}
+	if v := record.GetString("notes"); v != `{"allergies":["peanuts"]}` {
+		t.Fatalf("Expected decrypted notes, got %q", v)
+	}
+}
+
+func TestReencryptRecordFieldsSnapshots(t *testing.T) {
+	t.Setenv(core.FieldEncryptionKeyEnv, testFieldEncryptionKey)
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test_encrypted_snapshots")
+	collection.Fields.Add(
+		&core.TextField{Name: "title"},
+		&core.TextField{Name: "ssn", Encrypted: true},
+	)
+	collection.History = &core.HistoryConfig{Enabled: true}
+	collection.ChangeLog = &core.ChangeLogConfig{Enabled: true}
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	record := core.NewRecord(collection)
+	record.Set("title", "a")
+	record.Set("ssn", "111-11-1111")
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	// the encrypted field must not be reported as changed
+	record.Set("title", "b")
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	record.Set("ssn", "222-22-2222")
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	revisions, err := app.FindAllRecordRevisions(collection, record.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revisions) != 3 {
+		t.Fatalf("Expected 3 revisions, got %d", len(revisions))
+	}
+	if diff := revisions[1].Diff(); len(diff) != 1 || diff["title"].New != "b" {
+		t.Fatalf("Expected only the title diff, got %v", diff)
+	}
+	if diff := revisions[0].Diff(); len(diff) != 1 || diff["ssn"].New == nil {
+		t.Fatalf("Expected only the ssn diff, got %v", diff)
+	}
+
+	rawSnapshots := func() []string {
+		result := []string{}
+
+		var revisionsData []string
+		err := app.DB().Select("(COALESCE([[data]], '') || COALESCE([[diff]], ''))").From(core.CollectionNameRecordRevisions).
+			AndWhere(dbx.HashExp{"collectionRef": collection.Id}).
+			Column(&revisionsData)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var changesData []string
+		err = app.DB().Select("data").From(core.RecordChangesTable).
+			AndWhere(dbx.HashExp{"collectionRef": collection.Id}).
+			Column(&changesData)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return append(append(result, revisionsData...), changesData...)
+	}
+
+	before := rawSnapshots()
+
+	if err := core.ReencryptRecordFields(app, testFieldEncryptionKey, testFieldEncryptionKey2); err != nil {
+		t.Fatal(err)
+	}
+
+	after := rawSnapshots()
+	if len(after) != len(before) {
+		t.Fatalf("Expected %d snapshots, got %d", len(before), len(after))
+	}
+	for i := range before {
+		if before[i] == after[i] {
+			t.Fatalf("Expected snapshot %d to be reencrypted, got %s", i, after[i])
+		}
+	}
+
+	t.Setenv(core.FieldEncryptionKeyEnv, testFieldEncryptionKey2)
+
+	// revisions
+	revisions, err = app.FindAllRecordRevisions(collection, record.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, expected := range []string{"222-22-2222", "111-11-1111", "111-11-1111"} {
+		restored := core.NewRecord(collection)
+		if err := revisions[i].ApplyTo(restored); err != nil {
+			t.Fatalf("[revision %d] Failed to apply: %v", i, err)
+		}
+		if v := restored.GetString("ssn"); v != expected {
+			t.Fatalf("[revision %d] Expected ssn %q, got %q", i, expected, v)
+		}
+	}
+
+	// change log
+	changes, err := app.FindRecordChanges(0, []string{collection.Id}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 3 {
+		t.Fatalf("Expected 3 changes, got %d", len(changes))
+	}
+	for i, expected := range []string{"111-11-1111", "111-11-1111", "222-22-2222"} {
+		changed, err := changes[i].Record(collection)
+		if err != nil {
+			t.Fatalf("[change %d] Failed to load: %v", i, err)
+		}
+		if v := changed.GetString("ssn"); v != expected {
+			t.Fatalf("[change %d] Expected ssn %q, got %q", i, expected, v)
+		}
+	}
+}
+
+func TestFieldEncryptionValidateSettings(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test_collection")
+
+	scenarios := []struct {
+		name         string
+		key          string
+		field        core.Field
+		expectErrors []string
+	}{
+		{
+			"text field with missing key",
+			"",
+			&core.TextField{Id: "test", Name: "test", Encrypted: true},
+			[]string{"encrypted"},
+		},
+		{
+			"text field with invalid key",
+			"abc",
+			&core.TextField{Id: "test", Name: "test", Encrypted: true},
+			[]string{"encrypted"},
+		},
+		{
+			"text field with valid key",
+			testFieldEncryptionKey,
+			&core.TextField{Id: "test", Name: "test", Encrypted: true},
+			[]string{},
+		},
+		{
+			"encrypted primary key",
+			testFieldEncryptionKey,
+			&core.TextField{Id: "test", Name: "id", PrimaryKey: true, Required: true, Pattern: `\w+`, Encrypted: true},
+			[]string{"encrypted"},
+		},
+		{
+			"json field with missing key",
+			"",
+			&core.JSONField{Id: "test", Name: "test", Encrypted: true},
+			[]string{"encrypted"},
+		},
+		{
+			"json field with valid key",
+			testFieldEncryptionKey,
+			&core.JSONField{Id: "test", Name: "test", Encrypted: true},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			t.Setenv(core.FieldEncryptionKeyEnv, s.key)
+
+			collection.Fields = core.NewFieldsList(s.field)
+
+			errs := s.field.ValidateSettings(context.Background(), app, collection)
+
+			tests.TestValidationErrors(t, errs, s.expectErrors)
+		})
+	}
+}
+
+func TestFieldEncryptionSearchConfig(t *testing.T) {
+	t.Setenv(core.FieldEncryptionKeyEnv, testFieldEncryptionKey)
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test_search")
+	collection.Fields.Add(&core.TextField{Name: "ssn", Encrypted: true})
+	collection.Search = &core.SearchConfig{Fields: []string{"ssn"}}
+
+	err := app.Validate(collection)
+
+	tests.TestValidationErrors(t, err, []string{"search"})
+}
+
+func TestFieldEncryptionUniqueIndex(t *testing.T) {
+	t.Setenv(core.FieldEncryptionKeyEnv, testFieldEncryptionKey)
+
+	scenarios := []struct {
+		name           string
+		index          string
+		expectedErrors []string
+	}{
+		{"unique index on plain field", "CREATE UNIQUE INDEX idx_test_title ON test_unique (title)", []string{}},
+		{"non-unique index on encrypted field", "CREATE INDEX idx_test_ssn ON test_unique (ssn)", []string{}},
+		{"unique index on encrypted field", "CREATE UNIQUE INDEX idx_test_ssn ON test_unique (ssn)", []string{"indexes"}},
+		{"composite unique index with encrypted field", "CREATE UNIQUE INDEX idx_test_ssn ON test_unique (title, ssn)", []string{"indexes"}},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			app, _ := tests.NewTestApp()
+			defer app.Cleanup()
+
+			collection := core.NewBaseCollection("test_unique")
+			collection.Fields.Add(
+				&core.TextField{Name: "title"},
+				&core.TextField{Name: "ssn", Encrypted: true},
+			)
+			collection.Indexes = []string{s.index}
+
+			tests.TestValidationErrors(t, app.Validate(collection), s.expectedErrors)
+		})
+	}
+}
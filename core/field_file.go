@@ -249,6 +249,11 @@ func (f *FileField) ValidateValue(ctx context.Context, app App, record *Record)
 	existingStrings := list.ToInterfaceSlice(f.extractPlainStrings(files))
 	addedStrings := f.excludeFiles(existingStrings, oldExistingStrings)
 
+	// allow the files kept for the record revisions (eg. on revision restore)
+	if len(addedStrings) > 0 && record.Collection().HistoryEnabled() {
+		addedStrings = f.excludeFiles(addedStrings, list.ToInterfaceSlice(findRecordRevisionsFileNames(app, record)))
+	}
+
 	if len(addedStrings) > 0 {
 		invalidFiles := make([]string, len(addedStrings))
 		for i, invalid := range addedStrings {
@@ -474,6 +479,13 @@ func (f *FileField) processFilesToDelete(ctx context.Context, app App, record *R
 		return nil
 	}
 
+	// keep the old files as long as there are revisions referencing them
+	// (they are deleted together with the expired revisions)
+	if record.Collection().HistoryEnabled() {
+		record.SetRaw(deletedFilesPrefix+f.Name, nil)
+		return nil
+	}
+
 	old := list.ToInterfaceSlice(markedForDelete)
 	new := list.ToInterfaceSlice(f.extractPlainStrings(f.toSliceValue(record.GetRaw(f.Name))))
 	diff := f.excludeFiles(old, new)
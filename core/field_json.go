@@ -1,7 +1,10 @@
 package core
 
 import (
+	"bytes"
 	"context"
+	"database/sql/driver"
+	"encoding/json"
 	"slices"
 	"strconv"
 	"strings"
@@ -25,11 +28,17 @@ const DefaultJSONFieldMaxSize int64 = 1 << 20
 var (
 	_ Field                 = (*JSONField)(nil)
 	_ MaxBodySizeCalculator = (*JSONField)(nil)
+	_ DriverValuer          = (*JSONField)(nil)
+	_ EncryptedValuer       = (*JSONField)(nil)
 )
 
 // JSONField defines "json" type field for storing any serialized JSON value.
 //
 // The respective zero record field value is the zero [types.JSONRaw].
+//
+// If Encrypted is set, the non-empty field values are stored encrypted with
+// the [FieldEncryptionKeyEnv] key and only equality filter comparisons
+// (of the compacted JSON value) are allowed.
 type JSONField struct {
 	// Name (required) is the unique name of the field.
 	Name string `form:"name" json:"name"`
@@ -59,6 +68,14 @@ type JSONField struct {
 	// Required will require the field value to be non-empty JSON value
 	// (aka. not "null", `""`, "[]", "{}").
 	Required bool `form:"required" json:"required"`
+
+	// Encrypted will store the field values encrypted at rest using
+	// the key from the [FieldEncryptionKeyEnv] env variable.
+	//
+	// Encrypted fields cannot be sorted and could be filtered only with
+	// equality comparisons (=, !=, ?=, ?!=) against their blind index
+	// (nested JSON paths are not supported).
+	Encrypted bool `form:"encrypted" json:"encrypted"`
 }
 
 // Type implements [Field.Type] interface method.
@@ -113,6 +130,14 @@ func (f *JSONField) ColumnType(app App) string {
 
 // PrepareValue implements [Field.PrepareValue] interface method.
 func (f *JSONField) PrepareValue(record *Record, raw any) (any, error) {
+	if str, ok := raw.(string); ok && f.Encrypted && isEncryptedValue(str) {
+		decrypted, err := decryptFieldValueWithEnvKey(str)
+		if err != nil {
+			return types.JSONRaw{}, err
+		}
+		return types.ParseJSONRaw(decrypted)
+	}
+
 	if str, ok := raw.(string); ok {
 		// in order to support seamlessly both json and multipart/form-data requests,
 		// the following normalization rules are applied for plain string values:
@@ -182,9 +207,48 @@ func (f *JSONField) ValidateSettings(ctx context.Context, app App, collection *C
 		validation.Field(&f.Id, validation.By(DefaultFieldIdValidationRule)),
 		validation.Field(&f.Name, validation.By(DefaultFieldNameValidationRule)),
 		validation.Field(&f.MaxSize, validation.Min(0), validation.Max(maxSafeJSONInt)),
+		validation.Field(&f.Encrypted, validation.By(checkFieldEncryptionKey)),
 	)
 }
 
+// DriverValue implements the [DriverValuer] interface.
+func (f *JSONField) DriverValue(record *Record) (driver.Value, error) {
+	raw := record.GetRaw(f.Name)
+
+	if !f.Encrypted {
+		return raw, nil
+	}
+
+	jsonRaw, _ := raw.(types.JSONRaw)
+	if len(jsonRaw) == 0 {
+		return nil, nil
+	}
+
+	return encryptFieldValueWithEnvKey(f, string(jsonRaw))
+}
+
+// IsEncrypted implements the [EncryptedValuer] interface.
+func (f *JSONField) IsEncrypted() bool {
+	return f.Encrypted
+}
+
+// BlindIndexValue implements the [EncryptedValuer] interface.
+//
+// The raw value is normalized the same way as with [JSONField.PrepareValue]
+// and the resulting JSON is compacted.
+func (f *JSONField) BlindIndexValue(raw any) string {
+	prepared, _ := f.PrepareValue(nil, raw)
+
+	jsonRaw, _ := prepared.(types.JSONRaw)
+
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, jsonRaw); err != nil {
+		return string(jsonRaw)
+	}
+
+	return buf.String()
+}
+
 // CalculateMaxBodySize implements the [MaxBodySizeCalculator] interface.
 func (f *JSONField) CalculateMaxBodySize() int64 {
 	if f.MaxSize <= 0 {
@@ -218,12 +218,21 @@ func (f *RelationField) ValidateValue(ctx context.Context, app App, record *Reco
 		return validation.NewError("validation_missing_rel_collection", "Relation connection is missing or cannot be accessed")
 	}
 
-	var total int
-	_ = app.ConcurrentDB().
+	query := app.ConcurrentDB().
 		Select("count(*)").
 		From(relCollection.Name).
-		AndWhere(dbx.In("id", list.ToInterfaceSlice(ids)...)).
-		Row(&total)
+		AndWhere(dbx.In("id", list.ToInterfaceSlice(ids)...))
+
+	// tenant scoped records can reference only records from the same tenant
+	// (the error is intentionally the same as for the missing records)
+	if tenantId := record.TenantId(); tenantId != "" &&
+		relCollection.TenantScoped() &&
+		relCollection.Tenant.CollectionId == record.Collection().Tenant.CollectionId {
+		query.AndWhere(dbx.HashExp{FieldNameTenant: tenantId})
+	}
+
+	var total int
+	_ = query.Row(&total)
 	if total != len(ids) {
 		return validation.NewError("validation_missing_rel_records", "Failed to find all relation records with the provided ids")
 	}
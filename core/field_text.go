@@ -3,6 +3,7 @@ package core
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"fmt"
 	"regexp"
@@ -29,12 +30,17 @@ var (
 	_ Field             = (*TextField)(nil)
 	_ SetterFinder      = (*TextField)(nil)
 	_ RecordInterceptor = (*TextField)(nil)
+	_ DriverValuer      = (*TextField)(nil)
+	_ EncryptedValuer   = (*TextField)(nil)
 )
 
 // TextField defines "text" type field for storing any string value.
 //
 // The respective zero record field value is empty string.
 //
+// If Encrypted is set, the non-empty field values are stored encrypted with
+// the [FieldEncryptionKeyEnv] key and only equality filter comparisons are allowed.
+//
 // The following additional setter keys are available:
 //
 // - "fieldName:autogenerate" - autogenerate field value if AutogeneratePattern is set. For example:
@@ -91,6 +97,13 @@ type TextField struct {
 	//
 	// A single collection can have only 1 field marked as primary key.
 	PrimaryKey bool `form:"primaryKey" json:"primaryKey"`
+
+	// Encrypted will store the field values encrypted at rest using
+	// the key from the [FieldEncryptionKeyEnv] env variable.
+	//
+	// Encrypted fields cannot be sorted and could be filtered only with
+	// equality comparisons (=, !=, ?=, ?!=) against their blind index.
+	Encrypted bool `form:"encrypted" json:"encrypted"`
 }
 
 // Type implements [Field.Type] interface method.
@@ -152,7 +165,43 @@ func (f *TextField) ColumnType(app App) string {
 
 // PrepareValue implements [Field.PrepareValue] interface method.
 func (f *TextField) PrepareValue(record *Record, raw any) (any, error) {
-	return cast.ToString(raw), nil
+	str := cast.ToString(raw)
+
+	if f.Encrypted && isEncryptedValue(str) {
+		decrypted, err := decryptFieldValueWithEnvKey(str)
+		if err != nil {
+			return str, err
+		}
+		return decrypted, nil
+	}
+
+	return str, nil
+}
+
+// DriverValue implements the [DriverValuer] interface.
+func (f *TextField) DriverValue(record *Record) (driver.Value, error) {
+	raw := record.GetRaw(f.Name)
+
+	if !f.Encrypted {
+		return raw, nil
+	}
+
+	str := cast.ToString(raw)
+	if str == "" {
+		return str, nil
+	}
+
+	return encryptFieldValueWithEnvKey(f, str)
+}
+
+// IsEncrypted implements the [EncryptedValuer] interface.
+func (f *TextField) IsEncrypted() bool {
+	return f.Encrypted
+}
+
+// BlindIndexValue implements the [EncryptedValuer] interface.
+func (f *TextField) BlindIndexValue(raw any) string {
+	return cast.ToString(raw)
 }
 
 var forbiddenPKChars = []string{"/", "\\"}
@@ -265,6 +314,7 @@ func (f *TextField) ValidateSettings(ctx context.Context, app App, collection *C
 		validation.Field(&f.Hidden, validation.When(f.PrimaryKey, validation.Empty)),
 		validation.Field(&f.Required, validation.When(f.PrimaryKey, validation.Required)),
 		validation.Field(&f.AutogeneratePattern, validation.By(validators.IsRegex), validation.By(f.checkAutogeneratePattern)),
+		validation.Field(&f.Encrypted, validation.When(f.PrimaryKey, validation.Empty), validation.By(checkFieldEncryptionKey)),
 	)
 }
 
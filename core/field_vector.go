@@ -0,0 +1,192 @@
+package core
+
+import (
+	"context"
+	"math"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core/validators"
+	"github.com/pocketbase/pocketbase/tools/list"
+	"github.com/pocketbase/pocketbase/tools/types"
+	"github.com/pocketbase/pocketbase/tools/vector"
+)
+
+func init() {
+	Fields[FieldTypeVector] = func() Field {
+		return &VectorField{}
+	}
+}
+
+const FieldTypeVector = "vector"
+
+// VectorFieldMaxDimensions is the max allowed VectorField dimensions.
+const VectorFieldMaxDimensions = 16000
+
+var (
+	_ Field = (*VectorField)(nil)
+)
+
+// VectorField defines "vector" type field for storing fixed length
+// float32 vectors (e.g. text embeddings).
+//
+// The value is stored as compact little-endian float32 blob and
+// could be compared in filter and sort expressions with the
+// vectorDistance() function, e.g.:
+//
+//	vectorDistance(embedding, @request.query.q, 'cosine') < 0.3
+//
+// If Index is enabled, an in-memory HNSW index is maintained for the
+// field allowing approximate nearest neighbor filtering with:
+//
+//	vectorNearest(embedding, @request.query.q, 10) = true
+//
+// You can set the record field value as [types.Vector], slice of numbers,
+// serialized json array or float32 blob.
+// The stored value is always converted to [types.Vector].
+// Nil, empty slice, empty string, etc. results in empty [types.Vector].
+//
+// Examples of updating a record's VectorField value programmatically:
+//
+//	record.Set("embedding", types.Vector{0.1, 0.2, 0.3})
+//	record.Set("embedding", []float64{0.1, 0.2, 0.3})
+//	record.Set("embedding", "[0.1, 0.2, 0.3]")
+type VectorField struct {
+	// Name (required) is the unique name of the field.
+	Name string `form:"name" json:"name"`
+
+	// Id is the unique stable field identifier.
+	//
+	// It is automatically generated from the name when adding to a collection FieldsList.
+	Id string `form:"id" json:"id"`
+
+	// System prevents the renaming and removal of the field.
+	System bool `form:"system" json:"system"`
+
+	// Hidden hides the field from the API response.
+	Hidden bool `form:"hidden" json:"hidden"`
+
+	// Presentable hints the Dashboard UI to use the underlying
+	// field record value in the relation preview label.
+	Presentable bool `form:"presentable" json:"presentable"`
+
+	// ---
+
+	// Dimensions (required) specifies the exact vector length.
+	Dimensions int `form:"dimensions" json:"dimensions"`
+
+	// Index enables the in-memory approximate nearest neighbor
+	// index of the field (used by the vectorNearest() filter function).
+	//
+	// The index is persisted in the app data directory on terminate
+	// and it is rebuilt from the database on start if missing or stale.
+	Index bool `form:"index" json:"index"`
+
+	// Metric specifies the distance metric of the field index
+	// ("cosine", "l2" or "dot"; default to "cosine" if empty).
+	Metric string `form:"metric" json:"metric"`
+
+	// Required will require the field value to be non-empty vector.
+	Required bool `form:"required" json:"required"`
+}
+
+// Type implements [Field.Type] interface method.
+func (f *VectorField) Type() string {
+	return FieldTypeVector
+}
+
+// GetId implements [Field.GetId] interface method.
+func (f *VectorField) GetId() string {
+	return f.Id
+}
+
+// SetId implements [Field.SetId] interface method.
+func (f *VectorField) SetId(id string) {
+	f.Id = id
+}
+
+// GetName implements [Field.GetName] interface method.
+func (f *VectorField) GetName() string {
+	return f.Name
+}
+
+// SetName implements [Field.SetName] interface method.
+func (f *VectorField) SetName(name string) {
+	f.Name = name
+}
+
+// GetSystem implements [Field.GetSystem] interface method.
+func (f *VectorField) GetSystem() bool {
+	return f.System
+}
+
+// SetSystem implements [Field.SetSystem] interface method.
+func (f *VectorField) SetSystem(system bool) {
+	f.System = system
+}
+
+// GetHidden implements [Field.GetHidden] interface method.
+func (f *VectorField) GetHidden() bool {
+	return f.Hidden
+}
+
+// SetHidden implements [Field.SetHidden] interface method.
+func (f *VectorField) SetHidden(hidden bool) {
+	f.Hidden = hidden
+}
+
+// ColumnType implements [Field.ColumnType] interface method.
+func (f *VectorField) ColumnType(app App) string {
+	return "BLOB DEFAULT x'' NOT NULL"
+}
+
+// PrepareValue implements [Field.PrepareValue] interface method.
+func (f *VectorField) PrepareValue(record *Record, raw any) (any, error) {
+	return types.ParseVector(raw)
+}
+
+// ValidateValue implements [Field.ValidateValue] interface method.
+func (f *VectorField) ValidateValue(ctx context.Context, app App, record *Record) error {
+	val, ok := record.GetRaw(f.Name).(types.Vector)
+	if !ok {
+		return validators.ErrUnsupportedValueType
+	}
+
+	if len(val) == 0 {
+		if f.Required {
+			return validation.ErrRequired
+		}
+		return nil
+	}
+
+	if len(val) != f.Dimensions {
+		return validation.NewError("validation_invalid_vector_dimensions", "The vector must have exactly {{.dimensions}} dimensions.").
+			SetParams(map[string]any{"dimensions": f.Dimensions})
+	}
+
+	for _, v := range val {
+		if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+			return validation.NewError("validation_invalid_vector_value", "The vector must contain only finite numbers.")
+		}
+	}
+
+	return nil
+}
+
+// ValidateSettings implements [Field.ValidateSettings] interface method.
+func (f *VectorField) ValidateSettings(ctx context.Context, app App, collection *Collection) error {
+	return validation.ValidateStruct(f,
+		validation.Field(&f.Id, validation.By(DefaultFieldIdValidationRule)),
+		validation.Field(&f.Name, validation.By(DefaultFieldNameValidationRule)),
+		validation.Field(&f.Dimensions, validation.Required, validation.Min(1), validation.Max(VectorFieldMaxDimensions)),
+		validation.Field(&f.Metric, validation.In(list.ToInterfaceSlice(vector.Metrics)...)),
+	)
+}
+
+// metric returns the field index metric or its default.
+func (f *VectorField) metric() string {
+	if f.Metric == "" {
+		return vector.MetricCosine
+	}
+
+	return f.Metric
+}
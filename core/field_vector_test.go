@@ -0,0 +1,229 @@
+package core_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/search"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestVectorFieldBaseMethods(t *testing.T) {
+	testFieldBaseMethods(t, core.FieldTypeVector)
+}
+
+func TestVectorFieldColumnType(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	f := &core.VectorField{}
+
+	expected := `BLOB DEFAULT x'' NOT NULL`
+
+	if v := f.ColumnType(app); v != expected {
+		t.Fatalf("Expected\n%q\ngot\n%q", expected, v)
+	}
+}
+
+func TestVectorFieldPrepareValue(t *testing.T) {
+	f := &core.VectorField{}
+	record := core.NewRecord(core.NewBaseCollection("test"))
+
+	scenarios := []struct {
+		raw         any
+		expected    string
+		expectError bool
+	}{
+		{nil, `[]`, false},
+		{"", `[]`, false},
+		{[]byte{}, `[]`, false},
+		{types.Vector{1, 2}, `[1,2]`, false},
+		{[]float64{1.5, -2}, `[1.5,-2]`, false},
+		{[]any{1, 2.5}, `[1,2.5]`, false},
+		{"[1, 2]", `[1,2]`, false},
+		{string(types.Vector{3, 4}.Bytes()), `[3,4]`, false},
+		{"invalid", `[]`, true},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%#v", i, s.raw), func(t *testing.T) {
+			v, err := f.PrepareValue(record, s.raw)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			raw, err := json.Marshal(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(raw) != s.expected {
+				t.Fatalf("Expected\n%s\ngot\n%s", s.expected, raw)
+			}
+		})
+	}
+}
+
+func TestVectorFieldValidateValue(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test_collection")
+
+	scenarios := []struct {
+		name        string
+		field       *core.VectorField
+		value       any
+		expectError bool
+	}{
+		{"invalid raw value", &core.VectorField{Name: "test", Dimensions: 2}, 123, true},
+		{"empty (non-required)", &core.VectorField{Name: "test", Dimensions: 2}, types.Vector{}, false},
+		{"empty (required)", &core.VectorField{Name: "test", Dimensions: 2, Required: true}, types.Vector{}, true},
+		{"less dimensions", &core.VectorField{Name: "test", Dimensions: 2}, types.Vector{1}, true},
+		{"more dimensions", &core.VectorField{Name: "test", Dimensions: 2}, types.Vector{1, 2, 3}, true},
+		{"NaN", &core.VectorField{Name: "test", Dimensions: 2}, types.Vector{1, float32(math.NaN())}, true},
+		{"Inf", &core.VectorField{Name: "test", Dimensions: 2}, types.Vector{float32(math.Inf(1)), 1}, true},
+		{"valid", &core.VectorField{Name: "test", Dimensions: 2, Required: true}, types.Vector{1, 2}, false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			record := core.NewRecord(collection)
+			record.SetRaw("test", s.value)
+
+			err := s.field.ValidateValue(context.Background(), app, record)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+		})
+	}
+}
+
+func TestVectorFieldValidateSettings(t *testing.T) {
+	testDefaultFieldIdValidation(t, core.FieldTypeVector)
+	testDefaultFieldNameValidation(t, core.FieldTypeVector)
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test_collection")
+
+	scenarios := []struct {
+		name         string
+		field        *core.VectorField
+		expectErrors []string
+	}{
+		{
+			"zero dimensions",
+			&core.VectorField{Id: "test", Name: "test"},
+			[]string{"dimensions"},
+		},
+		{
+			"too many dimensions",
+			&core.VectorField{Id: "test", Name: "test", Dimensions: core.VectorFieldMaxDimensions + 1},
+			[]string{"dimensions"},
+		},
+		{
+			"unknown metric",
+			&core.VectorField{Id: "test", Name: "test", Dimensions: 3, Metric: "unknown"},
+			[]string{"metric"},
+		},
+		{
+			"valid",
+			&core.VectorField{Id: "test", Name: "test", Dimensions: 3, Metric: "l2", Index: true},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			errs := s.field.ValidateSettings(context.Background(), app, collection)
+
+			tests.TestValidationErrors(t, errs, s.expectErrors)
+		})
+	}
+}
+
+func TestVectorFieldRecordSaveAndSearch(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test_vectors")
+	collection.Fields.Add(
+		&core.TextField{Name: "title"},
+		&core.VectorField{Name: "embedding", Dimensions: 2},
+	)
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	data := map[string]types.Vector{
+		"a": {1, 0},
+		"b": {0, 1},
+		"c": {0.9, 0.1},
+		"d": {},
+	}
+	for title, vec := range data {
+		record := core.NewRecord(collection)
+		record.Set("title", title)
+		record.Set("embedding", vec)
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	found, err := app.FindFirstRecordByData(collection, "title", "c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := found.GetRaw("embedding"); fmt.Sprint(v) != fmt.Sprint(types.Vector{0.9, 0.1}) {
+		t.Fatalf("Expected the stored vector to be loaded, got %v", v)
+	}
+
+	scenarios := []struct {
+		filter   string
+		sort     string
+		expected []string
+	}{
+		{"vectorDistance(embedding, '[1, 0]') < 0.1", "title", []string{"a", "c"}},
+		{"vectorDistance(embedding, '[1, 0]', 'l2') > 1", "title", []string{"b"}},
+		{"id != ''", "vectorDistance(embedding, '[0, 1]', 'dot'),title", []string{"d", "b", "c", "a"}},
+		{"vectorDistance(embedding, '[1, 0]') != null", "-vectorDistance(embedding, '[1, 0]')", []string{"b", "c", "a"}},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.filter+"_"+s.sort, func(t *testing.T) {
+			records := []*core.Record{}
+
+			resolver := core.NewRecordFieldResolver(app, collection, nil, true)
+			_, err := search.NewProvider(resolver).
+				Query(app.RecordQuery(collection)).
+				Filter([]search.FilterData{search.FilterData(s.filter)}).
+				Sort(search.ParseSortFromString(s.sort)).
+				Exec(&records)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			titles := make([]string, len(records))
+			for i, r := range records {
+				titles[i] = r.GetString("title")
+			}
+
+			if fmt.Sprint(titles) != fmt.Sprint(s.expected) {
+				t.Fatalf("Expected %v, got %v", s.expected, titles)
+			}
+		})
+	}
+}
@@ -78,7 +78,7 @@ func NewLambdaFunctionContext(app App, function *LambdaFunction) *LambdaFunction
 		App:         app,
 		Function:    function,
 		StartTime:   time.Now(),
-		RequestID:   generateRequestID(),
+		RequestID:   NewTraceId(),
 		Environment: mergeEnvironment(function.EnvVars, getSystemEnv()),
 	}
 }
@@ -119,20 +119,6 @@ func (ctx *LambdaFunctionContext) WithCronTrigger(scheduledTime time.Time, confi
 
 // Helper functions
 
-func generateRequestID() string {
-	// Simple implementation - in production you might want to use a UUID
-	return time.Now().Format("20060102150405") + "-" + randomString(8)
-}
-
-func randomString(length int) string {
-	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
-	b := make([]byte, length)
-	for i := range b {
-		b[i] = charset[time.Now().UnixNano()%int64(len(charset))]
-	}
-	return string(b)
-}
-
 func mergeEnvironment(functionEnv types.JSONMap[any], systemEnv map[string]string) map[string]string {
 	result := make(map[string]string)
 	
@@ -0,0 +1,123 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// LambdaEnvironmentConfig defines the lambda function overrides for a single app environment.
+type LambdaEnvironmentConfig struct {
+	// Enabled overrides the function enabled state (if set).
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// EnvVars are merged on top of the function environment variables.
+	EnvVars map[string]any `json:"envVars,omitempty"`
+
+	// CronTriggers replaces the function cron triggers (if not nil).
+	//
+	// Set to an empty list to disable all function cron triggers in the environment.
+	CronTriggers []CronTriggerConfig `json:"cronTriggers"`
+
+	// AllowBackgroundTriggers enables the cron and database triggers
+	// even when the environment differs from the function deployment environment.
+	AllowBackgroundTriggers bool `json:"allowBackgroundTriggers,omitempty"`
+}
+
+// LambdaEnvironmentState represents the effective lambda function
+// configuration for a specific app environment.
+type LambdaEnvironmentState struct {
+	// Name is the app environment name.
+	Name string
+
+	// Enabled indicates whether the function is enabled in the environment.
+	Enabled bool
+
+	// EnvVars is the merged list of function environment variables.
+	EnvVars map[string]any
+
+	// CronTriggers is the list of the environment cron triggers.
+	//
+	// nil means that the function cron triggers should be used.
+	CronTriggers []CronTriggerConfig
+
+	// BackgroundTriggers indicates whether the cron and database
+	// triggers are allowed to run in the environment.
+	BackgroundTriggers bool
+}
+
+// ResolveLambdaEnvironment resolves the effective lambda function configuration
+// for the appEnv environment.
+//
+// deployedEnv is the environment in which the function was deployed (created).
+// Functions without a deployment environment are considered environment agnostic.
+//
+// By default the cron and database triggers are disabled when appEnv
+// doesn't match deployedEnv (eg. a production database cloned on a dev machine),
+// unless explicitly allowed by the appEnv overrides.
+func ResolveLambdaEnvironment(
+	appEnv string,
+	deployedEnv string,
+	enabled bool,
+	envVars map[string]any,
+	environments map[string]*LambdaEnvironmentConfig,
+) *LambdaEnvironmentState {
+	state := &LambdaEnvironmentState{
+		Name:               appEnv,
+		Enabled:            enabled,
+		EnvVars:            make(map[string]any, len(envVars)),
+		BackgroundTriggers: deployedEnv == "" || deployedEnv == appEnv,
+	}
+
+	for k, v := range envVars {
+		state.EnvVars[k] = v
+	}
+
+	override := environments[appEnv]
+	if override == nil {
+		return state
+	}
+
+	if override.Enabled != nil {
+		state.Enabled = *override.Enabled
+	}
+
+	for k, v := range override.EnvVars {
+		state.EnvVars[k] = v
+	}
+
+	if override.CronTriggers != nil {
+		state.CronTriggers = override.CronTriggers
+	}
+
+	if override.AllowBackgroundTriggers {
+		state.BackgroundTriggers = true
+	}
+
+	return state
+}
+
+// ParseLambdaEnvironments parses the raw JSON serialized lambda function
+// environments configuration.
+func ParseLambdaEnvironments(raw []byte) (map[string]*LambdaEnvironmentConfig, error) {
+	result := map[string]*LambdaEnvironmentConfig{}
+
+	if len(raw) == 0 || string(raw) == "null" {
+		return result, nil
+	}
+
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("invalid lambda environments configuration: %w", err)
+	}
+
+	return result, nil
+}
+
+// ResolveEnvironment resolves the effective function configuration for the appEnv environment.
+func (m *LambdaFunction) ResolveEnvironment(appEnv string) (*LambdaEnvironmentState, error) {
+	environments, err := ParseLambdaEnvironments(m.Environments)
+	if err != nil {
+		return nil, err
+	}
+
+	return ResolveLambdaEnvironment(appEnv, m.Environment, m.Enabled, m.EnvVars, environments), nil
+}
@@ -0,0 +1,185 @@
+package core_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestResolveLambdaEnvironment(t *testing.T) {
+	t.Parallel()
+
+	disabled := false
+
+	environments := map[string]*core.LambdaEnvironmentConfig{
+		"staging": {
+			Enabled: &disabled,
+			EnvVars: map[string]any{"b": "staging_b", "c": "staging_c"},
+		},
+		"development": {
+			CronTriggers: []core.CronTriggerConfig{},
+		},
+		"preview": {
+			AllowBackgroundTriggers: true,
+			CronTriggers:            []core.CronTriggerConfig{{Expression: "@daily"}},
+		},
+	}
+
+	envVars := map[string]any{"a": "base_a", "b": "base_b"}
+
+	scenarios := []struct {
+		name                       string
+		appEnv                     string
+		deployedEnv                string
+		expectedEnabled            bool
+		expectedEnvVars            string
+		expectedCronTriggers       string
+		expectedBackgroundTriggers bool
+	}{
+		{
+			"no deployment environment",
+			"production",
+			"",
+			true,
+			`{"a":"base_a","b":"base_b"}`,
+			"null",
+			true,
+		},
+		{
+			"matching deployment environment",
+			"production",
+			"production",
+			true,
+			`{"a":"base_a","b":"base_b"}`,
+			"null",
+			true,
+		},
+		{
+			"different deployment environment",
+			"development",
+			"production",
+			true,
+			`{"a":"base_a","b":"base_b"}`,
+			"[]",
+			false,
+		},
+		{
+			"overrides",
+			"staging",
+			"staging",
+			false,
+			`{"a":"base_a","b":"staging_b","c":"staging_c"}`,
+			"null",
+			true,
+		},
+		{
+			"allowed background triggers",
+			"preview",
+			"production",
+			true,
+			`{"a":"base_a","b":"base_b"}`,
+			`[{"expression":"@daily"}]`,
+			true,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			state := core.ResolveLambdaEnvironment(s.appEnv, s.deployedEnv, true, envVars, environments)
+
+			if state.Name != s.appEnv {
+				t.Fatalf("Expected name %q, got %q", s.appEnv, state.Name)
+			}
+
+			if state.Enabled != s.expectedEnabled {
+				t.Fatalf("Expected enabled %v, got %v", s.expectedEnabled, state.Enabled)
+			}
+
+			if state.BackgroundTriggers != s.expectedBackgroundTriggers {
+				t.Fatalf("Expected background triggers %v, got %v", s.expectedBackgroundTriggers, state.BackgroundTriggers)
+			}
+
+			rawEnvVars, _ := json.Marshal(state.EnvVars)
+			if str := string(rawEnvVars); str != s.expectedEnvVars {
+				t.Fatalf("Expected env vars %s, got %s", s.expectedEnvVars, str)
+			}
+
+			rawCronTriggers, _ := json.Marshal(state.CronTriggers)
+			if str := string(rawCronTriggers); str != s.expectedCronTriggers {
+				t.Fatalf("Expected cron triggers %s, got %s", s.expectedCronTriggers, str)
+			}
+		})
+	}
+
+	// the base env vars must remain unchanged
+	if len(envVars) != 2 || envVars["b"] != "base_b" {
+		t.Fatalf("Expected the base env vars to remain unchanged, got %v", envVars)
+	}
+}
+
+func TestParseLambdaEnvironments(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		raw         string
+		expectError bool
+		expectedLen int
+	}{
+		{"", false, 0},
+		{"null", false, 0},
+		{"{}", false, 0},
+		{`{"a":{"enabled":false},"b":{"envVars":{"x":1}}}`, false, 2},
+		{`[1,2]`, true, 0},
+		{`invalid`, true, 0},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.raw, func(t *testing.T) {
+			result, err := core.ParseLambdaEnvironments([]byte(s.raw))
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if len(result) != s.expectedLen {
+				t.Fatalf("Expected %d environments, got %d", s.expectedLen, len(result))
+			}
+		})
+	}
+}
+
+func TestLambdaFunctionResolveEnvironment(t *testing.T) {
+	t.Parallel()
+
+	function := &core.LambdaFunction{
+		Enabled:      true,
+		Environment:  "production",
+		EnvVars:      map[string]any{"a": 1},
+		Environments: types.JSONRaw(`{"development":{"enabled":false,"envVars":{"a":2}}}`),
+	}
+
+	state, err := function.ResolveEnvironment("development")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if state.Enabled {
+		t.Fatal("Expected the function to be disabled")
+	}
+
+	if state.BackgroundTriggers {
+		t.Fatal("Expected the background triggers to be disabled")
+	}
+
+	if v := state.EnvVars["a"]; v != float64(2) {
+		t.Fatalf("Expected env var a to be 2, got %v", v)
+	}
+
+	function.Environments = types.JSONRaw(`invalid`)
+	if _, err := function.ResolveEnvironment("development"); err == nil {
+		t.Fatal("Expected error for invalid environments configuration")
+	}
+}
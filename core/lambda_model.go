@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/pocketbase/pocketbase/tools/types"
 )
@@ -16,11 +17,15 @@ var (
 
 const CollectionNameLambdaFunctions = "lambdas"
 
+// CollectionNameLambdaLogs is the name of the lambda functions execution logs collection.
+const CollectionNameLambdaLogs = "lambda_logs"
+
 const (
 	// Trigger types
 	TriggerTypeHTTP     = "http"
 	TriggerTypeDatabase = "database"
 	TriggerTypeCron     = "cron"
+	TriggerTypeRealtime = "realtime"
 
 	// Database trigger events
 	DatabaseEventInsert = "insert"
@@ -50,6 +55,14 @@ type LambdaFunction struct {
 
 	// EnvVars stores environment variables as key-value pairs
 	EnvVars types.JSONMap[any] `db:"envVars" json:"envVars"`
+
+	// Environment is the name of the app environment
+	// in which the function was deployed (eg. "production").
+	Environment string `db:"environment" json:"environment"`
+
+	// Environments stores the per app environment overrides
+	// as JSON serialized map[string]*LambdaEnvironmentConfig.
+	Environments types.JSONRaw `db:"environments" json:"environments"`
 }
 
 // TriggerConfig represents a single trigger configuration
@@ -62,6 +75,19 @@ type TriggerConfig struct {
 type HTTPTriggerConfig struct {
 	Method string `json:"method"` // GET, POST, PUT, DELETE, etc.
 	Path   string `json:"path"`   // e.g., "/api/functions/hello"
+
+	// Summary is an optional short description of the endpoint
+	// used in the generated OpenAPI document.
+	Summary string `json:"summary,omitempty"`
+
+	// RequestSchema is an optional JSON Schema of the request body.
+	//
+	// When set, the incoming request body is validated against it
+	// before the function execution.
+	RequestSchema map[string]any `json:"requestSchema,omitempty"`
+
+	// ResponseSchema is an optional JSON Schema of the successful response body.
+	ResponseSchema map[string]any `json:"responseSchema,omitempty"`
 }
 
 // DatabaseTriggerConfig represents database event trigger configuration
@@ -75,6 +101,28 @@ type CronTriggerConfig struct {
 	Expression string `json:"expression"` // cron expression
 }
 
+// RealtimeTriggerConfig represents custom realtime topic trigger configuration
+type RealtimeTriggerConfig struct {
+	// Topic is the realtime topic name, eg. "chat".
+	//
+	// A trailing "*" could be used to match all topics with the specified prefix, eg. "rooms/*".
+	Topic string `json:"topic"`
+
+	// PublishRule is an optional API rule that the publisher must satisfy.
+	//
+	// nil means that only superusers are allowed to publish to the topic.
+	PublishRule *string `json:"publishRule"`
+}
+
+// MatchTopic reports whether the trigger topic matches the specified topic name.
+func (c RealtimeTriggerConfig) MatchTopic(topic string) bool {
+	if prefix, ok := strings.CutSuffix(c.Topic, "*"); ok {
+		return strings.HasPrefix(topic, prefix)
+	}
+
+	return c.Topic == topic
+}
+
 // TableName returns the LambdaFunction model SQL table name.
 func (m *LambdaFunction) TableName() string {
 	return "_pb_functions"
@@ -107,6 +155,19 @@ func (m *LambdaFunction) PostValidate(ctx context.Context, app App) error {
 		}
 	}
 
+	// Validate environments
+	environments, err := ParseLambdaEnvironments(m.Environments)
+	if err != nil {
+		return err
+	}
+	for name, config := range environments {
+		for i, cron := range config.CronTriggers {
+			if cron.Expression == "" {
+				return fmt.Errorf("invalid %q environment cron trigger at index %d: cron expression is required", name, i)
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -120,6 +181,9 @@ func (m *LambdaFunction) DBExport(app App) (map[string]any, error) {
 		"timeout":  m.Timeout,
 		"triggers": m.Triggers,
 		"envVars":  m.EnvVars,
+		// environment overrides
+		"environment":  m.Environment,
+		"environments": m.Environments,
 	}
 
 	if m.IsNew() {
@@ -178,6 +242,21 @@ func (m *LambdaFunction) GetCronTriggers() ([]CronTriggerConfig, error) {
 	return configs, nil
 }
 
+// GetRealtimeTriggers returns all realtime trigger configurations
+func (m *LambdaFunction) GetRealtimeTriggers() ([]RealtimeTriggerConfig, error) {
+	var configs []RealtimeTriggerConfig
+	for _, trigger := range m.Triggers {
+		if trigger.Type == TriggerTypeRealtime {
+			var config RealtimeTriggerConfig
+			if err := json.Unmarshal(trigger.Config, &config); err != nil {
+				return nil, err
+			}
+			configs = append(configs, config)
+		}
+	}
+	return configs, nil
+}
+
 func validateTriggerConfig(trigger TriggerConfig) error {
 	switch trigger.Type {
 	case TriggerTypeHTTP:
@@ -218,6 +297,14 @@ func validateTriggerConfig(trigger TriggerConfig) error {
 		if config.Expression == "" {
 			return errors.New("cron expression is required")
 		}
+	case TriggerTypeRealtime:
+		var config RealtimeTriggerConfig
+		if err := json.Unmarshal(trigger.Config, &config); err != nil {
+			return fmt.Errorf("invalid realtime trigger config: %w", err)
+		}
+		if config.Topic == "" {
+			return errors.New("realtime topic is required")
+		}
 	default:
 		return fmt.Errorf("unknown trigger type: %s", trigger.Type)
 	}
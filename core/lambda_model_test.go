@@ -0,0 +1,35 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func TestRealtimeTriggerConfigMatchTopic(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		pattern  string
+		topic    string
+		expected bool
+	}{
+		{"chat", "chat", true},
+		{"chat", "chat2", false},
+		{"chat", "cha", false},
+		{"rooms/*", "rooms/", true},
+		{"rooms/*", "rooms/general", true},
+		{"rooms/*", "rooms", false},
+		{"*", "anything", true},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.pattern+"_"+s.topic, func(t *testing.T) {
+			config := core.RealtimeTriggerConfig{Topic: s.pattern}
+
+			if result := config.MatchTopic(s.topic); result != s.expected {
+				t.Fatalf("Expected %v, got %v", s.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package core
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+const (
+	// TraceparentHeader is the W3C Trace Context request header name.
+	TraceparentHeader = "traceparent"
+
+	// RequestIdHeader is the response header name with the request trace id.
+	RequestIdHeader = "X-Request-Id"
+)
+
+const hexAlphabet = "0123456789abcdef"
+
+type traceIdContextKey struct{}
+
+// NewTraceId generates a new random W3C Trace Context compatible trace id
+// (32 lowercase hex characters).
+func NewTraceId() string {
+	return security.RandomStringWithAlphabet(32, hexAlphabet)
+}
+
+// ParseTraceparent extracts the trace id from a W3C "traceparent" header value.
+//
+// Returns an empty string if the header value is missing or malformed.
+//
+// Example:
+//
+//	ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01") // "4bf92f3577b34da6a3ce929d0e0e4736"
+func ParseTraceparent(value string) string {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 {
+		return ""
+	}
+
+	version, traceId, parentId, flags := parts[0], parts[1], parts[2], parts[3]
+
+	// "ff" is forbidden and version "00" must have exactly 4 parts
+	if !isLowerHex(version, 2) || version == "ff" || (version == "00" && len(parts) != 4) {
+		return ""
+	}
+
+	if !isLowerHex(traceId, 32) || traceId == strings.Repeat("0", 32) {
+		return ""
+	}
+
+	if !isLowerHex(parentId, 16) || parentId == strings.Repeat("0", 16) {
+		return ""
+	}
+
+	if !isLowerHex(flags, 2) {
+		return ""
+	}
+
+	return traceId
+}
+
+// FormatTraceparent returns a new W3C "traceparent" header value
+// for the specified trace id with a random parent (span) id.
+func FormatTraceparent(traceId string) string {
+	return "00-" + traceId + "-" + security.RandomStringWithAlphabet(16, hexAlphabet) + "-01"
+}
+
+// ContextWithTraceId returns a copy of ctx with the specified trace id.
+//
+// It could be used for example with [App.SaveWithContext] to propagate
+// the trace id to the lambda functions triggered by the model change.
+func ContextWithTraceId(ctx context.Context, traceId string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return context.WithValue(ctx, traceIdContextKey{}, traceId)
+}
+
+// TraceIdFromContext returns the trace id stored in ctx (if any).
+func TraceIdFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+
+	traceId, _ := ctx.Value(traceIdContextKey{}).(string)
+
+	return traceId
+}
+
+func isLowerHex(str string, length int) bool {
+	if len(str) != length {
+		return false
+	}
+
+	for _, c := range str {
+		if !strings.ContainsRune(hexAlphabet, c) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,91 @@
+package core_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func TestNewTraceId(t *testing.T) {
+	t.Parallel()
+
+	id1 := core.NewTraceId()
+	id2 := core.NewTraceId()
+
+	pattern := regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+	if !pattern.MatchString(id1) || !pattern.MatchString(id2) {
+		t.Fatalf("Expected 32 lowercase hex characters, got %q and %q", id1, id2)
+	}
+
+	if id1 == id2 {
+		t.Fatalf("Expected different trace ids, got %q", id1)
+	}
+}
+
+func TestParseTraceparent(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		value    string
+		expected string
+	}{
+		{"", ""},
+		{"invalid", ""},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{" 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00 ", "4bf92f3577b34da6a3ce929d0e0e4736"},
+		// future version with extra fields
+		{"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", "4bf92f3577b34da6a3ce929d0e0e4736"},
+		// version 00 with extra fields
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", ""},
+		// forbidden version
+		{"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", ""},
+		// uppercase
+		{"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", ""},
+		// all zeros trace id
+		{"00-00000000000000000000000000000000-00f067aa0ba902b7-01", ""},
+		// all zeros parent id
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", ""},
+		// invalid lengths
+		{"00-4bf92f3577b34da6a3ce929d0e0e473-00f067aa0ba902b7-01", ""},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b-01", ""},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-1", ""},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.value, func(t *testing.T) {
+			result := core.ParseTraceparent(s.value)
+			if result != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, result)
+			}
+		})
+	}
+}
+
+func TestFormatTraceparent(t *testing.T) {
+	t.Parallel()
+
+	traceId := core.NewTraceId()
+
+	traceparent := core.FormatTraceparent(traceId)
+
+	if parsed := core.ParseTraceparent(traceparent); parsed != traceId {
+		t.Fatalf("Expected %q to be parsed to %q, got %q", traceparent, traceId, parsed)
+	}
+}
+
+func TestTraceIdContext(t *testing.T) {
+	t.Parallel()
+
+	if id := core.TraceIdFromContext(context.Background()); id != "" {
+		t.Fatalf("Expected empty trace id, got %q", id)
+	}
+
+	ctx := core.ContextWithTraceId(context.Background(), "test")
+
+	if id := core.TraceIdFromContext(ctx); id != "test" {
+		t.Fatalf("Expected trace id %q, got %q", "test", id)
+	}
+}
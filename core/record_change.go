@@ -0,0 +1,245 @@
+package core
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/types"
+	"github.com/spf13/cast"
+)
+
+const (
+	RecordChangeActionCreate = "create"
+	RecordChangeActionUpdate = "update"
+	RecordChangeActionDelete = "delete"
+)
+
+const (
+	// RecordChangesTable is the name of the append-only records change log table.
+	RecordChangesTable = "_recordChanges"
+
+	// RecordChangesPrunedTable is the name of the table that keeps
+	// the last pruned change log cursor of each collection.
+	RecordChangesPrunedTable = "_recordChangesPruned"
+
+	// RecordChangeCheckpointsTable is the name of the table that
+	// keeps the change log consumers last processed cursor.
+	RecordChangeCheckpointsTable = "_recordChangeCheckpoints"
+)
+
+// RecordChange defines a single entry from the records change log.
+//
+// A change is logged in the same transaction as the record create,
+// update and delete of a collection with enabled change log (see [ChangeLogConfig]).
+//
+// Soft deletes are logged as "delete" and restores as "update" changes.
+type RecordChange struct {
+	// Cursor is the unique, monotonically increasing position of the change in the log.
+	Cursor int64 `db:"cursor" json:"cursor"`
+
+	Action         string         `db:"action" json:"action"`
+	CollectionRef  string         `db:"collectionRef" json:"collectionRef"`
+	CollectionName string         `db:"collectionName" json:"collectionName"`
+	RecordRef      string         `db:"recordRef" json:"recordRef"`
+	Created        types.DateTime `db:"created" json:"created"`
+
+	// Data is the raw stored record row snapshot
+	// (for deletes this is the last state of the record).
+	Data types.JSONMap[any] `db:"data" json:"-"`
+}
+
+// RowData returns the change record snapshot as db row map.
+func (c *RecordChange) RowData() dbx.NullStringMap {
+	result := make(dbx.NullStringMap, len(c.Data))
+
+	for k, v := range c.Data {
+		if v == nil {
+			result[k] = sql.NullString{}
+		} else {
+			result[k] = sql.NullString{String: cast.ToString(v), Valid: true}
+		}
+	}
+
+	return result
+}
+
+// Record returns a new Record model loaded with the change snapshot data.
+//
+// Note that the snapshot fields are matched by name with the
+// provided collection fields, aka. fields that were added after the
+// change are loaded with their zero value and the removed ones are ignored.
+func (c *RecordChange) Record(collection *Collection) (*Record, error) {
+	return newRecordFromNullStringMap(collection, c.RowData())
+}
+
+// -------------------------------------------------------------------
+
+// findRecordRow returns the raw stored row of a single collection record.
+func findRecordRow(app App, collection *Collection, id string) (dbx.NullStringMap, error) {
+	row := dbx.NullStringMap{}
+
+	err := app.DB().Select("*").
+		From(collection.Name).
+		AndWhere(dbx.HashExp{FieldNameId: id}).
+		Limit(1).
+		One(&row)
+
+	return row, err
+}
+
+// logRecordChange appends a new record change entry to the change log.
+//
+// The password hash and the auth record tokenKey are excluded from
+// the stored snapshot (see isRevisionExcludedField).
+func logRecordChange(app App, action string, collection *Collection, id string, row dbx.NullStringMap) error {
+	data := make(map[string]any, len(row))
+	for k, v := range row {
+		if field := collection.Fields.GetByName(k); field != nil && isRevisionExcludedField(collection, field) {
+			continue
+		}
+
+		if v.Valid {
+			data[k] = v.String
+		} else {
+			data[k] = nil
+		}
+	}
+
+	rawData, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	_, err = app.DB().Insert(RecordChangesTable, dbx.Params{
+		"action":         action,
+		"collectionRef":  collection.Id,
+		"collectionName": collection.Name,
+		"recordRef":      id,
+		"data":           string(rawData),
+		"created":        types.NowDateTime().String(),
+	}).Execute()
+
+	return err
+}
+
+func (app *BaseApp) registerRecordChangeHooks() {
+	// run on every hour to cleanup the expired change log entries
+	app.Cron().Add("__pbRecordChangesCleanup__", "40 * * * *", func() {
+		if err := app.DeleteExpiredRecordChanges(); err != nil {
+			app.Logger().Warn("Failed to delete expired record changes", "error", err)
+		}
+	})
+
+	saveHandler := func(action string) func(e *RecordEvent) error {
+		return func(e *RecordEvent) error {
+			if !e.Record.Collection().ChangeLogEnabled() {
+				return e.Next()
+			}
+
+			originalApp := e.App
+			txErr := e.App.RunInTransaction(func(txApp App) error {
+				e.App = txApp
+
+				if err := e.Next(); err != nil {
+					return err
+				}
+
+				row, err := findRecordRow(txApp, e.Record.Collection(), e.Record.Id)
+				if err != nil {
+					return err
+				}
+
+				return logRecordChange(txApp, action, e.Record.Collection(), e.Record.Id, row)
+			})
+			e.App = originalApp
+
+			return txErr
+		}
+	}
+
+	app.OnRecordCreateExecute().Bind(&hook.Handler[*RecordEvent]{
+		Func:     saveHandler(RecordChangeActionCreate),
+		Priority: 99,
+	})
+
+	app.OnRecordUpdateExecute().Bind(&hook.Handler[*RecordEvent]{
+		Func:     saveHandler(RecordChangeActionUpdate),
+		Priority: 99,
+	})
+
+	// note: runs before the soft delete handler so that
+	// the soft deletes are also logged as delete changes
+	app.OnRecordDeleteExecute().Bind(&hook.Handler[*RecordEvent]{
+		Func: func(e *RecordEvent) error {
+			collection := e.Record.Collection()
+
+			// the purge of an already soft deleted record was logged on its soft delete
+			if !collection.ChangeLogEnabled() || e.Record.IsSoftDeleted() {
+				return e.Next()
+			}
+
+			originalApp := e.App
+			txErr := e.App.RunInTransaction(func(txApp App) error {
+				e.App = txApp
+
+				id := cast.ToString(e.Record.LastSavedPK())
+
+				row, err := findRecordRow(txApp, collection, id)
+				if err != nil {
+					return err
+				}
+
+				if err := e.Next(); err != nil {
+					return err
+				}
+
+				// reload to include the "deleted" field change
+				if isSoftDeletedRecord(e.Record) {
+					row, err = findRecordRow(txApp, collection, id)
+					if err != nil {
+						return err
+					}
+				}
+
+				return logRecordChange(txApp, RecordChangeActionDelete, collection, id, row)
+			})
+			e.App = originalApp
+
+			return txErr
+		},
+		Priority: -100,
+	})
+
+	// delete the collection change log entries on collection delete
+	app.OnCollectionDeleteExecute().Bind(&hook.Handler[*CollectionEvent]{
+		Func: func(e *CollectionEvent) error {
+			if e.Collection.IsView() {
+				return e.Next()
+			}
+
+			originalApp := e.App
+			txErr := e.App.RunInTransaction(func(txApp App) error {
+				e.App = txApp
+
+				if err := e.Next(); err != nil {
+					return err
+				}
+
+				for _, table := range []string{RecordChangesTable, RecordChangesPrunedTable} {
+					_, err := txApp.DB().Delete(table, dbx.HashExp{"collectionRef": e.Collection.Id}).Execute()
+					if err != nil {
+						return err
+					}
+				}
+
+				return nil
+			})
+			e.App = originalApp
+
+			return txErr
+		},
+		Priority: 99,
+	})
+}
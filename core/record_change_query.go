@@ -0,0 +1,175 @@
+package core
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/list"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// FindRecordChanges returns the record changes logged after the
+// specified cursor ordered from the oldest to the newest.
+//
+// collectionIds could be used to limit the changes to specific collections
+// (an empty slice means all collections).
+//
+// If limit is <= 0 no limit is applied.
+func (app *BaseApp) FindRecordChanges(afterCursor int64, collectionIds []string, limit int) ([]*RecordChange, error) {
+	query := app.DB().Select("*").
+		From(RecordChangesTable).
+		AndWhere(dbx.NewExp("[[cursor]] > {:cursor}", dbx.Params{"cursor": afterCursor})).
+		OrderBy("cursor ASC")
+
+	if len(collectionIds) > 0 {
+		query.AndWhere(dbx.In("collectionRef", list.ToInterfaceSlice(collectionIds)...))
+	}
+
+	if limit > 0 {
+		query.Limit(int64(limit))
+	}
+
+	result := []*RecordChange{}
+
+	if err := query.All(&result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindLatestRecordChangeCursor returns the cursor of the last logged
+// record change (or 0 if no changes were logged).
+func (app *BaseApp) FindLatestRecordChangeCursor() (int64, error) {
+	var cursor int64
+
+	err := app.DB().Select("COALESCE(MAX([[cursor]]), 0)").
+		From(RecordChangesTable).
+		Row(&cursor)
+
+	return cursor, err
+}
+
+// FindRecordChangesPrunedCursor returns the highest change log cursor
+// that was deleted because of the retention limit of the specified
+// collections (an empty slice means all collections).
+//
+// Consumers that haven't processed the changes up to the returned
+// cursor have missed changes and need to resynchronize.
+func (app *BaseApp) FindRecordChangesPrunedCursor(collectionIds []string) (int64, error) {
+	var cursor int64
+
+	query := app.DB().Select("COALESCE(MAX([[cursor]]), 0)").From(RecordChangesPrunedTable)
+
+	if len(collectionIds) > 0 {
+		query.AndWhere(dbx.In("collectionRef", list.ToInterfaceSlice(collectionIds)...))
+	}
+
+	err := query.Row(&cursor)
+
+	return cursor, err
+}
+
+// DeleteExpiredRecordChanges deletes the record changes that are older
+// than their collection change log max days limit.
+//
+// The cursor of the last deleted change is stored so that the consumers
+// could detect whether they have missed changes (see [BaseApp.FindRecordChangesPrunedCursor]).
+func (app *BaseApp) DeleteExpiredRecordChanges() error {
+	collections, err := app.FindAllCollections(CollectionTypeBase, CollectionTypeAuth)
+	if err != nil {
+		return err
+	}
+
+	// note: perform even if the change log is disabled to ensure that there are no dangling old changes
+	for _, collection := range collections {
+		if collection.ChangeLog == nil || collection.ChangeLog.MaxDays <= 0 {
+			continue
+		}
+
+		minValidDate, err := types.ParseDateTime(time.Now().Add(-1 * collection.ChangeLog.MaxDuration()))
+		if err != nil {
+			return err
+		}
+
+		err = app.RunInTransaction(func(txApp App) error {
+			var lastCursor int64
+
+			err := txApp.DB().Select("COALESCE(MAX([[cursor]]), 0)").
+				From(RecordChangesTable).
+				AndWhere(dbx.HashExp{"collectionRef": collection.Id}).
+				AndWhere(dbx.NewExp("[[created]] < {:date}", dbx.Params{"date": minValidDate})).
+				Row(&lastCursor)
+			if err != nil || lastCursor == 0 {
+				return err
+			}
+
+			_, err = txApp.DB().Delete(RecordChangesTable, dbx.And(
+				dbx.HashExp{"collectionRef": collection.Id},
+				dbx.NewExp("[[cursor]] <= {:cursor}", dbx.Params{"cursor": lastCursor}),
+			)).Execute()
+			if err != nil {
+				return err
+			}
+
+			_, err = txApp.DB().NewQuery(
+				"INSERT INTO {{" + RecordChangesPrunedTable + "}} ([[collectionRef]], [[cursor]]) " +
+					"VALUES ({:collectionRef}, {:cursor}) " +
+					"ON CONFLICT DO UPDATE SET [[cursor]] = MAX([[cursor]], excluded.[[cursor]])",
+			).Bind(dbx.Params{
+				"collectionRef": collection.Id,
+				"cursor":        lastCursor,
+			}).Execute()
+
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FindRecordChangeCheckpoint returns the last stored change log cursor
+// of the specified auth record consumer (or 0 if there is no checkpoint).
+func (app *BaseApp) FindRecordChangeCheckpoint(authRecord *Record, consumer string) (int64, error) {
+	var cursor int64
+
+	err := app.DB().Select("cursor").
+		From(RecordChangeCheckpointsTable).
+		AndWhere(dbx.HashExp{
+			"authRef":  recordChangeAuthRef(authRecord),
+			"consumer": consumer,
+		}).
+		Limit(1).
+		Row(&cursor)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, err
+	}
+
+	return cursor, nil
+}
+
+// SaveRecordChangeCheckpoint stores (or replaces) the change log
+// cursor of the specified auth record consumer.
+func (app *BaseApp) SaveRecordChangeCheckpoint(authRecord *Record, consumer string, cursor int64) error {
+	_, err := app.NonconcurrentDB().NewQuery(
+		"INSERT INTO {{" + RecordChangeCheckpointsTable + "}} ([[authRef]], [[consumer]], [[cursor]], [[updated]]) " +
+			"VALUES ({:authRef}, {:consumer}, {:cursor}, {:updated}) " +
+			"ON CONFLICT DO UPDATE SET [[cursor]] = excluded.[[cursor]], [[updated]] = excluded.[[updated]]",
+	).Bind(dbx.Params{
+		"authRef":  recordChangeAuthRef(authRecord),
+		"consumer": consumer,
+		"cursor":   cursor,
+		"updated":  types.NowDateTime().String(),
+	}).Execute()
+
+	return err
+}
+
+func recordChangeAuthRef(authRecord *Record) string {
+	return authRecord.Collection().Id + ":" + authRecord.Id
+}
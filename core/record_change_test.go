@@ -0,0 +1,415 @@
+package core_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func createChangeLogTestCollection(t testing.TB, app core.App, name string, changeLog *core.ChangeLogConfig) *core.Collection {
+	collection := core.NewBaseCollection(name)
+	collection.ChangeLog = changeLog
+	collection.Fields.Add(
+		&core.TextField{Name: "title"},
+		&core.NumberField{Name: "total"},
+		&core.BoolField{Name: "active"},
+	)
+
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	return collection
+}
+
+func recordChangeActions(changes []*core.RecordChange) []string {
+	result := make([]string, len(changes))
+	for i, c := range changes {
+		result[i] = c.Action + ":" + c.RecordRef
+	}
+	return result
+}
+
+func TestRecordChangesLog(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := createChangeLogTestCollection(t, app, "test_changes", &core.ChangeLogConfig{Enabled: true})
+	disabled := createChangeLogTestCollection(t, app, "test_changes_disabled", nil)
+
+	// disabled collection
+	other := core.NewRecord(disabled)
+	other.Set("title", "other")
+	if err := app.Save(other); err != nil {
+		t.Fatal(err)
+	}
+
+	record := core.NewRecord(collection)
+	record.Set("title", "a")
+	record.Set("total", 1)
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	record.Set("title", "b")
+	record.Set("active", true)
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	// failed save
+	failed := core.NewRecord(collection)
+	failed.Set("title", "failed")
+	app.OnRecordCreateExecute(collection.Name).Bind(&hook.Handler[*core.RecordEvent]{
+		Func: func(e *core.RecordEvent) error {
+			if e.Record.GetString("title") == "failed" {
+				return errors.New("test")
+			}
+			return e.Next()
+		},
+		Priority: 100,
+	})
+	if err := app.Save(failed); err == nil {
+		t.Fatal("Expected the save to fail")
+	}
+
+	if err := app.Delete(record); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := app.FindRecordChanges(0, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedActions := []string{
+		"create:" + record.Id,
+		"update:" + record.Id,
+		"delete:" + record.Id,
+	}
+	if actions := recordChangeActions(changes); !slices.Equal(actions, expectedActions) {
+		t.Fatalf("Expected changes %v, got %v", expectedActions, actions)
+	}
+
+	for i, change := range changes {
+		if change.CollectionRef != collection.Id || change.CollectionName != collection.Name {
+			t.Fatalf("[%d] Expected collection %q, got %q (%q)", i, collection.Id, change.CollectionRef, change.CollectionName)
+		}
+
+		if i > 0 && change.Cursor <= changes[i-1].Cursor {
+			t.Fatalf("[%d] Expected increasing cursor, got %d after %d", i, change.Cursor, changes[i-1].Cursor)
+		}
+
+		if change.Created.IsZero() {
+			t.Fatalf("[%d] Expected nonzero created date", i)
+		}
+	}
+
+	snapshot, err := changes[2].Record(collection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Id != record.Id || snapshot.GetString("title") != "b" || snapshot.GetInt("total") != 1 || !snapshot.GetBool("active") {
+		t.Fatalf("Expected the delete change snapshot to be the last record state, got %v", snapshot.PublicExport())
+	}
+
+	// filter and limit
+	filtered, err := app.FindRecordChanges(changes[0].Cursor, []string{collection.Id}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(filtered) != 1 || filtered[0].Cursor != changes[1].Cursor {
+		t.Fatalf("Expected only the update change, got %v", recordChangeActions(filtered))
+	}
+
+	filtered, err = app.FindRecordChanges(0, []string{disabled.Id}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(filtered) != 0 {
+		t.Fatalf("Expected no changes for the disabled collection, got %v", recordChangeActions(filtered))
+	}
+
+	latest, err := app.FindLatestRecordChangeCursor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest != changes[2].Cursor {
+		t.Fatalf("Expected latest cursor %d, got %d", changes[2].Cursor, latest)
+	}
+}
+
+func TestRecordChangesLogAuthCredentials(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	collection.ChangeLog = &core.ChangeLogConfig{Enabled: true}
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := app.FindAuthRecordByEmail(collection, "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user.SetPassword("1234567890")
+	user.Set("name", "new")
+	if err := app.Save(user); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := app.FindRecordChanges(0, []string{collection.Id}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 {
+		t.Fatalf("Expected 1 change, got %d", len(changes))
+	}
+
+	if changes[0].Data["name"] != "new" {
+		t.Fatalf("Expected the name to be logged, got %v", changes[0].Data)
+	}
+
+	for _, name := range []string{core.FieldNamePassword, core.FieldNameTokenKey} {
+		if _, ok := changes[0].Data[name]; ok {
+			t.Fatalf("Expected %q to be excluded from the change data, got %v", name, changes[0].Data)
+		}
+	}
+}
+
+func TestRecordChangesLogSoftDelete(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := createChangeLogTestCollection(t, app, "test_changes", &core.ChangeLogConfig{Enabled: true})
+	collection.SoftDelete = &core.SoftDeleteConfig{Enabled: true}
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	record := core.NewRecord(collection)
+	record.Set("title", "a")
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	// soft delete
+	if err := app.Delete(record); err != nil {
+		t.Fatal(err)
+	}
+
+	// restore
+	record.Set(core.FieldNameDeleted, "")
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	// soft delete + purge
+	if err := app.Delete(record); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.Delete(record); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := app.FindRecordChanges(0, []string{collection.Id}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedActions := []string{
+		"create:" + record.Id,
+		"delete:" + record.Id,
+		"update:" + record.Id,
+		"delete:" + record.Id,
+	}
+	if actions := recordChangeActions(changes); !slices.Equal(actions, expectedActions) {
+		t.Fatalf("Expected changes %v, got %v", expectedActions, actions)
+	}
+
+	snapshot, err := changes[1].Record(collection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.GetDateTime(core.FieldNameDeleted).IsZero() {
+		t.Fatal("Expected the soft delete change snapshot to have nonempty deleted field")
+	}
+}
+
+func TestRecordChangesCollectionDelete(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := createChangeLogTestCollection(t, app, "test_changes", &core.ChangeLogConfig{Enabled: true})
+
+	record := core.NewRecord(collection)
+	record.Set("title", "a")
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Delete(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := app.FindRecordChanges(0, []string{collection.Id}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 0 {
+		t.Fatalf("Expected the collection changes to be deleted, got %v", recordChangeActions(changes))
+	}
+}
+
+func TestDeleteExpiredRecordChanges(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection1 := createChangeLogTestCollection(t, app, "test_changes1", &core.ChangeLogConfig{Enabled: true, MaxDays: 2})
+	collection2 := createChangeLogTestCollection(t, app, "test_changes2", &core.ChangeLogConfig{Enabled: true})
+
+	for _, c := range []*core.Collection{collection1, collection2} {
+		for _, title := range []string{"old", "new"} {
+			record := core.NewRecord(c)
+			record.Set("title", title)
+			if err := app.Save(record); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	oldDate, err := types.ParseDateTime(time.Now().AddDate(0, 0, -3))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// mark the "old" record changes as expired
+	_, err = app.DB().NewQuery(
+		"UPDATE {{" + core.RecordChangesTable + "}} SET [[created]] = {:date} WHERE json_extract([[data]], '$.title') = 'old'",
+	).Bind(dbx.Params{"date": oldDate.String()}).Execute()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before, err := app.FindRecordChanges(0, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(before) != 4 {
+		t.Fatalf("Expected 4 changes, got %d", len(before))
+	}
+
+	if err := app.DeleteExpiredRecordChanges(); err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := app.FindRecordChanges(0, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedCursors := []int64{before[1].Cursor, before[2].Cursor, before[3].Cursor}
+	cursors := make([]int64, len(after))
+	for i, c := range after {
+		cursors[i] = c.Cursor
+	}
+	if !slices.Equal(cursors, expectedCursors) {
+		t.Fatalf("Expected remaining cursors %v, got %v", expectedCursors, cursors)
+	}
+
+	scenarios := []struct {
+		collectionIds []string
+		expected      int64
+	}{
+		{nil, before[0].Cursor},
+		{[]string{collection1.Id}, before[0].Cursor},
+		{[]string{collection2.Id}, 0},
+	}
+
+	for _, s := range scenarios {
+		cursor, err := app.FindRecordChangesPrunedCursor(s.collectionIds)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cursor != s.expected {
+			t.Fatalf("[%v] Expected pruned cursor %d, got %d", s.collectionIds, s.expected, cursor)
+		}
+	}
+}
+
+func TestRecordChangeCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user1, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user2, err := app.FindAuthRecordByEmail("users", "test2@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cursor, err := app.FindRecordChangeCheckpoint(user1, "sync")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cursor != 0 {
+		t.Fatalf("Expected 0 cursor for missing checkpoint, got %d", cursor)
+	}
+
+	if err := app.SaveRecordChangeCheckpoint(user1, "sync", 10); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.SaveRecordChangeCheckpoint(user1, "sync", 5); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.SaveRecordChangeCheckpoint(user2, "sync", 20); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		auth     *core.Record
+		consumer string
+		expected int64
+	}{
+		{user1, "sync", 5},
+		{user1, "other", 0},
+		{user2, "sync", 20},
+	}
+
+	for _, s := range scenarios {
+		cursor, err := app.FindRecordChangeCheckpoint(s.auth, s.consumer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cursor != s.expected {
+			t.Fatalf("[%s:%s] Expected cursor %d, got %d", s.auth.Id, s.consumer, s.expected, cursor)
+		}
+	}
+}
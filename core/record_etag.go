@@ -0,0 +1,167 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/spf13/cast"
+)
+
+// ErrETagMismatch is returned by the record Save and Delete operations
+// when the record expected ETag doesn't match the ETag of its stored state.
+var ErrETagMismatch = errors.New("the record was modified since it was last fetched")
+
+// ETag returns the entity tag (including the surrounding double quotes)
+// of the record state as it was loaded from the database.
+//
+// The ETag is derived from the record "updated" field value and
+// changes on every persisted record modification (see also [recordETag]).
+//
+// Returns empty string for new records, records that were not loaded
+// from the database or after the record was saved (in which case you
+// need to refetch the record to get its new ETag).
+func (m *Record) ETag() string {
+	// computed on demand to avoid hashing every loaded record
+	if m.etag == "" && m.etagData != nil {
+		m.etag = recordETag(m.collection, m.etagData)
+		m.etagData = nil
+	}
+
+	return m.etag
+}
+
+// resetETag clears the loaded record ETag state.
+func (m *Record) resetETag() {
+	m.etag = ""
+	m.etagData = nil
+}
+
+// SetExpectedETag registers an optimistic concurrency precondition
+// for the next [App.Save] or [App.Delete] of the record.
+//
+// If the provided etag doesn't match the ETag of the current stored record state
+// the operation fails with [ErrETagMismatch] and no changes are persisted.
+//
+// The precondition is checked only for existing records and
+// it is reset once the record save/delete execution is reached.
+// Set an empty string to remove a previously registered precondition.
+//
+// Example:
+//
+//	record, _ := app.FindRecordById("articles", "RECORD_ID")
+//	record.Set("title", "example")
+//	record.SetExpectedETag(record.ETag()) // fail if modified in the meantime
+//	err := app.Save(record)
+func (m *Record) SetExpectedETag(etag string) *Record {
+	m.expectedETag = etag
+
+	return m
+}
+
+// ExpectedETag returns the ETag precondition registered with [Record.SetExpectedETag] (if any).
+func (m *Record) ExpectedETag() string {
+	return m.expectedETag
+}
+
+// recordETag returns the ETag of the provided collection record row data.
+//
+// The ETag is derived from the record "updated" (aka. OnUpdate autodate)
+// field values and the values of the fields that are visible to everyone
+// who can view the record (to account for the changes made within the
+// same millisecond).
+//
+// Hidden and view rule protected field values are never hashed because
+// they could be brute-forced from the ETag response header.
+func recordETag(collection *Collection, data dbx.NullStringMap) string {
+	viewRules := collection.FieldViewRules()
+
+	fields := make([]Field, 0, len(collection.Fields))
+	for _, field := range collection.Fields {
+		if f, ok := field.(*AutodateField); ok && f.OnUpdate {
+			fields = append(fields, field)
+		} else if !field.GetHidden() && viewRules[field.GetName()] == "" {
+			fields = append(fields, field)
+		}
+	}
+
+	h := sha256.New()
+
+	for _, field := range fields {
+		v := data[field.GetName()]
+
+		h.Write([]byte(field.GetName()))
+		if v.Valid {
+			h.Write([]byte{1})
+		} else {
+			h.Write([]byte{0})
+		}
+		h.Write([]byte(v.String))
+		h.Write([]byte{0})
+	}
+
+	return `"` + hex.EncodeToString(h.Sum(nil))[:32] + `"`
+}
+
+// checkRecordETag loads the current stored record state
+// and compares its ETag with the record expected one.
+func checkRecordETag(app App, record *Record) error {
+	stored, err := app.FindRecordById(record.Collection(), cast.ToString(record.LastSavedPK()))
+	if err != nil {
+		return err
+	}
+
+	if stored.ETag() != record.expectedETag {
+		return ErrETagMismatch
+	}
+
+	return nil
+}
+
+func (app *BaseApp) registerRecordETagHooks() {
+	handler := func(e *RecordEvent) error {
+		if e.Record.expectedETag == "" || e.Record.IsNew() {
+			err := e.Next()
+			if err == nil {
+				e.Record.resetETag()
+			}
+			return err
+		}
+
+		originalApp := e.App
+		txErr := e.App.RunInTransaction(func(txApp App) error {
+			e.App = txApp
+
+			err := checkRecordETag(txApp, e.Record)
+
+			e.Record.expectedETag = ""
+
+			if err != nil {
+				return err
+			}
+
+			return e.Next()
+		})
+		e.App = originalApp
+
+		if txErr == nil {
+			e.Record.resetETag()
+		}
+
+		return txErr
+	}
+
+	// note: runs before the soft delete handler to ensure
+	// that the precondition is also checked for soft deletes
+	app.OnRecordUpdateExecute().Bind(&hook.Handler[*RecordEvent]{
+		Func:     handler,
+		Priority: -100,
+	})
+
+	app.OnRecordDeleteExecute().Bind(&hook.Handler[*RecordEvent]{
+		Func:     handler,
+		Priority: -100,
+	})
+}
@@ -0,0 +1,299 @@
+package core_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestRecordETag(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if etag := core.NewRecord(collection).ETag(); etag != "" {
+		t.Fatalf("Expected empty ETag for new record, got %q", etag)
+	}
+
+	record1, err := app.FindRecordById(collection, "achvryl401bhse3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	etag := record1.ETag()
+	if len(etag) != 34 || !strings.HasPrefix(etag, `"`) || !strings.HasSuffix(etag, `"`) {
+		t.Fatalf("Expected quoted 32 chars ETag, got %q", etag)
+	}
+
+	// same state
+	record2, err := app.FindRecordById(collection, "achvryl401bhse3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record2.ETag() != etag {
+		t.Fatalf("Expected the same ETag for the same record state, got %q vs %q", record2.ETag(), etag)
+	}
+
+	if v := record1.Original().ETag(); v != etag {
+		t.Fatalf("Expected Original() to have the same ETag, got %q", v)
+	}
+
+	if v := record1.Fresh().ETag(); v != etag {
+		t.Fatalf("Expected Fresh() to have the same ETag, got %q", v)
+	}
+
+	// copies of a record whose ETag was not accessed yet
+	record4, err := app.FindRecordById(collection, "achvryl401bhse3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := record4.Original().ETag(); v != etag {
+		t.Fatalf("Expected the not accessed record Original() to have the same ETag, got %q", v)
+	}
+
+	// different record
+	record3, err := app.FindRecordById(collection, "0yxhwia2amd8gec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record3.ETag() == etag {
+		t.Fatal("Expected different records to have different ETags")
+	}
+
+	// changed state (with not accessed ETag)
+	record4.Set("active", !record4.GetBool("active"))
+	if err := app.Save(record4); err != nil {
+		t.Fatal(err)
+	}
+	if v := record4.ETag(); v != "" {
+		t.Fatalf("Expected the not accessed ETag to be reset after save, got %q", v)
+	}
+
+	// changed state
+	record1.Set("title", "new")
+	if err := app.Save(record1); err != nil {
+		t.Fatal(err)
+	}
+
+	if v := record1.ETag(); v != "" {
+		t.Fatalf("Expected the ETag to be reset after save, got %q", v)
+	}
+
+	refreshed, err := app.FindRecordById(collection, record1.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refreshed.ETag() == "" || refreshed.ETag() == etag {
+		t.Fatalf("Expected new nonempty ETag, got %q", refreshed.ETag())
+	}
+}
+
+func TestRecordExpectedETagSave(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	editor1, err := app.FindRecordById("demo2", "achvryl401bhse3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	editor2, err := app.FindRecordById("demo2", "achvryl401bhse3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// first editor update
+	editor1.Set("title", "editor1")
+	editor1.SetExpectedETag(editor1.ETag())
+	if err := app.Save(editor1); err != nil {
+		t.Fatalf("Expected the first save to succeed, got %v", err)
+	}
+
+	if v := editor1.ExpectedETag(); v != "" {
+		t.Fatalf("Expected the precondition to be reset after save, got %q", v)
+	}
+
+	// second editor update with stale state
+	editor2.Set("title", "editor2")
+	editor2.SetExpectedETag(editor2.ETag())
+	err = app.Save(editor2)
+	if !errors.Is(err, core.ErrETagMismatch) {
+		t.Fatalf("Expected ErrETagMismatch, got %v", err)
+	}
+
+	stored, err := app.FindRecordById("demo2", "achvryl401bhse3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := stored.GetString("title"); v != "editor1" {
+		t.Fatalf("Expected the stored title to remain %q, got %q", "editor1", v)
+	}
+
+	// retry after refetch
+	stored.Set("title", "editor2")
+	stored.SetExpectedETag(stored.ETag())
+	if err := app.Save(stored); err != nil {
+		t.Fatalf("Expected the save with the latest ETag to succeed, got %v", err)
+	}
+
+	// no precondition
+	editor2.Set("title", "editor2_force")
+	if err := app.Save(editor2); err != nil {
+		t.Fatalf("Expected the save without precondition to succeed, got %v", err)
+	}
+
+	// new records are not affected
+	collection, err := app.FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newRecord := core.NewRecord(collection)
+	newRecord.Set("title", "new_record")
+	newRecord.SetExpectedETag(`"invalid"`)
+	if err := app.Save(newRecord); err != nil {
+		t.Fatalf("Expected the new record save to succeed, got %v", err)
+	}
+}
+
+func TestRecordExpectedETagDelete(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, softDelete := range []bool{false, true} {
+		name := "hard delete"
+		if softDelete {
+			name = "soft delete"
+		}
+
+		t.Run(name, func(t *testing.T) {
+			collection.SoftDelete = &core.SoftDeleteConfig{Enabled: softDelete}
+			if err := app.Save(collection); err != nil {
+				t.Fatal(err)
+			}
+
+			record := core.NewRecord(collection)
+			record.Set("title", "etag_"+name)
+			if err := app.Save(record); err != nil {
+				t.Fatal(err)
+			}
+
+			stale, err := app.FindRecordById(collection, record.Id)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			record.Set("title", "etag_"+name+"_changed")
+			if err := app.Save(record); err != nil {
+				t.Fatal(err)
+			}
+
+			stale.SetExpectedETag(stale.ETag())
+			err = app.Delete(stale)
+			if !errors.Is(err, core.ErrETagMismatch) {
+				t.Fatalf("Expected ErrETagMismatch, got %v", err)
+			}
+
+			latest, err := app.FindRecordById(collection, record.Id)
+			if err != nil {
+				t.Fatalf("Expected the record to be still available, got %v", err)
+			}
+			if !latest.GetDateTime(core.FieldNameDeleted).IsZero() {
+				t.Fatal("Expected the record to not be soft deleted")
+			}
+
+			latest.SetExpectedETag(latest.ETag())
+			if err := app.Delete(latest); err != nil {
+				t.Fatalf("Expected the delete with the latest ETag to succeed, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRecordETagProtectedFields(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test_etag")
+	collection.Fields.Add(
+		&core.TextField{Name: "title"},
+		&core.TextField{Name: "secret", Hidden: true},
+		&core.TextField{Name: "protected"},
+		&core.AutodateField{Name: "updated", OnCreate: true, OnUpdate: true},
+	)
+	collection.FieldRules = map[string]core.FieldRule{
+		"protected": {ViewRule: "@request.auth.id != ''"},
+	}
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	record := core.NewRecord(collection)
+	record.Id = "etagtest0000001"
+	record.Set("title", "a")
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	findETag := func() string {
+		record, err := app.FindRecordById(collection, "etagtest0000001")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return record.ETag()
+	}
+
+	updateColumn := func(name string, value string) {
+		_, err := app.DB().Update(collection.Name, dbx.Params{name: value}, dbx.HashExp{"id": "etagtest0000001"}).Execute()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	etag := findETag()
+
+	// the hidden and the view rule protected values must not be part of the hash
+	updateColumn("secret", "b")
+	updateColumn("protected", "c")
+	if v := findETag(); v != etag {
+		t.Fatalf("Expected the ETag to not depend on the protected fields, got %q vs %q", v, etag)
+	}
+
+	scenarios := []struct {
+		column string
+		value  string
+	}{
+		{"title", "new"},
+		{"updated", "2000-01-01 00:00:00.000Z"},
+	}
+
+	for _, s := range scenarios {
+		updateColumn(s.column, s.value)
+
+		newETag := findETag()
+		if newETag == etag {
+			t.Fatalf("[%s] Expected the ETag to change", s.column)
+		}
+		etag = newETag
+	}
+}
@@ -0,0 +1,136 @@
+package core
+
+import (
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// IsExpired reports whether the record is from a collection with
+// enabled expiry and its expiry date is in the past.
+func (m *Record) IsExpired() bool {
+	collection := m.Collection()
+	if !collection.ExpiryEnabled() {
+		return false
+	}
+
+	var expiry types.DateTime
+	if collection.Expiry.Field != "" {
+		expiry = m.GetDateTime(collection.Expiry.Field)
+	} else if created := m.GetDateTime(FieldNameCreated); !created.IsZero() {
+		expiry = created.Add(collection.Expiry.TTLDuration())
+	}
+
+	return !expiry.IsZero() && !expiry.Time().After(time.Now())
+}
+
+// ExpiredRecordsExpr returns a db expression that matches the expired
+// records of the specified collection.
+//
+// Returns nil if the collection doesn't have enabled expiry.
+func ExpiredRecordsExpr(collection *Collection) dbx.Expression {
+	if !collection.ExpiryEnabled() {
+		return nil
+	}
+
+	now := time.Now()
+
+	column := FieldNameCreated
+	if collection.Expiry.Field != "" {
+		column = collection.Expiry.Field
+	} else {
+		now = now.Add(-1 * collection.Expiry.TTLDuration())
+	}
+
+	date, err := types.ParseDateTime(now)
+	if err != nil {
+		return nil
+	}
+
+	column = "[[" + collection.Name + "." + column + "]]"
+
+	return dbx.NewExp(
+		column+" != '' AND "+column+" <= {:expiryDate}",
+		dbx.Params{"expiryDate": date.String()},
+	)
+}
+
+// expiredRecordsBatchSize is the number of expired records
+// loaded at once when deleting the expired records.
+const expiredRecordsBatchSize = 100
+
+// DeleteExpiredRecords deletes the expired records
+// of all collections with enabled expiry.
+//
+// The records are loaded in batches and deleted one by one with app.Delete
+// so that the related record hooks, the relations cascade and the files delete are executed.
+//
+// Records that fail to be deleted are logged and skipped.
+//
+// Note that for collections with enabled soft delete the expired records
+// are only soft deleted and they are purged later by PurgeSoftDeletedRecords.
+func (app *BaseApp) DeleteExpiredRecords() error {
+	collections, err := app.FindAllCollections(CollectionTypeBase, CollectionTypeAuth)
+	if err != nil {
+		return err
+	}
+
+	for _, collection := range collections {
+		expr := ExpiredRecordsExpr(collection)
+		if expr == nil {
+			continue
+		}
+
+		exprs := []dbx.Expression{expr}
+		if collection.SoftDeleteEnabled() {
+			exprs = append(exprs, dbx.HashExp{collection.Name + "." + FieldNameDeleted: ""})
+		}
+
+		// iterate by id so that the skipped failed records are not fetched again
+		var lastId string
+		for {
+			records := []*Record{}
+
+			err := app.RecordQuery(collection).
+				AndWhere(dbx.And(exprs...)).
+				AndWhere(dbx.NewExp("[["+collection.Name+"."+FieldNameId+"]] > {:lastId}", dbx.Params{"lastId": lastId})).
+				OrderBy(collection.Name + "." + FieldNameId + " ASC").
+				Limit(expiredRecordsBatchSize).
+				All(&records)
+			if err != nil {
+				return err
+			}
+
+			for _, record := range records {
+				// log and skip the failed records (eg. required by another record relation)
+				// so that they don't block the delete of the remaining ones
+				if err := app.Delete(record); err != nil {
+					app.Logger().Warn(
+						"Failed to delete expired record",
+						"error", err,
+						"recordId", record.Id,
+						"collectionId", collection.Id,
+					)
+				}
+			}
+
+			if len(records) < expiredRecordsBatchSize {
+				break
+			}
+
+			lastId = records[len(records)-1].Id
+		}
+	}
+
+	return nil
+}
+
+func (app *BaseApp) registerRecordExpiryHooks() {
+	// run on every minute to delete the expired records
+	app.Cron().Add("__pbRecordExpirySweep__", "* * * * *", func() {
+		if err := app.DeleteExpiredRecords(); err != nil {
+			app.Logger().Warn("Failed to delete expired records", "error", err)
+		}
+	})
+}
@@ -0,0 +1,265 @@
+package core_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// createExpiryTestCollection creates a "test_expiry" collection with
+// the specified expiry config and 3 records:
+//   - "expirya00000000" - without expiry date
+//   - "expiryb00000000" - with expiry date in the future
+//   - "expiryc00000000" - with expiry date in the past
+//
+// For TTL configs the "created" date is used instead of the "expires" field.
+func createExpiryTestCollection(t testing.TB, app core.App, config *core.ExpiryConfig) *core.Collection {
+	collection := core.NewBaseCollection("test_expiry")
+	collection.Fields.Add(
+		&core.DateField{Name: "expires"},
+		&core.AutodateField{Name: core.FieldNameCreated, OnCreate: true},
+	)
+	collection.Expiry = config
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	dates := map[string]types.DateTime{
+		"expirya00000000": {},
+		"expiryb00000000": types.NowDateTime().Add(1 * time.Hour),
+		"expiryc00000000": types.NowDateTime().Add(-1 * time.Hour),
+	}
+
+	for id, date := range dates {
+		record := core.NewRecord(collection)
+		record.Id = id
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+
+		column := "expires"
+		if config.Field == "" {
+			column = core.FieldNameCreated
+			if date.IsZero() {
+				date = types.NowDateTime()
+			} else {
+				date = date.Add(-1 * config.TTLDuration())
+			}
+		}
+
+		_, err := app.DB().Update(
+			collection.Name,
+			dbx.Params{column: date.String()},
+			dbx.HashExp{"id": id},
+		).Execute()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return collection
+}
+
+func TestRecordIsExpired(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name     string
+		config   *core.ExpiryConfig
+		expected map[string]bool
+	}{
+		{
+			"disabled expiry",
+			&core.ExpiryConfig{Field: "expires"},
+			map[string]bool{"expirya00000000": false, "expiryb00000000": false, "expiryc00000000": false},
+		},
+		{
+			"expiry field",
+			&core.ExpiryConfig{Enabled: true, Field: "expires"},
+			map[string]bool{"expirya00000000": false, "expiryb00000000": false, "expiryc00000000": true},
+		},
+		{
+			"expiry ttl",
+			&core.ExpiryConfig{Enabled: true, TTL: 60},
+			map[string]bool{"expirya00000000": false, "expiryb00000000": false, "expiryc00000000": true},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			app, _ := tests.NewTestApp()
+			defer app.Cleanup()
+
+			collection := createExpiryTestCollection(t, app, s.config)
+
+			for id, expected := range s.expected {
+				record, err := app.FindRecordById(collection, id)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if v := record.IsExpired(); v != expected {
+					t.Errorf("[%s] Expected IsExpired %v, got %v", id, expected, v)
+				}
+			}
+
+			expr := core.ExpiredRecordsExpr(collection)
+			if !collection.ExpiryEnabled() {
+				if expr != nil {
+					t.Fatalf("Expected nil expression, got %v", expr)
+				}
+				return
+			}
+
+			records, err := app.FindAllRecords(collection, expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(records) != 1 || records[0].Id != "expiryc00000000" {
+				t.Fatalf("Expected only the expiryc00000000 record to be matched, got %v", records)
+			}
+		})
+	}
+}
+
+func TestDeleteExpiredRecords(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name       string
+		config     *core.ExpiryConfig
+		softDelete bool
+	}{
+		{"expiry field", &core.ExpiryConfig{Enabled: true, Field: "expires"}, false},
+		{"expiry ttl", &core.ExpiryConfig{Enabled: true, TTL: 60}, false},
+		{"expiry field with soft delete", &core.ExpiryConfig{Enabled: true, Field: "expires"}, true},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			app, _ := tests.NewTestApp()
+			defer app.Cleanup()
+
+			collection := createExpiryTestCollection(t, app, s.config)
+
+			if s.softDelete {
+				collection.SoftDelete = &core.SoftDeleteConfig{Enabled: true}
+				if err := app.Save(collection); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var deleted []string
+			app.OnRecordAfterDeleteSuccess(collection.Name).BindFunc(func(e *core.RecordEvent) error {
+				deleted = append(deleted, e.Record.Id)
+				return e.Next()
+			})
+
+			// run twice to ensure that the soft deleted records are not deleted again
+			for i := 0; i < 2; i++ {
+				if err := app.DeleteExpiredRecords(); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if len(deleted) != 1 || deleted[0] != "expiryc00000000" {
+				t.Fatalf("Expected only the expiryc00000000 record to be deleted, got %v", deleted)
+			}
+
+			for id, expectExists := range map[string]bool{
+				"expirya00000000": true,
+				"expiryb00000000": true,
+				"expiryc00000000": s.softDelete,
+			} {
+				record, err := app.FindRecordById(collection, id)
+				if exists := err == nil; exists != expectExists {
+					t.Errorf("[%s] Expected exists %v, got %v", id, expectExists, exists)
+				}
+				if s.softDelete && record != nil && id == "expiryc00000000" && !record.IsSoftDeleted() {
+					t.Errorf("[%s] Expected the record to be soft deleted", id)
+				}
+			}
+		})
+	}
+}
+
+func TestDeleteExpiredRecordsBatchesAndFailures(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := createExpiryTestCollection(t, app, &core.ExpiryConfig{Enabled: true, Field: "expires"})
+
+	// more than a single batch
+	total := 250
+	for i := 0; i < total; i++ {
+		record := core.NewRecord(collection)
+		record.Id = fmt.Sprintf("expiryx%08d", i)
+		record.Set("expires", types.NowDateTime().Add(-1*time.Hour))
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	failed := map[string]bool{"expiryc00000000": true, "expiryx00000120": true}
+	attempts := map[string]int{}
+	app.OnRecordDelete(collection.Name).BindFunc(func(e *core.RecordEvent) error {
+		attempts[e.Record.Id]++
+		if failed[e.Record.Id] {
+			return errors.New("test")
+		}
+		return e.Next()
+	})
+
+	if err := app.DeleteExpiredRecords(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(attempts) != total+1 {
+		t.Fatalf("Expected %d delete attempts, got %d", total+1, len(attempts))
+	}
+
+	for id := range failed {
+		if attempts[id] != 1 {
+			t.Fatalf("Expected a single delete attempt for the failed record %q, got %d", id, attempts[id])
+		}
+	}
+
+	records, err := app.FindAllRecords(collection)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the not expired and the failed records
+	expected := map[string]bool{"expirya00000000": true, "expiryb00000000": true, "expiryc00000000": true, "expiryx00000120": true}
+	if len(records) != len(expected) {
+		t.Fatalf("Expected %d remaining records, got %d", len(expected), len(records))
+	}
+	for _, r := range records {
+		if !expected[r.Id] {
+			t.Fatalf("Unexpected remaining record %q", r.Id)
+		}
+	}
+}
+
+func TestRecordExpirySweepCronJob(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	for _, job := range app.Cron().Jobs() {
+		if job.Id() == "__pbRecordExpirySweep__" {
+			return
+		}
+	}
+
+	t.Fatal("Expected the __pbRecordExpirySweep__ cron job to be registered")
+}
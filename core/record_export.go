@@ -0,0 +1,496 @@
+package core
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/tools/picker"
+	"github.com/pocketbase/pocketbase/tools/search"
+	"github.com/pocketbase/pocketbase/tools/xlsx"
+)
+
+const (
+	RecordExportFormatCSV    = "csv"
+	RecordExportFormatNDJSON = "ndjson"
+	RecordExportFormatXLSX   = "xlsx"
+)
+
+// DefaultRecordExportBatchSize is the default number
+// of records that are loaded in memory at once during export.
+const DefaultRecordExportBatchSize = 200
+
+// RecordExportOptions defines the [ExportRecords] configuration options.
+type RecordExportOptions struct {
+	// Format specifies the output format
+	// (RecordExportFormatCSV, RecordExportFormatNDJSON or RecordExportFormatXLSX).
+	Format string
+
+	// Fields specifies an optional fields picker expression
+	// (the same as the "fields" query parameter of the records list API).
+	Fields string
+
+	// Expand specifies the relations to expand.
+	//
+	// For the CSV and XLSX formats the expanded record fields are
+	// flattened as columns prefixed with the expand path (eg. "author.name").
+	Expand []string
+
+	// BatchSize specifies the number of records that are loaded
+	// in memory at once (default to [DefaultRecordExportBatchSize]).
+	BatchSize int
+
+	// AllowHiddenFields specifies whether to include the hidden fields columns.
+	AllowHiddenFields bool
+
+	// Enrich is an optional function that is invoked for each loaded
+	// records batch before writing them (eg. to expand the relations
+	// and to apply the request access checks).
+	//
+	// If not set, the Expand relations are loaded without access checks and
+	// when AllowHiddenFields is set all records fields are made visible.
+	Enrich func(records []*Record) error
+}
+
+// recordExportColumn describes a single CSV/XLSX export column.
+type recordExportColumn struct {
+	name string   // eg. "author.name"
+	path []string // eg. ["expand", "author", "name"]
+}
+
+// ExportRecords streams the collection records fetched by the provided
+// search provider (with already applied filter and sort) to w.
+//
+// If provider is nil, all collection records are exported.
+//
+// The records are fetched in batches using keyset pagination so that
+// the memory usage remains constant regardless of the number of records.
+//
+// Returns the number of the exported records.
+func ExportRecords(
+	ctx context.Context,
+	app App,
+	collection *Collection,
+	provider *search.Provider,
+	w io.Writer,
+	options RecordExportOptions,
+) (int, error) {
+	batchSize := options.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultRecordExportBatchSize
+	}
+
+	enrich := options.Enrich
+	if enrich == nil {
+		enrich = defaultRecordExportEnrich(app, options)
+	}
+
+	columns, err := recordExportColumns(app, collection, options)
+	if err != nil {
+		return 0, err
+	}
+
+	out, err := newRecordExportWriter(w, options.Format, collection.Name, columns)
+	if err != nil {
+		return 0, err
+	}
+
+	if provider == nil {
+		provider = search.NewProvider(NewRecordFieldResolver(app, collection, nil, true)).
+			Query(app.RecordQuery(collection))
+	}
+
+	provider.SkipTotal(true).PerPage(batchSize).Cursor("")
+
+	var total int
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, errors.Join(err, out.close())
+		}
+
+		records := []*Record{}
+
+		result, err := provider.Exec(&records)
+		if err != nil {
+			return total, errors.Join(err, out.close())
+		}
+
+		if len(records) > 0 {
+			if err := enrich(records); err != nil {
+				return total, errors.Join(err, out.close())
+			}
+
+			for _, record := range records {
+				data, err := picker.Pick(record, options.Fields)
+				if err != nil {
+					return total, errors.Join(err, out.close())
+				}
+
+				if err := out.write(data); err != nil {
+					return total, errors.Join(err, out.close())
+				}
+
+				total++
+			}
+
+			if err := out.flush(); err != nil {
+				return total, errors.Join(err, out.close())
+			}
+		}
+
+		if result.NextCursor == "" {
+			break
+		}
+
+		provider.Cursor(result.NextCursor)
+	}
+
+	return total, out.close()
+}
+
+func defaultRecordExportEnrich(app App, options RecordExportOptions) func(records []*Record) error {
+	return func(records []*Record) error {
+		if len(options.Expand) > 0 {
+			failed := app.ExpandRecords(records, options.Expand, nil)
+			for expand, err := range failed {
+				return fmt.Errorf("failed to expand %q: %w", expand, err)
+			}
+		}
+
+		if options.AllowHiddenFields {
+			unhideRecordsWithExpand(records)
+		}
+
+		return nil
+	}
+}
+
+// unhideRecordsWithExpand makes visible all fields of the
+// provided records, including their expanded relations.
+func unhideRecordsWithExpand(records []*Record) {
+	for _, r := range records {
+		r.Unhide(r.Collection().Fields.FieldNames()...)
+		r.IgnoreEmailVisibility(true)
+
+		for _, v := range r.Expand() {
+			switch rels := v.(type) {
+			case *Record:
+				unhideRecordsWithExpand([]*Record{rels})
+			case []*Record:
+				unhideRecordsWithExpand(rels)
+			}
+		}
+	}
+}
+
+// -------------------------------------------------------------------
+
+// recordExportColumns resolves the CSV/XLSX columns from the collection
+// fields, the expanded relations fields and the fields picker expression.
+func recordExportColumns(app App, collection *Collection, options RecordExportOptions) ([]recordExportColumn, error) {
+	var columns []recordExportColumn
+
+	// json shaped placeholder of the exported record used for evaluating the fields picker
+	template := map[string]any{}
+
+	addFields := func(c *Collection, path []string, dst map[string]any) {
+		for _, f := range c.Fields {
+			name := f.GetName()
+
+			if c.IsAuth() && (name == FieldNamePassword || name == FieldNameTokenKey) {
+				continue
+			}
+
+			if f.GetHidden() && !options.AllowHiddenFields {
+				continue
+			}
+
+			dst[name] = ""
+
+			columns = append(columns, recordExportColumn{
+				name: strings.Join(append(slices.Clone(path), name), "."),
+				path: append(recordExportExpandPath(path), name),
+			})
+		}
+	}
+
+	addFields(collection, nil, template)
+
+	for _, expand := range options.Expand {
+		current := collection
+		currentTemplate := template
+
+		parts := strings.Split(strings.TrimSpace(expand), ".")
+		for i, part := range parts {
+			expandTemplate, _ := currentTemplate[FieldNameExpand].(map[string]any)
+			if expandTemplate == nil {
+				expandTemplate = map[string]any{}
+				currentTemplate[FieldNameExpand] = expandTemplate
+			}
+
+			rel, err := recordExportRelCollection(app, current, part)
+			if err != nil {
+				return nil, err
+			}
+
+			relTemplate, exists := expandTemplate[part].(map[string]any)
+			if !exists {
+				relTemplate = map[string]any{}
+				expandTemplate[part] = relTemplate
+				addFields(rel, parts[:i+1], relTemplate)
+			}
+
+			current = rel
+			currentTemplate = relTemplate
+		}
+	}
+
+	if options.Fields == "" {
+		return columns, nil
+	}
+
+	picked, err := picker.Pick(template, options.Fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(columns, func(c recordExportColumn) bool {
+		_, ok := recordExportPathValue(picked, c.path)
+		return !ok
+	}), nil
+}
+
+// recordExportExpandPath converts a relations path (eg. ["author", "company"])
+// to its json export path (eg. ["expand", "author", "expand", "company"]).
+func recordExportExpandPath(relPath []string) []string {
+	result := make([]string, 0, len(relPath)*2+1)
+
+	for _, rel := range relPath {
+		result = append(result, FieldNameExpand, rel)
+	}
+
+	return result
+}
+
+// recordExportRelCollection returns the related collection of a single expand path part
+// (either a relation field name or a back-relation in the format "collection_via_field").
+func recordExportRelCollection(app App, collection *Collection, expand string) (*Collection, error) {
+	if matches := indirectExpandRegex.FindStringSubmatch(expand); len(matches) == 3 {
+		rel, err := app.FindCachedCollectionByNameOrId(matches[1])
+		if err != nil {
+			return nil, fmt.Errorf("couldn't find back-related collection %q", matches[1])
+		}
+
+		relField, _ := rel.Fields.GetByName(matches[2]).(*RelationField)
+		if relField == nil || relField.CollectionId != collection.Id {
+			return nil, fmt.Errorf("couldn't find back-relation field %q in collection %q", matches[2], rel.Name)
+		}
+
+		return rel, nil
+	}
+
+	relField, _ := collection.Fields.GetByName(expand).(*RelationField)
+	if relField == nil {
+		return nil, fmt.Errorf("couldn't find relation field %q in collection %q", expand, collection.Name)
+	}
+
+	rel, err := app.FindCachedCollectionByNameOrId(relField.CollectionId)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't find related collection %q", relField.CollectionId)
+	}
+
+	return rel, nil
+}
+
+// recordExportPathValue returns the value at the provided path of a plain json value.
+//
+// Arrays (eg. multiple relations) are traversed and their
+// items values are combined in a single flat slice.
+func recordExportPathValue(data any, path []string) (any, bool) {
+	if len(path) == 0 {
+		return data, true
+	}
+
+	switch v := data.(type) {
+	case map[string]any:
+		item, ok := v[path[0]]
+		if !ok {
+			return nil, false
+		}
+		return recordExportPathValue(item, path[1:])
+	case []any:
+		result := make([]any, 0, len(v))
+		for _, item := range v {
+			value, ok := recordExportPathValue(item, path)
+			if !ok {
+				continue
+			}
+			if values, ok := value.([]any); ok {
+				result = append(result, values...)
+			} else {
+				result = append(result, value)
+			}
+		}
+		return result, true
+	}
+
+	return nil, false
+}
+
+// -------------------------------------------------------------------
+
+type recordExportWriter interface {
+	write(data any) error
+	flush() error
+	close() error
+}
+
+func newRecordExportWriter(w io.Writer, format string, sheetName string, columns []recordExportColumn) (recordExportWriter, error) {
+	switch format {
+	case RecordExportFormatCSV:
+		return newCSVRecordExportWriter(w, columns)
+	case RecordExportFormatXLSX:
+		return newXLSXRecordExportWriter(w, sheetName, columns)
+	case RecordExportFormatNDJSON:
+		return &ndjsonRecordExportWriter{enc: json.NewEncoder(w)}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported export format %q", format)
+}
+
+// csv
+// ---
+
+type csvRecordExportWriter struct {
+	cw      *csv.Writer
+	columns []recordExportColumn
+	row     []string
+}
+
+func newCSVRecordExportWriter(w io.Writer, columns []recordExportColumn) (*csvRecordExportWriter, error) {
+	out := &csvRecordExportWriter{
+		cw:      csv.NewWriter(w),
+		columns: columns,
+		row:     make([]string, len(columns)),
+	}
+
+	for i, c := range columns {
+		out.row[i] = c.name
+	}
+
+	return out, out.cw.Write(out.row)
+}
+
+func (w *csvRecordExportWriter) write(data any) error {
+	for i, c := range w.columns {
+		value, _ := recordExportPathValue(data, c.path)
+		w.row[i] = recordExportCellString(value)
+	}
+
+	return w.cw.Write(w.row)
+}
+
+func (w *csvRecordExportWriter) flush() error {
+	w.cw.Flush()
+	return w.cw.Error()
+}
+
+func (w *csvRecordExportWriter) close() error {
+	return w.flush()
+}
+
+// xlsx
+// ---
+
+type xlsxRecordExportWriter struct {
+	xw      *xlsx.Writer
+	columns []recordExportColumn
+	row     []any
+}
+
+func newXLSXRecordExportWriter(w io.Writer, sheetName string, columns []recordExportColumn) (*xlsxRecordExportWriter, error) {
+	xw, err := xlsx.NewWriter(w, sheetName)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &xlsxRecordExportWriter{
+		xw:      xw,
+		columns: columns,
+		row:     make([]any, len(columns)),
+	}
+
+	for i, c := range columns {
+		out.row[i] = c.name
+	}
+
+	return out, out.xw.WriteRow(out.row)
+}
+
+func (w *xlsxRecordExportWriter) write(data any) error {
+	for i, c := range w.columns {
+		value, _ := recordExportPathValue(data, c.path)
+
+		switch v := value.(type) {
+		case nil, bool, float64, string:
+			w.row[i] = v
+		default:
+			w.row[i] = recordExportCellString(v)
+		}
+	}
+
+	return w.xw.WriteRow(w.row)
+}
+
+func (w *xlsxRecordExportWriter) flush() error {
+	return w.xw.Flush()
+}
+
+func (w *xlsxRecordExportWriter) close() error {
+	return w.xw.Close()
+}
+
+// ndjson
+// ---
+
+type ndjsonRecordExportWriter struct {
+	enc *json.Encoder
+}
+
+func (w *ndjsonRecordExportWriter) write(data any) error {
+	return w.enc.Encode(data)
+}
+
+func (w *ndjsonRecordExportWriter) flush() error {
+	return nil
+}
+
+func (w *ndjsonRecordExportWriter) close() error {
+	return nil
+}
+
+// recordExportCellString converts a plain json value to a single cell string
+// (nested values like arrays and objects are serialized as json).
+func recordExportCellString(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+
+	raw, _ := json.Marshal(value)
+
+	return string(raw)
+}
@@ -0,0 +1,255 @@
+package core_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/search"
+)
+
+func createExportTestCollections(t testing.TB, app core.App) (*core.Collection, *core.Collection) {
+	authors := core.NewBaseCollection("test_export_authors")
+	authors.Fields.Add(
+		&core.TextField{Name: "name"},
+		&core.TextField{Name: "secret", Hidden: true},
+	)
+	if err := app.Save(authors); err != nil {
+		t.Fatal(err)
+	}
+
+	posts := core.NewBaseCollection("test_export_posts")
+	posts.Fields.Add(
+		&core.TextField{Name: "title"},
+		&core.NumberField{Name: "total"},
+		&core.BoolField{Name: "active"},
+		&core.RelationField{Name: "author", CollectionId: authors.Id, MaxSelect: 1},
+		&core.RelationField{Name: "coauthors", CollectionId: authors.Id, MaxSelect: 5},
+	)
+	if err := app.Save(posts); err != nil {
+		t.Fatal(err)
+	}
+
+	authorIds := make([]string, 2)
+	for i, name := range []string{"a1", "a2"} {
+		author := core.NewRecord(authors)
+		author.Set("name", name)
+		author.Set("secret", name+"_secret")
+		if err := app.Save(author); err != nil {
+			t.Fatal(err)
+		}
+		authorIds[i] = author.Id
+	}
+
+	for i, title := range []string{"p1", "p2", "p3"} {
+		post := core.NewRecord(posts)
+		post.Set("title", title)
+		post.Set("total", i+1)
+		post.Set("active", i%2 == 0)
+		post.Set("author", authorIds[i%2])
+		post.Set("coauthors", authorIds)
+		if err := app.Save(post); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return posts, authors
+}
+
+func TestExportRecordsCSV(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	posts, _ := createExportTestCollections(t, app)
+
+	provider := search.NewProvider(core.NewRecordFieldResolver(app, posts, nil, true)).
+		Query(app.RecordQuery(posts))
+	provider.AddSort(search.SortField{Name: "total", Direction: search.SortDesc})
+	provider.AddFilter("total > 1")
+
+	var buf bytes.Buffer
+
+	total, err := core.ExportRecords(context.Background(), app, posts, provider, &buf, core.RecordExportOptions{
+		Format:    core.RecordExportFormatCSV,
+		Fields:    "title,total,active,expand.author.name,expand.author.secret,expand.coauthors.name",
+		Expand:    []string{"author", "coauthors"},
+		BatchSize: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if total != 2 {
+		t.Fatalf("Expected 2 exported records, got %d", total)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the hidden fields are not exported without AllowHiddenFields
+	expected := [][]string{
+		{"title", "total", "active", "author.name", "coauthors.name"},
+		{"p3", "3", "true", "a1", `["a1","a2"]`},
+		{"p2", "2", "false", "a2", `["a1","a2"]`},
+	}
+
+	if len(rows) != len(expected) {
+		t.Fatalf("Expected %d rows, got %d: %v", len(expected), len(rows), rows)
+	}
+
+	for i, row := range rows {
+		if strings.Join(row, "|") != strings.Join(expected[i], "|") {
+			t.Fatalf("[%d] Expected row\n%v\ngot\n%v", i, expected[i], row)
+		}
+	}
+}
+
+func TestExportRecordsNDJSON(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	posts, _ := createExportTestCollections(t, app)
+
+	var buf bytes.Buffer
+
+	total, err := core.ExportRecords(context.Background(), app, posts, nil, &buf, core.RecordExportOptions{
+		Format:            core.RecordExportFormatNDJSON,
+		Fields:            "title,expand.author.secret",
+		Expand:            []string{"author"},
+		AllowHiddenFields: true,
+		BatchSize:         2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if total != 3 || len(lines) != 3 {
+		t.Fatalf("Expected 3 exported records, got %d (%d lines)", total, len(lines))
+	}
+
+	titles := map[string]string{}
+	for _, line := range lines {
+		item := struct {
+			Title  string `json:"title"`
+			Expand struct {
+				Author map[string]any `json:"author"`
+			} `json:"expand"`
+		}{}
+		if err := json.Unmarshal([]byte(line), &item); err != nil {
+			t.Fatal(err)
+		}
+		if len(item.Expand.Author) != 1 {
+			t.Fatalf("Expected only the picked author fields, got %v", item.Expand.Author)
+		}
+		titles[item.Title], _ = item.Expand.Author["secret"].(string)
+	}
+
+	expected := map[string]string{"p1": "a1_secret", "p2": "a2_secret", "p3": "a1_secret"}
+	for title, secret := range expected {
+		if titles[title] != secret {
+			t.Fatalf("Expected %q secret %q, got %q", title, secret, titles[title])
+		}
+	}
+}
+
+func TestExportRecordsXLSX(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	posts, _ := createExportTestCollections(t, app)
+
+	var buf bytes.Buffer
+
+	total, err := core.ExportRecords(context.Background(), app, posts, nil, &buf, core.RecordExportOptions{
+		Format: core.RecordExportFormatXLSX,
+		Fields: "title,total",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if total != 3 {
+		t.Fatalf("Expected 3 exported records, got %d", total)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sheet string
+	for _, f := range zr.File {
+		if f.Name != "xl/worksheets/sheet1.xml" {
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		sheet = string(raw)
+	}
+
+	if n := strings.Count(sheet, "<row>"); n != 4 {
+		t.Fatalf("Expected 4 rows (header + 3 records), got %d:\n%s", n, sheet)
+	}
+
+	for _, part := range []string{">title<", ">total<", ">p2<", "<c><v>2</v></c>"} {
+		if !strings.Contains(sheet, part) {
+			t.Fatalf("Missing %q in sheet:\n%s", part, sheet)
+		}
+	}
+}
+
+func TestExportRecordsInvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	posts, _ := createExportTestCollections(t, app)
+
+	scenarios := []struct {
+		name    string
+		options core.RecordExportOptions
+	}{
+		{"invalid format", core.RecordExportOptions{Format: "xml"}},
+		{"non-relation expand", core.RecordExportOptions{Format: core.RecordExportFormatCSV, Expand: []string{"title"}}},
+		{"missing back-relation", core.RecordExportOptions{Format: core.RecordExportFormatCSV, Expand: []string{"missing_via_author"}}},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			_, err := core.ExportRecords(context.Background(), app, posts, nil, &buf, s.options)
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+
+			if buf.Len() != 0 {
+				t.Fatalf("Expected nothing to be written, got %q", buf.String())
+			}
+		})
+	}
+}
@@ -8,7 +8,9 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/ganigeorgiev/fexpr"
 	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/inflector"
 	"github.com/pocketbase/pocketbase/tools/search"
 	"github.com/pocketbase/pocketbase/tools/security"
 	"github.com/pocketbase/pocketbase/tools/types"
@@ -23,8 +25,15 @@ const (
 	lowerModifier  string = "lower"
 )
 
-// ensure that `search.FieldResolver` interface is implemented
-var _ search.FieldResolver = (*RecordFieldResolver)(nil)
+// ensure that `search.FieldResolver` and `search.FunctionResolver` interfaces are implemented
+var (
+	_ search.FieldResolver    = (*RecordFieldResolver)(nil)
+	_ search.FunctionResolver = (*RecordFieldResolver)(nil)
+)
+
+// searchRankField is the special sort/filter field that resolves to
+// the relevance score of the first fts() filter function.
+const searchRankField = "@rank"
 
 // RecordFieldResolver defines a custom search resolver struct for
 // managing Record model search fields.
@@ -47,7 +56,11 @@ type RecordFieldResolver struct {
 	staticRequestInfo map[string]any
 	allowedFields     []string
 	joins             []*join
+	searchMatches     []*search.ResolverResult
 	allowHiddenFields bool
+
+	// indicates whether the base collection "deleted" field was resolved
+	softDeleteFieldResolved bool
 }
 
 // AllowedFields returns a copy of the resolver's allowed fields.
@@ -70,6 +83,13 @@ func (r *RecordFieldResolver) SetAllowHiddenFields(allowHiddenFields bool) {
 	r.allowHiddenFields = allowHiddenFields
 }
 
+// SoftDeleteFieldResolved reports whether the resolver has resolved
+// the base collection "deleted" field, aka. whether the soft deleted
+// records are explicitly referenced by the resolved expressions.
+func (r *RecordFieldResolver) SoftDeleteFieldResolved() bool {
+	return r.softDeleteFieldResolved
+}
+
 // NewRecordFieldResolver creates and initializes a new `RecordFieldResolver`.
 func NewRecordFieldResolver(
 	app App,
@@ -92,6 +112,7 @@ func NewRecordFieldResolver(
 			`^\@request\.query\.[\w\.\:]*\w+$`,
 			`^\@request\.headers\.[\w\.\:]*\w+$`,
 			`^\@collection\.\w+(\:\w+)?\.[\w\.\:]*\w+$`,
+			`^\@rank$`,
 		},
 	}
 
@@ -156,6 +177,171 @@ func (r *RecordFieldResolver) Resolve(fieldName string) (*search.ResolverResult,
 	return parseAndRun(fieldName, r)
 }
 
+// ResolveFunction implements `search.FunctionResolver` interface.
+//
+// It resolves the collection full-text search function:
+//
+//	fts('query') = true
+//
+// The query argument uses the SQLite FTS5 query syntax and it is
+// applied only on the collection searchable fields.
+//
+// It also resolves the indexed vector field nearest neighbors function:
+//
+//	vectorNearest(field, query, k) = true
+//
+// The query argument could be a serialized json array text or a
+// @request.* placeholder and it matches the approximate k nearest records
+// (before applying the other filter and rule constraints).
+func (r *RecordFieldResolver) ResolveFunction(
+	name string,
+	argTokenResolverFunc func(fexpr.Token) (*search.ResolverResult, error),
+	args ...fexpr.Token,
+) (*search.ResolverResult, error) {
+	switch name {
+	case "fts":
+		return r.resolveSearchFunction(argTokenResolverFunc, args...)
+	case "vectorNearest":
+		return r.resolveVectorNearestFunction(argTokenResolverFunc, args...)
+	default:
+		return nil, nil // not a resolver function
+	}
+}
+
+func (r *RecordFieldResolver) resolveSearchFunction(
+	argTokenResolverFunc func(fexpr.Token) (*search.ResolverResult, error),
+	args ...fexpr.Token,
+) (*search.ResolverResult, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("[fts] expected 1 argument, got %d", len(args))
+	}
+
+	if !r.baseCollection.SearchEnabled() {
+		return nil, fmt.Errorf("[fts] collection %q doesn't have searchable fields", r.baseCollection.Name)
+	}
+
+	arg, err := argTokenResolverFunc(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("[fts] failed to resolve the query argument: %w", err)
+	}
+
+	// restrict the query only to the searchable fields (aka. exclude the id column)
+	columns := make([]string, len(r.baseCollection.Search.Fields))
+	for i, name := range r.baseCollection.Search.Fields {
+		columns[i] = `"` + name + `"`
+	}
+	match := &search.ResolverResult{
+		Identifier: "('{" + strings.Join(columns, " ") + "} : (' || " + arg.Identifier + " || ')')",
+		Params:     arg.Params,
+	}
+
+	r.searchMatches = append(r.searchMatches, match)
+
+	tableName := searchTableName(r.baseCollection)
+
+	return &search.ResolverResult{
+		NoCoalesce: true,
+		Identifier: "([[" + inflector.Columnify(r.baseCollection.Name) + "." + FieldNameId + "]] IN (" +
+			"SELECT [[" + FieldNameId + "]] FROM {{" + tableName + "}} WHERE {{" + tableName + "}} MATCH " + match.Identifier +
+			"))",
+		Params: match.Params,
+	}, nil
+}
+
+// vectorNearestMaxK is the max allowed vectorNearest() k argument.
+const vectorNearestMaxK = 1000
+
+func (r *RecordFieldResolver) resolveVectorNearestFunction(
+	argTokenResolverFunc func(fexpr.Token) (*search.ResolverResult, error),
+	args ...fexpr.Token,
+) (*search.ResolverResult, error) {
+	if len(args) != 3 {
+		return nil, fmt.Errorf("[vectorNearest] expected 3 arguments, got %d", len(args))
+	}
+
+	if args[0].Type != fexpr.TokenIdentifier {
+		return nil, errors.New("[vectorNearest] the first argument must be a vector field name")
+	}
+
+	// ensure that the field is accessible (e.g. not hidden for regular users)
+	if _, err := argTokenResolverFunc(args[0]); err != nil {
+		return nil, fmt.Errorf("[vectorNearest] failed to resolve the field argument: %w", err)
+	}
+
+	// resolve the query vector
+	var rawQuery any
+	switch args[1].Type {
+	case fexpr.TokenText:
+		rawQuery = args[1].Literal
+	case fexpr.TokenIdentifier:
+		resolved, err := argTokenResolverFunc(args[1])
+		if err != nil {
+			return nil, fmt.Errorf("[vectorNearest] failed to resolve the query argument: %w", err)
+		}
+		if len(resolved.Params) != 1 {
+			return nil, errors.New("[vectorNearest] the query argument must be a static value")
+		}
+		for _, v := range resolved.Params {
+			rawQuery = v
+		}
+	default:
+		return nil, errors.New("[vectorNearest] the query argument must be a json array text or a @request.* placeholder")
+	}
+
+	query, err := types.ParseVector(rawQuery)
+	if err != nil {
+		return nil, fmt.Errorf("[vectorNearest] invalid query vector: %w", err)
+	}
+
+	k := cast.ToInt(args[2].Literal)
+	if args[2].Type != fexpr.TokenNumber || k <= 0 || k > vectorNearestMaxK {
+		return nil, fmt.Errorf("[vectorNearest] k must be a number between 1 and %d", vectorNearestMaxK)
+	}
+
+	results, err := FindVectorNearest(r.app, r.baseCollection, args[0].Literal, query, k)
+	if err != nil {
+		return nil, fmt.Errorf("[vectorNearest] %w", err)
+	}
+
+	ids := make([]string, len(results))
+	for i, result := range results {
+		ids[i] = result.Id
+	}
+	rawIds, _ := json.Marshal(ids)
+
+	placeholder := "vn" + security.PseudorandomString(6)
+
+	return &search.ResolverResult{
+		NoCoalesce: true,
+		Identifier: "([[" + inflector.Columnify(r.baseCollection.Name) + "." + FieldNameId + "]] IN (" +
+			"SELECT [[value]] FROM json_each({:" + placeholder + "})" +
+			"))",
+		Params: dbx.Params{placeholder: string(rawIds)},
+	}, nil
+}
+
+// resolveSearchRank joins the rank of the first resolved fts() filter function.
+func (r *RecordFieldResolver) resolveSearchRank() (*search.ResolverResult, error) {
+	if len(r.searchMatches) == 0 {
+		return nil, fmt.Errorf("%s requires a fts() filter function", searchRankField)
+	}
+
+	match := r.searchMatches[0]
+	tableName := searchTableName(r.baseCollection)
+	alias := "__fts_rank"
+
+	r.registerJoin(
+		"(SELECT [["+FieldNameId+"]], [[rank]] FROM {{"+tableName+"}} WHERE {{"+tableName+"}} MATCH "+match.Identifier+")",
+		alias,
+		dbx.NewExp(
+			"[["+alias+"."+FieldNameId+"]] = [["+inflector.Columnify(r.baseCollection.Name)+"."+FieldNameId+"]]",
+			match.Params,
+		),
+	)
+
+	return &search.ResolverResult{Identifier: "[[" + alias + ".rank]]"}, nil
+}
+
 func (r *RecordFieldResolver) resolveStaticRequestField(path ...string) (*search.ResolverResult, error) {
 	if len(path) == 0 {
 		return nil, errors.New("at least one path key should be provided")
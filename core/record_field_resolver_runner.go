@@ -74,6 +74,10 @@ func (r *runner) run() (*search.ResolverResult, error) {
 
 	r.prepare()
 
+	if r.fieldName == searchRankField {
+		return r.resolver.resolveSearchRank()
+	}
+
 	// check for @collection field (aka. non-relational join)
 	// must be in the format "@collection.COLLECTION_NAME.FIELD[.FIELD2....]"
 	if r.activeProps[0] == "@collection" {
@@ -180,13 +184,18 @@ func (r *runner) processCollectionField() (*search.ResolverResult, error) {
 	r.withMultiMatch = true
 
 	// join the collection to the main query
-	r.resolver.registerJoin(inflector.Columnify(collection.Name), r.activeTableAlias, nil)
+	r.resolver.registerJoin(
+		inflector.Columnify(collection.Name),
+		r.activeTableAlias,
+		r.withTenantScope(nil, collection, r.activeTableAlias),
+	)
 
 	// join the collection to the multi-match subquery
 	r.multiMatchActiveTableAlias = "__mm" + r.activeTableAlias
 	r.multiMatch.joins = append(r.multiMatch.joins, &join{
 		tableName:  inflector.Columnify(collection.Name),
 		tableAlias: r.multiMatchActiveTableAlias,
+		on:         r.withTenantScope(nil, collection, r.multiMatchActiveTableAlias),
 	})
 
 	// leave only the collection fields
@@ -415,13 +424,17 @@ func (r *runner) processActiveProps() (*search.ResolverResult, error) {
 
 		field := collection.Fields.GetByName(prop)
 
-		if field != nil && field.GetHidden() && !r.allowHiddenFields {
+		if field != nil && r.isNonFilterableField(collection, field) {
 			return nil, fmt.Errorf("non-filterable field %q", prop)
 		}
 
 		// json or geoPoint field -> treat the rest of the props as json path
 		// @todo consider converting to "JSONExtractable" interface with optional extra validation for the remaining props?
 		if field != nil && (field.Type() == FieldTypeJSON || field.Type() == FieldTypeGeoPoint) {
+			if isEncryptedField(field) {
+				return nil, fmt.Errorf("the encrypted field %q doesn't support nested props", prop)
+			}
+
 			var jsonPath strings.Builder
 			for j, p := range r.activeProps[i+1:] {
 				if _, err := strconv.Atoi(p); err == nil {
@@ -488,7 +501,7 @@ func (r *runner) processActiveProps() (*search.ResolverResult, error) {
 				return nil, fmt.Errorf("invalid back relation field %q", parts[2])
 			}
 
-			if backField.GetHidden() && !r.allowHiddenFields {
+			if r.isNonFilterableField(backCollection, backField) {
 				return nil, fmt.Errorf("non-filterable back relation field %q", backField.GetName())
 			}
 
@@ -522,20 +535,28 @@ func (r *runner) processActiveProps() (*search.ResolverResult, error) {
 				r.resolver.registerJoin(
 					newCollectionName,
 					newTableAlias,
-					dbx.NewExp(fmt.Sprintf("[[%s.%s]] = [[%s.id]]", newTableAlias, cleanBackFieldName, r.activeTableAlias)),
+					r.withTenantScope(
+						dbx.NewExp(fmt.Sprintf("[[%s.%s]] = [[%s.id]]", newTableAlias, cleanBackFieldName, r.activeTableAlias)),
+						backCollection,
+						newTableAlias,
+					),
 				)
 			} else {
 				jeAlias := r.activeTableAlias + "_" + cleanProp + "_je"
 				r.resolver.registerJoin(
 					newCollectionName,
 					newTableAlias,
-					dbx.NewExp(fmt.Sprintf(
-						"[[%s.id]] IN (SELECT [[%s.value]] FROM %s {{%s}})",
-						r.activeTableAlias,
-						jeAlias,
-						dbutils.JSONEach(newTableAlias+"."+cleanBackFieldName),
-						jeAlias,
-					)),
+					r.withTenantScope(
+						dbx.NewExp(fmt.Sprintf(
+							"[[%s.id]] IN (SELECT [[%s.value]] FROM %s {{%s}})",
+							r.activeTableAlias,
+							jeAlias,
+							dbutils.JSONEach(newTableAlias+"."+cleanBackFieldName),
+							jeAlias,
+						)),
+						backCollection,
+						newTableAlias,
+					),
 				)
 			}
 
@@ -557,7 +578,11 @@ func (r *runner) processActiveProps() (*search.ResolverResult, error) {
 					&join{
 						tableName:  newCollectionName,
 						tableAlias: newTableAlias2,
-						on:         dbx.NewExp(fmt.Sprintf("[[%s.%s]] = [[%s.id]]", newTableAlias2, cleanBackFieldName, r.multiMatchActiveTableAlias)),
+						on: r.withTenantScope(
+							dbx.NewExp(fmt.Sprintf("[[%s.%s]] = [[%s.id]]", newTableAlias2, cleanBackFieldName, r.multiMatchActiveTableAlias)),
+							backCollection,
+							newTableAlias2,
+						),
 					},
 				)
 			} else {
@@ -567,13 +592,17 @@ func (r *runner) processActiveProps() (*search.ResolverResult, error) {
 					&join{
 						tableName:  newCollectionName,
 						tableAlias: newTableAlias2,
-						on: dbx.NewExp(fmt.Sprintf(
-							"[[%s.id]] IN (SELECT [[%s.value]] FROM %s {{%s}})",
-							r.multiMatchActiveTableAlias,
-							jeAlias2,
-							dbutils.JSONEach(newTableAlias2+"."+cleanBackFieldName),
-							jeAlias2,
-						)),
+						on: r.withTenantScope(
+							dbx.NewExp(fmt.Sprintf(
+								"[[%s.id]] IN (SELECT [[%s.value]] FROM %s {{%s}})",
+								r.multiMatchActiveTableAlias,
+								jeAlias2,
+								dbutils.JSONEach(newTableAlias2+"."+cleanBackFieldName),
+								jeAlias2,
+							)),
+							backCollection,
+							newTableAlias2,
+						),
 					},
 				)
 			}
@@ -619,7 +648,11 @@ func (r *runner) processActiveProps() (*search.ResolverResult, error) {
 			r.resolver.registerJoin(
 				inflector.Columnify(newCollectionName),
 				newTableAlias,
-				dbx.NewExp(fmt.Sprintf("[[%s.id]] = [[%s]]", newTableAlias, prefixedFieldName)),
+				r.withTenantScope(
+					dbx.NewExp(fmt.Sprintf("[[%s.id]] = [[%s]]", newTableAlias, prefixedFieldName)),
+					relCollection,
+					newTableAlias,
+				),
 			)
 		} else {
 			jeAlias := r.activeTableAlias + "_" + cleanFieldName + "_je"
@@ -627,7 +660,11 @@ func (r *runner) processActiveProps() (*search.ResolverResult, error) {
 			r.resolver.registerJoin(
 				inflector.Columnify(newCollectionName),
 				newTableAlias,
-				dbx.NewExp(fmt.Sprintf("[[%s.id]] = [[%s.value]]", newTableAlias, jeAlias)),
+				r.withTenantScope(
+					dbx.NewExp(fmt.Sprintf("[[%s.id]] = [[%s.value]]", newTableAlias, jeAlias)),
+					relCollection,
+					newTableAlias,
+				),
 			)
 		}
 
@@ -650,7 +687,11 @@ func (r *runner) processActiveProps() (*search.ResolverResult, error) {
 				&join{
 					tableName:  inflector.Columnify(newCollectionName),
 					tableAlias: newTableAlias2,
-					on:         dbx.NewExp(fmt.Sprintf("[[%s.id]] = [[%s]]", newTableAlias2, prefixedFieldName2)),
+					on: r.withTenantScope(
+						dbx.NewExp(fmt.Sprintf("[[%s.id]] = [[%s]]", newTableAlias2, prefixedFieldName2)),
+						relCollection,
+						newTableAlias2,
+					),
 				},
 			)
 		} else {
@@ -664,7 +705,11 @@ func (r *runner) processActiveProps() (*search.ResolverResult, error) {
 				&join{
 					tableName:  inflector.Columnify(newCollectionName),
 					tableAlias: newTableAlias2,
-					on:         dbx.NewExp(fmt.Sprintf("[[%s.id]] = [[%s.value]]", newTableAlias2, jeAlias2)),
+					on: r.withTenantScope(
+						dbx.NewExp(fmt.Sprintf("[[%s.id]] = [[%s.value]]", newTableAlias2, jeAlias2)),
+						relCollection,
+						newTableAlias2,
+					),
 				},
 			)
 		}
@@ -676,6 +721,45 @@ func (r *runner) processActiveProps() (*search.ResolverResult, error) {
 	return nil, fmt.Errorf("failed to resolve field %q", r.fieldName)
 }
 
+// isNonFilterableField reports whether the field is not allowed to be
+// filtered or sorted when the hidden fields are not allowed.
+//
+// Fields with a field view rule are treated as hidden because their
+// values could be otherwise recovered with consecutive filter comparisons.
+func (r *runner) isNonFilterableField(collection *Collection, field Field) bool {
+	if r.allowHiddenFields {
+		return false
+	}
+
+	if field.GetHidden() {
+		return true
+	}
+
+	_, hasViewRule := collection.FieldViewRules()[field.GetName()]
+
+	return hasViewRule
+}
+
+// withTenantScope combines the join expression of the specified
+// collection table alias with the request auth tenant constraint
+// (if the collection is tenant scoped and the auth is not a superuser).
+func (r *runner) withTenantScope(on dbx.Expression, collection *Collection, tableAlias string) dbx.Expression {
+	if r.resolver.requestInfo == nil {
+		return on
+	}
+
+	scope := tenantScopeExpr(collection, r.resolver.requestInfo.Auth, tableAlias)
+	if scope == nil {
+		return on
+	}
+
+	if on == nil {
+		return scope
+	}
+
+	return dbx.And(on, scope)
+}
+
 func (r *runner) processLastProp(collection *Collection, prop string) (*search.ResolverResult, error) {
 	name, modifier, err := splitModifier(prop)
 	if err != nil {
@@ -690,10 +774,14 @@ func (r *runner) processLastProp(collection *Collection, prop string) (*search.R
 		return nil, fmt.Errorf("unknown field %q", name)
 	}
 
-	if field.GetHidden() && !r.allowHiddenFields {
+	if r.isNonFilterableField(collection, field) {
 		return nil, fmt.Errorf("non-filterable field %q", name)
 	}
 
+	if name == FieldNameDeleted && r.activeTableAlias == inflector.Columnify(r.resolver.baseCollection.Name) {
+		r.resolver.softDeleteFieldResolved = true
+	}
+
 	multvaluer, isMultivaluer := field.(MultiValuer)
 
 	cleanFieldName := inflector.Columnify(field.GetName())
@@ -747,6 +835,26 @@ func (r *runner) processLastProp(collection *Collection, prop string) (*search.R
 		return result, nil
 	}
 
+	// encrypted fields (compared only by their blind index)
+	// -------------------------------------------------------
+	if isEncryptedField(field) {
+		if modifier != "" {
+			return nil, fmt.Errorf("the encrypted field %q doesn't support modifiers", name)
+		}
+
+		result := &search.ResolverResult{
+			Identifier:        blindIndexIdentifier("[[" + r.activeTableAlias + "." + cleanFieldName + "]]"),
+			EqualityTransform: blindIndexTransform(field.(EncryptedValuer)),
+		}
+
+		if r.withMultiMatch {
+			r.multiMatch.valueIdentifier = blindIndexIdentifier("[[" + r.multiMatchActiveTableAlias + "." + cleanFieldName + "]]")
+			result.MultiMatchSubQuery = r.multiMatch
+		}
+
+		return result, nil
+	}
+
 	// default
 	// -------------------------------------------------------
 	result := &search.ResolverResult{
@@ -780,6 +888,17 @@ func (r *runner) processLastProp(collection *Collection, prop string) (*search.R
 		}
 	}
 
+	// cast the canonical decimal strings to REAL so that they are
+	// compared and sorted numerically (the max field precision ensures
+	// that the conversion preserves the ordering and equality)
+	if field.Type() == FieldTypeDecimal {
+		result.NoCoalesce = true
+		result.Identifier = "CAST(" + result.Identifier + " AS REAL)"
+		if r.withMultiMatch {
+			r.multiMatch.valueIdentifier = "CAST(" + r.multiMatch.valueIdentifier + " AS REAL)"
+		}
+	}
+
 	// account for the ":lower" modifier
 	if modifier == lowerModifier {
 		result.Identifier = "LOWER(" + result.Identifier + ")"
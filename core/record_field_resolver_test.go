@@ -2,6 +2,7 @@ package core_test
 
 import (
 	"encoding/json"
+	"fmt"
 	"regexp"
 	"slices"
 	"strings"
@@ -28,8 +29,8 @@ func TestRecordFieldResolverAllowedFields(t *testing.T) {
 	r := core.NewRecordFieldResolver(app, collection, nil, false)
 
 	fields := r.AllowedFields()
-	if len(fields) != 8 {
-		t.Fatalf("Expected %d original allowed fields, got %d", 8, len(fields))
+	if len(fields) != 9 {
+		t.Fatalf("Expected %d original allowed fields, got %d", 9, len(fields))
 	}
 
 	// change the allowed fields
@@ -80,6 +81,89 @@ func TestRecordFieldResolverAllowHiddenFields(t *testing.T) {
 	}
 }
 
+func TestRecordFieldResolverFieldViewRules(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	collection.FieldRules = map[string]core.FieldRule{
+		"title": {ViewRule: "active = true"},
+	}
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		allowHidden bool
+		field       string
+		expectError bool
+	}{
+		{false, "active", false},
+		{false, "title", true},
+		{false, "title:lower", true},
+		{true, "title", false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(fmt.Sprintf("%v_%s", s.allowHidden, s.field), func(t *testing.T) {
+			r := core.NewRecordFieldResolver(app, collection, &core.RequestInfo{}, s.allowHidden)
+
+			_, err := r.Resolve(s.field)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+		})
+	}
+}
+
+func TestRecordFieldResolverSoftDeleteFieldResolved(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	collection.SoftDelete = &core.SoftDeleteConfig{Enabled: true}
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		rule     string
+		expected bool
+	}{
+		{"title = 'deleted'", false},
+		{"@request.query.deleted = ''", false},
+		{"@collection.demo2.deleted = ''", false},
+		{"deleted = ''", true},
+		{"title != '' && deleted:lower != ''", true},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.rule, func(t *testing.T) {
+			r := core.NewRecordFieldResolver(app, collection, &core.RequestInfo{}, true)
+
+			if _, err := search.FilterData(s.rule).BuildExpr(r); err != nil {
+				t.Fatal(err)
+			}
+
+			if v := r.SoftDeleteFieldResolved(); v != s.expected {
+				t.Fatalf("Expected %v, got %v", s.expected, v)
+			}
+		})
+	}
+}
+
 func TestRecordFieldResolverUpdateQuery(t *testing.T) {
 	t.Parallel()
 
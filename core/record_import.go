@@ -0,0 +1,497 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/dbutils"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const (
+	RecordImportFormatCSV    = "csv"
+	RecordImportFormatNDJSON = "ndjson"
+)
+
+// RecordImportsTable is the name of the table that stores the records import reports.
+const RecordImportsTable = "_recordImports"
+
+// DefaultRecordImportChunkSize is the default number
+// of rows that are imported in a single transaction.
+const DefaultRecordImportChunkSize = 500
+
+// recordImportReportMaxDuration specifies how long the import reports are kept.
+const recordImportReportMaxDuration = 7 * 24 * time.Hour
+
+var _ Model = (*RecordImport)(nil)
+
+// RecordImportOptions defines the [ImportRecords] configuration options.
+type RecordImportOptions struct {
+	// Format specifies the source data format
+	// (RecordImportFormatCSV or RecordImportFormatNDJSON).
+	Format string
+
+	// Mapping specifies the source column (CSV header or NDJSON key)
+	// to collection field name mapping.
+	//
+	// If empty, the source columns are mapped to the collection fields with the same name.
+	// If not empty, the source columns that are not listed are ignored.
+	Mapping map[string]string
+
+	// UpsertField specifies an optional unique field name used to match
+	// the existing records that should be updated instead of created.
+	//
+	// The field must be "id" or a field with a single column unique index.
+	UpsertField string
+
+	// ChunkSize specifies the number of rows imported in a single
+	// transaction (default to [DefaultRecordImportChunkSize]).
+	ChunkSize int
+}
+
+// RecordImportRowError defines a single failed import row error.
+type RecordImportRowError struct {
+	// Row is the 1-based source data row number (the CSV header is not counted).
+	Row int `json:"row"`
+
+	// Field is the name of the invalid collection field
+	// (empty for row level errors).
+	Field string `json:"field"`
+
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// RecordImport defines the report of a single records import.
+type RecordImport struct {
+	BaseModel
+
+	CollectionRef string                                 `db:"collectionRef" json:"collectionRef"`
+	Format        string                                 `db:"format" json:"format"`
+	TotalRows     int                                    `db:"totalRows" json:"totalRows"`
+	CreatedRows   int                                    `db:"createdRows" json:"createdRows"`
+	UpdatedRows   int                                    `db:"updatedRows" json:"updatedRows"`
+	FailedRows    int                                    `db:"failedRows" json:"failedRows"`
+	Errors        types.JSONArray[*RecordImportRowError] `db:"errors" json:"-"` // could be large, see WriteErrorsCSV
+	Created       types.DateTime                         `db:"created" json:"created"`
+}
+
+// TableName implements the [Model] interface.
+func (m *RecordImport) TableName() string {
+	return RecordImportsTable
+}
+
+// WriteErrorsCSV writes the import rows errors report as CSV to w.
+func (m *RecordImport) WriteErrorsCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write([]string{"row", "field", "code", "message"}); err != nil {
+		return err
+	}
+
+	for _, item := range m.Errors {
+		err := cw.Write([]string{strconv.Itoa(item.Row), item.Field, item.Code, item.Message})
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// FindRecordImportById returns a single records import report by its id.
+func FindRecordImportById(app App, id string) (*RecordImport, error) {
+	m := &RecordImport{}
+
+	err := app.ModelQuery(m).
+		AndWhere(dbx.HashExp{"id": id}).
+		Limit(1).
+		One(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// DeleteExpiredRecordImports deletes the records import reports older than 7 days.
+func DeleteExpiredRecordImports(app App) error {
+	minValidDate, err := types.ParseDateTime(time.Now().Add(-1 * recordImportReportMaxDuration))
+	if err != nil {
+		return err
+	}
+
+	_, err = app.NonconcurrentDB().Delete(
+		RecordImportsTable,
+		dbx.NewExp("[[created]] < {:date}", dbx.Params{"date": minValidDate}),
+	).Execute()
+
+	return err
+}
+
+// ImportRecords reads the CSV or NDJSON rows from r and saves them
+// as collection records in transactions of options.ChunkSize rows.
+//
+// Every row is saved with [App.SaveWithContext] (aka. the record
+// validations and hooks are executed) and a failed row doesn't
+// affect the other rows from the same chunk.
+//
+// The returned import report is also persisted so that it could be
+// loaded later with [FindRecordImportById].
+//
+// Note that in case of a read or db error the import is stopped
+// but the already imported chunks are not reverted.
+func ImportRecords(ctx context.Context, app App, collection *Collection, r io.Reader, options RecordImportOptions) (*RecordImport, error) {
+	if err := validateRecordImportOptions(collection, &options); err != nil {
+		return nil, err
+	}
+
+	var next func() (map[string]any, error)
+	switch options.Format {
+	case RecordImportFormatCSV:
+		next = csvImportRowsReader(r)
+	case RecordImportFormatNDJSON:
+		next = ndjsonImportRowsReader(r)
+	}
+
+	report := &RecordImport{
+		CollectionRef: collection.Id,
+		Format:        options.Format,
+		Errors:        types.JSONArray[*RecordImportRowError]{},
+	}
+
+	chunk := make([]*recordImportRow, 0, options.ChunkSize)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		data, readErr := next()
+		if readErr != nil && !errors.Is(readErr, io.EOF) {
+			var rowErr *recordImportRowReadError
+			if !errors.As(readErr, &rowErr) {
+				return nil, readErr
+			}
+		}
+
+		if !errors.Is(readErr, io.EOF) {
+			report.TotalRows++
+			chunk = append(chunk, &recordImportRow{
+				number:  report.TotalRows,
+				data:    data,
+				readErr: readErr,
+			})
+		}
+
+		if len(chunk) > 0 && (len(chunk) >= options.ChunkSize || errors.Is(readErr, io.EOF)) {
+			if err := importRecordsChunk(ctx, app, collection, chunk, options, report); err != nil {
+				return nil, err
+			}
+			chunk = chunk[:0]
+		}
+
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+	}
+
+	report.Id = GenerateDefaultRandomId()
+	report.Created = types.NowDateTime()
+
+	if err := app.SaveNoValidateWithContext(ctx, report); err != nil {
+		return nil, fmt.Errorf("failed to save the import report: %w", err)
+	}
+
+	return report, nil
+}
+
+func validateRecordImportOptions(collection *Collection, options *RecordImportOptions) error {
+	if collection.IsView() {
+		return errors.New("view collections records can't be imported")
+	}
+
+	if options.Format != RecordImportFormatCSV && options.Format != RecordImportFormatNDJSON {
+		return fmt.Errorf("invalid import format %q", options.Format)
+	}
+
+	for source, fieldName := range options.Mapping {
+		if collection.Fields.GetByName(fieldName) == nil {
+			return fmt.Errorf("column %q is mapped to missing field %q", source, fieldName)
+		}
+	}
+
+	if options.UpsertField != "" &&
+		options.UpsertField != FieldNameId &&
+		(collection.Fields.GetByName(options.UpsertField) == nil ||
+			!dbutils.HasSingleColumnUniqueIndex(options.UpsertField, collection.Indexes)) {
+		return fmt.Errorf("upsert field %q must be an existing field with a single column unique index", options.UpsertField)
+	}
+
+	// the stored encrypted values use a random nonce and cannot be looked up
+	if field := collection.Fields.GetByName(options.UpsertField); field != nil && isEncryptedField(field) {
+		return fmt.Errorf("upsert field %q cannot be an encrypted field", options.UpsertField)
+	}
+
+	if options.ChunkSize <= 0 {
+		options.ChunkSize = DefaultRecordImportChunkSize
+	}
+
+	return nil
+}
+
+type recordImportRow struct {
+	data    map[string]any
+	readErr error
+	number  int
+}
+
+// recordImportRowReadError defines an error for a single unreadable source
+// row (the import could continue with the next row).
+type recordImportRowReadError struct {
+	err error
+}
+
+func (e *recordImportRowReadError) Error() string {
+	return e.err.Error()
+}
+
+func (e *recordImportRowReadError) Unwrap() error {
+	return e.err
+}
+
+func importRecordsChunk(
+	ctx context.Context,
+	app App,
+	collection *Collection,
+	rows []*recordImportRow,
+	options RecordImportOptions,
+	report *RecordImport,
+) error {
+	return app.RunInTransaction(func(txApp App) error {
+		for _, row := range rows {
+			if row.readErr != nil {
+				report.FailedRows++
+				report.Errors = append(report.Errors, &RecordImportRowError{
+					Row:     row.number,
+					Code:    "invalid_row",
+					Message: row.readErr.Error(),
+				})
+				continue
+			}
+
+			// isolate the row changes so that it could be reverted
+			// without affecting the other chunk rows
+			if _, err := txApp.DB().NewQuery("SAVEPOINT pb_import_row").Execute(); err != nil {
+				return err
+			}
+
+			isNew, err := importRecordRow(ctx, txApp, collection, row.data, options)
+			if err != nil {
+				if _, rollbackErr := txApp.DB().NewQuery("ROLLBACK TO pb_import_row").Execute(); rollbackErr != nil {
+					return rollbackErr
+				}
+
+				report.FailedRows++
+				report.Errors = append(report.Errors, recordImportRowErrors(row.number, err)...)
+			} else if isNew {
+				report.CreatedRows++
+			} else {
+				report.UpdatedRows++
+			}
+
+			if _, err := txApp.DB().NewQuery("RELEASE pb_import_row").Execute(); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// importRecordRow creates or updates a single record from the source row data.
+func importRecordRow(ctx context.Context, app App, collection *Collection, row map[string]any, options RecordImportOptions) (bool, error) {
+	data := make(map[string]any, len(row))
+
+	for source, value := range row {
+		fieldName := source
+		if len(options.Mapping) > 0 {
+			fieldName = options.Mapping[source]
+		}
+
+		if fieldName != "" && collection.Fields.GetByName(fieldName) != nil {
+			data[fieldName] = value
+		}
+	}
+
+	var record *Record
+
+	if options.UpsertField != "" {
+		if value, ok := data[options.UpsertField]; ok && value != nil && value != "" {
+			existing, err := app.FindFirstRecordByData(collection, options.UpsertField, value)
+			if err == nil {
+				record = existing
+			}
+		}
+	}
+
+	if record == nil {
+		record = NewRecord(collection)
+	}
+
+	record.Load(data)
+
+	isNew := record.IsNew()
+
+	return isNew, app.SaveWithContext(ctx, record)
+}
+
+// recordImportRowErrors converts the provided row save error into report errors.
+func recordImportRowErrors(rowNumber int, err error) []*RecordImportRowError {
+	var validationErrs validation.Errors
+	if !errors.As(err, &validationErrs) || len(validationErrs) == 0 {
+		return []*RecordImportRowError{{
+			Row:     rowNumber,
+			Code:    "failed_row",
+			Message: err.Error(),
+		}}
+	}
+
+	result := make([]*RecordImportRowError, 0, len(validationErrs))
+
+	// sort the keys to ensure a stable report
+	for _, field := range slices.Sorted(maps.Keys(validationErrs)) {
+		fieldErr := validationErrs[field]
+
+		item := &RecordImportRowError{
+			Row:     rowNumber,
+			Field:   field,
+			Code:    "invalid_value",
+			Message: fieldErr.Error(),
+		}
+
+		var ve validation.Error
+		if errors.As(fieldErr, &ve) {
+			item.Code = ve.Code()
+		}
+
+		result = append(result, item)
+	}
+
+	return result
+}
+
+// -------------------------------------------------------------------
+
+// csvImportRowsReader returns a function that reads one CSV data row
+// at a time (the first row is used as header).
+func csvImportRowsReader(r io.Reader) func() (map[string]any, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1 // checked manually to report it as row error
+
+	var header []string
+
+	return func() (map[string]any, error) {
+		if header == nil {
+			h, err := reader.Read()
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil, err
+				}
+				return nil, fmt.Errorf("failed to read the CSV header: %w", err)
+			}
+
+			if len(h) > 0 {
+				h[0] = strings.TrimPrefix(h[0], "\ufeff") // BOM
+			}
+
+			header = make([]string, len(h))
+			for i, v := range h {
+				header[i] = strings.TrimSpace(v)
+			}
+		}
+
+		values, err := reader.Read()
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				return nil, &recordImportRowReadError{err}
+			}
+			return nil, err
+		}
+
+		if len(values) != len(header) {
+			return nil, &recordImportRowReadError{
+				fmt.Errorf("expected %d columns, got %d", len(header), len(values)),
+			}
+		}
+
+		row := make(map[string]any, len(header))
+		for i, column := range header {
+			row[column] = values[i]
+		}
+
+		return row, nil
+	}
+}
+
+// ndjsonImportRowsReader returns a function that reads one
+// JSON object line at a time (empty lines are skipped).
+func ndjsonImportRowsReader(r io.Reader) func() (map[string]any, error) {
+	reader := bufio.NewReader(r)
+
+	var eof bool
+
+	return func() (map[string]any, error) {
+		for {
+			// don't read again after EOF in case r is rewindable
+			if eof {
+				return nil, io.EOF
+			}
+
+			line, err := reader.ReadBytes('\n')
+			if err != nil {
+				if !errors.Is(err, io.EOF) {
+					return nil, err
+				}
+				eof = true
+			}
+
+			line = bytes.TrimSpace(line)
+			if len(line) == 0 {
+				continue
+			}
+
+			row := map[string]any{}
+			if jsonErr := json.Unmarshal(line, &row); jsonErr != nil {
+				return nil, &recordImportRowReadError{fmt.Errorf("invalid JSON object: %w", jsonErr)}
+			}
+
+			return row, nil
+		}
+	}
+}
+
+func (app *BaseApp) registerRecordImportHooks() {
+	// run on every hour to cleanup the expired import reports
+	app.Cron().Add("__pbRecordImportsCleanup__", "50 * * * *", func() {
+		if err := DeleteExpiredRecordImports(app); err != nil {
+			app.Logger().Warn("Failed to delete expired record import reports", "error", err)
+		}
+	})
+}
@@ -0,0 +1,292 @@
+package core_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/hook"
+)
+
+func createImportTestCollection(t testing.TB, app core.App) *core.Collection {
+	collection := core.NewBaseCollection("test_import")
+	collection.Fields.Add(
+		&core.TextField{Name: "title", Required: true},
+		&core.TextField{Name: "slug"},
+		&core.NumberField{Name: "total"},
+	)
+	collection.AddIndex("idx_test_import_slug", true, "slug", "slug != ''")
+
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	existing := core.NewRecord(collection)
+	existing.Set("title", "existing")
+	existing.Set("slug", "existing")
+	existing.Set("total", 1)
+	if err := app.Save(existing); err != nil {
+		t.Fatal(err)
+	}
+
+	return collection
+}
+
+func TestImportRecordsCSV(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := createImportTestCollection(t, app)
+
+	app.OnRecordCreateExecute(collection.Name).Bind(&hook.Handler[*core.RecordEvent]{
+		Func: func(e *core.RecordEvent) error {
+			if err := e.Next(); err != nil {
+				return err
+			}
+
+			if e.Record.GetString("title") == "fail_after_insert" {
+				return errors.New("test_error")
+			}
+
+			return nil
+		},
+		Priority: 100,
+	})
+
+	data := "\ufeffTitle,slug,total,extra\n" +
+		"a,a,10,x\n" +
+		",b,20,x\n" + // missing required title
+		"existing_updated,existing,30,x\n" +
+		"c,c\n" + // invalid columns count
+		"fail_after_insert,d,40,x\n" +
+		"e,e,50,x\n"
+
+	report, err := core.ImportRecords(context.Background(), app, collection, strings.NewReader(data), core.RecordImportOptions{
+		Format: core.RecordImportFormatCSV,
+		Mapping: map[string]string{
+			"Title": "title",
+			"slug":  "slug",
+			"total": "total",
+		},
+		UpsertField: "slug",
+		ChunkSize:   2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.TotalRows != 6 || report.CreatedRows != 2 || report.UpdatedRows != 1 || report.FailedRows != 3 {
+		t.Fatalf("Unexpected report counts: %+v", report)
+	}
+
+	expectedErrors := []core.RecordImportRowError{
+		{Row: 2, Field: "title", Code: "validation_required"},
+		{Row: 4, Code: "invalid_row"},
+		{Row: 5, Code: "failed_row"},
+	}
+	if len(report.Errors) != len(expectedErrors) {
+		t.Fatalf("Expected %d errors, got %d", len(expectedErrors), len(report.Errors))
+	}
+	for i, expected := range expectedErrors {
+		item := report.Errors[i]
+		if item.Row != expected.Row || item.Field != expected.Field || item.Code != expected.Code || item.Message == "" {
+			t.Fatalf("[%d] Expected error %+v, got %+v", i, expected, item)
+		}
+	}
+
+	records, err := app.FindAllRecords(collection)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stored := map[string]string{}
+	for _, r := range records {
+		stored[r.GetString("slug")] = r.GetString("title") + ":" + r.GetString("total")
+	}
+
+	expectedStored := map[string]string{
+		"existing": "existing_updated:30",
+		"a":        "a:10",
+		"e":        "e:50",
+	}
+	if len(stored) != len(expectedStored) {
+		t.Fatalf("Expected stored records %v, got %v", expectedStored, stored)
+	}
+	for slug, v := range expectedStored {
+		if stored[slug] != v {
+			t.Fatalf("Expected stored record %q to be %q, got %q", slug, v, stored[slug])
+		}
+	}
+
+	// persisted report
+	persisted, err := core.FindRecordImportById(app, report.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if persisted.CollectionRef != collection.Id || persisted.FailedRows != 3 || len(persisted.Errors) != 3 {
+		t.Fatalf("Unexpected persisted report %+v", persisted)
+	}
+
+	var buf bytes.Buffer
+	if err := persisted.WriteErrorsCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	csvReport := buf.String()
+	for _, expected := range []string{"row,field,code,message\n", "2,title,validation_required,", "4,,invalid_row,", "5,,failed_row,test_error"} {
+		if !strings.Contains(csvReport, expected) {
+			t.Fatalf("Expected %q in CSV report:\n%s", expected, csvReport)
+		}
+	}
+}
+
+func TestImportRecordsNDJSON(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := createImportTestCollection(t, app)
+
+	data := `{"title":"a","slug":"a","total":10,"unknown":1}` + "\n" +
+		"\n" +
+		`{"title":"b",` + "\n" +
+		`{"title":"c","slug":"existing","total":5}` // duplicated slug without upsert
+
+	report, err := core.ImportRecords(context.Background(), app, collection, strings.NewReader(data), core.RecordImportOptions{
+		Format: core.RecordImportFormatNDJSON,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.TotalRows != 3 || report.CreatedRows != 1 || report.UpdatedRows != 0 || report.FailedRows != 2 {
+		t.Fatalf("Unexpected report counts: %+v", report)
+	}
+
+	if report.Errors[0].Row != 2 || report.Errors[0].Code != "invalid_row" {
+		t.Fatalf("Expected invalid_row error for row 2, got %+v", report.Errors[0])
+	}
+
+	if report.Errors[1].Row != 3 || report.Errors[1].Field != "slug" {
+		t.Fatalf("Expected slug error for row 3, got %+v", report.Errors[1])
+	}
+
+	record, err := app.FindFirstRecordByData(collection, "slug", "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.GetString("title") != "a" || record.GetInt("total") != 10 {
+		t.Fatalf("Unexpected imported record %v", record.PublicExport())
+	}
+}
+
+func TestImportRecordsInvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := createImportTestCollection(t, app)
+
+	view, err := app.FindCollectionByNameOrId("view1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// note: not persisted because the unique index is rejected by the collection validator
+	encrypted := core.NewBaseCollection("test_import_encrypted")
+	encrypted.Fields.Add(&core.TextField{Name: "ssn", Encrypted: true})
+	encrypted.AddIndex("idx_test_import_encrypted_ssn", true, "ssn", "")
+
+	scenarios := []struct {
+		name       string
+		collection *core.Collection
+		options    core.RecordImportOptions
+	}{
+		{
+			"view collection",
+			view,
+			core.RecordImportOptions{Format: core.RecordImportFormatCSV},
+		},
+		{
+			"invalid format",
+			collection,
+			core.RecordImportOptions{Format: "xml"},
+		},
+		{
+			"mapping to missing field",
+			collection,
+			core.RecordImportOptions{Format: core.RecordImportFormatCSV, Mapping: map[string]string{"a": "missing"}},
+		},
+		{
+			"upsert field without unique index",
+			collection,
+			core.RecordImportOptions{Format: core.RecordImportFormatCSV, UpsertField: "title"},
+		},
+		{
+			"upsert missing field",
+			collection,
+			core.RecordImportOptions{Format: core.RecordImportFormatCSV, UpsertField: "missing"},
+		},
+		{
+			"upsert encrypted field",
+			encrypted,
+			core.RecordImportOptions{Format: core.RecordImportFormatCSV, UpsertField: "ssn"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			_, err := core.ImportRecords(context.Background(), app, s.collection, strings.NewReader("title\na\n"), s.options)
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestDeleteExpiredRecordImports(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := createImportTestCollection(t, app)
+
+	reports := make([]*core.RecordImport, 2)
+	for i := range reports {
+		report, err := core.ImportRecords(context.Background(), app, collection, strings.NewReader("title\na\n"), core.RecordImportOptions{
+			Format: core.RecordImportFormatCSV,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		reports[i] = report
+	}
+
+	_, err := app.DB().NewQuery("UPDATE {{" + core.RecordImportsTable + "}} SET [[created]] = '2020-01-01 00:00:00.000Z' WHERE [[id]] = {:id}").
+		Bind(dbx.Params{"id": reports[0].Id}).
+		Execute()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := core.DeleteExpiredRecordImports(app); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := core.FindRecordImportById(app, reports[0].Id); err == nil {
+		t.Fatal("Expected the expired report to be deleted")
+	}
+
+	if _, err := core.FindRecordImportById(app, reports[1].Id); err != nil {
+		t.Fatalf("Expected the recent report to remain, got %v", err)
+	}
+}
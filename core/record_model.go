@@ -45,6 +45,10 @@ type Record struct {
 
 	BaseModel
 
+	etag         string
+	etagData     dbx.NullStringMap // the loaded row data used to lazily compute the etag
+	expectedETag string
+
 	exportCustomData      bool
 	ignoreEmailVisibility bool
 	ignoreUnchangedFields bool
@@ -512,6 +516,8 @@ func newRecordFromNullStringMap(collection *Collection, data dbx.NullStringMap)
 
 	record.BaseModel.PostScan()
 
+	record.etagData = data
+
 	return record, nil
 }
 
@@ -619,6 +625,8 @@ func (m *Record) Original() *Record {
 	newRecord := NewRecord(m.collection)
 
 	newRecord.originalData = maps.Clone(m.originalData)
+	newRecord.etag = m.etag
+	newRecord.etagData = m.etagData
 
 	if newRecord.originalData[FieldNameId] != nil {
 		newRecord.lastSavedPK = cast.ToString(newRecord.originalData[FieldNameId])
@@ -656,6 +664,7 @@ func (m *Record) Clone() *Record {
 	newRecord.exportCustomData = m.exportCustomData
 	newRecord.ignoreEmailVisibility = m.ignoreEmailVisibility
 	newRecord.ignoreUnchangedFields = m.ignoreUnchangedFields
+	newRecord.expectedETag = m.expectedETag
 	newRecord.customVisibility.Reset(m.customVisibility.GetAll())
 
 	data := m.data.GetAll()
@@ -1130,6 +1139,10 @@ func (m *Record) dbExport() (map[string]any, error) {
 
 	var fieldName string
 	for _, field := range fields {
+		if f, ok := field.(GeneratedColumner); ok && f.IsGeneratedColumn() {
+			continue // the column value is managed by the db
+		}
+
 		fieldName = field.GetName()
 
 		if f, ok := field.(DriverValuer); ok {
@@ -620,3 +620,17 @@ func (app *BaseApp) CanAccessRecord(record *Record, requestInfo *RequestInfo, ac
 
 	return exists > 0, nil
 }
+
+// RecordsCursorSecret returns the server-side secret used to sign
+// the records list pagination cursors of the specified collection.
+//
+// The secret is derived from the superusers auth token secret so that
+// the issued cursors are invalidated together with the superuser tokens.
+func RecordsCursorSecret(app App, collection *Collection) (string, error) {
+	superusers, err := app.FindCachedCollectionByNameOrId(CollectionNameSuperusers)
+	if err != nil {
+		return "", err
+	}
+
+	return superusers.AuthToken.Secret + collection.Id, nil
+}
@@ -0,0 +1,424 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/types"
+	"github.com/spf13/cast"
+)
+
+const (
+	RecordRevisionActionCreate = "create"
+	RecordRevisionActionUpdate = "update"
+	RecordRevisionActionDelete = "delete"
+)
+
+const CollectionNameRecordRevisions = "_recordRevisions"
+
+var (
+	_ Model        = (*RecordRevision)(nil)
+	_ PreValidator = (*RecordRevision)(nil)
+	_ RecordProxy  = (*RecordRevision)(nil)
+)
+
+// RecordRevisionDiff defines a single changed record field value.
+type RecordRevisionDiff struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
+// RecordRevision defines a Record proxy for working with the recordRevisions collection.
+//
+// A revision is created on every create, update and delete of a record
+// from a collection with enabled history (see [HistoryConfig]).
+type RecordRevision struct {
+	*Record
+}
+
+// NewRecordRevision instantiates and returns a new blank *RecordRevision model.
+//
+// Example usage:
+//
+//	revision := core.NewRecordRevision(app)
+//	revision.SetCollectionRef(record.Collection().Id)
+//	revision.SetRecordRef(record.Id)
+//	revision.SetAction(core.RecordRevisionActionUpdate)
+//	app.Save(revision)
+func NewRecordRevision(app App) *RecordRevision {
+	m := &RecordRevision{}
+
+	c, err := app.FindCachedCollectionByNameOrId(CollectionNameRecordRevisions)
+	if err != nil {
+		// this is just to make tests easier since recordRevisions is a system collection and it is expected to be always accessible
+		// (note: the loaded record is further checked on RecordRevision.PreValidate())
+		c = NewBaseCollection("@__invalid__")
+	}
+
+	m.Record = NewRecord(c)
+
+	return m
+}
+
+// PreValidate implements the [PreValidator] interface and checks
+// whether the proxy is properly loaded.
+func (m *RecordRevision) PreValidate(ctx context.Context, app App) error {
+	if m.Record == nil || m.Record.Collection().Name != CollectionNameRecordRevisions {
+		return errors.New("missing or invalid RecordRevision ProxyRecord")
+	}
+
+	return nil
+}
+
+// ProxyRecord returns the proxied Record model.
+func (m *RecordRevision) ProxyRecord() *Record {
+	return m.Record
+}
+
+// SetProxyRecord loads the specified record model into the current proxy.
+func (m *RecordRevision) SetProxyRecord(record *Record) {
+	m.Record = record
+}
+
+// CollectionRef returns the "collectionRef" field value.
+func (m *RecordRevision) CollectionRef() string {
+	return m.GetString("collectionRef")
+}
+
+// SetCollectionRef updates the "collectionRef" record field value.
+func (m *RecordRevision) SetCollectionRef(collectionId string) {
+	m.Set("collectionRef", collectionId)
+}
+
+// RecordRef returns the "recordRef" record field value.
+func (m *RecordRevision) RecordRef() string {
+	return m.GetString("recordRef")
+}
+
+// SetRecordRef updates the "recordRef" record field value.
+func (m *RecordRevision) SetRecordRef(recordId string) {
+	m.Set("recordRef", recordId)
+}
+
+// Action returns the "action" record field value.
+func (m *RecordRevision) Action() string {
+	return m.GetString("action")
+}
+
+// SetAction updates the "action" record field value.
+func (m *RecordRevision) SetAction(action string) {
+	m.Set("action", action)
+}
+
+// Data returns the raw "data" record field value
+// (aka. the serialized record state after the change).
+//
+// The data is null for the delete revisions.
+func (m *RecordRevision) Data() types.JSONRaw {
+	raw, _ := m.GetRaw("data").(types.JSONRaw)
+	return raw
+}
+
+// SetData updates the "data" record field value.
+func (m *RecordRevision) SetData(data any) {
+	m.Set("data", data)
+}
+
+// Diff returns the decoded "diff" record field value.
+func (m *RecordRevision) Diff() map[string]RecordRevisionDiff {
+	result := map[string]RecordRevisionDiff{}
+
+	_ = m.UnmarshalJSONField("diff", &result)
+
+	return result
+}
+
+// SetDiff updates the "diff" record field value.
+func (m *RecordRevision) SetDiff(diff map[string]RecordRevisionDiff) {
+	m.Set("diff", diff)
+}
+
+// ActorCollectionRef returns the "actorCollectionRef" record field value.
+func (m *RecordRevision) ActorCollectionRef() string {
+	return m.GetString("actorCollectionRef")
+}
+
+// SetActorCollectionRef updates the "actorCollectionRef" record field value.
+func (m *RecordRevision) SetActorCollectionRef(collectionId string) {
+	m.Set("actorCollectionRef", collectionId)
+}
+
+// ActorRef returns the "actorRef" record field value.
+func (m *RecordRevision) ActorRef() string {
+	return m.GetString("actorRef")
+}
+
+// SetActorRef updates the "actorRef" record field value.
+func (m *RecordRevision) SetActorRef(recordId string) {
+	m.Set("actorRef", recordId)
+}
+
+// RequestId returns the "requestId" record field value.
+func (m *RecordRevision) RequestId() string {
+	return m.GetString("requestId")
+}
+
+// SetRequestId updates the "requestId" record field value.
+func (m *RecordRevision) SetRequestId(requestId string) {
+	m.Set("requestId", requestId)
+}
+
+// Created returns the "created" record field value.
+func (m *RecordRevision) Created() types.DateTime {
+	return m.GetDateTime("created")
+}
+
+// HasExpired checks if the revision is expired, aka. whether it has been
+// more than maxElapsed time since its creation.
+//
+// A zero maxElapsed means that the revision never expires.
+func (m *RecordRevision) HasExpired(maxElapsed time.Duration) bool {
+	return maxElapsed > 0 && time.Since(m.Created().Time()) > maxElapsed
+}
+
+// ApplyTo loads the revision record state into the provided record model.
+//
+// Returns an error for delete revisions since they don't have a state to restore.
+func (m *RecordRevision) ApplyTo(record *Record) error {
+	if m.Action() == RecordRevisionActionDelete {
+		return errors.New("delete revisions don't have a record state to apply")
+	}
+
+	data := map[string]json.RawMessage{}
+	if err := json.Unmarshal(m.Data(), &data); err != nil {
+		return err
+	}
+
+	for _, field := range record.Collection().Fields {
+		if isRevisionExcludedField(record.Collection(), field) {
+			continue // keep the current credentials
+		}
+
+		raw, ok := data[field.GetName()]
+		if !ok {
+			continue // the field was added after the revision creation
+		}
+
+		var value any
+		if _, isJSON := field.(*JSONField); isJSON {
+			value = types.JSONRaw(raw)
+		} else if err := json.Unmarshal(raw, &value); err != nil {
+			return err
+		}
+
+		prepared, err := field.PrepareValue(record, value)
+		if err != nil {
+			return err
+		}
+
+		record.SetRaw(field.GetName(), prepared)
+	}
+
+	return nil
+}
+
+// -------------------------------------------------------------------
+
+func (app *BaseApp) registerRecordRevisionHooks() {
+	// run on every hour to cleanup the expired revisions and their files
+	app.Cron().Add("__pbRecordRevisionsCleanup__", "30 * * * *", func() {
+		if err := app.DeleteExpiredRecordRevisions(); err != nil {
+			app.Logger().Warn("Failed to delete expired record revisions", "error", err)
+		}
+	})
+
+	revisionHandler := func(action string) func(e *RecordEvent) error {
+		return func(e *RecordEvent) error {
+			if !e.Record.Collection().HistoryEnabled() {
+				return e.Next()
+			}
+
+			originalApp := e.App
+			txErr := e.App.RunInTransaction(func(txApp App) error {
+				e.App = txApp
+
+				// note: load the last saved state from the db since
+				// the record Original() is not refreshed after save
+				var old *Record
+				if action != RecordRevisionActionCreate {
+					var err error
+					old, err = txApp.FindRecordById(e.Record.Collection(), cast.ToString(e.Record.LastSavedPK()))
+					if err != nil {
+						return err
+					}
+				}
+
+				if err := e.Next(); err != nil {
+					return err
+				}
+
+				return saveRecordRevision(e.Context, txApp, action, old, e.Record)
+			})
+			e.App = originalApp
+
+			return txErr
+		}
+	}
+
+	app.OnRecordCreateExecute().Bind(&hook.Handler[*RecordEvent]{
+		Func:     revisionHandler(RecordRevisionActionCreate),
+		Priority: 99,
+	})
+
+	app.OnRecordUpdateExecute().Bind(&hook.Handler[*RecordEvent]{
+		Func:     revisionHandler(RecordRevisionActionUpdate),
+		Priority: 99,
+	})
+
+	app.OnRecordDeleteExecute().Bind(&hook.Handler[*RecordEvent]{
+		Func:     revisionHandler(RecordRevisionActionDelete),
+		Priority: 99,
+	})
+
+	// delete the collection revisions on collection delete
+	app.OnCollectionDeleteExecute().Bind(&hook.Handler[*CollectionEvent]{
+		Func: func(e *CollectionEvent) error {
+			if e.Collection.IsView() {
+				return e.Next()
+			}
+
+			originalApp := e.App
+			txErr := e.App.RunInTransaction(func(txApp App) error {
+				e.App = txApp
+
+				if err := e.Next(); err != nil {
+					return err
+				}
+
+				_, err := txApp.DB().Delete(
+					CollectionNameRecordRevisions,
+					dbx.HashExp{"collectionRef": e.Collection.Id},
+				).Execute()
+
+				return err
+			})
+			e.App = originalApp
+
+			return txErr
+		},
+		Priority: 99,
+	})
+}
+
+// isHistoryRecord reports whether m is a record from a collection with enabled history.
+func isHistoryRecord(m Model) bool {
+	switch v := m.(type) {
+	case *Record:
+		return v.Collection().HistoryEnabled()
+	case RecordProxy:
+		return v.ProxyRecord() != nil && v.ProxyRecord().Collection().HistoryEnabled()
+	}
+
+	return false
+}
+
+// isRevisionExcludedField reports whether the field value must not be
+// stored in or restored from a revision (aka. the password hash and
+// the auth record tokenKey).
+//
+// Restoring them would bring back an old password and reactivate
+// the auth tokens revoked with the password change.
+func isRevisionExcludedField(collection *Collection, field Field) bool {
+	if _, ok := field.(*PasswordField); ok {
+		return true
+	}
+
+	return collection.IsAuth() && field.GetName() == FieldNameTokenKey
+}
+
+// areRevisionValuesEqual reports whether the old and new exported field values are equal.
+//
+// The encrypted field values are compared by their decrypted record
+// values because the random nonce makes every export different.
+func areRevisionValuesEqual(field Field, old *Record, new *Record, oldValue any, newValue any) bool {
+	if !isEncryptedField(field) || old == nil || new == nil {
+		return areValuesEqual(oldValue, newValue)
+	}
+
+	name := field.GetName()
+
+	return areValuesEqual(old.GetRaw(name), new.GetRaw(name))
+}
+
+// saveRecordRevision creates and persists a new revision for the
+// specified record change.
+//
+// old is the record state before the change (nil for create).
+func saveRecordRevision(ctx context.Context, app App, action string, old *Record, new *Record) error {
+	var newData, oldData map[string]any
+
+	if action != RecordRevisionActionDelete {
+		data, err := new.dbExport()
+		if err != nil {
+			return err
+		}
+		newData = data
+	}
+
+	if old != nil {
+		data, err := old.dbExport()
+		if err != nil {
+			return err
+		}
+		oldData = data
+	}
+
+	diff := map[string]RecordRevisionDiff{}
+	for _, field := range new.Collection().Fields {
+		if isRevisionExcludedField(new.Collection(), field) {
+			continue // never expose the credentials changes
+		}
+
+		name := field.GetName()
+
+		oldValue, oldOk := oldData[name]
+		newValue, newOk := newData[name]
+		if oldOk == newOk && areRevisionValuesEqual(field, old, new, oldValue, newValue) {
+			continue
+		}
+
+		diff[name] = RecordRevisionDiff{Old: oldValue, New: newValue}
+	}
+
+	// nothing has changed
+	if action == RecordRevisionActionUpdate && len(diff) == 0 {
+		return nil
+	}
+
+	revision := NewRecordRevision(app)
+	revision.SetCollectionRef(new.Collection().Id)
+	revision.SetRecordRef(new.Id)
+	revision.SetAction(action)
+	revision.SetDiff(diff)
+	revision.SetRequestId(TraceIdFromContext(ctx))
+
+	if newData != nil {
+		for _, field := range new.Collection().Fields {
+			if isRevisionExcludedField(new.Collection(), field) {
+				delete(newData, field.GetName())
+			}
+		}
+		revision.SetData(newData)
+	}
+
+	if actor := AuthFromContext(ctx); actor != nil {
+		revision.SetActorCollectionRef(actor.Collection().Id)
+		revision.SetActorRef(actor.Id)
+	}
+
+	return app.SaveNoValidateWithContext(newContextIfInvalid(ctx), revision)
+}
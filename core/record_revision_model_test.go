@@ -0,0 +1,405 @@
+package core_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestNewRecordRevision(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	revision := core.NewRecordRevision(app)
+
+	if revision.Collection().Name != core.CollectionNameRecordRevisions {
+		t.Fatalf("Expected record with %q collection, got %q", core.CollectionNameRecordRevisions, revision.Collection().Name)
+	}
+}
+
+func TestRecordRevisionPreValidate(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	revisionsCol, err := app.FindCollectionByNameOrId(core.CollectionNameRecordRevisions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("no proxy record", func(t *testing.T) {
+		revision := &core.RecordRevision{}
+
+		if err := app.Validate(revision); err == nil {
+			t.Fatal("Expected collection validation error")
+		}
+	})
+
+	t.Run("non-revision collection", func(t *testing.T) {
+		revision := &core.RecordRevision{}
+		revision.SetProxyRecord(core.NewRecord(core.NewBaseCollection("invalid")))
+
+		if err := app.Validate(revision); err == nil {
+			t.Fatal("Expected collection validation error")
+		}
+	})
+
+	t.Run("revision collection", func(t *testing.T) {
+		revision := &core.RecordRevision{}
+		revision.SetProxyRecord(core.NewRecord(revisionsCol))
+		revision.SetCollectionRef("test")
+		revision.SetRecordRef("test")
+		revision.SetAction(core.RecordRevisionActionCreate)
+
+		if err := app.Validate(revision); err != nil {
+			t.Fatalf("Expected nil validation error, got %v", err)
+		}
+	})
+}
+
+func TestRecordRevisionProxyRecord(t *testing.T) {
+	t.Parallel()
+
+	record := core.NewRecord(core.NewBaseCollection("test"))
+	record.Id = "test_id"
+
+	revision := core.RecordRevision{}
+	revision.SetProxyRecord(record)
+
+	if revision.ProxyRecord() == nil || revision.ProxyRecord().Id != record.Id {
+		t.Fatalf("Expected proxy record with id %q, got %v", record.Id, revision.ProxyRecord())
+	}
+}
+
+func TestRecordRevisionGettersAndSetters(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	revision := core.NewRecordRevision(app)
+
+	revision.SetCollectionRef("test_collection")
+	revision.SetRecordRef("test_record")
+	revision.SetAction(core.RecordRevisionActionUpdate)
+	revision.SetData(map[string]any{"title": "a"})
+	revision.SetDiff(map[string]core.RecordRevisionDiff{"title": {Old: "b", New: "a"}})
+	revision.SetActorCollectionRef("test_actor_collection")
+	revision.SetActorRef("test_actor")
+	revision.SetRequestId("test_request")
+
+	created := types.NowDateTime()
+	revision.SetRaw("created", created)
+
+	if v := revision.CollectionRef(); v != "test_collection" {
+		t.Fatalf("Expected CollectionRef %q, got %q", "test_collection", v)
+	}
+
+	if v := revision.RecordRef(); v != "test_record" {
+		t.Fatalf("Expected RecordRef %q, got %q", "test_record", v)
+	}
+
+	if v := revision.Action(); v != core.RecordRevisionActionUpdate {
+		t.Fatalf("Expected Action %q, got %q", core.RecordRevisionActionUpdate, v)
+	}
+
+	if v := revision.Data().String(); v != `{"title":"a"}` {
+		t.Fatalf("Expected Data %q, got %q", `{"title":"a"}`, v)
+	}
+
+	if v := revision.Diff(); len(v) != 1 || v["title"].Old != "b" || v["title"].New != "a" {
+		t.Fatalf("Expected Diff with title change, got %v", v)
+	}
+
+	if v := revision.ActorCollectionRef(); v != "test_actor_collection" {
+		t.Fatalf("Expected ActorCollectionRef %q, got %q", "test_actor_collection", v)
+	}
+
+	if v := revision.ActorRef(); v != "test_actor" {
+		t.Fatalf("Expected ActorRef %q, got %q", "test_actor", v)
+	}
+
+	if v := revision.RequestId(); v != "test_request" {
+		t.Fatalf("Expected RequestId %q, got %q", "test_request", v)
+	}
+
+	if v := revision.Created().String(); v != created.String() {
+		t.Fatalf("Expected Created %q, got %q", created.String(), v)
+	}
+}
+
+func TestRecordRevisionHasExpired(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	now := types.NowDateTime()
+
+	revision := core.NewRecordRevision(app)
+	revision.SetRaw("created", now.Add(-5*time.Minute))
+
+	scenarios := []struct {
+		maxElapsed time.Duration
+		expected   bool
+	}{
+		{0, false},
+		{6 * time.Minute, false},
+		{5 * time.Minute, true},
+		{4 * time.Minute, true},
+	}
+
+	for i, s := range scenarios {
+		result := revision.HasExpired(s.maxElapsed)
+		if result != s.expected {
+			t.Errorf("[%d] Expected %v, got %v", i, s.expected, result)
+		}
+	}
+}
+
+func TestRecordRevisionApplyTo(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test")
+	collection.Fields.Add(
+		&core.TextField{Name: "title"},
+		&core.JSONField{Name: "meta"},
+		&core.NumberField{Name: "total"},
+	)
+
+	revision := core.NewRecordRevision(app)
+	revision.SetAction(core.RecordRevisionActionUpdate)
+	revision.SetData(map[string]any{
+		"id":      "test_id",
+		"title":   "a",
+		"meta":    "[1]",
+		"total":   5,
+		"missing": "b",
+	})
+
+	record := core.NewRecord(collection)
+	record.Set("total", 10)
+
+	if err := revision.ApplyTo(record); err != nil {
+		t.Fatal(err)
+	}
+
+	if record.Id != "test_id" {
+		t.Fatalf("Expected id %q, got %q", "test_id", record.Id)
+	}
+
+	if v := record.GetString("title"); v != "a" {
+		t.Fatalf("Expected title %q, got %q", "a", v)
+	}
+
+	// the JSON string value must be preserved as it is
+	if v := record.GetString("meta"); v != `"[1]"` {
+		t.Fatalf("Expected meta %q, got %q", `"[1]"`, v)
+	}
+
+	if v := record.GetInt("total"); v != 5 {
+		t.Fatalf("Expected total %d, got %d", 5, v)
+	}
+
+	revision.SetAction(core.RecordRevisionActionDelete)
+	if err := revision.ApplyTo(record); err == nil {
+		t.Fatal("Expected delete revisions apply error")
+	}
+}
+
+func TestRecordRevisionAuthCredentials(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	collection.History = &core.HistoryConfig{Enabled: true}
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := app.FindAuthRecordByEmail(collection, "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user.Set("name", "old")
+	if err := app.Save(user); err != nil {
+		t.Fatal(err)
+	}
+
+	revisions, err := app.FindAllRecordRevisions(collection, user.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revisions) != 1 {
+		t.Fatalf("Expected 1 revision, got %d", len(revisions))
+	}
+
+	data := revisions[0].Data().String()
+	for _, name := range []string{core.FieldNamePassword, core.FieldNameTokenKey} {
+		if strings.Contains(data, `"`+name+`"`) {
+			t.Fatalf("Expected %q to be excluded from the revision data, got %s", name, data)
+		}
+		if _, ok := revisions[0].Diff()[name]; ok {
+			t.Fatalf("Expected %q to be excluded from the revision diff", name)
+		}
+	}
+
+	// change the password and restore the old revision
+	user.SetPassword("1234567890")
+	user.Set("name", "new")
+	if err := app.Save(user); err != nil {
+		t.Fatal(err)
+	}
+	tokenKey := user.TokenKey()
+
+	// simulate an older revision with stored credentials
+	revision := revisions[0]
+	revision.SetData(map[string]any{
+		"name":                 "old",
+		core.FieldNamePassword: "old_hash",
+		core.FieldNameTokenKey: "old_token_key",
+	})
+
+	if err := revision.ApplyTo(user); err != nil {
+		t.Fatal(err)
+	}
+
+	if v := user.GetString("name"); v != "old" {
+		t.Fatalf("Expected name %q, got %q", "old", v)
+	}
+	if !user.ValidatePassword("1234567890") {
+		t.Fatal("Expected the current password to be preserved")
+	}
+	if v := user.TokenKey(); v != tokenKey {
+		t.Fatalf("Expected tokenKey %q, got %q", tokenKey, v)
+	}
+}
+
+func TestRecordRevisionHooks(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test_history")
+	collection.Fields.Add(&core.TextField{Name: "title"})
+	collection.History = &core.HistoryConfig{Enabled: true}
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := core.ContextWithTraceId(context.Background(), "test_trace")
+	ctx = core.ContextWithAuth(ctx, user)
+
+	record := core.NewRecord(collection)
+	record.Set("title", "a")
+	if err := app.SaveWithContext(ctx, record); err != nil {
+		t.Fatal(err)
+	}
+
+	record.Set("title", "b")
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	// no changes
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Delete(record); err != nil {
+		t.Fatal(err)
+	}
+
+	revisions, err := app.FindAllRecordRevisions(collection, record.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedActions := []string{
+		core.RecordRevisionActionDelete,
+		core.RecordRevisionActionUpdate,
+		core.RecordRevisionActionCreate,
+	}
+
+	if len(revisions) != len(expectedActions) {
+		t.Fatalf("Expected %d revisions, got %d", len(expectedActions), len(revisions))
+	}
+
+	for i, action := range expectedActions {
+		if revisions[i].Action() != action {
+			t.Fatalf("Expected revision %d to have action %q, got %q", i, action, revisions[i].Action())
+		}
+	}
+
+	created := revisions[2]
+	if created.RequestId() != "test_trace" {
+		t.Fatalf("Expected request id %q, got %q", "test_trace", created.RequestId())
+	}
+	if created.ActorRef() != user.Id || created.ActorCollectionRef() != user.Collection().Id {
+		t.Fatalf("Expected actor %q, got %q (%q)", user.Id, created.ActorRef(), created.ActorCollectionRef())
+	}
+
+	updated := revisions[1]
+	if diff := updated.Diff()["title"]; diff.Old != "a" || diff.New != "b" {
+		t.Fatalf("Expected title diff a->b, got %v", diff)
+	}
+	if updated.ActorRef() != "" || updated.RequestId() != "" {
+		t.Fatalf("Expected no actor and request id, got %q and %q", updated.ActorRef(), updated.RequestId())
+	}
+
+	deleted := revisions[0]
+	if diff := deleted.Diff()["title"]; diff.Old != "b" || diff.New != nil {
+		t.Fatalf("Expected title diff b->nil, got %v", diff)
+	}
+	if raw := deleted.Data().String(); raw != "" && raw != "null" {
+		t.Fatalf("Expected empty delete revision data, got %q", raw)
+	}
+
+	// collection without history
+	demo, err := app.FindRecordById("demo1", "84nmscqy84lsi1t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	demo.Set("text", "changed")
+	if err := app.Save(demo); err != nil {
+		t.Fatal(err)
+	}
+	if revisions, _ := app.FindAllRecordRevisions("demo1", demo.Id); len(revisions) != 0 {
+		t.Fatalf("Expected no revisions for collection without history, got %d", len(revisions))
+	}
+
+	// collection delete
+	if err := app.Delete(collection); err != nil {
+		t.Fatal(err)
+	}
+	total, err := app.CountRecords(core.CollectionNameRecordRevisions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 0 {
+		t.Fatalf("Expected the collection revisions to be deleted, got %d", total)
+	}
+}
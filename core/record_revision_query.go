@@ -0,0 +1,306 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/list"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// FindAllRecordRevisions returns all revisions of a single collection record
+// (including the revisions of an already deleted record) ordered from the newest to the oldest.
+func (app *BaseApp) FindAllRecordRevisions(collectionModelOrIdentifier any, recordId string) ([]*RecordRevision, error) {
+	collection, err := getCollectionByModelOrIdentifier(app, collectionModelOrIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*RecordRevision{}
+
+	err = app.RecordQuery(CollectionNameRecordRevisions).
+		AndWhere(dbx.HashExp{
+			"collectionRef": collection.Id,
+			"recordRef":     recordId,
+		}).
+		OrderBy("created DESC", "_rowid_ DESC").
+		All(&result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindRecordRevisionById returns a single RecordRevision model by its id.
+func (app *BaseApp) FindRecordRevisionById(id string) (*RecordRevision, error) {
+	result := &RecordRevision{}
+
+	err := app.RecordQuery(CollectionNameRecordRevisions).
+		AndWhere(dbx.HashExp{"id": id}).
+		Limit(1).
+		One(result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindRecordRevisionAsOf returns the latest revision of a single
+// collection record that was created before or at the specified date.
+//
+// Returns [sql.ErrNoRows] if there is no such revision.
+func (app *BaseApp) FindRecordRevisionAsOf(collectionModelOrIdentifier any, recordId string, date time.Time) (*RecordRevision, error) {
+	collection, err := getCollectionByModelOrIdentifier(app, collectionModelOrIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	maxDate, err := types.ParseDateTime(date)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RecordRevision{}
+
+	err = app.RecordQuery(CollectionNameRecordRevisions).
+		AndWhere(dbx.HashExp{
+			"collectionRef": collection.Id,
+			"recordRef":     recordId,
+		}).
+		AndWhere(dbx.NewExp("[[created]] <= {:date}", dbx.Params{"date": maxDate})).
+		OrderBy("created DESC", "_rowid_ DESC").
+		Limit(1).
+		One(result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindRecordAsOf returns the state of a single collection record
+// as it was at the specified date (based on the record revisions).
+//
+// Returns [sql.ErrNoRows] if the record didn't exist at the specified date
+// or there is no revision history for it.
+func (app *BaseApp) FindRecordAsOf(collectionModelOrIdentifier any, recordId string, date time.Time) (*Record, error) {
+	collection, err := getCollectionByModelOrIdentifier(app, collectionModelOrIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := app.FindRecordRevisionAsOf(collection, recordId, date)
+	if err != nil {
+		return nil, err
+	}
+
+	if revision.Action() == RecordRevisionActionDelete {
+		return nil, sql.ErrNoRows
+	}
+
+	record := NewRecord(collection)
+	if err := revision.ApplyTo(record); err != nil {
+		return nil, err
+	}
+	record.Id = recordId
+
+	if err := record.PostScan(); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// DeleteExpiredRecordRevisions deletes the expired record revisions
+// for all collections with history max days limit.
+//
+// The record files that are no longer referenced by the record itself or
+// by any of its remaining revisions are also deleted.
+func (app *BaseApp) DeleteExpiredRecordRevisions() error {
+	collections, err := app.FindAllCollections(CollectionTypeBase, CollectionTypeAuth)
+	if err != nil {
+		return err
+	}
+
+	// note: perform even if the history is disabled to ensure that there are no dangling old revisions
+	for _, collection := range collections {
+		if collection.History == nil || collection.History.MaxDays <= 0 {
+			continue
+		}
+
+		minValidDate, err := types.ParseDateTime(time.Now().Add(-1 * collection.History.MaxDuration()))
+		if err != nil {
+			return err
+		}
+
+		items := []*RecordRevision{}
+
+		err = app.RecordQuery(CollectionNameRecordRevisions).
+			AndWhere(dbx.HashExp{"collectionRef": collection.Id}).
+			AndWhere(dbx.NewExp("[[created]] < {:date}", dbx.Params{"date": minValidDate})).
+			All(&items)
+		if err != nil {
+			return err
+		}
+
+		expiredFiles := map[string][]string{}
+
+		for _, item := range items {
+			err = app.Delete(item)
+			if err != nil {
+				return err
+			}
+
+			expiredFiles[item.RecordRef()] = append(
+				expiredFiles[item.RecordRef()],
+				revisionFileNames(collection, item)...,
+			)
+		}
+
+		for recordId, files := range expiredFiles {
+			err = app.deleteUnreferencedRevisionFiles(collection, recordId, files)
+			if err != nil {
+				app.Logger().Warn(
+					"Failed to delete the expired record revision files",
+					"error", err,
+					"recordId", recordId,
+					"collectionId", collection.Id,
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
+// deleteUnreferencedRevisionFiles deletes the specified record files that are
+// no longer used by the record or by any of its remaining revisions.
+//
+// If the record was deleted and there are no remaining revisions, the entire record files directory is deleted.
+func (app *BaseApp) deleteUnreferencedRevisionFiles(collection *Collection, recordId string, files []string) error {
+	record, err := app.FindRecordById(collection, recordId)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	remaining, err := app.FindAllRecordRevisions(collection, recordId)
+	if err != nil {
+		return err
+	}
+
+	if record == nil && len(remaining) == 0 {
+		record = NewRecord(collection)
+		record.Id = recordId
+
+		fsys, err := app.NewFilesystem()
+		if err != nil {
+			return err
+		}
+		defer fsys.Close()
+
+		if failed := fsys.DeletePrefix(record.BaseFilesPath() + "/"); len(failed) > 0 {
+			return errors.Join(failed...)
+		}
+
+		return nil
+	}
+
+	fileFields := collectionFileFields(collection)
+	if len(fileFields) == 0 {
+		return nil
+	}
+
+	used := map[string]struct{}{}
+	if record != nil {
+		for _, field := range fileFields {
+			for _, name := range record.GetStringSlice(field.Name) {
+				used[name] = struct{}{}
+			}
+		}
+	} else {
+		record = NewRecord(collection)
+		record.Id = recordId
+	}
+
+	for _, revision := range remaining {
+		for _, name := range revisionFileNames(collection, revision) {
+			used[name] = struct{}{}
+		}
+	}
+
+	toDelete := make([]string, 0, len(files))
+	for _, name := range list.ToUniqueStringSlice(files) {
+		if _, ok := used[name]; !ok {
+			toDelete = append(toDelete, name)
+		}
+	}
+
+	_, err = fileFields[0].deleteFilesByNamesList(context.Background(), app, record, toDelete)
+
+	return err
+}
+
+// findRecordRevisionsFileNames returns the names of all files referenced in the record revisions.
+func findRecordRevisionsFileNames(app App, record *Record) []string {
+	revisions, err := app.FindAllRecordRevisions(record.Collection(), record.Id)
+	if err != nil {
+		return nil
+	}
+
+	var result []string
+	for _, revision := range revisions {
+		result = append(result, revisionFileNames(record.Collection(), revision)...)
+	}
+
+	return result
+}
+
+// revisionFileNames returns the names of all files referenced in the revision data and diff.
+func revisionFileNames(collection *Collection, revision *RecordRevision) []string {
+	fileFields := collectionFileFields(collection)
+	if len(fileFields) == 0 {
+		return nil
+	}
+
+	data := map[string]any{}
+	if raw := revision.Data(); len(raw) > 0 {
+		_ = json.Unmarshal(raw, &data)
+	}
+
+	diff := revision.Diff()
+
+	var result []string
+	for _, field := range fileFields {
+		name := field.Name
+
+		result = append(result, list.ToUniqueStringSlice(data[name])...)
+
+		if d, ok := diff[name]; ok {
+			result = append(result, list.ToUniqueStringSlice(d.Old)...)
+			result = append(result, list.ToUniqueStringSlice(d.New)...)
+		}
+	}
+
+	return result
+}
+
+func collectionFileFields(collection *Collection) []*FileField {
+	var result []*FileField
+
+	for _, field := range collection.Fields {
+		if f, ok := field.(*FileField); ok {
+			result = append(result, f)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,260 @@
+package core_test
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func createHistoryTestCollection(t testing.TB, app core.App, maxDays int) *core.Collection {
+	collection := core.NewBaseCollection("test_history")
+	collection.Fields.Add(
+		&core.TextField{Name: "title"},
+		&core.FileField{Name: "files", MaxSelect: 1},
+	)
+	collection.History = &core.HistoryConfig{Enabled: true, MaxDays: maxDays}
+
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	return collection
+}
+
+func setRevisionsCreated(t testing.TB, app core.App, recordId string, date time.Time) {
+	_, err := app.DB().Update(
+		core.CollectionNameRecordRevisions,
+		dbx.Params{"created": types.NowDateTime().Add(time.Until(date))},
+		dbx.HashExp{"recordRef": recordId},
+	).Execute()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFindRecordRevisionById(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := createHistoryTestCollection(t, app, 0)
+
+	record := core.NewRecord(collection)
+	record.Set("title", "a")
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	revisions, err := app.FindAllRecordRevisions(collection, record.Id)
+	if err != nil || len(revisions) != 1 {
+		t.Fatalf("Expected 1 revision, got %d (%v)", len(revisions), err)
+	}
+
+	revision, err := app.FindRecordRevisionById(revisions[0].Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if revision.Id != revisions[0].Id {
+		t.Fatalf("Expected revision %q, got %q", revisions[0].Id, revision.Id)
+	}
+
+	if _, err := app.FindRecordRevisionById("missing"); err == nil {
+		t.Fatal("Expected error for missing revision")
+	}
+}
+
+func TestFindRecordAsOf(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := createHistoryTestCollection(t, app, 0)
+
+	record := core.NewRecord(collection)
+	record.Set("title", "a")
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+	setRevisionsCreated(t, app, record.Id, time.Now().Add(-2*time.Hour))
+
+	record.Set("title", "b")
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	// backdate only the update revision
+	_, err := app.DB().Update(
+		core.CollectionNameRecordRevisions,
+		dbx.Params{"created": types.NowDateTime().Add(-1 * time.Hour)},
+		dbx.HashExp{"recordRef": record.Id, "action": core.RecordRevisionActionUpdate},
+	).Execute()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Delete(record); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name          string
+		date          time.Time
+		expectedTitle string
+		expectedErr   bool
+	}{
+		{"before create", time.Now().Add(-3 * time.Hour), "", true},
+		{"after create", time.Now().Add(-90 * time.Minute), "a", false},
+		{"after update", time.Now().Add(-30 * time.Minute), "b", false},
+		{"after delete", time.Now().Add(time.Minute), "", true},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result, err := app.FindRecordAsOf(collection, record.Id, s.date)
+
+			hasErr := err != nil
+			if hasErr != s.expectedErr {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectedErr, hasErr, err)
+			}
+
+			if hasErr {
+				if !errors.Is(err, sql.ErrNoRows) {
+					t.Fatalf("Expected sql.ErrNoRows, got %v", err)
+				}
+				return
+			}
+
+			if result.Id != record.Id {
+				t.Fatalf("Expected record id %q, got %q", record.Id, result.Id)
+			}
+
+			if result.IsNew() {
+				t.Fatal("Expected the record to be marked as not new")
+			}
+
+			if v := result.GetString("title"); v != s.expectedTitle {
+				t.Fatalf("Expected title %q, got %q", s.expectedTitle, v)
+			}
+		})
+	}
+}
+
+func TestDeleteExpiredRecordRevisions(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := createHistoryTestCollection(t, app, 1)
+
+	newFile := func(name string) *filesystem.File {
+		f, err := filesystem.NewFileFromBytes([]byte("test"), name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+
+	fileExists := func(record *core.Record, name string) bool {
+		fsys, err := app.NewFilesystem()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer fsys.Close()
+
+		exists, _ := fsys.Exists(record.BaseFilesPath() + "/" + name)
+		return exists
+	}
+
+	// updated record
+	// ---
+	record1 := core.NewRecord(collection)
+	record1.Set("files", newFile("old.txt"))
+	if err := app.Save(record1); err != nil {
+		t.Fatal(err)
+	}
+	oldFile := record1.GetString("files")
+
+	record1.Set("files", newFile("new.txt"))
+	if err := app.Save(record1); err != nil {
+		t.Fatal(err)
+	}
+	newFilename := record1.GetString("files")
+
+	if !fileExists(record1, oldFile) {
+		t.Fatal("Expected the replaced file to be kept for the revisions")
+	}
+
+	// deleted record
+	// ---
+	record2 := core.NewRecord(collection)
+	record2.Set("files", newFile("deleted.txt"))
+	if err := app.Save(record2); err != nil {
+		t.Fatal(err)
+	}
+	deletedFile := record2.GetString("files")
+
+	if err := app.Delete(record2); err != nil {
+		t.Fatal(err)
+	}
+
+	// wait for the eventual background files delete
+	time.Sleep(100 * time.Millisecond)
+
+	if !fileExists(record2, deletedFile) {
+		t.Fatal("Expected the deleted record file to be kept for the revisions")
+	}
+
+	// non-expired
+	// ---
+	if err := app.DeleteExpiredRecordRevisions(); err != nil {
+		t.Fatal(err)
+	}
+
+	total, err := app.CountRecords(core.CollectionNameRecordRevisions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 4 {
+		t.Fatalf("Expected 4 revisions, got %d", total)
+	}
+
+	// expired
+	// ---
+	setRevisionsCreated(t, app, record1.Id, time.Now().Add(-25*time.Hour))
+	setRevisionsCreated(t, app, record2.Id, time.Now().Add(-25*time.Hour))
+
+	if err := app.DeleteExpiredRecordRevisions(); err != nil {
+		t.Fatal(err)
+	}
+
+	total, err = app.CountRecords(core.CollectionNameRecordRevisions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 0 {
+		t.Fatalf("Expected 0 revisions, got %d", total)
+	}
+
+	if fileExists(record1, oldFile) {
+		t.Fatal("Expected the replaced file to be deleted")
+	}
+
+	if !fileExists(record1, newFilename) {
+		t.Fatal("Expected the current record file to be kept")
+	}
+
+	if fileExists(record2, deletedFile) {
+		t.Fatal("Expected the deleted record file to be deleted")
+	}
+}
@@ -0,0 +1,179 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/spf13/cast"
+)
+
+// SearchSnippetKey is the record custom data key under which the
+// full-text search snippets are loaded (see [LoadSearchSnippets]).
+const SearchSnippetKey = "@snippet"
+
+// LoadSearchSnippets loads the highlighted full-text search snippets of
+// the searchable fields for each of the provided records based on the
+// first fts() filter function resolved by the provided resolver.
+//
+// The snippets are stored as map[fieldName]snippet custom data under
+// the [SearchSnippetKey] key of each record.
+//
+// This method is a no-op if the resolver hasn't resolved a fts() function.
+func LoadSearchSnippets(app App, resolver *RecordFieldResolver, records []*Record) error {
+	if len(records) == 0 || len(resolver.searchMatches) == 0 {
+		return nil
+	}
+
+	collection := resolver.baseCollection
+	tableName := searchTableName(collection)
+	match := resolver.searchMatches[0]
+
+	selects := make([]string, 0, len(collection.Search.Fields)+1)
+	selects = append(selects, "[["+FieldNameId+"]]")
+	for i, name := range collection.Search.Fields {
+		// note: the first shadow table column is the record id
+		selects = append(selects, fmt.Sprintf(
+			"snippet({{%s}}, %d, '<mark>', '</mark>', '...', 16) as [[%s]]",
+			tableName,
+			i+1,
+			name,
+		))
+	}
+
+	ids := make([]any, len(records))
+	for i, r := range records {
+		ids[i] = r.Id
+	}
+
+	rows := []dbx.NullStringMap{}
+
+	err := app.DB().Select(selects...).
+		From(tableName).
+		Where(dbx.NewExp("{{"+tableName+"}} MATCH "+match.Identifier, match.Params)).
+		AndWhere(dbx.In(FieldNameId, ids...)).
+		All(&rows)
+	if err != nil {
+		return err
+	}
+
+	snippets := make(map[string]map[string]string, len(rows))
+	for _, row := range rows {
+		item := make(map[string]string, len(collection.Search.Fields))
+		for _, name := range collection.Search.Fields {
+			item[name] = row[name].String
+		}
+		snippets[row[FieldNameId].String] = item
+	}
+
+	for _, r := range records {
+		if item, ok := snippets[r.Id]; ok {
+			r.SetRaw(SearchSnippetKey, item)
+			r.WithCustomData(true)
+		}
+	}
+
+	return nil
+}
+
+func (app *BaseApp) registerRecordSearchHooks() {
+	searchHandler := func(e *RecordEvent) error {
+		if !e.Record.Collection().SearchEnabled() {
+			return e.Next()
+		}
+
+		oldId := cast.ToString(e.Record.LastSavedPK())
+
+		originalApp := e.App
+		txErr := e.App.RunInTransaction(func(txApp App) error {
+			e.App = txApp
+
+			if err := e.Next(); err != nil {
+				return err
+			}
+
+			if oldId != "" {
+				if err := deleteSearchRow(txApp, e.Record.Collection(), oldId); err != nil {
+					return err
+				}
+			}
+
+			return insertSearchRow(txApp, e.Record)
+		})
+		e.App = originalApp
+
+		return txErr
+	}
+
+	app.OnRecordCreateExecute().Bind(&hook.Handler[*RecordEvent]{
+		Func: searchHandler,
+	})
+
+	app.OnRecordUpdateExecute().Bind(&hook.Handler[*RecordEvent]{
+		Func: searchHandler,
+	})
+
+	app.OnRecordDeleteExecute().Bind(&hook.Handler[*RecordEvent]{
+		Func: func(e *RecordEvent) error {
+			if !e.Record.Collection().SearchEnabled() {
+				return e.Next()
+			}
+
+			recordId := cast.ToString(e.Record.LastSavedPK())
+
+			originalApp := e.App
+			txErr := e.App.RunInTransaction(func(txApp App) error {
+				e.App = txApp
+
+				if err := e.Next(); err != nil {
+					return err
+				}
+
+				return deleteSearchRow(txApp, e.Record.Collection(), recordId)
+			})
+			e.App = originalApp
+
+			return txErr
+		},
+	})
+}
+
+var searchTokenRegex = regexp.MustCompile(`[\pL\pN]`)
+
+// deleteSearchRow deletes the shadow table row of a single record.
+func deleteSearchRow(app App, collection *Collection, recordId string) error {
+	tableName := searchTableName(collection)
+
+	// use the id column index as long as the id has at least one token character
+	// (the exact match is ensured by the additional equality check)
+	var matchExpr dbx.Expression
+	if searchTokenRegex.MatchString(recordId) {
+		matchExpr = dbx.NewExp("{{"+tableName+"}} MATCH {:match}", dbx.Params{
+			"match": FieldNameId + `:"` + strings.ReplaceAll(recordId, `"`, `""`) + `"`,
+		})
+	}
+
+	_, err := app.DB().Delete(tableName, dbx.And(
+		matchExpr,
+		dbx.HashExp{FieldNameId: recordId},
+	)).Execute()
+
+	return err
+}
+
+// insertSearchRow inserts the searchable fields of a single record in its collection shadow table.
+func insertSearchRow(app App, record *Record) error {
+	collection := record.Collection()
+
+	params := make(dbx.Params, len(collection.Search.Fields)+1)
+	params[FieldNameId] = record.Id
+	for _, name := range collection.Search.Fields {
+		params[name] = record.GetString(name)
+	}
+
+	_, err := app.DB().Insert(searchTableName(collection), params).Execute()
+
+	return err
+}
@@ -0,0 +1,271 @@
+package core_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/search"
+)
+
+func createSearchTestCollection(t testing.TB, app core.App) *core.Collection {
+	collection := core.NewBaseCollection("test_search")
+	collection.Fields.Add(
+		&core.TextField{Name: "title"},
+		&core.EditorField{Name: "content"},
+		&core.TextField{Name: "other"},
+	)
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	// existing records before enabling the full-text search
+	for _, data := range []map[string]any{
+		{"id": "search000000001", "title": "hello world", "content": "lorem ipsum", "other": "search"},
+		{"id": "search000000002", "title": "other", "content": "hello hello lorem", "other": "search"},
+	} {
+		record := core.NewRecord(collection)
+		record.Load(data)
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	collection.Search = &core.SearchConfig{Fields: []string{"title", "content"}}
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	return collection
+}
+
+func searchRecordIds(t testing.TB, app core.App, collection *core.Collection, filter string, sort string) []string {
+	resolver := core.NewRecordFieldResolver(app, collection, nil, true)
+
+	provider := search.NewProvider(resolver).
+		Query(app.RecordQuery(collection)).
+		Filter([]search.FilterData{search.FilterData(filter)})
+	if sort != "" {
+		provider.Sort(search.ParseSortFromString(sort))
+	}
+
+	records := []*core.Record{}
+	_, err := provider.Exec(&records)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := make([]string, len(records))
+	for i, r := range records {
+		ids[i] = r.Id
+	}
+
+	return ids
+}
+
+func TestCollectionSearchConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name           string
+		fields         []string
+		expectedErrors []string
+	}{
+		{"no fields", []string{}, []string{}},
+		{"missing field", []string{"missing"}, []string{"search"}},
+		{"non-text field", []string{"num"}, []string{"search"}},
+		{"reserved field", []string{"id"}, []string{"search"}},
+		{"duplicated field", []string{"title", "title"}, []string{"search"}},
+		{"hidden field", []string{"title", "secret"}, []string{"search"}},
+		{"field with a view rule", []string{"title", "protected"}, []string{"search"}},
+		{"valid fields", []string{"title", "content"}, []string{}},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			app, _ := tests.NewTestApp()
+			defer app.Cleanup()
+
+			collection := core.NewBaseCollection("test")
+			collection.Fields.Add(
+				&core.TextField{Name: "title"},
+				&core.EditorField{Name: "content"},
+				&core.NumberField{Name: "num"},
+				&core.TextField{Name: "secret", Hidden: true},
+				&core.TextField{Name: "protected"},
+			)
+			collection.FieldRules = map[string]core.FieldRule{
+				"protected": {ViewRule: "@request.auth.id != ''"},
+			}
+			collection.Search = &core.SearchConfig{Fields: s.fields}
+
+			tests.TestValidationErrors(t, app.Validate(collection), s.expectedErrors)
+		})
+	}
+}
+
+func TestRecordSearchTableSync(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := createSearchTestCollection(t, app)
+
+	tableName := "_fts_" + collection.Id
+
+	if !app.HasTable(tableName) {
+		t.Fatalf("Expected %q table to be created", tableName)
+	}
+
+	// existing records
+	ids := searchRecordIds(t, app, collection, "fts('hello') = true", "id")
+	if str := strings.Join(ids, ","); str != "search000000001,search000000002" {
+		t.Fatalf("Expected the existing records to be indexed, got %v", ids)
+	}
+
+	// non-searchable field
+	if ids := searchRecordIds(t, app, collection, "fts('search') = true", ""); len(ids) != 0 {
+		t.Fatalf("Expected non-searchable fields to be ignored, got %v", ids)
+	}
+
+	// rename the searchable field
+	collection.Fields.GetByName("title").SetName("title_new")
+	collection.Search.Fields[0] = "title_new"
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+	if ids := searchRecordIds(t, app, collection, "fts('title_new:world') = true", ""); len(ids) != 1 {
+		t.Fatalf("Expected the table to be recreated with the new field name, got %v", ids)
+	}
+
+	// disable
+	collection.Search = nil
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+	if app.HasTable(tableName) {
+		t.Fatalf("Expected %q table to be deleted", tableName)
+	}
+
+	// enable again and delete the collection
+	collection.Search = &core.SearchConfig{Fields: []string{"content"}}
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+	if !app.HasTable(tableName) {
+		t.Fatalf("Expected %q table to be recreated", tableName)
+	}
+	if err := app.Delete(collection); err != nil {
+		t.Fatal(err)
+	}
+	if app.HasTable(tableName) {
+		t.Fatalf("Expected %q table to be deleted with the collection", tableName)
+	}
+}
+
+func TestRecordSearchHooks(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := createSearchTestCollection(t, app)
+
+	// create
+	record := core.NewRecord(collection)
+	record.Set("title", "unique_create")
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+	if ids := searchRecordIds(t, app, collection, "fts('unique_create') = true", ""); len(ids) != 1 || ids[0] != record.Id {
+		t.Fatalf("Expected the created record to be indexed, got %v", ids)
+	}
+
+	// update
+	record.Set("title", "unique_update")
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+	if ids := searchRecordIds(t, app, collection, "fts('unique_create') = true", ""); len(ids) != 0 {
+		t.Fatalf("Expected the old record data to be removed from the index, got %v", ids)
+	}
+	if ids := searchRecordIds(t, app, collection, "fts('unique_update') = true", ""); len(ids) != 1 {
+		t.Fatalf("Expected the updated record to be indexed, got %v", ids)
+	}
+
+	// delete
+	if err := app.Delete(record); err != nil {
+		t.Fatal(err)
+	}
+	total, err := app.CountRecords(collection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var indexed int
+	err = app.DB().Select("count(*)").From("_fts_" + collection.Id).Row(&indexed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int64(indexed) != total {
+		t.Fatalf("Expected %d indexed rows, got %d", total, indexed)
+	}
+}
+
+func TestRecordSearchRankAndSnippets(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := createSearchTestCollection(t, app)
+
+	// the second record has more "hello" occurrences
+	ids := searchRecordIds(t, app, collection, "fts('hello') = true", "@rank")
+	if str := strings.Join(ids, ","); str != "search000000002,search000000001" {
+		t.Fatalf("Expected the records to be sorted by relevance, got %v", ids)
+	}
+
+	// @rank without fts()
+	resolver := core.NewRecordFieldResolver(app, collection, nil, true)
+	_, err := search.NewProvider(resolver).
+		Query(app.RecordQuery(collection)).
+		Sort(search.ParseSortFromString("@rank")).
+		Exec(&[]*core.Record{})
+	if err == nil {
+		t.Fatal("Expected @rank sort error without fts() filter")
+	}
+
+	// collection without searchable fields
+	demo, err := app.FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := search.FilterData("fts('hello') = true").BuildExpr(core.NewRecordFieldResolver(app, demo, nil, true)); err == nil {
+		t.Fatal("Expected fts() error for collection without searchable fields")
+	}
+
+	// snippets
+	resolver = core.NewRecordFieldResolver(app, collection, nil, true)
+	records := []*core.Record{}
+	_, err = search.NewProvider(resolver).
+		Query(app.RecordQuery(collection)).
+		Filter([]search.FilterData{"fts('world') = true"}).
+		Exec(&records)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(records))
+	}
+
+	if err := core.LoadSearchSnippets(app, resolver, records); err != nil {
+		t.Fatal(err)
+	}
+
+	snippets, _ := records[0].GetRaw(core.SearchSnippetKey).(map[string]string)
+	if snippets["title"] != "hello <mark>world</mark>" {
+		t.Fatalf("Expected title snippet %q, got %q", "hello <mark>world</mark>", snippets["title"])
+	}
+}
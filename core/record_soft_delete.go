@@ -0,0 +1,142 @@
+package core
+
+import (
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/pocketbase/pocketbase/tools/types"
+	"github.com/spf13/cast"
+)
+
+// softDeletedKey is an internal record flag that marks the record
+// as soft deleted during the current delete operation.
+const softDeletedKey = internalCustomFieldKeyPrefix + "_softDeleted_"
+
+// IsSoftDeleted reports whether the record is from a collection with
+// enabled soft delete and it has a non-empty "deleted" field value.
+func (m *Record) IsSoftDeleted() bool {
+	return m.Collection().SoftDeleteEnabled() && !m.GetDateTime(FieldNameDeleted).IsZero()
+}
+
+// PurgeSoftDeletedRecords permanently deletes the soft deleted records
+// that are older than their collection soft delete max days limit.
+//
+// The records are deleted one by one with app.Delete so that the
+// related record hooks and the relations cascade are executed.
+//
+// Records that fail to be deleted are logged and skipped.
+func (app *BaseApp) PurgeSoftDeletedRecords() error {
+	collections, err := app.FindAllCollections(CollectionTypeBase, CollectionTypeAuth)
+	if err != nil {
+		return err
+	}
+
+	for _, collection := range collections {
+		if !collection.SoftDeleteEnabled() || collection.SoftDelete.MaxDays <= 0 {
+			continue
+		}
+
+		minValidDate, err := types.ParseDateTime(time.Now().Add(-1 * collection.SoftDelete.MaxDuration()))
+		if err != nil {
+			return err
+		}
+
+		records, err := app.FindAllRecords(
+			collection,
+			dbx.Not(dbx.HashExp{FieldNameDeleted: ""}),
+			dbx.NewExp("[["+FieldNameDeleted+"]] < {:date}", dbx.Params{"date": minValidDate}),
+		)
+		if err != nil {
+			return err
+		}
+
+		for _, record := range records {
+			// log and skip the failed records (eg. required by another record relation)
+			// so that they don't block the purge of the remaining ones
+			if err := app.Delete(record); err != nil {
+				app.Logger().Warn(
+					"Failed to purge soft deleted record",
+					"error", err,
+					"recordId", record.Id,
+					"collectionId", collection.Id,
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (app *BaseApp) registerRecordSoftDeleteHooks() {
+	// run on every hour to purge the expired soft deleted records
+	app.Cron().Add("__pbSoftDeletePurge__", "45 * * * *", func() {
+		if err := app.PurgeSoftDeletedRecords(); err != nil {
+			app.Logger().Warn("Failed to purge soft deleted records", "error", err)
+		}
+	})
+
+	// replace the record db delete with a "deleted" field update
+	//
+	// note: the handler doesn't continue the execution chain so the
+	// relations cascade and the files delete are performed only when
+	// an already soft deleted record is deleted (aka. purged)
+	app.OnRecordDeleteExecute().Bind(&hook.Handler[*RecordEvent]{
+		Func: func(e *RecordEvent) error {
+			if !e.Record.Collection().SoftDeleteEnabled() || e.Record.IsSoftDeleted() {
+				// reset in case of a previous soft delete of the same record model
+				e.Record.SetRaw(softDeletedKey, nil)
+
+				return e.Next()
+			}
+
+			now := types.NowDateTime()
+
+			params := dbx.Params{FieldNameDeleted: now.String()}
+
+			// invalidate the previously issued auth tokens
+			var tokenKey string
+			if e.Record.Collection().IsAuth() {
+				tokenKey = security.RandomString(50)
+				params[FieldNameTokenKey] = tokenKey
+			}
+
+			err := baseLockRetry(func(attempt int) error {
+				_, err := e.App.NonconcurrentDB().Update(
+					e.Record.Collection().Name,
+					params,
+					dbx.HashExp{FieldNameId: cast.ToString(e.Record.LastSavedPK())},
+				).WithContext(e.Context).Execute()
+
+				return err
+			}, defaultMaxLockRetries)
+			if err != nil {
+				return err
+			}
+
+			e.Record.SetRaw(FieldNameDeleted, now)
+			if tokenKey != "" {
+				e.Record.SetRaw(FieldNameTokenKey, tokenKey)
+			}
+			e.Record.SetRaw(softDeletedKey, true)
+
+			return nil
+		},
+		Priority: -99,
+	})
+}
+
+// isSoftDeletedRecord reports whether the model is a record that
+// was soft deleted as part of the current delete operation.
+func isSoftDeletedRecord(m Model) bool {
+	var record *Record
+	switch v := m.(type) {
+	case *Record:
+		record = v
+	case RecordProxy:
+		record = v.ProxyRecord()
+	}
+
+	return record != nil && cast.ToBool(record.GetRaw(softDeletedKey))
+}
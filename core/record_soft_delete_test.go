@@ -0,0 +1,219 @@
+package core_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// createSoftDeleteTestCollections creates a soft delete "test_parent"
+// collection and a "test_child" collection with a cascade relation to it.
+func createSoftDeleteTestCollections(t testing.TB, app core.App, maxDays int) (*core.Collection, *core.Collection) {
+	parent := core.NewBaseCollection("test_parent")
+	parent.Fields.Add(&core.TextField{Name: "title"})
+	parent.SoftDelete = &core.SoftDeleteConfig{Enabled: true, MaxDays: maxDays}
+	if err := app.Save(parent); err != nil {
+		t.Fatal(err)
+	}
+
+	child := core.NewBaseCollection("test_child")
+	child.Fields.Add(&core.RelationField{
+		Name:          "parent",
+		CollectionId:  parent.Id,
+		MaxSelect:     1,
+		CascadeDelete: true,
+	})
+	if err := app.Save(child); err != nil {
+		t.Fatal(err)
+	}
+
+	return parent, child
+}
+
+func TestCollectionSoftDeleteField(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test")
+	collection.SoftDelete = &core.SoftDeleteConfig{Enabled: true}
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	field, ok := collection.Fields.GetByName(core.FieldNameDeleted).(*core.DateField)
+	if !ok {
+		t.Fatalf("Expected %q date field, got %v", core.FieldNameDeleted, collection.Fields.GetByName(core.FieldNameDeleted))
+	}
+	if !field.System || !field.Hidden {
+		t.Fatalf("Expected system hidden field, got system %v and hidden %v", field.System, field.Hidden)
+	}
+
+	// disable
+	collection.SoftDelete.Enabled = false
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+	if field.System {
+		t.Fatal("Expected the field to be no longer system")
+	}
+
+	// remove the field
+	collection.Fields.RemoveByName(core.FieldNameDeleted)
+	if err := app.Save(collection); err != nil {
+		t.Fatalf("Expected the deleted field to be removable, got %v", err)
+	}
+}
+
+func TestRecordSoftDelete(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	parent, child := createSoftDeleteTestCollections(t, app, 0)
+
+	parentRecord := core.NewRecord(parent)
+	parentRecord.Set("title", "a")
+	if err := app.Save(parentRecord); err != nil {
+		t.Fatal(err)
+	}
+
+	childRecord := core.NewRecord(child)
+	childRecord.Set("parent", parentRecord.Id)
+	if err := app.Save(childRecord); err != nil {
+		t.Fatal(err)
+	}
+
+	// soft delete
+	// ---
+	if err := app.Delete(parentRecord); err != nil {
+		t.Fatal(err)
+	}
+
+	if !parentRecord.IsSoftDeleted() {
+		t.Fatal("Expected the model to be marked as soft deleted")
+	}
+
+	found, err := app.FindRecordById(parent, parentRecord.Id)
+	if err != nil {
+		t.Fatalf("Expected the soft deleted record to be still in the db, got %v", err)
+	}
+	if !found.IsSoftDeleted() {
+		t.Fatal("Expected the db record to be soft deleted")
+	}
+
+	if _, err := app.FindRecordById(child, childRecord.Id); err != nil {
+		t.Fatalf("Expected the cascade to not be applied on soft delete, got %v", err)
+	}
+
+	// purge
+	// ---
+	if err := app.Delete(found); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.FindRecordById(parent, parentRecord.Id); err == nil {
+		t.Fatal("Expected the purged record to be deleted")
+	}
+
+	if _, err := app.FindRecordById(child, childRecord.Id); err == nil {
+		t.Fatal("Expected the cascade to be applied on purge")
+	}
+}
+
+func TestRecordSoftDeleteAuthTokenKey(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.FindCollectionByNameOrId("nologin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	collection.SoftDelete = &core.SoftDeleteConfig{Enabled: true}
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := app.FindAllRecords(collection, dbx.NewExp("1=1"))
+	if err != nil || len(records) == 0 {
+		t.Fatalf("Expected at least one nologin record, got %d (%v)", len(records), err)
+	}
+	record := records[0]
+
+	token, err := record.NewAuthToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Delete(record); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.FindAuthRecordByToken(token, core.TokenTypeAuth); err == nil {
+		t.Fatal("Expected the old auth token to be invalidated")
+	}
+}
+
+func TestPurgeSoftDeletedRecords(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	parent, _ := createSoftDeleteTestCollections(t, app, 1)
+
+	records := make([]*core.Record, 4)
+	for i := range records {
+		records[i] = core.NewRecord(parent)
+		if err := app.Save(records[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// records[0] - active
+	// records[1] - recently soft deleted
+	// records[2] - expired soft deleted but undeletable
+	// records[3] - expired soft deleted
+	for _, record := range records[1:] {
+		if err := app.Delete(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, record := range records[2:] {
+		_, err := app.DB().Update(
+			parent.Name,
+			dbx.Params{core.FieldNameDeleted: types.NowDateTime().Add(-25 * time.Hour)},
+			dbx.HashExp{"id": record.Id},
+		).Execute()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	app.OnRecordDelete(parent.Name).BindFunc(func(e *core.RecordEvent) error {
+		if e.Record.Id == records[2].Id {
+			return errors.New("test_error")
+		}
+		return e.Next()
+	})
+
+	if err := app.PurgeSoftDeletedRecords(); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, expectExists := range []bool{true, true, true, false} {
+		_, err := app.FindRecordById(parent, records[i].Id)
+		if exists := err == nil; exists != expectExists {
+			t.Errorf("[%d] Expected exists %v, got %v", i, expectExists, exists)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package core
+
+import (
+	"errors"
+
+	"github.com/pocketbase/dbx"
+)
+
+// TenantId returns the tenant id of the record
+// (empty string if the record collection is not tenant scoped).
+func (m *Record) TenantId() string {
+	if !m.Collection().TenantScoped() {
+		return ""
+	}
+
+	return m.GetString(FieldNameTenant)
+}
+
+// AuthTenantId returns the tenant id of the auth record
+// in the context of the specified tenant scoped collection.
+//
+// Returns empty string if the auth record is nil or it doesn't
+// belong to the same tenant collection as the specified collection.
+func AuthTenantId(collection *Collection, auth *Record) string {
+	if auth == nil || !collection.TenantScoped() {
+		return ""
+	}
+
+	authCollection := auth.Collection()
+	if !authCollection.TenantScoped() || authCollection.Tenant.CollectionId != collection.Tenant.CollectionId {
+		return ""
+	}
+
+	return auth.GetString(FieldNameTenant)
+}
+
+// TenantScopeExpr returns a db expression that limits the collection
+// records to the tenant of the specified auth record
+// (aka. "tenant = @request.auth.tenant").
+//
+// Returns nil if the collection is not tenant scoped or the auth record is a superuser.
+//
+// Returns an always false expression if the auth record is nil or
+// it doesn't have a tenant from the same tenant collection.
+func TenantScopeExpr(collection *Collection, auth *Record) dbx.Expression {
+	return tenantScopeExpr(collection, auth, collection.Name)
+}
+
+// tenantScopeExpr is similar to [TenantScopeExpr] but allows specifying
+// the collection table alias (e.g. for joined tables).
+func tenantScopeExpr(collection *Collection, auth *Record, tableAlias string) dbx.Expression {
+	if !collection.TenantScoped() || (auth != nil && auth.IsSuperuser()) {
+		return nil
+	}
+
+	tenantId := AuthTenantId(collection, auth)
+	if tenantId == "" {
+		return dbx.NewExp("1=0")
+	}
+
+	return dbx.HashExp{tableAlias + "." + FieldNameTenant: tenantId}
+}
+
+// tenantRecordsBatchSize is the number of records loaded at once
+// when iterating over the tenant records.
+const tenantRecordsBatchSize = 200
+
+// EachTenantRecords loads the records of the specified tenant in batches
+// and calls fn for each batch.
+//
+// The records are iterated per tenant scoped collection and ordered by their id,
+// meaning that all batches of a collection are passed consecutively.
+//
+// The iteration stops on the first fn error.
+func (app *BaseApp) EachTenantRecords(tenant *Record, fn func(collection *Collection, records []*Record) error) error {
+	if tenant == nil || tenant.Id == "" {
+		return errors.New("missing tenant record")
+	}
+
+	collections, err := app.FindAllCollections(CollectionTypeBase, CollectionTypeAuth)
+	if err != nil {
+		return err
+	}
+
+	for _, collection := range collections {
+		if !collection.TenantScoped() || collection.Tenant.CollectionId != tenant.Collection().Id {
+			continue
+		}
+
+		var lastId string
+		for {
+			records := []*Record{}
+
+			err := app.RecordQuery(collection).
+				AndWhere(dbx.HashExp{collection.Name + "." + FieldNameTenant: tenant.Id}).
+				AndWhere(dbx.NewExp("[["+collection.Name+"."+FieldNameId+"]] > {:lastId}", dbx.Params{"lastId": lastId})).
+				OrderBy(collection.Name + "." + FieldNameId + " ASC").
+				Limit(tenantRecordsBatchSize).
+				All(&records)
+			if err != nil {
+				return err
+			}
+
+			if len(records) == 0 {
+				break
+			}
+
+			if err := fn(collection, records); err != nil {
+				return err
+			}
+
+			if len(records) < tenantRecordsBatchSize {
+				break
+			}
+
+			lastId = records[len(records)-1].Id
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,444 @@
+package core_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/search"
+)
+
+// createTenantTestCollections creates a "test_tenants" collection with
+// 2 tenant records, a tenant scoped "test_notes" collection with
+// 1 note per tenant and enables the tenant scope for the "users" collection.
+func createTenantTestCollections(t testing.TB, app core.App) (tenants *core.Collection, notes *core.Collection) {
+	tenants = core.NewBaseCollection("test_tenants")
+	tenants.Fields.Add(&core.TextField{Name: "name"})
+	if err := app.Save(tenants); err != nil {
+		t.Fatal(err)
+	}
+
+	notes = core.NewBaseCollection("test_notes")
+	notes.Fields.Add(&core.TextField{Name: "title"})
+	notes.Tenant = &core.TenantConfig{Enabled: true, CollectionId: tenants.Id}
+	if err := app.Save(notes); err != nil {
+		t.Fatal(err)
+	}
+
+	users, err := app.FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	users.Tenant = &core.TenantConfig{Enabled: true, CollectionId: tenants.Id}
+	if err := app.Save(users); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"a", "b"} {
+		tenant := core.NewRecord(tenants)
+		tenant.Id = "tenant" + name + "00000000"
+		tenant.Set("name", name)
+		if err := app.Save(tenant); err != nil {
+			t.Fatal(err)
+		}
+
+		note := core.NewRecord(notes)
+		note.Id = "note" + name + "0000000000"
+		note.Set("title", "note "+name)
+		note.Set(core.FieldNameTenant, tenant.Id)
+		if err := app.Save(note); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return tenants, notes
+}
+
+func TestCollectionTenantField(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	tenants, notes := createTenantTestCollections(t, app)
+
+	field, ok := notes.Fields.GetByName(core.FieldNameTenant).(*core.RelationField)
+	if !ok {
+		t.Fatalf("Expected %q relation field, got %v", core.FieldNameTenant, notes.Fields.GetByName(core.FieldNameTenant))
+	}
+	if !field.System || !field.Hidden || !field.CascadeDelete || field.MaxSelect != 1 || field.CollectionId != tenants.Id {
+		t.Fatalf("Unexpected tenant field %#v", field)
+	}
+
+	if len(notes.Indexes) != 1 {
+		t.Fatalf("Expected the tenant field index, got %v", notes.Indexes)
+	}
+
+	// the tenant collection cannot be tenant scoped
+	tenants.Tenant = &core.TenantConfig{Enabled: true, CollectionId: notes.Id}
+	if err := app.Save(tenants); err == nil {
+		t.Fatal("Expected the tenant collection save to fail")
+	}
+
+	// disable
+	notes.Tenant.Enabled = false
+	if err := app.Save(notes); err != nil {
+		t.Fatal(err)
+	}
+	if field.System {
+		t.Fatal("Expected the field to be no longer system")
+	}
+	if len(notes.Indexes) != 0 {
+		t.Fatalf("Expected the tenant field index to be removed, got %v", notes.Indexes)
+	}
+
+	// remove the field
+	notes.Fields.RemoveByName(core.FieldNameTenant)
+	if err := app.Save(notes); err != nil {
+		t.Fatalf("Expected the tenant field to be removable, got %v", err)
+	}
+}
+
+func TestAuthTenantId(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	_, notes := createTenantTestCollections(t, app)
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user.Set(core.FieldNameTenant, "tenanta00000000")
+
+	nologin, err := app.FindAuthRecordByEmail("nologin", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	demo1, err := app.FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name       string
+		collection *core.Collection
+		auth       *core.Record
+		expected   string
+	}{
+		{"nil auth", notes, nil, ""},
+		{"non tenant scoped collection", demo1, user, ""},
+		{"non tenant scoped auth collection", notes, nologin, ""},
+		{"same tenant collection", notes, user, "tenanta00000000"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			if v := core.AuthTenantId(s.collection, s.auth); v != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestTenantScopeExpr(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	_, notes := createTenantTestCollections(t, app)
+
+	superuser, err := app.FindAuthRecordByEmail(core.CollectionNameSuperusers, "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user.Set(core.FieldNameTenant, "tenantb00000000")
+
+	demo1, err := app.FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expr := core.TenantScopeExpr(demo1, user); expr != nil {
+		t.Fatalf("Expected nil expression for non tenant scoped collection, got %v", expr)
+	}
+
+	if expr := core.TenantScopeExpr(notes, superuser); expr != nil {
+		t.Fatalf("Expected nil expression for superuser, got %v", expr)
+	}
+
+	scenarios := []struct {
+		name     string
+		auth     *core.Record
+		expected []string
+	}{
+		{"guest", nil, []string{}},
+		{"tenant user", user, []string{"noteb0000000000"}},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			records, err := app.FindAllRecords(notes, core.TenantScopeExpr(notes, s.auth))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(records) != len(s.expected) {
+				t.Fatalf("Expected %d records, got %d", len(s.expected), len(records))
+			}
+
+			for i, id := range s.expected {
+				if records[i].Id != id {
+					t.Fatalf("Expected record %d to be %q, got %q", i, id, records[i].Id)
+				}
+			}
+		})
+	}
+}
+
+func TestRecordFieldResolverTenantScope(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	tenants, notes := createTenantTestCollections(t, app)
+
+	// non tenant scoped collection referencing the other tenant note
+	links := core.NewBaseCollection("test_links")
+	links.Fields.Add(
+		&core.RelationField{Name: "note", CollectionId: notes.Id, MaxSelect: 1},
+		&core.RelationField{Name: "notes", CollectionId: notes.Id, MaxSelect: 2},
+	)
+	if err := app.Save(links); err != nil {
+		t.Fatal(err)
+	}
+	link := core.NewRecord(links)
+	link.Id = "link00000000000"
+	link.Set("note", "noteb0000000000")
+	link.Set("notes", []string{"noteb0000000000"})
+	if err := app.Save(link); err != nil {
+		t.Fatal(err)
+	}
+
+	superuser, err := app.FindAuthRecordByEmail(core.CollectionNameSuperusers, "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userA, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	userA.Set(core.FieldNameTenant, "tenanta00000000")
+
+	userB := userA.Clone()
+	userB.Set(core.FieldNameTenant, "tenantb00000000")
+
+	scenarios := []struct {
+		name       string
+		collection *core.Collection
+		auth       *core.Record
+		filter     string
+		expected   []string
+	}{
+		{"@collection join (superuser)", tenants, superuser, "@collection.test_notes.title ?= 'note b'", []string{"tenanta00000000", "tenantb00000000"}},
+		{"@collection join (guest)", tenants, nil, "@collection.test_notes.title ?= 'note b'", []string{}},
+		{"@collection join (other tenant user)", tenants, userA, "@collection.test_notes.title ?= 'note b'", []string{}},
+		{"@collection join (same tenant user)", tenants, userB, "@collection.test_notes.title ?= 'note b'", []string{"tenanta00000000", "tenantb00000000"}},
+		{"back relation join (superuser)", tenants, superuser, "test_notes_via_tenant.title ?= 'note b'", []string{"tenantb00000000"}},
+		{"back relation join (guest)", tenants, nil, "test_notes_via_tenant.title ?= 'note b'", []string{}},
+		{"back relation join (other tenant user)", tenants, userA, "test_notes_via_tenant.title ?= 'note b'", []string{}},
+		{"back relation join (same tenant user)", tenants, userB, "test_notes_via_tenant.title ?= 'note b'", []string{"tenantb00000000"}},
+		{"single relation join (superuser)", links, superuser, "note.title = 'note b'", []string{"link00000000000"}},
+		{"single relation join (guest)", links, nil, "note.title = 'note b'", []string{}},
+		{"single relation join (other tenant user)", links, userA, "note.title = 'note b'", []string{}},
+		{"single relation join (same tenant user)", links, userB, "note.title = 'note b'", []string{"link00000000000"}},
+		{"multiple relation join (superuser)", links, superuser, "notes.title ?= 'note b'", []string{"link00000000000"}},
+		{"multiple relation join (other tenant user)", links, userA, "notes.title ?= 'note b'", []string{}},
+		{"multiple relation join (same tenant user)", links, userB, "notes.title ?= 'note b'", []string{"link00000000000"}},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			// note: allow hidden fields because the tenant relation field is hidden
+			resolver := core.NewRecordFieldResolver(app, s.collection, &core.RequestInfo{Auth: s.auth}, true)
+
+			expr, err := search.FilterData(s.filter).BuildExpr(resolver)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			query := app.RecordQuery(s.collection).AndWhere(expr).OrderBy("id ASC")
+			if err := resolver.UpdateQuery(query); err != nil {
+				t.Fatal(err)
+			}
+
+			records := []*core.Record{}
+			if err := query.All(&records); err != nil {
+				t.Fatal(err)
+			}
+
+			ids := make([]string, len(records))
+			for i, r := range records {
+				ids[i] = r.Id
+			}
+
+			if len(ids) != len(s.expected) {
+				t.Fatalf("Expected records %v, got %v", s.expected, ids)
+			}
+			for i, id := range s.expected {
+				if ids[i] != id {
+					t.Fatalf("Expected records %v, got %v", s.expected, ids)
+				}
+			}
+		})
+	}
+}
+
+func TestRelationFieldTenantValidation(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	_, notes := createTenantTestCollections(t, app)
+
+	notes.Fields.Add(&core.RelationField{Name: "related", CollectionId: notes.Id, MaxSelect: 2})
+	if err := app.Save(notes); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name        string
+		related     []string
+		expectError bool
+	}{
+		{"same tenant", []string{"notea0000000000"}, false},
+		{"other tenant", []string{"noteb0000000000"}, true},
+		{"mixed tenants", []string{"notea0000000000", "noteb0000000000"}, true},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			record := core.NewRecord(notes)
+			record.Set(core.FieldNameTenant, "tenanta00000000")
+			record.Set("related", s.related)
+
+			err := app.Validate(record)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+		})
+	}
+}
+
+func TestEachTenantRecordsAndDelete(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	tenants, _ := createTenantTestCollections(t, app)
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user.Set(core.FieldNameTenant, "tenanta00000000")
+	if err := app.Save(user); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.EachTenantRecords(nil, func(c *core.Collection, records []*core.Record) error { return nil }); err == nil {
+		t.Fatal("Expected error for nil tenant")
+	}
+
+	tenant, err := app.FindRecordById(tenants, "tenanta00000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := map[string][]string{}
+	err = app.EachTenantRecords(tenant, func(c *core.Collection, records []*core.Record) error {
+		for _, r := range records {
+			result[c.Name] = append(result[c.Name], r.Id)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result) != 2 ||
+		len(result["test_notes"]) != 1 || result["test_notes"][0] != "notea0000000000" ||
+		len(result["users"]) != 1 || result["users"][0] != user.Id {
+		t.Fatalf("Unexpected tenant records %v", result)
+	}
+
+	// batches
+	notes, err := app.FindCollectionByNameOrId("test_notes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 200; i++ {
+		note := core.NewRecord(notes)
+		note.Id = fmt.Sprintf("notebatch%06d", i)
+		note.Set(core.FieldNameTenant, tenant.Id)
+		if err := app.Save(note); err != nil {
+			t.Fatal(err)
+		}
+	}
+	batches := []int{}
+	err = app.EachTenantRecords(tenant, func(c *core.Collection, records []*core.Record) error {
+		if c.Name == "test_notes" {
+			batches = append(batches, len(records))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(batches) != 2 || batches[0] != 200 || batches[1] != 1 {
+		t.Fatalf("Expected test_notes batches [200 1], got %v", batches)
+	}
+
+	// stop on error
+	fnErr := errors.New("test")
+	calls := 0
+	err = app.EachTenantRecords(tenant, func(c *core.Collection, records []*core.Record) error {
+		calls++
+		return fnErr
+	})
+	if !errors.Is(err, fnErr) || calls != 1 {
+		t.Fatalf("Expected the iteration to stop with the fn error after 1 call, got %v (%d calls)", err, calls)
+	}
+
+	// delete the tenant as a unit
+	if err := app.Delete(tenant); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.FindRecordById("test_notes", "notea0000000000"); err == nil {
+		t.Fatal("Expected the tenant note to be deleted")
+	}
+	if _, err := app.FindRecordById("users", user.Id); err == nil {
+		t.Fatal("Expected the tenant user to be deleted")
+	}
+	if _, err := app.FindRecordById("test_notes", "noteb0000000000"); err != nil {
+		t.Fatalf("Expected the other tenant note to be preserved, got %v", err)
+	}
+}
@@ -0,0 +1,382 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/types"
+	"github.com/pocketbase/pocketbase/tools/vector"
+)
+
+// VectorIndexesDirName is the name of the app data directory
+// subfolder where the vector indexes are persisted.
+const VectorIndexesDirName = "vector_indexes"
+
+const storeKeyVectorIndexes = "pbAppVectorIndexes"
+
+// vectorIndexRegistry holds the loaded in-memory vector field indexes.
+type vectorIndexRegistry struct {
+	mu      sync.Mutex
+	indexes map[string]*vector.HNSW // collectionId_fieldId => index
+}
+
+func vectorIndexes(app App) *vectorIndexRegistry {
+	return app.Store().GetOrSet(storeKeyVectorIndexes, func() any {
+		return &vectorIndexRegistry{indexes: map[string]*vector.HNSW{}}
+	}).(*vectorIndexRegistry)
+}
+
+func vectorIndexKey(collection *Collection, field *VectorField) string {
+	return collection.Id + "_" + field.Id
+}
+
+func vectorIndexPath(app App, key string) string {
+	return filepath.Join(app.DataDir(), VectorIndexesDirName, key+".hnsw")
+}
+
+// indexedVectorFields returns the collection vector fields with enabled index.
+func indexedVectorFields(collection *Collection) []*VectorField {
+	var result []*VectorField
+
+	for _, f := range collection.Fields {
+		if vf, ok := f.(*VectorField); ok && vf.Index {
+			result = append(result, vf)
+		}
+	}
+
+	return result
+}
+
+// FindVectorNearest returns the ids of the approximate k nearest records
+// to the query vector based on the specified indexed vector field.
+//
+// The index is loaded (or rebuilt) on first use.
+func FindVectorNearest(app App, collection *Collection, fieldName string, query types.Vector, k int) ([]vector.SearchResult, error) {
+	field, ok := collection.Fields.GetByName(fieldName).(*VectorField)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a vector field", fieldName)
+	}
+
+	if !field.Index {
+		return nil, fmt.Errorf("vector field %q doesn't have enabled index", fieldName)
+	}
+
+	registry := vectorIndexes(app)
+
+	registry.mu.Lock()
+	index, err := registry.load(app, collection, field)
+	registry.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	return index.Search(query, k)
+}
+
+// load returns the loaded vector field index or tries to load it
+// from its persisted file, falling back to a full rebuild from the db.
+//
+// The registry must be locked by the caller.
+func (r *vectorIndexRegistry) load(app App, collection *Collection, field *VectorField) (*vector.HNSW, error) {
+	key := vectorIndexKey(collection, field)
+
+	options := vector.HNSWOptions{Metric: field.metric(), Dimensions: field.Dimensions}
+
+	index, ok := r.indexes[key]
+	if ok && isVectorIndexCompatible(index, options) {
+		return index, nil
+	}
+
+	var total int
+	err := app.DB().Select("count(*)").
+		From(collection.Name).
+		Where(dbx.NewExp("length([["+field.Name+"]]) = {:size}", dbx.Params{"size": field.Dimensions * 4})).
+		Row(&total)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err = loadVectorIndexFile(vectorIndexPath(app, key))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		app.Logger().Warn("Failed to load vector index file", "key", key, "error", err)
+	}
+
+	if index == nil || !isVectorIndexCompatible(index, options) || index.Len() != total {
+		index, err = buildVectorIndex(app, collection, field, options)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the file is removed after load so that a non-graceful app
+	// termination could trigger a rebuild on the next start
+	if err := os.Remove(vectorIndexPath(app, key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		app.Logger().Warn("Failed to remove vector index file", "key", key, "error", err)
+	}
+
+	r.indexes[key] = index
+
+	return index, nil
+}
+
+// save persists all loaded indexes in the app data dir.
+func (r *vectorIndexRegistry) save(app App) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.indexes) == 0 {
+		return nil
+	}
+
+	dir := filepath.Join(app.DataDir(), VectorIndexesDirName)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for key, index := range r.indexes {
+		if err := saveVectorIndexFile(vectorIndexPath(app, key), index); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// invalidate unloads and deletes the persisted indexes of the specified
+// collection that are no longer indexed vector fields.
+//
+// If deleted is true, all collection indexes are invalidated.
+func (r *vectorIndexRegistry) invalidate(app App, collection *Collection, deleted bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	keep := map[string]struct{}{}
+	if !deleted {
+		for _, f := range indexedVectorFields(collection) {
+			keep[vectorIndexKey(collection, f)] = struct{}{}
+		}
+	}
+
+	files, _ := filepath.Glob(filepath.Join(app.DataDir(), VectorIndexesDirName, collection.Id+"_*.hnsw"))
+
+	keys := make([]string, 0, len(r.indexes)+len(files))
+	for key := range r.indexes {
+		keys = append(keys, key)
+	}
+	for _, file := range files {
+		keys = append(keys, strings.TrimSuffix(filepath.Base(file), ".hnsw"))
+	}
+
+	for _, key := range keys {
+		if !strings.HasPrefix(key, collection.Id+"_") {
+			continue
+		}
+
+		if _, ok := keep[key]; ok {
+			continue
+		}
+
+		delete(r.indexes, key)
+
+		if err := os.Remove(vectorIndexPath(app, key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			app.Logger().Warn("Failed to remove vector index file", "key", key, "error", err)
+		}
+	}
+}
+
+// sync updates the record vector in the loaded indexes (if any).
+func (r *vectorIndexRegistry) sync(app App, record *Record, deleted bool) {
+	fields := indexedVectorFields(record.Collection())
+	if len(fields) == 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, f := range fields {
+		index, ok := r.indexes[vectorIndexKey(record.Collection(), f)]
+		if !ok {
+			continue // not loaded yet
+		}
+
+		vec, _ := record.GetRaw(f.Name).(types.Vector)
+
+		if deleted || len(vec) != index.Options().Dimensions {
+			index.Remove(record.Id)
+			continue
+		}
+
+		if err := index.Add(record.Id, vec); err != nil {
+			app.Logger().Warn("Failed to index record vector", "recordId", record.Id, "field", f.Name, "error", err)
+		}
+	}
+}
+
+func isVectorIndexCompatible(index *vector.HNSW, options vector.HNSWOptions) bool {
+	indexOptions := index.Options()
+
+	return indexOptions.Metric == options.Metric && indexOptions.Dimensions == options.Dimensions
+}
+
+func buildVectorIndex(app App, collection *Collection, field *VectorField, options vector.HNSWOptions) (*vector.HNSW, error) {
+	index, err := vector.NewHNSW(options)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := app.DB().Select("[["+FieldNameId+"]]", "[["+field.Name+"]]").
+		From(collection.Name).
+		Where(dbx.NewExp("length([["+field.Name+"]]) = {:size}", dbx.Params{"size": field.Dimensions * 4})).
+		Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		var raw []byte
+		if err := rows.Scan(&id, &raw); err != nil {
+			return nil, err
+		}
+
+		vec, err := types.ParseVector(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := index.Add(id, vec); err != nil {
+			return nil, err
+		}
+	}
+
+	return index, rows.Err()
+}
+
+func loadVectorIndexFile(path string) (*vector.HNSW, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return vector.LoadHNSW(f)
+}
+
+func saveVectorIndexFile(path string, index *vector.HNSW) error {
+	// write in a temp file first to prevent partial writes
+	tmpPath := path + ".tmp"
+
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+
+	if err := index.Save(f); err != nil {
+		f.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
+}
+
+func (app *BaseApp) registerRecordVectorIndexHooks() {
+	// load (or rebuild) the indexes on start
+	app.OnBootstrap().Bind(&hook.Handler[*BootstrapEvent]{
+		Func: func(e *BootstrapEvent) error {
+			if err := e.Next(); err != nil {
+				return err
+			}
+
+			collections, err := e.App.FindAllCollections()
+			if err != nil {
+				// most likely the migrations haven't been applied yet
+				e.App.Logger().Debug("Failed to load the vector indexes", "error", err)
+				return nil
+			}
+
+			registry := vectorIndexes(e.App)
+			registry.mu.Lock()
+			defer registry.mu.Unlock()
+
+			for _, collection := range collections {
+				for _, field := range indexedVectorFields(collection) {
+					if _, err := registry.load(e.App, collection, field); err != nil {
+						e.App.Logger().Warn(
+							"Failed to load vector index",
+							"collection", collection.Name,
+							"field", field.Name,
+							"error", err,
+						)
+					}
+				}
+			}
+
+			return nil
+		},
+	})
+
+	// persist the loaded indexes
+	app.OnTerminate().Bind(&hook.Handler[*TerminateEvent]{
+		Func: func(e *TerminateEvent) error {
+			if err := vectorIndexes(e.App).save(e.App); err != nil {
+				e.App.Logger().Warn("Failed to persist the vector indexes", "error", err)
+			}
+
+			return e.Next()
+		},
+	})
+
+	syncHandler := func(deleted bool) func(e *RecordEvent) error {
+		return func(e *RecordEvent) error {
+			vectorIndexes(e.App).sync(e.App, e.Record, deleted)
+
+			return e.Next()
+		}
+	}
+
+	app.OnRecordAfterCreateSuccess().Bind(&hook.Handler[*RecordEvent]{
+		Func: syncHandler(false),
+	})
+
+	app.OnRecordAfterUpdateSuccess().Bind(&hook.Handler[*RecordEvent]{
+		Func: syncHandler(false),
+	})
+
+	app.OnRecordAfterDeleteSuccess().Bind(&hook.Handler[*RecordEvent]{
+		Func: syncHandler(true),
+	})
+
+	// unload the indexes of the removed collection fields
+	// (the indexes with changed settings are rebuilt on first use)
+	app.OnCollectionAfterUpdateSuccess().Bind(&hook.Handler[*CollectionEvent]{
+		Func: func(e *CollectionEvent) error {
+			vectorIndexes(e.App).invalidate(e.App, e.Collection, false)
+
+			return e.Next()
+		},
+	})
+
+	app.OnCollectionAfterDeleteSuccess().Bind(&hook.Handler[*CollectionEvent]{
+		Func: func(e *CollectionEvent) error {
+			vectorIndexes(e.App).invalidate(e.App, e.Collection, true)
+
+			return e.Next()
+		},
+	})
+}
@@ -0,0 +1,259 @@
+package core_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/search"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func createVectorTestCollection(t *testing.T, app core.App) *core.Collection {
+	collection := core.NewBaseCollection("test_vectors")
+	collection.ListRule = types.Pointer("")
+	collection.Fields.Add(
+		&core.TextField{Name: "title"},
+		&core.VectorField{Name: "embedding", Dimensions: 2, Index: true, Metric: "l2"},
+		&core.VectorField{Name: "other", Dimensions: 2},
+		&core.VectorField{Name: "secret", Dimensions: 2, Index: true, Hidden: true},
+	)
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 10; i++ {
+		record := core.NewRecord(collection)
+		record.Id = fmt.Sprintf("vector%09d", i)
+		record.Set("title", fmt.Sprintf("t%d", i))
+		record.Set("embedding", types.Vector{float32(i), 0})
+		record.Set("secret", types.Vector{float32(i), 1})
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return collection
+}
+
+func findVectorNearestTitles(t *testing.T, app core.App, collection *core.Collection, filter string, requestInfo *core.RequestInfo) ([]string, error) {
+	records := []*core.Record{}
+
+	resolver := core.NewRecordFieldResolver(app, collection, requestInfo, requestInfo == nil)
+	_, err := search.NewProvider(resolver).
+		Query(app.RecordQuery(collection)).
+		Filter([]search.FilterData{search.FilterData(filter)}).
+		Sort(search.ParseSortFromString("title")).
+		Exec(&records)
+	if err != nil {
+		return nil, err
+	}
+
+	titles := make([]string, len(records))
+	for i, r := range records {
+		titles[i] = r.GetString("title")
+	}
+
+	return titles, nil
+}
+
+func TestRecordVectorNearestFilter(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := createVectorTestCollection(t, app)
+
+	guest := &core.RequestInfo{Query: map[string]string{"q": "[7.2, 0]"}}
+
+	scenarios := []struct {
+		name        string
+		filter      string
+		requestInfo *core.RequestInfo
+		expected    []string
+		expectError bool
+	}{
+		{"missing args", "vectorNearest(embedding, '[1, 0]') = true", nil, nil, true},
+		{"non-vector field", "vectorNearest(title, '[1, 0]', 2) = true", nil, nil, true},
+		{"non-indexed field", "vectorNearest(other, '[1, 0]', 2) = true", nil, nil, true},
+		{"hidden field (guest)", "vectorNearest(secret, @request.query.q, 2) = true", guest, nil, true},
+		{"invalid query", "vectorNearest(embedding, 'abc', 2) = true", nil, nil, true},
+		{"mismatched query dimensions", "vectorNearest(embedding, '[1]', 2) = true", nil, nil, true},
+		{"column query", "vectorNearest(embedding, other, 2) = true", nil, nil, true},
+		{"zero k", "vectorNearest(embedding, '[1, 0]', 0) = true", nil, nil, true},
+		{"too large k", "vectorNearest(embedding, '[1, 0]', 1001) = true", nil, nil, true},
+		{"text query", "vectorNearest(embedding, '[2.1, 0]', 3) = true", nil, []string{"t1", "t2", "t3"}, false},
+		{"request query (guest)", "vectorNearest(embedding, @request.query.q, 2) = true", guest, []string{"t7", "t8"}, false},
+		{"hidden field (superuser)", "vectorNearest(secret, '[0, 1]', 1) = true", nil, []string{"t0"}, false},
+		{"combined with other filter", "vectorNearest(embedding, '[2.1, 0]', 3) = true && title != 't2'", nil, []string{"t1", "t3"}, false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			titles, err := findVectorNearestTitles(t, app, collection, s.filter, s.requestInfo)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if !hasErr && fmt.Sprint(titles) != fmt.Sprint(s.expected) {
+				t.Fatalf("Expected %v, got %v", s.expected, titles)
+			}
+		})
+	}
+}
+
+func TestRecordVectorIndexSync(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := createVectorTestCollection(t, app)
+
+	nearest := func(k int) []string {
+		results, err := core.FindVectorNearest(app, collection, "embedding", types.Vector{100, 0}, k)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ids := make([]string, len(results))
+		for i, r := range results {
+			ids[i] = r.Id
+		}
+		return ids
+	}
+
+	if ids := nearest(1); !slices.Equal(ids, []string{"vector000000009"}) {
+		t.Fatalf("Expected the last record to be the nearest, got %v", ids)
+	}
+
+	// create
+	record := core.NewRecord(collection)
+	record.Id = "vectornew000000"
+	record.Set("embedding", types.Vector{99, 0})
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+	if ids := nearest(1); !slices.Equal(ids, []string{"vectornew000000"}) {
+		t.Fatalf("Expected the created record to be indexed, got %v", ids)
+	}
+
+	// update
+	record.Set("embedding", types.Vector{-1, 0})
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+	if ids := nearest(1); !slices.Equal(ids, []string{"vector000000009"}) {
+		t.Fatalf("Expected the updated record vector to be reindexed, got %v", ids)
+	}
+
+	// clear
+	record.Set("embedding", nil)
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+	if ids := nearest(20); len(ids) != 10 || slices.Contains(ids, record.Id) {
+		t.Fatalf("Expected the empty record vector to be removed from the index, got %v", ids)
+	}
+
+	// delete
+	toDelete, err := app.FindRecordById(collection, "vector000000009")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := app.Delete(toDelete); err != nil {
+		t.Fatal(err)
+	}
+	if ids := nearest(1); !slices.Equal(ids, []string{"vector000000008"}) {
+		t.Fatalf("Expected the deleted record to be removed from the index, got %v", ids)
+	}
+
+	// change the field metric and dimensions (rebuild)
+	field := collection.Fields.GetByName("embedding").(*core.VectorField)
+	field.Dimensions = 3
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+	results, err := core.FindVectorNearest(app, collection, "embedding", types.Vector{1, 0, 0}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 0 {
+		t.Fatalf("Expected the existing 2 dimensional vectors to be excluded from the rebuilt index, got %v", results)
+	}
+}
+
+func TestRecordVectorIndexPersistence(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+
+	collection := createVectorTestCollection(t, app)
+	field := collection.Fields.GetByName("embedding")
+
+	indexFile := filepath.Join(core.VectorIndexesDirName, collection.Id+"_"+field.GetId()+".hnsw")
+
+	// load the index
+	if _, err := core.FindVectorNearest(app, collection, "embedding", types.Vector{1, 0}, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// change a vector without triggering the record hooks to check
+	// whether the persisted index is used on the next start
+	_, err := app.DB().Update(
+		collection.Name,
+		dbx.Params{"embedding": types.Vector{1000, 0}},
+		dbx.HashExp{"id": "vector000000000"},
+	).Execute()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// clone the data dir after the indexes persist on terminate
+	var dataDir string
+	app.OnTerminate().Bind(&hook.Handler[*core.TerminateEvent]{
+		Func: func(e *core.TerminateEvent) error {
+			if err := e.Next(); err != nil {
+				return err
+			}
+
+			if _, err := os.Stat(filepath.Join(e.App.DataDir(), indexFile)); err != nil {
+				t.Fatalf("Expected the index file to be persisted: %v", err)
+			}
+
+			dataDir, err = tests.TempDirClone(e.App.DataDir())
+
+			return err
+		},
+		Priority: -1000,
+	})
+	app.Cleanup()
+	defer os.RemoveAll(dataDir)
+
+	restarted, err := tests.NewTestApp(dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer restarted.Cleanup()
+
+	if _, err := os.Stat(filepath.Join(restarted.DataDir(), indexFile)); !os.IsNotExist(err) {
+		t.Fatalf("Expected the index file to be removed after load, got %v", err)
+	}
+
+	results, err := core.FindVectorNearest(restarted, collection, "embedding", types.Vector{1000, 0}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Id != "vector000000009" {
+		t.Fatalf("Expected the persisted (stale) index to be loaded, got %v", results)
+	}
+}
@@ -127,6 +127,7 @@ type settings struct {
 	RateLimits   RateLimitsConfig   `form:"rateLimits" json:"rateLimits"`
 	TrustedProxy TrustedProxyConfig `form:"trustedProxy" json:"trustedProxy"`
 	Batch        BatchConfig        `form:"batch" json:"batch"`
+	GraphQL      GraphQLConfig      `form:"graphql" json:"graphql"`
 	Logs         LogsConfig         `form:"logs" json:"logs"`
 }
 
@@ -169,6 +170,11 @@ func newDefaultSettings() *Settings {
 				MaxRequests: 50,
 				Timeout:     3,
 			},
+			GraphQL: GraphQLConfig{
+				Enabled:       false,
+				MaxDepth:      10,
+				MaxComplexity: 5000,
+			},
 			RateLimits: RateLimitsConfig{
 				Enabled: false, // @todo once tested enough enable by default for new installations
 				Rules: []RateLimitRule{
@@ -285,6 +291,7 @@ func (s *Settings) PostValidate(ctx context.Context, app App) error {
 		validation.Field(&s.S3),
 		validation.Field(&s.Backups),
 		validation.Field(&s.Batch),
+		validation.Field(&s.GraphQL),
 		validation.Field(&s.RateLimits),
 		validation.Field(&s.TrustedProxy),
 	)
@@ -443,6 +450,29 @@ func (c BatchConfig) Validate() error {
 
 // -------------------------------------------------------------------
 
+type GraphQLConfig struct {
+	Enabled bool `form:"enabled" json:"enabled"`
+
+	// MaxDepth is the maximum allowed nesting of the selected fields.
+	MaxDepth int `form:"maxDepth" json:"maxDepth"`
+
+	// MaxComplexity is the maximum allowed total query complexity.
+	//
+	// Each selected field costs 1 point and the list fields multiply
+	// the cost of their sub-selection by the requested page size.
+	MaxComplexity int `form:"maxComplexity" json:"maxComplexity"`
+}
+
+// Validate makes GraphQLConfig validatable by implementing [validation.Validatable] interface.
+func (c GraphQLConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.MaxDepth, validation.When(c.Enabled, validation.Required), validation.Min(0)),
+		validation.Field(&c.MaxComplexity, validation.When(c.Enabled, validation.Required), validation.Min(0)),
+	)
+}
+
+// -------------------------------------------------------------------
+
 type BackupsConfig struct {
 	// Cron is a cron expression to schedule auto backups, eg. "* * * * *".
 	//
@@ -106,7 +106,7 @@ func TestSettingsMarshalJSON(t *testing.T) {
 	}
 	rawStr := string(raw)
 
-	expected := `{"smtp":{"enabled":false,"port":0,"host":"","username":"abc","authMethod":"","tls":false,"localName":""},"backups":{"cron":"","cronMaxKeep":0,"s3":{"enabled":false,"bucket":"","region":"","endpoint":"","accessKey":"","forcePathStyle":false}},"s3":{"enabled":false,"bucket":"","region":"","endpoint":"","accessKey":"","forcePathStyle":false},"meta":{"appName":"test123","appURL":"","senderName":"","senderAddress":"","hideControls":false},"rateLimits":{"rules":[],"enabled":false},"trustedProxy":{"headers":[],"useLeftmostIP":false},"batch":{"enabled":false,"maxRequests":0,"timeout":0,"maxBodySize":0},"logs":{"maxDays":0,"minLevel":0,"logIP":false,"logAuthId":false}}`
+	expected := `{"smtp":{"enabled":false,"port":0,"host":"","username":"abc","authMethod":"","tls":false,"localName":""},"backups":{"cron":"","cronMaxKeep":0,"s3":{"enabled":false,"bucket":"","region":"","endpoint":"","accessKey":"","forcePathStyle":false}},"s3":{"enabled":false,"bucket":"","region":"","endpoint":"","accessKey":"","forcePathStyle":false},"meta":{"appName":"test123","appURL":"","senderName":"","senderAddress":"","hideControls":false},"rateLimits":{"rules":[],"enabled":false},"trustedProxy":{"headers":[],"useLeftmostIP":false},"batch":{"enabled":false,"maxRequests":0,"timeout":0,"maxBodySize":0},"graphql":{"enabled":false,"maxDepth":0,"maxComplexity":0},"logs":{"maxDays":0,"minLevel":0,"logIP":false,"logAuthId":false}}`
 
 	if rawStr != expected {
 		t.Fatalf("Expected\n%v\ngot\n%v", expected, rawStr)
@@ -133,6 +133,8 @@ func TestSettingsValidate(t *testing.T) {
 	s.Batch.Enabled = true
 	s.Batch.MaxRequests = -1
 	s.Batch.Timeout = -1
+	s.GraphQL.Enabled = true
+	s.GraphQL.MaxDepth = -1
 	s.RateLimits.Enabled = true
 	s.RateLimits.Rules = nil
 
@@ -149,6 +151,7 @@ func TestSettingsValidate(t *testing.T) {
 		`"s3":{`,
 		`"backups":{`,
 		`"batch":{`,
+		`"graphql":{`,
 		`"rateLimits":{`,
 	}
 
@@ -485,6 +488,50 @@ func TestBatchConfigValidate(t *testing.T) {
 	}
 }
 
+func TestGraphQLConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		config         core.GraphQLConfig
+		expectedErrors []string
+	}{
+		{
+			"zero value",
+			core.GraphQLConfig{},
+			[]string{},
+		},
+		{
+			"zero value (enabled)",
+			core.GraphQLConfig{Enabled: true},
+			[]string{"maxDepth", "maxComplexity"},
+		},
+		{
+			"invalid data (negative values)",
+			core.GraphQLConfig{
+				MaxDepth:      -1,
+				MaxComplexity: -1,
+			},
+			[]string{"maxDepth", "maxComplexity"},
+		},
+		{
+			"valid data",
+			core.GraphQLConfig{
+				Enabled:       true,
+				MaxDepth:      5,
+				MaxComplexity: 100,
+			},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := s.config.Validate()
+
+			tests.TestValidationErrors(t, result, s.expectedErrors)
+		})
+	}
+}
+
 func TestRateLimitsConfigValidate(t *testing.T) {
 	scenarios := []struct {
 		name           string
@@ -0,0 +1,465 @@
+package core
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// MaterializedViewsTable is the name of the system table that stores
+// the last refresh state of the materialized view collections.
+const MaterializedViewsTable = "_materializedViews"
+
+// DefaultMaterializedViewRefreshOnChangeDelay is the default number of
+// milliseconds to wait before refreshing a materialized view on source
+// records change (used when the collection RefreshOnChangeDelay is not set).
+const DefaultMaterializedViewRefreshOnChangeDelay = 500
+
+// MaterializedViewInfo holds the last refresh state of a materialized view collection.
+type MaterializedViewInfo struct {
+	CollectionRef string         `db:"collectionRef" json:"collectionRef"`
+	Refreshed     types.DateTime `db:"refreshed" json:"refreshed"`
+	TotalRows     int            `db:"totalRows" json:"totalRows"`
+}
+
+// FindMaterializedViewInfo returns the last refresh state of the provided
+// materialized view collection.
+//
+// A zero Refreshed date is returned if the view wasn't refreshed yet.
+func (app *BaseApp) FindMaterializedViewInfo(collection *Collection) (*MaterializedViewInfo, error) {
+	if !collection.IsMaterializedView() {
+		return nil, errors.New("not a materialized view collection")
+	}
+
+	info := &MaterializedViewInfo{}
+
+	err := app.DB().Select("*").
+		From(MaterializedViewsTable).
+		AndWhere(dbx.HashExp{"collectionRef": collection.Id}).
+		Limit(1).
+		One(info)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &MaterializedViewInfo{CollectionRef: collection.Id}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// RefreshMaterializedView recomputes the view query of the provided
+// materialized view collection and syncs its table in a single transaction.
+//
+// Only the rows that were changed since the last refresh are written.
+func (app *BaseApp) RefreshMaterializedView(collection *Collection) error {
+	if !collection.IsMaterializedView() {
+		return errors.New("not a materialized view collection")
+	}
+
+	query, err := normalizeViewQueryId(app, collection.ViewQuery)
+	if err != nil {
+		return fmt.Errorf("failed to normalize view query id: %w", err)
+	}
+
+	return app.RunInTransaction(func(txApp App) error {
+		if !txApp.HasTable(collection.Name) {
+			if err := createMaterializedViewTable(txApp, collection); err != nil {
+				return err
+			}
+		}
+
+		return refreshMaterializedViewTable(txApp, collection, query)
+	})
+}
+
+// -------------------------------------------------------------------
+
+// createMaterializedViewTable (re)creates the materialized view table and its indexes.
+func createMaterializedViewTable(app App, collection *Collection) error {
+	return app.RunInTransaction(func(txApp App) error {
+		if err := dropViewOrTable(txApp, collection.Name); err != nil {
+			return err
+		}
+
+		cols := make(map[string]string, len(collection.Fields))
+		for _, field := range collection.Fields {
+			cols[field.GetName()] = materializedColumnType(txApp, field)
+		}
+
+		if _, err := txApp.DB().CreateTable(collection.Name, cols).Execute(); err != nil {
+			return err
+		}
+
+		return createCollectionIndexes(txApp, collection)
+	})
+}
+
+// materializedColumnType returns the column type of the materialized view field.
+//
+// The default values and the NOT NULL constraints of the regular field
+// column types are omitted since the view query could return NULL values.
+func materializedColumnType(app App, field Field) string {
+	if field.GetName() == FieldNameId {
+		return "TEXT PRIMARY KEY NOT NULL"
+	}
+
+	columnType := strings.Fields(field.ColumnType(app))
+	if len(columnType) == 0 {
+		return "TEXT"
+	}
+
+	return columnType[0]
+}
+
+func refreshMaterializedViewTable(app App, collection *Collection, query string) error {
+	columns := make([]string, 0, len(collection.Fields))
+	for _, field := range collection.Fields {
+		columns = append(columns, "[["+field.GetName()+"]]")
+	}
+	rawColumns := strings.Join(columns, ",")
+
+	query = strings.Trim(strings.TrimSpace(query), ";")
+
+	// delete the rows that are no longer part of the view result
+	_, err := app.DB().NewQuery(fmt.Sprintf(
+		"DELETE FROM {{%s}} WHERE [[id]] NOT IN (SELECT [[id]] FROM (%s) WHERE [[id]] IS NOT NULL)",
+		collection.Name,
+		query,
+	)).Execute()
+	if err != nil {
+		return fmt.Errorf("failed to delete the stale materialized view rows: %w", err)
+	}
+
+	// insert or replace only the new and changed rows
+	_, err = app.DB().NewQuery(fmt.Sprintf(
+		"INSERT OR REPLACE INTO {{%s}} (%s) SELECT %s FROM (%s) WHERE [[id]] IS NOT NULL EXCEPT SELECT %s FROM {{%s}}",
+		collection.Name,
+		rawColumns,
+		rawColumns,
+		query,
+		rawColumns,
+		collection.Name,
+	)).Execute()
+	if err != nil {
+		return fmt.Errorf("failed to insert the materialized view rows: %w", err)
+	}
+
+	var total int
+	err = app.DB().Select("count(*)").From(collection.Name).Row(&total)
+	if err != nil {
+		return err
+	}
+
+	_, err = app.DB().NewQuery(
+		"INSERT INTO {{" + MaterializedViewsTable + "}} ([[collectionRef]], [[refreshed]], [[totalRows]]) " +
+			"VALUES ({:collectionRef}, {:refreshed}, {:totalRows}) " +
+			"ON CONFLICT ([[collectionRef]]) DO UPDATE SET [[refreshed]] = excluded.[[refreshed]], [[totalRows]] = excluded.[[totalRows]]",
+	).Bind(dbx.Params{
+		"collectionRef": collection.Id,
+		"refreshed":     types.NowDateTime().String(),
+		"totalRows":     total,
+	}).Execute()
+
+	return err
+}
+
+// dropViewOrTable drops the view or table with the specified name (if exists).
+func dropViewOrTable(app App, name string) error {
+	var objectType string
+
+	err := app.DB().Select("type").
+		From("sqlite_schema").
+		AndWhere(dbx.NewExp("LOWER([[name]])=LOWER({:name})", dbx.Params{"name": name})).
+		AndWhere(dbx.In("type", "table", "view")).
+		Limit(1).
+		Row(&objectType)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if objectType == "view" {
+		return app.DeleteView(name)
+	}
+
+	return app.DeleteTable(name)
+}
+
+// saveMaterializedView persists the materialized view table changes of the
+// provided view collection (it is expected to be called in a transaction).
+//
+// The table is recreated and fully refreshed only if its columns or
+// view query have changed. Otherwise only its indexes are synced.
+func saveMaterializedView(app App, newCollection, oldCollection *Collection, query string) error {
+	isUnchanged := oldCollection != nil &&
+		oldCollection.IsMaterializedView() &&
+		oldCollection.Name == newCollection.Name &&
+		oldCollection.ViewQuery == newCollection.ViewQuery &&
+		sameColumns(oldCollection.Fields, newCollection.Fields) &&
+		app.HasTable(newCollection.Name)
+
+	if isUnchanged {
+		if err := dropCollectionIndexes(app, oldCollection); err != nil {
+			return err
+		}
+
+		return createCollectionIndexes(app, newCollection)
+	}
+
+	if err := createMaterializedViewTable(app, newCollection); err != nil {
+		return err
+	}
+
+	return refreshMaterializedViewTable(app, newCollection, query)
+}
+
+func sameColumns(a, b FieldsList) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i].GetName() != b[i].GetName() || a[i].Type() != b[i].Type() {
+			return false
+		}
+	}
+
+	return true
+}
+
+var identifierRegex = regexp.MustCompile(`\w+`)
+
+// materializedViewDependsOn reports whether the view query of the provided
+// materialized view references the source collection (directly or through other views).
+//
+// The references are loosely detected by the collection names in the view query.
+func materializedViewDependsOn(view *Collection, source *Collection, collections []*Collection) bool {
+	visited := map[string]struct{}{}
+
+	var check func(c *Collection) bool
+	check = func(c *Collection) bool {
+		if _, ok := visited[c.Id]; ok {
+			return false
+		}
+		visited[c.Id] = struct{}{}
+
+		identifiers := map[string]struct{}{}
+		for _, word := range identifierRegex.FindAllString(c.ViewQuery, -1) {
+			identifiers[strings.ToLower(word)] = struct{}{}
+		}
+
+		if _, ok := identifiers[strings.ToLower(source.Name)]; ok {
+			return true
+		}
+
+		for _, other := range collections {
+			if !other.IsView() || other.Id == c.Id {
+				continue
+			}
+			if _, ok := identifiers[strings.ToLower(other.Name)]; ok && check(other) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return check(view)
+}
+
+func materializedViewCronJobId(collection *Collection) string {
+	return "__pbMaterializedView_" + collection.Id + "__"
+}
+
+func (app *BaseApp) registerMaterializedViewHooks() {
+	loadCronJob := func(collection *Collection) {
+		jobId := materializedViewCronJobId(collection)
+
+		if !collection.IsMaterializedView() || collection.Materialized.RefreshCron == "" {
+			app.Cron().Remove(jobId)
+			return
+		}
+
+		collectionId := collection.Id
+
+		err := app.Cron().Add(jobId, collection.Materialized.RefreshCron, func() {
+			// refetch to ensure that the latest collection state is used
+			latest, err := app.FindCachedCollectionByNameOrId(collectionId)
+			if err != nil {
+				return
+			}
+
+			if err := app.RefreshMaterializedView(latest); err != nil {
+				app.Logger().Error(
+					"[Materialized view cron] Failed to refresh materialized view",
+					slog.String("collection", latest.Name),
+					slog.String("error", err.Error()),
+				)
+			}
+		})
+		if err != nil {
+			app.Logger().Warn("Failed to register materialized view cron job", "collection", collection.Name, "error", err)
+		}
+	}
+
+	app.OnBootstrap().Bind(&hook.Handler[*BootstrapEvent]{
+		Func: func(e *BootstrapEvent) error {
+			if err := e.Next(); err != nil {
+				return err
+			}
+
+			views, err := e.App.FindAllCollections(CollectionTypeView)
+			if err != nil {
+				return err
+			}
+
+			for _, view := range views {
+				loadCronJob(view)
+			}
+
+			return nil
+		},
+		Priority: 999, // execute as latest as possible
+	})
+
+	app.OnCollectionAfterCreateSuccess().BindFunc(func(e *CollectionEvent) error {
+		loadCronJob(e.Collection)
+		return e.Next()
+	})
+
+	app.OnCollectionAfterUpdateSuccess().BindFunc(func(e *CollectionEvent) error {
+		loadCronJob(e.Collection)
+		return e.Next()
+	})
+
+	app.OnCollectionAfterDeleteSuccess().BindFunc(func(e *CollectionEvent) error {
+		app.Cron().Remove(materializedViewCronJobId(e.Collection))
+		return e.Next()
+	})
+
+	// refresh on source records change
+	// (always debounced and outside of the record request)
+	// ---
+	var timersMu sync.Mutex
+	var terminated bool
+	timers := map[string]*time.Timer{}
+
+	var schedule func(view *Collection)
+
+	refresh := func(collectionId string) {
+		view, err := app.FindCachedCollectionByNameOrId(collectionId)
+		if err != nil || !view.IsMaterializedView() {
+			return
+		}
+
+		if err := app.RefreshMaterializedView(view); err != nil {
+			app.Logger().Error(
+				"Failed to refresh materialized view on source change",
+				slog.String("collection", view.Name),
+				slog.String("error", err.Error()),
+			)
+			return
+		}
+
+		// the materialized view table is updated with raw queries
+		// so the dependent views need to be scheduled explicitly
+		collections, _ := app.Store().Get(StoreKeyCachedCollections).([]*Collection)
+		for _, other := range collections {
+			if other.Id == view.Id ||
+				!other.IsMaterializedView() ||
+				!other.Materialized.RefreshOnChange ||
+				!materializedViewDependsOn(other, view, collections) {
+				continue
+			}
+
+			schedule(other)
+		}
+	}
+
+	schedule = func(view *Collection) {
+		delay := view.Materialized.RefreshOnChangeDelay
+		if delay <= 0 {
+			delay = DefaultMaterializedViewRefreshOnChangeDelay
+		}
+
+		timersMu.Lock()
+		defer timersMu.Unlock()
+
+		if terminated || timers[view.Id] != nil {
+			return
+		}
+
+		viewId := view.Id
+		timers[viewId] = time.AfterFunc(time.Duration(delay)*time.Millisecond, func() {
+			timersMu.Lock()
+			delete(timers, viewId)
+			timersMu.Unlock()
+
+			refresh(viewId)
+		})
+	}
+
+	app.OnTerminate().BindFunc(func(e *TerminateEvent) error {
+		timersMu.Lock()
+		terminated = true
+		for id, timer := range timers {
+			timer.Stop()
+			delete(timers, id)
+		}
+		timersMu.Unlock()
+
+		return e.Next()
+	})
+
+	changeHandler := func(e *RecordEvent) error {
+		if err := e.Next(); err != nil {
+			return err
+		}
+
+		source := e.Record.Collection()
+		if source.IsView() {
+			return nil
+		}
+
+		collections, _ := e.App.Store().Get(StoreKeyCachedCollections).([]*Collection)
+
+		for _, view := range collections {
+			if !view.IsMaterializedView() ||
+				!view.Materialized.RefreshOnChange ||
+				!materializedViewDependsOn(view, source, collections) {
+				continue
+			}
+
+			schedule(view)
+		}
+
+		return nil
+	}
+
+	app.OnRecordAfterCreateSuccess().Bind(&hook.Handler[*RecordEvent]{
+		Func:     changeHandler,
+		Priority: 99,
+	})
+
+	app.OnRecordAfterUpdateSuccess().Bind(&hook.Handler[*RecordEvent]{
+		Func:     changeHandler,
+		Priority: 99,
+	})
+
+	app.OnRecordAfterDeleteSuccess().Bind(&hook.Handler[*RecordEvent]{
+		Func:     changeHandler,
+		Priority: 99,
+	})
+}
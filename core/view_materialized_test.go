@@ -0,0 +1,339 @@
+package core_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func setupMaterializedViewTest(t testing.TB, app core.App, config *core.MaterializedViewConfig) (*core.Collection, *core.Collection) {
+	source := core.NewBaseCollection("mv_source")
+	source.Fields.Add(
+		&core.TextField{Name: "category"},
+		&core.NumberField{Name: "amount"},
+	)
+	if err := app.Save(source); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, data := range []map[string]any{
+		{"category": "a", "amount": 1},
+		{"category": "a", "amount": 2},
+		{"category": "b", "amount": 5},
+	} {
+		record := core.NewRecord(source)
+		record.Load(data)
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	view := core.NewViewCollection("mv_totals")
+	view.ViewQuery = "SELECT category as id, total(amount) as total, count(*) as items FROM mv_source GROUP BY category"
+	view.Materialized = config
+	view.AddIndex("idx_mv_totals_total", false, "total", "")
+	if err := app.Save(view); err != nil {
+		t.Fatal(err)
+	}
+
+	return source, view
+}
+
+func materializedTotals(t testing.TB, app core.App, view *core.Collection) map[string]int {
+	records, err := app.FindAllRecords(view)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := make(map[string]int, len(records))
+	for _, r := range records {
+		result[r.Id] = r.GetInt("total")
+	}
+
+	return result
+}
+
+func waitMaterializedTotal(t testing.TB, app core.App, view *core.Collection, id string, expected int) {
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		totals := materializedTotals(t, app, view)
+		if totals[id] == expected {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %s total %d for %q, got %v", view.Name, expected, id, totals)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func sqliteObjectType(app core.App, name string) string {
+	var typ string
+	app.DB().Select("type").From("sqlite_schema").AndWhere(dbx.HashExp{"name": name}).Row(&typ)
+	return typ
+}
+
+func TestMaterializedViewSave(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	_, view := setupMaterializedViewTest(t, app, &core.MaterializedViewConfig{Enabled: true})
+
+	if !view.IsMaterializedView() {
+		t.Fatal("Expected IsMaterializedView to be true")
+	}
+
+	if typ := sqliteObjectType(app, "mv_totals"); typ != "table" {
+		t.Fatalf("Expected mv_totals to be a table, got %q", typ)
+	}
+
+	if typ := sqliteObjectType(app, "idx_mv_totals_total"); typ != "index" {
+		t.Fatalf("Expected the idx_mv_totals_total index to be created, got %q", typ)
+	}
+
+	totals := materializedTotals(t, app, view)
+	if len(totals) != 2 || totals["a"] != 3 || totals["b"] != 5 {
+		t.Fatalf("Unexpected materialized totals %v", totals)
+	}
+
+	info, err := app.FindMaterializedViewInfo(view)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Refreshed.IsZero() || info.TotalRows != 2 || info.CollectionRef != view.Id {
+		t.Fatalf("Unexpected materialized view info %+v", info)
+	}
+
+	// filter and sort on the materialized table
+	records, err := app.FindRecordsByFilter(view, "total > 2", "-total", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].Id != "b" || records[1].Id != "a" {
+		t.Fatalf("Unexpected filtered records %v", records)
+	}
+
+	// rename
+	view.Name = "mv_totals_renamed"
+	if err := app.Save(view); err != nil {
+		t.Fatal(err)
+	}
+	if typ := sqliteObjectType(app, "mv_totals"); typ != "" {
+		t.Fatalf("Expected the old mv_totals table to be deleted, got %q", typ)
+	}
+	if typ := sqliteObjectType(app, "mv_totals_renamed"); typ != "table" {
+		t.Fatalf("Expected mv_totals_renamed to be a table, got %q", typ)
+	}
+
+	// disable materialization
+	view.Materialized.Enabled = false
+	view.Indexes = nil
+	if err := app.Save(view); err != nil {
+		t.Fatal(err)
+	}
+	if typ := sqliteObjectType(app, "mv_totals_renamed"); typ != "view" {
+		t.Fatalf("Expected mv_totals_renamed to be a view, got %q", typ)
+	}
+	if _, err := app.FindMaterializedViewInfo(view); err == nil {
+		t.Fatal("Expected FindMaterializedViewInfo error for non-materialized view")
+	}
+
+	// enable again and delete
+	view.Materialized.Enabled = true
+	if err := app.Save(view); err != nil {
+		t.Fatal(err)
+	}
+	if typ := sqliteObjectType(app, "mv_totals_renamed"); typ != "table" {
+		t.Fatalf("Expected mv_totals_renamed to be a table, got %q", typ)
+	}
+
+	if err := app.Delete(view); err != nil {
+		t.Fatal(err)
+	}
+	if typ := sqliteObjectType(app, "mv_totals_renamed"); typ != "" {
+		t.Fatalf("Expected the mv_totals_renamed table to be deleted, got %q", typ)
+	}
+
+	var total int
+	app.DB().Select("count(*)").From(core.MaterializedViewsTable).Row(&total)
+	if total != 0 {
+		t.Fatalf("Expected the materialized view info to be deleted, got %d rows", total)
+	}
+}
+
+func TestMaterializedViewIndexesValidation(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	view := core.NewViewCollection("mv_invalid")
+	view.ViewQuery = "SELECT id, title FROM demo2"
+	view.AddIndex("idx_mv_invalid", false, "title", "")
+
+	if err := app.Save(view); err == nil {
+		t.Fatal("Expected non-materialized view with indexes to fail")
+	}
+
+	view.Materialized = &core.MaterializedViewConfig{Enabled: true, RefreshCron: "invalid"}
+	if err := app.Save(view); err == nil {
+		t.Fatal("Expected invalid refresh cron to fail")
+	}
+
+	view.Materialized.RefreshCron = "*/5 * * * *"
+	if err := app.Save(view); err != nil {
+		t.Fatal(err)
+	}
+
+	jobs := app.Cron().Jobs()
+	var found bool
+	for _, j := range jobs {
+		if j.Id() == "__pbMaterializedView_"+view.Id+"__" {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Fatal("Expected the materialized view cron job to be registered")
+	}
+
+	if err := app.Delete(view); err != nil {
+		t.Fatal(err)
+	}
+	for _, j := range app.Cron().Jobs() {
+		if j.Id() == "__pbMaterializedView_"+view.Id+"__" {
+			t.Fatal("Expected the materialized view cron job to be removed")
+		}
+	}
+}
+
+func TestRefreshMaterializedView(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	source, view := setupMaterializedViewTest(t, app, &core.MaterializedViewConfig{Enabled: true})
+
+	if err := app.RefreshMaterializedView(source); err == nil {
+		t.Fatal("Expected error for non-materialized view collection")
+	}
+
+	record := core.NewRecord(source)
+	record.Set("category", "c")
+	record.Set("amount", 10)
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	// no refresh on change
+	totals := materializedTotals(t, app, view)
+	if len(totals) != 2 {
+		t.Fatalf("Expected the materialized view to not be refreshed, got %v", totals)
+	}
+
+	before, err := app.FindMaterializedViewInfo(view)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(2 * time.Millisecond)
+
+	if err := app.RefreshMaterializedView(view); err != nil {
+		t.Fatal(err)
+	}
+
+	totals = materializedTotals(t, app, view)
+	if len(totals) != 3 || totals["c"] != 10 {
+		t.Fatalf("Expected the materialized view to be refreshed, got %v", totals)
+	}
+
+	after, err := app.FindMaterializedViewInfo(view)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !after.Refreshed.After(before.Refreshed) || after.TotalRows != 3 {
+		t.Fatalf("Expected the refreshed info to be updated, got %+v (before %+v)", after, before)
+	}
+
+	// stale rows delete
+	if err := app.Delete(record); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.RefreshMaterializedView(view); err != nil {
+		t.Fatal(err)
+	}
+	totals = materializedTotals(t, app, view)
+	if len(totals) != 2 {
+		t.Fatalf("Expected the stale row to be deleted, got %v", totals)
+	}
+}
+
+func TestMaterializedViewRefreshOnChange(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	source, view := setupMaterializedViewTest(t, app, &core.MaterializedViewConfig{Enabled: true, RefreshOnChange: true})
+
+	// dependent plain view of a materialized view
+	nested := core.NewViewCollection("mv_nested")
+	nested.ViewQuery = "SELECT id, total FROM mv_totals"
+	nested.Materialized = &core.MaterializedViewConfig{Enabled: true, RefreshOnChange: true, RefreshOnChangeDelay: 10}
+	if err := app.Save(nested); err != nil {
+		t.Fatal(err)
+	}
+
+	record := core.NewRecord(source)
+	record.Set("category", "b")
+	record.Set("amount", 10)
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	// debounced (aka. not refreshed as part of the record save)
+	totals := materializedTotals(t, app, view)
+	if totals["b"] != 5 {
+		t.Fatalf("Expected the materialized view to not be refreshed yet, got %v", totals)
+	}
+	nestedTotals := materializedTotals(t, app, nested)
+	if nestedTotals["b"] != 5 {
+		t.Fatalf("Expected the nested materialized view to not be refreshed yet, got %v", nestedTotals)
+	}
+
+	// default delay
+	waitMaterializedTotal(t, app, view, "b", 15)
+
+	// refreshed after the parent materialized view refresh
+	waitMaterializedTotal(t, app, nested, "b", 15)
+
+	// unrelated collection change
+	before, err := app.FindMaterializedViewInfo(view)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	demo2Record, err := app.FindRecordById("demo2", "0yxhwia2amd8gec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := app.Save(demo2Record); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep((core.DefaultMaterializedViewRefreshOnChangeDelay + 100) * time.Millisecond)
+
+	after, err := app.FindMaterializedViewInfo(view)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !after.Refreshed.Equal(before.Refreshed) {
+		t.Fatal("Expected the materialized view to not be refreshed on unrelated changes")
+	}
+}
@@ -887,6 +887,42 @@ func TestRecordUpsertSubmitSuccess(t *testing.T) {
 	testFilesCount(t, testApp, record, 2) // the file + attrs
 }
 
+func TestRecordUpsertComputedField(t *testing.T) {
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	col := core.NewBaseCollection("test_computed")
+	col.Fields.Add(
+		&core.TextField{Name: "title"},
+		&core.ComputedField{Name: "title_upper", Expression: "upper(title)"},
+	)
+	if err := testApp.Save(col); err != nil {
+		t.Fatal(err)
+	}
+
+	record := core.NewRecord(col)
+
+	form := forms.NewRecordUpsert(testApp, record)
+	form.GrantSuperuserAccess()
+	form.Load(map[string]any{
+		"title":       "test",
+		"title_upper": "custom",
+	})
+
+	if err := form.Submit(); err != nil {
+		t.Fatalf("Expected Submit success, got error: %v", err)
+	}
+
+	record, err := testApp.FindRecordById(col, record.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v := record.GetString("title_upper"); v != "TEST" {
+		t.Fatalf("Expected the computed field value to be read-only %q, got %q", "TEST", v)
+	}
+}
+
 func TestRecordUpsertPasswordsSync(t *testing.T) {
 	testApp, _ := tests.NewTestApp()
 	defer testApp.Cleanup()
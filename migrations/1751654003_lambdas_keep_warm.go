@@ -0,0 +1,39 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func init() {
+	Register(func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId(core.CollectionNameLambdaFunctions)
+		if err != nil {
+			return err
+		}
+
+		// Add the keepWarm field if it doesn't exist
+		if collection.Fields.GetByName("keepWarm") == nil {
+			collection.Fields.Add(&core.BoolField{
+				Name:   "keepWarm",
+				System: true,
+			})
+
+			return app.Save(collection)
+		}
+
+		return nil
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId(core.CollectionNameLambdaFunctions)
+		if err != nil {
+			return nil // Collection doesn't exist, nothing to do
+		}
+
+		field := collection.Fields.GetByName("keepWarm")
+		if field != nil {
+			collection.Fields.RemoveById(field.GetId())
+			return app.Save(collection)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,60 @@
+package migrations
+
+import (
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func init() {
+	Register(func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId(core.CollectionNameLambdaFunctions)
+		if err != nil {
+			return err
+		}
+
+		// Add the deployment environment field if it doesn't exist
+		if collection.Fields.GetByName("environment") == nil {
+			collection.Fields.Add(&core.TextField{
+				Name:   "environment",
+				System: true,
+				Max:    100,
+			})
+		}
+
+		// Add the per environment overrides field if it doesn't exist
+		if collection.Fields.GetByName("environments") == nil {
+			collection.Fields.Add(&core.JSONField{
+				Name:   "environments",
+				System: true,
+			})
+		}
+
+		if err := app.Save(collection); err != nil {
+			return err
+		}
+
+		// Backfill the deployment environment of the existing functions
+		// so that their background triggers continue to run only in
+		// the environment where they were originally created
+		_, err = app.DB().Update(
+			core.CollectionNameLambdaFunctions,
+			dbx.Params{"environment": app.Environment()},
+			dbx.NewExp("[[environment]] = '' OR [[environment]] IS NULL"),
+		).Execute()
+
+		return err
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId(core.CollectionNameLambdaFunctions)
+		if err != nil {
+			return nil // Collection doesn't exist, nothing to do
+		}
+
+		for _, name := range []string{"environment", "environments"} {
+			if field := collection.Fields.GetByName(name); field != nil {
+				collection.Fields.RemoveById(field.GetId())
+			}
+		}
+
+		return app.Save(collection)
+	})
+}
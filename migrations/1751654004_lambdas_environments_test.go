@@ -0,0 +1,70 @@
+package migrations_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestLambdasEnvironmentsMigrationBackfill(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.FindCollectionByNameOrId(core.CollectionNameLambdaFunctions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// simulate functions created before the environment field was added
+	existing := core.NewRecord(collection)
+	existing.Set("name", "existing")
+	existing.Set("code", "export default () => {}")
+	if err := app.SaveNoValidate(existing); err != nil {
+		t.Fatal(err)
+	}
+
+	staging := core.NewRecord(collection)
+	staging.Set("name", "staging")
+	staging.Set("code", "export default () => {}")
+	staging.Set("environment", "staging")
+	if err := app.SaveNoValidate(staging); err != nil {
+		t.Fatal(err)
+	}
+
+	var migration *core.Migration
+	for _, m := range core.AppMigrations.Items() {
+		if m.File == "1751654004_lambdas_environments.go" {
+			migration = m
+			break
+		}
+	}
+	if migration == nil {
+		t.Fatal("Missing lambdas environments migration")
+	}
+
+	if err := migration.Up(app); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		id       string
+		expected string
+	}{
+		{existing.Id, app.Environment()},
+		{staging.Id, "staging"},
+	}
+
+	for _, s := range scenarios {
+		record, err := app.FindRecordById(collection, s.id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if v := record.GetString("environment"); v != s.expected {
+			t.Errorf("[%s] Expected environment %q, got %q", s.id, s.expected, v)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func init() {
+	Register(func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId(core.CollectionNameLambdaLogs)
+		if err != nil {
+			return err
+		}
+
+		// Add the request_id (trace id) field if it doesn't exist
+		if collection.Fields.GetByName("request_id") == nil {
+			collection.Fields.Add(&core.TextField{
+				Name:   "request_id",
+				System: true,
+				Max:    100,
+			})
+
+			collection.AddIndex("idx_lambda_logs_request_id", false, "request_id", "")
+
+			return app.Save(collection)
+		}
+
+		return nil
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId(core.CollectionNameLambdaLogs)
+		if err != nil {
+			return nil // Collection doesn't exist, nothing to do
+		}
+
+		field := collection.Fields.GetByName("request_id")
+		if field != nil {
+			collection.RemoveIndex("idx_lambda_logs_request_id")
+			collection.Fields.RemoveById(field.GetId())
+			return app.Save(collection)
+		}
+
+		return nil
+	})
+}
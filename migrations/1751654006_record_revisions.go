@@ -0,0 +1,75 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func init() {
+	Register(func(app core.App) error {
+		// Create the record revisions collection (superusers only)
+		collection := core.NewBaseCollection(core.CollectionNameRecordRevisions)
+		collection.System = true
+
+		collection.Fields.Add(&core.TextField{
+			Name:     "collectionRef",
+			System:   true,
+			Required: true,
+		})
+		collection.Fields.Add(&core.TextField{
+			Name:     "recordRef",
+			System:   true,
+			Required: true,
+		})
+		collection.Fields.Add(&core.SelectField{
+			Name:      "action",
+			System:    true,
+			Required:  true,
+			MaxSelect: 1,
+			Values: []string{
+				core.RecordRevisionActionCreate,
+				core.RecordRevisionActionUpdate,
+				core.RecordRevisionActionDelete,
+			},
+		})
+		// the full record state after the change (including the password hash)
+		collection.Fields.Add(&core.JSONField{
+			Name:   "data",
+			System: true,
+			Hidden: true,
+		})
+		collection.Fields.Add(&core.JSONField{
+			Name:   "diff",
+			System: true,
+		})
+		collection.Fields.Add(&core.TextField{
+			Name:   "actorCollectionRef",
+			System: true,
+		})
+		collection.Fields.Add(&core.TextField{
+			Name:   "actorRef",
+			System: true,
+		})
+		collection.Fields.Add(&core.TextField{
+			Name:   "requestId",
+			System: true,
+			Max:    100,
+		})
+		collection.Fields.Add(&core.AutodateField{
+			Name:     "created",
+			System:   true,
+			OnCreate: true,
+		})
+
+		collection.AddIndex("idx_recordRevisions_collectionRef_recordRef", false, "collectionRef, recordRef, created", "")
+		collection.AddIndex("idx_recordRevisions_created", false, "created", "")
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId(core.CollectionNameRecordRevisions)
+		if err != nil {
+			return nil // Collection doesn't exist, nothing to do
+		}
+
+		return app.Delete(collection)
+	})
+}
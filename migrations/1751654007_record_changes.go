@@ -0,0 +1,50 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func init() {
+	Register(func(app core.App) error {
+		// note: the change log is a plain table (instead of a collection)
+		// to avoid triggering the record hooks for every logged change and
+		// to guarantee a never reused monotonically increasing cursor
+		_, err := app.DB().NewQuery(`
+			CREATE TABLE {{` + core.RecordChangesTable + `}} (
+				[[cursor]]         INTEGER PRIMARY KEY AUTOINCREMENT,
+				[[action]]         TEXT NOT NULL,
+				[[collectionRef]]  TEXT NOT NULL,
+				[[collectionName]] TEXT NOT NULL,
+				[[recordRef]]      TEXT NOT NULL,
+				[[data]]           JSON DEFAULT NULL,
+				[[created]]        TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL
+			);
+
+			CREATE INDEX IF NOT EXISTS idx__recordChanges_collectionRef on {{` + core.RecordChangesTable + `}} ([[collectionRef]], [[cursor]]);
+			CREATE INDEX IF NOT EXISTS idx__recordChanges_created on {{` + core.RecordChangesTable + `}} ([[created]]);
+
+			CREATE TABLE {{` + core.RecordChangesPrunedTable + `}} (
+				[[collectionRef]] TEXT PRIMARY KEY NOT NULL,
+				[[cursor]]        INTEGER DEFAULT 0 NOT NULL
+			);
+
+			CREATE TABLE {{` + core.RecordChangeCheckpointsTable + `}} (
+				[[authRef]]  TEXT NOT NULL,
+				[[consumer]] TEXT NOT NULL,
+				[[cursor]]   INTEGER DEFAULT 0 NOT NULL,
+				[[updated]]  TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL,
+				PRIMARY KEY ([[authRef]], [[consumer]])
+			);
+		`).Execute()
+
+		return err
+	}, func(app core.App) error {
+		_, err := app.DB().NewQuery(`
+			DROP TABLE IF EXISTS {{` + core.RecordChangeCheckpointsTable + `}};
+			DROP TABLE IF EXISTS {{` + core.RecordChangesPrunedTable + `}};
+			DROP TABLE IF EXISTS {{` + core.RecordChangesTable + `}};
+		`).Execute()
+
+		return err
+	})
+}
@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func init() {
+	Register(func(app core.App) error {
+		_, err := app.DB().NewQuery(`
+			CREATE TABLE {{` + core.RecordImportsTable + `}} (
+				[[id]]            TEXT PRIMARY KEY NOT NULL,
+				[[collectionRef]] TEXT NOT NULL,
+				[[format]]        TEXT NOT NULL,
+				[[totalRows]]     INTEGER DEFAULT 0 NOT NULL,
+				[[createdRows]]   INTEGER DEFAULT 0 NOT NULL,
+				[[updatedRows]]   INTEGER DEFAULT 0 NOT NULL,
+				[[failedRows]]    INTEGER DEFAULT 0 NOT NULL,
+				[[errors]]        JSON DEFAULT '[]' NOT NULL,
+				[[created]]       TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL
+			);
+
+			CREATE INDEX IF NOT EXISTS idx__recordImports_created on {{` + core.RecordImportsTable + `}} ([[created]]);
+		`).Execute()
+
+		return err
+	}, func(app core.App) error {
+		_, err := app.DB().DropTable(core.RecordImportsTable).Execute()
+
+		return err
+	})
+}
@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func init() {
+	Register(func(app core.App) error {
+		_, err := app.DB().NewQuery(`
+			CREATE TABLE {{` + core.MaterializedViewsTable + `}} (
+				[[collectionRef]] TEXT PRIMARY KEY NOT NULL,
+				[[refreshed]]     TEXT DEFAULT "" NOT NULL,
+				[[totalRows]]     INTEGER DEFAULT 0 NOT NULL
+			);
+		`).Execute()
+
+		return err
+	}, func(app core.App) error {
+		_, err := app.DB().DropTable(core.MaterializedViewsTable).Execute()
+
+		return err
+	})
+}
@@ -537,6 +537,10 @@ func baseBinds(vm *goja.Runtime) {
 		instance := &core.GeoPointField{}
 		return structConstructorUnmarshal(vm, call, instance)
 	})
+	vm.Set("DecimalField", func(call goja.ConstructorCall) *goja.Object {
+		instance := &core.DecimalField{}
+		return structConstructorUnmarshal(vm, call, instance)
+	})
 	// ---
 
 	vm.Set("MailerMessage", func(call goja.ConstructorCall) *goja.Object {
@@ -610,6 +614,21 @@ func baseBinds(vm *goja.Runtime) {
 		return structConstructor(vm, call, instance)
 	})
 
+	vm.Set("Decimal", func(call goja.ConstructorCall) *goja.Object {
+		var instance types.Decimal
+
+		if raw := call.Argument(0).Export(); raw != nil {
+			if err := instance.Scan(raw); err != nil {
+				panic(vm.NewGoError(err))
+			}
+		}
+
+		instanceValue := vm.ToValue(instance).(*goja.Object)
+		instanceValue.SetPrototype(call.This.Prototype())
+
+		return instanceValue
+	})
+
 	vm.Set("ValidationError", func(call goja.ConstructorCall) *goja.Object {
 		code, _ := call.Argument(0).Export().(string)
 		message, _ := call.Argument(1).Export().(string)
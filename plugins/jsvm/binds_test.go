@@ -46,7 +46,7 @@ func TestBaseBindsCount(t *testing.T) {
 	vm := goja.New()
 	baseBinds(vm)
 
-	testBindsCount(vm, "this", 35, t)
+	testBindsCount(vm, "this", 37, t)
 }
 
 func TestBaseBindsSleep(t *testing.T) {
@@ -444,6 +444,10 @@ func TestBaseBindsNamedFields(t *testing.T) {
 			"new GeoPointField({name: 'test'})",
 			isType[*core.GeoPointField],
 		},
+		{
+			"new DecimalField({name: 'test'})",
+			isType[*core.DecimalField],
+		},
 	}
 
 	for _, s := range scenarios {
@@ -649,6 +653,43 @@ func TestBaseBindsDateTime(t *testing.T) {
 	}
 }
 
+func TestBaseBindsDecimal(t *testing.T) {
+	vm := goja.New()
+	baseBinds(vm)
+
+	_, err := vm.RunString(`
+		const scenarios = [
+			{value: new Decimal(), expected: "0"},
+			{value: new Decimal(""), expected: "0"},
+			{value: new Decimal("12.50"), expected: "12.50"},
+			{value: new Decimal(0.1), expected: "0.1"},
+			{value: new Decimal("0.10").add(new Decimal("0.2")), expected: "0.30"},
+			{value: new Decimal("1").sub(new Decimal("0.01")), expected: "0.99"},
+			{value: new Decimal("1.005").round(2), expected: "1.01"},
+		];
+
+		for (let i = 0; i < scenarios.length; i++) {
+			const s = scenarios[i];
+			if (s.value.string() != s.expected) {
+				throw new Error('(' + i + ') Expected ' + s.expected + ', got ' + s.value.string());
+			}
+		}
+
+		let invalidErr;
+		try {
+			new Decimal("invalid");
+		} catch (err) {
+			invalidErr = err;
+		}
+		if (!invalidErr) {
+			throw new Error("Expected invalid decimal error");
+		}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestBaseBindsValidationError(t *testing.T) {
 	vm := goja.New()
 	baseBinds(vm)
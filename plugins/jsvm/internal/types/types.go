@@ -524,6 +524,16 @@ declare class GeoPointField implements core.GeoPointField {
   constructor(data?: Partial<core.GeoPointField>)
 }
 
+interface DecimalField extends core.DecimalField{} // merge
+/**
+ * {@inheritDoc core.DecimalField}
+ *
+ * @group PocketBase
+ */
+declare class DecimalField implements core.DecimalField {
+  constructor(data?: Partial<core.DecimalField>)
+}
+
 interface MailerMessage extends mailer.Message{} // merge
 /**
  * MailerMessage defines a single email message.
@@ -673,6 +683,24 @@ declare class DateTime implements types.DateTime {
   constructor(date?: string, defaultParseInLocation?: string)
 }
 
+interface Decimal extends types.Decimal{} // merge
+/**
+ * Decimal defines a single exact fixed-point decimal number.
+ *
+ * Example:
+ *
+ * ` + "```" + `js
+ * const price = new Decimal("0.10")
+ *
+ * const total = price.add(new Decimal("0.20")) // "0.30"
+ * ` + "```" + `
+ *
+ * @group PocketBase
+ */
+declare class Decimal implements types.Decimal {
+  constructor(value?: string|number)
+}
+
 interface ValidationError extends ozzo_validation.Error{} // merge
 /**
  * ValidationError defines a single formatted data validation error,
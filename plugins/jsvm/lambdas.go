@@ -14,9 +14,11 @@ import (
 	"github.com/dop251/goja_nodejs/console"
 	"github.com/dop251/goja_nodejs/process"
 	"github.com/dop251/goja_nodejs/require"
+	"github.com/pocketbase/pocketbase/apis"
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/tools/cron"
 	"github.com/pocketbase/pocketbase/tools/template"
+	"github.com/spf13/cast"
 )
 
 // LambdaFunctionPluginConfig defines the configuration for the lambda function plugin
@@ -25,6 +27,24 @@ type LambdaFunctionPluginConfig struct {
 	// for lambda function execution
 	PoolSize int
 
+	// MaxPoolSize specifies up to how many goja.Runtime instances
+	// the pool could grow under load.
+	//
+	// If not set or less than PoolSize it fallbacks to 4*PoolSize.
+	MaxPoolSize int
+
+	// PoolIdleTimeout specifies for how long the runtimes above PoolSize
+	// could stay idle before being released from the pool.
+	//
+	// If not set it fallbacks to 5 minutes.
+	PoolIdleTimeout time.Duration
+
+	// MetricsWindowSize specifies the number of the most recent executions
+	// per function used for calculating the latency percentiles.
+	//
+	// If not set it fallbacks to 200.
+	MetricsWindowSize int
+
 	// MaxExecutionTime specifies the maximum execution time for lambda functions
 	MaxExecutionTime time.Duration
 
@@ -39,21 +59,32 @@ type LambdaFunctionPluginConfig struct {
 type LambdaFunctionPlugin struct {
 	app           core.App
 	config        LambdaFunctionPluginConfig
-	executors     *vmsPool
+	executors     *adaptivePool
+	programs      *lambdaPrograms
+	warmStates    *lambdaWarmStates
+	metrics       *lambdaMetrics
 	scheduler     *cron.Cron
 	httpRoutes    sync.Map // map[string]*LambdaFunctionHTTPRoute
 	dbTriggers    sync.Map // map[string][]*LambdaFunctionDBTrigger
 	cronJobs      sync.Map // map[string]*LambdaFunctionCronJob
+	realtimeTriggers sync.Map // map[string]*LambdaFunctionRealtimeTrigger
 	templateRegistry *template.Registry
 	requireRegistry  *require.Registry
 }
 
 // LambdaFunctionHTTPRoute represents an HTTP route for an lambda function
 type LambdaFunctionHTTPRoute struct {
-	FunctionID string
-	Method     string
-	Path       string
-	Handler    func(*core.RequestEvent) error
+	FunctionID   string
+	FunctionName string
+	Description  string
+	Method       string
+	Path         string
+	Handler      func(*core.RequestEvent) error
+
+	// optional OpenAPI metadata
+	Summary        string
+	RequestSchema  map[string]any
+	ResponseSchema map[string]any
 }
 
 // LambdaFunctionDBTrigger represents a database trigger for an lambda function
@@ -72,14 +103,16 @@ type LambdaFunctionCronJob struct {
 
 // LambdaFunctionExecutionContext provides context for lambda function execution
 type LambdaFunctionExecutionContext struct {
-	FunctionID   string
-	TriggerType  string
-	Request      *http.Request
-	Response     http.ResponseWriter
-	Record       interface{}
-	OldRecord    interface{}
-	Environment  map[string]string
-	StartTime    time.Time
+	FunctionID      string
+	TriggerType     string
+	Request         *http.Request
+	Response        http.ResponseWriter
+	Record          interface{}
+	OldRecord       interface{}
+	RealtimeMessage *LambdaFunctionRealtimeMessage
+	RequestID       string // trace id shared by all executions initiated by the same action
+	Environment     map[string]string
+	StartTime       time.Time
 }
 
 // LambdaFunctionExecutionResult represents the result of lambda function execution
@@ -89,6 +122,7 @@ type LambdaFunctionExecutionResult struct {
 	Error     string
 	Duration  time.Duration
 	Memory    int64
+	RequestID string
 }
 
 // RegisterLambdaFunctionPlugin registers the lambda function plugin with the app
@@ -99,6 +133,12 @@ func RegisterLambdaFunctionPlugin(app core.App, config LambdaFunctionPluginConfi
 	if config.MaxMemory == 0 {
 		config.MaxMemory = 128 * 1024 * 1024 // 128MB
 	}
+	if config.MaxPoolSize < config.PoolSize {
+		config.MaxPoolSize = 4 * config.PoolSize
+	}
+	if config.PoolIdleTimeout == 0 {
+		config.PoolIdleTimeout = 5 * time.Minute
+	}
 
 	plugin := &LambdaFunctionPlugin{
 		app:              app,
@@ -106,10 +146,13 @@ func RegisterLambdaFunctionPlugin(app core.App, config LambdaFunctionPluginConfi
 		scheduler:        cron.New(),
 		templateRegistry: template.NewRegistry(),
 		requireRegistry:  new(require.Registry),
+		programs:         newLambdaPrograms(),
+		warmStates:       newLambdaWarmStates(),
+		metrics:          newLambdaMetrics(config.MetricsWindowSize),
 	}
 
 	// Initialize VM pool
-	plugin.executors = newPool(config.PoolSize, plugin.createVM)
+	plugin.executors = newAdaptivePool(config.PoolSize, config.MaxPoolSize, config.PoolIdleTimeout, plugin.createVM)
 
 	// Register app lifecycle hooks
 	plugin.registerLifecycleHooks()
@@ -150,6 +193,10 @@ func (p *LambdaFunctionPlugin) createVM() *goja.Runtime {
 	// Add lambda function specific bindings
 	vm.Set("$app", p.app)
 	vm.Set("$template", p.templateRegistry)
+	vm.Set("$realtime", &lambdaRealtime{app: p.app})
+
+	// propagate the execution trace id to the outgoing requests
+	bindTracedHTTPClient(vm)
 
 	// Custom initialization
 	if p.config.OnInit != nil {
@@ -184,6 +231,11 @@ func (p *LambdaFunctionPlugin) registerLifecycleHooks() {
 
 	// Handle lambda function CRUD operations
 	p.app.OnRecordCreate("lambdas").BindFunc(func(e *core.RecordEvent) error {
+		// associate the function with the environment in which it was deployed
+		if e.Record.GetString("environment") == "" {
+			e.Record.Set("environment", e.App.Environment())
+		}
+
 		if err := e.Next(); err != nil {
 			return err
 		}
@@ -201,6 +253,7 @@ func (p *LambdaFunctionPlugin) registerLifecycleHooks() {
 		if err := e.Next(); err != nil {
 			return err
 		}
+		p.metrics.remove(e.Record.Id)
 		return p.handleFunctionDeleted(e.Record)
 	})
 }
@@ -215,7 +268,8 @@ func (p *LambdaFunctionPlugin) loadLambdaFunctions() error {
 		return nil
 	}
 
-	functions, err := p.app.FindRecordsByFilter("lambdas", "enabled = true", "", 0, 0)
+	// note: load all functions because the enabled state could be overwritten per environment
+	functions, err := p.app.FindRecordsByFilter("lambdas", "", "", 0, 0)
 	if err != nil {
 		return fmt.Errorf("failed to load lambda functions: %w", err)
 	}
@@ -232,7 +286,12 @@ func (p *LambdaFunctionPlugin) loadLambdaFunctions() error {
 
 // registerFunction registers triggers for a specific lambda function
 func (p *LambdaFunctionPlugin) registerFunction(function *core.Record) error {
-	if !function.GetBool("enabled") {
+	state, err := p.resolveEnvironment(function)
+	if err != nil {
+		return err
+	}
+
+	if !state.Enabled {
 		return nil
 	}
 
@@ -248,13 +307,40 @@ func (p *LambdaFunctionPlugin) registerFunction(function *core.Record) error {
 	if httpTriggers, ok := triggerConfig["http"].([]interface{}); ok {
 		for _, trigger := range httpTriggers {
 			if httpTrigger, ok := trigger.(map[string]interface{}); ok {
-				method := strings.ToUpper(httpTrigger["method"].(string))
-				path := httpTrigger["path"].(string)
-				p.registerHTTPTrigger(functionID, method, path)
+				config, err := parseHTTPTriggerConfig(httpTrigger)
+				if err != nil {
+					return fmt.Errorf("invalid HTTP trigger configuration: %w", err)
+				}
+				p.registerHTTPTrigger(function, config)
+			}
+		}
+	}
+
+	// Register realtime triggers
+	if realtimeTriggers, ok := triggerConfig["realtime"].([]interface{}); ok {
+		for _, trigger := range realtimeTriggers {
+			if realtimeTrigger, ok := trigger.(map[string]interface{}); ok {
+				config, err := parseRealtimeTriggerConfig(realtimeTrigger)
+				if err != nil {
+					return fmt.Errorf("invalid realtime trigger configuration: %w", err)
+				}
+				p.registerRealtimeTrigger(functionID, config)
 			}
 		}
 	}
 
+	// Cron and database triggers are disabled by default when the function
+	// was deployed in another environment (eg. cloned production database)
+	if !state.BackgroundTriggers {
+		p.app.Logger().Debug(
+			"Lambda function background triggers are disabled for the current environment",
+			"function", function.GetString("name"),
+			"environment", state.Name,
+			"deployedEnvironment", function.GetString("environment"),
+		)
+		return nil
+	}
+
 	// Register database triggers
 	if dbTriggers, ok := triggerConfig["database"].([]interface{}); ok {
 		for _, trigger := range dbTriggers {
@@ -267,7 +353,11 @@ func (p *LambdaFunctionPlugin) registerFunction(function *core.Record) error {
 	}
 
 	// Register cron triggers
-	if cronTriggers, ok := triggerConfig["cron"].([]interface{}); ok {
+	if state.CronTriggers != nil {
+		for _, cronTrigger := range state.CronTriggers {
+			p.registerCronTrigger(functionID, cronTrigger.Expression)
+		}
+	} else if cronTriggers, ok := triggerConfig["cron"].([]interface{}); ok {
 		for _, trigger := range cronTriggers {
 			if cronTrigger, ok := trigger.(map[string]interface{}); ok {
 				schedule := cronTrigger["schedule"].(string)
@@ -279,15 +369,60 @@ func (p *LambdaFunctionPlugin) registerFunction(function *core.Record) error {
 	return nil
 }
 
+// resolveEnvironment resolves the effective function configuration for the current app environment.
+func (p *LambdaFunctionPlugin) resolveEnvironment(function *core.Record) (*core.LambdaEnvironmentState, error) {
+	envVars := map[string]any{}
+	if raw := function.GetString("envVars"); raw != "" {
+		// invalid env vars are ignored
+		json.Unmarshal([]byte(raw), &envVars)
+	}
+
+	environments, err := core.ParseLambdaEnvironments([]byte(function.GetString("environments")))
+	if err != nil {
+		return nil, err
+	}
+
+	return core.ResolveLambdaEnvironment(
+		p.app.Environment(),
+		function.GetString("environment"),
+		function.GetBool("enabled"),
+		envVars,
+		environments,
+	), nil
+}
+
+// parseHTTPTriggerConfig converts a raw HTTP trigger map into [core.HTTPTriggerConfig].
+func parseHTTPTriggerConfig(raw map[string]interface{}) (core.HTTPTriggerConfig, error) {
+	config := core.HTTPTriggerConfig{}
+
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		return config, err
+	}
+
+	if err := json.Unmarshal(encoded, &config); err != nil {
+		return config, err
+	}
+
+	config.Method = strings.ToUpper(config.Method)
+
+	return config, nil
+}
+
 // registerHTTPTrigger registers an HTTP trigger for an lambda function
-func (p *LambdaFunctionPlugin) registerHTTPTrigger(functionID, method, path string) {
-	routeKey := fmt.Sprintf("%s:%s", method, path)
+func (p *LambdaFunctionPlugin) registerHTTPTrigger(function *core.Record, config core.HTTPTriggerConfig) {
+	routeKey := fmt.Sprintf("%s:%s", config.Method, config.Path)
 	route := &LambdaFunctionHTTPRoute{
-		FunctionID: functionID,
-		Method:     method,
-		Path:       path,
-		Handler:    p.createHTTPHandler(functionID),
+		FunctionID:     function.Id,
+		FunctionName:   function.GetString("name"),
+		Description:    function.GetString("description"),
+		Method:         config.Method,
+		Path:           config.Path,
+		Summary:        config.Summary,
+		RequestSchema:  config.RequestSchema,
+		ResponseSchema: config.ResponseSchema,
 	}
+	route.Handler = p.createHTTPHandler(route)
 	p.httpRoutes.Store(routeKey, route)
 }
 
@@ -323,6 +458,11 @@ func (p *LambdaFunctionPlugin) registerCronTrigger(functionID, schedule string)
 
 // registerHTTPRoutes registers HTTP routes with the PocketBase router
 func (p *LambdaFunctionPlugin) registerHTTPRoutes(e *core.ServeEvent) {
+	e.Router.GET("/api/lambdas/openapi.json", p.openAPIHandler)
+	e.Router.GET("/api/lambdas/metrics", p.metricsListHandler).Bind(apis.RequireSuperuserAuth())
+	e.Router.GET("/api/lambdas/{id}/metrics", p.metricsViewHandler).Bind(apis.RequireSuperuserAuth())
+	e.Router.POST("/api/realtime/publish", p.realtimePublishHandler)
+
 	p.httpRoutes.Range(func(key, value interface{}) bool {
 		route := value.(*LambdaFunctionHTTPRoute)
 		fullPath := "/api/functions" + route.Path
@@ -338,7 +478,7 @@ func (p *LambdaFunctionPlugin) registerDatabaseTriggers() {
 		if err := e.Next(); err != nil {
 			return err
 		}
-		return p.executeFunctionForDBEvent(e.Record, nil, "create")
+		return p.executeFunctionForDBEvent(e.Context, e.Record, nil, "create")
 	})
 
 	// Register for record updates
@@ -347,7 +487,7 @@ func (p *LambdaFunctionPlugin) registerDatabaseTriggers() {
 		if err := e.Next(); err != nil {
 			return err
 		}
-		return p.executeFunctionForDBEvent(e.Record, oldRecord, "update")
+		return p.executeFunctionForDBEvent(e.Context, e.Record, oldRecord, "update")
 	})
 
 	// Register for record deletion
@@ -355,21 +495,30 @@ func (p *LambdaFunctionPlugin) registerDatabaseTriggers() {
 		if err := e.Next(); err != nil {
 			return err
 		}
-		return p.executeFunctionForDBEvent(e.Record, nil, "delete")
+		return p.executeFunctionForDBEvent(e.Context, e.Record, nil, "delete")
 	})
 }
 
 // createHTTPHandler creates an HTTP handler for an lambda function
-func (p *LambdaFunctionPlugin) createHTTPHandler(functionID string) func(*core.RequestEvent) error {
+func (p *LambdaFunctionPlugin) createHTTPHandler(route *LambdaFunctionHTTPRoute) func(*core.RequestEvent) error {
 	return func(e *core.RequestEvent) error {
+		if len(route.RequestSchema) > 0 {
+			if err := validateRequestBody(e, route.RequestSchema); err != nil {
+				return err
+			}
+		}
+
 		ctx := &LambdaFunctionExecutionContext{
-			FunctionID:  functionID,
+			FunctionID:  route.FunctionID,
 			TriggerType: "http",
 			Request:     e.Request,
 			Response:    e.Response,
+			RequestID:   requestTraceId(e.Request),
 			StartTime:   time.Now(),
 		}
 
+		e.Response.Header().Set(core.RequestIdHeader, ctx.RequestID)
+
 		result := p.executeFunction(ctx)
 		
 		if !result.Success {
@@ -378,8 +527,9 @@ func (p *LambdaFunctionPlugin) createHTTPHandler(functionID string) func(*core.R
 
 		// If the function returned a response object, handle it
 		if response, ok := result.Output.(map[string]interface{}); ok {
-			if status, ok := response["status"].(float64); ok {
-				e.Response.WriteHeader(int(status))
+			status := http.StatusOK
+			if rawStatus, ok := response["status"]; ok {
+				status = cast.ToInt(rawStatus)
 			}
 			if headers, ok := response["headers"].(map[string]interface{}); ok {
 				for key, value := range headers {
@@ -388,9 +538,12 @@ func (p *LambdaFunctionPlugin) createHTTPHandler(functionID string) func(*core.R
 			}
 			if body, ok := response["body"]; ok {
 				if bodyStr, ok := body.(string); ok {
-					return e.String(http.StatusOK, bodyStr)
+					return e.String(status, bodyStr)
 				}
-				return e.JSON(http.StatusOK, body)
+				return e.JSON(status, body)
+			}
+			if _, ok := response["status"]; ok {
+				return e.NoContent(status)
 			}
 		}
 
@@ -399,9 +552,18 @@ func (p *LambdaFunctionPlugin) createHTTPHandler(functionID string) func(*core.R
 }
 
 // executeFunctionForDBEvent executes functions triggered by database events
-func (p *LambdaFunctionPlugin) executeFunctionForDBEvent(record, oldRecord *core.Record, event string) error {
+//
+// The trace id of the model change context (if any) is shared with the triggered executions.
+func (p *LambdaFunctionPlugin) executeFunctionForDBEvent(changeCtx context.Context, record, oldRecord *core.Record, event string) error {
 	collection := record.Collection().Name
+
+	// prevent execution loops caused by the functions logs
+	if collection == core.CollectionNameLambdaLogs {
+		return nil
+	}
+
 	key := fmt.Sprintf("%s:%s", collection, event)
+	requestId := core.TraceIdFromContext(changeCtx)
 
 	if triggers, ok := p.dbTriggers.Load(key); ok {
 		for _, trigger := range triggers.([]*LambdaFunctionDBTrigger) {
@@ -410,6 +572,7 @@ func (p *LambdaFunctionPlugin) executeFunctionForDBEvent(record, oldRecord *core
 				TriggerType: "database",
 				Record:      record,
 				OldRecord:   oldRecord,
+				RequestID:   requestId,
 				StartTime:   time.Now(),
 			}
 
@@ -446,57 +609,112 @@ func (p *LambdaFunctionPlugin) executeFunctionForCron(functionID string) {
 
 // executeFunction executes an lambda function with the given context
 func (p *LambdaFunctionPlugin) executeFunction(ctx *LambdaFunctionExecutionContext) *LambdaFunctionExecutionResult {
+	if ctx.RequestID == "" {
+		ctx.RequestID = core.NewTraceId()
+	}
+
 	// Load function from database
 	function, err := p.app.FindRecordById("lambdas", ctx.FunctionID)
 	if err != nil {
 		return &LambdaFunctionExecutionResult{
-			Success:  false,
-			Error:    fmt.Sprintf("Function not found: %v", err),
-			Duration: time.Since(ctx.StartTime),
+			Success:   false,
+			Error:     fmt.Sprintf("Function not found: %v", err),
+			Duration:  time.Since(ctx.StartTime),
+			RequestID: ctx.RequestID,
 		}
 	}
 
-	if !function.GetBool("enabled") {
+	state, err := p.resolveEnvironment(function)
+	if err != nil {
 		return &LambdaFunctionExecutionResult{
-			Success:  false,
-			Error:    "Function is disabled",
-			Duration: time.Since(ctx.StartTime),
+			Success:   false,
+			Error:     p.formatError(err),
+			Duration:  time.Since(ctx.StartTime),
+			RequestID: ctx.RequestID,
 		}
 	}
 
-	var result *LambdaFunctionExecutionResult
-
-	// Execute with VM from pool
-	p.executors.run(func(vm *goja.Runtime) error {
-		// Set execution context
-		p.setExecutionContext(vm, ctx, function)
+	if !state.Enabled {
+		return &LambdaFunctionExecutionResult{
+			Success:   false,
+			Error:     "Function is disabled",
+			Duration:  time.Since(ctx.StartTime),
+			RequestID: ctx.RequestID,
+		}
+	}
 
-		// Execute with timeout
-		execCtx, cancel := context.WithTimeout(context.Background(), p.config.MaxExecutionTime)
-		defer cancel()
+	ctx.Environment = make(map[string]string, len(state.EnvVars))
+	for k, v := range state.EnvVars {
+		ctx.Environment[k] = cast.ToString(v)
+	}
 
-		// Execute the function
-		output, err := p.executeWithContext(execCtx, vm, function.GetString("code"))
-		
-		result = &LambdaFunctionExecutionResult{
-			Success:  err == nil,
-			Output:   output,
-			Error:    p.formatError(err),
-			Duration: time.Since(ctx.StartTime),
+	program, compiled, err := p.programs.load(function)
+	if err != nil {
+		result := &LambdaFunctionExecutionResult{
+			Success:   false,
+			Error:     p.formatError(err),
+			Duration:  time.Since(ctx.StartTime),
+			RequestID: ctx.RequestID,
 		}
-		
-		return nil
+		p.metrics.record(function.Id, function.GetString("name"), lambdaMetricsSample{
+			duration: result.Duration,
+			failed:   true,
+		})
+		p.logExecution(ctx, function, result)
+		return result
+	}
+
+	// Execute with timeout
+	execCtx, cancel := context.WithTimeout(context.Background(), p.config.MaxExecutionTime)
+	defer cancel()
+
+	var output interface{}
+	var coldStart bool
+
+	if function.GetBool("keepWarm") {
+		output, coldStart, err = p.executeWarm(execCtx, ctx, function, program)
+	} else {
+		// Execute with VM from pool
+		p.executors.run(func(vm *goja.Runtime, isNew bool) error {
+			coldStart = isNew
+
+			// Set execution context
+			p.setExecutionContext(vm, ctx, function)
+
+			output, err = p.executeWithContext(execCtx, vm, func() (goja.Value, error) {
+				return vm.RunProgram(program.program)
+			})
+
+			return nil
+		})
+	}
+
+	result := &LambdaFunctionExecutionResult{
+		Success:   err == nil,
+		Output:    output,
+		Error:     p.formatError(err),
+		Duration:  time.Since(ctx.StartTime),
+		RequestID: ctx.RequestID,
+	}
+
+	p.metrics.record(function.Id, function.GetString("name"), lambdaMetricsSample{
+		duration:  result.Duration,
+		failed:    !result.Success,
+		coldStart: coldStart,
+		compiled:  compiled,
 	})
 
+	p.logExecution(ctx, function, result)
+
 	return result
 }
 
 // setExecutionContext sets the execution context in the VM
 func (p *LambdaFunctionPlugin) setExecutionContext(vm *goja.Runtime, ctx *LambdaFunctionExecutionContext, function *core.Record) {
 	// Set environment variables
-	env := make(map[string]string)
-	if envVars := function.GetString("env_vars"); envVars != "" {
-		json.Unmarshal([]byte(envVars), &env)
+	env := ctx.Environment
+	if env == nil {
+		env = map[string]string{}
 	}
 	vm.Set("$env", env)
 
@@ -505,8 +723,18 @@ func (p *LambdaFunctionPlugin) setExecutionContext(vm *goja.Runtime, ctx *Lambda
 		"type":       ctx.TriggerType,
 		"function":   function.GetString("name"),
 		"timestamp":  ctx.StartTime.Unix(),
+		"requestId":  ctx.RequestID,
 	})
 
+	// Set trace context
+	vm.Set("$trace", map[string]interface{}{
+		"id":          ctx.RequestID,
+		"traceparent": core.FormatTraceparent(ctx.RequestID),
+	})
+	if p.app != nil {
+		vm.Set("$app", newLambdaTracedApp(p.app, ctx.RequestID))
+	}
+
 	// Set request context for HTTP triggers
 	if ctx.Request != nil {
 		vm.Set("$request", map[string]interface{}{
@@ -517,6 +745,11 @@ func (p *LambdaFunctionPlugin) setExecutionContext(vm *goja.Runtime, ctx *Lambda
 		})
 	}
 
+	// Set message context for realtime triggers
+	if ctx.RealtimeMessage != nil {
+		vm.Set("$message", ctx.RealtimeMessage)
+	}
+
 	// Set record context for database triggers
 	if ctx.Record != nil {
 		vm.Set("$record", ctx.Record)
@@ -526,20 +759,31 @@ func (p *LambdaFunctionPlugin) setExecutionContext(vm *goja.Runtime, ctx *Lambda
 	}
 }
 
-// executeWithContext executes JavaScript code with timeout
-func (p *LambdaFunctionPlugin) executeWithContext(ctx context.Context, vm *goja.Runtime, code string) (interface{}, error) {
+// executeWithContext executes the JavaScript call with timeout and
+// returns its exported result.
+//
+// On timeout the runtime is interrupted so that it could be safely reused.
+func (p *LambdaFunctionPlugin) executeWithContext(ctx context.Context, vm *goja.Runtime, call func() (goja.Value, error)) (interface{}, error) {
 	done := make(chan struct{})
 	var result interface{}
 	var err error
 
 	go func() {
 		defer close(done)
-		result, err = vm.RunString(code)
+
+		var value goja.Value
+		value, err = call()
+		if err == nil && value != nil {
+			result = value.Export()
+		}
 	}()
 
 	select {
 	case <-ctx.Done():
-		return nil, fmt.Errorf("execution timeout")
+		vm.Interrupt(errExecutionTimeout)
+		<-done
+		vm.ClearInterrupt()
+		return nil, errExecutionTimeout
 	case <-done:
 		return result, err
 	}
@@ -559,6 +803,53 @@ func (p *LambdaFunctionPlugin) getRequestBody(r *http.Request) string {
 	return string(body)
 }
 
+// logExecution stores the execution result in the lambda logs collection (if exists).
+func (p *LambdaFunctionPlugin) logExecution(ctx *LambdaFunctionExecutionContext, function *core.Record, result *LambdaFunctionExecutionResult) {
+	if p.app == nil {
+		return
+	}
+
+	collection, err := p.app.FindCachedCollectionByNameOrId(core.CollectionNameLambdaLogs)
+	if err != nil {
+		return // logs collection is not created yet
+	}
+
+	execContext := map[string]any{}
+	switch {
+	case ctx.Request != nil:
+		execContext["request"] = map[string]any{
+			"method": ctx.Request.Method,
+			"url":    ctx.Request.URL.String(),
+		}
+	case ctx.RealtimeMessage != nil:
+		execContext["topic"] = ctx.RealtimeMessage.Topic
+	}
+	if record, ok := ctx.Record.(*core.Record); ok {
+		execContext["collection"] = record.Collection().Name
+		execContext["record"] = record.Id
+	}
+
+	log := core.NewRecord(collection)
+	log.Set("function_id", function.Id)
+	log.Set("function_name", function.GetString("name"))
+	log.Set("trigger_type", ctx.TriggerType)
+	log.Set("request_id", ctx.RequestID)
+	log.Set("success", result.Success)
+	log.Set("error", result.Error)
+	log.Set("duration_ms", result.Duration.Milliseconds())
+	log.Set("output", result.Output)
+	log.Set("context", execContext)
+
+	if err := p.app.Save(log); err != nil {
+		p.app.Logger().Warn(
+			"Failed to save lambda function execution log",
+			"function", function.GetString("name"),
+			"requestId", ctx.RequestID,
+			"error", err,
+		)
+	}
+}
+
 // formatError formats an error for output
 func (p *LambdaFunctionPlugin) formatError(err error) string {
 	if err == nil {
@@ -582,6 +873,10 @@ func (p *LambdaFunctionPlugin) handleFunctionUpdated(record *core.Record) error
 func (p *LambdaFunctionPlugin) handleFunctionDeleted(record *core.Record) error {
 	functionID := record.Id
 
+	// Release the compiled program and the warm runtime
+	p.programs.remove(functionID)
+	p.warmStates.remove(functionID)
+
 	// Remove HTTP routes
 	p.httpRoutes.Range(func(key, value interface{}) bool {
 		route := value.(*LambdaFunctionHTTPRoute)
@@ -591,6 +886,9 @@ func (p *LambdaFunctionPlugin) handleFunctionDeleted(record *core.Record) error
 		return true
 	})
 
+	// Remove realtime triggers
+	p.removeRealtimeTriggers(functionID)
+
 	// Remove database triggers
 	p.dbTriggers.Range(func(key, value interface{}) bool {
 		triggers := value.([]*LambdaFunctionDBTrigger)
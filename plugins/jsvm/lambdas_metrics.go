@@ -0,0 +1,225 @@
+package jsvm
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// defaultMetricsWindowSize is the default number of the most recent
+// executions used for calculating the latency percentiles.
+const defaultMetricsWindowSize = 200
+
+// LambdaFunctionMetrics represents the aggregated execution metrics of a single lambda function.
+type LambdaFunctionMetrics struct {
+	FunctionID   string    `json:"functionId"`
+	FunctionName string    `json:"functionName"`
+	Invocations  int64     `json:"invocations"`
+	Errors       int64     `json:"errors"`
+	ColdStarts   int64     `json:"coldStarts"`
+	Compilations int64     `json:"compilations"`
+	LastInvoked  time.Time `json:"lastInvoked"`
+
+	// latency values in milliseconds
+	AvgMs float64 `json:"avgMs"`
+	MinMs float64 `json:"minMs"`
+	MaxMs float64 `json:"maxMs"`
+	P50Ms float64 `json:"p50Ms"`
+	P95Ms float64 `json:"p95Ms"`
+	P99Ms float64 `json:"p99Ms"`
+}
+
+// Metrics returns the execution metrics of all invoked lambda functions.
+func (p *LambdaFunctionPlugin) Metrics() []*LambdaFunctionMetrics {
+	return p.metrics.all()
+}
+
+// metricsListHandler serves the metrics of all functions together with the runtimes pool state.
+func (p *LambdaFunctionPlugin) metricsListHandler(e *core.RequestEvent) error {
+	return e.JSON(http.StatusOK, map[string]any{
+		"functions": p.metrics.all(),
+		"pool":      p.executors.stats(),
+	})
+}
+
+// metricsViewHandler serves the metrics of a single function.
+func (p *LambdaFunctionPlugin) metricsViewHandler(e *core.RequestEvent) error {
+	metrics, ok := p.metrics.get(e.Request.PathValue("id"))
+	if !ok {
+		return e.NotFoundError("Missing lambda function execution metrics.", nil)
+	}
+
+	return e.JSON(http.StatusOK, metrics)
+}
+
+// lambdaMetricsSample describes a single function execution measurement.
+type lambdaMetricsSample struct {
+	duration  time.Duration
+	failed    bool
+	coldStart bool
+	compiled  bool
+}
+
+type lambdaFunctionMetricsEntry struct {
+	name          string
+	invocations   int64
+	errors        int64
+	coldStarts    int64
+	compilations  int64
+	lastInvoked   time.Time
+	totalDuration time.Duration
+	min           time.Duration
+	max           time.Duration
+
+	// ring buffer with the most recent durations
+	window []time.Duration
+	next   int
+}
+
+// lambdaMetrics is a concurrent safe per function metrics registry.
+type lambdaMetrics struct {
+	mux        sync.RWMutex
+	windowSize int
+	entries    map[string]*lambdaFunctionMetricsEntry
+}
+
+func newLambdaMetrics(windowSize int) *lambdaMetrics {
+	if windowSize <= 0 {
+		windowSize = defaultMetricsWindowSize
+	}
+
+	return &lambdaMetrics{
+		windowSize: windowSize,
+		entries:    map[string]*lambdaFunctionMetricsEntry{},
+	}
+}
+
+// record registers a new execution sample for the specified function.
+func (m *lambdaMetrics) record(functionID string, functionName string, sample lambdaMetricsSample) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	entry, ok := m.entries[functionID]
+	if !ok {
+		entry = &lambdaFunctionMetricsEntry{
+			window: make([]time.Duration, 0, m.windowSize),
+		}
+		m.entries[functionID] = entry
+	}
+
+	if functionName != "" {
+		entry.name = functionName
+	}
+
+	entry.invocations++
+	entry.lastInvoked = time.Now()
+	entry.totalDuration += sample.duration
+
+	if sample.failed {
+		entry.errors++
+	}
+	if sample.coldStart {
+		entry.coldStarts++
+	}
+	if sample.compiled {
+		entry.compilations++
+	}
+
+	if entry.invocations == 1 || sample.duration < entry.min {
+		entry.min = sample.duration
+	}
+	if sample.duration > entry.max {
+		entry.max = sample.duration
+	}
+
+	if len(entry.window) < m.windowSize {
+		entry.window = append(entry.window, sample.duration)
+	} else {
+		entry.window[entry.next] = sample.duration
+		entry.next = (entry.next + 1) % m.windowSize
+	}
+}
+
+// remove deletes the metrics of the specified function.
+func (m *lambdaMetrics) remove(functionID string) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	delete(m.entries, functionID)
+}
+
+// get returns the metrics snapshot of a single function.
+func (m *lambdaMetrics) get(functionID string) (*LambdaFunctionMetrics, bool) {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	entry, ok := m.entries[functionID]
+	if !ok {
+		return nil, false
+	}
+
+	return entry.snapshot(functionID), true
+}
+
+// all returns the metrics snapshots of all functions sorted by their name.
+func (m *lambdaMetrics) all() []*LambdaFunctionMetrics {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	result := make([]*LambdaFunctionMetrics, 0, len(m.entries))
+	for id, entry := range m.entries {
+		result = append(result, entry.snapshot(id))
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].FunctionName < result[j].FunctionName
+	})
+
+	return result
+}
+
+func (e *lambdaFunctionMetricsEntry) snapshot(functionID string) *LambdaFunctionMetrics {
+	result := &LambdaFunctionMetrics{
+		FunctionID:   functionID,
+		FunctionName: e.name,
+		Invocations:  e.invocations,
+		Errors:       e.errors,
+		ColdStarts:   e.coldStarts,
+		Compilations: e.compilations,
+		LastInvoked:  e.lastInvoked,
+		MinMs:        toMs(e.min),
+		MaxMs:        toMs(e.max),
+	}
+
+	if e.invocations > 0 {
+		result.AvgMs = toMs(e.totalDuration / time.Duration(e.invocations))
+	}
+
+	if len(e.window) > 0 {
+		sorted := append([]time.Duration{}, e.window...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+		result.P50Ms = toMs(percentile(sorted, 50))
+		result.P95Ms = toMs(percentile(sorted, 95))
+		result.P99Ms = toMs(percentile(sorted, 99))
+	}
+
+	return result
+}
+
+// percentile returns the nearest-rank percentile value from the sorted durations.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100 // ceil
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
+
+func toMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,57 @@
+package jsvm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLambdaMetrics(t *testing.T) {
+	m := newLambdaMetrics(4)
+
+	if _, ok := m.get("missing"); ok {
+		t.Fatal("Expected missing metrics")
+	}
+
+	durations := []time.Duration{50, 10, 20, 30, 40} // ms
+	for i, d := range durations {
+		m.record("fn1", "test", lambdaMetricsSample{
+			duration:  d * time.Millisecond,
+			failed:    i == 1,
+			coldStart: i == 0,
+			compiled:  i == 0,
+		})
+	}
+	m.record("fn2", "abc", lambdaMetricsSample{duration: time.Millisecond})
+
+	metrics, ok := m.get("fn1")
+	if !ok {
+		t.Fatal("Expected fn1 metrics")
+	}
+
+	if metrics.FunctionName != "test" ||
+		metrics.Invocations != 5 ||
+		metrics.Errors != 1 ||
+		metrics.ColdStarts != 1 ||
+		metrics.Compilations != 1 {
+		t.Fatalf("Unexpected counters %#v", metrics)
+	}
+
+	if metrics.MinMs != 10 || metrics.MaxMs != 50 || metrics.AvgMs != 30 {
+		t.Fatalf("Unexpected min/max/avg %#v", metrics)
+	}
+
+	// the window contains only the last 4 durations (10,20,30,40)
+	if metrics.P50Ms != 20 || metrics.P95Ms != 40 || metrics.P99Ms != 40 {
+		t.Fatalf("Unexpected percentiles %#v", metrics)
+	}
+
+	all := m.all()
+	if len(all) != 2 || all[0].FunctionName != "abc" || all[1].FunctionName != "test" {
+		t.Fatalf("Expected metrics sorted by function name, got %#v", all)
+	}
+
+	m.remove("fn1")
+	if _, ok := m.get("fn1"); ok {
+		t.Fatal("Expected fn1 metrics to be removed")
+	}
+}
@@ -0,0 +1,131 @@
+package jsvm
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/jsonschema"
+	"github.com/pocketbase/pocketbase/tools/openapi"
+)
+
+// lambdaRoutesPrefix is the base path under which all lambda HTTP triggers are mounted.
+const lambdaRoutesPrefix = "/api/functions"
+
+var pathParamRegex = regexp.MustCompile(`\{(\w+)(\.\.\.)?\}`)
+
+// OpenAPIDocument generates an OpenAPI 3.1 document describing
+// all currently registered (aka. enabled) lambda HTTP endpoints.
+func (p *LambdaFunctionPlugin) OpenAPIDocument() *openapi.Document {
+	doc := openapi.NewDocument("PocketBase lambda functions", "1.0.0")
+	doc.Info.Description = "HTTP endpoints exposed by the enabled lambda functions."
+
+	routes := []*LambdaFunctionHTTPRoute{}
+	p.httpRoutes.Range(func(key, value interface{}) bool {
+		routes = append(routes, value.(*LambdaFunctionHTTPRoute))
+		return true
+	})
+
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path == routes[j].Path {
+			return routes[i].Method < routes[j].Method
+		}
+		return routes[i].Path < routes[j].Path
+	})
+
+	tags := map[string]struct{}{}
+
+	for _, route := range routes {
+		path, params := normalizeOpenAPIPath(lambdaRoutesPrefix + route.Path)
+
+		op := &openapi.Operation{
+			OperationId: route.FunctionName + "_" + strings.ToLower(route.Method),
+			Summary:     route.Summary,
+			Description: route.Description,
+			Tags:        []string{route.FunctionName},
+			Parameters:  params,
+			Responses: map[string]*openapi.Response{
+				"200": {Description: "Successful response."},
+				"500": {
+					Description: "Function execution failure.",
+					Content:     openapi.JSONContent(openapi.ErrorSchema()),
+				},
+			},
+		}
+
+		if op.Summary == "" {
+			op.Summary = route.FunctionName
+		}
+
+		if len(route.ResponseSchema) > 0 {
+			op.Responses["200"].Content = openapi.JSONContent(route.ResponseSchema)
+		}
+
+		if len(route.RequestSchema) > 0 {
+			op.RequestBody = &openapi.RequestBody{
+				Required: true,
+				Content:  openapi.JSONContent(route.RequestSchema),
+			}
+			op.Responses["400"] = &openapi.Response{
+				Description: "Request body validation failure.",
+				Content:     openapi.JSONContent(openapi.ErrorSchema()),
+			}
+		}
+
+		doc.AddOperation(route.Method, path, op)
+
+		if _, ok := tags[route.FunctionName]; !ok {
+			tags[route.FunctionName] = struct{}{}
+			doc.Tags = append(doc.Tags, openapi.Tag{Name: route.FunctionName, Description: route.Description})
+		}
+	}
+
+	return doc
+}
+
+// openAPIHandler serves the lambda functions OpenAPI document.
+func (p *LambdaFunctionPlugin) openAPIHandler(e *core.RequestEvent) error {
+	return e.JSON(http.StatusOK, p.OpenAPIDocument())
+}
+
+// normalizeOpenAPIPath converts the router path parameters (incl. wildcards)
+// into their OpenAPI equivalent and returns the related parameters definitions.
+func normalizeOpenAPIPath(path string) (string, []*openapi.Parameter) {
+	var params []*openapi.Parameter
+
+	for _, match := range pathParamRegex.FindAllStringSubmatch(path, -1) {
+		params = append(params, &openapi.Parameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   openapi.Schema{"type": "string"},
+		})
+	}
+
+	return pathParamRegex.ReplaceAllString(path, "{$1}"), params
+}
+
+// validateRequestBody validates the request body against the provided JSON Schema.
+func validateRequestBody(e *core.RequestEvent, schema map[string]any) error {
+	info, err := e.RequestInfo()
+	if err != nil {
+		return e.BadRequestError("Failed to read the request body.", err)
+	}
+
+	err = jsonschema.Validate(schema, info.Body)
+	if err == nil {
+		return nil
+	}
+
+	var vErr validation.Error
+	var vErrs validation.Errors
+	if !errors.As(err, &vErr) && !errors.As(err, &vErrs) {
+		return e.InternalServerError("Invalid lambda function request schema.", err)
+	}
+
+	return e.BadRequestError("Failed to validate the request body.", err)
+}
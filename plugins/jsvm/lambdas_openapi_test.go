@@ -0,0 +1,107 @@
+package jsvm
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+)
+
+func TestLambdaOpenAPIDocument(t *testing.T) {
+	p := &LambdaFunctionPlugin{}
+
+	p.httpRoutes.Store("POST:/orders/{id}", &LambdaFunctionHTTPRoute{
+		FunctionID:     "fn1",
+		FunctionName:   "orders",
+		Description:    "Orders api",
+		Method:         "POST",
+		Path:           "/orders/{id}",
+		Summary:        "Update order",
+		RequestSchema:  map[string]any{"type": "object"},
+		ResponseSchema: map[string]any{"type": "string"},
+	})
+	p.httpRoutes.Store("GET:/files/{path...}", &LambdaFunctionHTTPRoute{
+		FunctionID:   "fn2",
+		FunctionName: "files",
+		Method:       "GET",
+		Path:         "/files/{path...}",
+	})
+
+	raw, err := json.Marshal(p.OpenAPIDocument())
+	if err != nil {
+		t.Fatal(err)
+	}
+	str := string(raw)
+
+	expectedParts := []string{
+		`"openapi":"3.1.0"`,
+		`"/api/functions/files/{path}":{"get":{"operationId":"files_get","summary":"files","tags":["files"],"parameters":[{"name":"path","in":"path","required":true,"schema":{"type":"string"}}]`,
+		`"/api/functions/orders/{id}":{"post":{"operationId":"orders_post","summary":"Update order","description":"Orders api","tags":["orders"]`,
+		`"requestBody":{"required":true,"content":{"application/json":{"schema":{"type":"object"}}}}`,
+		`"200":{"description":"Successful response.","content":{"application/json":{"schema":{"type":"string"}}}}`,
+		`"400":{"description":"Request body validation failure."`,
+		`"tags":[{"name":"files"},{"name":"orders","description":"Orders api"}]`,
+	}
+	for _, part := range expectedParts {
+		if !strings.Contains(str, part) {
+			t.Errorf("Missing expected part\n%s\nin\n%s", part, str)
+		}
+	}
+}
+
+func TestLambdaValidateRequestBody(t *testing.T) {
+	schema := map[string]any{
+		"type":     "object",
+		"required": []any{"title"},
+		"properties": map[string]any{
+			"title": map[string]any{"type": "string"},
+		},
+	}
+
+	scenarios := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedData   string
+	}{
+		{"valid body", `{"title":"test"}`, 0, ""},
+		{"missing required", `{}`, 400, `{"title":{"code":"validation_required","message":"Cannot be blank."}}`},
+		{"invalid type", `{"title":123}`, 400, `{"title":{"code":"validation_schema_invalid_type","message":"Must be of type string.","params":{"type":"string"}}}`},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(s.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			e := &core.RequestEvent{Event: router.Event{Request: req, Response: httptest.NewRecorder()}}
+
+			err := validateRequestBody(e, schema)
+
+			if s.expectedStatus == 0 {
+				if err != nil {
+					t.Fatalf("Expected nil error, got %v", err)
+				}
+				return
+			}
+
+			apiErr, ok := err.(*router.ApiError)
+			if !ok {
+				t.Fatalf("Expected ApiError, got %v", err)
+			}
+
+			if apiErr.Status != s.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", s.expectedStatus, apiErr.Status)
+			}
+
+			data, _ := json.Marshal(apiErr.Data)
+			if str := string(data); str != s.expectedData {
+				t.Fatalf("Expected data\n%s\ngot\n%s", s.expectedData, str)
+			}
+		})
+	}
+}
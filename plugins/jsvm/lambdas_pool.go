@@ -0,0 +1,147 @@
+package jsvm
+
+import (
+	"sync"
+	"time"
+
+	"github.com/dop251/goja"
+)
+
+// adaptivePool is a goja.Runtime pool that grows on demand up to
+// a max size and releases the idle runtimes above its min size.
+//
+// Unlike [vmsPool] the runtimes created when all pooled items are busy
+// are kept in the pool (if there is room), so that sustained load
+// doesn't pay the runtime initialization cost on every call.
+type adaptivePool struct {
+	mux         sync.Mutex
+	factory     func() *goja.Runtime
+	idle        []*adaptivePoolItem
+	total       int
+	minSize     int
+	maxSize     int
+	idleTimeout time.Duration
+}
+
+type adaptivePoolItem struct {
+	vm       *goja.Runtime
+	lastUsed time.Time
+}
+
+// adaptivePoolStats represents a snapshot of the pool state.
+type adaptivePoolStats struct {
+	Total int `json:"total"`
+	Idle  int `json:"idle"`
+	Busy  int `json:"busy"`
+	Min   int `json:"min"`
+	Max   int `json:"max"`
+}
+
+// newAdaptivePool creates a new adaptive pool with minSize pre-warmed runtimes.
+//
+// If maxSize is less than minSize, it is set to minSize.
+func newAdaptivePool(minSize int, maxSize int, idleTimeout time.Duration, factory func() *goja.Runtime) *adaptivePool {
+	if maxSize < minSize {
+		maxSize = minSize
+	}
+
+	pool := &adaptivePool{
+		factory:     factory,
+		minSize:     minSize,
+		maxSize:     maxSize,
+		idleTimeout: idleTimeout,
+		idle:        make([]*adaptivePoolItem, 0, maxSize),
+	}
+
+	now := time.Now()
+	for i := 0; i < minSize; i++ {
+		pool.idle = append(pool.idle, &adaptivePoolItem{vm: factory(), lastUsed: now})
+	}
+	pool.total = minSize
+
+	return pool
+}
+
+// run executes "call" with a runtime from the pool.
+//
+// The second argument of "call" reports whether the runtime was just created.
+func (p *adaptivePool) run(call func(vm *goja.Runtime, isNew bool) error) error {
+	item, isNew, pooled := p.acquire()
+
+	err := call(item.vm, isNew)
+
+	if pooled {
+		p.release(item)
+	}
+
+	return err
+}
+
+// acquire returns an idle runtime or creates a new one.
+//
+// pooled reports whether the returned item is tracked by the pool
+// and should be released after use.
+func (p *adaptivePool) acquire() (item *adaptivePoolItem, isNew bool, pooled bool) {
+	p.mux.Lock()
+
+	if n := len(p.idle); n > 0 {
+		// LIFO so that the least recently used items could expire
+		item = p.idle[n-1]
+		p.idle = p.idle[:n-1]
+		p.mux.Unlock()
+		return item, false, true
+	}
+
+	pooled = p.total < p.maxSize
+	if pooled {
+		p.total++
+	}
+
+	p.mux.Unlock()
+
+	return &adaptivePoolItem{vm: p.factory()}, true, pooled
+}
+
+// release returns the item back to the idle list and removes
+// the expired idle items above the min pool size.
+func (p *adaptivePool) release(item *adaptivePoolItem) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	now := time.Now()
+	item.lastUsed = now
+
+	p.idle = append(p.idle, item)
+
+	if p.idleTimeout <= 0 {
+		return
+	}
+
+	// the idle list is ordered from the least to the most recently used
+	var expired int
+	for _, idleItem := range p.idle {
+		if p.total-expired <= p.minSize || now.Sub(idleItem.lastUsed) < p.idleTimeout {
+			break
+		}
+		expired++
+	}
+
+	if expired > 0 {
+		p.idle = append(p.idle[:0], p.idle[expired:]...)
+		p.total -= expired
+	}
+}
+
+// stats returns a snapshot of the current pool state.
+func (p *adaptivePool) stats() adaptivePoolStats {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	return adaptivePoolStats{
+		Total: p.total,
+		Idle:  len(p.idle),
+		Busy:  p.total - len(p.idle),
+		Min:   p.minSize,
+		Max:   p.maxSize,
+	}
+}
@@ -0,0 +1,92 @@
+package jsvm
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dop251/goja"
+)
+
+func TestAdaptivePoolGrowAndShrink(t *testing.T) {
+	var created int
+	var mux sync.Mutex
+	factory := func() *goja.Runtime {
+		mux.Lock()
+		created++
+		mux.Unlock()
+		return goja.New()
+	}
+
+	pool := newAdaptivePool(1, 2, 50*time.Millisecond, factory)
+
+	if created != 1 {
+		t.Fatalf("Expected 1 prewarmed runtime, got %d", created)
+	}
+
+	// occupy all runtimes (incl. one above the max)
+	started := make(chan struct{}, 3)
+	release := make(chan struct{})
+	isNewCalls := make(chan bool, 3)
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pool.run(func(vm *goja.Runtime, isNew bool) error {
+				isNewCalls <- isNew
+				started <- struct{}{}
+				<-release
+				return nil
+			})
+		}()
+	}
+	for i := 0; i < 3; i++ {
+		<-started
+	}
+
+	if stats := pool.stats(); stats.Total != 2 || stats.Busy != 2 || stats.Idle != 0 {
+		t.Fatalf("Expected 2 busy pooled runtimes, got %#v", stats)
+	}
+
+	close(release)
+	wg.Wait()
+	close(isNewCalls)
+
+	var totalNew int
+	for isNew := range isNewCalls {
+		if isNew {
+			totalNew++
+		}
+	}
+	if totalNew != 2 {
+		t.Fatalf("Expected 2 new runtimes, got %d", totalNew)
+	}
+
+	// the one-off runtime shouldn't be kept
+	if stats := pool.stats(); stats.Total != 2 || stats.Idle != 2 {
+		t.Fatalf("Expected 2 idle pooled runtimes, got %#v", stats)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+
+	// trigger the idle cleanup
+	pool.run(func(vm *goja.Runtime, isNew bool) error {
+		if isNew {
+			t.Fatal("Expected to reuse a pooled runtime")
+		}
+		return nil
+	})
+
+	if stats := pool.stats(); stats.Total != 1 || stats.Idle != 1 {
+		t.Fatalf("Expected the pool to shrink to its min size, got %#v", stats)
+	}
+}
+
+func TestAdaptivePoolMaxLessThanMin(t *testing.T) {
+	pool := newAdaptivePool(3, 1, 0, goja.New)
+
+	if stats := pool.stats(); stats.Min != 3 || stats.Max != 3 || stats.Total != 3 {
+		t.Fatalf("Expected max to be normalized to the min size, got %#v", stats)
+	}
+}
@@ -0,0 +1,189 @@
+package jsvm
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/dop251/goja"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+// errExecutionTimeout is returned when a lambda function exceeds its execution time.
+var errExecutionTimeout = errors.New("execution timeout")
+
+// lambdaProgram is a compiled lambda function code for a specific function version.
+type lambdaProgram struct {
+	version string
+	program *goja.Program
+}
+
+// lambdaPrograms is a concurrent safe cache with the
+// compiled lambda function programs indexed by their function id.
+type lambdaPrograms struct {
+	mux   sync.RWMutex
+	items map[string]*lambdaProgram
+}
+
+func newLambdaPrograms() *lambdaPrograms {
+	return &lambdaPrograms{items: map[string]*lambdaProgram{}}
+}
+
+// load returns the cached program of the specified function
+// or compiles a new one if the function code has changed.
+//
+// The second returned value reports whether the program was compiled.
+func (c *lambdaPrograms) load(function *core.Record) (*lambdaProgram, bool, error) {
+	code := function.GetString("code")
+	version := security.SHA256(code)
+
+	c.mux.RLock()
+	item := c.items[function.Id]
+	c.mux.RUnlock()
+
+	if item != nil && item.version == version {
+		return item, false, nil
+	}
+
+	program, err := goja.Compile(function.GetString("name"), code, false)
+	if err != nil {
+		return nil, false, err
+	}
+
+	item = &lambdaProgram{version: version, program: program}
+
+	c.mux.Lock()
+	c.items[function.Id] = item
+	c.mux.Unlock()
+
+	return item, true, nil
+}
+
+// remove deletes the cached program of the specified function.
+func (c *lambdaPrograms) remove(functionID string) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	delete(c.items, functionID)
+}
+
+// -------------------------------------------------------------------
+
+// lambdaWarmState holds the dedicated runtime of a "keepWarm" function
+// together with its module state initialized on the first call.
+type lambdaWarmState struct {
+	mux     sync.Mutex
+	version string
+	vm      *goja.Runtime
+
+	// handler is the function exported via module.exports (if any)
+	handler goja.Callable
+}
+
+// lambdaWarmStates is a concurrent safe registry with the
+// warm function states indexed by their function id.
+type lambdaWarmStates struct {
+	mux   sync.Mutex
+	items map[string]*lambdaWarmState
+}
+
+func newLambdaWarmStates() *lambdaWarmStates {
+	return &lambdaWarmStates{items: map[string]*lambdaWarmState{}}
+}
+
+// get returns the warm state of the specified function (creating an empty one if missing).
+func (s *lambdaWarmStates) get(functionID string) *lambdaWarmState {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	state, ok := s.items[functionID]
+	if !ok {
+		state = &lambdaWarmState{}
+		s.items[functionID] = state
+	}
+
+	return state
+}
+
+// remove discards the warm state of the specified function.
+func (s *lambdaWarmStates) remove(functionID string) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	delete(s.items, functionID)
+}
+
+// executeWarm executes the program in the dedicated function runtime.
+//
+// On the first call (or after a code change) the program is executed
+// on a new runtime to initialize the module state. If the program assigns
+// a function to module.exports, all following calls invoke only that
+// function, otherwise the program is rerun in the same runtime
+// (aka. the global state is preserved between the calls).
+//
+// The second returned value reports whether the runtime was initialized.
+func (p *LambdaFunctionPlugin) executeWarm(
+	execCtx context.Context,
+	ctx *LambdaFunctionExecutionContext,
+	function *core.Record,
+	program *lambdaProgram,
+) (any, bool, error) {
+	state := p.warmStates.get(function.Id)
+
+	state.mux.Lock()
+	defer state.mux.Unlock()
+
+	if state.vm != nil && state.version == program.version {
+		p.setExecutionContext(state.vm, ctx, function)
+
+		output, err := p.executeWithContext(execCtx, state.vm, func() (goja.Value, error) {
+			if state.handler != nil {
+				return state.handler(goja.Undefined())
+			}
+			return state.vm.RunProgram(program.program)
+		})
+		if errors.Is(err, errExecutionTimeout) {
+			// the interrupted runtime state is not reliable
+			state.vm = nil
+		}
+
+		return output, false, err
+	}
+
+	vm := p.createVM()
+
+	module := vm.NewObject()
+	exports := vm.NewObject()
+	module.Set("exports", exports)
+	vm.Set("module", module)
+	vm.Set("exports", exports)
+
+	p.setExecutionContext(vm, ctx, function)
+
+	var handler goja.Callable
+
+	output, err := p.executeWithContext(execCtx, vm, func() (goja.Value, error) {
+		result, err := vm.RunProgram(program.program)
+		if err != nil {
+			return nil, err
+		}
+
+		if fn, ok := goja.AssertFunction(module.Get("exports")); ok {
+			handler = fn
+			return fn(goja.Undefined())
+		}
+
+		return result, nil
+	})
+	if err != nil {
+		state.vm = nil
+		return nil, true, err
+	}
+
+	state.vm = vm
+	state.version = program.version
+	state.handler = handler
+
+	return output, true, nil
+}
@@ -0,0 +1,156 @@
+package jsvm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dop251/goja_nodejs/require"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/template"
+)
+
+func newTestLambdaRecord(id string, code string) *core.Record {
+	record := core.NewRecord(core.NewBaseCollection(core.CollectionNameLambdaFunctions))
+	record.Id = id
+	record.Set("name", "test_"+id)
+	record.Set("code", code)
+	record.Set("enabled", true)
+	return record
+}
+
+func newTestLambdaPlugin() *LambdaFunctionPlugin {
+	return &LambdaFunctionPlugin{
+		config:           LambdaFunctionPluginConfig{MaxExecutionTime: time.Second},
+		templateRegistry: template.NewRegistry(),
+		requireRegistry:  new(require.Registry),
+		programs:         newLambdaPrograms(),
+		warmStates:       newLambdaWarmStates(),
+		metrics:          newLambdaMetrics(0),
+	}
+}
+
+func TestLambdaProgramsLoad(t *testing.T) {
+	programs := newLambdaPrograms()
+
+	fn := newTestLambdaRecord("a", "1 + 1")
+
+	p1, compiled, err := programs.load(fn)
+	if err != nil || !compiled {
+		t.Fatalf("Expected the program to be compiled, got %v (%v)", compiled, err)
+	}
+
+	p2, compiled, err := programs.load(fn)
+	if err != nil || compiled || p1 != p2 {
+		t.Fatalf("Expected the cached program, got %v (%v)", compiled, err)
+	}
+
+	fn.Set("code", "2 + 2")
+	p3, compiled, err := programs.load(fn)
+	if err != nil || !compiled || p3 == p2 {
+		t.Fatalf("Expected the program to be recompiled after code change, got %v (%v)", compiled, err)
+	}
+
+	fn.Set("code", "invalid(")
+	if _, _, err := programs.load(fn); err == nil {
+		t.Fatal("Expected compile error")
+	}
+
+	programs.remove(fn.Id)
+	if len(programs.items) != 0 {
+		t.Fatalf("Expected the program to be removed, got %d items", len(programs.items))
+	}
+}
+
+func TestLambdaExecuteWarmExportedHandler(t *testing.T) {
+	p := newTestLambdaPlugin()
+
+	fn := newTestLambdaRecord("a", `
+		let counter = 0;
+		module.exports = function() {
+			counter++;
+			return counter;
+		}
+	`)
+
+	program, _, err := p.programs.load(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 1; i <= 3; i++ {
+		output, initialized, err := p.executeWarm(context.Background(), &LambdaFunctionExecutionContext{}, fn, program)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if initialized != (i == 1) {
+			t.Fatalf("[%d] Expected initialized %v, got %v", i, i == 1, initialized)
+		}
+
+		if output != int64(i) {
+			t.Fatalf("[%d] Expected output %d, got %v", i, i, output)
+		}
+	}
+
+	// code change should reset the module state
+	fn.Set("code", `module.exports = () => "changed"`)
+	program, _, err = p.programs.load(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output, initialized, err := p.executeWarm(context.Background(), &LambdaFunctionExecutionContext{}, fn, program)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !initialized || output != "changed" {
+		t.Fatalf("Expected reinitialized runtime with output changed, got %v (%v)", output, initialized)
+	}
+}
+
+func TestLambdaExecuteWarmGlobalState(t *testing.T) {
+	p := newTestLambdaPlugin()
+
+	fn := newTestLambdaRecord("a", `globalThis.total = (globalThis.total || 0) + 1; total`)
+
+	program, _, err := p.programs.load(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 1; i <= 2; i++ {
+		output, _, err := p.executeWarm(context.Background(), &LambdaFunctionExecutionContext{}, fn, program)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if output != int64(i) {
+			t.Fatalf("[%d] Expected output %d, got %v", i, i, output)
+		}
+	}
+}
+
+func TestLambdaExecuteWithContextTimeout(t *testing.T) {
+	p := newTestLambdaPlugin()
+
+	fn := newTestLambdaRecord("a", `while(true) {}`)
+
+	program, _, err := p.programs.load(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, _, err = p.executeWarm(ctx, &LambdaFunctionExecutionContext{}, fn, program)
+	if !errors.Is(err, errExecutionTimeout) {
+		t.Fatalf("Expected timeout error, got %v", err)
+	}
+
+	if state := p.warmStates.get(fn.Id); state.vm != nil {
+		t.Fatal("Expected the interrupted warm runtime to be discarded")
+	}
+}
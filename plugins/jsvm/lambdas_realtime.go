@@ -0,0 +1,186 @@
+package jsvm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/spf13/cast"
+)
+
+// LambdaFunctionRealtimeTrigger represents a custom realtime topic trigger for an lambda function
+type LambdaFunctionRealtimeTrigger struct {
+	FunctionID string
+	Config     core.RealtimeTriggerConfig
+}
+
+// LambdaFunctionRealtimeMessage represents a single message published to a custom realtime topic.
+type LambdaFunctionRealtimeMessage struct {
+	Topic string       `json:"topic"`
+	Data  any          `json:"data"`
+	Auth  *core.Record `json:"auth"`
+}
+
+// lambdaRealtime is the $realtime JS binding.
+type lambdaRealtime struct {
+	app core.App
+}
+
+// Broadcast sends a message to the realtime clients subscribed to the topic.
+//
+// The optional options argument could specify a "filter" rule that
+// the subscribers must satisfy (see [apis.RealtimeBroadcast]).
+//
+// Example:
+//
+//	$realtime.broadcast("chat", { text: "hello" }, { filter: "@request.auth.id != ''" })
+func (r *lambdaRealtime) Broadcast(topic string, data any, options ...map[string]any) error {
+	if topic == "" {
+		return errors.New("missing realtime topic")
+	}
+
+	var filter string
+	if len(options) > 0 && options[0] != nil {
+		filter = cast.ToString(options[0]["filter"])
+	}
+
+	return apis.RealtimeBroadcast(r.app, topic, data, filter)
+}
+
+// parseRealtimeTriggerConfig converts a raw realtime trigger map into [core.RealtimeTriggerConfig].
+func parseRealtimeTriggerConfig(raw map[string]interface{}) (core.RealtimeTriggerConfig, error) {
+	config := core.RealtimeTriggerConfig{}
+
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		return config, err
+	}
+
+	if err := json.Unmarshal(encoded, &config); err != nil {
+		return config, err
+	}
+
+	if config.Topic == "" {
+		return config, errors.New("realtime topic is required")
+	}
+
+	return config, nil
+}
+
+// registerRealtimeTrigger registers a custom realtime topic trigger for an lambda function
+func (p *LambdaFunctionPlugin) registerRealtimeTrigger(functionID string, config core.RealtimeTriggerConfig) {
+	key := fmt.Sprintf("%s:%s", functionID, config.Topic)
+	p.realtimeTriggers.Store(key, &LambdaFunctionRealtimeTrigger{
+		FunctionID: functionID,
+		Config:     config,
+	})
+}
+
+// removeRealtimeTriggers removes all realtime triggers of the specified function.
+func (p *LambdaFunctionPlugin) removeRealtimeTriggers(functionID string) {
+	p.realtimeTriggers.Range(func(key, value interface{}) bool {
+		if value.(*LambdaFunctionRealtimeTrigger).FunctionID == functionID {
+			p.realtimeTriggers.Delete(key)
+		}
+		return true
+	})
+}
+
+// findRealtimeTriggers returns the realtime triggers matching the topic
+// (sorted by their function id for deterministic execution order).
+func (p *LambdaFunctionPlugin) findRealtimeTriggers(topic string) []*LambdaFunctionRealtimeTrigger {
+	result := []*LambdaFunctionRealtimeTrigger{}
+
+	p.realtimeTriggers.Range(func(key, value interface{}) bool {
+		trigger := value.(*LambdaFunctionRealtimeTrigger)
+		if trigger.Config.MatchTopic(topic) {
+			result = append(result, trigger)
+		}
+		return true
+	})
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].FunctionID < result[j].FunctionID
+	})
+
+	return result
+}
+
+type realtimePublishForm struct {
+	Topic string `form:"topic" json:"topic"`
+	Data  any    `form:"data" json:"data"`
+}
+
+func (form *realtimePublishForm) validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(&form.Topic, validation.Required, validation.Length(1, 2500)),
+	)
+}
+
+// realtimePublishHandler handles the custom realtime topic messages
+// by executing the lambda functions with a matching realtime trigger.
+//
+// Only the triggers whose publish rule is satisfied by the current request are executed.
+func (p *LambdaFunctionPlugin) realtimePublishHandler(e *core.RequestEvent) error {
+	form := new(realtimePublishForm)
+
+	if err := e.BindBody(form); err != nil {
+		return e.BadRequestError("An error occurred while loading the submitted data.", err)
+	}
+
+	if err := form.validate(); err != nil {
+		return e.BadRequestError("", err)
+	}
+
+	triggers := p.findRealtimeTriggers(form.Topic)
+	if len(triggers) == 0 {
+		return e.NotFoundError("Missing realtime topic trigger.", nil)
+	}
+
+	requestInfo, err := e.RequestInfo()
+	if err != nil {
+		return e.BadRequestError("", err)
+	}
+
+	allowed := make([]*LambdaFunctionRealtimeTrigger, 0, len(triggers))
+	for _, trigger := range triggers {
+		if apis.RealtimeCheckMessageRule(e.App, requestInfo, form.Topic, form.Data, trigger.Config.PublishRule) {
+			allowed = append(allowed, trigger)
+		}
+	}
+
+	if len(allowed) == 0 {
+		return e.ForbiddenError("You are not allowed to publish to the realtime topic.", nil)
+	}
+
+	message := &LambdaFunctionRealtimeMessage{
+		Topic: form.Topic,
+		Data:  form.Data,
+		Auth:  e.Auth,
+	}
+
+	requestId := requestTraceId(e.Request)
+
+	for _, trigger := range allowed {
+		ctx := &LambdaFunctionExecutionContext{
+			FunctionID:      trigger.FunctionID,
+			TriggerType:     core.TriggerTypeRealtime,
+			RealtimeMessage: message,
+			RequestID:       requestId,
+			StartTime:       time.Now(),
+		}
+
+		result := p.executeFunction(ctx)
+		if !result.Success {
+			return e.InternalServerError("Lambda function execution failed", errors.New(result.Error))
+		}
+	}
+
+	return e.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,172 @@
+package jsvm
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dop251/goja"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/router"
+	"github.com/pocketbase/pocketbase/tools/subscriptions"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestParseRealtimeTriggerConfig(t *testing.T) {
+	config, err := parseRealtimeTriggerConfig(map[string]interface{}{
+		"topic":       "rooms/*",
+		"publishRule": "@request.auth.id != ''",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config.Topic != "rooms/*" {
+		t.Fatalf("Expected topic rooms/*, got %q", config.Topic)
+	}
+
+	if config.PublishRule == nil || *config.PublishRule != "@request.auth.id != ''" {
+		t.Fatalf("Expected publish rule to be set, got %v", config.PublishRule)
+	}
+
+	config, err = parseRealtimeTriggerConfig(map[string]interface{}{"topic": "chat"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.PublishRule != nil {
+		t.Fatalf("Expected nil publish rule, got %q", *config.PublishRule)
+	}
+
+	if _, err := parseRealtimeTriggerConfig(map[string]interface{}{}); err == nil {
+		t.Fatal("Expected error for missing topic")
+	}
+}
+
+func TestLambdaRealtimeTriggersRegistry(t *testing.T) {
+	p := &LambdaFunctionPlugin{}
+
+	p.registerRealtimeTrigger("b", core.RealtimeTriggerConfig{Topic: "rooms/*"})
+	p.registerRealtimeTrigger("a", core.RealtimeTriggerConfig{Topic: "rooms/general"})
+	p.registerRealtimeTrigger("a", core.RealtimeTriggerConfig{Topic: "presence"})
+
+	scenarios := []struct {
+		topic    string
+		expected []string
+	}{
+		{"rooms/general", []string{"a", "b"}},
+		{"rooms/other", []string{"b"}},
+		{"presence", []string{"a"}},
+		{"missing", []string{}},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.topic, func(t *testing.T) {
+			triggers := p.findRealtimeTriggers(s.topic)
+
+			if len(triggers) != len(s.expected) {
+				t.Fatalf("Expected %d triggers, got %d", len(s.expected), len(triggers))
+			}
+
+			for i, id := range s.expected {
+				if triggers[i].FunctionID != id {
+					t.Fatalf("Expected trigger %d to be for function %q, got %q", i, id, triggers[i].FunctionID)
+				}
+			}
+		})
+	}
+
+	p.removeRealtimeTriggers("a")
+
+	if triggers := p.findRealtimeTriggers("rooms/general"); len(triggers) != 1 || triggers[0].FunctionID != "b" {
+		t.Fatalf("Expected only the function b trigger to remain, got %v", triggers)
+	}
+}
+
+func TestLambdaRealtimePublishHandler(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	p := &LambdaFunctionPlugin{app: app}
+	p.registerRealtimeTrigger("a", core.RealtimeTriggerConfig{
+		Topic:       "chat",
+		PublishRule: types.Pointer("data.text != ''"),
+	})
+	p.registerRealtimeTrigger("b", core.RealtimeTriggerConfig{
+		Topic: "admin",
+	})
+
+	scenarios := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{"missing topic", `{}`, 400},
+		{"unknown topic", `{"topic":"unknown"}`, 404},
+		{"unsatisfied publish rule", `{"topic":"chat","data":{"text":""}}`, 403},
+		{"superusers only topic", `{"topic":"admin"}`, 403},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/realtime/publish", strings.NewReader(s.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Body = &router.RereadableReadCloser{ReadCloser: req.Body}
+
+			e := &core.RequestEvent{App: app, Event: router.Event{Request: req, Response: httptest.NewRecorder()}}
+
+			err := p.realtimePublishHandler(e)
+
+			apiErr, ok := err.(*router.ApiError)
+			if !ok {
+				t.Fatalf("Expected ApiError, got %v", err)
+			}
+
+			if apiErr.Status != s.expectedStatus {
+				t.Fatalf("Expected status %d, got %d (%v)", s.expectedStatus, apiErr.Status, apiErr)
+			}
+		})
+	}
+}
+
+func TestLambdaRealtimeBroadcastBinding(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	client := subscriptions.NewDefaultClient()
+	client.Subscribe("chat")
+	app.SubscriptionsBroker().Register(client)
+
+	vm := goja.New()
+	baseBinds(vm)
+	vm.Set("$realtime", &lambdaRealtime{app: app})
+
+	_, err := vm.RunString(`
+		$realtime.broadcast("chat", { text: "a" });
+		$realtime.broadcast("chat", { text: "b" }, { filter: "@request.auth.id != ''" });
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case msg := <-client.Channel():
+		if msg.Name != "chat" || string(msg.Data) != `{"text":"a"}` {
+			t.Fatalf("Unexpected message %s: %s", msg.Name, msg.Data)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected a realtime message")
+	}
+
+	select {
+	case msg := <-client.Channel():
+		t.Fatalf("Expected the filtered message to not be sent, got %s", msg.Data)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if _, err := vm.RunString(`$realtime.broadcast("", {})`); err == nil {
+		t.Fatal("Expected error for missing topic")
+	}
+}
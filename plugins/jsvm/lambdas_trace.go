@@ -0,0 +1,146 @@
+package jsvm
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/dop251/goja"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/spf13/cast"
+)
+
+// lambdaTracedApp is the $app binding of a single lambda function execution.
+//
+// It propagates the execution trace id to the model save/delete
+// operations so that the lambda functions triggered by the database
+// changes share the same trace id.
+type lambdaTracedApp struct {
+	core.App
+
+	ctx context.Context
+}
+
+func newLambdaTracedApp(app core.App, traceId string) *lambdaTracedApp {
+	return &lambdaTracedApp{
+		App: app,
+		ctx: core.ContextWithTraceId(context.Background(), traceId),
+	}
+}
+
+// Save is the same as [core.App.Save] but with the execution trace context.
+func (a *lambdaTracedApp) Save(model core.Model) error {
+	return a.App.SaveWithContext(a.ctx, model)
+}
+
+// SaveNoValidate is the same as [core.App.SaveNoValidate] but with the execution trace context.
+func (a *lambdaTracedApp) SaveNoValidate(model core.Model) error {
+	return a.App.SaveNoValidateWithContext(a.ctx, model)
+}
+
+// Delete is the same as [core.App.Delete] but with the execution trace context.
+func (a *lambdaTracedApp) Delete(model core.Model) error {
+	return a.App.DeleteWithContext(a.ctx, model)
+}
+
+// RunInTransaction is the same as [core.App.RunInTransaction] but
+// with the execution trace context for the transactional app.
+func (a *lambdaTracedApp) RunInTransaction(fn func(txApp core.App) error) error {
+	return a.App.RunInTransaction(func(txApp core.App) error {
+		return fn(&lambdaTracedApp{App: txApp, ctx: a.ctx})
+	})
+}
+
+// requestTraceId returns the trace id of the specified request.
+//
+// It fallbacks to the "traceparent" header or a new trace id
+// in case the request wasn't handled by the default trace middleware.
+func requestTraceId(r *http.Request) string {
+	if traceId := core.TraceIdFromContext(r.Context()); traceId != "" {
+		return traceId
+	}
+
+	if traceId := core.ParseTraceparent(r.Header.Get(core.TraceparentHeader)); traceId != "" {
+		return traceId
+	}
+
+	return core.NewTraceId()
+}
+
+// bindTracedHTTPClient wraps the $http.send binding so that the
+// "traceparent" header of the current execution trace ($trace.id)
+// is added to the outgoing requests (if not already set).
+func bindTracedHTTPClient(vm *goja.Runtime) {
+	httpObj, ok := vm.Get("$http").(*goja.Object)
+	if !ok {
+		return
+	}
+
+	send, ok := goja.AssertFunction(httpObj.Get("send"))
+	if !ok {
+		return
+	}
+
+	httpObj.Set("send", func(call goja.FunctionCall) goja.Value {
+		params, _ := call.Argument(0).Export().(map[string]any)
+
+		traceId := currentTraceId(vm)
+
+		if params != nil && traceId != "" {
+			headers := map[string]string{}
+			for k, v := range cast.ToStringMapString(params["headers"]) {
+				headers[k] = v
+			}
+
+			var hasTraceparent bool
+			for k := range headers {
+				if strings.EqualFold(k, core.TraceparentHeader) {
+					hasTraceparent = true
+					break
+				}
+			}
+
+			if !hasTraceparent {
+				headers[core.TraceparentHeader] = core.FormatTraceparent(traceId)
+			}
+
+			// shallow copy to avoid modifying the user provided object
+			clone := make(map[string]any, len(params))
+			for k, v := range params {
+				clone[k] = v
+			}
+			clone["headers"] = headers
+
+			params = clone
+		}
+
+		var arg goja.Value = goja.Undefined()
+		if params != nil {
+			arg = vm.ToValue(params)
+		} else if len(call.Arguments) > 0 {
+			arg = call.Arguments[0]
+		}
+
+		result, err := send(goja.Undefined(), arg)
+		if err != nil {
+			panic(err)
+		}
+
+		return result
+	})
+}
+
+// currentTraceId returns the trace id of the current VM execution ($trace.id).
+func currentTraceId(vm *goja.Runtime) string {
+	trace, ok := vm.Get("$trace").(*goja.Object)
+	if !ok {
+		return ""
+	}
+
+	id := trace.Get("id")
+	if id == nil || goja.IsUndefined(id) || goja.IsNull(id) {
+		return ""
+	}
+
+	return id.String()
+}
@@ -0,0 +1,136 @@
+package jsvm
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dop251/goja"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestLambdaTracedAppSave(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	var traceIds []string
+	app.OnRecordCreate("demo1").BindFunc(func(e *core.RecordEvent) error {
+		traceIds = append(traceIds, core.TraceIdFromContext(e.Context))
+		return e.Next()
+	})
+	app.OnRecordDelete("demo1").BindFunc(func(e *core.RecordEvent) error {
+		traceIds = append(traceIds, core.TraceIdFromContext(e.Context))
+		return e.Next()
+	})
+
+	collection, err := app.FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tracedApp := newLambdaTracedApp(app, "test_trace")
+
+	record := core.NewRecord(collection)
+	if err := tracedApp.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	err = tracedApp.RunInTransaction(func(txApp core.App) error {
+		return txApp.Delete(record)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(traceIds) != 2 || traceIds[0] != "test_trace" || traceIds[1] != "test_trace" {
+		t.Fatalf("Expected the trace id to be propagated, got %v", traceIds)
+	}
+}
+
+func TestLambdaTracedHTTPClient(t *testing.T) {
+	var traceparents []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparents = append(traceparents, r.Header.Get(core.TraceparentHeader))
+	}))
+	defer server.Close()
+
+	vm := goja.New()
+	baseBinds(vm)
+	httpClientBinds(vm)
+	bindTracedHTTPClient(vm)
+
+	vm.Set("testURL", server.URL)
+
+	// without trace
+	if _, err := vm.RunString(`$http.send({ url: testURL })`); err != nil {
+		t.Fatal(err)
+	}
+
+	traceId := core.NewTraceId()
+	vm.Set("$trace", map[string]any{"id": traceId})
+
+	// with trace
+	_, err := vm.RunString(`
+		const params = { url: testURL, headers: { "x-test": "1" } };
+		$http.send(params);
+		if (params.headers.traceparent) {
+			throw new Error("the user params must not be modified");
+		}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// with explicit traceparent
+	if _, err := vm.RunString(`$http.send({ url: testURL, headers: { "Traceparent": "custom" } })`); err != nil {
+		t.Fatal(err)
+	}
+
+	// invalid send params
+	if _, err := vm.RunString(`$http.send({ url: "" })`); err == nil {
+		t.Fatal("Expected the send error to be propagated")
+	}
+
+	if len(traceparents) != 3 {
+		t.Fatalf("Expected 3 requests, got %d", len(traceparents))
+	}
+
+	if traceparents[0] != "" {
+		t.Fatalf("Expected no traceparent header, got %q", traceparents[0])
+	}
+
+	if id := core.ParseTraceparent(traceparents[1]); id != traceId {
+		t.Fatalf("Expected traceparent with trace id %q, got %q", traceId, traceparents[1])
+	}
+
+	if traceparents[2] != "custom" {
+		t.Fatalf("Expected the explicit traceparent header, got %q", traceparents[2])
+	}
+}
+
+func TestRequestTraceId(t *testing.T) {
+	const traceId = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+	// from context
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req = req.WithContext(core.ContextWithTraceId(req.Context(), "ctx_trace"))
+	req.Header.Set(core.TraceparentHeader, "00-"+traceId+"-00f067aa0ba902b7-01")
+	if id := requestTraceId(req); id != "ctx_trace" {
+		t.Fatalf("Expected the context trace id, got %q", id)
+	}
+
+	// from header
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(core.TraceparentHeader, "00-"+traceId+"-00f067aa0ba902b7-01")
+	if id := requestTraceId(req); id != traceId {
+		t.Fatalf("Expected the header trace id, got %q", id)
+	}
+
+	// new
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	if id := requestTraceId(req); len(id) != 32 {
+		t.Fatalf("Expected new trace id, got %q", id)
+	}
+}
@@ -50,6 +50,7 @@ migrate((app) => {
     "fields": [
       {
         "autogeneratePattern": "[a-z0-9]{15}",
+        "encrypted": false,
         "hidden": false,
         "id": "text@TEST_RANDOM",
         "max": 15,
@@ -77,6 +78,7 @@ migrate((app) => {
       },
       {
         "autogeneratePattern": "[a-zA-Z0-9]{50}",
+        "encrypted": false,
         "hidden": true,
         "id": "text@TEST_RANDOM",
         "max": 60,
@@ -226,6 +228,7 @@ func init() {
 			"fields": [
 				{
 					"autogeneratePattern": "[a-z0-9]{15}",
+					"encrypted": false,
 					"hidden": false,
 					"id": "text@TEST_RANDOM",
 					"max": 15,
@@ -253,6 +256,7 @@ func init() {
 				},
 				{
 					"autogeneratePattern": "[a-zA-Z0-9]{50}",
+					"encrypted": false,
 					"hidden": true,
 					"id": "text@TEST_RANDOM",
 					"max": 60,
@@ -491,6 +495,7 @@ migrate((app) => {
     "fields": [
       {
         "autogeneratePattern": "[a-z0-9]{15}",
+        "encrypted": false,
         "hidden": false,
         "id": "text@TEST_RANDOM",
         "max": 15,
@@ -518,6 +523,7 @@ migrate((app) => {
       },
       {
         "autogeneratePattern": "[a-zA-Z0-9]{50}",
+        "encrypted": false,
         "hidden": true,
         "id": "text@TEST_RANDOM",
         "max": 60,
@@ -670,6 +676,7 @@ func init() {
 			"fields": [
 				{
 					"autogeneratePattern": "[a-z0-9]{15}",
+					"encrypted": false,
 					"hidden": false,
 					"id": "text@TEST_RANDOM",
 					"max": 15,
@@ -697,6 +704,7 @@ func init() {
 				},
 				{
 					"autogeneratePattern": "[a-zA-Z0-9]{50}",
+					"encrypted": false,
 					"hidden": true,
 					"id": "text@TEST_RANDOM",
 					"max": 60,
@@ -923,6 +931,7 @@ migrate((app) => {
   // add field
   collection.fields.addAt(8, new Field({
     "autogeneratePattern": "",
+    "encrypted": false,
     "hidden": false,
     "id": "f4_id",
     "max": 0,
@@ -1054,6 +1063,7 @@ func init() {
 		// add field
 		if err := collection.Fields.AddMarshaledJSONAt(8, []byte(` + "`" + `{
 			"autogeneratePattern": "",
+			"encrypted": false,
 			"hidden": false,
 			"id": "f4_id",
 			"max": 0,
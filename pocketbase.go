@@ -25,6 +25,10 @@ var _ core.App = (*PocketBase)(nil)
 // Version of PocketBase
 var Version = "(untracked)"
 
+// EnvironmentEnvVar is the name of the env variable used as
+// default value for the --env flag.
+const EnvironmentEnvVar = "PB_ENV"
+
 // PocketBase defines a PocketBase app launcher.
 //
 // It implements [core.App] via embedding and all of the app interface methods
@@ -35,6 +39,7 @@ type PocketBase struct {
 	devFlag           bool
 	dataDirFlag       string
 	encryptionEnvFlag string
+	environmentFlag   string
 	queryTimeout      int
 	hideStartBanner   bool
 
@@ -51,6 +56,7 @@ type Config struct {
 	DefaultDev           bool
 	DefaultDataDir       string // if not set, it will fallback to "./pb_data"
 	DefaultEncryptionEnv string
+	DefaultEnvironment   string        // if not set, it will fallback to the PB_ENV env variable
 	DefaultQueryTimeout  time.Duration // default to core.DefaultQueryTimeout (in seconds)
 
 	// optional DB configurations
@@ -95,6 +101,10 @@ func NewWithConfig(config Config) *PocketBase {
 		config.DefaultQueryTimeout = core.DefaultQueryTimeout
 	}
 
+	if config.DefaultEnvironment == "" {
+		config.DefaultEnvironment = os.Getenv(EnvironmentEnvVar)
+	}
+
 	executableName := filepath.Base(os.Args[0])
 
 	pb := &PocketBase{
@@ -113,6 +123,7 @@ func NewWithConfig(config Config) *PocketBase {
 		devFlag:           config.DefaultDev,
 		dataDirFlag:       config.DefaultDataDir,
 		encryptionEnvFlag: config.DefaultEncryptionEnv,
+		environmentFlag:   config.DefaultEnvironment,
 		hideStartBanner:   config.HideStartBanner,
 	}
 
@@ -128,6 +139,7 @@ func NewWithConfig(config Config) *PocketBase {
 		IsDev:            pb.devFlag,
 		DataDir:          pb.dataDirFlag,
 		EncryptionEnv:    pb.encryptionEnvFlag,
+		Environment:      pb.environmentFlag,
 		QueryTimeout:     time.Duration(pb.queryTimeout) * time.Second,
 		DataMaxOpenConns: config.DataMaxOpenConns,
 		DataMaxIdleConns: config.DataMaxIdleConns,
@@ -165,6 +177,10 @@ func NewWithConfig(config Config) *PocketBase {
 func (pb *PocketBase) Start() error {
 	// register system commands
 	pb.RootCmd.AddCommand(cmd.NewSuperuserCommand(pb))
+	pb.RootCmd.AddCommand(cmd.NewEncryptionCommand(pb))
+	pb.RootCmd.AddCommand(cmd.NewImportCommand(pb))
+	pb.RootCmd.AddCommand(cmd.NewExportCommand(pb))
+	pb.RootCmd.AddCommand(cmd.NewSchemaCommand(pb))
 	pb.RootCmd.AddCommand(cmd.NewServeCommand(pb, !pb.hideStartBanner))
 
 	return pb.Execute()
@@ -228,6 +244,13 @@ func (pb *PocketBase) eagerParseFlags(config *Config) error {
 		"the env variable whose value of 32 characters will be used \nas encryption key for the app settings (default none)",
 	)
 
+	pb.RootCmd.PersistentFlags().StringVar(
+		&pb.environmentFlag,
+		"env",
+		config.DefaultEnvironment,
+		"the app environment name, eg. staging (default \"development\" with --dev, otherwise \"production\")",
+	)
+
 	pb.RootCmd.PersistentFlags().BoolVar(
 		&pb.devFlag,
 		"dev",
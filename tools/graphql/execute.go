@@ -0,0 +1,910 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Request defines a single GraphQL execution request.
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+
+	// MaxDepth is the max allowed fields nesting (0 means no limit).
+	//
+	// The introspection fields are excluded from the depth calculation.
+	MaxDepth int `json:"-"`
+
+	// MaxComplexity is the max allowed total fields complexity (0 means no limit).
+	//
+	// The introspection fields are excluded from the complexity calculation.
+	MaxComplexity int `json:"-"`
+
+	// QueryOnly disallows the execution of mutation operations
+	// (eg. for GET requests).
+	QueryOnly bool `json:"-"`
+}
+
+// Result defines a GraphQL execution result.
+type Result struct {
+	// Data is the operation result (nil if the execution hasn't started or failed).
+	Data *OrderedMap
+
+	Errors []*Error
+
+	// executed indicates whether the execution has started
+	// and the "data" key must be present in the response.
+	executed bool
+}
+
+// MarshalJSON implements the [json.Marshaler] interface.
+func (r *Result) MarshalJSON() ([]byte, error) {
+	out := NewOrderedMap()
+
+	if len(r.Errors) > 0 {
+		out.Set("errors", r.Errors)
+	}
+
+	if r.executed {
+		if r.Data == nil {
+			out.Set("data", nil)
+		} else {
+			out.Set("data", r.Data)
+		}
+	}
+
+	return json.Marshal(out)
+}
+
+// Error defines a single GraphQL result error.
+type Error struct {
+	Message    string         `json:"message"`
+	Path       []any          `json:"path,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
+// Error implements the [error] interface.
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// NewError creates a new GraphQL error with optional extensions.
+func NewError(message string, extensions map[string]any) *Error {
+	return &Error{Message: message, Extensions: extensions}
+}
+
+// OrderedMap is a simple string keyed map that preserves
+// the insertion order when serialized to JSON.
+type OrderedMap struct {
+	keys   []string
+	values map[string]any
+}
+
+// NewOrderedMap creates a new empty OrderedMap.
+func NewOrderedMap() *OrderedMap {
+	return &OrderedMap{values: map[string]any{}}
+}
+
+// Set sets a single map value (appending the key if missing).
+func (m *OrderedMap) Set(key string, value any) {
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// Get returns a single map value.
+func (m *OrderedMap) Get(key string) any {
+	return m.values[key]
+}
+
+// Keys returns the map keys in their insertion order.
+func (m *OrderedMap) Keys() []string {
+	return slices.Clone(m.keys)
+}
+
+// MarshalJSON implements the [json.Marshaler] interface.
+func (m *OrderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+	for i, k := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+
+		value, err := json.Marshal(m.values[k])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// Execute parses, validates and executes the provided GraphQL request.
+func (s *Schema) Execute(ctx context.Context, req Request) *Result {
+	result := &Result{}
+
+	doc, err := Parse(req.Query)
+	if err != nil {
+		result.Errors = append(result.Errors, &Error{Message: "Syntax error: " + err.Error()})
+		return result
+	}
+
+	op, err := selectOperation(doc, req.OperationName)
+	if err != nil {
+		result.Errors = append(result.Errors, &Error{Message: err.Error()})
+		return result
+	}
+
+	var root *Object
+	switch op.Operation {
+	case "query":
+		root = s.query
+	case "mutation":
+		if req.QueryOnly {
+			return errorResult("Mutations are not allowed for this request.")
+		}
+		if s.mutation == nil {
+			return errorResult("The schema doesn't support mutations.")
+		}
+		root = s.mutation
+	default:
+		return errorResult(fmt.Sprintf("Unsupported %s operation.", op.Operation))
+	}
+
+	vars, err := s.coerceVariables(op.Variables, req.Variables)
+	if err != nil {
+		return errorResult(err.Error())
+	}
+
+	p := &preparer{schema: s, doc: doc, vars: vars}
+
+	fields, err := p.prepareSelectionSet(root, op.SelectionSet, op.Operation == "query")
+	if err != nil {
+		return errorResult(err.Error())
+	}
+
+	if req.MaxDepth > 0 {
+		if depth := selectionDepth(fields); depth > req.MaxDepth {
+			return errorResult(fmt.Sprintf("The query depth %d exceeds the max allowed depth of %d.", depth, req.MaxDepth))
+		}
+	}
+
+	if req.MaxComplexity > 0 {
+		if complexity := selectionComplexity(fields); complexity > req.MaxComplexity {
+			return errorResult(fmt.Sprintf("The query complexity %d exceeds the max allowed complexity of %d.", complexity, req.MaxComplexity))
+		}
+	}
+
+	e := &executor{ctx: ctx}
+
+	result.executed = true
+	result.Data, _ = e.executeSelectionSet(root, nil, fields, nil)
+	result.Errors = e.errors
+
+	return result
+}
+
+func errorResult(message string) *Result {
+	return &Result{Errors: []*Error{{Message: message}}}
+}
+
+func selectOperation(doc *Document, name string) (*OperationDefinition, error) {
+	if name == "" {
+		if len(doc.Operations) > 1 {
+			return nil, errors.New("Must provide operation name if query contains multiple operations.")
+		}
+		return doc.Operations[0], nil
+	}
+
+	for _, op := range doc.Operations {
+		if op.Name == name {
+			return op, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Unknown operation named %q.", name)
+}
+
+// Limits
+// -------------------------------------------------------------------
+
+func selectionDepth(fields []*SelectedField) int {
+	var max int
+
+	for _, f := range fields {
+		if strings.HasPrefix(f.Name, "__") {
+			continue
+		}
+
+		if d := 1 + selectionDepth(f.SelectionSet); d > max {
+			max = d
+		}
+	}
+
+	return max
+}
+
+func selectionComplexity(fields []*SelectedField) int {
+	var total int
+
+	for _, f := range fields {
+		if strings.HasPrefix(f.Name, "__") {
+			continue
+		}
+
+		child := selectionComplexity(f.SelectionSet)
+
+		if f.def != nil && f.def.Complexity != nil {
+			total += f.def.Complexity(child, f.Args)
+		} else {
+			total += 1 + child
+		}
+	}
+
+	return total
+}
+
+// Variables and arguments coercion
+// -------------------------------------------------------------------
+
+func (s *Schema) resolveTypeNode(node *TypeNode) (Type, error) {
+	var t Type
+
+	if node.OfType != nil {
+		of, err := s.resolveTypeNode(node.OfType)
+		if err != nil {
+			return nil, err
+		}
+		t = NewList(of)
+	} else {
+		t = s.Type(node.Name)
+		if t == nil {
+			return nil, fmt.Errorf("Unknown type %q.", node.Name)
+		}
+	}
+
+	if node.NonNull {
+		t = NewNonNull(t)
+	}
+
+	return t, nil
+}
+
+func (s *Schema) coerceVariables(defs []*VariableDefinition, raw map[string]any) (map[string]any, error) {
+	result := make(map[string]any, len(defs))
+
+	for _, def := range defs {
+		t, err := s.resolveTypeNode(def.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		if !isInputType(t) {
+			return nil, fmt.Errorf("Variable \"$%s\" cannot be non-input type %q.", def.Name, def.Type.String())
+		}
+
+		value, ok := raw[def.Name]
+		if !ok {
+			if def.HasDefault {
+				v, err := coerceInput(t, def.DefaultValue, nil, true)
+				if err != nil {
+					return nil, fmt.Errorf("Variable \"$%s\" has invalid default value: %w", def.Name, err)
+				}
+				result[def.Name] = v
+			} else if _, isNonNull := t.(*NonNull); isNonNull {
+				return nil, fmt.Errorf("Variable \"$%s\" of required type %q was not provided.", def.Name, def.Type.String())
+			}
+			continue
+		}
+
+		v, err := coerceInput(t, value, nil, false)
+		if err != nil {
+			return nil, fmt.Errorf("Variable \"$%s\" got invalid value: %w", def.Name, err)
+		}
+		result[def.Name] = v
+	}
+
+	return result, nil
+}
+
+// coerceInput coerces the provided input value according to the specified type.
+//
+// If literal is true, the value is expected to be an AST literal
+// (with possible variable references that are resolved from vars).
+func coerceInput(t Type, value any, vars map[string]any, literal bool) (any, error) {
+	if name, ok := value.(Variable); ok && literal {
+		v, exists := vars[string(name)]
+		if _, isNonNull := t.(*NonNull); isNonNull && (!exists || v == nil) {
+			return nil, fmt.Errorf("expected non-null value for variable \"$%s\"", name)
+		}
+		return v, nil
+	}
+
+	switch v := t.(type) {
+	case *NonNull:
+		if value == nil {
+			return nil, fmt.Errorf("expected non-null %s value", v.OfType)
+		}
+		return coerceInput(v.OfType, value, vars, literal)
+	}
+
+	if value == nil {
+		return nil, nil
+	}
+
+	switch v := t.(type) {
+	case *List:
+		items, ok := value.([]any)
+		if !ok {
+			item, err := coerceInput(v.OfType, value, vars, literal)
+			if err != nil {
+				return nil, err
+			}
+			return []any{item}, nil
+		}
+
+		result := make([]any, len(items))
+		for i, item := range items {
+			coerced, err := coerceInput(v.OfType, item, vars, literal)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			result[i] = coerced
+		}
+		return result, nil
+	case *InputObject:
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("expected %s object value", v.Name)
+		}
+
+		result := make(map[string]any, len(v.Fields))
+
+		for key := range obj {
+			if !slices.ContainsFunc(v.Fields, func(f *Argument) bool { return f.Name == key }) {
+				return nil, fmt.Errorf("field %q is not defined by type %s", key, v.Name)
+			}
+		}
+
+		for _, f := range v.Fields {
+			raw, exists := obj[f.Name]
+			if literal && exists {
+				if name, ok := raw.(Variable); ok {
+					_, exists = vars[string(name)]
+				}
+			}
+
+			if !exists {
+				if f.DefaultValue != nil {
+					result[f.Name] = f.DefaultValue
+				} else if _, isNonNull := f.Type.(*NonNull); isNonNull {
+					return nil, fmt.Errorf("field %s.%s of required type %s was not provided", v.Name, f.Name, f.Type)
+				}
+				continue
+			}
+
+			coerced, err := coerceInput(f.Type, raw, vars, literal)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", v.Name, f.Name, err)
+			}
+			result[f.Name] = coerced
+		}
+
+		return result, nil
+	case *Enum:
+		var str string
+		if literal {
+			enum, ok := value.(EnumValue)
+			if !ok {
+				return nil, fmt.Errorf("enum %s cannot represent non-enum value: %v", v.Name, value)
+			}
+			str = string(enum)
+		} else {
+			s, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("enum %s cannot represent non-string value: %v", v.Name, value)
+			}
+			str = s
+		}
+
+		if !slices.Contains(v.Values, str) {
+			return nil, fmt.Errorf("value %q does not exist in %s enum", str, v.Name)
+		}
+		return str, nil
+	case *Scalar:
+		if literal {
+			if _, ok := value.(EnumValue); ok && isBuiltinScalar(v) {
+				return nil, fmt.Errorf("%s cannot represent an enum value: %v", v.Name, value)
+			}
+			value = resolveLiteralVariables(value, vars)
+		}
+		if v.ParseValue == nil {
+			return value, nil
+		}
+		return v.ParseValue(value)
+	}
+
+	return nil, fmt.Errorf("%s is not an input type", t)
+}
+
+// resolveLiteralVariables replaces the nested variable and enum
+// literals with their plain values (used for custom scalar literals).
+func resolveLiteralVariables(value any, vars map[string]any) any {
+	switch v := value.(type) {
+	case Variable:
+		return vars[string(v)]
+	case EnumValue:
+		return string(v)
+	case []any:
+		result := make([]any, len(v))
+		for i, item := range v {
+			result[i] = resolveLiteralVariables(item, vars)
+		}
+		return result
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for k, item := range v {
+			result[k] = resolveLiteralVariables(item, vars)
+		}
+		return result
+	}
+
+	return value
+}
+
+func isBuiltinScalar(t *Scalar) bool {
+	return t == String || t == Int || t == Float || t == Boolean || t == ID
+}
+
+// Selection preparation (validation)
+// -------------------------------------------------------------------
+
+type preparer struct {
+	schema *Schema
+	doc    *Document
+	vars   map[string]any
+}
+
+type fieldGroup struct {
+	key   string
+	nodes []*FieldNode
+}
+
+func (p *preparer) prepareSelectionSet(obj *Object, set []Selection, isQueryRoot bool) ([]*SelectedField, error) {
+	var groups []*fieldGroup
+
+	if err := p.collectFields(obj, set, &groups, map[string]bool{}); err != nil {
+		return nil, err
+	}
+
+	result := make([]*SelectedField, 0, len(groups))
+
+	for _, group := range groups {
+		first := group.nodes[0]
+
+		def := p.fieldDefinition(obj, first.Name, isQueryRoot)
+		if def == nil {
+			return nil, fmt.Errorf("Cannot query field %q on type %q.", first.Name, obj.Name)
+		}
+
+		args, err := p.coerceArguments(obj.Name+"."+def.Name, def.Args, first.Arguments)
+		if err != nil {
+			return nil, err
+		}
+
+		var subset []Selection
+		for _, node := range group.nodes {
+			if node.Name != first.Name {
+				return nil, fmt.Errorf("Fields %q conflict because %q and %q are different fields.", group.key, first.Name, node.Name)
+			}
+
+			if node != first {
+				nodeArgs, err := p.coerceArguments(obj.Name+"."+def.Name, def.Args, node.Arguments)
+				if err != nil {
+					return nil, err
+				}
+				if !reflect.DeepEqual(args, nodeArgs) {
+					return nil, fmt.Errorf("Fields %q conflict because they have differing arguments.", group.key)
+				}
+			}
+
+			subset = append(subset, node.SelectionSet...)
+		}
+
+		selected := &SelectedField{
+			Alias: first.Alias,
+			Name:  first.Name,
+			Args:  args,
+			def:   def,
+		}
+
+		childObj, isObj := NamedType(def.Type).(*Object)
+		if isObj {
+			if len(subset) == 0 {
+				return nil, fmt.Errorf("Field %q of type %q must have a selection of subfields.", first.Name, def.Type)
+			}
+			selected.SelectionSet, err = p.prepareSelectionSet(childObj, subset, false)
+			if err != nil {
+				return nil, err
+			}
+		} else if len(subset) > 0 {
+			return nil, fmt.Errorf("Field %q must not have a selection since type %q has no subfields.", first.Name, def.Type)
+		}
+
+		result = append(result, selected)
+	}
+
+	return result, nil
+}
+
+func (p *preparer) collectFields(obj *Object, set []Selection, groups *[]*fieldGroup, visited map[string]bool) error {
+	for _, selection := range set {
+		switch node := selection.(type) {
+		case *FieldNode:
+			include, err := p.shouldInclude(node.Directives)
+			if err != nil {
+				return err
+			}
+			if !include {
+				continue
+			}
+
+			key := node.ResponseKey()
+
+			idx := slices.IndexFunc(*groups, func(g *fieldGroup) bool { return g.key == key })
+			if idx >= 0 {
+				(*groups)[idx].nodes = append((*groups)[idx].nodes, node)
+			} else {
+				*groups = append(*groups, &fieldGroup{key: key, nodes: []*FieldNode{node}})
+			}
+		case *InlineFragmentNode:
+			include, err := p.shouldInclude(node.Directives)
+			if err != nil {
+				return err
+			}
+			if !include {
+				continue
+			}
+
+			if err := p.checkTypeCondition(obj, node.TypeCondition); err != nil {
+				return err
+			}
+
+			if err := p.collectFields(obj, node.SelectionSet, groups, visited); err != nil {
+				return err
+			}
+		case *FragmentSpreadNode:
+			include, err := p.shouldInclude(node.Directives)
+			if err != nil {
+				return err
+			}
+			if !include {
+				continue
+			}
+
+			if visited[node.Name] {
+				return fmt.Errorf("Cannot spread fragment %q within itself.", node.Name)
+			}
+
+			fragment, ok := p.doc.Fragments[node.Name]
+			if !ok {
+				return fmt.Errorf("Unknown fragment %q.", node.Name)
+			}
+
+			if err := p.checkTypeCondition(obj, fragment.TypeCondition); err != nil {
+				return err
+			}
+
+			visited[node.Name] = true
+			err = p.collectFields(obj, fragment.SelectionSet, groups, visited)
+			delete(visited, node.Name)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (p *preparer) checkTypeCondition(obj *Object, condition string) error {
+	if condition == "" || condition == obj.Name {
+		return nil
+	}
+
+	if p.schema.Type(condition) == nil {
+		return fmt.Errorf("Unknown type %q.", condition)
+	}
+
+	return fmt.Errorf("Fragment cannot be spread here as objects of type %q can never be of type %q.", obj.Name, condition)
+}
+
+func (p *preparer) shouldInclude(directives []*DirectiveNode) (bool, error) {
+	for _, d := range directives {
+		if d.Name != "skip" && d.Name != "include" {
+			return false, fmt.Errorf("Unknown directive \"@%s\".", d.Name)
+		}
+
+		args, err := p.coerceArguments("@"+d.Name, []*Argument{{Name: "if", Type: NewNonNull(Boolean)}}, d.Arguments)
+		if err != nil {
+			return false, err
+		}
+
+		value, _ := args["if"].(bool)
+		if (d.Name == "skip" && value) || (d.Name == "include" && !value) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (p *preparer) coerceArguments(owner string, defs []*Argument, nodes []*ArgumentNode) (map[string]any, error) {
+	result := make(map[string]any, len(defs))
+
+	for _, node := range nodes {
+		if !slices.ContainsFunc(defs, func(a *Argument) bool { return a.Name == node.Name }) {
+			return nil, fmt.Errorf("Unknown argument %q on %q.", node.Name, owner)
+		}
+	}
+
+	for _, def := range defs {
+		idx := slices.IndexFunc(nodes, func(n *ArgumentNode) bool { return n.Name == def.Name })
+
+		exists := idx >= 0
+		if exists {
+			if name, ok := nodes[idx].Value.(Variable); ok {
+				_, exists = p.vars[string(name)]
+			}
+		}
+
+		if !exists {
+			if def.DefaultValue != nil {
+				result[def.Name] = def.DefaultValue
+			} else if _, isNonNull := def.Type.(*NonNull); isNonNull {
+				return nil, fmt.Errorf("Argument %q of required type %q was not provided for %q.", def.Name, def.Type, owner)
+			}
+			continue
+		}
+
+		value, err := coerceInput(def.Type, nodes[idx].Value, p.vars, true)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid %q argument value for %q: %w.", def.Name, owner, err)
+		}
+		result[def.Name] = value
+	}
+
+	return result, nil
+}
+
+func (p *preparer) fieldDefinition(obj *Object, name string, isQueryRoot bool) *Field {
+	switch name {
+	case "__typename":
+		return typenameField
+	case "__schema":
+		if isQueryRoot {
+			return p.schemaField()
+		}
+		return nil
+	case "__type":
+		if isQueryRoot {
+			return p.typeField()
+		}
+		return nil
+	}
+
+	return obj.Field(name)
+}
+
+// Execution
+// -------------------------------------------------------------------
+
+type executor struct {
+	ctx    context.Context
+	errors []*Error
+}
+
+// getter is implemented by sources with a generic value getter (eg. core.Record).
+type getter interface {
+	Get(key string) any
+}
+
+func defaultResolve(p ResolveParams) (any, error) {
+	switch v := p.Source.(type) {
+	case map[string]any:
+		return v[p.Info.FieldName], nil
+	case getter:
+		return v.Get(p.Info.FieldName), nil
+	}
+
+	return nil, nil
+}
+
+func (e *executor) addError(err error, path []any) {
+	gqlErr := &Error{}
+
+	var custom *Error
+	if errors.As(err, &custom) {
+		gqlErr.Message = custom.Message
+		gqlErr.Extensions = custom.Extensions
+	} else {
+		gqlErr.Message = err.Error()
+	}
+
+	gqlErr.Path = slices.Clone(path)
+
+	e.errors = append(e.errors, gqlErr)
+}
+
+// executeSelectionSet executes the prepared selection set for the specified source.
+//
+// It returns false if a non-null field failed to resolve and the
+// null value must be propagated to the parent.
+func (e *executor) executeSelectionSet(obj *Object, source any, fields []*SelectedField, path []any) (*OrderedMap, bool) {
+	result := NewOrderedMap()
+
+	for _, f := range fields {
+		fieldPath := append(slices.Clone(path), f.ResponseKey())
+
+		if f.Name == "__typename" {
+			result.Set(f.ResponseKey(), obj.Name)
+			continue
+		}
+
+		var value any
+		var err error
+
+		if ctxErr := e.ctx.Err(); ctxErr != nil {
+			err = ctxErr
+		} else {
+			resolve := f.def.Resolve
+			if resolve == nil {
+				resolve = defaultResolve
+			}
+
+			value, err = e.safeResolve(resolve, ResolveParams{
+				Context: e.ctx,
+				Source:  source,
+				Args:    f.Args,
+				Info: ResolveInfo{
+					FieldName:    f.Name,
+					Path:         fieldPath,
+					ParentType:   obj,
+					ReturnType:   f.def.Type,
+					SelectionSet: f.SelectionSet,
+				},
+			})
+		}
+
+		if err != nil {
+			e.addError(err, fieldPath)
+			value = nil
+			if _, isNonNull := f.def.Type.(*NonNull); isNonNull {
+				return nil, false
+			}
+			result.Set(f.ResponseKey(), nil)
+			continue
+		}
+
+		completed, ok := e.completeValue(f.def.Type, f.SelectionSet, value, fieldPath)
+		if !ok {
+			if _, isNonNull := f.def.Type.(*NonNull); isNonNull {
+				return nil, false
+			}
+			completed = nil
+		}
+
+		result.Set(f.ResponseKey(), completed)
+	}
+
+	return result, true
+}
+
+func (e *executor) safeResolve(resolve ResolveFunc, p ResolveParams) (value any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to resolve field %q: %v", p.Info.FieldName, r)
+		}
+	}()
+
+	return resolve(p)
+}
+
+func (e *executor) completeValue(t Type, fields []*SelectedField, value any, path []any) (any, bool) {
+	if nonNull, ok := t.(*NonNull); ok {
+		completed, ok := e.completeValue(nonNull.OfType, fields, value, path)
+		if !ok {
+			return nil, false
+		}
+		if completed == nil {
+			e.addError(fmt.Errorf("Cannot return null for non-nullable field."), path)
+			return nil, false
+		}
+		return completed, true
+	}
+
+	if isNil(value) {
+		return nil, true
+	}
+
+	switch v := t.(type) {
+	case *List:
+		rv := reflect.ValueOf(value)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			e.addError(fmt.Errorf("Expected a list value, got %T.", value), path)
+			return nil, false
+		}
+
+		result := make([]any, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			item, ok := e.completeValue(v.OfType, fields, rv.Index(i).Interface(), append(slices.Clone(path), i))
+			if !ok {
+				if _, isNonNull := v.OfType.(*NonNull); isNonNull {
+					return nil, false
+				}
+				item = nil
+			}
+			result[i] = item
+		}
+		return result, true
+	case *Scalar:
+		if v.Serialize == nil {
+			return value, true
+		}
+		serialized, err := v.Serialize(value)
+		if err != nil {
+			e.addError(err, path)
+			return nil, false
+		}
+		return serialized, true
+	case *Enum:
+		str := fmt.Sprint(value)
+		if !slices.Contains(v.Values, str) {
+			e.addError(fmt.Errorf("Enum %q cannot represent value %q.", v.Name, str), path)
+			return nil, false
+		}
+		return str, true
+	case *Object:
+		obj, ok := e.executeSelectionSet(v, value, fields, path)
+		if !ok {
+			return nil, false
+		}
+		return obj, true
+	}
+
+	e.addError(fmt.Errorf("Unsupported output type %s.", t), path)
+	return nil, false
+}
+
+func isNil(value any) bool {
+	if value == nil {
+		return true
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+		return rv.IsNil()
+	}
+
+	return false
+}
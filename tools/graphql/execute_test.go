@@ -0,0 +1,479 @@
+package graphql_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/graphql"
+)
+
+type testAuthor struct {
+	data map[string]any
+}
+
+func (a *testAuthor) Get(key string) any {
+	return a.data[key]
+}
+
+func newTestSchema(t testing.TB) *graphql.Schema {
+	authors := map[string]*testAuthor{
+		"a1": {data: map[string]any{"id": "a1", "name": "Alice"}},
+		"a2": {data: map[string]any{"id": "a2", "name": "Bob"}},
+	}
+
+	posts := []map[string]any{
+		{"id": "p1", "title": "First", "status": "DRAFT", "views": 10, "author": "a1"},
+		{"id": "p2", "title": "Second", "status": "PUBLISHED", "views": 20.0, "author": "a2"},
+		{"id": "p3", "title": "Third", "status": "PUBLISHED", "views": nil, "author": "missing"},
+	}
+
+	statusEnum := &graphql.Enum{Name: "Status", Values: []string{"DRAFT", "PUBLISHED"}}
+
+	authorType := &graphql.Object{
+		Name: "Author",
+		Fields: []*graphql.Field{
+			{Name: "id", Type: graphql.NewNonNull(graphql.ID)},
+			{Name: "name", Type: graphql.String},
+		},
+	}
+
+	postType := &graphql.Object{
+		Name:        "Post",
+		Description: "A single blog post.",
+		Fields: []*graphql.Field{
+			{Name: "id", Type: graphql.NewNonNull(graphql.ID)},
+			{Name: "title", Type: graphql.String, Description: "The post title."},
+			{Name: "status", Type: statusEnum},
+			{Name: "views", Type: graphql.Int},
+			{
+				Name: "author",
+				Type: authorType,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					id := p.Source.(map[string]any)["author"].(string)
+					if a, ok := authors[id]; ok {
+						return a, nil
+					}
+					return nil, errors.New("author not found")
+				},
+			},
+			{
+				Name: "requiredAuthor",
+				Type: graphql.NewNonNull(authorType),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					id := p.Source.(map[string]any)["author"].(string)
+					if a, ok := authors[id]; ok {
+						return a, nil
+					}
+					return nil, nil
+				},
+			},
+		},
+	}
+
+	filterInput := &graphql.InputObject{
+		Name: "PostFilter",
+		Fields: []*graphql.Argument{
+			{Name: "status", Type: statusEnum},
+			{Name: "minViews", Type: graphql.Int, DefaultValue: 0},
+		},
+	}
+
+	query := &graphql.Object{
+		Name: "Query",
+		Fields: []*graphql.Field{
+			{
+				Name: "posts",
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(postType))),
+				Args: []*graphql.Argument{
+					{Name: "filter", Type: filterInput},
+					{Name: "limit", Type: graphql.Int, DefaultValue: 10},
+				},
+				Complexity: func(childComplexity int, args map[string]any) int {
+					return 1 + args["limit"].(int)*childComplexity
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					var result []map[string]any
+
+					filter, _ := p.Args["filter"].(map[string]any)
+					for _, post := range posts {
+						if filter != nil && filter["status"] != nil && filter["status"] != post["status"] {
+							continue
+						}
+						result = append(result, post)
+						if len(result) >= p.Args["limit"].(int) {
+							break
+						}
+					}
+
+					return result, nil
+				},
+			},
+			{
+				Name: "post",
+				Type: postType,
+				Args: []*graphql.Argument{{Name: "id", Type: graphql.NewNonNull(graphql.ID)}},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					for _, post := range posts {
+						if post["id"] == p.Args["id"] {
+							return post, nil
+						}
+					}
+					return nil, graphql.NewError("post not found", map[string]any{"status": 404})
+				},
+			},
+			{
+				Name: "panic",
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					panic("boom")
+				},
+			},
+			{
+				Name: "selection",
+				Type: graphql.NewList(graphql.String),
+				Args: []*graphql.Argument{{Name: "ids", Type: graphql.NewList(graphql.NewNonNull(graphql.ID))}},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Args["ids"], nil
+				},
+			},
+		},
+	}
+
+	mutation := &graphql.Object{
+		Name: "Mutation",
+		Fields: []*graphql.Field{
+			{
+				Name: "createPost",
+				Type: postType,
+				Args: []*graphql.Argument{{Name: "title", Type: graphql.NewNonNull(graphql.String)}},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					post := map[string]any{"id": "p" + strings.Repeat("x", len(posts)), "title": p.Args["title"], "author": "a1"}
+					posts = append(posts, post)
+					return post, nil
+				},
+			},
+		},
+	}
+
+	schema, err := graphql.NewSchema(query, mutation)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return schema
+}
+
+func TestSchemaValidation(t *testing.T) {
+	t.Parallel()
+
+	obj := func(name string, fields ...*graphql.Field) *graphql.Object {
+		return &graphql.Object{Name: name, Fields: fields}
+	}
+
+	scenarios := []struct {
+		name  string
+		query *graphql.Object
+	}{
+		{"nil query", nil},
+		{"no fields", obj("Query")},
+		{"invalid type name", obj("1Query", &graphql.Field{Name: "a", Type: graphql.String})},
+		{"reserved type name", obj("__Query", &graphql.Field{Name: "a", Type: graphql.String})},
+		{"invalid field name", obj("Query", &graphql.Field{Name: "a-b", Type: graphql.String})},
+		{"duplicated field", obj("Query", &graphql.Field{Name: "a", Type: graphql.String}, &graphql.Field{Name: "a", Type: graphql.String})},
+		{"missing field type", obj("Query", &graphql.Field{Name: "a"})},
+		{"input object as output", obj("Query", &graphql.Field{Name: "a", Type: &graphql.InputObject{Name: "In", Fields: []*graphql.Argument{{Name: "a", Type: graphql.String}}}})},
+		{"object as argument", obj("Query", &graphql.Field{Name: "a", Type: graphql.String, Args: []*graphql.Argument{{Name: "b", Type: obj("B", &graphql.Field{Name: "a", Type: graphql.String})}}})},
+		{"duplicated type name", obj("Query", &graphql.Field{Name: "a", Type: obj("B", &graphql.Field{Name: "a", Type: graphql.String})}, &graphql.Field{Name: "b", Type: obj("B", &graphql.Field{Name: "a", Type: graphql.String})})},
+		{"invalid enum value", obj("Query", &graphql.Field{Name: "a", Type: &graphql.Enum{Name: "E", Values: []string{"true"}}})},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			if _, err := graphql.NewSchema(s.query, nil); err == nil {
+				t.Fatal("Expected schema error")
+			}
+		})
+	}
+}
+
+func TestExecute(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name     string
+		request  graphql.Request
+		expected string
+	}{
+		{
+			"syntax error",
+			graphql.Request{Query: "{ posts "},
+			`{"errors":[{"message":"Syntax error: unexpected end of document"}]}`,
+		},
+		{
+			"unknown field",
+			graphql.Request{Query: "{ missing }"},
+			`{"errors":[{"message":"Cannot query field \"missing\" on type \"Query\"."}]}`,
+		},
+		{
+			"missing sub-selection",
+			graphql.Request{Query: "{ posts }"},
+			`{"errors":[{"message":"Field \"posts\" of type \"[Post!]!\" must have a selection of subfields."}]}`,
+		},
+		{
+			"leaf sub-selection",
+			graphql.Request{Query: "{ posts { title { a } } }"},
+			`{"errors":[{"message":"Field \"title\" must not have a selection since type \"String\" has no subfields."}]}`,
+		},
+		{
+			"multiple operations without name",
+			graphql.Request{Query: "query A { posts { id } } query B { posts { id } }"},
+			`{"errors":[{"message":"Must provide operation name if query contains multiple operations."}]}`,
+		},
+		{
+			"select operation by name",
+			graphql.Request{Query: "query A { posts { id } } query B { post(id: \"p2\") { id } }", OperationName: "B"},
+			`{"data":{"post":{"id":"p2"}}}`,
+		},
+		{
+			"missing required argument",
+			graphql.Request{Query: "{ post { id } }"},
+			`{"errors":[{"message":"Argument \"id\" of required type \"ID!\" was not provided for \"Query.post\"."}]}`,
+		},
+		{
+			"unknown argument",
+			graphql.Request{Query: `{ post(id: "p1", other: 1) { id } }`},
+			`{"errors":[{"message":"Unknown argument \"other\" on \"Query.post\"."}]}`,
+		},
+		{
+			"invalid enum argument",
+			graphql.Request{Query: `{ posts(filter: {status: "DRAFT"}) { id } }`},
+			`{"errors":[{"message":"Invalid \"filter\" argument value for \"Query.posts\": PostFilter.status: enum Status cannot represent non-enum value: DRAFT."}]}`,
+		},
+		{
+			"aliases, fragments, directives and typename",
+			graphql.Request{Query: `
+				{
+					drafts: posts(filter: {status: DRAFT}) { ...Fields }
+					published: posts(filter: {status: PUBLISHED}, limit: 1) {
+						id
+						... on Post { title }
+						status @skip(if: true)
+						views @include(if: false)
+					}
+				}
+				fragment Fields on Post { __typename id title author { name } }
+			`},
+			`{"data":{"drafts":[{"__typename":"Post","id":"p1","title":"First","author":{"name":"Alice"}}],"published":[{"id":"p2","title":"Second"}]}}`,
+		},
+		{
+			"merge same fields",
+			graphql.Request{Query: `{ post(id: "p1") { author { id } author { name } } }`},
+			`{"data":{"post":{"author":{"id":"a1","name":"Alice"}}}}`,
+		},
+		{
+			"conflicting fields",
+			graphql.Request{Query: `{ post(id: "p1") { title: id title } }`},
+			`{"errors":[{"message":"Fields \"title\" conflict because \"id\" and \"title\" are different fields."}]}`,
+		},
+		{
+			"fragment cycle",
+			graphql.Request{Query: `{ posts { ...A } } fragment A on Post { id ...B } fragment B on Post { ...A }`},
+			`{"errors":[{"message":"Cannot spread fragment \"A\" within itself."}]}`,
+		},
+		{
+			"fragment on different type",
+			graphql.Request{Query: `{ posts { ... on Author { id } } }`},
+			`{"errors":[{"message":"Fragment cannot be spread here as objects of type \"Post\" can never be of type \"Author\"."}]}`,
+		},
+		{
+			"unknown directive",
+			graphql.Request{Query: `{ posts { id @foo } }`},
+			`{"errors":[{"message":"Unknown directive \"@foo\"."}]}`,
+		},
+		{
+			"variables",
+			graphql.Request{
+				Query:     `query ($id: ID!, $status: Status, $ids: [ID!]) { post(id: $id) { id } posts(filter: {status: $status}) { id views } selection(ids: $ids) }`,
+				Variables: map[string]any{"id": "p1", "status": "PUBLISHED", "ids": "single"},
+			},
+			`{"data":{"post":{"id":"p1"},"posts":[{"id":"p2","views":20},{"id":"p3","views":null}],"selection":["single"]}}`,
+		},
+		{
+			"missing required variable",
+			graphql.Request{Query: `query ($id: ID!) { post(id: $id) { id } }`},
+			`{"errors":[{"message":"Variable \"$id\" of required type \"ID!\" was not provided."}]}`,
+		},
+		{
+			"invalid variable value",
+			graphql.Request{Query: `query ($limit: Int) { posts(limit: $limit) { id } }`, Variables: map[string]any{"limit": 1.5}},
+			`{"errors":[{"message":"Variable \"$limit\" got invalid value: Int cannot represent non 32-bit signed integer value: 1.5"}]}`,
+		},
+		{
+			"variable default value",
+			graphql.Request{Query: `query ($limit: Int = 1) { posts(limit: $limit) { id } }`},
+			`{"data":{"posts":[{"id":"p1"}]}}`,
+		},
+		{
+			"resolver error with extensions",
+			graphql.Request{Query: `{ post(id: "missing") { id } }`},
+			`{"errors":[{"message":"post not found","path":["post"],"extensions":{"status":404}}],"data":{"post":null}}`,
+		},
+		{
+			"nullable nested error",
+			graphql.Request{Query: `{ post(id: "p3") { id author { name } } }`},
+			`{"errors":[{"message":"author not found","path":["post","author"]}],"data":{"post":{"id":"p3","author":null}}}`,
+		},
+		{
+			"non-null propagation",
+			graphql.Request{Query: `{ posts { id requiredAuthor { name } } }`},
+			`{"errors":[{"message":"Cannot return null for non-nullable field.","path":["posts",2,"requiredAuthor"]}],"data":null}`,
+		},
+		{
+			"recovered panic",
+			graphql.Request{Query: `{ panic }`},
+			`{"errors":[{"message":"failed to resolve field \"panic\": boom","path":["panic"]}],"data":{"panic":null}}`,
+		},
+		{
+			"max depth",
+			graphql.Request{Query: `{ posts { author { name } } }`, MaxDepth: 2},
+			`{"errors":[{"message":"The query depth 3 exceeds the max allowed depth of 2."}]}`,
+		},
+		{
+			"max complexity",
+			graphql.Request{Query: `{ posts(limit: 5) { id title } }`, MaxComplexity: 10},
+			`{"errors":[{"message":"The query complexity 11 exceeds the max allowed complexity of 10."}]}`,
+		},
+		{
+			"introspection is excluded from the limits",
+			graphql.Request{Query: `{ __type(name: "Post") { fields { type { ofType { name } } } } }`, MaxDepth: 1, MaxComplexity: 1},
+			`{"data":{"__type":{"fields":[{"type":{"ofType":{"name":"ID"}}},{"type":{"ofType":null}},{"type":{"ofType":null}},{"type":{"ofType":null}},{"type":{"ofType":null}},{"type":{"ofType":{"name":"Author"}}}]}}}`,
+		},
+		{
+			"query only",
+			graphql.Request{Query: `mutation { createPost(title: "a") { id } }`, QueryOnly: true},
+			`{"errors":[{"message":"Mutations are not allowed for this request."}]}`,
+		},
+		{
+			"subscription",
+			graphql.Request{Query: `subscription { posts { id } }`},
+			`{"errors":[{"message":"Unsupported subscription operation."}]}`,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := newTestSchema(t).Execute(context.Background(), s.request)
+
+			raw, err := json.Marshal(result)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(raw) != s.expected {
+				t.Fatalf("Expected\n%s\ngot\n%s", s.expected, raw)
+			}
+		})
+	}
+}
+
+func TestExecuteMutationsSerially(t *testing.T) {
+	t.Parallel()
+
+	schema := newTestSchema(t)
+
+	result := schema.Execute(context.Background(), graphql.Request{
+		Query: `mutation { a: createPost(title: "A") { title } b: createPost(title: "B") { title author { name } } }`,
+	})
+
+	raw, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"data":{"a":{"title":"A"},"b":{"title":"B","author":{"name":"Alice"}}}}`
+	if string(raw) != expected {
+		t.Fatalf("Expected\n%s\ngot\n%s", expected, raw)
+	}
+
+	result = schema.Execute(context.Background(), graphql.Request{Query: `{ posts(limit: 100) { title } }`})
+	if posts := result.Data.Get("posts").([]any); len(posts) != 5 {
+		t.Fatalf("Expected 5 posts, got %d", len(posts))
+	}
+}
+
+func TestExecuteCanceledContext(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result := newTestSchema(t).Execute(ctx, graphql.Request{Query: `{ panic }`})
+
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Message, "canceled") {
+		t.Fatalf("Expected canceled context error, got %v", result.Errors)
+	}
+}
+
+func TestExecuteIntrospection(t *testing.T) {
+	t.Parallel()
+
+	result := newTestSchema(t).Execute(context.Background(), graphql.Request{Query: `
+		{
+			__schema {
+				queryType { name }
+				mutationType { name }
+				subscriptionType { name }
+				types { name kind }
+				directives { name args { name } }
+			}
+			post: __type(name: "Post") {
+				kind
+				description
+				fields { name description args { name defaultValue } type { kind name ofType { kind name } } }
+			}
+			filter: __type(name: "PostFilter") { kind inputFields { name defaultValue type { name } } }
+			status: __type(name: "Status") { kind enumValues { name } }
+			missing: __type(name: "Missing") { name }
+		}
+	`})
+
+	if len(result.Errors) > 0 {
+		t.Fatalf("Unexpected errors %v", result.Errors[0])
+	}
+
+	raw, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedParts := []string{
+		`"queryType":{"name":"Query"},"mutationType":{"name":"Mutation"},"subscriptionType":null`,
+		`{"name":"String","kind":"SCALAR"}`,
+		`{"name":"Post","kind":"OBJECT"}`,
+		`{"name":"PostFilter","kind":"INPUT_OBJECT"}`,
+		`{"name":"Status","kind":"ENUM"}`,
+		`{"name":"__Schema","kind":"OBJECT"}`,
+		`{"name":"include","args":[{"name":"if"}]}`,
+		`"post":{"kind":"OBJECT","description":"A single blog post."`,
+		`{"name":"id","description":null,"args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"ID"}}}`,
+		`{"name":"title","description":"The post title.","args":[],"type":{"kind":"SCALAR","name":"String","ofType":null}}`,
+		`"filter":{"kind":"INPUT_OBJECT","inputFields":[{"name":"status","defaultValue":null,"type":{"name":"Status"}},{"name":"minViews","defaultValue":"0","type":{"name":"Int"}}]}`,
+		`"status":{"kind":"ENUM","enumValues":[{"name":"DRAFT"},{"name":"PUBLISHED"}]}`,
+		`"missing":null`,
+	}
+
+	for _, part := range expectedParts {
+		if !strings.Contains(string(raw), part) {
+			t.Fatalf("Missing expected part\n%s\nin\n%s", part, raw)
+		}
+	}
+
+	// __schema and __type are allowed only at the query root
+	result = newTestSchema(t).Execute(context.Background(), graphql.Request{Query: `{ posts { __schema { queryType { name } } } }`})
+	if len(result.Errors) != 1 {
+		t.Fatalf("Expected nested __schema error, got %v", result.Errors)
+	}
+}
@@ -0,0 +1,290 @@
+package graphql
+
+import (
+	"errors"
+)
+
+// introspectionTypes holds the built-in introspection types.
+var introspectionTypes = map[string]Type{}
+
+var (
+	typeKindEnum = &Enum{
+		Name:        "__TypeKind",
+		Description: "An enum describing what kind of type a given `__Type` is.",
+		Values:      []string{"SCALAR", "OBJECT", "INTERFACE", "UNION", "ENUM", "INPUT_OBJECT", "LIST", "NON_NULL"},
+	}
+
+	directiveLocationEnum = &Enum{
+		Name:        "__DirectiveLocation",
+		Description: "A Directive can be adjacent to many parts of the GraphQL language.",
+		Values:      []string{"QUERY", "MUTATION", "SUBSCRIPTION", "FIELD", "FRAGMENT_DEFINITION", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"},
+	}
+
+	schemaType       = &Object{Name: "__Schema", Description: "A GraphQL Schema defines the capabilities of a GraphQL server."}
+	typeType         = &Object{Name: "__Type", Description: "The fundamental unit of any GraphQL Schema is the type."}
+	fieldType        = &Object{Name: "__Field", Description: "Object and Interface types are described by a list of Fields."}
+	inputValueType   = &Object{Name: "__InputValue", Description: "Arguments provided to Fields or Directives and the input fields of an InputObject."}
+	enumValueType    = &Object{Name: "__EnumValue", Description: "One possible value for a given Enum."}
+	directiveType    = &Object{Name: "__Directive", Description: "A Directive provides a way to describe alternate runtime execution in GraphQL."}
+	typenameField    = &Field{Name: "__typename", Type: NewNonNull(String)}
+	builtinDirective = []*directive{
+		{
+			name:        "include",
+			description: "Directs the executor to include this field or fragment only when the `if` argument is true.",
+		},
+		{
+			name:        "skip",
+			description: "Directs the executor to skip this field or fragment when the `if` argument is true.",
+		},
+	}
+)
+
+type directive struct {
+	name        string
+	description string
+}
+
+type enumValue struct {
+	name string
+}
+
+func init() {
+	nonNullString := NewNonNull(String)
+	nonNullType := NewNonNull(typeType)
+	includeDeprecatedArg := []*Argument{{Name: "includeDeprecated", Type: Boolean, DefaultValue: false}}
+
+	schemaType.Fields = []*Field{
+		{Name: "description", Type: String, Resolve: func(p ResolveParams) (any, error) { return nil, nil }},
+		{Name: "types", Type: NewNonNull(NewList(nonNullType)), Resolve: func(p ResolveParams) (any, error) {
+			s := p.Source.(*Schema)
+			result := make([]Type, 0, len(s.names)+len(introspectionTypes))
+			for _, name := range s.names {
+				result = append(result, s.types[name])
+			}
+			for _, t := range []Type{schemaType, typeType, fieldType, inputValueType, enumValueType, directiveType, typeKindEnum, directiveLocationEnum} {
+				result = append(result, t)
+			}
+			return result, nil
+		}},
+		{Name: "queryType", Type: nonNullType, Resolve: func(p ResolveParams) (any, error) {
+			return p.Source.(*Schema).query, nil
+		}},
+		{Name: "mutationType", Type: typeType, Resolve: func(p ResolveParams) (any, error) {
+			if m := p.Source.(*Schema).mutation; m != nil {
+				return m, nil
+			}
+			return nil, nil
+		}},
+		{Name: "subscriptionType", Type: typeType, Resolve: func(p ResolveParams) (any, error) { return nil, nil }},
+		{Name: "directives", Type: NewNonNull(NewList(NewNonNull(directiveType))), Resolve: func(p ResolveParams) (any, error) {
+			return builtinDirective, nil
+		}},
+	}
+
+	typeType.Fields = []*Field{
+		{Name: "kind", Type: NewNonNull(typeKindEnum), Resolve: func(p ResolveParams) (any, error) {
+			switch p.Source.(type) {
+			case *Scalar:
+				return "SCALAR", nil
+			case *Object:
+				return "OBJECT", nil
+			case *Enum:
+				return "ENUM", nil
+			case *InputObject:
+				return "INPUT_OBJECT", nil
+			case *List:
+				return "LIST", nil
+			case *NonNull:
+				return "NON_NULL", nil
+			}
+			return nil, errors.New("unknown type kind")
+		}},
+		{Name: "name", Type: String, Resolve: func(p ResolveParams) (any, error) {
+			switch v := p.Source.(type) {
+			case *Scalar:
+				return v.Name, nil
+			case *Object:
+				return v.Name, nil
+			case *Enum:
+				return v.Name, nil
+			case *InputObject:
+				return v.Name, nil
+			}
+			return nil, nil
+		}},
+		{Name: "description", Type: String, Resolve: func(p ResolveParams) (any, error) {
+			switch v := p.Source.(type) {
+			case *Scalar:
+				return nilIfEmpty(v.Description), nil
+			case *Object:
+				return nilIfEmpty(v.Description), nil
+			case *Enum:
+				return nilIfEmpty(v.Description), nil
+			case *InputObject:
+				return nilIfEmpty(v.Description), nil
+			}
+			return nil, nil
+		}},
+		{Name: "specifiedByURL", Type: String, Resolve: func(p ResolveParams) (any, error) { return nil, nil }},
+		{Name: "fields", Type: NewList(NewNonNull(fieldType)), Args: includeDeprecatedArg, Resolve: func(p ResolveParams) (any, error) {
+			obj, ok := p.Source.(*Object)
+			if !ok {
+				return nil, nil
+			}
+			includeDeprecated, _ := p.Args["includeDeprecated"].(bool)
+			result := make([]*Field, 0, len(obj.Fields))
+			for _, f := range obj.Fields {
+				if f.DeprecationReason == "" || includeDeprecated {
+					result = append(result, f)
+				}
+			}
+			return result, nil
+		}},
+		{Name: "interfaces", Type: NewList(nonNullType), Resolve: func(p ResolveParams) (any, error) {
+			if _, ok := p.Source.(*Object); ok {
+				return []Type{}, nil
+			}
+			return nil, nil
+		}},
+		{Name: "possibleTypes", Type: NewList(nonNullType), Resolve: func(p ResolveParams) (any, error) { return nil, nil }},
+		{Name: "enumValues", Type: NewList(NewNonNull(enumValueType)), Args: includeDeprecatedArg, Resolve: func(p ResolveParams) (any, error) {
+			enum, ok := p.Source.(*Enum)
+			if !ok {
+				return nil, nil
+			}
+			result := make([]*enumValue, len(enum.Values))
+			for i, v := range enum.Values {
+				result[i] = &enumValue{name: v}
+			}
+			return result, nil
+		}},
+		{Name: "inputFields", Type: NewList(NewNonNull(inputValueType)), Args: includeDeprecatedArg, Resolve: func(p ResolveParams) (any, error) {
+			input, ok := p.Source.(*InputObject)
+			if !ok {
+				return nil, nil
+			}
+			return input.Fields, nil
+		}},
+		{Name: "ofType", Type: typeType, Resolve: func(p ResolveParams) (any, error) {
+			switch v := p.Source.(type) {
+			case *List:
+				return v.OfType, nil
+			case *NonNull:
+				return v.OfType, nil
+			}
+			return nil, nil
+		}},
+		{Name: "isOneOf", Type: Boolean, Resolve: func(p ResolveParams) (any, error) {
+			if _, ok := p.Source.(*InputObject); ok {
+				return false, nil
+			}
+			return nil, nil
+		}},
+	}
+
+	fieldType.Fields = []*Field{
+		{Name: "name", Type: nonNullString, Resolve: func(p ResolveParams) (any, error) {
+			return p.Source.(*Field).Name, nil
+		}},
+		{Name: "description", Type: String, Resolve: func(p ResolveParams) (any, error) {
+			return nilIfEmpty(p.Source.(*Field).Description), nil
+		}},
+		{Name: "args", Type: NewNonNull(NewList(NewNonNull(inputValueType))), Args: includeDeprecatedArg, Resolve: func(p ResolveParams) (any, error) {
+			if args := p.Source.(*Field).Args; args != nil {
+				return args, nil
+			}
+			return []*Argument{}, nil
+		}},
+		{Name: "type", Type: nonNullType, Resolve: func(p ResolveParams) (any, error) {
+			return p.Source.(*Field).Type, nil
+		}},
+		{Name: "isDeprecated", Type: NewNonNull(Boolean), Resolve: func(p ResolveParams) (any, error) {
+			return p.Source.(*Field).DeprecationReason != "", nil
+		}},
+		{Name: "deprecationReason", Type: String, Resolve: func(p ResolveParams) (any, error) {
+			return nilIfEmpty(p.Source.(*Field).DeprecationReason), nil
+		}},
+	}
+
+	inputValueType.Fields = []*Field{
+		{Name: "name", Type: nonNullString, Resolve: func(p ResolveParams) (any, error) {
+			return p.Source.(*Argument).Name, nil
+		}},
+		{Name: "description", Type: String, Resolve: func(p ResolveParams) (any, error) {
+			return nilIfEmpty(p.Source.(*Argument).Description), nil
+		}},
+		{Name: "type", Type: nonNullType, Resolve: func(p ResolveParams) (any, error) {
+			return p.Source.(*Argument).Type, nil
+		}},
+		{Name: "defaultValue", Type: String, Resolve: func(p ResolveParams) (any, error) {
+			arg := p.Source.(*Argument)
+			if arg.DefaultValue == nil {
+				return nil, nil
+			}
+			return printValue(arg.DefaultValue, arg.Type), nil
+		}},
+		{Name: "isDeprecated", Type: NewNonNull(Boolean), Resolve: func(p ResolveParams) (any, error) { return false, nil }},
+		{Name: "deprecationReason", Type: String, Resolve: func(p ResolveParams) (any, error) { return nil, nil }},
+	}
+
+	enumValueType.Fields = []*Field{
+		{Name: "name", Type: nonNullString, Resolve: func(p ResolveParams) (any, error) {
+			return p.Source.(*enumValue).name, nil
+		}},
+		{Name: "description", Type: String, Resolve: func(p ResolveParams) (any, error) { return nil, nil }},
+		{Name: "isDeprecated", Type: NewNonNull(Boolean), Resolve: func(p ResolveParams) (any, error) { return false, nil }},
+		{Name: "deprecationReason", Type: String, Resolve: func(p ResolveParams) (any, error) { return nil, nil }},
+	}
+
+	directiveType.Fields = []*Field{
+		{Name: "name", Type: nonNullString, Resolve: func(p ResolveParams) (any, error) {
+			return p.Source.(*directive).name, nil
+		}},
+		{Name: "description", Type: String, Resolve: func(p ResolveParams) (any, error) {
+			return p.Source.(*directive).description, nil
+		}},
+		{Name: "isRepeatable", Type: NewNonNull(Boolean), Resolve: func(p ResolveParams) (any, error) { return false, nil }},
+		{Name: "locations", Type: NewNonNull(NewList(NewNonNull(directiveLocationEnum))), Resolve: func(p ResolveParams) (any, error) {
+			return []string{"FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"}, nil
+		}},
+		{Name: "args", Type: NewNonNull(NewList(NewNonNull(inputValueType))), Args: includeDeprecatedArg, Resolve: func(p ResolveParams) (any, error) {
+			return []*Argument{{Name: "if", Description: "The condition value.", Type: NewNonNull(Boolean)}}, nil
+		}},
+	}
+
+	for _, t := range []Type{schemaType, typeType, fieldType, inputValueType, enumValueType, directiveType, typeKindEnum, directiveLocationEnum} {
+		introspectionTypes[t.String()] = t
+	}
+}
+
+func (p *preparer) schemaField() *Field {
+	return &Field{
+		Name: "__schema",
+		Type: NewNonNull(schemaType),
+		Resolve: func(ResolveParams) (any, error) {
+			return p.schema, nil
+		},
+	}
+}
+
+func (p *preparer) typeField() *Field {
+	return &Field{
+		Name: "__type",
+		Type: typeType,
+		Args: []*Argument{{Name: "name", Type: NewNonNull(String)}},
+		Resolve: func(rp ResolveParams) (any, error) {
+			name, _ := rp.Args["name"].(string)
+			if t := p.schema.Type(name); t != nil {
+				return t, nil
+			}
+			return nil, nil
+		},
+	}
+}
+
+func nilIfEmpty(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
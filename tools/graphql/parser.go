@@ -0,0 +1,882 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Document is the parsed representation of a GraphQL executable document.
+type Document struct {
+	Operations []*OperationDefinition
+	Fragments  map[string]*FragmentDefinition
+}
+
+// OperationDefinition is a single query or mutation operation.
+type OperationDefinition struct {
+	Operation    string // "query", "mutation" or "subscription"
+	Name         string
+	Variables    []*VariableDefinition
+	Directives   []*DirectiveNode
+	SelectionSet []Selection
+}
+
+// FragmentDefinition is a single named fragment.
+type FragmentDefinition struct {
+	Name          string
+	TypeCondition string
+	Directives    []*DirectiveNode
+	SelectionSet  []Selection
+}
+
+// VariableDefinition is a single operation variable definition.
+type VariableDefinition struct {
+	Name         string
+	Type         *TypeNode
+	DefaultValue any
+	HasDefault   bool
+}
+
+// TypeNode is a type reference in a variable definition (eg. "[String!]!").
+type TypeNode struct {
+	Name    string    // set for named types
+	OfType  *TypeNode // set for list types
+	NonNull bool
+}
+
+// String returns the GraphQL notation of the type reference.
+func (t *TypeNode) String() string {
+	var s string
+	if t.OfType != nil {
+		s = "[" + t.OfType.String() + "]"
+	} else {
+		s = t.Name
+	}
+	if t.NonNull {
+		s += "!"
+	}
+	return s
+}
+
+// Selection is one of *FieldNode, *FragmentSpreadNode or *InlineFragmentNode.
+type Selection interface {
+	isSelection()
+}
+
+// FieldNode is a single field selection.
+type FieldNode struct {
+	Alias        string
+	Name         string
+	Arguments    []*ArgumentNode
+	Directives   []*DirectiveNode
+	SelectionSet []Selection
+}
+
+// FragmentSpreadNode is a named fragment spread (eg. "...UserFields").
+type FragmentSpreadNode struct {
+	Name       string
+	Directives []*DirectiveNode
+}
+
+// InlineFragmentNode is an inline fragment (eg. "... on User { id }").
+type InlineFragmentNode struct {
+	TypeCondition string
+	Directives    []*DirectiveNode
+	SelectionSet  []Selection
+}
+
+func (*FieldNode) isSelection()          {}
+func (*FragmentSpreadNode) isSelection() {}
+func (*InlineFragmentNode) isSelection() {}
+
+// ResponseKey returns the field alias or its name if there is no alias.
+func (f *FieldNode) ResponseKey() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+// ArgumentNode is a single field or directive argument.
+type ArgumentNode struct {
+	Name  string
+	Value any
+}
+
+// DirectiveNode is a single directive usage (eg. "@include(if: $flag)").
+type DirectiveNode struct {
+	Name      string
+	Arguments []*ArgumentNode
+}
+
+// Variable is a variable reference literal value (eg. "$id").
+type Variable string
+
+// EnumValue is an enum literal value.
+type EnumValue string
+
+// Parse parses the provided GraphQL executable document.
+//
+// Literal values are parsed to their plain Go representation:
+// int64, float64, string, bool, nil, [EnumValue], [Variable],
+// []any (lists) and map[string]any (input objects).
+func Parse(source string) (*Document, error) {
+	p := &parser{lexer: &lexer{src: source}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	doc := &Document{Fragments: map[string]*FragmentDefinition{}}
+
+	for p.tok.kind != tokenEOF {
+		switch {
+		case p.tok.is(tokenPunct, "{"):
+			set, err := p.parseSelectionSet()
+			if err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, &OperationDefinition{Operation: "query", SelectionSet: set})
+		case p.tok.is(tokenName, "query"), p.tok.is(tokenName, "mutation"), p.tok.is(tokenName, "subscription"):
+			op, err := p.parseOperation()
+			if err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, op)
+		case p.tok.is(tokenName, "fragment"):
+			fragment, err := p.parseFragment()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := doc.Fragments[fragment.Name]; ok {
+				return nil, fmt.Errorf("there can be only one fragment named %q", fragment.Name)
+			}
+			doc.Fragments[fragment.Name] = fragment
+		default:
+			return nil, p.unexpected()
+		}
+	}
+
+	if len(doc.Operations) == 0 {
+		return nil, fmt.Errorf("the document must contain at least one operation")
+	}
+
+	return doc, nil
+}
+
+// lexer
+// -------------------------------------------------------------------
+
+const (
+	tokenEOF = iota
+	tokenPunct
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+type token struct {
+	kind  int
+	value string
+	pos   int
+}
+
+func (t token) is(kind int, value string) bool {
+	return t.kind == kind && t.value == value
+}
+
+type lexer struct {
+	src string
+	pos int
+}
+
+func (l *lexer) next() (token, error) {
+	// skip ignored tokens (whitespaces, commas, comments and BOM)
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',' {
+			l.pos++
+			continue
+		}
+		if c == '#' {
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' && l.src[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+		if strings.HasPrefix(l.src[l.pos:], "\uFEFF") {
+			l.pos += len("\uFEFF")
+			continue
+		}
+		break
+	}
+
+	start := l.pos
+
+	if l.pos >= len(l.src) {
+		return token{kind: tokenEOF, pos: start}, nil
+	}
+
+	c := l.src[l.pos]
+
+	switch {
+	case strings.ContainsRune("!$&()[]{}:=@|", rune(c)):
+		l.pos++
+		return token{kind: tokenPunct, value: string(c), pos: start}, nil
+	case c == '.':
+		if strings.HasPrefix(l.src[l.pos:], "...") {
+			l.pos += 3
+			return token{kind: tokenPunct, value: "...", pos: start}, nil
+		}
+		return token{}, fmt.Errorf("unexpected character %q at position %d", c, start)
+	case c == '_' || isLetter(c):
+		for l.pos < len(l.src) && (l.src[l.pos] == '_' || isLetter(l.src[l.pos]) || isDigit(l.src[l.pos])) {
+			l.pos++
+		}
+		return token{kind: tokenName, value: l.src[start:l.pos], pos: start}, nil
+	case c == '-' || isDigit(c):
+		return l.readNumber()
+	case c == '"':
+		if strings.HasPrefix(l.src[l.pos:], `"""`) {
+			return l.readBlockString()
+		}
+		return l.readString()
+	}
+
+	return token{}, fmt.Errorf("unexpected character %q at position %d", c, start)
+}
+
+func (l *lexer) readNumber() (token, error) {
+	start := l.pos
+	kind := tokenInt
+
+	if l.src[l.pos] == '-' {
+		l.pos++
+	}
+
+	digits := func() int {
+		n := 0
+		for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+			l.pos++
+			n++
+		}
+		return n
+	}
+
+	if digits() == 0 {
+		return token{}, fmt.Errorf("invalid number at position %d", start)
+	}
+
+	if l.pos < len(l.src) && l.src[l.pos] == '.' {
+		kind = tokenFloat
+		l.pos++
+		if digits() == 0 {
+			return token{}, fmt.Errorf("invalid number at position %d", start)
+		}
+	}
+
+	if l.pos < len(l.src) && (l.src[l.pos] == 'e' || l.src[l.pos] == 'E') {
+		kind = tokenFloat
+		l.pos++
+		if l.pos < len(l.src) && (l.src[l.pos] == '+' || l.src[l.pos] == '-') {
+			l.pos++
+		}
+		if digits() == 0 {
+			return token{}, fmt.Errorf("invalid number at position %d", start)
+		}
+	}
+
+	if l.pos < len(l.src) && (l.src[l.pos] == '_' || l.src[l.pos] == '.' || isLetter(l.src[l.pos])) {
+		return token{}, fmt.Errorf("invalid number at position %d", start)
+	}
+
+	return token{kind: kind, value: l.src[start:l.pos], pos: start}, nil
+}
+
+func (l *lexer) readString() (token, error) {
+	start := l.pos
+	l.pos++ // opening quote
+
+	var sb strings.Builder
+
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+
+		switch c {
+		case '"':
+			l.pos++
+			return token{kind: tokenString, value: sb.String(), pos: start}, nil
+		case '\n', '\r':
+			return token{}, fmt.Errorf("unterminated string at position %d", start)
+		case '\\':
+			if l.pos+1 >= len(l.src) {
+				return token{}, fmt.Errorf("unterminated string at position %d", start)
+			}
+			esc := l.src[l.pos+1]
+			l.pos += 2
+			switch esc {
+			case '"', '\\', '/':
+				sb.WriteByte(esc)
+			case 'b':
+				sb.WriteByte('\b')
+			case 'f':
+				sb.WriteByte('\f')
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			case 'u':
+				if l.pos+4 > len(l.src) {
+					return token{}, fmt.Errorf("invalid unicode escape at position %d", l.pos)
+				}
+				code, err := strconv.ParseUint(l.src[l.pos:l.pos+4], 16, 32)
+				if err != nil {
+					return token{}, fmt.Errorf("invalid unicode escape at position %d", l.pos)
+				}
+				sb.WriteRune(rune(code))
+				l.pos += 4
+			default:
+				return token{}, fmt.Errorf("invalid escape sequence at position %d", l.pos-2)
+			}
+		default:
+			r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+			sb.WriteRune(r)
+			l.pos += size
+		}
+	}
+
+	return token{}, fmt.Errorf("unterminated string at position %d", start)
+}
+
+func (l *lexer) readBlockString() (token, error) {
+	start := l.pos
+	l.pos += 3
+
+	var sb strings.Builder
+
+	for l.pos < len(l.src) {
+		if strings.HasPrefix(l.src[l.pos:], `"""`) {
+			l.pos += 3
+			return token{kind: tokenString, value: blockStringValue(sb.String()), pos: start}, nil
+		}
+		if strings.HasPrefix(l.src[l.pos:], `\"""`) {
+			sb.WriteString(`"""`)
+			l.pos += 4
+			continue
+		}
+		sb.WriteByte(l.src[l.pos])
+		l.pos++
+	}
+
+	return token{}, fmt.Errorf("unterminated block string at position %d", start)
+}
+
+// blockStringValue removes the common indentation and the leading
+// and trailing blank lines of a block string raw value.
+func blockStringValue(raw string) string {
+	lines := strings.Split(strings.ReplaceAll(strings.ReplaceAll(raw, "\r\n", "\n"), "\r", "\n"), "\n")
+
+	commonIndent := -1
+	for _, line := range lines[1:] {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed == "" {
+			continue
+		}
+		indent := len(line) - len(trimmed)
+		if commonIndent == -1 || indent < commonIndent {
+			commonIndent = indent
+		}
+	}
+
+	if commonIndent > 0 {
+		for i := 1; i < len(lines); i++ {
+			if len(lines[i]) >= commonIndent {
+				lines[i] = lines[i][commonIndent:]
+			} else {
+				lines[i] = strings.TrimLeft(lines[i], " \t")
+			}
+		}
+	}
+
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// parser
+// -------------------------------------------------------------------
+
+// maxParseNesting limits the selection sets and values nesting
+// to prevent stack exhaustion with maliciously crafted documents.
+const maxParseNesting = 100
+
+type parser struct {
+	lexer   *lexer
+	tok     token
+	nesting int
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) unexpected() error {
+	if p.tok.kind == tokenEOF {
+		return fmt.Errorf("unexpected end of document")
+	}
+	return fmt.Errorf("unexpected %q at position %d", p.tok.value, p.tok.pos)
+}
+
+func (p *parser) expectPunct(value string) error {
+	if !p.tok.is(tokenPunct, value) {
+		return p.unexpected()
+	}
+	return p.advance()
+}
+
+func (p *parser) skipPunct(value string) (bool, error) {
+	if !p.tok.is(tokenPunct, value) {
+		return false, nil
+	}
+	return true, p.advance()
+}
+
+func (p *parser) expectName() (string, error) {
+	if p.tok.kind != tokenName {
+		return "", p.unexpected()
+	}
+	name := p.tok.value
+	return name, p.advance()
+}
+
+func (p *parser) enter() error {
+	p.nesting++
+	if p.nesting > maxParseNesting {
+		return fmt.Errorf("the document exceeds the max allowed nesting of %d", maxParseNesting)
+	}
+	return nil
+}
+
+func (p *parser) leave() {
+	p.nesting--
+}
+
+func (p *parser) parseOperation() (*OperationDefinition, error) {
+	op := &OperationDefinition{Operation: p.tok.value}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	var err error
+
+	if p.tok.kind == tokenName {
+		if op.Name, err = p.expectName(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.tok.is(tokenPunct, "(") {
+		if op.Variables, err = p.parseVariableDefinitions(); err != nil {
+			return nil, err
+		}
+	}
+
+	if op.Directives, err = p.parseDirectives(false); err != nil {
+		return nil, err
+	}
+
+	if op.SelectionSet, err = p.parseSelectionSet(); err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+func (p *parser) parseVariableDefinitions() ([]*VariableDefinition, error) {
+	if err := p.expectPunct("("); err != nil {
+		return nil, err
+	}
+
+	var result []*VariableDefinition
+
+	for !p.tok.is(tokenPunct, ")") {
+		if err := p.expectPunct("$"); err != nil {
+			return nil, err
+		}
+
+		def := &VariableDefinition{}
+
+		var err error
+		if def.Name, err = p.expectName(); err != nil {
+			return nil, err
+		}
+
+		if err := p.expectPunct(":"); err != nil {
+			return nil, err
+		}
+
+		if def.Type, err = p.parseType(); err != nil {
+			return nil, err
+		}
+
+		if ok, err := p.skipPunct("="); err != nil {
+			return nil, err
+		} else if ok {
+			if def.DefaultValue, err = p.parseValue(true); err != nil {
+				return nil, err
+			}
+			def.HasDefault = true
+		}
+
+		// variable directives are ignored
+		if _, err := p.parseDirectives(true); err != nil {
+			return nil, err
+		}
+
+		result = append(result, def)
+	}
+
+	return result, p.advance()
+}
+
+func (p *parser) parseType() (*TypeNode, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	t := &TypeNode{}
+
+	if ok, err := p.skipPunct("["); err != nil {
+		return nil, err
+	} else if ok {
+		if t.OfType, err = p.parseType(); err != nil {
+			return nil, err
+		}
+		if err := p.expectPunct("]"); err != nil {
+			return nil, err
+		}
+	} else {
+		if t.Name, err = p.expectName(); err != nil {
+			return nil, err
+		}
+	}
+
+	nonNull, err := p.skipPunct("!")
+	if err != nil {
+		return nil, err
+	}
+	t.NonNull = nonNull
+
+	return t, nil
+}
+
+func (p *parser) parseDirectives(isConst bool) ([]*DirectiveNode, error) {
+	var result []*DirectiveNode
+
+	for p.tok.is(tokenPunct, "@") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+
+		args, err := p.parseArguments(isConst)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, &DirectiveNode{Name: name, Arguments: args})
+	}
+
+	return result, nil
+}
+
+func (p *parser) parseArguments(isConst bool) ([]*ArgumentNode, error) {
+	if !p.tok.is(tokenPunct, "(") {
+		return nil, nil
+	}
+
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	var result []*ArgumentNode
+
+	for !p.tok.is(tokenPunct, ")") {
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.expectPunct(":"); err != nil {
+			return nil, err
+		}
+
+		value, err := p.parseValue(isConst)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, &ArgumentNode{Name: name, Value: value})
+	}
+
+	return result, p.advance()
+}
+
+func (p *parser) parseSelectionSet() ([]Selection, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	if err := p.expectPunct("{"); err != nil {
+		return nil, err
+	}
+
+	var result []Selection
+
+	for !p.tok.is(tokenPunct, "}") {
+		selection, err := p.parseSelection()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, selection)
+	}
+
+	if len(result) == 0 {
+		return nil, p.unexpected()
+	}
+
+	return result, p.advance()
+}
+
+func (p *parser) parseSelection() (Selection, error) {
+	if p.tok.is(tokenPunct, "...") {
+		return p.parseFragmentSelection()
+	}
+
+	field := &FieldNode{}
+
+	var err error
+	if field.Name, err = p.expectName(); err != nil {
+		return nil, err
+	}
+
+	if ok, err := p.skipPunct(":"); err != nil {
+		return nil, err
+	} else if ok {
+		field.Alias = field.Name
+		if field.Name, err = p.expectName(); err != nil {
+			return nil, err
+		}
+	}
+
+	if field.Arguments, err = p.parseArguments(false); err != nil {
+		return nil, err
+	}
+
+	if field.Directives, err = p.parseDirectives(false); err != nil {
+		return nil, err
+	}
+
+	if p.tok.is(tokenPunct, "{") {
+		if field.SelectionSet, err = p.parseSelectionSet(); err != nil {
+			return nil, err
+		}
+	}
+
+	return field, nil
+}
+
+func (p *parser) parseFragmentSelection() (Selection, error) {
+	if err := p.expectPunct("..."); err != nil {
+		return nil, err
+	}
+
+	if p.tok.kind == tokenName && p.tok.value != "on" {
+		spread := &FragmentSpreadNode{Name: p.tok.value}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+
+		var err error
+		if spread.Directives, err = p.parseDirectives(false); err != nil {
+			return nil, err
+		}
+
+		return spread, nil
+	}
+
+	inline := &InlineFragmentNode{}
+
+	var err error
+
+	if p.tok.is(tokenName, "on") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if inline.TypeCondition, err = p.expectName(); err != nil {
+			return nil, err
+		}
+	}
+
+	if inline.Directives, err = p.parseDirectives(false); err != nil {
+		return nil, err
+	}
+
+	if inline.SelectionSet, err = p.parseSelectionSet(); err != nil {
+		return nil, err
+	}
+
+	return inline, nil
+}
+
+func (p *parser) parseFragment() (*FragmentDefinition, error) {
+	if err := p.advance(); err != nil { // "fragment"
+		return nil, err
+	}
+
+	fragment := &FragmentDefinition{}
+
+	var err error
+	if fragment.Name, err = p.expectName(); err != nil {
+		return nil, err
+	}
+
+	if fragment.Name == "on" {
+		return nil, fmt.Errorf("invalid fragment name %q", fragment.Name)
+	}
+
+	if !p.tok.is(tokenName, "on") {
+		return nil, p.unexpected()
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	if fragment.TypeCondition, err = p.expectName(); err != nil {
+		return nil, err
+	}
+
+	if fragment.Directives, err = p.parseDirectives(false); err != nil {
+		return nil, err
+	}
+
+	if fragment.SelectionSet, err = p.parseSelectionSet(); err != nil {
+		return nil, err
+	}
+
+	return fragment, nil
+}
+
+func (p *parser) parseValue(isConst bool) (any, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	tok := p.tok
+
+	switch tok.kind {
+	case tokenInt:
+		v, err := strconv.ParseInt(tok.value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid int value %q", tok.value)
+		}
+		return v, p.advance()
+	case tokenFloat:
+		v, err := strconv.ParseFloat(tok.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid float value %q", tok.value)
+		}
+		return v, p.advance()
+	case tokenString:
+		return tok.value, p.advance()
+	case tokenName:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		switch tok.value {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		return EnumValue(tok.value), nil
+	case tokenPunct:
+		switch tok.value {
+		case "$":
+			if isConst {
+				return nil, p.unexpected()
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			name, err := p.expectName()
+			if err != nil {
+				return nil, err
+			}
+			return Variable(name), nil
+		case "[":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			list := []any{}
+			for !p.tok.is(tokenPunct, "]") {
+				item, err := p.parseValue(isConst)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, item)
+			}
+			return list, p.advance()
+		case "{":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			obj := map[string]any{}
+			for !p.tok.is(tokenPunct, "}") {
+				name, err := p.expectName()
+				if err != nil {
+					return nil, err
+				}
+				if err := p.expectPunct(":"); err != nil {
+					return nil, err
+				}
+				if obj[name], err = p.parseValue(isConst); err != nil {
+					return nil, err
+				}
+			}
+			return obj, p.advance()
+		}
+	}
+
+	return nil, p.unexpected()
+}
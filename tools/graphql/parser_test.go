@@ -0,0 +1,172 @@
+package graphql_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/graphql"
+)
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name  string
+		query string
+	}{
+		{"empty", ""},
+		{"only fragment", "fragment A on Post { id }"},
+		{"unclosed selection", "{ posts { id }"},
+		{"empty selection", "{ }"},
+		{"invalid character", "{ id ? }"},
+		{"unterminated string", `{ posts(filter: "abc) { id } }`},
+		{"invalid number", "{ posts(page: 1a) { id } }"},
+		{"invalid escape", `{ posts(filter: "\q") { id } }`},
+		{"variable in const default", "query ($a: Int = $b) { id }"},
+		{"duplicated fragment", "{ id } fragment A on Post { id } fragment A on Post { id }"},
+		{"fragment named on", "{ id } fragment on on Post { id }"},
+		{"unknown definition", "type Post { id }"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			if _, err := graphql.Parse(s.query); err == nil {
+				t.Fatalf("Expected error for %q", s.query)
+			}
+		})
+	}
+}
+
+func TestParseNestingLimit(t *testing.T) {
+	t.Parallel()
+
+	query := ""
+	for i := 0; i < 150; i++ {
+		query += "{ a "
+	}
+	for i := 0; i < 150; i++ {
+		query += "}"
+	}
+
+	if _, err := graphql.Parse(query); err == nil {
+		t.Fatal("Expected nesting limit error")
+	}
+}
+
+func TestParseDocument(t *testing.T) {
+	t.Parallel()
+
+	doc, err := graphql.Parse(`
+		# comment
+		query Posts($filter: String = "a", $ids: [ID!]!) @dir {
+			items: posts(filter: $filter, page: -1, ratio: 1.5e2, flag: true, none: null, sort: ASC, list: [1, "2"], obj: {a: {b: $ids}}) {
+				id
+				...PostFields
+				... on Post @include(if: true) { title }
+				... @skip(if: false) { title }
+			}
+		}
+
+		mutation { deletePost(id: "1") }
+
+		fragment PostFields on Post {
+			description(text: """
+				multi
+				  line
+			""")
+		}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(doc.Operations) != 2 {
+		t.Fatalf("Expected 2 operations, got %d", len(doc.Operations))
+	}
+
+	op := doc.Operations[0]
+	if op.Operation != "query" || op.Name != "Posts" || len(op.Directives) != 1 {
+		t.Fatalf("Unexpected operation %#v", op)
+	}
+
+	if len(op.Variables) != 2 {
+		t.Fatalf("Expected 2 variables, got %d", len(op.Variables))
+	}
+	if v := op.Variables[0]; v.Name != "filter" || v.Type.String() != "String" || !v.HasDefault || v.DefaultValue != "a" {
+		t.Fatalf("Unexpected first variable %#v", v)
+	}
+	if v := op.Variables[1]; v.Name != "ids" || v.Type.String() != "[ID!]!" || v.HasDefault {
+		t.Fatalf("Unexpected second variable %#v", v)
+	}
+
+	field, ok := op.SelectionSet[0].(*graphql.FieldNode)
+	if !ok || field.Alias != "items" || field.Name != "posts" || field.ResponseKey() != "items" {
+		t.Fatalf("Unexpected field %#v", op.SelectionSet[0])
+	}
+
+	args := map[string]any{}
+	for _, arg := range field.Arguments {
+		args[arg.Name] = arg.Value
+	}
+
+	rawArgs, err := json.Marshal(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedArgs := `{"filter":"filter","flag":true,"list":[1,"2"],"none":null,"obj":{"a":{"b":"ids"}},"page":-1,"ratio":150,"sort":"ASC"}`
+	if string(rawArgs) != expectedArgs {
+		t.Fatalf("Expected args\n%s\ngot\n%s", expectedArgs, rawArgs)
+	}
+
+	if _, ok := args["filter"].(graphql.Variable); !ok {
+		t.Fatalf("Expected filter to be a variable, got %T", args["filter"])
+	}
+	if _, ok := args["sort"].(graphql.EnumValue); !ok {
+		t.Fatalf("Expected sort to be an enum value, got %T", args["sort"])
+	}
+	if _, ok := args["page"].(int64); !ok {
+		t.Fatalf("Expected page to be int64, got %T", args["page"])
+	}
+
+	if len(field.SelectionSet) != 4 {
+		t.Fatalf("Expected 4 selections, got %d", len(field.SelectionSet))
+	}
+	if spread, ok := field.SelectionSet[1].(*graphql.FragmentSpreadNode); !ok || spread.Name != "PostFields" {
+		t.Fatalf("Unexpected fragment spread %#v", field.SelectionSet[1])
+	}
+	if inline, ok := field.SelectionSet[2].(*graphql.InlineFragmentNode); !ok || inline.TypeCondition != "Post" || len(inline.Directives) != 1 {
+		t.Fatalf("Unexpected inline fragment %#v", field.SelectionSet[2])
+	}
+	if inline, ok := field.SelectionSet[3].(*graphql.InlineFragmentNode); !ok || inline.TypeCondition != "" {
+		t.Fatalf("Unexpected inline fragment %#v", field.SelectionSet[3])
+	}
+
+	if op := doc.Operations[1]; op.Operation != "mutation" || op.Name != "" {
+		t.Fatalf("Unexpected mutation %#v", op)
+	}
+
+	fragment := doc.Fragments["PostFields"]
+	if fragment == nil || fragment.TypeCondition != "Post" {
+		t.Fatalf("Unexpected fragment %#v", fragment)
+	}
+
+	description := fragment.SelectionSet[0].(*graphql.FieldNode).Arguments[0].Value
+	if description != "multi\n  line" {
+		t.Fatalf("Unexpected block string %q", description)
+	}
+}
+
+func TestParseStringEscapes(t *testing.T) {
+	t.Parallel()
+
+	doc, err := graphql.Parse(`{ a(v: "q\"\\\/\n\téü") }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	value := doc.Operations[0].SelectionSet[0].(*graphql.FieldNode).Arguments[0].Value
+	if value != "q\"\\/\n\téü" {
+		t.Fatalf("Unexpected string value %q", value)
+	}
+}
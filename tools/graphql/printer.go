@@ -0,0 +1,189 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SDL returns the schema definition language representation
+// of the schema (without the built-in scalars).
+func (s *Schema) SDL() string {
+	var sb strings.Builder
+
+	names := make([]string, 0, len(s.names))
+	for _, name := range s.names {
+		if t, ok := s.types[name].(*Scalar); ok && isBuiltinScalar(t) {
+			continue
+		}
+		if name == s.query.Name || (s.mutation != nil && name == s.mutation.Name) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if s.query.Name != "Query" || (s.mutation != nil && s.mutation.Name != "Mutation") {
+		sb.WriteString("schema {\n  query: ")
+		sb.WriteString(s.query.Name)
+		sb.WriteString("\n")
+		if s.mutation != nil {
+			sb.WriteString("  mutation: ")
+			sb.WriteString(s.mutation.Name)
+			sb.WriteString("\n")
+		}
+		sb.WriteString("}\n\n")
+	}
+
+	printType(&sb, s.query)
+	if s.mutation != nil {
+		sb.WriteString("\n")
+		printType(&sb, s.mutation)
+	}
+
+	for _, name := range names {
+		sb.WriteString("\n")
+		printType(&sb, s.types[name])
+	}
+
+	return sb.String()
+}
+
+func printType(sb *strings.Builder, t Type) {
+	switch v := t.(type) {
+	case *Scalar:
+		printDescription(sb, v.Description, "")
+		sb.WriteString("scalar " + v.Name + "\n")
+	case *Enum:
+		printDescription(sb, v.Description, "")
+		sb.WriteString("enum " + v.Name + " {\n")
+		for _, value := range v.Values {
+			sb.WriteString("  " + value + "\n")
+		}
+		sb.WriteString("}\n")
+	case *Object:
+		printDescription(sb, v.Description, "")
+		sb.WriteString("type " + v.Name + " {\n")
+		for _, f := range v.Fields {
+			printDescription(sb, f.Description, "  ")
+			sb.WriteString("  " + f.Name)
+			printArgs(sb, f.Args)
+			sb.WriteString(": " + f.Type.String())
+			if f.DeprecationReason != "" {
+				sb.WriteString(" @deprecated(reason: " + quote(f.DeprecationReason) + ")")
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("}\n")
+	case *InputObject:
+		printDescription(sb, v.Description, "")
+		sb.WriteString("input " + v.Name + " {\n")
+		for _, f := range v.Fields {
+			printDescription(sb, f.Description, "  ")
+			sb.WriteString("  " + printArg(f) + "\n")
+		}
+		sb.WriteString("}\n")
+	}
+}
+
+func printArgs(sb *strings.Builder, args []*Argument) {
+	if len(args) == 0 {
+		return
+	}
+
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		parts[i] = printArg(arg)
+	}
+
+	sb.WriteString("(" + strings.Join(parts, ", ") + ")")
+}
+
+func printArg(arg *Argument) string {
+	str := arg.Name + ": " + arg.Type.String()
+	if arg.DefaultValue != nil {
+		str += " = " + printValue(arg.DefaultValue, arg.Type)
+	}
+	return str
+}
+
+func printDescription(sb *strings.Builder, description string, indent string) {
+	if description == "" {
+		return
+	}
+
+	if !strings.Contains(description, "\n") {
+		sb.WriteString(indent + quote(description) + "\n")
+		return
+	}
+
+	sb.WriteString(indent + `"""` + "\n")
+	for _, line := range strings.Split(description, "\n") {
+		sb.WriteString(indent + strings.ReplaceAll(line, `"""`, `\"""`) + "\n")
+	}
+	sb.WriteString(indent + `"""` + "\n")
+}
+
+// printValue returns the GraphQL literal representation of a Go input value.
+func printValue(value any, t Type) string {
+	if nonNull, ok := t.(*NonNull); ok {
+		t = nonNull.OfType
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		if _, ok := t.(*Enum); ok {
+			return v
+		}
+		return quote(v)
+	case EnumValue:
+		return string(v)
+	case []any:
+		var of Type = t
+		if list, ok := t.(*List); ok {
+			of = list.OfType
+		}
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = printValue(item, of)
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		input, _ := t.(*InputObject)
+
+		parts := make([]string, len(keys))
+		for i, k := range keys {
+			var fieldType Type = String
+			if input != nil {
+				for _, f := range input.Fields {
+					if f.Name == k {
+						fieldType = f.Type
+					}
+				}
+			}
+			parts[i] = k + ": " + printValue(v[k], fieldType)
+		}
+		return "{" + strings.Join(parts, ", ") + "}"
+	}
+
+	return fmt.Sprint(value)
+}
+
+func quote(s string) string {
+	var sb strings.Builder
+
+	enc := json.NewEncoder(&sb)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
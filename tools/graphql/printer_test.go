@@ -0,0 +1,53 @@
+package graphql_test
+
+import (
+	"testing"
+)
+
+func TestSchemaSDL(t *testing.T) {
+	t.Parallel()
+
+	sdl := newTestSchema(t).SDL()
+
+	expected := `type Query {
+  posts(filter: PostFilter, limit: Int = 10): [Post!]!
+  post(id: ID!): Post
+  panic: String
+  selection(ids: [ID!]): [String]
+}
+
+type Mutation {
+  createPost(title: String!): Post
+}
+
+type Author {
+  id: ID!
+  name: String
+}
+
+"A single blog post."
+type Post {
+  id: ID!
+  "The post title."
+  title: String
+  status: Status
+  views: Int
+  author: Author
+  requiredAuthor: Author!
+}
+
+input PostFilter {
+  status: Status
+  minViews: Int = 0
+}
+
+enum Status {
+  DRAFT
+  PUBLISHED
+}
+`
+
+	if sdl != expected {
+		t.Fatalf("Expected\n%s\ngot\n%s", expected, sdl)
+	}
+}
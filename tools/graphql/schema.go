@@ -0,0 +1,458 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cast"
+)
+
+// Type is one of *Scalar, *Enum, *Object, *InputObject, *List or *NonNull.
+type Type interface {
+	// String returns the GraphQL type reference notation (eg. "[Post!]!").
+	String() string
+}
+
+// ResolveFunc defines a single field resolver function.
+type ResolveFunc func(p ResolveParams) (any, error)
+
+// ComplexityFunc defines a custom field complexity calculation function.
+//
+// childComplexity is the total complexity of the field sub-selection.
+type ComplexityFunc func(childComplexity int, args map[string]any) int
+
+// ResolveParams defines the arguments passed to a field resolver.
+type ResolveParams struct {
+	Context context.Context
+
+	// Source is the resolved value of the parent object.
+	Source any
+
+	// Args are the coerced field arguments (including the defaults).
+	Args map[string]any
+
+	// Info contains the resolved field execution details.
+	Info ResolveInfo
+}
+
+// ResolveInfo contains the execution details of the resolved field.
+type ResolveInfo struct {
+	FieldName  string
+	Path       []any
+	ParentType *Object
+	ReturnType Type
+
+	// SelectionSet is the field sub-selection (if any)
+	// that could be used for prefetching nested data.
+	SelectionSet []*SelectedField
+}
+
+// SelectedField describes a single prepared field selection.
+type SelectedField struct {
+	Alias        string
+	Name         string
+	Args         map[string]any
+	SelectionSet []*SelectedField
+
+	def *Field
+}
+
+// ResponseKey returns the field alias or its name if there is no alias.
+func (f *SelectedField) ResponseKey() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+// Scalar defines a leaf scalar type.
+type Scalar struct {
+	Name        string
+	Description string
+
+	// Serialize converts the resolved value to its output representation.
+	// Defaults to returning the value as it is.
+	Serialize func(v any) (any, error)
+
+	// ParseValue coerces a literal or variable input value.
+	// Defaults to returning the value as it is.
+	ParseValue func(v any) (any, error)
+}
+
+func (t *Scalar) String() string { return t.Name }
+
+// Enum defines a leaf enum type.
+type Enum struct {
+	Name        string
+	Description string
+	Values      []string
+}
+
+func (t *Enum) String() string { return t.Name }
+
+// Object defines an output object type.
+type Object struct {
+	Name        string
+	Description string
+	Fields      []*Field
+}
+
+func (t *Object) String() string { return t.Name }
+
+// Field returns the object field with the specified name (if exists).
+func (t *Object) Field(name string) *Field {
+	for _, f := range t.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// Field defines a single object field.
+type Field struct {
+	Name              string
+	Description       string
+	DeprecationReason string
+	Type              Type
+	Args              []*Argument
+
+	// Resolve is the field resolver function.
+	// If not set, the field value is extracted from the parent map[string]any source.
+	Resolve ResolveFunc
+
+	// Complexity is an optional field complexity function.
+	// If not set, the field complexity is 1 + childComplexity.
+	Complexity ComplexityFunc
+}
+
+// Argument defines a single field argument or input object field.
+type Argument struct {
+	Name         string
+	Description  string
+	Type         Type
+	DefaultValue any
+}
+
+// InputObject defines an input object type.
+type InputObject struct {
+	Name        string
+	Description string
+	Fields      []*Argument
+}
+
+func (t *InputObject) String() string { return t.Name }
+
+// List defines a list type wrapper.
+type List struct {
+	OfType Type
+}
+
+// NewList creates a new list type wrapper.
+func NewList(t Type) *List {
+	return &List{OfType: t}
+}
+
+func (t *List) String() string { return "[" + t.OfType.String() + "]" }
+
+// NonNull defines a non-null type wrapper.
+type NonNull struct {
+	OfType Type
+}
+
+// NewNonNull creates a new non-null type wrapper.
+func NewNonNull(t Type) *NonNull {
+	return &NonNull{OfType: t}
+}
+
+func (t *NonNull) String() string { return t.OfType.String() + "!" }
+
+// NamedType unwraps the List and NonNull type wrappers.
+func NamedType(t Type) Type {
+	for {
+		switch v := t.(type) {
+		case *List:
+			t = v.OfType
+		case *NonNull:
+			t = v.OfType
+		default:
+			return t
+		}
+	}
+}
+
+// Built-in scalars
+// -------------------------------------------------------------------
+
+var (
+	String = &Scalar{
+		Name:        "String",
+		Description: "The `String` scalar type represents textual data.",
+		Serialize: func(v any) (any, error) {
+			return cast.ToStringE(v)
+		},
+		ParseValue: func(v any) (any, error) {
+			s, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("String cannot represent a non string value: %v", v)
+			}
+			return s, nil
+		},
+	}
+
+	Int = &Scalar{
+		Name:        "Int",
+		Description: "The `Int` scalar type represents non-fractional signed whole numeric values.",
+		Serialize: func(v any) (any, error) {
+			return cast.ToIntE(v)
+		},
+		ParseValue: func(v any) (any, error) {
+			f, ok := toFloat(v)
+			if !ok || f != math.Trunc(f) || f > math.MaxInt32 || f < math.MinInt32 {
+				return nil, fmt.Errorf("Int cannot represent non 32-bit signed integer value: %v", v)
+			}
+			return int(f), nil
+		},
+	}
+
+	Float = &Scalar{
+		Name:        "Float",
+		Description: "The `Float` scalar type represents signed double-precision fractional values.",
+		Serialize: func(v any) (any, error) {
+			return cast.ToFloat64E(v)
+		},
+		ParseValue: func(v any) (any, error) {
+			f, ok := toFloat(v)
+			if !ok {
+				return nil, fmt.Errorf("Float cannot represent non numeric value: %v", v)
+			}
+			return f, nil
+		},
+	}
+
+	Boolean = &Scalar{
+		Name:        "Boolean",
+		Description: "The `Boolean` scalar type represents `true` or `false`.",
+		Serialize: func(v any) (any, error) {
+			return cast.ToBoolE(v)
+		},
+		ParseValue: func(v any) (any, error) {
+			b, ok := v.(bool)
+			if !ok {
+				return nil, fmt.Errorf("Boolean cannot represent a non boolean value: %v", v)
+			}
+			return b, nil
+		},
+	}
+
+	ID = &Scalar{
+		Name:        "ID",
+		Description: "The `ID` scalar type represents a unique identifier.",
+		Serialize: func(v any) (any, error) {
+			return cast.ToStringE(v)
+		},
+		ParseValue: func(v any) (any, error) {
+			switch val := v.(type) {
+			case string:
+				return val, nil
+			case int64:
+				return strconv.FormatInt(val, 10), nil
+			case float64:
+				if val == math.Trunc(val) {
+					return strconv.FormatInt(int64(val), 10), nil
+				}
+			}
+			return nil, fmt.Errorf("ID cannot represent value: %v", v)
+		},
+	}
+)
+
+func toFloat(v any) (float64, bool) {
+	switch val := v.(type) {
+	case int:
+		return float64(val), true
+	case int64:
+		return float64(val), true
+	case float64:
+		return val, true
+	}
+	return 0, false
+}
+
+// Schema
+// -------------------------------------------------------------------
+
+var nameRegex = regexp.MustCompile(`^[_a-zA-Z][_a-zA-Z0-9]*$`)
+
+// Schema defines an executable GraphQL schema.
+type Schema struct {
+	query    *Object
+	mutation *Object
+	types    map[string]Type
+	names    []string // in the order of registration
+}
+
+// NewSchema creates and validates a new executable schema.
+//
+// The mutation root type is optional.
+func NewSchema(query *Object, mutation *Object) (*Schema, error) {
+	if query == nil {
+		return nil, errors.New("the query root type is required")
+	}
+
+	s := &Schema{
+		query:    query,
+		mutation: mutation,
+		types:    map[string]Type{},
+	}
+
+	for _, t := range []Type{String, Int, Float, Boolean, ID, query} {
+		if err := s.register(t); err != nil {
+			return nil, err
+		}
+	}
+
+	if mutation != nil {
+		if err := s.register(mutation); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// QueryType returns the schema query root type.
+func (s *Schema) QueryType() *Object {
+	return s.query
+}
+
+// MutationType returns the schema mutation root type (could be nil).
+func (s *Schema) MutationType() *Object {
+	return s.mutation
+}
+
+// Type returns the named schema type (if exists).
+func (s *Schema) Type(name string) Type {
+	if t, ok := s.types[name]; ok {
+		return t
+	}
+	return introspectionTypes[name]
+}
+
+func (s *Schema) register(t Type) error {
+	named := NamedType(t)
+
+	var name string
+	switch v := named.(type) {
+	case *Scalar:
+		name = v.Name
+	case *Enum:
+		name = v.Name
+		for _, value := range v.Values {
+			if !nameRegex.MatchString(value) || value == "true" || value == "false" || value == "null" {
+				return fmt.Errorf("invalid %s enum value %q", name, value)
+			}
+		}
+	case *Object:
+		name = v.Name
+	case *InputObject:
+		name = v.Name
+	default:
+		return fmt.Errorf("unsupported type %T", t)
+	}
+
+	if !nameRegex.MatchString(name) || strings.HasPrefix(name, "__") {
+		return fmt.Errorf("invalid type name %q", name)
+	}
+
+	if existing, ok := s.types[name]; ok {
+		if existing != named {
+			return fmt.Errorf("duplicated type name %q", name)
+		}
+		return nil // already registered
+	}
+
+	s.types[name] = named
+	s.names = append(s.names, name)
+
+	switch v := named.(type) {
+	case *Object:
+		if len(v.Fields) == 0 {
+			return fmt.Errorf("object type %q must define at least one field", name)
+		}
+		seen := make(map[string]struct{}, len(v.Fields))
+		for _, f := range v.Fields {
+			if !nameRegex.MatchString(f.Name) || strings.HasPrefix(f.Name, "__") {
+				return fmt.Errorf("invalid %s field name %q", name, f.Name)
+			}
+			if _, ok := seen[f.Name]; ok {
+				return fmt.Errorf("duplicated %s field %q", name, f.Name)
+			}
+			seen[f.Name] = struct{}{}
+
+			if f.Type == nil || isInputType(f.Type) && !isLeafType(f.Type) {
+				return fmt.Errorf("invalid %s.%s field type", name, f.Name)
+			}
+			if err := s.register(f.Type); err != nil {
+				return err
+			}
+
+			if err := s.registerArgs(name+"."+f.Name, f.Args); err != nil {
+				return err
+			}
+		}
+	case *InputObject:
+		if len(v.Fields) == 0 {
+			return fmt.Errorf("input object type %q must define at least one field", name)
+		}
+		if err := s.registerArgs(name, v.Fields); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Schema) registerArgs(owner string, args []*Argument) error {
+	seen := make(map[string]struct{}, len(args))
+
+	for _, arg := range args {
+		if !nameRegex.MatchString(arg.Name) || strings.HasPrefix(arg.Name, "__") {
+			return fmt.Errorf("invalid %s argument name %q", owner, arg.Name)
+		}
+		if _, ok := seen[arg.Name]; ok {
+			return fmt.Errorf("duplicated %s argument %q", owner, arg.Name)
+		}
+		seen[arg.Name] = struct{}{}
+
+		if arg.Type == nil || !isInputType(arg.Type) {
+			return fmt.Errorf("%s.%s must be an input type", owner, arg.Name)
+		}
+		if err := s.register(arg.Type); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func isInputType(t Type) bool {
+	switch NamedType(t).(type) {
+	case *Scalar, *Enum, *InputObject:
+		return true
+	}
+	return false
+}
+
+func isLeafType(t Type) bool {
+	switch NamedType(t).(type) {
+	case *Scalar, *Enum:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,353 @@
+// Package jsonschema implements a minimal JSON Schema validator
+// covering the most commonly used subset of the specification.
+//
+// The supported keywords are:
+//   - type (single value or array of types, incl. "null")
+//   - enum, const
+//   - properties, required, additionalProperties
+//   - items, minItems, maxItems
+//   - minLength, maxLength, pattern
+//   - minimum, maximum, exclusiveMinimum, exclusiveMaximum
+//
+// Unknown keywords (eg. "description", "format", "$id") are ignored.
+package jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+var (
+	ErrInvalidType  = validation.NewError("validation_schema_invalid_type", "Must be of type {{.type}}.")
+	ErrInvalidConst = validation.NewError("validation_schema_invalid_const", "Must be equal to the schema constant value.")
+	ErrUnknownField = validation.NewError("validation_schema_unknown_field", "Unknown field.")
+)
+
+// Validate checks whether data satisfies the provided JSON Schema.
+//
+// data is expected to be a decoded JSON value (map[string]any, []any,
+// string, float64, bool, nil, etc.).
+//
+// On failure it returns either a single [validation.Error] (when the
+// root value is invalid) or a nested [validation.Errors] map mirroring
+// the data structure (array items are keyed by their index).
+func Validate(schema map[string]any, data any) error {
+	if len(schema) == 0 {
+		return nil
+	}
+
+	return validateValue(schema, data)
+}
+
+// ValidateJSON is similar to [Validate] but accepts raw JSON encoded data.
+func ValidateJSON(schema map[string]any, raw []byte) error {
+	var data any
+
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &data); err != nil {
+			return err
+		}
+	}
+
+	return Validate(schema, data)
+}
+
+func validateValue(schema map[string]any, value any) error {
+	value = normalize(value)
+
+	if types := schemaTypes(schema); len(types) > 0 {
+		var matched bool
+		for _, t := range types {
+			if isType(value, t) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return ErrInvalidType.SetParams(map[string]any{"type": joinTypes(types)})
+		}
+	}
+
+	if value == nil {
+		return nil
+	}
+
+	if c, ok := schema["const"]; ok && !reflect.DeepEqual(normalize(c), value) {
+		return ErrInvalidConst
+	}
+
+	if enum, ok := schema["enum"].([]any); ok {
+		var found bool
+		for _, v := range enum {
+			if reflect.DeepEqual(normalize(v), value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return validation.ErrInInvalid
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		return validateString(schema, v)
+	case float64:
+		return validateNumber(schema, v)
+	case []any:
+		return validateArray(schema, v)
+	case map[string]any:
+		return validateObject(schema, v)
+	}
+
+	return nil
+}
+
+func validateString(schema map[string]any, value string) error {
+	length := utf8.RuneCountInString(value)
+
+	if min, ok := toInt(schema["minLength"]); ok && length < min {
+		return validation.ErrLengthTooShort.SetParams(map[string]any{"min": min})
+	}
+
+	if max, ok := toInt(schema["maxLength"]); ok && length > max {
+		return validation.ErrLengthTooLong.SetParams(map[string]any{"max": max})
+	}
+
+	if pattern, ok := schema["pattern"].(string); ok && pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid schema pattern %q: %w", pattern, err)
+		}
+		if !re.MatchString(value) {
+			return validation.ErrMatchInvalid
+		}
+	}
+
+	return nil
+}
+
+func validateNumber(schema map[string]any, value float64) error {
+	if min, ok := toFloat(schema["minimum"]); ok && value < min {
+		return validation.ErrMinGreaterEqualThanRequired.SetParams(map[string]any{"threshold": min})
+	}
+
+	if min, ok := toFloat(schema["exclusiveMinimum"]); ok && value <= min {
+		return validation.ErrMinGreaterThanRequired.SetParams(map[string]any{"threshold": min})
+	}
+
+	if max, ok := toFloat(schema["maximum"]); ok && value > max {
+		return validation.ErrMaxLessEqualThanRequired.SetParams(map[string]any{"threshold": max})
+	}
+
+	if max, ok := toFloat(schema["exclusiveMaximum"]); ok && value >= max {
+		return validation.ErrMaxLessThanRequired.SetParams(map[string]any{"threshold": max})
+	}
+
+	return nil
+}
+
+func validateArray(schema map[string]any, value []any) error {
+	if min, ok := toInt(schema["minItems"]); ok && len(value) < min {
+		return validation.ErrLengthTooShort.SetParams(map[string]any{"min": min})
+	}
+
+	if max, ok := toInt(schema["maxItems"]); ok && len(value) > max {
+		return validation.ErrLengthTooLong.SetParams(map[string]any{"max": max})
+	}
+
+	itemsSchema, _ := schema["items"].(map[string]any)
+	if len(itemsSchema) == 0 {
+		return nil
+	}
+
+	errs := validation.Errors{}
+
+	for i, item := range value {
+		if err := validateValue(itemsSchema, item); err != nil {
+			if isInternalError(err) {
+				return err
+			}
+			errs[strconv.Itoa(i)] = err
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+func validateObject(schema map[string]any, value map[string]any) error {
+	errs := validation.Errors{}
+
+	if required, ok := schema["required"].([]any); ok {
+		for _, r := range required {
+			name, _ := r.(string)
+			if _, exists := value[name]; name != "" && !exists {
+				errs[name] = validation.ErrRequired
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]any)
+
+	for name, v := range value {
+		if _, hasErr := errs[name]; hasErr {
+			continue
+		}
+
+		propSchema, isDefined := properties[name].(map[string]any)
+		if isDefined {
+			if err := validateValue(propSchema, v); err != nil {
+				if isInternalError(err) {
+					return err
+				}
+				errs[name] = err
+			}
+			continue
+		}
+
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				errs[name] = ErrUnknownField
+			}
+		case map[string]any:
+			if err := validateValue(additional, v); err != nil {
+				if isInternalError(err) {
+					return err
+				}
+				errs[name] = err
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// -------------------------------------------------------------------
+
+// isInternalError reports whether err is a schema definition error
+// (eg. invalid pattern) and not a data validation error.
+func isInternalError(err error) bool {
+	var vErr validation.Error
+	var vErrs validation.Errors
+
+	return !errors.As(err, &vErr) && !errors.As(err, &vErrs)
+}
+
+func schemaTypes(schema map[string]any) []string {
+	switch v := schema["type"].(type) {
+	case string:
+		return []string{v}
+	case []any:
+		result := make([]string, 0, len(v))
+		for _, t := range v {
+			if str, ok := t.(string); ok {
+				result = append(result, str)
+			}
+		}
+		return result
+	case []string:
+		return v
+	}
+
+	return nil
+}
+
+func joinTypes(types []string) string {
+	sorted := append([]string{}, types...)
+	sort.Strings(sorted)
+
+	return strings.Join(sorted, "|")
+}
+
+func isType(value any, t string) bool {
+	switch t {
+	case "null":
+		return value == nil
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		v, ok := value.(float64)
+		return ok && v == math.Trunc(v)
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	}
+
+	return false
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := normalize(v).(type) {
+	case float64:
+		return n, true
+	}
+
+	return 0, false
+}
+
+func toInt(v any) (int, bool) {
+	f, ok := toFloat(v)
+
+	return int(f), ok
+}
+
+// normalize converts the Go value into its plain decoded JSON equivalent
+// so that it can be compared and type checked consistently.
+func normalize(v any) any {
+	switch n := v.(type) {
+	case nil, bool, string, float64, map[string]any, []any:
+		return v
+	case int:
+		return float64(n)
+	case int32:
+		return float64(n)
+	case int64:
+		return float64(n)
+	case float32:
+		return float64(n)
+	case json.Number:
+		f, _ := n.Float64()
+		return f
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+
+	var result any
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return v
+	}
+
+	return result
+}
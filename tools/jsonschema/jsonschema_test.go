@@ -0,0 +1,150 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/jsonschema"
+)
+
+func TestValidateJSON(t *testing.T) {
+	t.Parallel()
+
+	schema := map[string]any{}
+	err := json.Unmarshal([]byte(`{
+		"type": "object",
+		"required": ["title", "tags"],
+		"additionalProperties": false,
+		"properties": {
+			"title":  {"type": "string", "minLength": 3, "maxLength": 5},
+			"code":   {"type": "string", "pattern": "^[a-z]+$"},
+			"total":  {"type": "integer", "minimum": 1, "exclusiveMaximum": 10},
+			"status": {"enum": ["draft", "published"]},
+			"kind":   {"const": "post"},
+			"note":   {"type": ["string", "null"]},
+			"tags":   {"type": "array", "maxItems": 2, "items": {"type": "string"}}
+		}
+	}`), &schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name     string
+		schema   map[string]any
+		data     string
+		expected string // json serialized error or empty string
+	}{
+		{"empty schema", nil, `123`, ``},
+		{"invalid root type", schema, `[]`, `"Must be of type object."`},
+		{
+			"valid object",
+			schema,
+			`{"title":"abc","code":"abc","total":9,"status":"draft","kind":"post","note":null,"tags":["a"]}`,
+			``,
+		},
+		{
+			"missing required",
+			schema,
+			`{"title":"abc"}`,
+			`{"tags":"cannot be blank"}`,
+		},
+		{
+			"unknown field",
+			schema,
+			`{"title":"abc","tags":[],"unknown":1}`,
+			`{"unknown":"Unknown field."}`,
+		},
+		{
+			"invalid string constraints",
+			schema,
+			`{"title":"ab","code":"ABC","tags":[]}`,
+			`{"code":"must be in a valid format","title":"the length must be no less than 3"}`,
+		},
+		{
+			"invalid number constraints",
+			schema,
+			`{"title":"abc","total":10,"tags":[]}`,
+			`{"total":"must be less than 10"}`,
+		},
+		{
+			"non integer",
+			schema,
+			`{"title":"abc","total":1.5,"tags":[]}`,
+			`{"total":"Must be of type integer."}`,
+		},
+		{
+			"invalid enum and const",
+			schema,
+			`{"title":"abc","status":"other","kind":"other","tags":[]}`,
+			`{"kind":"Must be equal to the schema constant value.","status":"must be a valid value"}`,
+		},
+		{
+			"invalid array items",
+			schema,
+			`{"title":"abc","tags":["a",2]}`,
+			`{"tags":{"1":"Must be of type string."}}`,
+		},
+		{
+			"too many array items",
+			schema,
+			`{"title":"abc","tags":["a","b","c"]}`,
+			`{"tags":"the length must be no more than 2"}`,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			err := jsonschema.ValidateJSON(s.schema, []byte(s.data))
+
+			if s.expected == "" {
+				if err != nil {
+					t.Fatalf("Expected nil error, got %v", err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("Expected error %s, got nil", s.expected)
+			}
+
+			raw, _ := json.Marshal(err)
+			if string(raw) == "{}" {
+				raw = []byte(fmt.Sprintf("%q", err.Error()))
+			}
+
+			if str := string(raw); str != s.expected {
+				t.Fatalf("Expected error\n%s\ngot\n%s", s.expected, str)
+			}
+		})
+	}
+}
+
+func TestValidateInvalidPattern(t *testing.T) {
+	t.Parallel()
+
+	schema := map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"a": map[string]any{"pattern": "["}},
+	}
+
+	err := jsonschema.Validate(schema, map[string]any{"a": "test"})
+	if err == nil {
+		t.Fatal("Expected invalid pattern error, got nil")
+	}
+}
+
+func TestValidateGoValues(t *testing.T) {
+	t.Parallel()
+
+	schema := map[string]any{"type": "integer", "minimum": 2}
+
+	if err := jsonschema.Validate(schema, 3); err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+
+	if err := jsonschema.Validate(schema, int64(1)); err == nil {
+		t.Fatal("Expected minimum error, got nil")
+	}
+}
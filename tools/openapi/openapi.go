@@ -0,0 +1,181 @@
+// Package openapi defines a minimal set of types for building
+// OpenAPI 3.1 documents.
+//
+// Only the parts of the specification that are used by PocketBase are
+// modeled explicitly. Schemas are stored as plain JSON Schema maps
+// because OpenAPI 3.1 is fully aligned with JSON Schema 2020-12.
+package openapi
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Version is the OpenAPI specification version of the generated documents.
+const Version = "3.1.0"
+
+// Schema represents a JSON Schema object.
+type Schema = map[string]any
+
+// Document represents the root OpenAPI document object.
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Servers    []Server             `json:"servers,omitempty"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components *Components          `json:"components,omitempty"`
+	Tags       []Tag                `json:"tags,omitempty"`
+}
+
+// Info provides metadata about the API.
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// Server represents a single API server.
+type Server struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// Tag adds metadata to a single operations tag.
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// Components holds the reusable document objects.
+type Components struct {
+	Schemas         map[string]Schema          `json:"schemas,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme defines a single security scheme that can be used by the operations.
+type SecurityScheme struct {
+	Type        string `json:"type"`
+	Name        string `json:"name,omitempty"`
+	In          string `json:"in,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem describes the operations available on a single path.
+type PathItem struct {
+	Get     *Operation `json:"get,omitempty"`
+	Put     *Operation `json:"put,omitempty"`
+	Post    *Operation `json:"post,omitempty"`
+	Delete  *Operation `json:"delete,omitempty"`
+	Options *Operation `json:"options,omitempty"`
+	Head    *Operation `json:"head,omitempty"`
+	Patch   *Operation `json:"patch,omitempty"`
+}
+
+// SetOperation assigns op to the path item field matching the specified HTTP method.
+//
+// Unsupported methods are ignored.
+func (p *PathItem) SetOperation(method string, op *Operation) {
+	switch strings.ToUpper(method) {
+	case http.MethodGet:
+		p.Get = op
+	case http.MethodPut:
+		p.Put = op
+	case http.MethodPost:
+		p.Post = op
+	case http.MethodDelete:
+		p.Delete = op
+	case http.MethodOptions:
+		p.Options = op
+	case http.MethodHead:
+		p.Head = op
+	case http.MethodPatch:
+		p.Patch = op
+	}
+}
+
+// Operation describes a single API operation on a path.
+type Operation struct {
+	OperationId string                `json:"operationId,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []*Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+// Parameter describes a single operation parameter.
+type Parameter struct {
+	Name        string `json:"name"`
+	In          string `json:"in"` // "query", "header", "path" or "cookie"
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+	Schema      Schema `json:"schema,omitempty"`
+}
+
+// RequestBody describes a single request body.
+type RequestBody struct {
+	Description string                `json:"description,omitempty"`
+	Required    bool                  `json:"required,omitempty"`
+	Content     map[string]*MediaType `json:"content"`
+}
+
+// Response describes a single response from an API operation.
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// MediaType provides schema for the media type identified by its key.
+type MediaType struct {
+	Schema Schema `json:"schema,omitempty"`
+}
+
+// NewDocument creates a new empty OpenAPI document with the specified title and version.
+func NewDocument(title string, version string) *Document {
+	return &Document{
+		OpenAPI: Version,
+		Info: Info{
+			Title:   title,
+			Version: version,
+		},
+		Paths: map[string]*PathItem{},
+	}
+}
+
+// AddOperation registers op for the specified method and path
+// (creating the path item if missing).
+func (d *Document) AddOperation(method string, path string, op *Operation) {
+	if d.Paths == nil {
+		d.Paths = map[string]*PathItem{}
+	}
+
+	item, ok := d.Paths[path]
+	if !ok {
+		item = &PathItem{}
+		d.Paths[path] = item
+	}
+
+	item.SetOperation(method, op)
+}
+
+// JSONContent is a helper that returns a single "application/json" content map for the provided schema.
+func JSONContent(schema Schema) map[string]*MediaType {
+	return map[string]*MediaType{
+		"application/json": {Schema: schema},
+	}
+}
+
+// ErrorSchema returns the JSON Schema of the standard router.ApiError response.
+func ErrorSchema() Schema {
+	return Schema{
+		"type":     "object",
+		"required": []string{"status", "message", "data"},
+		"properties": map[string]any{
+			"status":  Schema{"type": "integer"},
+			"message": Schema{"type": "string"},
+			"data":    Schema{"type": "object"},
+		},
+	}
+}
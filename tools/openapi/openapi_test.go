@@ -0,0 +1,59 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/openapi"
+)
+
+func TestNewDocument(t *testing.T) {
+	t.Parallel()
+
+	doc := openapi.NewDocument("test", "1.0.0")
+
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"openapi":"3.1.0","info":{"title":"test","version":"1.0.0"},"paths":{}}`
+	if str := string(raw); str != expected {
+		t.Fatalf("Expected\n%s\ngot\n%s", expected, str)
+	}
+}
+
+func TestDocumentAddOperation(t *testing.T) {
+	t.Parallel()
+
+	doc := &openapi.Document{}
+
+	doc.AddOperation("get", "/a", &openapi.Operation{Summary: "get_a"})
+	doc.AddOperation("POST", "/a", &openapi.Operation{Summary: "post_a"})
+	doc.AddOperation("DELETE", "/b", &openapi.Operation{Summary: "delete_b"})
+	doc.AddOperation("INVALID", "/c", &openapi.Operation{Summary: "invalid_c"})
+
+	raw, err := json.Marshal(doc.Paths)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"/a":{"get":{"summary":"get_a","responses":null},"post":{"summary":"post_a","responses":null}},"/b":{"delete":{"summary":"delete_b","responses":null}},"/c":{}}`
+	if str := string(raw); str != expected {
+		t.Fatalf("Expected\n%s\ngot\n%s", expected, str)
+	}
+}
+
+func TestJSONContent(t *testing.T) {
+	t.Parallel()
+
+	raw, err := json.Marshal(openapi.JSONContent(openapi.Schema{"type": "string"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"application/json":{"schema":{"type":"string"}}}`
+	if str := string(raw); str != expected {
+		t.Fatalf("Expected\n%s\ngot\n%s", expected, str)
+	}
+}
@@ -0,0 +1,192 @@
+package search
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/pocketbase/dbx"
+)
+
+// cursorKeyField is the unique field that is appended as last sort
+// field in cursor pagination mode to ensure stable ordering.
+const cursorKeyField = "id"
+
+// ErrInvalidCursor is returned when the provided pagination cursor is
+// malformed, tampered or was generated for a different sort.
+var ErrInvalidCursor = errors.New("invalid or mismatched pagination cursor")
+
+// cursor defines a decoded keyset pagination position.
+type cursor struct {
+	// Values are the sort key values of the boundary item.
+	Values []any `json:"v"`
+
+	// Prev indicates whether the cursor points backward
+	// (aka. to the items before Values).
+	Prev bool `json:"p,omitempty"`
+}
+
+// cursorKey defines a single resolved keyset sort key.
+type cursorKey struct {
+	identifier string
+	desc       bool
+}
+
+// cursorSignature returns a string representation of the provided sort
+// keys that is used to bind the cursor to a specific sort.
+func cursorSignature(keys []cursorKey) string {
+	var sb strings.Builder
+
+	for _, k := range keys {
+		sb.WriteString(k.identifier)
+		if k.desc {
+			sb.WriteString(" " + SortDesc)
+		} else {
+			sb.WriteString(" " + SortAsc)
+		}
+		sb.WriteString(",")
+	}
+
+	return sb.String()
+}
+
+// cursorChecksum returns a truncated HMAC-SHA256 of the cursor payload
+// and sort signature keyed with the provided server-side secret.
+func cursorChecksum(secret string, signature string, payload string) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(signature + "\x00" + payload))
+
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil)[:16])
+}
+
+// encodeCursor encodes the cursor into an opaque url safe string
+// bound to the provided sort keys and signed with the provided secret.
+func encodeCursor(c cursor, keys []cursorKey, secret string) (string, error) {
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString(raw)
+
+	return payload + "." + cursorChecksum(secret, cursorSignature(keys), payload), nil
+}
+
+// decodeCursor decodes and validates a cursor string
+// generated with encodeCursor for the same sort keys and secret.
+func decodeCursor(str string, keys []cursorKey, secret string) (cursor, error) {
+	result := cursor{}
+
+	payload, checksum, ok := strings.Cut(str, ".")
+	if !ok {
+		return result, ErrInvalidCursor
+	}
+
+	expectedChecksum := cursorChecksum(secret, cursorSignature(keys), payload)
+	if subtle.ConstantTimeCompare([]byte(checksum), []byte(expectedChecksum)) != 1 {
+		return result, ErrInvalidCursor
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return result, ErrInvalidCursor
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&result); err != nil || len(result.Values) != len(keys) {
+		return result, ErrInvalidCursor
+	}
+
+	// restore the original numeric types
+	for i, v := range result.Values {
+		n, ok := v.(json.Number)
+		if !ok {
+			continue
+		}
+
+		if intV, err := n.Int64(); err == nil {
+			result.Values[i] = intV
+		} else if floatV, err := n.Float64(); err == nil {
+			result.Values[i] = floatV
+		} else {
+			return result, ErrInvalidCursor
+		}
+	}
+
+	return result, nil
+}
+
+// normalizeCursorValue normalizes a single db scanned sort key value.
+func normalizeCursorValue(v any) any {
+	if b, ok := v.([]byte); ok {
+		return string(b)
+	}
+
+	return v
+}
+
+// cursorAfterExpr returns an expression that matches the items positioned
+// after the provided sort key values in the keys sort order.
+//
+// Set reverse to match the items positioned before the provided values.
+//
+// paramsPrefix is used to avoid placeholder names collision in case
+// the expression is used more than once in the same query.
+//
+// Note that NULL values are considered smaller than any other value
+// (following the SQLite ordering).
+func cursorAfterExpr(keys []cursorKey, values []any, reverse bool, paramsPrefix string) dbx.Expression {
+	ors := make([]dbx.Expression, 0, len(keys))
+
+	for i, k := range keys {
+		ands := make([]dbx.Expression, 0, i+1)
+
+		// equality of all previous keys
+		for j := 0; j < i; j++ {
+			ands = append(ands, cursorCompareExpr(keys[j].identifier, "IS", values[j], paramsPrefix+strconv.Itoa(j)))
+		}
+
+		greater := k.desc == reverse
+
+		var expr dbx.Expression
+		switch {
+		case values[i] == nil && greater:
+			expr = dbx.NewExp(k.identifier + " IS NOT NULL")
+		case values[i] == nil:
+			// nothing is smaller than NULL
+			expr = dbx.NewExp("0=1")
+		case greater:
+			// the explicit NULL check ensures that the expression
+			// is always boolean (so that it can be safely negated)
+			expr = dbx.And(
+				dbx.NewExp(k.identifier+" IS NOT NULL"),
+				cursorCompareExpr(k.identifier, ">", values[i], paramsPrefix+strconv.Itoa(i)),
+			)
+		default:
+			expr = dbx.Or(
+				cursorCompareExpr(k.identifier, "<", values[i], paramsPrefix+strconv.Itoa(i)),
+				dbx.NewExp(k.identifier+" IS NULL"),
+			)
+		}
+		ands = append(ands, expr)
+
+		ors = append(ors, dbx.And(ands...))
+	}
+
+	return dbx.Enclose(dbx.Or(ors...))
+}
+
+func cursorCompareExpr(identifier string, op string, value any, param string) dbx.Expression {
+	if value == nil {
+		return dbx.NewExp(identifier + " IS NULL")
+	}
+
+	return dbx.NewExp(identifier+" "+op+" {:"+param+"}", dbx.Params{param: value})
+}
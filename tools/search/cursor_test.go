@@ -0,0 +1,256 @@
+package search
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/dbx"
+)
+
+func TestCursorEncodeDecode(t *testing.T) {
+	keys := []cursorKey{{"a", false}, {"b", true}, {"id", false}}
+
+	original := cursor{Values: []any{int64(10), 1.5, "abc"}, Prev: true}
+
+	encoded, err := encodeCursor(original, keys, "test_secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := decodeCursor(encoded, keys, "test_secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rawOriginal, _ := json.Marshal(original)
+	rawDecoded, _ := json.Marshal(decoded)
+	if string(rawOriginal) != string(rawDecoded) {
+		t.Fatalf("Expected %s, got %s", rawOriginal, rawDecoded)
+	}
+
+	if _, ok := decoded.Values[0].(int64); !ok {
+		t.Fatalf("Expected the integer type to be restored, got %T", decoded.Values[0])
+	}
+
+	payload, checksum, _ := strings.Cut(encoded, ".")
+
+	// unkeyed checksum of a crafted payload
+	craftedPayload := base64.RawURLEncoding.EncodeToString([]byte(`{"v":[1,2,"x"]}`))
+	craftedSum := sha256.Sum256([]byte(cursorSignature(keys) + "\x00" + craftedPayload))
+	crafted := craftedPayload + "." + base64.RawURLEncoding.EncodeToString(craftedSum[:16])
+
+	scenarios := []struct {
+		name   string
+		cursor string
+		keys   []cursorKey
+		secret string
+	}{
+		{"empty", "", keys, "test_secret"},
+		{"missing checksum", payload, keys, "test_secret"},
+		{"tampered checksum", payload + "." + checksum + "a", keys, "test_secret"},
+		{"tampered payload", "a" + payload + "." + checksum, keys, "test_secret"},
+		{"crafted payload with unkeyed checksum", crafted, keys, "test_secret"},
+		{"different secret", encoded, keys, "test_secret2"},
+		{"different sort direction", encoded, []cursorKey{{"a", true}, {"b", true}, {"id", false}}, "test_secret"},
+		{"different sort field", encoded, []cursorKey{{"c", false}, {"b", true}, {"id", false}}, "test_secret"},
+		{"different number of sort fields", encoded, []cursorKey{{"a", false}, {"id", false}}, "test_secret"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			_, err := decodeCursor(s.cursor, s.keys, s.secret)
+			if err != ErrInvalidCursor {
+				t.Fatalf("Expected ErrInvalidCursor, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCursorAfterExpr(t *testing.T) {
+	keys := []cursorKey{{"a", false}, {"b", true}}
+
+	scenarios := []struct {
+		name     string
+		values   []any
+		reverse  bool
+		expected string
+	}{
+		{
+			"after",
+			[]any{1, 2},
+			false,
+			"(((a IS NOT NULL) AND (a > {:p0})) OR ((a IS {:p0}) AND ((b < {:p1}) OR (b IS NULL))))",
+		},
+		{
+			"before",
+			[]any{1, 2},
+			true,
+			"(((a < {:p0}) OR (a IS NULL)) OR ((a IS {:p0}) AND ((b IS NOT NULL) AND (b > {:p1}))))",
+		},
+		{
+			"after nil values",
+			[]any{nil, nil},
+			false,
+			"((a IS NOT NULL) OR ((a IS NULL) AND (0=1)))",
+		},
+		{
+			"before nil values",
+			[]any{nil, nil},
+			true,
+			"((0=1) OR ((a IS NULL) AND (b IS NOT NULL)))",
+		},
+	}
+
+	db := dbx.NewFromDB(nil, "")
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			params := dbx.Params{}
+			raw := cursorAfterExpr(keys, s.values, s.reverse, "p").Build(db, params)
+			if raw != s.expected {
+				t.Fatalf("Expected\n%s\ngot\n%s", s.expected, raw)
+			}
+		})
+	}
+}
+
+func TestProviderExecCursor(t *testing.T) {
+	testDB, err := createTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer testDB.Close()
+
+	// note: the test DB has already 2 rows with test1 1 and 2
+	for i, test1 := range []int{3, 3, 3, 4} {
+		testDB.Insert("test", dbx.Params{"id": i + 3, "test1": test1}).Execute()
+	}
+
+	// collects the ids of all pages following the next (or prev) cursors
+	paginate := func(t *testing.T, sort []SortField, startCursor string, backward bool) ([][]int, *Result) {
+		pages := [][]int{}
+		cursor := startCursor
+
+		var lastResult *Result
+
+		for i := 0; i < 10; i++ {
+			items := []struct {
+				Id int `db:"id"`
+			}{}
+
+			result, err := NewProvider(&testFieldResolver{}).
+				Query(testDB.Select("*").From("test")).
+				Sort(sort).
+				PerPage(2).
+				SkipTotal(true).
+				Cursor(cursor).
+				Exec(&items)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lastResult = result
+
+			ids := make([]int, len(items))
+			for j, item := range items {
+				ids[j] = item.Id
+			}
+			pages = append(pages, ids)
+
+			if backward {
+				cursor = result.PrevCursor
+			} else {
+				cursor = result.NextCursor
+			}
+			if cursor == "" {
+				break
+			}
+		}
+
+		return pages, lastResult
+	}
+
+	t.Run("implicit id sort", func(t *testing.T) {
+		pages, last := paginate(t, nil, "", false)
+
+		expected := "[[1,2],[3,4],[5,6]]"
+		if raw, _ := json.Marshal(pages); string(raw) != expected {
+			t.Fatalf("Expected pages %s, got %s", expected, raw)
+		}
+
+		if last.PrevCursor == "" {
+			t.Fatal("Expected prevCursor for the last page")
+		}
+
+		// go back
+		pages, _ = paginate(t, nil, last.PrevCursor, true)
+
+		expected = "[[3,4],[1,2]]"
+		if raw, _ := json.Marshal(pages); string(raw) != expected {
+			t.Fatalf("Expected backward pages %s, got %s", expected, raw)
+		}
+	})
+
+	t.Run("desc sort with duplicated values", func(t *testing.T) {
+		sort := []SortField{{"test1", SortDesc}}
+
+		pages, last := paginate(t, sort, "", false)
+
+		expected := "[[6,3],[4,5],[2,1]]"
+		if raw, _ := json.Marshal(pages); string(raw) != expected {
+			t.Fatalf("Expected pages %s, got %s", expected, raw)
+		}
+
+		pages, _ = paginate(t, sort, last.PrevCursor, true)
+
+		expected = "[[4,5],[6,3]]"
+		if raw, _ := json.Marshal(pages); string(raw) != expected {
+			t.Fatalf("Expected backward pages %s, got %s", expected, raw)
+		}
+	})
+
+	t.Run("cursor from different sort", func(t *testing.T) {
+		_, last := paginate(t, []SortField{{"test1", SortDesc}}, "", false)
+
+		_, err := NewProvider(&testFieldResolver{}).
+			Query(testDB.Select("*").From("test")).
+			Sort([]SortField{{"test1", SortAsc}}).
+			Cursor(last.PrevCursor).
+			Exec(&[]testTableStruct{})
+		if err != ErrInvalidCursor {
+			t.Fatalf("Expected ErrInvalidCursor, got %v", err)
+		}
+	})
+
+	t.Run("random sort", func(t *testing.T) {
+		_, err := NewProvider(&testFieldResolver{}).
+			Query(testDB.Select("*").From("test")).
+			Sort([]SortField{{randomSortKey, SortAsc}}).
+			Cursor("").
+			Exec(&[]testTableStruct{})
+		if err == nil {
+			t.Fatal("Expected random sort error")
+		}
+	})
+
+	t.Run("with total", func(t *testing.T) {
+		result, err := NewProvider(&testFieldResolver{}).
+			Query(testDB.Select("*").From("test")).
+			PerPage(4).
+			Cursor("").
+			Exec(&[]testTableStruct{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if result.TotalItems != 6 || result.TotalPages != 2 {
+			t.Fatalf("Expected 6 total items and 2 total pages, got %d and %d", result.TotalItems, result.TotalPages)
+		}
+
+		if result.NextCursor == "" || result.PrevCursor != "" {
+			t.Fatalf("Expected only nextCursor, got %q and %q", result.NextCursor, result.PrevCursor)
+		}
+	})
+}
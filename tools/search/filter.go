@@ -163,9 +163,64 @@ func resolveTokenizedExpr(expr fexpr.Expr, fieldResolver FieldResolver) (dbx.Exp
 		return nil, fmt.Errorf("invalid right operand %q - %v", expr.Right.Literal, rErr)
 	}
 
+	if err := applyEqualityTransform(lResult, expr.Op, rResult); err != nil {
+		return nil, fmt.Errorf("invalid expression %q %s %q - %v", expr.Left.Literal, expr.Op, expr.Right.Literal, err)
+	}
+
 	return buildResolversExpr(lResult, expr.Op, rResult)
 }
 
+// applyEqualityTransform validates the operands with EqualityTransform (if any)
+// and replaces the static value of the other operand with its transformed version.
+func applyEqualityTransform(left *ResolverResult, op fexpr.SignOp, right *ResolverResult) error {
+	if left.EqualityTransform == nil && right.EqualityTransform == nil {
+		return nil // nothing to transform
+	}
+
+	switch op {
+	case fexpr.SignEq, fexpr.SignNeq, fexpr.SignAnyEq, fexpr.SignAnyNeq:
+	default:
+		return fmt.Errorf("only equality comparisons are allowed, got %q", op)
+	}
+
+	if left.EqualityTransform != nil && right.EqualityTransform != nil {
+		return nil // both operands are transformed the same way
+	}
+
+	transform := left.EqualityTransform
+	other := right
+	if transform == nil {
+		transform = right.EqualityTransform
+		other = left
+	}
+
+	if strings.EqualFold(other.Identifier, "null") {
+		return nil // the null comparison is handled by the operands coalesce
+	}
+
+	if len(other.Params) != 1 || other.MultiMatchSubQuery != nil {
+		return errors.New("the operand could be compared only with a single static value")
+	}
+
+	params := make(dbx.Params, 1)
+	for k, v := range other.Params {
+		if other.Identifier != "{:"+k+"}" {
+			return errors.New("the operand could be compared only with a single static value")
+		}
+
+		transformed, err := transform(v)
+		if err != nil {
+			return err
+		}
+
+		params[k] = transformed
+	}
+
+	other.Params = params
+
+	return nil
+}
+
 func buildResolversExpr(
 	left *ResolverResult,
 	op fexpr.SignOp,
@@ -305,15 +360,30 @@ func resolveToken(token fexpr.Token, fieldResolver FieldResolver) (*ResolverResu
 			Params:     dbx.Params{placeholder: cast.ToFloat64(token.Literal)},
 		}, nil
 	case fexpr.TokenFunction:
+		args, _ := token.Meta.([]fexpr.Token)
+
+		argTokenResolverFunc := func(argToken fexpr.Token) (*ResolverResult, error) {
+			result, err := resolveToken(argToken, fieldResolver)
+			if err == nil && result.EqualityTransform != nil {
+				return nil, fmt.Errorf("%q cannot be used as function argument", argToken.Literal)
+			}
+			return result, err
+		}
+
+		// resolver specific functions
+		if fr, ok := fieldResolver.(FunctionResolver); ok {
+			result, err := fr.ResolveFunction(token.Literal, argTokenResolverFunc, args...)
+			if err != nil || result != nil {
+				return result, err
+			}
+		}
+
 		fn, ok := TokenFunctions[token.Literal]
 		if !ok {
 			return nil, fmt.Errorf("unknown function %q", token.Literal)
 		}
 
-		args, _ := token.Meta.([]fexpr.Token)
-		return fn(func(argToken fexpr.Token) (*ResolverResult, error) {
-			return resolveToken(argToken, fieldResolver)
-		}, args...)
+		return fn(argTokenResolverFunc, args...)
 	}
 
 	return nil, fmt.Errorf("unsupported token type %q", token.Type)
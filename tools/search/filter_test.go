@@ -3,12 +3,14 @@ package search_test
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/ganigeorgiev/fexpr"
 	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/tools/search"
 )
@@ -339,3 +341,140 @@ func TestLikeParamsWrapping(t *testing.T) {
 		t.Fatalf("Expected query \n%s, \ngot \n%s", expectedQuery, calledQueries[0])
 	}
 }
+
+type testFunctionResolver struct {
+	*search.SimpleFieldResolver
+}
+
+func (r *testFunctionResolver) ResolveFunction(
+	name string,
+	argTokenResolverFunc func(fexpr.Token) (*search.ResolverResult, error),
+	args ...fexpr.Token,
+) (*search.ResolverResult, error) {
+	if name != "custom" {
+		return nil, nil
+	}
+
+	arg, err := argTokenResolverFunc(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return &search.ResolverResult{Identifier: "custom(" + arg.Identifier + ")", Params: arg.Params}, nil
+}
+
+func TestFilterDataBuildExprWithFunctionResolver(t *testing.T) {
+	resolver := &testFunctionResolver{search.NewSimpleFieldResolver(`^test\w+$`)}
+
+	scenarios := []struct {
+		filter      search.FilterData
+		expectError bool
+		expectSQL   string
+	}{
+		{"custom(test1) = 1", false, "custom([[test1]]) = {:"},
+		{"unknown(test1) = 1", true, ""},
+		// fallback to the global token functions
+		{"geoDistance(test1, test2, 1, 2) > 1", false, "acos("},
+	}
+
+	for _, s := range scenarios {
+		t.Run(string(s.filter), func(t *testing.T) {
+			expr, err := s.filter.BuildExpr(resolver)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr {
+				return
+			}
+
+			db := dbx.NewFromDB(nil, "")
+			rawSQL := expr.Build(db, dbx.Params{})
+
+			if !strings.Contains(rawSQL, s.expectSQL) {
+				t.Fatalf("Expected %q in %q", s.expectSQL, rawSQL)
+			}
+		})
+	}
+}
+
+type testEqualityTransformResolver struct {
+	*search.SimpleFieldResolver
+}
+
+func (r *testEqualityTransformResolver) Resolve(field string) (*search.ResolverResult, error) {
+	result, err := r.SimpleFieldResolver.Resolve(field)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(field, "secret") {
+		result.EqualityTransform = func(value any) (any, error) {
+			if value == "fail" {
+				return nil, errors.New("transform error")
+			}
+			return fmt.Sprintf("hash(%v)", value), nil
+		}
+	}
+
+	return result, nil
+}
+
+func TestFilterDataBuildExprWithEqualityTransform(t *testing.T) {
+	resolver := &testEqualityTransformResolver{search.NewSimpleFieldResolver(`^(test|secret)\w*$`)}
+
+	scenarios := []struct {
+		filter       search.FilterData
+		expectError  bool
+		expectSQL    string
+		expectParams []string
+	}{
+		{"secret1 = 'a'", false, "[[secret1]] = {:", []string{"hash(a)"}},
+		{"'a' != secret1", false, "[[secret1]]", []string{"hash(a)"}},
+		{"secret1 ?= 123", false, "[[secret1]] = {:", []string{"hash(123)"}},
+		{"secret1 ?!= 'a'", false, "[[secret1]]", []string{"hash(a)"}},
+		{"secret1 = null", false, "[[secret1]]", nil},
+		{"secret1 = secret2", false, "[[secret2]]", nil},
+		{"test1 = 'a'", false, "[[test1]] = {:", []string{"a"}},
+		{"secret1 = 'fail'", true, "", nil},
+		{"secret1 = test1", true, "", nil},
+		{"secret1 > 'a'", true, "", nil},
+		{"secret1 ~ 'a'", true, "", nil},
+		{"secret1 = geoDistance(test1, test2, 1, 2)", true, "", nil},
+		{"geoDistance(secret1, test2, 1, 2) > 1", true, "", nil},
+	}
+
+	for _, s := range scenarios {
+		t.Run(string(s.filter), func(t *testing.T) {
+			expr, err := s.filter.BuildExpr(resolver)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr {
+				return
+			}
+
+			db := dbx.NewFromDB(nil, "")
+			params := dbx.Params{}
+			rawSQL := expr.Build(db, params)
+
+			if !strings.Contains(rawSQL, s.expectSQL) {
+				t.Fatalf("Expected %q in %q", s.expectSQL, rawSQL)
+			}
+
+			values := make([]string, 0, len(params))
+			for _, v := range params {
+				values = append(values, fmt.Sprint(v))
+			}
+
+			if len(values) != len(s.expectParams) || (len(values) > 0 && fmt.Sprint(values) != fmt.Sprint(s.expectParams)) {
+				t.Fatalf("Expected params %v, got %v", s.expectParams, values)
+			}
+		})
+	}
+}
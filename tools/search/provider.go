@@ -4,6 +4,7 @@ import (
 	"errors"
 	"math"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -48,15 +49,18 @@ const (
 	SortQueryParam      string = "sort"
 	FilterQueryParam    string = "filter"
 	SkipTotalQueryParam string = "skipTotal"
+	CursorQueryParam    string = "cursor"
 )
 
 // Result defines the returned search result structure.
 type Result struct {
-	Items      any `json:"items"`
-	Page       int `json:"page"`
-	PerPage    int `json:"perPage"`
-	TotalItems int `json:"totalItems"`
-	TotalPages int `json:"totalPages"`
+	Items      any    `json:"items"`
+	Page       int    `json:"page"`
+	PerPage    int    `json:"perPage"`
+	TotalItems int    `json:"totalItems"`
+	TotalPages int    `json:"totalPages"`
+	NextCursor string `json:"nextCursor,omitempty"`
+	PrevCursor string `json:"prevCursor,omitempty"`
 }
 
 // Provider represents a single configured search provider instance.
@@ -69,6 +73,9 @@ type Provider struct {
 	page               int
 	perPage            int
 	skipTotal          bool
+	cursorMode         bool
+	cursor             string
+	cursorSecret       string
 	maxFilterExprLimit int
 	maxSortExprLimit   int
 }
@@ -123,6 +130,35 @@ func (s *Provider) SkipTotal(skipTotal bool) *Provider {
 	return s
 }
 
+// Cursor enables the cursor (aka. keyset) pagination mode and sets
+// the position from which the items will be fetched.
+//
+// An empty cursor string fetches the first page.
+//
+// In cursor mode the page field is ignored, the "id" field is appended
+// as last sort key (if not already) and the result contains opaque
+// NextCursor and PrevCursor strings that could be used to fetch the
+// adjacent pages with the same sort.
+func (s *Provider) Cursor(cursor string) *Provider {
+	s.cursorMode = true
+	s.cursor = cursor
+	return s
+}
+
+// CursorSecret sets the server-side secret used to sign
+// the generated pagination cursors.
+//
+// The cursor payload contains the raw sort key values of the boundary
+// item, so the provider fieldResolver is expected to allow sorting
+// only by fields that the requester can see.
+//
+// It is strongly recommended to set a secret for client facing providers,
+// otherwise a client could craft a valid cursor with an arbitrary payload.
+func (s *Provider) CursorSecret(secret string) *Provider {
+	s.cursorSecret = secret
+	return s
+}
+
 // CountCol allows changing the default column (id) that is used
 // to generate the COUNT SQL query statement.
 //
@@ -194,6 +230,10 @@ func (s *Provider) Parse(urlQuery string) error {
 		s.SkipTotal(v)
 	}
 
+	if params.Has(CursorQueryParam) {
+		s.Cursor(params.Get(CursorQueryParam))
+	}
+
 	if raw := params.Get(PageQueryParam); raw != "" {
 		v, err := strconv.Atoi(raw)
 		if err != nil {
@@ -223,11 +263,23 @@ func (s *Provider) Parse(urlQuery string) error {
 	return nil
 }
 
-// Exec executes the search provider and fills/scans
-// the provided `items` slice with the found models.
-func (s *Provider) Exec(items any) (*Result, error) {
+// BuildQuery returns a shallow clone of the provider's base query
+// with applied filter, sort and field resolver query modifications
+// (the pagination is not applied).
+//
+// It could be used to embed the search results in another query
+// (e.g. as a subquery of an aggregation).
+func (s *Provider) BuildQuery() (*dbx.SelectQuery, error) {
+	query, _, err := s.buildQuery()
+
+	return query, err
+}
+
+// buildQuery is similar to BuildQuery but additionally returns the
+// resolved cursor sort keys when the cursor pagination mode is enabled.
+func (s *Provider) buildQuery() (*dbx.SelectQuery, []cursorKey, error) {
 	if s.query == nil {
-		return nil, ErrEmptyQuery
+		return nil, nil, ErrEmptyQuery
 	}
 
 	// shallow clone the provider's query
@@ -236,11 +288,11 @@ func (s *Provider) Exec(items any) (*Result, error) {
 	// build filters
 	for _, f := range s.filter {
 		if len(f) > MaxFilterLength {
-			return nil, ErrFilterLengthLimit
+			return nil, nil, ErrFilterLengthLimit
 		}
 		expr, err := f.BuildExprWithLimit(s.fieldResolver, s.maxFilterExprLimit)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		if expr != nil {
 			modelsQuery.AndWhere(expr)
@@ -249,15 +301,21 @@ func (s *Provider) Exec(items any) (*Result, error) {
 
 	// apply sorting
 	if len(s.sort) > s.maxSortExprLimit {
-		return nil, ErrSortExprLimit
+		return nil, nil, ErrSortExprLimit
+	}
+	sortFields := s.sort
+	if s.cursorMode && (len(sortFields) == 0 || sortFields[len(sortFields)-1].Name != cursorKeyField) {
+		// ensure that the keyset is unique
+		sortFields = append(slices.Clone(sortFields), SortField{cursorKeyField, SortAsc})
 	}
-	for _, sortField := range s.sort {
+	var keys []cursorKey
+	for _, sortField := range sortFields {
 		if len(sortField.Name) > MaxSortFieldLength {
-			return nil, ErrSortFieldLengthLimit
+			return nil, nil, ErrSortFieldLengthLimit
 		}
 		expr, err := sortField.BuildExpr(s.fieldResolver)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		if expr != "" {
 			// ensure that _rowid_ expressions are always prefixed with the first FROM table
@@ -269,14 +327,45 @@ func (s *Provider) Exec(items any) (*Result, error) {
 			}
 
 			modelsQuery.AndOrderBy(expr)
+
+			if s.cursorMode {
+				if sortField.Name == randomSortKey {
+					return nil, nil, errors.New("random sort is not supported in cursor pagination mode")
+				}
+
+				idx := strings.LastIndex(expr, " ")
+				keys = append(keys, cursorKey{
+					identifier: expr[:idx],
+					desc:       strings.EqualFold(expr[idx+1:], SortDesc),
+				})
+			}
 		}
 	}
 
 	// apply field resolver query modifications (if any)
 	if err := s.fieldResolver.UpdateQuery(&modelsQuery); err != nil {
+		return nil, nil, err
+	}
+
+	return &modelsQuery, keys, nil
+}
+
+// Exec executes the search provider and fills/scans
+// the provided `items` slice with the found models.
+func (s *Provider) Exec(items any) (*Result, error) {
+	modelsQuery, keys, err := s.buildQuery()
+	if err != nil {
 		return nil, err
 	}
 
+	var currentCursor cursor
+	if s.cursorMode && s.cursor != "" {
+		currentCursor, err = decodeCursor(s.cursor, keys, s.cursorSecret)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// normalize page
 	if s.page <= 0 {
 		s.page = 1
@@ -294,7 +383,7 @@ func (s *Provider) Exec(items any) (*Result, error) {
 	totalPages := -1
 
 	// prepare a count query from the base one
-	countQuery := modelsQuery // shallow clone
+	countQuery := *modelsQuery // shallow clone
 	countExec := func() error {
 		queryInfo := countQuery.Info()
 		countCol := s.countCol
@@ -324,6 +413,15 @@ func (s *Provider) Exec(items any) (*Result, error) {
 		return modelsQuery.All(items)
 	}
 
+	var nextCursor, prevCursor string
+	if s.cursorMode {
+		modelsExec = func() error {
+			var err error
+			nextCursor, prevCursor, err = s.execCursor(modelsQuery, keys, currentCursor, items)
+			return err
+		}
+	}
+
 	if !s.skipTotal {
 		// execute the 2 queries concurrently
 		errg := new(errgroup.Group)
@@ -345,11 +443,139 @@ func (s *Provider) Exec(items any) (*Result, error) {
 		TotalItems: totalCount,
 		TotalPages: totalPages,
 		Items:      items,
+		NextCursor: nextCursor,
+		PrevCursor: prevCursor,
 	}
 
 	return result, nil
 }
 
+// execCursor fetches a single page of items positioned after (or before)
+// the provided cursor and returns the adjacent pages cursors.
+//
+// The sort keys of the page are fetched first and then the items are
+// loaded from the same keys window to ensure that the returned cursors
+// match the boundary items.
+func (s *Provider) execCursor(
+	modelsQuery *dbx.SelectQuery,
+	keys []cursorKey,
+	current cursor,
+	items any,
+) (nextCursor string, prevCursor string, err error) {
+	hasCursor := len(current.Values) > 0
+
+	// fetch the page sort keys
+	// ---
+	keysQuery := *modelsQuery // shallow clone
+
+	selects := make([]string, len(keys))
+	for i, k := range keys {
+		selects[i] = k.identifier + " AS [[__cursor" + strconv.Itoa(i) + "]]"
+	}
+	keysQuery.Select(selects...)
+
+	if current.Prev {
+		// fetch in reversed order and flip later
+		orderBy := make([]string, len(keys))
+		for i, k := range keys {
+			if k.desc {
+				orderBy[i] = k.identifier + " " + SortAsc
+			} else {
+				orderBy[i] = k.identifier + " " + SortDesc
+			}
+		}
+		keysQuery.OrderBy(orderBy...)
+	}
+
+	if hasCursor {
+		keysQuery.AndWhere(cursorAfterExpr(keys, current.Values, current.Prev, "cursor"))
+	}
+
+	rows, err := keysQuery.Limit(int64(s.perPage + 1)).Rows()
+	if err != nil {
+		return "", "", err
+	}
+	defer rows.Close()
+
+	pageKeys := [][]any{}
+	for rows.Next() {
+		values := make([]any, len(keys))
+		pointers := make([]any, len(keys))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return "", "", err
+		}
+		for i, v := range values {
+			values[i] = normalizeCursorValue(v)
+		}
+		pageKeys = append(pageKeys, values)
+	}
+	if err := rows.Err(); err != nil {
+		return "", "", err
+	}
+
+	hasMore := len(pageKeys) > s.perPage
+	if hasMore {
+		pageKeys = pageKeys[:s.perPage]
+	}
+
+	if current.Prev {
+		slices.Reverse(pageKeys)
+	}
+
+	// fetch the models from the keys window
+	// ---
+	itemsQuery := *modelsQuery // shallow clone
+
+	if len(pageKeys) == 0 {
+		itemsQuery.AndWhere(dbx.NewExp("0=1"))
+	} else {
+		itemsQuery.AndWhere(dbx.And(
+			dbx.Not(cursorAfterExpr(keys, pageKeys[0], true, "cursor_first")),
+			dbx.Not(cursorAfterExpr(keys, pageKeys[len(pageKeys)-1], false, "cursor_last")),
+		))
+	}
+
+	if err := itemsQuery.Limit(int64(s.perPage)).All(items); err != nil {
+		return "", "", err
+	}
+
+	// generate the adjacent pages cursors
+	// ---
+	var hasNext, hasPrev bool
+	if current.Prev {
+		hasNext = hasCursor
+		hasPrev = hasMore
+	} else {
+		hasNext = hasMore
+		hasPrev = hasCursor
+	}
+
+	nextValues, prevValues := current.Values, current.Values
+	if len(pageKeys) > 0 {
+		prevValues = pageKeys[0]
+		nextValues = pageKeys[len(pageKeys)-1]
+	}
+
+	if hasNext {
+		nextCursor, err = encodeCursor(cursor{Values: nextValues}, keys, s.cursorSecret)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	if hasPrev {
+		prevCursor, err = encodeCursor(cursor{Values: prevValues, Prev: true}, keys, s.cursorSecret)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	return nextCursor, prevCursor, nil
+}
+
 // ParseAndExec is a short convenient method to trigger both
 // `Parse()` and `Exec()` in a single call.
 func (s *Provider) ParseAndExec(urlQuery string, modelsSlice any) (*Result, error) {
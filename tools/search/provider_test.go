@@ -98,6 +98,65 @@ func TestProviderSkipTotal(t *testing.T) {
 	}
 }
 
+func TestProviderCursor(t *testing.T) {
+	p := NewProvider(&testFieldResolver{})
+
+	if p.cursorMode {
+		t.Fatal("Expected the cursor mode to be disabled by default")
+	}
+
+	p.Cursor("test")
+
+	if !p.cursorMode || p.cursor != "test" {
+		t.Fatalf("Expected the cursor mode to be enabled with cursor %q, got %v and %q", "test", p.cursorMode, p.cursor)
+	}
+}
+
+func TestProviderCursorSecret(t *testing.T) {
+	p := NewProvider(&testFieldResolver{})
+
+	if p.cursorSecret != "" {
+		t.Fatalf("Expected empty cursor secret by default, got %q", p.cursorSecret)
+	}
+
+	p.CursorSecret("test")
+
+	if p.cursorSecret != "test" {
+		t.Fatalf("Expected cursor secret %q, got %q", "test", p.cursorSecret)
+	}
+}
+
+func TestProviderParseCursor(t *testing.T) {
+	scenarios := []struct {
+		query            string
+		expectCursorMode bool
+		expectCursor     string
+	}{
+		{"", false, ""},
+		{"page=2", false, ""},
+		{"cursor=", true, ""},
+		{"cursor=abc", true, "abc"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.query, func(t *testing.T) {
+			p := NewProvider(&testFieldResolver{})
+
+			if err := p.Parse(s.query); err != nil {
+				t.Fatal(err)
+			}
+
+			if p.cursorMode != s.expectCursorMode {
+				t.Fatalf("Expected cursorMode %v, got %v", s.expectCursorMode, p.cursorMode)
+			}
+
+			if p.cursor != s.expectCursor {
+				t.Fatalf("Expected cursor %q, got %q", s.expectCursor, p.cursor)
+			}
+		})
+	}
+}
+
 func TestProviderCountCol(t *testing.T) {
 	p := NewProvider(&testFieldResolver{})
 
@@ -258,6 +317,51 @@ func TestProviderParse(t *testing.T) {
 	}
 }
 
+func TestProviderBuildQuery(t *testing.T) {
+	testDB, err := createTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer testDB.Close()
+
+	r := &testFieldResolver{}
+
+	// empty query
+	if _, err := NewProvider(r).BuildQuery(); err == nil {
+		t.Fatal("Expected error with empty query, got nil")
+	}
+
+	baseQuery := testDB.Select("*").From("test")
+
+	query, err := NewProvider(r).
+		Query(baseQuery).
+		Page(2).
+		PerPage(1).
+		Filter([]FilterData{"test1 > test3"}).
+		Sort([]SortField{{"test2", SortDesc}}).
+		BuildQuery()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if query == baseQuery {
+		t.Fatal("Expected the base query to be cloned")
+	}
+
+	if r.UpdateQueryCalls != 1 {
+		t.Fatalf("Expected UpdateQuery to be called once, got %d", r.UpdateQueryCalls)
+	}
+
+	expectedSQL := "SELECT * FROM `test` WHERE test1 > test3 ORDER BY `test2` DESC"
+	if rawSQL := query.Build().SQL(); rawSQL != expectedSQL {
+		t.Fatalf("Expected\n%s\ngot\n%s", expectedSQL, rawSQL)
+	}
+
+	if baseSQL := baseQuery.Build().SQL(); baseSQL != "SELECT * FROM `test`" {
+		t.Fatalf("Expected the base query to remain unchanged, got %s", baseSQL)
+	}
+}
+
 func TestProviderExecEmptyQuery(t *testing.T) {
 	p := NewProvider(&testFieldResolver{}).
 		Query(nil)
@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/ganigeorgiev/fexpr"
 	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/tools/inflector"
 	"github.com/pocketbase/pocketbase/tools/list"
@@ -31,6 +32,16 @@ type ResolverResult struct {
 	// AfterBuild is an optional function that will be called after building
 	// and combining the result of both resolved operands/sides in a single expression.
 	AfterBuild func(expr dbx.Expression) dbx.Expression
+
+	// EqualityTransform is an optional function that restricts the resolved
+	// operand to equality comparisons (=, !=, ?=, ?!=) with a single static
+	// value or another operand with EqualityTransform.
+	//
+	// The static value of the other operand is transformed with the function
+	// before building the expression (e.g. to compare against a blind index).
+	//
+	// Operands with EqualityTransform cannot be sorted or used as function arguments.
+	EqualityTransform func(value any) (any, error)
 }
 
 // FieldResolver defines an interface for managing search fields.
@@ -46,6 +57,22 @@ type FieldResolver interface {
 	Resolve(field string) (*ResolverResult, error)
 }
 
+// FunctionResolver defines an optional FieldResolver interface for
+// resolving resolver specific filter functions (e.g. fts()).
+//
+// The resolver functions have precedence over the global [TokenFunctions].
+type FunctionResolver interface {
+	// ResolveFunction resolves a single filter function call.
+	//
+	// It should return (nil, nil) if the function name is not supported
+	// by the resolver so that the global [TokenFunctions] could be checked.
+	ResolveFunction(
+		name string,
+		argTokenResolverFunc func(fexpr.Token) (*ResolverResult, error),
+		args ...fexpr.Token,
+	) (*ResolverResult, error)
+}
+
 // NewSimpleFieldResolver creates a new `SimpleFieldResolver` with the
 // provided `allowedFields`.
 //
@@ -1,8 +1,14 @@
 package search
 
 import (
+	"encoding/hex"
 	"fmt"
+	"strconv"
 	"strings"
+
+	"github.com/ganigeorgiev/fexpr"
+	"github.com/pocketbase/dbx"
+	"github.com/spf13/cast"
 )
 
 const (
@@ -34,24 +40,94 @@ func (s *SortField) BuildExpr(fieldResolver FieldResolver) (string, error) {
 		return fmt.Sprintf("[[_rowid_]] %s", s.Direction), nil
 	}
 
+	// function sort expression (e.g. "geoDistance(lon, lat, 1, 2)")
+	if strings.HasSuffix(s.Name, ")") {
+		return buildFunctionSortExpr(s.Name, s.Direction, fieldResolver)
+	}
+
 	result, err := fieldResolver.Resolve(s.Name)
 
 	// invalidate empty fields and non-column identifiers
-	if err != nil || len(result.Params) > 0 || result.Identifier == "" || strings.ToLower(result.Identifier) == "null" {
+	if err != nil || len(result.Params) > 0 || result.Identifier == "" || strings.ToLower(result.Identifier) == "null" || result.EqualityTransform != nil {
 		return "", fmt.Errorf("invalid sort field %q", s.Name)
 	}
 
 	return fmt.Sprintf("%s %s", result.Identifier, s.Direction), nil
 }
 
+// buildFunctionSortExpr resolves a single filter function sort expression.
+//
+// Because the sort expressions are plain strings, the resolved
+// function params are inlined as SQL literals.
+func buildFunctionSortExpr(name string, direction string, fieldResolver FieldResolver) (string, error) {
+	scanner := fexpr.NewScanner([]byte(name))
+
+	token, err := scanner.Scan()
+	if err != nil || token.Type != fexpr.TokenFunction {
+		return "", fmt.Errorf("invalid sort function %q", name)
+	}
+
+	if next, _ := scanner.Scan(); next.Type != fexpr.TokenEOF {
+		return "", fmt.Errorf("invalid sort function %q", name)
+	}
+
+	result, err := resolveToken(token, fieldResolver)
+	if err != nil || result.Identifier == "" {
+		return "", fmt.Errorf("invalid sort function %q: %w", name, err)
+	}
+
+	expr, err := inlineParams(result.Identifier, result.Params)
+	if err != nil {
+		return "", fmt.Errorf("invalid sort function %q: %w", name, err)
+	}
+
+	return fmt.Sprintf("%s %s", expr, direction), nil
+}
+
+// inlineParams replaces the expr param placeholders with their SQL literal value.
+//
+// Text values are inlined as hex blobs casted to TEXT to prevent
+// the dbx quoting and placeholders processing of the literal.
+func inlineParams(expr string, params dbx.Params) (string, error) {
+	for k, v := range params {
+		var literal string
+
+		switch val := v.(type) {
+		case nil:
+			literal = "NULL"
+		case bool:
+			if val {
+				literal = "1"
+			} else {
+				literal = "0"
+			}
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+			literal = strconv.FormatFloat(cast.ToFloat64(val), 'g', -1, 64)
+		case string:
+			literal = "CAST(X'" + hex.EncodeToString([]byte(val)) + "' AS TEXT)"
+		case []byte:
+			literal = "X'" + hex.EncodeToString(val) + "'"
+		default:
+			return "", fmt.Errorf("unsupported param value type %T", v)
+		}
+
+		expr = strings.ReplaceAll(expr, "{:"+k+"}", literal)
+	}
+
+	return expr, nil
+}
+
 // ParseSortFromString parses the provided string expression
 // into a slice of SortFields.
 //
+// The commas inside function arguments and quoted texts are ignored,
+// aka. "-geoDistance(lon, lat, 1, 2),name" is split into 2 sort fields.
+//
 // Example:
 //
 //	fields := search.ParseSortFromString("-name,+created")
 func ParseSortFromString(str string) (fields []SortField) {
-	data := strings.Split(str, ",")
+	data := splitSortString(str)
 
 	for _, field := range data {
 		// trim whitespaces
@@ -65,3 +141,33 @@ func ParseSortFromString(str string) (fields []SortField) {
 
 	return
 }
+
+// splitSortString splits the sort string by its top level commas.
+func splitSortString(str string) []string {
+	var result []string
+	var depth int
+	var quote rune
+	var start int
+
+	for i, ch := range str {
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case ch == '(':
+			depth++
+		case ch == ')':
+			if depth > 0 {
+				depth--
+			}
+		case ch == ',' && depth == 0:
+			result = append(result, str[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(result, str[start:])
+}
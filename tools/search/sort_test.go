@@ -32,6 +32,24 @@ func TestSortFieldBuildExpr(t *testing.T) {
 		{search.SortField{"@random", search.SortDesc}, false, "RANDOM()"},
 		// special _rowid_ field
 		{search.SortField{"@rowid", search.SortDesc}, false, "[[_rowid_]] DESC"},
+		// unknown function
+		{search.SortField{"unknown(test1)", search.SortAsc}, true, ""},
+		// function with unknown field argument
+		{search.SortField{"vectorDistance(unknown, '[1,2]')", search.SortAsc}, true, ""},
+		// function with trailing expression
+		{search.SortField{"vectorDistance(test1, '[1,2]') > 1)", search.SortAsc}, true, ""},
+		// function
+		{
+			search.SortField{"geoDistance(test1, test2, 1.5, 2)", search.SortDesc},
+			false,
+			"(6371 * acos(cos(radians([[test2]])) * cos(radians(2)) * cos(radians(1.5) - radians([[test1]])) + sin(radians([[test2]])) * sin(radians(2)))) DESC",
+		},
+		// function with text arguments
+		{
+			search.SortField{"vectorDistance(test1, '[1,2]', 'l2')", search.SortAsc},
+			false,
+			"pb_vector_distance([[test1]], CAST(X'5b312c325d' AS TEXT), CAST(X'6c32' AS TEXT)) ASC",
+		},
 	}
 
 	for _, s := range scenarios {
@@ -50,6 +68,18 @@ func TestSortFieldBuildExpr(t *testing.T) {
 	}
 }
 
+func TestSortFieldBuildExprWithEqualityTransform(t *testing.T) {
+	resolver := &testEqualityTransformResolver{search.NewSimpleFieldResolver("test1", "secret1")}
+
+	if _, err := (&search.SortField{"test1", search.SortAsc}).BuildExpr(resolver); err != nil {
+		t.Fatalf("Expected test1 to be sortable, got %v", err)
+	}
+
+	if _, err := (&search.SortField{"secret1", search.SortAsc}).BuildExpr(resolver); err == nil {
+		t.Fatal("Expected secret1 to not be sortable")
+	}
+}
+
 func TestParseSortFromString(t *testing.T) {
 	scenarios := []struct {
 		value    string
@@ -62,6 +92,8 @@ func TestParseSortFromString(t *testing.T) {
 		{"test1,-test2,+test3", `[{"name":"test1","direction":"ASC"},{"name":"test2","direction":"DESC"},{"name":"test3","direction":"ASC"}]`},
 		{"@random,-test", `[{"name":"@random","direction":"ASC"},{"name":"test","direction":"DESC"}]`},
 		{"-@rowid,-test", `[{"name":"@rowid","direction":"DESC"},{"name":"test","direction":"DESC"}]`},
+		{"-geoDistance(a, b, 1, 2),test", `[{"name":"geoDistance(a, b, 1, 2)","direction":"DESC"},{"name":"test","direction":"ASC"}]`},
+		{"vectorDistance(a, '[1,2)'), -test", `[{"name":"vectorDistance(a, '[1,2)')","direction":"ASC"},{"name":"test","direction":"DESC"}]`},
 	}
 
 	for _, s := range scenarios {
@@ -2,8 +2,13 @@ package search
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/ganigeorgiev/fexpr"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/pocketbase/pocketbase/tools/types"
+	"github.com/pocketbase/pocketbase/tools/vector"
 )
 
 var TokenFunctions = map[string]func(
@@ -53,4 +58,61 @@ var TokenFunctions = map[string]func(
 			Params: mergeParams(resolvedArgs[0].Params, resolvedArgs[1].Params, resolvedArgs[2].Params, resolvedArgs[3].Params),
 		}, nil
 	},
+
+	// vectorDistance(vecA, vecB, [metric]) calculates the distance between 2 vectors
+	// using the specified metric ("cosine" (default), "l2" or "dot").
+	//
+	// The vector arguments could be either a column identifier (e.g. a "vector" field or
+	// a @request.* placeholder) or a serialized json array text (e.g. '[0.1, 0.2, 0.3]').
+	//
+	// The function resolves to NULL if any of the vectors is empty or
+	// the vectors dimensions don't match.
+	//
+	// Example:
+	//
+	//	vectorDistance(embedding, @request.query.q, 'l2') < 0.5
+	"vectorDistance": func(argTokenResolverFunc func(fexpr.Token) (*ResolverResult, error), args ...fexpr.Token) (*ResolverResult, error) {
+		if len(args) != 2 && len(args) != 3 {
+			return nil, fmt.Errorf("[vectorDistance] expected 2 or 3 arguments, got %d", len(args))
+		}
+
+		resolvedArgs := make([]*ResolverResult, 2)
+		for i, arg := range args[:2] {
+			switch arg.Type {
+			case fexpr.TokenIdentifier:
+				// resolved below
+			case fexpr.TokenText:
+				if _, err := types.ParseVector(arg.Literal); err != nil {
+					return nil, fmt.Errorf("[vectorDistance] argument %d must be a valid json array: %w", i, err)
+				}
+			default:
+				return nil, fmt.Errorf("[vectorDistance] argument %d must be an identifier or text", i)
+			}
+
+			resolved, err := argTokenResolverFunc(arg)
+			if err != nil {
+				return nil, fmt.Errorf("[vectorDistance] failed to resolve argument %d: %w", i, err)
+			}
+			resolvedArgs[i] = resolved
+		}
+
+		metric := vector.MetricCosine
+		if len(args) == 3 {
+			if args[2].Type != fexpr.TokenText || !slices.Contains(vector.Metrics, args[2].Literal) {
+				return nil, fmt.Errorf("[vectorDistance] metric argument must be one of %v", vector.Metrics)
+			}
+			metric = args[2].Literal
+		}
+
+		metricPlaceholder := "t" + security.PseudorandomString(8)
+
+		return &ResolverResult{
+			NoCoalesce: true,
+			Identifier: vector.SQLDistanceFunction + "(" +
+				resolvedArgs[0].Identifier + ", " +
+				resolvedArgs[1].Identifier + ", " +
+				"{:" + metricPlaceholder + "})",
+			Params: mergeParams(resolvedArgs[0].Params, resolvedArgs[1].Params, dbx.Params{metricPlaceholder: metric}),
+		}, nil
+	},
 }
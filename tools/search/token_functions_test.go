@@ -209,6 +209,141 @@ func TestTokenFunctionsGeoDistanceExec(t *testing.T) {
 	}
 }
 
+func TestTokenFunctionsVectorDistance(t *testing.T) {
+	t.Parallel()
+
+	fn, ok := TokenFunctions["vectorDistance"]
+	if !ok {
+		t.Error("Expected vectorDistance token function to be registered.")
+	}
+
+	baseTokenResolver := func(t fexpr.Token) (*ResolverResult, error) {
+		if t.Type == fexpr.TokenIdentifier {
+			return &ResolverResult{Identifier: "[[" + t.Literal + "]]"}, nil
+		}
+		placeholder := "t" + security.PseudorandomString(5)
+		return &ResolverResult{Identifier: "{:" + placeholder + "}", Params: map[string]any{placeholder: t.Literal}}, nil
+	}
+
+	scenarios := []struct {
+		name      string
+		args      []fexpr.Token
+		resolver  func(t fexpr.Token) (*ResolverResult, error)
+		result    *ResolverResult
+		expectErr bool
+	}{
+		{
+			"no args",
+			nil,
+			baseTokenResolver,
+			nil,
+			true,
+		},
+		{
+			"> 3 args",
+			[]fexpr.Token{
+				{Literal: "a", Type: fexpr.TokenIdentifier},
+				{Literal: "b", Type: fexpr.TokenIdentifier},
+				{Literal: "l2", Type: fexpr.TokenText},
+				{Literal: "c", Type: fexpr.TokenIdentifier},
+			},
+			baseTokenResolver,
+			nil,
+			true,
+		},
+		{
+			"unsupported number argument",
+			[]fexpr.Token{
+				{Literal: "a", Type: fexpr.TokenIdentifier},
+				{Literal: "1", Type: fexpr.TokenNumber},
+			},
+			baseTokenResolver,
+			nil,
+			true,
+		},
+		{
+			"invalid json array argument",
+			[]fexpr.Token{
+				{Literal: "a", Type: fexpr.TokenIdentifier},
+				{Literal: "[1,", Type: fexpr.TokenText},
+			},
+			baseTokenResolver,
+			nil,
+			true,
+		},
+		{
+			"unknown metric",
+			[]fexpr.Token{
+				{Literal: "a", Type: fexpr.TokenIdentifier},
+				{Literal: "b", Type: fexpr.TokenIdentifier},
+				{Literal: "unknown", Type: fexpr.TokenText},
+			},
+			baseTokenResolver,
+			nil,
+			true,
+		},
+		{
+			"resolver error",
+			[]fexpr.Token{
+				{Literal: "a", Type: fexpr.TokenIdentifier},
+				{Literal: "b", Type: fexpr.TokenIdentifier},
+			},
+			func(t fexpr.Token) (*ResolverResult, error) {
+				return nil, errors.New("test")
+			},
+			nil,
+			true,
+		},
+		{
+			"default metric",
+			[]fexpr.Token{
+				{Literal: "a", Type: fexpr.TokenIdentifier},
+				{Literal: "[1, 2]", Type: fexpr.TokenText},
+			},
+			baseTokenResolver,
+			&ResolverResult{
+				NoCoalesce: true,
+				Identifier: `pb_vector_distance([[a]], {:b}, {:metric})`,
+				Params: map[string]any{
+					"b":      "[1, 2]",
+					"metric": "cosine",
+				},
+			},
+			false,
+		},
+		{
+			"explicit metric",
+			[]fexpr.Token{
+				{Literal: "a", Type: fexpr.TokenIdentifier},
+				{Literal: "b", Type: fexpr.TokenIdentifier},
+				{Literal: "dot", Type: fexpr.TokenText},
+			},
+			baseTokenResolver,
+			&ResolverResult{
+				NoCoalesce: true,
+				Identifier: `pb_vector_distance([[a]], [[b]], {:metric})`,
+				Params: map[string]any{
+					"metric": "dot",
+				},
+			},
+			false,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result, err := fn(s.resolver, s.args...)
+
+			hasErr := err != nil
+			if hasErr != s.expectErr {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectErr, hasErr, err)
+			}
+
+			testCompareResults(t, s.result, result)
+		})
+	}
+}
+
 // -------------------------------------------------------------------
 
 func testCompareResults(t *testing.T, a, b *ResolverResult) {
@@ -0,0 +1,377 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cast"
+)
+
+// maxDecimalExponent limits the parsed decimal exponent to prevent
+// allocating arbitrary large numbers (eg. "1e1000000000").
+const maxDecimalExponent = 1000
+
+var bigTen = big.NewInt(10)
+
+// Decimal defines an exact fixed-point decimal number
+// (aka. an arbitrary precision integer with a decimal scale).
+//
+// The zero value is a ready to use 0 decimal.
+//
+// Decimal values are immutable - all arithmetic methods return a new value.
+//
+// By default Decimal is serialized in JSON as string to prevent precision loss in the
+// JSON consumers (use [Decimal.WithJSONNumber] to serialize it as JSON number).
+type Decimal struct {
+	unscaled   *big.Int // nil is treated as 0
+	scale      int32
+	jsonNumber bool
+}
+
+// NewDecimal creates a new Decimal from the provided unscaled value and scale,
+// aka. NewDecimal(1250, 2) is 12.50.
+//
+// Negative scale is normalized to 0 (aka. NewDecimal(12, -2) is 1200).
+func NewDecimal(unscaled int64, scale int32) Decimal {
+	return newDecimal(big.NewInt(unscaled), scale)
+}
+
+// NewDecimalFromFloat creates a new Decimal from the shortest decimal
+// representation of the provided float64 number (aka. 0.1 is exactly 0.1).
+func NewDecimalFromFloat(f float64) (Decimal, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Decimal{}, fmt.Errorf("[Decimal] unsupported float value %v", f)
+	}
+
+	return ParseDecimal(strconv.FormatFloat(f, 'g', -1, 64))
+}
+
+// MustParseDecimal is similar to [ParseDecimal] but panics on error.
+func MustParseDecimal(str string) Decimal {
+	d, err := ParseDecimal(str)
+	if err != nil {
+		panic(err)
+	}
+
+	return d
+}
+
+// ParseDecimal parses the provided decimal string representation.
+//
+// Supported formats: "123", "-123.450", "+.5", "1.5e3", "15E-1".
+func ParseDecimal(str string) (Decimal, error) {
+	s := strings.TrimSpace(str)
+
+	invalidErr := fmt.Errorf("[Decimal] invalid decimal string %q", str)
+
+	var exponent int64
+	if idx := strings.IndexAny(s, "eE"); idx >= 0 {
+		var err error
+		exponent, err = strconv.ParseInt(s[idx+1:], 10, 32)
+		if err != nil || exponent > maxDecimalExponent || exponent < -maxDecimalExponent {
+			return Decimal{}, invalidErr
+		}
+		s = s[:idx]
+	}
+
+	var negative bool
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		negative = s[0] == '-'
+		s = s[1:]
+	}
+
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	digits := intPart + fracPart
+	if digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return Decimal{}, invalidErr
+	}
+
+	unscaled, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return Decimal{}, invalidErr
+	}
+
+	if negative {
+		unscaled.Neg(unscaled)
+	}
+
+	return newDecimal(unscaled, int32(int64(len(fracPart))-exponent)), nil
+}
+
+// newDecimal creates a new decimal and normalizes the negative scale.
+func newDecimal(unscaled *big.Int, scale int32) Decimal {
+	if scale < 0 {
+		unscaled = new(big.Int).Mul(unscaled, pow10(-scale))
+		scale = 0
+	}
+
+	return Decimal{unscaled: unscaled, scale: scale}
+}
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
+
+func (d Decimal) bigInt() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+
+	return d.unscaled
+}
+
+// Scale returns the number of the decimal digits after the decimal point.
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// Unscaled returns a copy of the decimal unscaled integer value.
+func (d Decimal) Unscaled() *big.Int {
+	return new(big.Int).Set(d.bigInt())
+}
+
+// Precision returns the number of significant digits
+// (including the scale digits, aka. 12.50 has precision 4).
+func (d Decimal) Precision() int {
+	str := new(big.Int).Abs(d.bigInt()).String()
+
+	return max(len(str), int(d.scale))
+}
+
+// Sign returns -1 if d < 0, 0 if d == 0 and +1 if d > 0.
+func (d Decimal) Sign() int {
+	return d.bigInt().Sign()
+}
+
+// IsZero checks whether the current decimal is 0.
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Cmp compares d and other and returns -1 if d < other, 0 if d == other and +1 if d > other.
+//
+// The scale is ignored, aka. 1.50 == 1.5.
+func (d Decimal) Cmp(other Decimal) int {
+	a, b := alignDecimals(d, other)
+
+	return a.Cmp(b)
+}
+
+// Equal checks whether d and other represent the same number (scale is ignored).
+func (d Decimal) Equal(other Decimal) bool {
+	return d.Cmp(other) == 0
+}
+
+// Add returns d + other (with the larger of the 2 scales).
+func (d Decimal) Add(other Decimal) Decimal {
+	a, b := alignDecimals(d, other)
+
+	return d.withValue(new(big.Int).Add(a, b), max(d.scale, other.scale))
+}
+
+// Sub returns d - other (with the larger of the 2 scales).
+func (d Decimal) Sub(other Decimal) Decimal {
+	a, b := alignDecimals(d, other)
+
+	return d.withValue(new(big.Int).Sub(a, b), max(d.scale, other.scale))
+}
+
+// Mul returns d * other (with scale equal to the sum of the 2 scales).
+func (d Decimal) Mul(other Decimal) Decimal {
+	return d.withValue(new(big.Int).Mul(d.bigInt(), other.bigInt()), d.scale+other.scale)
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	return d.withValue(new(big.Int).Neg(d.bigInt()), d.scale)
+}
+
+// Round returns d rounded to the specified scale using the
+// "half away from zero" rounding mode (aka. 1.005 -> 1.01, -1.005 -> -1.01).
+//
+// If scale is larger than the current one, the number is padded with zeros.
+func (d Decimal) Round(scale int32) Decimal {
+	if scale < 0 {
+		scale = 0
+	}
+
+	if scale >= d.scale {
+		return d.withValue(new(big.Int).Mul(d.bigInt(), pow10(scale-d.scale)), scale)
+	}
+
+	divisor := pow10(d.scale - scale)
+
+	q, r := new(big.Int).QuoRem(new(big.Int).Abs(d.bigInt()), divisor, new(big.Int))
+	if r.Lsh(r, 1).Cmp(divisor) >= 0 {
+		q.Add(q, big.NewInt(1))
+	}
+
+	if d.Sign() < 0 {
+		q.Neg(q)
+	}
+
+	return d.withValue(q, scale)
+}
+
+// Float64 returns the nearest float64 value of d.
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+
+	return f
+}
+
+// WithJSONNumber returns a copy of d that is serialized as JSON number
+// instead of string (note that some JSON consumers may lose precision).
+func (d Decimal) WithJSONNumber(enable bool) Decimal {
+	d.jsonNumber = enable
+
+	return d
+}
+
+// String returns the plain string representation of the decimal
+// with exactly Scale digits after the decimal point (eg. "-12.50").
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.bigInt()).String()
+
+	var sb strings.Builder
+
+	if d.Sign() < 0 {
+		sb.WriteString("-")
+	}
+
+	if d.scale == 0 {
+		sb.WriteString(digits)
+		return sb.String()
+	}
+
+	if pad := int(d.scale) + 1 - len(digits); pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+
+	sb.WriteString(digits[:len(digits)-int(d.scale)])
+	sb.WriteString(".")
+	sb.WriteString(digits[len(digits)-int(d.scale):])
+
+	return sb.String()
+}
+
+// MarshalJSON implements the [json.Marshaler] interface.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	if d.jsonNumber {
+		return []byte(d.String()), nil
+	}
+
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON implements the [json.Unmarshaler] interface.
+//
+// Both JSON numbers and strings are accepted.
+func (d *Decimal) UnmarshalJSON(b []byte) error {
+	str := string(b)
+
+	if str == "null" {
+		*d = Decimal{}
+		return nil
+	}
+
+	if strings.HasPrefix(str, `"`) {
+		if err := json.Unmarshal(b, &str); err != nil {
+			return err
+		}
+	}
+
+	parsed, err := ParseDecimal(str)
+	if err != nil {
+		return err
+	}
+
+	*d = parsed.WithJSONNumber(d.jsonNumber)
+
+	return nil
+}
+
+// Value implements the [driver.Valuer] interface.
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// Scan implements [sql.Scanner] interface to scan the provided value
+// into the current Decimal instance.
+//
+// The value argument could be nil (zero decimal), another Decimal,
+// decimal string (or bytes), bool or a regular Go number.
+func (d *Decimal) Scan(value any) error {
+	var parsed Decimal
+	var err error
+
+	switch v := value.(type) {
+	case nil:
+		// zero decimal
+	case Decimal:
+		parsed = v
+	case *Decimal:
+		if v != nil {
+			parsed = *v
+		}
+	case string:
+		if strings.TrimSpace(v) != "" {
+			parsed, err = ParseDecimal(v)
+		}
+	case []byte:
+		if len(v) > 0 {
+			parsed, err = ParseDecimal(string(v))
+		}
+	case bool:
+		if v {
+			parsed = NewDecimal(1, 0)
+		}
+	case float32:
+		parsed, err = NewDecimalFromFloat(float64(v))
+	case float64:
+		parsed, err = NewDecimalFromFloat(v)
+	case int, int8, int16, int32, int64:
+		parsed = NewDecimal(cast.ToInt64(v), 0)
+	case uint, uint8, uint16, uint32, uint64:
+		parsed = newDecimal(new(big.Int).SetUint64(cast.ToUint64(v)), 0)
+	default:
+		str, castErr := cast.ToStringE(v)
+		if castErr != nil {
+			return fmt.Errorf("[Decimal] unsupported value type %T", value)
+		}
+		parsed, err = ParseDecimal(str)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	*d = parsed.WithJSONNumber(d.jsonNumber)
+
+	return nil
+}
+
+// withValue returns a new decimal with the same JSON settings as d.
+func (d Decimal) withValue(unscaled *big.Int, scale int32) Decimal {
+	result := newDecimal(unscaled, scale)
+	result.jsonNumber = d.jsonNumber
+
+	return result
+}
+
+// alignDecimals returns the unscaled values of a and b scaled to the same (larger) scale.
+func alignDecimals(a, b Decimal) (*big.Int, *big.Int) {
+	switch {
+	case a.scale > b.scale:
+		return a.bigInt(), new(big.Int).Mul(b.bigInt(), pow10(a.scale-b.scale))
+	case a.scale < b.scale:
+		return new(big.Int).Mul(a.bigInt(), pow10(b.scale-a.scale)), b.bigInt()
+	default:
+		return a.bigInt(), b.bigInt()
+	}
+}
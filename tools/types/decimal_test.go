@@ -0,0 +1,443 @@
+package types_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestParseDecimal(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		value         string
+		expected      string
+		expectedScale int32
+		expectError   bool
+	}{
+		{"", "", 0, true},
+		{"-", "", 0, true},
+		{".", "", 0, true},
+		{"abc", "", 0, true},
+		{"1.2.3", "", 0, true},
+		{"1e", "", 0, true},
+		{"1e1001", "", 0, true},
+		{"0x10", "", 0, true},
+		{"0", "0", 0, false},
+		{"123", "123", 0, false},
+		{" -123.450 ", "-123.450", 3, false},
+		{"+.5", "0.5", 1, false},
+		{"5.", "5", 0, false},
+		{"-0.001", "-0.001", 3, false},
+		{"1.5e3", "1500", 0, false},
+		{"15E-1", "1.5", 1, false},
+		{"1.25e-2", "0.0125", 4, false},
+		{"12345678901234567890.123456789", "12345678901234567890.123456789", 9, false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.value, func(t *testing.T) {
+			d, err := types.ParseDecimal(s.value)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr {
+				return
+			}
+
+			if str := d.String(); str != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, str)
+			}
+
+			if d.Scale() != s.expectedScale {
+				t.Fatalf("Expected scale %d, got %d", s.expectedScale, d.Scale())
+			}
+		})
+	}
+}
+
+func TestNewDecimal(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		unscaled int64
+		scale    int32
+		expected string
+	}{
+		{0, 0, "0"},
+		{1250, 2, "12.50"},
+		{-5, 3, "-0.005"},
+		{12, -2, "1200"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%d", s.unscaled, s.scale), func(t *testing.T) {
+			if str := types.NewDecimal(s.unscaled, s.scale).String(); str != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, str)
+			}
+		})
+	}
+}
+
+func TestNewDecimalFromFloat(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		value       float64
+		expected    string
+		expectError bool
+	}{
+		{math.NaN(), "", true},
+		{math.Inf(1), "", true},
+		{math.Inf(-1), "", true},
+		{0, "0", false},
+		{0.1, "0.1", false},
+		{-123.456, "-123.456", false},
+		{1e21, "1000000000000000000000", false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(fmt.Sprint(s.value), func(t *testing.T) {
+			d, err := types.NewDecimalFromFloat(s.value)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if !hasErr && d.String() != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, d.String())
+			}
+		})
+	}
+}
+
+func TestMustParseDecimal(t *testing.T) {
+	t.Parallel()
+
+	if str := types.MustParseDecimal("1.50").String(); str != "1.50" {
+		t.Fatalf("Expected 1.50, got %q", str)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Expected panic")
+		}
+	}()
+
+	types.MustParseDecimal("invalid")
+}
+
+func TestDecimalZeroValue(t *testing.T) {
+	t.Parallel()
+
+	var d types.Decimal
+
+	if !d.IsZero() {
+		t.Fatal("Expected IsZero to be true")
+	}
+
+	if d.String() != "0" {
+		t.Fatalf("Expected 0, got %q", d.String())
+	}
+
+	if d.Unscaled().Sign() != 0 {
+		t.Fatalf("Expected 0 unscaled value, got %v", d.Unscaled())
+	}
+
+	if str := d.Add(types.MustParseDecimal("1.5")).String(); str != "1.5" {
+		t.Fatalf("Expected 1.5, got %q", str)
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name     string
+		result   types.Decimal
+		expected string
+	}{
+		{"0.1 + 0.2", types.MustParseDecimal("0.1").Add(types.MustParseDecimal("0.2")), "0.3"},
+		{"1.10 + 2", types.MustParseDecimal("1.10").Add(types.MustParseDecimal("2")), "3.10"},
+		{"1 - 0.01", types.MustParseDecimal("1").Sub(types.MustParseDecimal("0.01")), "0.99"},
+		{"0.5 - 1.25", types.MustParseDecimal("0.5").Sub(types.MustParseDecimal("1.25")), "-0.75"},
+		{"1.5 * -0.2", types.MustParseDecimal("1.5").Mul(types.MustParseDecimal("-0.2")), "-0.30"},
+		{"-(1.5)", types.MustParseDecimal("1.5").Neg(), "-1.5"},
+		{"-(-1.5)", types.MustParseDecimal("-1.5").Neg(), "1.5"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			if str := s.result.String(); str != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, str)
+			}
+		})
+	}
+}
+
+func TestDecimalImmutability(t *testing.T) {
+	t.Parallel()
+
+	a := types.MustParseDecimal("1.5")
+	b := types.MustParseDecimal("2.5")
+
+	a.Add(b)
+	a.Sub(b)
+	a.Mul(b)
+	a.Neg()
+	a.Round(0)
+
+	if a.String() != "1.5" || b.String() != "2.5" {
+		t.Fatalf("Expected the operands to remain unchanged, got %q and %q", a.String(), b.String())
+	}
+
+	a.Unscaled().SetInt64(100)
+	if a.String() != "1.5" {
+		t.Fatalf("Expected Unscaled to return a copy, got %q", a.String())
+	}
+}
+
+func TestDecimalRound(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		value    string
+		scale    int32
+		expected string
+	}{
+		{"1.005", 2, "1.01"},
+		{"1.004", 2, "1.00"},
+		{"-1.005", 2, "-1.01"},
+		{"-1.004", 2, "-1.00"},
+		{"2.5", 0, "3"},
+		{"-2.5", 0, "-3"},
+		{"0.04", 1, "0.0"},
+		{"9.99", 1, "10.0"},
+		{"1.5", 3, "1.500"},
+		{"1.5", -1, "2"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(fmt.Sprintf("%s_%d", s.value, s.scale), func(t *testing.T) {
+			if str := types.MustParseDecimal(s.value).Round(s.scale).String(); str != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, str)
+			}
+		})
+	}
+}
+
+func TestDecimalCmp(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		a        string
+		b        string
+		expected int
+	}{
+		{"1.50", "1.5", 0},
+		{"0", "-0.00", 0},
+		{"0.1", "0.2", -1},
+		{"-0.1", "-0.2", 1},
+		{"10", "9.999", 1},
+		{"-10", "9.999", -1},
+		{"12345678901234567890.01", "12345678901234567890.02", -1},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.a+"_"+s.b, func(t *testing.T) {
+			a := types.MustParseDecimal(s.a)
+			b := types.MustParseDecimal(s.b)
+
+			if v := a.Cmp(b); v != s.expected {
+				t.Fatalf("Expected Cmp %d, got %d", s.expected, v)
+			}
+
+			if v := a.Equal(b); v != (s.expected == 0) {
+				t.Fatalf("Expected Equal %v, got %v", s.expected == 0, v)
+			}
+		})
+	}
+}
+
+func TestDecimalPrecisionAndSign(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		value             string
+		expectedPrecision int
+		expectedSign      int
+	}{
+		{"0", 1, 0},
+		{"0.00", 2, 0},
+		{"12.50", 4, 1},
+		{"-0.05", 2, -1},
+		{"-1200", 4, -1},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.value, func(t *testing.T) {
+			d := types.MustParseDecimal(s.value)
+
+			if v := d.Precision(); v != s.expectedPrecision {
+				t.Fatalf("Expected precision %d, got %d", s.expectedPrecision, v)
+			}
+
+			if v := d.Sign(); v != s.expectedSign {
+				t.Fatalf("Expected sign %d, got %d", s.expectedSign, v)
+			}
+		})
+	}
+}
+
+func TestDecimalFloat64(t *testing.T) {
+	t.Parallel()
+
+	if v := types.MustParseDecimal("-12.50").Float64(); v != -12.5 {
+		t.Fatalf("Expected -12.5, got %v", v)
+	}
+}
+
+func TestDecimalMarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		value    types.Decimal
+		expected string
+	}{
+		{types.Decimal{}, `"0"`},
+		{types.MustParseDecimal("12.50"), `"12.50"`},
+		{types.MustParseDecimal("-0.5").WithJSONNumber(true), `-0.5`},
+		{types.MustParseDecimal("1").WithJSONNumber(true).Add(types.MustParseDecimal("0.25")), `1.25`},
+		{types.MustParseDecimal("1").WithJSONNumber(true).WithJSONNumber(false), `"1"`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%s", i, s.expected), func(t *testing.T) {
+			raw, err := json.Marshal(s.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if str := string(raw); str != s.expected {
+				t.Fatalf("Expected %s, got %s", s.expected, str)
+			}
+		})
+	}
+}
+
+func TestDecimalUnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		json        string
+		expected    string
+		expectError bool
+	}{
+		{`null`, "0", false},
+		{`"12.50"`, "12.50", false},
+		{`12.50`, "12.50", false},
+		{`-1e2`, "-100", false},
+		{`"abc"`, "", true},
+		{`true`, "", true},
+		{`[1]`, "", true},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.json, func(t *testing.T) {
+			var d types.Decimal
+
+			err := json.Unmarshal([]byte(s.json), &d)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if !hasErr && d.String() != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, d.String())
+			}
+		})
+	}
+}
+
+func TestDecimalValue(t *testing.T) {
+	t.Parallel()
+
+	v, err := types.MustParseDecimal("-12.50").Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v != "-12.50" {
+		t.Fatalf("Expected -12.50, got %#v", v)
+	}
+}
+
+func TestDecimalScan(t *testing.T) {
+	t.Parallel()
+
+	d := types.MustParseDecimal("1.5")
+
+	scenarios := []struct {
+		value       any
+		expected    string
+		expectError bool
+	}{
+		{nil, "0", false},
+		{"", "0", false},
+		{[]byte{}, "0", false},
+		{d, "1.5", false},
+		{&d, "1.5", false},
+		{(*types.Decimal)(nil), "0", false},
+		{"12.50", "12.50", false},
+		{[]byte("-0.5"), "-0.5", false},
+		{float32(0.5), "0.5", false},
+		{0.1, "0.1", false},
+		{-12, "-12", false},
+		{int64(123), "123", false},
+		{uint64(math.MaxUint64), "18446744073709551615", false},
+		{true, "1", false},
+		{"abc", "", true},
+		{math.NaN(), "", true},
+		{[]int{1}, "", true},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%#v", i, s.value), func(t *testing.T) {
+			var result types.Decimal
+
+			err := result.Scan(s.value)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if !hasErr && result.String() != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, result.String())
+			}
+		})
+	}
+}
+
+func TestDecimalScanPreservesJSONNumber(t *testing.T) {
+	t.Parallel()
+
+	d := types.Decimal{}.WithJSONNumber(true)
+
+	if err := d.Scan("1.5"); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, _ := json.Marshal(d)
+	if string(raw) != "1.5" {
+		t.Fatalf("Expected 1.5 JSON number, got %s", raw)
+	}
+}
@@ -0,0 +1,140 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/spf13/cast"
+)
+
+// Vector defines a slice of float32 numbers (e.g. embeddings) that is
+// persisted in the database as compact little-endian float32 blob.
+//
+// The JSON representation of the Vector is a plain numbers array.
+type Vector []float32
+
+// String returns the JSON string representation of the current Vector.
+func (v Vector) String() string {
+	raw, _ := v.MarshalJSON()
+	return string(raw)
+}
+
+// MarshalJSON implements the [json.Marshaler] interface.
+func (v Vector) MarshalJSON() ([]byte, error) {
+	if v == nil {
+		return []byte("[]"), nil
+	}
+
+	return json.Marshal([]float32(v))
+}
+
+// Bytes returns the little-endian float32 blob representation of the current Vector.
+func (v Vector) Bytes() []byte {
+	result := make([]byte, len(v)*4)
+
+	for i, n := range v {
+		binary.LittleEndian.PutUint32(result[i*4:], math.Float32bits(n))
+	}
+
+	return result
+}
+
+// Value implements the [driver.Valuer] interface.
+func (v Vector) Value() (driver.Value, error) {
+	return v.Bytes(), nil
+}
+
+// Scan implements [sql.Scanner] interface to scan the provided value
+// into the current Vector instance.
+//
+// The value argument could be nil (empty vector), little-endian float32 blob,
+// serialized json array or a slice of numbers.
+func (v *Vector) Scan(value any) error {
+	result, err := ParseVector(value)
+	if err != nil {
+		return err
+	}
+
+	*v = result
+
+	return nil
+}
+
+// ParseVector creates a new Vector from the provided raw value.
+//
+// The value argument could be nil (empty vector), another Vector instance,
+// little-endian float32 blob (string or []byte), serialized json array or
+// a slice of numbers.
+func ParseVector(value any) (Vector, error) {
+	switch v := value.(type) {
+	case nil:
+		return Vector{}, nil
+	case Vector:
+		return v, nil
+	case *Vector:
+		if v == nil {
+			return Vector{}, nil
+		}
+		return *v, nil
+	case []float32:
+		return Vector(v), nil
+	case []byte:
+		return parseVectorBytes(v)
+	case string:
+		return parseVectorBytes([]byte(v))
+	case JSONRaw:
+		return parseVectorBytes(v)
+	case []float64:
+		result := make(Vector, len(v))
+		for i, n := range v {
+			result[i] = float32(n)
+		}
+		return result, nil
+	default:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return nil, fmt.Errorf("[Vector] unsupported value type %T", value)
+		}
+
+		result := make(Vector, rv.Len())
+		for i := range result {
+			n, err := cast.ToFloat32E(rv.Index(i).Interface())
+			if err != nil {
+				return nil, fmt.Errorf("[Vector] invalid item %d: %w", i, err)
+			}
+			result[i] = n
+		}
+
+		return result, nil
+	}
+}
+
+// parseVectorBytes parses a serialized json array or a little-endian float32 blob.
+func parseVectorBytes(raw []byte) (Vector, error) {
+	if len(raw) == 0 {
+		return Vector{}, nil
+	}
+
+	if raw[0] == '[' && json.Valid(raw) {
+		result := Vector{}
+		if err := json.Unmarshal(raw, (*[]float32)(&result)); err != nil {
+			return nil, fmt.Errorf("[Vector] invalid json array: %w", err)
+		}
+		return result, nil
+	}
+
+	if len(raw)%4 != 0 {
+		return nil, fmt.Errorf("[Vector] invalid blob length %d", len(raw))
+	}
+
+	result := make(Vector, len(raw)/4)
+	for i := range result {
+		result[i] = math.Float32frombits(binary.LittleEndian.Uint32(raw[i*4:]))
+	}
+
+	return result, nil
+}
@@ -0,0 +1,113 @@
+package types_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestParseVector(t *testing.T) {
+	t.Parallel()
+
+	blob := types.Vector{1.5, -2}.Bytes()
+
+	scenarios := []struct {
+		value       any
+		expected    string
+		expectError bool
+	}{
+		{nil, "[]", false},
+		{"", "[]", false},
+		{[]byte{}, "[]", false},
+		{types.Vector{1, 2}, "[1,2]", false},
+		{&types.Vector{3}, "[3]", false},
+		{[]float32{1.5}, "[1.5]", false},
+		{[]float64{1.5, 2}, "[1.5,2]", false},
+		{[]int{1, 2}, "[1,2]", false},
+		{[]any{1, "2.5"}, "[1,2.5]", false},
+		{"[1,2.5]", "[1,2.5]", false},
+		{[]byte("[1, -2]"), "[1,-2]", false},
+		{types.JSONRaw("[3]"), "[3]", false},
+		{blob, "[1.5,-2]", false},
+		{string(blob), "[1.5,-2]", false},
+		{"abc", "", true},
+		{`["a"]`, "", true},
+		{[]any{"a"}, "", true},
+		{123, "", true},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%#v", i, s.value), func(t *testing.T) {
+			v, err := types.ParseVector(s.value)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr {
+				return
+			}
+
+			if str := v.String(); str != s.expected {
+				t.Fatalf("Expected %s, got %s", s.expected, str)
+			}
+		})
+	}
+}
+
+func TestVectorValueAndScan(t *testing.T) {
+	t.Parallel()
+
+	original := types.Vector{0.25, -1, 3}
+
+	val, err := original.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw, ok := val.([]byte)
+	if !ok || len(raw) != 12 {
+		t.Fatalf("Expected 12 bytes blob, got %#v", val)
+	}
+
+	var scanned types.Vector
+	if err := scanned.Scan(raw); err != nil {
+		t.Fatal(err)
+	}
+
+	if scanned.String() != original.String() {
+		t.Fatalf("Expected %s, got %s", original, scanned)
+	}
+
+	if err := scanned.Scan("invalid"); err == nil {
+		t.Fatal("Expected scan error")
+	}
+}
+
+func TestVectorMarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		vector   types.Vector
+		expected string
+	}{
+		{nil, "[]"},
+		{types.Vector{}, "[]"},
+		{types.Vector{1, 0.5}, "[1,0.5]"},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%s", i, s.expected), func(t *testing.T) {
+			raw, err := s.vector.MarshalJSON()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(raw) != s.expected {
+				t.Fatalf("Expected %s, got %s", s.expected, raw)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// Package vector implements vector distance metrics and a simple
+// in-memory HNSW approximate nearest neighbor index.
+package vector
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// supported distance metrics
+const (
+	// MetricCosine calculates the cosine distance (1 - cosine similarity).
+	MetricCosine = "cosine"
+
+	// MetricL2 calculates the Euclidean distance.
+	MetricL2 = "l2"
+
+	// MetricDot calculates the negative dot product
+	// (negated so that smaller values are always closer).
+	MetricDot = "dot"
+)
+
+// Metrics lists all supported distance metrics.
+var Metrics = []string{MetricCosine, MetricL2, MetricDot}
+
+// ErrDimensionsMismatch is returned when comparing vectors with different length.
+var ErrDimensionsMismatch = errors.New("vectors dimensions mismatch")
+
+// DistanceFunc defines a function that calculates the distance between 2 vectors.
+//
+// The vectors are expected to have the same length.
+type DistanceFunc func(a, b []float32) float64
+
+// DistanceFuncFor returns the DistanceFunc for the specified metric name
+// (default to [MetricCosine] if empty).
+func DistanceFuncFor(metric string) (DistanceFunc, error) {
+	switch metric {
+	case MetricCosine, "":
+		return CosineDistance, nil
+	case MetricL2:
+		return L2Distance, nil
+	case MetricDot:
+		return DotDistance, nil
+	default:
+		return nil, fmt.Errorf("unsupported distance metric %q", metric)
+	}
+}
+
+// Distance calculates the distance between a and b using the specified metric.
+func Distance(metric string, a, b []float32) (float64, error) {
+	if len(a) != len(b) {
+		return 0, ErrDimensionsMismatch
+	}
+
+	fn, err := DistanceFuncFor(metric)
+	if err != nil {
+		return 0, err
+	}
+
+	return fn(a, b), nil
+}
+
+// CosineDistance returns the cosine distance between a and b in the range [0, 2].
+//
+// If any of the vectors has zero magnitude it returns 1.
+func CosineDistance(a, b []float32) float64 {
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+
+	if normA == 0 || normB == 0 {
+		return 1
+	}
+
+	return 1 - dot/(math.Sqrt(normA)*math.Sqrt(normB))
+}
+
+// L2Distance returns the Euclidean distance between a and b.
+func L2Distance(a, b []float32) float64 {
+	var sum float64
+	for i := range a {
+		d := float64(a[i]) - float64(b[i])
+		sum += d * d
+	}
+
+	return math.Sqrt(sum)
+}
+
+// DotDistance returns the negative dot product of a and b.
+func DotDistance(a, b []float32) float64 {
+	var dot float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+	}
+
+	return -dot
+}
@@ -0,0 +1,52 @@
+package vector_test
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/vector"
+)
+
+func TestDistance(t *testing.T) {
+	scenarios := []struct {
+		metric      string
+		a           []float32
+		b           []float32
+		expected    float64
+		expectError bool
+	}{
+		{vector.MetricCosine, []float32{1, 0}, []float32{1, 0}, 0, false},
+		{vector.MetricCosine, []float32{1, 0}, []float32{0, 1}, 1, false},
+		{vector.MetricCosine, []float32{1, 0}, []float32{-2, 0}, 2, false},
+		{vector.MetricCosine, []float32{0, 0}, []float32{1, 1}, 1, false},
+		{"", []float32{1, 1}, []float32{2, 2}, 0, false},
+		{vector.MetricL2, []float32{0, 0}, []float32{3, 4}, 5, false},
+		{vector.MetricDot, []float32{1, 2}, []float32{3, 4}, -11, false},
+		{vector.MetricL2, []float32{1, 2}, []float32{1}, 0, true},
+		{"unknown", []float32{1}, []float32{1}, 0, true},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%s", i, s.metric), func(t *testing.T) {
+			result, err := vector.Distance(s.metric, s.a, s.b)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if math.Abs(result-s.expected) > 1e-6 {
+				t.Fatalf("Expected %v, got %v", s.expected, result)
+			}
+		})
+	}
+}
+
+func TestDistanceDimensionsMismatch(t *testing.T) {
+	_, err := vector.Distance(vector.MetricCosine, []float32{1}, []float32{1, 2})
+	if !errors.Is(err, vector.ErrDimensionsMismatch) {
+		t.Fatalf("Expected ErrDimensionsMismatch, got %v", err)
+	}
+}
@@ -0,0 +1,470 @@
+package vector
+
+import (
+	"container/heap"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"slices"
+	"sync"
+)
+
+// default HNSW construction and search parameters
+const (
+	DefaultHNSWM              = 16
+	DefaultHNSWEfConstruction = 200
+	DefaultHNSWEfSearch       = 64
+)
+
+// HNSWOptions defines the [HNSW] index settings.
+type HNSWOptions struct {
+	// Metric is the index distance metric (default to [MetricCosine]).
+	Metric string
+
+	// Dimensions (required) is the length of the indexed vectors.
+	Dimensions int
+
+	// M is the max number of connections per node for the upper graph layers
+	// (the base layer allows 2*M connections; default to [DefaultHNSWM]).
+	M int
+
+	// EfConstruction is the size of the dynamic candidates list
+	// used during insertion (default to [DefaultHNSWEfConstruction]).
+	EfConstruction int
+
+	// EfSearch is the min size of the dynamic candidates list
+	// used during search (default to [DefaultHNSWEfSearch]).
+	EfSearch int
+}
+
+// SearchResult defines a single [HNSW.Search] result item.
+type SearchResult struct {
+	Id       string
+	Distance float64
+}
+
+// HNSW is an in-memory Hierarchical Navigable Small World graph
+// for approximate nearest neighbor search (https://arxiv.org/abs/1603.09320).
+//
+// It is safe for concurrent use.
+type HNSW struct {
+	mu sync.RWMutex
+
+	options    HNSWOptions
+	distFunc   DistanceFunc
+	levelMult  float64
+	rand       *rand.Rand
+	nodes      map[string]*hnswNode
+	entryPoint string
+	maxLevel   int
+}
+
+type hnswNode struct {
+	Id        string
+	Vector    []float32
+	Neighbors [][]string // per level
+}
+
+// NewHNSW creates a new empty HNSW index.
+func NewHNSW(options HNSWOptions) (*HNSW, error) {
+	if options.Dimensions <= 0 {
+		return nil, errors.New("the index dimensions must be greater than 0")
+	}
+
+	if options.Metric == "" {
+		options.Metric = MetricCosine
+	}
+
+	distFunc, err := DistanceFuncFor(options.Metric)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.M <= 1 {
+		options.M = DefaultHNSWM
+	}
+
+	if options.EfConstruction <= 0 {
+		options.EfConstruction = DefaultHNSWEfConstruction
+	}
+
+	if options.EfSearch <= 0 {
+		options.EfSearch = DefaultHNSWEfSearch
+	}
+
+	return &HNSW{
+		options:   options,
+		distFunc:  distFunc,
+		levelMult: 1 / math.Log(float64(options.M)),
+		rand:      rand.New(rand.NewSource(rand.Int63())),
+		nodes:     map[string]*hnswNode{},
+	}, nil
+}
+
+// Options returns the index settings.
+func (h *HNSW) Options() HNSWOptions {
+	return h.options
+}
+
+// Len returns the number of the indexed vectors.
+func (h *HNSW) Len() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.nodes)
+}
+
+// Has checks whether a vector with the specified id is indexed.
+func (h *HNSW) Has(id string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	_, ok := h.nodes[id]
+
+	return ok
+}
+
+// Add inserts a new vector in the index (or replaces an existing one with the same id).
+func (h *HNSW) Add(id string, vec []float32) error {
+	if len(vec) != h.options.Dimensions {
+		return fmt.Errorf("%w: expected %d, got %d", ErrDimensionsMismatch, h.options.Dimensions, len(vec))
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.nodes[id]; ok {
+		h.remove(id)
+	}
+
+	level := int(math.Floor(-math.Log(1-h.rand.Float64()) * h.levelMult))
+
+	node := &hnswNode{
+		Id:        id,
+		Vector:    slices.Clone(vec),
+		Neighbors: make([][]string, level+1),
+	}
+	h.nodes[id] = node
+
+	if h.entryPoint == "" {
+		h.entryPoint = id
+		h.maxLevel = level
+		return nil
+	}
+
+	ep := h.candidateFor(h.entryPoint, vec)
+	for l := h.maxLevel; l > level; l-- {
+		ep = h.searchLayer(vec, []candidate{ep}, 1, l)[0]
+	}
+
+	entries := []candidate{ep}
+	for l := min(level, h.maxLevel); l >= 0; l-- {
+		found := h.searchLayer(vec, entries, h.options.EfConstruction, l)
+
+		neighbors := found[:min(h.options.M, len(found))]
+		node.Neighbors[l] = make([]string, len(neighbors))
+		for i, n := range neighbors {
+			node.Neighbors[l][i] = n.id
+
+			neighbor := h.nodes[n.id]
+			neighbor.Neighbors[l] = append(neighbor.Neighbors[l], id)
+			h.prune(neighbor, l)
+		}
+
+		entries = found
+	}
+
+	if level > h.maxLevel {
+		h.maxLevel = level
+		h.entryPoint = id
+	}
+
+	return nil
+}
+
+// Remove deletes a single vector from the index (no-op if it doesn't exist).
+func (h *HNSW) Remove(id string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.remove(id)
+}
+
+func (h *HNSW) remove(id string) {
+	node, ok := h.nodes[id]
+	if !ok {
+		return
+	}
+
+	delete(h.nodes, id)
+
+	// unlink the node and reconnect its neighbors to each other
+	for l, neighbors := range node.Neighbors {
+		for _, nid := range neighbors {
+			n, ok := h.nodes[nid]
+			if !ok || l >= len(n.Neighbors) {
+				continue
+			}
+
+			n.Neighbors[l] = slices.DeleteFunc(n.Neighbors[l], func(v string) bool { return v == id })
+
+			for _, other := range neighbors {
+				if other == nid || slices.Contains(n.Neighbors[l], other) {
+					continue
+				}
+				if o, ok := h.nodes[other]; ok && l < len(o.Neighbors) {
+					n.Neighbors[l] = append(n.Neighbors[l], other)
+				}
+			}
+
+			h.prune(n, l)
+		}
+	}
+
+	if h.entryPoint != id {
+		return
+	}
+
+	// find a new entry point
+	h.entryPoint = ""
+	h.maxLevel = 0
+	for nid, n := range h.nodes {
+		if h.entryPoint == "" || len(n.Neighbors)-1 > h.maxLevel {
+			h.entryPoint = nid
+			h.maxLevel = len(n.Neighbors) - 1
+		}
+	}
+}
+
+// Search returns the approximate k nearest neighbors of the query vector
+// sorted by their distance in ascending order.
+func (h *HNSW) Search(query []float32, k int) ([]SearchResult, error) {
+	if len(query) != h.options.Dimensions {
+		return nil, fmt.Errorf("%w: expected %d, got %d", ErrDimensionsMismatch, h.options.Dimensions, len(query))
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.entryPoint == "" || k <= 0 {
+		return []SearchResult{}, nil
+	}
+
+	ep := h.candidateFor(h.entryPoint, query)
+	for l := h.maxLevel; l > 0; l-- {
+		ep = h.searchLayer(query, []candidate{ep}, 1, l)[0]
+	}
+
+	found := h.searchLayer(query, []candidate{ep}, max(h.options.EfSearch, k), 0)
+
+	result := make([]SearchResult, min(k, len(found)))
+	for i := range result {
+		result[i] = SearchResult{Id: found[i].id, Distance: found[i].dist}
+	}
+
+	return result, nil
+}
+
+func (h *HNSW) candidateFor(id string, query []float32) candidate {
+	return candidate{id: id, dist: h.distFunc(query, h.nodes[id].Vector)}
+}
+
+// searchLayer performs a greedy beam search on a single graph level
+// and returns the found candidates sorted by their distance.
+func (h *HNSW) searchLayer(query []float32, entries []candidate, ef int, level int) []candidate {
+	visited := make(map[string]struct{}, ef*2)
+	candidates := &candidateHeap{}
+	results := &candidateHeap{max: true}
+
+	for _, e := range entries {
+		visited[e.id] = struct{}{}
+		heap.Push(candidates, e)
+		heap.Push(results, e)
+		if results.Len() > ef {
+			heap.Pop(results)
+		}
+	}
+
+	for candidates.Len() > 0 {
+		c := heap.Pop(candidates).(candidate)
+		if results.Len() >= ef && c.dist > results.items[0].dist {
+			break
+		}
+
+		node, ok := h.nodes[c.id]
+		if !ok || level >= len(node.Neighbors) {
+			continue
+		}
+
+		for _, nid := range node.Neighbors[level] {
+			if _, ok := visited[nid]; ok {
+				continue
+			}
+			visited[nid] = struct{}{}
+
+			n, ok := h.nodes[nid]
+			if !ok {
+				continue
+			}
+
+			d := h.distFunc(query, n.Vector)
+			if results.Len() < ef || d < results.items[0].dist {
+				heap.Push(candidates, candidate{nid, d})
+				heap.Push(results, candidate{nid, d})
+				if results.Len() > ef {
+					heap.Pop(results)
+				}
+			}
+		}
+	}
+
+	found := results.items
+	slices.SortFunc(found, func(a, b candidate) int {
+		switch {
+		case a.dist < b.dist:
+			return -1
+		case a.dist > b.dist:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	return found
+}
+
+// prune keeps only the closest allowed connections of the node at the specified level.
+func (h *HNSW) prune(node *hnswNode, level int) {
+	maxConn := h.options.M
+	if level == 0 {
+		maxConn *= 2
+	}
+
+	if len(node.Neighbors[level]) <= maxConn {
+		return
+	}
+
+	dists := make(map[string]float64, len(node.Neighbors[level]))
+	for _, nid := range node.Neighbors[level] {
+		if n, ok := h.nodes[nid]; ok {
+			dists[nid] = h.distFunc(node.Vector, n.Vector)
+		} else {
+			dists[nid] = math.Inf(1)
+		}
+	}
+
+	slices.SortFunc(node.Neighbors[level], func(a, b string) int {
+		switch {
+		case dists[a] < dists[b]:
+			return -1
+		case dists[a] > dists[b]:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	node.Neighbors[level] = slices.Clip(node.Neighbors[level][:maxConn])
+}
+
+// -------------------------------------------------------------------
+// Persistence
+// -------------------------------------------------------------------
+
+type hnswData struct {
+	Options    HNSWOptions
+	EntryPoint string
+	MaxLevel   int
+	Nodes      []*hnswNode
+}
+
+// Save writes the binary (gob) representation of the index into w.
+func (h *HNSW) Save(w io.Writer) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	data := hnswData{
+		Options:    h.options,
+		EntryPoint: h.entryPoint,
+		MaxLevel:   h.maxLevel,
+		Nodes:      make([]*hnswNode, 0, len(h.nodes)),
+	}
+	for _, n := range h.nodes {
+		data.Nodes = append(data.Nodes, n)
+	}
+
+	return gob.NewEncoder(w).Encode(data)
+}
+
+// LoadHNSW loads an index previously persisted with [HNSW.Save].
+func LoadHNSW(r io.Reader) (*HNSW, error) {
+	var data hnswData
+	if err := gob.NewDecoder(r).Decode(&data); err != nil {
+		return nil, err
+	}
+
+	h, err := NewHNSW(data.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, n := range data.Nodes {
+		if len(n.Vector) != h.options.Dimensions || len(n.Neighbors) == 0 {
+			return nil, fmt.Errorf("invalid index node %q", n.Id)
+		}
+		h.nodes[n.Id] = n
+	}
+
+	if _, ok := h.nodes[data.EntryPoint]; !ok && len(h.nodes) > 0 {
+		return nil, errors.New("invalid index entry point")
+	}
+	h.entryPoint = data.EntryPoint
+	h.maxLevel = data.MaxLevel
+
+	return h, nil
+}
+
+// -------------------------------------------------------------------
+// Helpers
+// -------------------------------------------------------------------
+
+type candidate struct {
+	id   string
+	dist float64
+}
+
+// candidateHeap implements [heap.Interface] ordered by the candidates distance
+// (min-heap by default or max-heap if max is set).
+type candidateHeap struct {
+	items []candidate
+	max   bool
+}
+
+func (ch *candidateHeap) Len() int {
+	return len(ch.items)
+}
+
+func (ch *candidateHeap) Less(i, j int) bool {
+	if ch.max {
+		return ch.items[i].dist > ch.items[j].dist
+	}
+	return ch.items[i].dist < ch.items[j].dist
+}
+
+func (ch *candidateHeap) Swap(i, j int) {
+	ch.items[i], ch.items[j] = ch.items[j], ch.items[i]
+}
+
+func (ch *candidateHeap) Push(x any) {
+	ch.items = append(ch.items, x.(candidate))
+}
+
+func (ch *candidateHeap) Pop() any {
+	n := len(ch.items)
+	item := ch.items[n-1]
+	ch.items = ch.items[:n-1]
+	return item
+}
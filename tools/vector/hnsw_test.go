@@ -0,0 +1,239 @@
+package vector_test
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/vector"
+)
+
+func randomVectors(n, dim int, seed int64) map[string][]float32 {
+	r := rand.New(rand.NewSource(seed))
+
+	result := make(map[string][]float32, n)
+	for i := 0; i < n; i++ {
+		vec := make([]float32, dim)
+		for j := range vec {
+			vec[j] = r.Float32()*2 - 1
+		}
+		result[fmt.Sprintf("v%d", i)] = vec
+	}
+
+	return result
+}
+
+// bruteForce returns the ids of the exact k nearest neighbors.
+func bruteForce(metric string, vectors map[string][]float32, query []float32, k int) []string {
+	type item struct {
+		id   string
+		dist float64
+	}
+
+	items := make([]item, 0, len(vectors))
+	for id, vec := range vectors {
+		d, _ := vector.Distance(metric, query, vec)
+		items = append(items, item{id, d})
+	}
+
+	sort.Slice(items, func(i, j int) bool { return items[i].dist < items[j].dist })
+
+	ids := make([]string, k)
+	for i := range ids {
+		ids[i] = items[i].id
+	}
+
+	return ids
+}
+
+func TestNewHNSW(t *testing.T) {
+	if _, err := vector.NewHNSW(vector.HNSWOptions{}); err == nil {
+		t.Fatal("Expected error for missing dimensions")
+	}
+
+	if _, err := vector.NewHNSW(vector.HNSWOptions{Dimensions: 2, Metric: "unknown"}); err == nil {
+		t.Fatal("Expected error for unknown metric")
+	}
+
+	h, err := vector.NewHNSW(vector.HNSWOptions{Dimensions: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := h.Options()
+	if opts.Metric != vector.MetricCosine ||
+		opts.M != vector.DefaultHNSWM ||
+		opts.EfConstruction != vector.DefaultHNSWEfConstruction ||
+		opts.EfSearch != vector.DefaultHNSWEfSearch {
+		t.Fatalf("Expected the default options to be set, got %#v", opts)
+	}
+}
+
+func TestHNSWSearchRecall(t *testing.T) {
+	for _, metric := range vector.Metrics {
+		t.Run(metric, func(t *testing.T) {
+			vectors := randomVectors(500, 8, 1)
+
+			h, err := vector.NewHNSW(vector.HNSWOptions{Dimensions: 8, Metric: metric})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for id, vec := range vectors {
+				if err := h.Add(id, vec); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if h.Len() != len(vectors) {
+				t.Fatalf("Expected %d indexed vectors, got %d", len(vectors), h.Len())
+			}
+
+			queries := randomVectors(20, 8, 2)
+
+			var total, hits int
+			for _, query := range queries {
+				expected := bruteForce(metric, vectors, query, 10)
+
+				results, err := h.Search(query, 10)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				for i := 1; i < len(results); i++ {
+					if results[i].Distance < results[i-1].Distance {
+						t.Fatalf("Expected the results to be sorted by distance, got %v", results)
+					}
+				}
+
+				for _, r := range results {
+					if slices.Contains(expected, r.Id) {
+						hits++
+					}
+				}
+				total += len(expected)
+			}
+
+			if recall := float64(hits) / float64(total); recall < 0.9 {
+				t.Fatalf("Expected recall >= 0.9, got %v", recall)
+			}
+		})
+	}
+}
+
+func TestHNSWAddRemove(t *testing.T) {
+	h, err := vector.NewHNSW(vector.HNSWOptions{Dimensions: 2, Metric: vector.MetricL2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := h.Add("invalid", []float32{1}); err == nil {
+		t.Fatal("Expected dimensions mismatch error")
+	}
+
+	vectors := randomVectors(100, 2, 3)
+	for id, vec := range vectors {
+		if err := h.Add(id, vec); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// replace
+	if err := h.Add("v1", []float32{100, 100}); err != nil {
+		t.Fatal(err)
+	}
+	if h.Len() != 100 {
+		t.Fatalf("Expected the existing vector to be replaced, got %d items", h.Len())
+	}
+
+	results, err := h.Search([]float32{99, 99}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Id != "v1" {
+		t.Fatalf("Expected v1 to be the nearest, got %v", results)
+	}
+
+	// remove half of the vectors
+	for i := 0; i < 50; i++ {
+		id := fmt.Sprintf("v%d", i)
+		h.Remove(id)
+		delete(vectors, id)
+	}
+	h.Remove("missing") // no-op
+
+	if h.Len() != 50 || h.Has("v1") || !h.Has("v50") {
+		t.Fatalf("Expected 50 items without v1, got %d", h.Len())
+	}
+
+	results, err = h.Search([]float32{0, 0}, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 50 {
+		t.Fatalf("Expected all 50 remaining vectors to be reachable, got %d", len(results))
+	}
+	for _, r := range results {
+		if _, ok := vectors[r.Id]; !ok {
+			t.Fatalf("Unexpected removed vector %q", r.Id)
+		}
+	}
+
+	// remove all
+	for id := range vectors {
+		h.Remove(id)
+	}
+	results, err = h.Search([]float32{0, 0}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 0 {
+		t.Fatalf("Expected no results, got %v", results)
+	}
+}
+
+func TestHNSWSaveLoad(t *testing.T) {
+	h, err := vector.NewHNSW(vector.HNSWOptions{Dimensions: 4, Metric: vector.MetricDot, M: 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for id, vec := range randomVectors(200, 4, 4) {
+		if err := h.Add(id, vec); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := h.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := vector.LoadHNSW(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if loaded.Len() != h.Len() || loaded.Options() != h.Options() {
+		t.Fatalf("Expected the loaded index to match the original, got %d items and %#v", loaded.Len(), loaded.Options())
+	}
+
+	query := []float32{0.5, -0.5, 0.1, 0.2}
+
+	expected, _ := h.Search(query, 5)
+	results, err := loaded.Search(query, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fmt.Sprint(expected) != fmt.Sprint(results) {
+		t.Fatalf("Expected search results\n%v\ngot\n%v", expected, results)
+	}
+
+	if _, err := vector.LoadHNSW(bytes.NewBufferString("invalid")); err == nil {
+		t.Fatal("Expected load error")
+	}
+}
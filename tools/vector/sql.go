@@ -0,0 +1,45 @@
+package vector
+
+import (
+	"fmt"
+
+	"github.com/pocketbase/pocketbase/tools/types"
+	"github.com/spf13/cast"
+)
+
+// SQLDistanceFunction is the name of the custom SQLite scalar function
+// used for calculating the distance between 2 vectors, aka.:
+//
+//	pb_vector_distance(a, b, metric)
+//
+// The default driver registers it automatically. If you are using a custom
+// driver (the no_default_driver build tag), you'll have to register it
+// manually with [SQLDistance] as implementation in order to use the
+// vectorDistance() filter function.
+const SQLDistanceFunction = "pb_vector_distance"
+
+// SQLDistance implements the [SQLDistanceFunction] SQLite function.
+//
+// a and b could be either a float32 blob or a serialized json array
+// (see [types.ParseVector]).
+//
+// It returns NULL if any of the vectors is empty, invalid or the vectors
+// dimensions don't match and error for unknown metric.
+func SQLDistance(a, b, metric any) (any, error) {
+	distFunc, err := DistanceFuncFor(cast.ToString(metric))
+	if err != nil {
+		return nil, fmt.Errorf("[%s] %w", SQLDistanceFunction, err)
+	}
+
+	vecA, err := types.ParseVector(a)
+	if err != nil || len(vecA) == 0 {
+		return nil, nil
+	}
+
+	vecB, err := types.ParseVector(b)
+	if err != nil || len(vecB) != len(vecA) {
+		return nil, nil
+	}
+
+	return distFunc(vecA, vecB), nil
+}
@@ -0,0 +1,45 @@
+package vector_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/types"
+	"github.com/pocketbase/pocketbase/tools/vector"
+)
+
+func TestSQLDistance(t *testing.T) {
+	blob := types.Vector{3, 4}.Bytes()
+
+	scenarios := []struct {
+		a           any
+		b           any
+		metric      any
+		expected    any
+		expectError bool
+	}{
+		{nil, "[1,2]", "l2", nil, false},
+		{"[1,2]", nil, "l2", nil, false},
+		{"invalid", "[1,2]", "l2", nil, false},
+		{"[1,2,3]", "[1,2]", "l2", nil, false},
+		{"[1,2]", "[1,2]", "unknown", nil, true},
+		{blob, "[0,0]", "l2", 5.0, false},
+		{blob, blob, nil, 0.0, false},
+		{"[1,2]", blob, "dot", -11.0, false},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%v", i, s.metric), func(t *testing.T) {
+			result, err := vector.SQLDistance(s.a, s.b, s.metric)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if result != s.expected {
+				t.Fatalf("Expected %v, got %v", s.expected, result)
+			}
+		})
+	}
+}
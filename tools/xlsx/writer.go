@@ -0,0 +1,263 @@
+// Package xlsx implements a minimal streaming writer for single sheet
+// Office Open XML spreadsheet (.xlsx) files.
+//
+// The rows are written directly in the compressed sheet entry of the
+// archive, allowing large spreadsheets to be generated with constant memory.
+package xlsx
+
+import (
+	"archive/zip"
+	"bufio"
+	"compress/flate"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxCellLength is the max number of characters that a single spreadsheet
+// cell could hold (longer text values are truncated).
+const MaxCellLength = 32767
+
+// ErrClosed is returned when writing to an already closed Writer.
+var ErrClosed = errors.New("xlsx: writer is closed")
+
+// Writer writes spreadsheet rows to a single worksheet xlsx file.
+//
+// Example:
+//
+//	w, err := xlsx.NewWriter(f, "Sheet1")
+//	...
+//	w.WriteRow([]any{"name", "total"})
+//	w.WriteRow([]any{"test", 123.4})
+//	...
+//	err = w.Close()
+type Writer struct {
+	zw     *zip.Writer
+	sheet  *bufio.Writer
+	closed bool
+}
+
+// NewWriter creates a new streaming xlsx Writer with a single worksheet
+// named sheetName (fallbacks to "Sheet1" if empty).
+//
+// Call [Writer.Close] to finalize the file.
+func NewWriter(w io.Writer, sheetName string) (*Writer, error) {
+	sheetName = normalizeSheetName(sheetName)
+
+	zw := zip.NewWriter(w)
+
+	zw.RegisterCompressor(zip.Deflate, func(out io.Writer) (io.WriteCloser, error) {
+		return flate.NewWriter(out, flate.BestSpeed)
+	})
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", contentTypesXML},
+		{"_rels/.rels", rootRelsXML},
+		{"xl/workbook.xml", fmt.Sprintf(workbookXML, escape(sheetName))},
+		{"xl/_rels/workbook.xml.rels", workbookRelsXML},
+	}
+
+	for _, p := range parts {
+		if err := writeZipEntry(zw, p.name, p.content); err != nil {
+			return nil, errors.Join(err, zw.Close())
+		}
+	}
+
+	// the sheet must be the last entry because it remains open until Close
+	sheet, err := createZipEntry(zw, "xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, errors.Join(err, zw.Close())
+	}
+
+	bw := bufio.NewWriter(sheet)
+	if _, err := bw.WriteString(sheetHeaderXML); err != nil {
+		return nil, errors.Join(err, zw.Close())
+	}
+
+	return &Writer{zw: zw, sheet: bw}, nil
+}
+
+// WriteRow writes a single row with the provided cell values.
+//
+// Numbers are stored as numeric cells, booleans as boolean cells, nil as empty
+// cells and all other values as inline text (using their fmt.Sprint representation).
+func (w *Writer) WriteRow(cells []any) error {
+	if w.closed {
+		return ErrClosed
+	}
+
+	w.sheet.WriteString("<row>")
+
+	for _, cell := range cells {
+		if err := w.writeCell(cell); err != nil {
+			return err
+		}
+	}
+
+	_, err := w.sheet.WriteString("</row>")
+
+	return err
+}
+
+// Flush writes any buffered rows data to the underlying writer.
+func (w *Writer) Flush() error {
+	if w.closed {
+		return ErrClosed
+	}
+
+	if err := w.sheet.Flush(); err != nil {
+		return err
+	}
+
+	return w.zw.Flush()
+}
+
+// Close finalizes the worksheet and the xlsx archive.
+//
+// It doesn't close the underlying writer.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	_, err := w.sheet.WriteString(sheetFooterXML)
+
+	return errors.Join(err, w.sheet.Flush(), w.zw.Close())
+}
+
+func (w *Writer) writeCell(value any) error {
+	var err error
+
+	switch v := value.(type) {
+	case nil:
+		_, err = w.sheet.WriteString("<c/>")
+	case bool:
+		b := "0"
+		if v {
+			b = "1"
+		}
+		_, err = w.sheet.WriteString(`<c t="b"><v>` + b + `</v></c>`)
+	case int:
+		err = w.writeNumber(strconv.FormatInt(int64(v), 10))
+	case int64:
+		err = w.writeNumber(strconv.FormatInt(v, 10))
+	case float64:
+		err = w.writeNumber(strconv.FormatFloat(v, 'f', -1, 64))
+	case float32:
+		err = w.writeNumber(strconv.FormatFloat(float64(v), 'f', -1, 32))
+	case time.Time:
+		err = w.writeString(v.UTC().Format(time.RFC3339Nano))
+	case string:
+		err = w.writeString(v)
+	default:
+		err = w.writeString(fmt.Sprint(v))
+	}
+
+	return err
+}
+
+func (w *Writer) writeNumber(n string) error {
+	_, err := w.sheet.WriteString("<c><v>" + n + "</v></c>")
+	return err
+}
+
+func (w *Writer) writeString(s string) error {
+	if len(s) > MaxCellLength {
+		// truncate without splitting a multi-byte character
+		runes := []rune(s)
+		if len(runes) > MaxCellLength {
+			s = string(runes[:MaxCellLength])
+		}
+	}
+
+	_, err := w.sheet.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">` + escape(s) + `</t></is></c>`)
+
+	return err
+}
+
+// -------------------------------------------------------------------
+
+func escape(s string) string {
+	var sb strings.Builder
+
+	// EscapeText also replaces the invalid XML characters with U+FFFD
+	xml.EscapeText(&sb, []byte(s))
+
+	return sb.String()
+}
+
+var sheetNameReplacer = strings.NewReplacer(
+	`\`, "_",
+	"/", "_",
+	"?", "_",
+	"*", "_",
+	"[", "_",
+	"]", "_",
+	":", "_",
+)
+
+// normalizeSheetName replaces the characters that are not allowed
+// in a worksheet name and truncates it to the max allowed 31 characters.
+func normalizeSheetName(name string) string {
+	name = strings.Trim(sheetNameReplacer.Replace(name), "'")
+
+	if runes := []rune(name); len(runes) > 31 {
+		name = string(runes[:31])
+	}
+
+	if name == "" {
+		return "Sheet1"
+	}
+
+	return name
+}
+
+func createZipEntry(zw *zip.Writer, name string) (io.Writer, error) {
+	return zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: time.Now(),
+	})
+}
+
+func writeZipEntry(zw *zip.Writer, name string, content string) error {
+	fw, err := createZipEntry(zw, name)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(fw, content)
+
+	return err
+}
+
+const contentTypesXML = xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+	`</Types>`
+
+const rootRelsXML = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const workbookXML = xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+	`<sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets>` +
+	`</workbook>`
+
+const workbookRelsXML = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+	`</Relationships>`
+
+const sheetHeaderXML = xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+
+const sheetFooterXML = `</sheetData></worksheet>`
@@ -0,0 +1,156 @@
+package xlsx_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/xlsx"
+)
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+
+	w, err := xlsx.NewWriter(&buf, "test/[sheet]")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows := [][]any{
+		{"name", "total", "active", "empty"},
+		{"a <&> b", 12.5, true, nil},
+		{"c\x00", 3, false, []string{"x"}},
+	}
+	for _, row := range rows {
+		if err := w.WriteRow(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.WriteRow([]any{"after close"}); err != xlsx.ErrClosed {
+		t.Fatalf("Expected ErrClosed, got %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{}
+	for _, f := range zr.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[f.Name] = string(content)
+	}
+
+	expectedFiles := []string{
+		"[Content_Types].xml",
+		"_rels/.rels",
+		"xl/workbook.xml",
+		"xl/_rels/workbook.xml.rels",
+		"xl/worksheets/sheet1.xml",
+	}
+	if len(files) != len(expectedFiles) {
+		t.Fatalf("Expected %d files, got %d", len(expectedFiles), len(files))
+	}
+	for _, name := range expectedFiles {
+		content, ok := files[name]
+		if !ok {
+			t.Fatalf("Missing %q file", name)
+		}
+
+		// ensure that all parts are well-formed
+		d := xml.NewDecoder(strings.NewReader(content))
+		for {
+			_, err := d.Token()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("Invalid %q xml: %v", name, err)
+			}
+		}
+	}
+
+	if !strings.Contains(files["xl/workbook.xml"], `name="test__sheet_"`) {
+		t.Fatalf("Expected normalized sheet name, got\n%s", files["xl/workbook.xml"])
+	}
+
+	sheet := files["xl/worksheets/sheet1.xml"]
+	expectedParts := []string{
+		`<row><c t="inlineStr"><is><t xml:space="preserve">name</t></is></c>`,
+		`<t xml:space="preserve">a &lt;&amp;&gt; b</t>`,
+		`<c><v>12.5</v></c><c t="b"><v>1</v></c><c/></row>`,
+		"<t xml:space=\"preserve\">c�</t>",
+		`<c><v>3</v></c><c t="b"><v>0</v></c>`,
+		`<t xml:space="preserve">[x]</t>`,
+	}
+	for _, part := range expectedParts {
+		if !strings.Contains(sheet, part) {
+			t.Fatalf("Missing %q in sheet:\n%s", part, sheet)
+		}
+	}
+
+	if n := strings.Count(sheet, "<row>"); n != len(rows) {
+		t.Fatalf("Expected %d rows, got %d", len(rows), n)
+	}
+}
+
+func TestWriterTruncateLongText(t *testing.T) {
+	var buf bytes.Buffer
+
+	w, err := xlsx.NewWriter(&buf, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.WriteRow([]any{strings.Repeat("ж", xlsx.MaxCellLength+10)}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range zr.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		switch f.Name {
+		case "xl/workbook.xml":
+			if !strings.Contains(string(content), `name="Sheet1"`) {
+				t.Fatalf("Expected the default sheet name, got\n%s", content)
+			}
+		case "xl/worksheets/sheet1.xml":
+			if n := strings.Count(string(content), "ж"); n != xlsx.MaxCellLength {
+				t.Fatalf("Expected %d characters, got %d", xlsx.MaxCellLength, n)
+			}
+		}
+	}
+}
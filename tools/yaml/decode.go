@@ -0,0 +1,805 @@
+// Package yaml implements a minimal YAML decoder for configuration files.
+//
+// It supports the commonly used subset of YAML 1.2 - block mappings and
+// sequences, flow collections, plain and quoted scalars, literal and
+// folded block scalars and comments.
+//
+// Anchors, aliases, tags and multiple documents are not supported and
+// result in an error.
+package yaml
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Unmarshal decodes the YAML data and stores the result in the value pointed to by v.
+//
+// The decoded value is converted using the [encoding/json] rules,
+// aka. v could be any json.Unmarshal compatible target.
+func Unmarshal(data []byte, v any) error {
+	decoded, err := Decode(data)
+	if err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(decoded)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(raw, v)
+}
+
+// Decode decodes the YAML data into a generic Go value.
+//
+// Mappings are decoded as map[string]any, sequences as []any and
+// scalars as nil, bool, int64, float64 or string.
+func Decode(data []byte) (any, error) {
+	p := &parser{}
+
+	for i, line := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		if i == 0 {
+			line = strings.TrimPrefix(line, "\uFEFF")
+		}
+
+		if strings.Contains(line, "\t") && strings.TrimLeft(line, " ") != strings.TrimLeft(line, " \t") {
+			return nil, fmt.Errorf("yaml: line %d: tabs are not allowed as indentation", i+1)
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "---" || strings.HasPrefix(trimmed, "--- ") || trimmed == "..." {
+			if p.hasContent() {
+				if trimmed == "..." {
+					break
+				}
+				return nil, fmt.Errorf("yaml: line %d: multiple documents are not supported", i+1)
+			}
+			line = ""
+		} else if strings.HasPrefix(trimmed, "%") && !p.hasContent() {
+			line = "" // directive
+		}
+
+		p.lines = append(p.lines, line)
+	}
+
+	p.skipEmpty()
+	if p.pos >= len(p.lines) {
+		return nil, nil
+	}
+
+	result, err := p.parseNode(0)
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipEmpty()
+	if p.pos < len(p.lines) {
+		return nil, p.errorf("unexpected content %q", strings.TrimSpace(p.lines[p.pos]))
+	}
+
+	return result, nil
+}
+
+type parser struct {
+	lines []string
+	pos   int
+}
+
+func (p *parser) hasContent() bool {
+	for _, line := range p.lines {
+		if !isEmptyLine(line) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("yaml: line %d: %s", p.pos+1, fmt.Sprintf(format, args...))
+}
+
+// skipEmpty moves the parser position to the next non-empty and non-comment line.
+func (p *parser) skipEmpty() {
+	for p.pos < len(p.lines) && isEmptyLine(p.lines[p.pos]) {
+		p.pos++
+	}
+}
+
+func isEmptyLine(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed == "" || strings.HasPrefix(trimmed, "#")
+}
+
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+func isSequenceItem(content string) bool {
+	return content == "-" || strings.HasPrefix(content, "- ")
+}
+
+// parseNode parses the block node starting at the current (non-empty) line.
+func (p *parser) parseNode(minIndent int) (any, error) {
+	p.skipEmpty()
+	if p.pos >= len(p.lines) {
+		return nil, nil
+	}
+
+	line := p.lines[p.pos]
+	indent := indentOf(line)
+	if indent < minIndent {
+		return nil, nil
+	}
+
+	content := stripComment(strings.TrimSpace(line))
+
+	if isSequenceItem(content) {
+		return p.parseSequence(indent)
+	}
+
+	if _, _, ok := splitMappingEntry(content); ok {
+		return p.parseMapping(indent)
+	}
+
+	p.pos++
+
+	return p.parseInlineValue(content, indent)
+}
+
+func (p *parser) parseSequence(indent int) ([]any, error) {
+	result := []any{}
+
+	for {
+		p.skipEmpty()
+		if p.pos >= len(p.lines) {
+			break
+		}
+
+		line := p.lines[p.pos]
+		if indentOf(line) != indent {
+			if indentOf(line) > indent {
+				return nil, p.errorf("unexpected indentation")
+			}
+			break
+		}
+
+		content := strings.TrimSpace(line)
+		if !isSequenceItem(content) {
+			break
+		}
+
+		rest := strings.TrimPrefix(content[1:], " ")
+		if stripComment(rest) == "" {
+			p.pos++
+			item, err := p.parseNode(indent + 1)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, item)
+			continue
+		}
+
+		// replace the dash with space and parse the item content
+		// as a regular node with the new indentation
+		itemIndent := indent + len(content) - len(strings.TrimLeft(content[1:], " "))
+		p.lines[p.pos] = strings.Repeat(" ", itemIndent) + strings.TrimLeft(content[1:], " ")
+
+		item, err := p.parseNode(itemIndent)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+
+	return result, nil
+}
+
+func (p *parser) parseMapping(indent int) (map[string]any, error) {
+	result := map[string]any{}
+
+	for {
+		p.skipEmpty()
+		if p.pos >= len(p.lines) {
+			break
+		}
+
+		line := p.lines[p.pos]
+		if indentOf(line) != indent {
+			if indentOf(line) > indent {
+				return nil, p.errorf("unexpected indentation")
+			}
+			break
+		}
+
+		content := stripComment(strings.TrimSpace(line))
+
+		key, rawValue, ok := splitMappingEntry(content)
+		if !ok {
+			if isSequenceItem(content) {
+				break
+			}
+			return nil, p.errorf("expected a mapping entry, got %q", content)
+		}
+
+		if _, exists := result[key]; exists {
+			return nil, p.errorf("duplicated mapping key %q", key)
+		}
+
+		p.pos++
+
+		var value any
+		var err error
+
+		switch {
+		case rawValue == "":
+			p.skipEmpty()
+			if p.pos < len(p.lines) {
+				next := p.lines[p.pos]
+				nextIndent := indentOf(next)
+				if nextIndent > indent {
+					value, err = p.parseNode(nextIndent)
+				} else if nextIndent == indent && isSequenceItem(strings.TrimSpace(next)) {
+					// sequences are allowed to have the same indentation as the parent key
+					value, err = p.parseSequence(indent)
+				}
+			}
+		case rawValue[0] == '|' || rawValue[0] == '>':
+			value, err = p.parseBlockScalar(rawValue, indent)
+		default:
+			value, err = p.parseInlineValue(rawValue, indent)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		result[key] = value
+	}
+
+	return result, nil
+}
+
+// parseInlineValue parses an inline (flow or scalar) value,
+// including its eventual multi-line continuation.
+func (p *parser) parseInlineValue(raw string, indent int) (any, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	switch raw[0] {
+	case '&', '*', '!':
+		return nil, p.errorf("anchors, aliases and tags are not supported")
+	case '[', '{':
+		// join the following lines until the flow collection is closed
+		for !isFlowClosed(raw) {
+			if p.pos >= len(p.lines) {
+				return nil, p.errorf("unterminated flow collection")
+			}
+			raw += " " + stripComment(strings.TrimSpace(p.lines[p.pos]))
+			p.pos++
+		}
+
+		fp := &flowParser{src: raw}
+		value, err := fp.parseValue()
+		if err != nil {
+			return nil, p.errorf("%v", err)
+		}
+
+		fp.skipSpaces()
+		if fp.pos < len(fp.src) {
+			return nil, p.errorf("unexpected flow content %q", fp.src[fp.pos:])
+		}
+
+		return value, nil
+	case '"', '\'':
+		value, rest, err := parseQuoted(raw)
+		if err != nil {
+			return nil, p.errorf("%v", err)
+		}
+		if strings.TrimSpace(rest) != "" {
+			return nil, p.errorf("unexpected content after the quoted string %q", rest)
+		}
+		return value, nil
+	}
+
+	if err := validatePlainScalar(raw); err != nil {
+		return nil, p.errorf("%v", err)
+	}
+
+	// multi-line plain scalars are folded into a single line
+	isContinued := false
+	for p.pos < len(p.lines) {
+		next := p.lines[p.pos]
+		if isEmptyLine(next) || indentOf(next) <= indent {
+			break
+		}
+		nextContent := stripComment(strings.TrimSpace(next))
+		if _, _, ok := splitMappingEntry(nextContent); ok {
+			return nil, p.errorf("unexpected mapping entry %q", nextContent)
+		}
+		raw += " " + nextContent
+		isContinued = true
+		p.pos++
+	}
+
+	if isContinued {
+		return raw, nil
+	}
+
+	return resolvePlainScalar(raw), nil
+}
+
+func (p *parser) parseBlockScalar(header string, parentIndent int) (string, error) {
+	folded := header[0] == '>'
+	chomping := byte(0)
+	explicitIndent := 0
+
+	for _, c := range []byte(header[1:]) {
+		switch {
+		case c == '-' || c == '+':
+			chomping = c
+		case c >= '1' && c <= '9':
+			explicitIndent = int(c - '0')
+		case c == ' ':
+		default:
+			return "", p.errorf("invalid block scalar header %q", header)
+		}
+	}
+
+	blockIndent := 0
+	if explicitIndent > 0 {
+		blockIndent = parentIndent + explicitIndent
+	}
+
+	lines := []string{}
+
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+
+		if strings.TrimSpace(line) == "" {
+			lines = append(lines, "")
+			p.pos++
+			continue
+		}
+
+		indent := indentOf(line)
+		if blockIndent == 0 {
+			if indent <= parentIndent {
+				break
+			}
+			blockIndent = indent
+		}
+
+		if indent < blockIndent {
+			break
+		}
+
+		lines = append(lines, line[blockIndent:])
+		p.pos++
+	}
+
+	// trailing empty lines
+	trailing := 0
+	for i := len(lines) - 1; i >= 0 && lines[i] == ""; i-- {
+		trailing++
+	}
+	lines = lines[:len(lines)-trailing]
+
+	var sb strings.Builder
+
+	for i, line := range lines {
+		if i > 0 {
+			prev := lines[i-1]
+			if folded && prev != "" && line != "" && !strings.HasPrefix(prev, " ") && !strings.HasPrefix(line, " ") {
+				sb.WriteString(" ")
+			} else if !folded || prev != "" || line == "" || strings.HasPrefix(line, " ") {
+				sb.WriteString("\n")
+			}
+		}
+		sb.WriteString(line)
+	}
+
+	result := sb.String()
+
+	switch chomping {
+	case '-':
+	case '+':
+		if len(lines) > 0 {
+			result += "\n"
+		}
+		result += strings.Repeat("\n", trailing)
+	default:
+		if len(lines) > 0 {
+			result += "\n"
+		}
+	}
+
+	return result, nil
+}
+
+// splitMappingEntry splits a "key: value" line content.
+func splitMappingEntry(content string) (string, string, bool) {
+	if content == "" || content[0] == '[' || content[0] == '{' || content[0] == '#' {
+		return "", "", false
+	}
+
+	if content[0] == '"' || content[0] == '\'' {
+		key, rest, err := parseQuoted(content)
+		if err != nil {
+			return "", "", false
+		}
+
+		rest = strings.TrimLeft(rest, " ")
+		if rest == ":" {
+			return fmt.Sprint(key), "", true
+		}
+		if strings.HasPrefix(rest, ": ") {
+			return fmt.Sprint(key), strings.TrimSpace(rest[2:]), true
+		}
+
+		return "", "", false
+	}
+
+	for i := 0; i < len(content); i++ {
+		if content[i] != ':' {
+			continue
+		}
+		if i == len(content)-1 {
+			return strings.TrimSpace(content[:i]), "", true
+		}
+		if content[i+1] == ' ' {
+			return strings.TrimSpace(content[:i]), strings.TrimSpace(content[i+1:]), true
+		}
+	}
+
+	return "", "", false
+}
+
+// validatePlainScalar checks that raw is a valid single line plain scalar,
+// aka. that it doesn't start with a reserved indicator and it doesn't
+// contain nested mapping values (e.g. "a: b: c").
+func validatePlainScalar(raw string) error {
+	switch raw[0] {
+	case '@', '`', '%', ',', ']', '}', '|', '>':
+		return fmt.Errorf("plain scalars cannot start with the reserved indicator %q", raw[0])
+	case '?', ':', '-':
+		if len(raw) == 1 || raw[1] == ' ' || raw[1] == '\t' {
+			return fmt.Errorf("plain scalars cannot start with the indicator %q", raw[0])
+		}
+	}
+
+	for i := 0; i < len(raw); i++ {
+		if raw[i] == ':' && (i == len(raw)-1 || raw[i+1] == ' ' || raw[i+1] == '\t') {
+			return errors.New("mapping values are not allowed in this context")
+		}
+	}
+
+	return nil
+}
+
+// stripComment removes the trailing comment of a trimmed line content (if any).
+func stripComment(content string) string {
+	var quote byte
+
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				if quote == '\'' && i+1 < len(content) && content[i+1] == '\'' {
+					i++
+				} else {
+					quote = 0
+				}
+			}
+		case (c == '"' || c == '\'') && (i == 0 || strings.ContainsRune(" [{,:", rune(content[i-1]))):
+			quote = c
+		case c == '#' && (i == 0 || content[i-1] == ' '):
+			return strings.TrimSpace(content[:i])
+		}
+	}
+
+	return content
+}
+
+func isFlowClosed(raw string) bool {
+	depth := 0
+	var quote byte
+
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		}
+	}
+
+	return depth <= 0
+}
+
+// parseQuoted parses the leading single or double quoted string of raw
+// and returns the unquoted value and the remaining content.
+func parseQuoted(raw string) (string, string, error) {
+	quote := raw[0]
+
+	if quote == '\'' {
+		var sb strings.Builder
+		for i := 1; i < len(raw); i++ {
+			if raw[i] == '\'' {
+				if i+1 < len(raw) && raw[i+1] == '\'' {
+					sb.WriteByte('\'')
+					i++
+					continue
+				}
+				return sb.String(), raw[i+1:], nil
+			}
+			sb.WriteByte(raw[i])
+		}
+		return "", "", errors.New("unterminated single quoted string")
+	}
+
+	for i := 1; i < len(raw); i++ {
+		switch raw[i] {
+		case '\\':
+			i++
+		case '"':
+			value, err := unescapeDoubleQuoted(raw[1:i])
+			if err != nil {
+				return "", "", err
+			}
+			return value, raw[i+1:], nil
+		}
+	}
+
+	return "", "", errors.New("unterminated double quoted string")
+}
+
+func unescapeDoubleQuoted(s string) (string, error) {
+	if !strings.Contains(s, "\\") {
+		return s, nil
+	}
+
+	var sb strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			sb.WriteByte(s[i])
+			continue
+		}
+
+		i++
+		if i >= len(s) {
+			return "", errors.New("invalid escape sequence")
+		}
+
+		switch s[i] {
+		case '0':
+			sb.WriteByte(0)
+		case 'a':
+			sb.WriteByte('\a')
+		case 'b':
+			sb.WriteByte('\b')
+		case 't', '\t':
+			sb.WriteByte('\t')
+		case 'n':
+			sb.WriteByte('\n')
+		case 'v':
+			sb.WriteByte('\v')
+		case 'f':
+			sb.WriteByte('\f')
+		case 'r':
+			sb.WriteByte('\r')
+		case 'e':
+			sb.WriteByte(0x1b)
+		case ' ', '"', '/', '\\':
+			sb.WriteByte(s[i])
+		case 'x', 'u', 'U':
+			size := map[byte]int{'x': 2, 'u': 4, 'U': 8}[s[i]]
+			if i+size >= len(s) {
+				return "", errors.New("invalid escape sequence")
+			}
+			code, err := strconv.ParseUint(s[i+1:i+1+size], 16, 32)
+			if err != nil {
+				return "", errors.New("invalid escape sequence")
+			}
+			sb.WriteRune(rune(code))
+			i += size
+		default:
+			return "", fmt.Errorf("invalid escape sequence \\%c", s[i])
+		}
+	}
+
+	return sb.String(), nil
+}
+
+// resolvePlainScalar converts a plain (unquoted) scalar to its Go value.
+func resolvePlainScalar(raw string) any {
+	switch raw {
+	case "", "~", "null", "Null", "NULL":
+		return nil
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	case ".inf", ".Inf", ".INF", "+.inf", "+.Inf", "+.INF":
+		return math.Inf(1)
+	case "-.inf", "-.Inf", "-.INF":
+		return math.Inf(-1)
+	}
+
+	if c := raw[0]; (c >= '0' && c <= '9') || c == '-' || c == '+' || c == '.' {
+		if strings.HasPrefix(raw, "0x") {
+			if v, err := strconv.ParseInt(raw[2:], 16, 64); err == nil {
+				return v
+			}
+		} else if strings.HasPrefix(raw, "0o") {
+			if v, err := strconv.ParseInt(raw[2:], 8, 64); err == nil {
+				return v
+			}
+		} else if v, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			return v
+		} else if v, err := strconv.ParseFloat(raw, 64); err == nil && !strings.ContainsAny(raw, "xXpP_") {
+			return v
+		}
+	}
+
+	return raw
+}
+
+// -------------------------------------------------------------------
+
+type flowParser struct {
+	src string
+	pos int
+}
+
+func (fp *flowParser) skipSpaces() {
+	for fp.pos < len(fp.src) && fp.src[fp.pos] == ' ' {
+		fp.pos++
+	}
+}
+
+func (fp *flowParser) parseValue() (any, error) {
+	fp.skipSpaces()
+
+	if fp.pos >= len(fp.src) {
+		return nil, errors.New("unexpected end of flow collection")
+	}
+
+	switch fp.src[fp.pos] {
+	case '[':
+		return fp.parseSequence()
+	case '{':
+		return fp.parseMapping()
+	case '"', '\'':
+		value, rest, err := parseQuoted(fp.src[fp.pos:])
+		if err != nil {
+			return nil, err
+		}
+		fp.pos = len(fp.src) - len(rest)
+		return value, nil
+	case '&', '*', '!':
+		return nil, errors.New("anchors, aliases and tags are not supported")
+	}
+
+	start := fp.pos
+	for fp.pos < len(fp.src) {
+		c := fp.src[fp.pos]
+		if c == ',' || c == ']' || c == '}' || (c == ':' && (fp.pos+1 == len(fp.src) || strings.ContainsRune(" ,]}", rune(fp.src[fp.pos+1])))) {
+			break
+		}
+		fp.pos++
+	}
+
+	return resolvePlainScalar(strings.TrimSpace(fp.src[start:fp.pos])), nil
+}
+
+func (fp *flowParser) parseSequence() ([]any, error) {
+	fp.pos++ // [
+
+	result := []any{}
+
+	for {
+		fp.skipSpaces()
+		if fp.pos >= len(fp.src) {
+			return nil, errors.New("unterminated flow sequence")
+		}
+		if fp.src[fp.pos] == ']' {
+			fp.pos++
+			return result, nil
+		}
+
+		item, err := fp.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+
+		if err := fp.expectSeparator(']'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (fp *flowParser) parseMapping() (map[string]any, error) {
+	fp.pos++ // {
+
+	result := map[string]any{}
+
+	for {
+		fp.skipSpaces()
+		if fp.pos >= len(fp.src) {
+			return nil, errors.New("unterminated flow mapping")
+		}
+		if fp.src[fp.pos] == '}' {
+			fp.pos++
+			return result, nil
+		}
+
+		rawKey, err := fp.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		key := fmt.Sprint(rawKey)
+		if rawKey == nil {
+			key = "null"
+		}
+
+		fp.skipSpaces()
+
+		var value any
+		if fp.pos < len(fp.src) && fp.src[fp.pos] == ':' {
+			fp.pos++
+			value, err = fp.parseValue()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if _, exists := result[key]; exists {
+			return nil, fmt.Errorf("duplicated mapping key %q", key)
+		}
+		result[key] = value
+
+		if err := fp.expectSeparator('}'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (fp *flowParser) expectSeparator(closing byte) error {
+	fp.skipSpaces()
+
+	if fp.pos >= len(fp.src) {
+		return errors.New("unterminated flow collection")
+	}
+
+	switch fp.src[fp.pos] {
+	case ',':
+		fp.pos++
+		return nil
+	case closing:
+		return nil
+	}
+
+	return fmt.Errorf("unexpected character %q in flow collection", fp.src[fp.pos])
+}
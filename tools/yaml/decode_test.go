@@ -0,0 +1,111 @@
+package yaml_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/yaml"
+)
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name        string
+		data        string
+		expected    string
+		expectError bool
+	}{
+		{"empty", "", `null`, false},
+		{"only comments", "# comment\n\n---\n", `null`, false},
+		{"plain scalars", "a: 1\nb: -2.5\nc: true\nd: ~\ne: hello world\nf: 0x1f\ng: 1e3\nh: http://example.com\ni: null", `{"a":1,"b":-2.5,"c":true,"d":null,"e":"hello world","f":31,"g":1000,"h":"http://example.com","i":null}`, false},
+		{"quoted scalars", `a: "x\ty\n\"z\" \u00e9"` + "\nb: 'it''s # not a comment'\nc: \"123\"", `{"a":"x\ty\n\"z\" é","b":"it's # not a comment","c":"123"}`, false},
+		{"comments", "# top\na: 1 # trailing\n# between\nb: x#y", `{"a":1,"b":"x#y"}`, false},
+		{"nested mappings", "a:\n  b:\n    c: 1\n  d: 2\ne: 3", `{"a":{"b":{"c":1},"d":2},"e":3}`, false},
+		{"sequences", "- 1\n- two\n-\n  - nested\n- - inline nested\n  - x", `[1,"two",["nested"],["inline nested","x"]]`, false},
+		{"sequence of mappings", "- name: a\n  type: base\n  fields:\n    - name: title\n      required: true\n- name: b", `[{"fields":[{"name":"title","required":true}],"name":"a","type":"base"},{"name":"b"}]`, false},
+		{"sequence with the parent key indentation", "a:\n- 1\n- 2\nb: 3", `{"a":[1,2],"b":3}`, false},
+		{"flow collections", "a: [1, \"b, c\", {x: 1, 'y': [true, null]}]\nb: {}\nc: []\nd: [\n  1,\n  2\n]", `{"a":[1,"b, c",{"x":1,"y":[true,null]}],"b":{},"c":[],"d":[1,2]}`, false},
+		{"literal block scalar", "a: |\n  line 1\n    line 2\n\n  line 3\nb: x", `{"a":"line 1\n  line 2\n\nline 3\n","b":"x"}`, false},
+		{"literal block scalar chomping", "a: |-\n  x\n\nb: |+\n  y\n\nc: 1", `{"a":"x","b":"y\n\n","c":1}`, false},
+		{"folded block scalar", "a: >\n  folded\n  text\n\n  next\n", `{"a":"folded text\nnext\n"}`, false},
+		{"multi-line plain scalar", "rule: id != '' &&\n  name = 'x'\nb: 1", `{"b":1,"rule":"id != '' \u0026\u0026 name = 'x'"}`, false},
+		{"quoted keys", "\"a b\": 1\n'c': 2", `{"a b":1,"c":2}`, false},
+		{"document markers", "---\na: 1\n...\n", `{"a":1}`, false},
+		{"windows line endings", "a: 1\r\nb: 2\r\n", `{"a":1,"b":2}`, false},
+		{"duplicated keys", "a: 1\na: 2", ``, true},
+		{"invalid indentation", "a: 1\n  b: 2", ``, true},
+		{"tab indentation", "a:\n\tb: 1", ``, true},
+		{"anchors", "a: &x 1\nb: *x", ``, true},
+		{"tags", "a: !!str 1", ``, true},
+		{"multiple documents", "a: 1\n---\nb: 2", ``, true},
+		{"unterminated quote", "a: \"b", ``, true},
+		{"unterminated flow", "a: [1, 2", ``, true},
+		{"nested mapping value", "a: b: c", ``, true},
+		{"nested mapping value with empty value", "a: b:", ``, true},
+		{"nested mapping value in sequence item", "- a: b: c", ``, true},
+		{"nested mapping value in multi-line plain scalar", "a: x\n  y: z", ``, true},
+		{"reserved indicator @", "a: @x", ``, true},
+		{"reserved indicator backtick", "a: `x`", ``, true},
+		{"reserved indicator %", "a: %x", ``, true},
+		{"complex key indicator", "a: ? x", ``, true},
+		{"plain scalars with indicator characters", "a: x@y\nb: 'a: b'\nc: -x\nd: a:b\ne: 50%", `{"a":"x@y","b":"a: b","c":"-x","d":"a:b","e":"50%"}`, false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result, err := yaml.Decode([]byte(s.data))
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+			if hasErr {
+				return
+			}
+
+			raw, err := json.Marshal(result)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(raw) != s.expected {
+				t.Fatalf("Expected\n%s\ngot\n%s", s.expected, raw)
+			}
+		})
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	t.Parallel()
+
+	data := `
+collections:
+  - name: posts
+    listRule: ""
+    viewRule: null
+    maxSelect: 2
+`
+
+	result := struct {
+		Collections []struct {
+			Name      string  `json:"name"`
+			ListRule  *string `json:"listRule"`
+			ViewRule  *string `json:"viewRule"`
+			MaxSelect int     `json:"maxSelect"`
+		} `json:"collections"`
+	}{}
+
+	if err := yaml.Unmarshal([]byte(data), &result); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Collections) != 1 {
+		t.Fatalf("Expected 1 collection, got %d", len(result.Collections))
+	}
+
+	c := result.Collections[0]
+	if c.Name != "posts" || c.ListRule == nil || *c.ListRule != "" || c.ViewRule != nil || c.MaxSelect != 2 {
+		t.Fatalf("Unexpected unmarshaled collection %+v", c)
+	}
+}